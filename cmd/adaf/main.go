@@ -1,7 +1,12 @@
 package main
 
-import "github.com/agusx1211/adaf/internal/cli"
+import (
+	"github.com/agusx1211/adaf/internal/cli"
+	"github.com/agusx1211/adaf/internal/sandbox"
+)
 
 func main() {
+	// Sandboxed agents are launched by re-executing this binary.
+	sandbox.RunInitIfRequested()
 	cli.Execute()
 }
@@ -5,7 +5,10 @@ import (
 	"io"
 	"time"
 
+	"github.com/agusx1211/adaf/internal/isolation"
 	"github.com/agusx1211/adaf/internal/recording"
+	"github.com/agusx1211/adaf/internal/remote"
+	"github.com/agusx1211/adaf/internal/sandbox"
 	"github.com/agusx1211/adaf/internal/stream"
 )
 
@@ -33,6 +36,19 @@ type Config struct {
 	// process output. Nil means use the OS defaults.
 	Stdout io.Writer
 	Stderr io.Writer
+
+	// Sandbox, when set, confines the agent process (Linux only). Nil runs
+	// the agent unconfined.
+	Sandbox *sandbox.Spec
+
+	// Remote, when set, runs the agent process on a worker host instead of
+	// locally. Only Env is forwarded; it cannot be combined with Sandbox.
+	Remote *remote.Exec
+
+	// Isolation, when set, runs the agent inside the spawn's isolation
+	// backend (e.g. its container). Only Env is forwarded; it cannot be
+	// combined with Sandbox or Remote.
+	Isolation isolation.Wrapper
 }
 
 // Result holds the outcome of a single agent run.
@@ -42,6 +58,7 @@ type Result struct {
 	Output         string // captured stdout
 	Error          string // captured stderr
 	AgentSessionID string // session/thread ID captured from stream init event
+	ResultError    string // text of an error "result" stream event, if any
 }
 
 // Agent is the interface that all agent runners must implement.
@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"os"
@@ -36,6 +37,7 @@ type AgentRecord struct {
 	ModelOverride   string                     `json:"model_override,omitempty"`
 	Detected        bool                       `json:"detected"`
 	DetectedAt      time.Time                  `json:"detected_at,omitempty"`
+	Plugin          *PluginManifest            `json:"plugin,omitempty"` // set for plugin adapters
 }
 
 // LoadAgentsConfig loads ~/.adaf/agents.json, returning an empty config if absent.
@@ -112,6 +114,30 @@ func SyncDetectedAgents(detected []detect.DetectedAgent, globalCfg *config.Globa
 		cfg.Agents[name] = rec
 	}
 
+	for _, p := range DiscoverPlugins(context.Background(), PluginsDir()) {
+		name := p.Manifest.Name
+		seen[name] = struct{}{}
+
+		manifest := p.Manifest
+		info := manifest.Info(p.Path)
+		rec := cfg.Agents[name]
+		rec.Name = name
+		rec.Path = p.Path
+		rec.Version = manifest.Version
+		rec.Capabilities = info.Capabilities
+		rec.SupportedModels = info.SupportedModels
+		rec.ReasoningLevels = info.ReasoningLevels
+		rec.Plugin = &manifest
+		rec.Detected = true
+		rec.DetectedAt = now
+		if strings.TrimSpace(rec.ModelOverride) != "" {
+			rec.DefaultModel = strings.TrimSpace(rec.ModelOverride)
+		} else {
+			rec.DefaultModel = manifest.DefaultModel
+		}
+		cfg.Agents[name] = rec
+	}
+
 	for name, rec := range cfg.Agents {
 		if _, ok := seen[name]; ok {
 			continue
@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Failure kinds a different profile may be able to recover from.
+const (
+	FailureQuota = "quota" // rate limit, usage window or credit exhaustion
+	FailureAuth  = "auth"  // missing, expired or rejected credentials
+	FailureCrash = "crash" // the CLI failed to start or died without output
+)
+
+var quotaFailureMarkers = []string{
+	"rate limit",
+	"rate_limit",
+	"ratelimit",
+	"too many requests",
+	"quota",
+	"usage limit",
+	"limit reached",
+	"resource_exhausted",
+	"resource exhausted",
+	"credit balance",
+	"overloaded",
+}
+
+var authFailureMarkers = []string{
+	"unauthorized",
+	"unauthenticated",
+	"authentication",
+	"invalid api key",
+	"invalid_api_key",
+	"invalid x-api-key",
+	"api key not valid",
+	"not logged in",
+	"please run /login",
+	"login required",
+	"token has expired",
+	"token expired",
+}
+
+// HTTP status codes are matched as whole words so line numbers and sizes in
+// agent output do not trip them.
+var (
+	quotaStatusRe = regexp.MustCompile(`\b(429|529)\b`)
+	authStatusRe  = regexp.MustCompile(`\b401\b`)
+)
+
+// ClassifyFailure reports why a finished run failed when another profile
+// could plausibly succeed with the same prompt, or "" when the outcome should
+// stand. Cancellations (interrupts, waits, timeouts) never classify. Only
+// stderr, the reported result error and the run error are inspected, never
+// the agent's own reply, so an agent that merely talks about rate limits is
+// not mistaken for one that hit them.
+func ClassifyFailure(result *Result, runErr error) string {
+	if errors.Is(runErr, context.Canceled) || errors.Is(runErr, context.DeadlineExceeded) {
+		return ""
+	}
+	if runErr == nil && (result == nil || (result.ExitCode == 0 && result.ResultError == "")) {
+		return ""
+	}
+
+	var b strings.Builder
+	if runErr != nil {
+		b.WriteString(runErr.Error())
+		b.WriteByte('\n')
+	}
+	if result != nil {
+		b.WriteString(result.ResultError)
+		b.WriteByte('\n')
+		b.WriteString(result.Error)
+	}
+	text := strings.ToLower(b.String())
+	if containsAny(text, quotaFailureMarkers) || quotaStatusRe.MatchString(text) {
+		return FailureQuota
+	}
+	if containsAny(text, authFailureMarkers) || authStatusRe.MatchString(text) {
+		return FailureAuth
+	}
+	if runErr != nil || (result.ExitCode != 0 && strings.TrimSpace(result.Output) == "") {
+		return FailureCrash
+	}
+	return ""
+}
+
+func containsAny(s string, markers []string) bool {
+	for _, m := range markers {
+		if strings.Contains(s, m) {
+			return true
+		}
+	}
+	return false
+}
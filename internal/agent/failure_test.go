@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *Result
+		runErr error
+		want   string
+	}{
+		{
+			name:   "success",
+			result: &Result{ExitCode: 0, Output: "hit the rate limit earlier, all good now"},
+		},
+		{
+			name:   "quota in result error",
+			result: &Result{ExitCode: 0, ResultError: "Claude AI usage limit reached|1760000000"},
+			want:   FailureQuota,
+		},
+		{
+			name:   "http 429 in stderr",
+			result: &Result{ExitCode: 1, Error: "request failed with status 429"},
+			want:   FailureQuota,
+		},
+		{
+			name:   "status code inside a larger number",
+			result: &Result{ExitCode: 1, Output: "wrote 14290 bytes", Error: "panic: nil map"},
+		},
+		{
+			name:   "auth",
+			result: &Result{ExitCode: 1, Error: "Invalid API key · Please run /login"},
+			want:   FailureAuth,
+		},
+		{
+			name:   "crash without output",
+			result: &Result{ExitCode: 2, Error: "segmentation fault"},
+			want:   FailureCrash,
+		},
+		{
+			name:   "start error",
+			runErr: errors.New("starting codex: executable file not found in $PATH"),
+			want:   FailureCrash,
+		},
+		{
+			name:   "ordinary failure with output",
+			result: &Result{ExitCode: 1, Output: "tests failed"},
+		},
+		{
+			name:   "reply mentions quota and auth",
+			result: &Result{ExitCode: 1, Output: "Checked the quota handling and authentication flow; 429 tests still failing.", Error: "exit status 1"},
+		},
+		{
+			name:   "cancelled",
+			result: &Result{ExitCode: -1, Error: "429"},
+			runErr: fmt.Errorf("agent run: %w", context.Canceled),
+		},
+		{
+			name:   "timed out",
+			runErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyFailure(tt.result, tt.runErr); got != tt.want {
+				t.Fatalf("ClassifyFailure() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/agusx1211/adaf/internal/debug"
 	"github.com/agusx1211/adaf/internal/eventq"
 	"github.com/agusx1211/adaf/internal/recording"
+	"github.com/agusx1211/adaf/internal/sandbox"
 	"github.com/agusx1211/adaf/internal/stream"
 )
 
@@ -69,6 +70,48 @@ func setupProcessGroup(cmd *exec.Cmd) {
 	}
 }
 
+// wrapExecution wraps the fully built command in cfg.Sandbox, or rewrites it
+// to run on cfg.Remote or in cfg.Isolation. It must run after the environment
+// is set; the returned cleanup is never nil.
+func wrapExecution(cmd *exec.Cmd, cfg Config, agentName string) (func(), error) {
+	if cfg.Isolation != nil {
+		if cfg.Sandbox != nil || cfg.Remote != nil {
+			return func() {}, fmt.Errorf("%s agent: isolation backend cannot be combined with a sandbox or remote execution", agentName)
+		}
+		if err := cfg.Isolation.Wrap(cmd, cfg.Env); err != nil {
+			return func() {}, fmt.Errorf("%s agent: %w", agentName, err)
+		}
+		debug.LogKV("agent."+agentName, "isolation backend enabled")
+		return func() {}, nil
+	}
+	if cfg.Remote != nil {
+		if cfg.Sandbox != nil {
+			return func() {}, fmt.Errorf("%s agent: sandbox cannot be combined with remote execution", agentName)
+		}
+		if err := cfg.Remote.Wrap(cmd, cfg.Env); err != nil {
+			return func() {}, fmt.Errorf("%s agent: %w", agentName, err)
+		}
+		debug.LogKV("agent."+agentName, "remote execution enabled",
+			"host", cfg.Remote.Host,
+			"dir", cfg.Remote.Dir,
+		)
+		return func() {}, nil
+	}
+	if cfg.Sandbox == nil {
+		return func() {}, nil
+	}
+	cleanup, err := sandbox.Wrap(cmd, cfg.Sandbox)
+	if err != nil {
+		return cleanup, fmt.Errorf("%s agent: %w", agentName, err)
+	}
+	debug.LogKV("agent."+agentName, "sandbox enabled",
+		"network", cfg.Sandbox.Network,
+		"read_only", cfg.Sandbox.ReadOnly,
+		"writable", strings.Join(cfg.Sandbox.Writable, ","),
+	)
+	return cleanup, nil
+}
+
 func waitForProcessGroupExit(pgid int, timeout, pollEvery time.Duration) bool {
 	if timeout <= 0 {
 		return false
@@ -216,6 +259,12 @@ func runBufferAgent(
 	bo := setupBufferOutput(cmd, cfg, recorder)
 	recordMeta(recorder, agentName, cmdName, args, cfg.WorkDir)
 
+	cleanup, err := wrapExecution(cmd, cfg, agentName)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
 	start := time.Now()
 	debug.LogKV("agent."+agentName, "process starting", "binary", cmdName)
 	runErr := cmd.Run()
@@ -271,6 +320,12 @@ func runStreamAgent(
 	ss := setupStreamStderr(cmd, cfg, recorder)
 	recordMeta(recorder, agentName, cmdName, args, cfg.WorkDir)
 
+	cleanup, err := wrapExecution(cmd, cfg, agentName)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
 	start := time.Now()
 	if err := cmd.Start(); err != nil {
 		debug.LogKV("agent."+agentName, "process start failed", "error", err)
@@ -279,7 +334,7 @@ func runStreamAgent(
 	debug.LogKV("agent."+agentName, "process started", "pid", cmd.Process.Pid)
 
 	events := parser(ctx, stdoutPipe)
-	text, agentSessionID := runStreamLoop(cfg, events, recorder, start, ss.W)
+	text, agentSessionID, resultErr := runStreamLoop(cfg, events, recorder, start, ss.W)
 
 	waitErr := cmd.Wait()
 	duration := time.Since(start)
@@ -297,6 +352,7 @@ func runStreamAgent(
 				Output:         text,
 				Error:          ss.Buf.String(),
 				AgentSessionID: agentSessionID,
+				ResultError:    resultErr,
 			}, wrappedErr
 		}
 		return nil, wrappedErr
@@ -316,6 +372,7 @@ func runStreamAgent(
 		Output:         text,
 		Error:          ss.Buf.String(),
 		AgentSessionID: agentSessionID,
+		ResultError:    resultErr,
 	}, nil
 }
 
@@ -416,9 +473,9 @@ func defaultAccumulateText(ev stream.ClaudeEvent, buf *strings.Builder) {
 // forwarding to EventSink for live stream consumers or displaying via terminal
 // Display with a 30-second heartbeat ticker.
 //
-// Returns the accumulated text output and the agent session ID (if captured
-// from a system init event).
-func runStreamLoop(cfg Config, events <-chan stream.RawEvent, recorder *recording.Recorder, start time.Time, stderrW io.Writer) (text string, sessionID string) {
+// Returns the accumulated text output, the agent session ID (if captured
+// from a system init event) and the text of an error result event.
+func runStreamLoop(cfg Config, events <-chan stream.RawEvent, recorder *recording.Recorder, start time.Time, stderrW io.Writer) (text string, sessionID string, resultErr string) {
 	var textBuf strings.Builder
 
 	if cfg.EventSink != nil {
@@ -434,6 +491,9 @@ func runStreamLoop(cfg Config, events <-chan stream.RawEvent, recorder *recordin
 			if ev.Parsed.Type == "system" && ev.Parsed.Subtype == "init" && ev.Parsed.TurnID != "" {
 				sessionID = ev.Parsed.TurnID
 			}
+			if msg, ok := resultErrorText(ev.Parsed); ok {
+				resultErr = msg
+			}
 			ev.TurnID = cfg.TurnID
 			if !eventq.Offer(cfg.EventSink, ev) {
 				dropped++
@@ -457,7 +517,7 @@ func runStreamLoop(cfg Config, events <-chan stream.RawEvent, recorder *recordin
 			case ev, ok := <-events:
 				if !ok {
 					display.Finish()
-					return textBuf.String(), sessionID
+					return textBuf.String(), sessionID, resultErr
 				}
 				if len(ev.Raw) > 0 {
 					recorder.RecordStream(string(ev.Raw))
@@ -468,6 +528,9 @@ func runStreamLoop(cfg Config, events <-chan stream.RawEvent, recorder *recordin
 				if ev.Parsed.Type == "system" && ev.Parsed.Subtype == "init" && ev.Parsed.TurnID != "" {
 					sessionID = ev.Parsed.TurnID
 				}
+				if msg, ok := resultErrorText(ev.Parsed); ok {
+					resultErr = msg
+				}
 				display.Handle(ev.Parsed)
 				defaultAccumulateText(ev.Parsed, &textBuf)
 			case <-ticker.C:
@@ -477,5 +540,19 @@ func runStreamLoop(cfg Config, events <-chan stream.RawEvent, recorder *recordin
 		}
 	}
 
-	return textBuf.String(), sessionID
+	return textBuf.String(), sessionID, resultErr
+}
+
+// resultErrorText returns the message of an error "result" event.
+func resultErrorText(ev stream.ClaudeEvent) (string, bool) {
+	if ev.Type != "result" || !ev.IsError {
+		return "", false
+	}
+	if msg := strings.TrimSpace(ev.ResultText); msg != "" {
+		return msg, true
+	}
+	if ev.Subtype != "" {
+		return ev.Subtype, true
+	}
+	return "error", true
 }
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/sandbox"
 )
 
 // LaunchSpec is the shared command/args/env launch configuration for a profile.
@@ -11,6 +12,7 @@ type LaunchSpec struct {
 	Command string
 	Args    []string
 	Env     map[string]string
+	Sandbox *sandbox.Spec // nil when the profile has no sandbox
 }
 
 // BuildLaunchSpec builds agent launch settings from a profile.
@@ -66,11 +68,18 @@ func BuildLaunchSpec(prof *config.Profile, agentsCfg *AgentsConfig, commandOverr
 		if modelOverride != "" {
 			spec.Env["VIBE_ACTIVE_MODEL"] = modelOverride
 		}
+
+	default:
+		// Plugin adapters declare their own model/reasoning argv templates.
+		if p, ok := lookupPlugin(prof.Agent); ok {
+			spec.Args = append(spec.Args, p.manifest.launchArgs(modelOverride, reasoningLevel)...)
+		}
 	}
 
 	if len(spec.Env) == 0 {
 		spec.Env = nil
 	}
+	spec.Sandbox = sandbox.FromConfig(prof.Sandbox, prof.Agent)
 	return spec
 }
 
@@ -92,6 +101,9 @@ func resolveCommandPath(agentName string, agentsCfg *AgentsConfig, commandOverri
 	case "claude", "codex", "vibe", "opencode", "gemini", "generic":
 		return ""
 	default:
+		if p, ok := lookupPlugin(agentName); ok {
+			return p.path
+		}
 		return agentName
 	}
 }
@@ -97,3 +97,27 @@ func TestBuildLaunchSpecCommandResolution(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildLaunchSpecSandbox(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if spec := BuildLaunchSpec(&config.Profile{Agent: "claude"}, nil, ""); spec.Sandbox != nil {
+		t.Fatalf("Sandbox = %+v, want nil without profile sandbox", spec.Sandbox)
+	}
+
+	spec := BuildLaunchSpec(&config.Profile{
+		Agent:   "codex",
+		Sandbox: &config.Sandbox{Network: "allowlist", AllowHosts: []string{"api.openai.com"}, Writable: []string{"~/.cache/go-build"}},
+	}, nil, "")
+	if spec.Sandbox == nil {
+		t.Fatal("Sandbox = nil, want spec")
+	}
+	if spec.Sandbox.Network != config.SandboxNetworkAllowlist {
+		t.Fatalf("Network = %q, want allowlist", spec.Sandbox.Network)
+	}
+	want := []string{home + "/.codex", home + "/.cache/go-build"}
+	if !reflect.DeepEqual(spec.Sandbox.Writable, want) {
+		t.Fatalf("Writable = %v, want %v", spec.Sandbox.Writable, want)
+	}
+}
@@ -0,0 +1,286 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/agentmeta"
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/recording"
+	"github.com/agusx1211/adaf/internal/stream"
+)
+
+// PluginProtocolVersion is the adapter protocol version understood by adaf.
+//
+// A plugin is an executable placed in PluginsDir(). adaf talks to it in two
+// ways:
+//
+//   - "<plugin> describe" must print a PluginManifest as JSON on stdout and
+//     exit 0. It is called during agent detection; the result is cached in
+//     ~/.adaf/agents.json.
+//   - "<plugin> <args...>" runs one agent turn. The prompt is written to
+//     stdin and the plugin must emit newline-delimited JSON events shaped like
+//     stream.ClaudeEvent on stdout: a "system"/"init" event carrying
+//     session_id (used for resume), "assistant" events with content blocks,
+//     and a final "result" event with usage and total_cost_usd (used for
+//     stats). Non-JSON lines are recorded but otherwise ignored.
+const PluginProtocolVersion = 1
+
+// pluginDescribeTimeout bounds how long a plugin may take to describe itself.
+const pluginDescribeTimeout = 5 * time.Second
+
+// PluginManifest is what a plugin reports from "describe". Argument templates
+// may contain {model}, {reasoning} and {session_id} placeholders.
+type PluginManifest struct {
+	Protocol        int      `json:"protocol"`
+	Name            string   `json:"name"`
+	Version         string   `json:"version,omitempty"`
+	DefaultModel    string   `json:"default_model,omitempty"`
+	Models          []string `json:"models,omitempty"`
+	ReasoningLevels []string `json:"reasoning_levels,omitempty"`
+	Capabilities    []string `json:"capabilities,omitempty"`
+
+	// Args is the argv used for a fresh turn (default: ["run"]).
+	Args []string `json:"args,omitempty"`
+	// ResumeArgs is appended when resuming a session (default: ["--resume", "{session_id}"]).
+	ResumeArgs []string `json:"resume_args,omitempty"`
+	// ModelArgs is appended when a profile selects a model (default: ["--model", "{model}"]).
+	ModelArgs []string `json:"model_args,omitempty"`
+	// ReasoningArgs is appended when a profile selects a reasoning level (default: ["--reasoning", "{reasoning}"]).
+	ReasoningArgs []string `json:"reasoning_args,omitempty"`
+	// Env holds extra environment variables set for every turn.
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// PluginsDir returns the directory scanned for agent plugins (~/.adaf/plugins/agents).
+func PluginsDir() string {
+	return filepath.Join(config.Dir(), "plugins", "agents")
+}
+
+// Validate checks that a manifest can be used by this version of adaf.
+func (m *PluginManifest) Validate() error {
+	if m.Protocol != PluginProtocolVersion {
+		return fmt.Errorf("unsupported plugin protocol %d (want %d)", m.Protocol, PluginProtocolVersion)
+	}
+	name := normalizeAgentName(m.Name)
+	if name == "" {
+		return fmt.Errorf("plugin manifest has no name")
+	}
+	if strings.ContainsAny(name, " /\\") {
+		return fmt.Errorf("invalid plugin name %q", m.Name)
+	}
+	if agentmeta.IsBuiltin(name) {
+		return fmt.Errorf("plugin name %q conflicts with a built-in agent", name)
+	}
+	return nil
+}
+
+// Info converts the manifest into agent metadata for model discovery.
+func (m *PluginManifest) Info(binary string) agentmeta.Info {
+	info := agentmeta.Info{
+		Name:            normalizeAgentName(m.Name),
+		Binary:          binary,
+		DefaultModel:    m.DefaultModel,
+		SupportedModels: append([]string(nil), m.Models...),
+		Capabilities:    append([]string{"stream-output"}, m.Capabilities...),
+	}
+	for _, lvl := range m.ReasoningLevels {
+		info.ReasoningLevels = append(info.ReasoningLevels, agentmeta.ReasoningLevel{Name: lvl})
+	}
+	return info
+}
+
+func (m *PluginManifest) runArgs() []string {
+	if len(m.Args) == 0 {
+		return []string{"run"}
+	}
+	return m.Args
+}
+
+// launchArgs returns the profile-driven args (model, reasoning) for a turn.
+func (m *PluginManifest) launchArgs(model, reasoning string) []string {
+	var args []string
+	if model != "" {
+		tmpl := m.ModelArgs
+		if len(tmpl) == 0 {
+			tmpl = []string{"--model", "{model}"}
+		}
+		args = append(args, expandPluginArgs(tmpl, map[string]string{"model": model})...)
+	}
+	if reasoning != "" {
+		tmpl := m.ReasoningArgs
+		if len(tmpl) == 0 {
+			tmpl = []string{"--reasoning", "{reasoning}"}
+		}
+		args = append(args, expandPluginArgs(tmpl, map[string]string{"reasoning": reasoning})...)
+	}
+	return args
+}
+
+func (m *PluginManifest) resumeArgs(sessionID string) []string {
+	tmpl := m.ResumeArgs
+	if len(tmpl) == 0 {
+		tmpl = []string{"--resume", "{session_id}"}
+	}
+	return expandPluginArgs(tmpl, map[string]string{"session_id": sessionID})
+}
+
+func expandPluginArgs(tmpl []string, vars map[string]string) []string {
+	out := make([]string, 0, len(tmpl))
+	for _, arg := range tmpl {
+		for k, v := range vars {
+			arg = strings.ReplaceAll(arg, "{"+k+"}", v)
+		}
+		out = append(out, arg)
+	}
+	return out
+}
+
+// DescribePlugin runs "<path> describe" and returns the validated manifest.
+func DescribePlugin(ctx context.Context, path string) (*PluginManifest, error) {
+	ctx, cancel := context.WithTimeout(ctx, pluginDescribeTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path, "describe")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: describe: %w", path, err)
+	}
+	var m PluginManifest
+	if err := json.Unmarshal(out, &m); err != nil {
+		return nil, fmt.Errorf("plugin %s: invalid manifest: %w", path, err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", path, err)
+	}
+	m.Name = normalizeAgentName(m.Name)
+	return &m, nil
+}
+
+// DiscoveredPlugin is a plugin executable together with its manifest.
+type DiscoveredPlugin struct {
+	Path     string
+	Manifest PluginManifest
+}
+
+// DiscoverPlugins describes every executable in dir. Plugins that fail to
+// describe themselves are skipped and logged. A missing dir yields no plugins.
+func DiscoverPlugins(ctx context.Context, dir string) []DiscoveredPlugin {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var found []DiscoveredPlugin
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		fi, err := os.Stat(path)
+		if err != nil || fi.Mode()&0111 == 0 {
+			continue
+		}
+		m, err := DescribePlugin(ctx, path)
+		if err != nil {
+			debug.LogKV("agent.plugin", "skipping plugin", "path", path, "error", err)
+			continue
+		}
+		if seen[m.Name] {
+			debug.LogKV("agent.plugin", "duplicate plugin name", "path", path, "name", m.Name)
+			continue
+		}
+		seen[m.Name] = true
+		found = append(found, DiscoveredPlugin{Path: path, Manifest: *m})
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Manifest.Name < found[j].Manifest.Name })
+	return found
+}
+
+// PluginAgent runs an external adapter executable that speaks the adaf
+// plugin protocol (see PluginProtocolVersion).
+type PluginAgent struct {
+	name     string
+	path     string
+	manifest PluginManifest
+}
+
+// NewPluginAgent creates a PluginAgent for the executable at path.
+func NewPluginAgent(path string, manifest PluginManifest) *PluginAgent {
+	return &PluginAgent{
+		name:     normalizeAgentName(manifest.Name),
+		path:     path,
+		manifest: manifest,
+	}
+}
+
+// Name returns the plugin's declared agent name.
+func (p *PluginAgent) Name() string {
+	return p.name
+}
+
+// Manifest returns a copy of the plugin's manifest.
+func (p *PluginAgent) Manifest() PluginManifest {
+	return p.manifest
+}
+
+// Run executes one turn through the plugin and parses its normalized
+// NDJSON output like a Claude stream.
+func (p *PluginAgent) Run(ctx context.Context, cfg Config, recorder *recording.Recorder) (*Result, error) {
+	cmdName := cfg.Command
+	if cmdName == "" {
+		cmdName = p.path
+	}
+
+	args := append([]string(nil), p.manifest.runArgs()...)
+	if cfg.ResumeSessionID != "" {
+		args = append(args, p.manifest.resumeArgs(cfg.ResumeSessionID)...)
+	}
+	args = append(args, cfg.Args...)
+
+	debug.LogKV("agent.plugin", "building command",
+		"name", p.name,
+		"binary", cmdName,
+		"args", strings.Join(args, " "),
+		"workdir", cfg.WorkDir,
+		"prompt_len", len(cfg.Prompt),
+		"resume_session", cfg.ResumeSessionID,
+	)
+
+	env := make(map[string]string, len(p.manifest.Env)+len(cfg.Env)+1)
+	for k, v := range p.manifest.Env {
+		env[k] = v
+	}
+	for k, v := range cfg.Env {
+		env[k] = v
+	}
+	env["ADAF_PLUGIN_PROTOCOL"] = fmt.Sprintf("%d", PluginProtocolVersion)
+
+	cmd := exec.CommandContext(ctx, cmdName, args...)
+	cmd.Dir = cfg.WorkDir
+
+	setupStdin(cmd, cfg.Prompt, recorder)
+	setupProcessGroup(cmd)
+	cmd.WaitDelay = 5 * time.Second
+	setupEnv(cmd, env)
+
+	return runStreamAgent(ctx, cmd, cfg, recorder, p.name, cmdName, args, stream.Parse)
+}
+
+// lookupPlugin returns the registered plugin agent for name, if any.
+func lookupPlugin(name string) (*PluginAgent, bool) {
+	a, ok := Get(normalizeAgentName(name))
+	if !ok {
+		return nil, false
+	}
+	p, ok := a.(*PluginAgent)
+	return p, ok
+}
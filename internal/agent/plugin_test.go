@@ -0,0 +1,177 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/recording"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+const fakePluginScript = `#!/usr/bin/env sh
+if [ "$1" = "describe" ]; then
+  cat <<'JSON'
+{"protocol":1,"name":"Fake-Plugin","default_model":"fast","models":["fast","smart"],"reasoning_levels":["low","high"],"model_args":["-m","{model}"],"resume_args":["--continue={session_id}"]}
+JSON
+  exit 0
+fi
+cat >/dev/null
+echo "args: $*" >&2
+echo '{"type":"system","subtype":"init","session_id":"sess-42","model":"fast"}'
+echo '{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"hello from plugin"}]}}'
+echo '{"type":"result","subtype":"success","total_cost_usd":0.25,"usage":{"input_tokens":10,"output_tokens":5}}'
+`
+
+func writeFakePlugin(t *testing.T, dir string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script helper not supported on windows")
+	}
+	path := filepath.Join(dir, "fake-plugin")
+	if err := os.WriteFile(path, []byte(fakePluginScript), 0755); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFakePlugin(t, dir)
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("not a plugin"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	found := DiscoverPlugins(context.Background(), dir)
+	if len(found) != 1 {
+		t.Fatalf("DiscoverPlugins() = %d plugins, want 1", len(found))
+	}
+	if found[0].Path != path {
+		t.Fatalf("Path = %q, want %q", found[0].Path, path)
+	}
+	m := found[0].Manifest
+	if m.Name != "fake-plugin" {
+		t.Fatalf("Name = %q, want normalized fake-plugin", m.Name)
+	}
+	if !reflect.DeepEqual(m.Models, []string{"fast", "smart"}) {
+		t.Fatalf("Models = %v", m.Models)
+	}
+}
+
+func TestPluginManifestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest PluginManifest
+		wantErr  string
+	}{
+		{"ok", PluginManifest{Protocol: 1, Name: "aider"}, ""},
+		{"protocol", PluginManifest{Protocol: 2, Name: "aider"}, "unsupported plugin protocol"},
+		{"no name", PluginManifest{Protocol: 1}, "no name"},
+		{"builtin", PluginManifest{Protocol: 1, Name: "claude"}, "built-in"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.manifest.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPluginAgentRunParsesNormalizedStream(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFakePlugin(t, dir)
+	m, err := DescribePlugin(context.Background(), path)
+	if err != nil {
+		t.Fatalf("DescribePlugin() error = %v", err)
+	}
+
+	s, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	rec := recording.New(1, s)
+
+	var stderr strings.Builder
+	result, err := NewPluginAgent(path, *m).Run(context.Background(), Config{
+		WorkDir:         dir,
+		Prompt:          "do it",
+		ResumeSessionID: "sess-1",
+		Stdout:          &strings.Builder{},
+		Stderr:          &stderr,
+	}, rec)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.AgentSessionID != "sess-42" {
+		t.Fatalf("AgentSessionID = %q, want sess-42", result.AgentSessionID)
+	}
+	if !strings.Contains(result.Output, "hello from plugin") {
+		t.Fatalf("Output = %q, want plugin text", result.Output)
+	}
+	if !strings.Contains(result.Error, "args: run --continue=sess-1") {
+		t.Fatalf("stderr = %q, want resume args", result.Error)
+	}
+
+	streamEvents := 0
+	for _, ev := range rec.Events() {
+		if ev.Type == "claude_stream" {
+			streamEvents++
+		}
+	}
+	if streamEvents != 3 {
+		t.Fatalf("recorded %d claude_stream events, want 3", streamEvents)
+	}
+}
+
+func TestPopulateFromConfigRegistersPlugin(t *testing.T) {
+	path := writeFakePlugin(t, t.TempDir())
+	m, err := DescribePlugin(context.Background(), path)
+	if err != nil {
+		t.Fatalf("DescribePlugin() error = %v", err)
+	}
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(registry, m.Name)
+		registryMu.Unlock()
+	})
+
+	PopulateFromConfig(&AgentsConfig{Agents: map[string]AgentRecord{
+		m.Name: {Name: m.Name, Path: path, Detected: true, Plugin: m},
+	}})
+
+	a, ok := Get(m.Name)
+	if !ok {
+		t.Fatalf("plugin %q not registered", m.Name)
+	}
+	if _, ok := a.(*PluginAgent); !ok {
+		t.Fatalf("registered agent = %T, want *PluginAgent", a)
+	}
+	if got := DefaultModel(m.Name); got != "fast" {
+		t.Fatalf("DefaultModel() = %q, want fast", got)
+	}
+	if !IsModelSupported(m.Name, "smart") {
+		t.Fatalf("IsModelSupported(smart) = false, want true")
+	}
+
+	spec := BuildLaunchSpec(&config.Profile{Agent: m.Name, Model: "smart", ReasoningLevel: "high"}, nil, "")
+	if spec.Command != path {
+		t.Fatalf("Command = %q, want plugin path %q", spec.Command, path)
+	}
+	wantArgs := []string{"-m", "smart", "--reasoning", "high"}
+	if !reflect.DeepEqual(spec.Args, wantArgs) {
+		t.Fatalf("Args = %#v, want %#v", spec.Args, wantArgs)
+	}
+}
@@ -2,6 +2,8 @@ package agent
 
 import (
 	"sync"
+
+	"github.com/agusx1211/adaf/internal/agentmeta"
 )
 
 var (
@@ -47,8 +49,10 @@ func All() map[string]Agent {
 }
 
 // PopulateFromConfig adds agents found in the persisted config to the registry
-// as generic agents (if not already registered). This avoids running PATH
-// detection — it only uses the previously cached ~/.adaf/agents.json.
+// (if not already registered). Plugin adapters are registered as PluginAgents
+// with their cached metadata; anything else becomes a generic agent. This
+// avoids running PATH detection — it only uses the previously cached
+// ~/.adaf/agents.json.
 func PopulateFromConfig(cfg *AgentsConfig) {
 	if cfg == nil {
 		return
@@ -59,6 +63,15 @@ func PopulateFromConfig(cfg *AgentsConfig) {
 		if name == "" || !rec.Detected {
 			continue
 		}
+		if rec.Plugin != nil && rec.Plugin.Validate() == nil {
+			existing, exists := registry[name]
+			if _, isPlugin := existing.(*PluginAgent); exists && !isPlugin {
+				continue
+			}
+			registry[name] = NewPluginAgent(rec.Path, *rec.Plugin)
+			agentmeta.Register(rec.Plugin.Info(rec.Path))
+			continue
+		}
 		if _, exists := registry[name]; exists {
 			continue
 		}
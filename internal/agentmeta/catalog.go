@@ -3,6 +3,7 @@ package agentmeta
 import (
 	"sort"
 	"strings"
+	"sync"
 )
 
 // ReasoningLevel represents a named reasoning effort option for an agent.
@@ -110,22 +111,61 @@ var builtin = map[string]Info{
 	},
 }
 
+var (
+	pluginsMu sync.RWMutex
+	plugins   = map[string]Info{}
+)
+
+// Register adds metadata for an externally provided (plugin) agent.
+// Built-in agents cannot be overridden; Register reports whether the
+// metadata was accepted.
+func Register(info Info) bool {
+	name := strings.ToLower(strings.TrimSpace(info.Name))
+	if name == "" {
+		return false
+	}
+	if _, ok := builtin[name]; ok {
+		return false
+	}
+	info.Name = name
+	pluginsMu.Lock()
+	plugins[name] = clone(info)
+	pluginsMu.Unlock()
+	return true
+}
+
+// IsBuiltin reports whether name is one of the agents compiled into adaf.
+func IsBuiltin(name string) bool {
+	_, ok := builtin[strings.ToLower(strings.TrimSpace(name))]
+	return ok
+}
+
 // InfoFor returns metadata for an agent name.
 func InfoFor(name string) (Info, bool) {
 	name = strings.ToLower(strings.TrimSpace(name))
 	info, ok := builtin[name]
 	if !ok {
-		return Info{}, false
+		pluginsMu.RLock()
+		info, ok = plugins[name]
+		pluginsMu.RUnlock()
+		if !ok {
+			return Info{}, false
+		}
 	}
 	return clone(info), true
 }
 
 // Names returns known agent names in stable order.
 func Names() []string {
-	names := make([]string, 0, len(builtin))
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+	names := make([]string, 0, len(builtin)+len(plugins))
 	for name := range builtin {
 		names = append(names, name)
 	}
+	for name := range plugins {
+		names = append(names, name)
+	}
 	sort.Strings(names)
 	return names
 }
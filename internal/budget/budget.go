@@ -0,0 +1,267 @@
+// Package budget tracks live agent spend against USD and token limits across
+// a loop run, its steps, and the spawn tree below them.
+package budget
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/agusx1211/adaf/internal/config"
+)
+
+// Tracker states.
+const (
+	StateOK          = ""
+	StateWindingDown = "winding_down"
+	StateExceeded    = "exceeded"
+)
+
+// treeMu guards every tracker's counters and links. Spend updates are rare
+// compared to agent work, so one lock keeps re-parenting simple.
+var treeMu sync.Mutex
+
+// Usage is an amount of agent spend.
+type Usage struct {
+	CostUSD float64
+	Tokens  int
+}
+
+// Tracker accumulates spend for one scope (loop run, step, or spawn). Spend
+// added to a tracker is also added to all of its ancestors, so a limit on a
+// scope covers everything running beneath it.
+type Tracker struct {
+	name   string
+	limit  config.Budget
+	parent *Tracker
+
+	spent    Usage
+	state    string
+	children []*Tracker
+
+	onSoft   func(*Tracker)
+	onHard   func(*Tracker)
+	onChange func(*Tracker)
+}
+
+// New creates a tracker for name with an optional limit, linked under parent
+// when parent is non-nil.
+func New(name string, limit *config.Budget, parent *Tracker) *Tracker {
+	t := &Tracker{name: name, parent: parent}
+	if limit != nil {
+		t.limit = *limit
+	}
+	if parent != nil {
+		treeMu.Lock()
+		parent.children = append(parent.children, t)
+		treeMu.Unlock()
+	}
+	return t
+}
+
+// OnSoftLimit registers fn to run once when the wind-down threshold is crossed.
+func (t *Tracker) OnSoftLimit(fn func(*Tracker)) {
+	treeMu.Lock()
+	t.onSoft = fn
+	treeMu.Unlock()
+}
+
+// OnHardLimit registers fn to run once when this tracker or any ancestor
+// crosses its hard limit.
+func (t *Tracker) OnHardLimit(fn func(*Tracker)) {
+	treeMu.Lock()
+	t.onHard = fn
+	treeMu.Unlock()
+}
+
+// OnChange registers fn to run after every spend update of this tracker.
+func (t *Tracker) OnChange(fn func(*Tracker)) {
+	treeMu.Lock()
+	t.onChange = fn
+	treeMu.Unlock()
+}
+
+// Name returns the tracker's display name.
+func (t *Tracker) Name() string {
+	return t.name
+}
+
+// Limit returns the tracker's configured limit.
+func (t *Tracker) Limit() config.Budget {
+	return t.limit
+}
+
+// Spent returns the spend accumulated so far.
+func (t *Tracker) Spent() Usage {
+	treeMu.Lock()
+	defer treeMu.Unlock()
+	return t.spent
+}
+
+// State returns StateOK, StateWindingDown or StateExceeded. A tracker whose
+// ancestor exceeded its limit reports StateExceeded too.
+func (t *Tracker) State() string {
+	treeMu.Lock()
+	defer treeMu.Unlock()
+	return t.stateLocked()
+}
+
+func (t *Tracker) stateLocked() string {
+	state := t.state
+	for n := t.parent; n != nil; n = n.parent {
+		if n.state == StateExceeded {
+			return StateExceeded
+		}
+	}
+	return state
+}
+
+// Add records spend on t and its ancestors and fires any limit callbacks
+// outside the lock.
+func (t *Tracker) Add(u Usage) {
+	if t == nil || (u.CostUSD == 0 && u.Tokens == 0) {
+		return
+	}
+	var fire []func()
+
+	treeMu.Lock()
+	for n := t; n != nil; n = n.parent {
+		n.spent.CostUSD += u.CostUSD
+		n.spent.Tokens += u.Tokens
+		if n.onChange != nil {
+			node, fn := n, n.onChange
+			fire = append(fire, func() { fn(node) })
+		}
+		switch {
+		case n.state != StateExceeded && n.limit.Exceeded(n.spent.CostUSD, n.spent.Tokens):
+			n.state = StateExceeded
+			fire = append(fire, n.hardCallbacksLocked()...)
+		case n.state == StateOK && n.limit.SoftExceeded(n.spent.CostUSD, n.spent.Tokens):
+			n.state = StateWindingDown
+			if n.onSoft != nil {
+				node, fn := n, n.onSoft
+				fire = append(fire, func() { fn(node) })
+			}
+		}
+	}
+	treeMu.Unlock()
+
+	for _, fn := range fire {
+		fn()
+	}
+}
+
+// hardCallbacksLocked collects the hard-limit callbacks of t and its
+// whole subtree, so crossing a limit cancels everything beneath it.
+func (t *Tracker) hardCallbacksLocked() []func() {
+	var out []func()
+	var walk func(n *Tracker)
+	walk = func(n *Tracker) {
+		if n.onHard != nil {
+			fn := n.onHard
+			out = append(out, func() { fn(t) })
+		}
+		for _, c := range n.children {
+			walk(c)
+		}
+	}
+	walk(t)
+	return out
+}
+
+// Detach unlinks t from its parent once its scope has finished. Children
+// that are still running (e.g. handed-off spawns) move up to t's parent so
+// their spend stays accounted.
+func (t *Tracker) Detach() {
+	treeMu.Lock()
+	defer treeMu.Unlock()
+	p := t.parent
+	if p == nil {
+		return
+	}
+	for i, c := range p.children {
+		if c == t {
+			p.children = append(p.children[:i], p.children[i+1:]...)
+			break
+		}
+	}
+	for _, c := range t.children {
+		c.parent = p
+		p.children = append(p.children, c)
+	}
+	t.children = nil
+}
+
+// Err describes the limit t crossed, or returns nil when it has not.
+func (t *Tracker) Err() error {
+	treeMu.Lock()
+	defer treeMu.Unlock()
+	for n := t; n != nil; n = n.parent {
+		if n.state == StateExceeded {
+			return &ExceededError{Name: n.name, Spent: n.spent, Limit: n.limit}
+		}
+	}
+	return nil
+}
+
+// ExceededError reports a scope that crossed its hard limit.
+type ExceededError struct {
+	Name  string
+	Spent Usage
+	Limit config.Budget
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("budget exceeded for %s: spent %s", e.Name, Describe(e.Spent, e.Limit))
+}
+
+// Describe renders spend against a limit, e.g. "$4.10 of $5.00, 820000 of 1000000 tokens".
+func Describe(spent Usage, limit config.Budget) string {
+	cost := fmt.Sprintf("$%.2f", spent.CostUSD)
+	if limit.MaxCostUSD > 0 {
+		cost += fmt.Sprintf(" of $%.2f", limit.MaxCostUSD)
+	}
+	tokens := fmt.Sprintf("%d", spent.Tokens)
+	if limit.MaxTokens > 0 {
+		tokens += fmt.Sprintf(" of %d", limit.MaxTokens)
+	}
+	return cost + ", " + tokens + " tokens"
+}
+
+// WindDownMessage is the interrupt sent to an agent when its scope crosses
+// the soft threshold.
+func WindDownMessage(t *Tracker) string {
+	return fmt.Sprintf("Budget notice: %s has used %s. "+
+		"Wrap up now: finish or commit the work in progress, report what is done and what remains, and end your turn. "+
+		"Do not start new work or spawn new sub-agents; the run is cancelled when the hard limit is reached.",
+		t.Name(), Describe(t.Spent(), t.Limit()))
+}
+
+var (
+	turnMu sync.Mutex
+	byTurn = make(map[int]*Tracker)
+)
+
+// Bind associates a turn with the tracker that pays for it, so spawns
+// requested from that turn are accounted under the same tree.
+func Bind(turnID int, t *Tracker) {
+	if turnID <= 0 || t == nil {
+		return
+	}
+	turnMu.Lock()
+	byTurn[turnID] = t
+	turnMu.Unlock()
+}
+
+// Unbind removes a turn association created by Bind.
+func Unbind(turnID int) {
+	turnMu.Lock()
+	delete(byTurn, turnID)
+	turnMu.Unlock()
+}
+
+// ForTurn returns the tracker bound to turnID, or nil.
+func ForTurn(turnID int) *Tracker {
+	turnMu.Lock()
+	defer turnMu.Unlock()
+	return byTurn[turnID]
+}
@@ -0,0 +1,148 @@
+package budget
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/stream"
+)
+
+func TestTrackerPropagatesToAncestors(t *testing.T) {
+	run := New("run", &config.Budget{MaxTokens: 1000}, nil)
+	step := New("step", nil, run)
+	spawn := New("spawn", nil, step)
+
+	spawn.Add(Usage{CostUSD: 0.5, Tokens: 100})
+	step.Add(Usage{Tokens: 50})
+
+	if got := run.Spent(); got.Tokens != 150 || got.CostUSD != 0.5 {
+		t.Fatalf("run spent = %+v, want 150 tokens and $0.50", got)
+	}
+	if got := step.Spent().Tokens; got != 150 {
+		t.Fatalf("step tokens = %d, want 150", got)
+	}
+	if got := spawn.Spent().Tokens; got != 100 {
+		t.Fatalf("spawn tokens = %d, want 100", got)
+	}
+}
+
+func TestTrackerLimitCallbacksFireOnce(t *testing.T) {
+	run := New("run", &config.Budget{MaxCostUSD: 10}, nil)
+	var soft, hard int
+	run.OnSoftLimit(func(*Tracker) { soft++ })
+	run.OnHardLimit(func(*Tracker) { hard++ })
+
+	run.Add(Usage{CostUSD: 5})
+	if soft != 0 || hard != 0 {
+		t.Fatalf("callbacks below threshold: soft=%d hard=%d", soft, hard)
+	}
+	run.Add(Usage{CostUSD: 3})
+	run.Add(Usage{CostUSD: 1})
+	if soft != 1 || hard != 0 {
+		t.Fatalf("after soft threshold: soft=%d hard=%d, want 1/0", soft, hard)
+	}
+	if got := run.State(); got != StateWindingDown {
+		t.Fatalf("state = %q, want %q", got, StateWindingDown)
+	}
+	run.Add(Usage{CostUSD: 1})
+	run.Add(Usage{CostUSD: 1})
+	if hard != 1 {
+		t.Fatalf("hard callbacks = %d, want 1", hard)
+	}
+
+	var exceeded *ExceededError
+	if err := run.Err(); !errors.As(err, &exceeded) || exceeded.Name != "run" {
+		t.Fatalf("Err() = %v, want ExceededError for run", err)
+	}
+}
+
+func TestTrackerHardLimitCancelsSubtree(t *testing.T) {
+	run := New("run", &config.Budget{MaxTokens: 100}, nil)
+	step := New("step", nil, run)
+	spawn := New("spawn", nil, step)
+
+	var tripped []string
+	step.OnHardLimit(func(t *Tracker) { tripped = append(tripped, "step:"+t.Name()) })
+	spawn.OnHardLimit(func(t *Tracker) { tripped = append(tripped, "spawn:"+t.Name()) })
+
+	spawn.Add(Usage{Tokens: 100})
+
+	if len(tripped) != 2 || tripped[0] != "step:run" || tripped[1] != "spawn:run" {
+		t.Fatalf("hard callbacks = %v, want step and spawn notified of run", tripped)
+	}
+	if got := spawn.State(); got != StateExceeded {
+		t.Fatalf("spawn state = %q, want %q", got, StateExceeded)
+	}
+	if spawn.Err() == nil {
+		t.Fatal("spawn Err() = nil, want ancestor limit error")
+	}
+}
+
+func TestTrackerDetachReparentsChildren(t *testing.T) {
+	run := New("run", nil, nil)
+	step := New("step", nil, run)
+	spawn := New("spawn", nil, step)
+
+	step.Detach()
+	spawn.Add(Usage{Tokens: 10})
+
+	if got := run.Spent().Tokens; got != 10 {
+		t.Fatalf("run tokens after detach = %d, want 10", got)
+	}
+	if got := step.Spent().Tokens; got != 0 {
+		t.Fatalf("detached step tokens = %d, want 0", got)
+	}
+}
+
+func TestTurnBinding(t *testing.T) {
+	tr := New("step", nil, nil)
+	Bind(4242, tr)
+	if ForTurn(4242) != tr {
+		t.Fatal("ForTurn() did not return bound tracker")
+	}
+	Unbind(4242)
+	if ForTurn(4242) != nil {
+		t.Fatal("ForTurn() after Unbind should be nil")
+	}
+}
+
+func TestMeterReconcilesResultUsage(t *testing.T) {
+	var m Meter
+	assistant := func(id string, in, out int) stream.ClaudeEvent {
+		return stream.ClaudeEvent{
+			Type: "assistant",
+			AssistantMessage: &stream.AssistantMessage{
+				ID:    id,
+				Usage: &stream.Usage{InputTokens: in, OutputTokens: out},
+			},
+		}
+	}
+
+	var total Usage
+	add := func(u Usage) {
+		total.CostUSD += u.CostUSD
+		total.Tokens += u.Tokens
+	}
+	add(m.Observe(assistant("msg_1", 100, 10)))
+	add(m.Observe(assistant("msg_1", 100, 10))) // repeated block of the same message
+	add(m.Observe(assistant("msg_2", 200, 20)))
+	if total.Tokens != 330 {
+		t.Fatalf("provisional tokens = %d, want 330", total.Tokens)
+	}
+
+	add(m.Observe(stream.ClaudeEvent{
+		Type:         "result",
+		TotalCostUSD: 0.25,
+		Usage:        &stream.Usage{InputTokens: 300, OutputTokens: 50},
+	}))
+	if total.Tokens != 350 || total.CostUSD != 0.25 {
+		t.Fatalf("total after result = %+v, want 350 tokens and $0.25", total)
+	}
+
+	// Agents that only report usage on results are counted once.
+	add(m.Observe(stream.ClaudeEvent{Type: "result", Usage: &stream.Usage{InputTokens: 10, OutputTokens: 5}}))
+	if total.Tokens != 365 {
+		t.Fatalf("total after second result = %d, want 365", total.Tokens)
+	}
+}
@@ -0,0 +1,48 @@
+package budget
+
+import "github.com/agusx1211/adaf/internal/stream"
+
+// Meter turns the parsed event stream of one agent into incremental Usage.
+// Assistant message usage is counted provisionally while a turn streams;
+// the following result event is authoritative and replaces it.
+type Meter struct {
+	pending      map[string]int // assistant message ID -> tokens counted so far
+	pendingTotal int
+}
+
+// Observe returns the spend added by ev.
+func (m *Meter) Observe(ev stream.ClaudeEvent) Usage {
+	switch ev.Type {
+	case "assistant":
+		if ev.AssistantMessage == nil || ev.AssistantMessage.Usage == nil {
+			return Usage{}
+		}
+		tokens := usageTokens(ev.AssistantMessage.Usage)
+		if m.pending == nil {
+			m.pending = make(map[string]int)
+		}
+		id := ev.AssistantMessage.ID
+		delta := tokens - m.pending[id]
+		if delta <= 0 {
+			return Usage{}
+		}
+		m.pending[id] = tokens
+		m.pendingTotal += delta
+		return Usage{Tokens: delta}
+
+	case "result":
+		// Without result usage the provisional counts stand as final.
+		u := Usage{CostUSD: ev.TotalCostUSD}
+		if ev.Usage != nil {
+			u.Tokens = usageTokens(ev.Usage) - m.pendingTotal
+		}
+		m.pending = nil
+		m.pendingTotal = 0
+		return u
+	}
+	return Usage{}
+}
+
+func usageTokens(u *stream.Usage) int {
+	return u.InputTokens + u.OutputTokens
+}
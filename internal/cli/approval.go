@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agusx1211/adaf/internal/session"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+var approvalsCmd = &cobra.Command{
+	Use:     "approvals",
+	Aliases: []string{"approval"},
+	Short:   "List actions waiting for human approval",
+	Long: `List actions waiting for human approval.
+
+Approval policies on a loop or a team's delegation make agents wait for a
+human before gated actions:
+
+  "approval": {"merge_line_limit": 300, "steps": ["deploy"]}
+
+"merges": true gates every spawn merge, "merge_line_limit" gates merges that
+change more lines, and "steps" (loops only) lists step IDs or profiles to
+pause before.
+
+A gated spawn-merge files an approval and tells the agent it is blocked; the
+agent re-runs the merge once approved. A gated loop step waits until decided;
+a denied step is skipped. Decide with 'adaf approve' or 'adaf deny'; the
+optional comment is relayed to the agent.
+
+Examples:
+  adaf approvals
+  adaf approvals --all
+  adaf approvals show 4
+  adaf approvals wait 4 --timeout 30m`,
+	RunE: runApprovalsList,
+}
+
+var approvalsShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show one approval",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runApprovalsShow,
+}
+
+var approvalsWaitCmd = &cobra.Command{
+	Use:   "wait <id>",
+	Short: "Block until an approval is decided",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runApprovalsWait,
+}
+
+var approveCmd = &cobra.Command{
+	Use:   "approve <id>",
+	Short: "Approve a pending action",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return decideApproval(cmd, args[0], true)
+	},
+}
+
+var denyCmd = &cobra.Command{
+	Use:   "deny <id>",
+	Short: "Deny a pending action",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return decideApproval(cmd, args[0], false)
+	},
+}
+
+func init() {
+	approvalsCmd.Flags().Bool("all", false, "Include decided approvals")
+	approvalsWaitCmd.Flags().Duration("timeout", 0, "Give up after this long (0 = wait forever)")
+	for _, c := range []*cobra.Command{approveCmd, denyCmd} {
+		c.Flags().StringP("comment", "m", "", "Comment relayed to the agent")
+		c.Flags().String("by", "", "Reviewer name (default: human)")
+	}
+	approvalsCmd.AddCommand(approvalsShowCmd)
+	approvalsCmd.AddCommand(approvalsWaitCmd)
+	rootCmd.AddCommand(approvalsCmd)
+	rootCmd.AddCommand(approveCmd)
+	rootCmd.AddCommand(denyCmd)
+}
+
+func runApprovalsList(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	all, _ := cmd.Flags().GetBool("all")
+	var list []store.Approval
+	if all {
+		list, err = s.ListApprovals()
+	} else {
+		list, err = s.PendingApprovals()
+	}
+	if err != nil {
+		return fmt.Errorf("listing approvals: %w", err)
+	}
+
+	printHeader("Approvals")
+	if len(list) == 0 {
+		fmt.Printf("  %sNo pending approvals.%s\n\n", colorDim, colorReset)
+		return nil
+	}
+	headers := []string{"ID", "KIND", "STATUS", "SUMMARY", "REASON", "CREATED"}
+	var rows [][]string
+	for i := len(list) - 1; i >= 0; i-- {
+		a := list[i]
+		rows = append(rows, []string{
+			fmt.Sprintf("#%d", a.ID),
+			a.Kind,
+			a.Status,
+			truncate(a.Summary, 48),
+			truncate(a.Reason, 40),
+			a.CreatedAt.Local().Format("2006-01-02 15:04"),
+		})
+	}
+	printTable(headers, rows)
+	fmt.Println()
+	return nil
+}
+
+func runApprovalsShow(cmd *cobra.Command, args []string) error {
+	_, a, err := loadApproval(args[0])
+	if err != nil {
+		return err
+	}
+	printHeader(fmt.Sprintf("Approval #%d", a.ID))
+	printApproval(a)
+	fmt.Println()
+	return nil
+}
+
+func runApprovalsWait(cmd *cobra.Command, args []string) error {
+	s, a, err := loadApproval(args[0])
+	if err != nil {
+		return err
+	}
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	for a.Status == store.ApprovalPending {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return fmt.Errorf("approval #%d still pending after %s", a.ID, timeout)
+		}
+		time.Sleep(2 * time.Second)
+		if a, err = s.GetApproval(a.ID); err != nil {
+			return fmt.Errorf("reading approval: %w", err)
+		}
+	}
+	fmt.Printf("Approval #%d %s by %s.\n", a.ID, a.Status, approvalDecider(a))
+	if a.Comment != "" {
+		fmt.Printf("Comment: %s\n", a.Comment)
+	}
+	if a.Status != store.ApprovalApproved {
+		return fmt.Errorf("approval #%d was %s", a.ID, a.Status)
+	}
+	return nil
+}
+
+func decideApproval(cmd *cobra.Command, ref string, approve bool) error {
+	if session.IsAgentContext() {
+		return fmt.Errorf("approvals must be decided by a human, not from an agent context")
+	}
+	s, a, err := loadApproval(ref)
+	if err != nil {
+		return err
+	}
+	comment, _ := cmd.Flags().GetString("comment")
+	by, _ := cmd.Flags().GetString("by")
+	a, err = s.DecideApproval(a.ID, approve, resolveIssueActor(by), comment)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n  %sApproval #%d %s.%s\n", styleBoldGreen, a.ID, a.Status, colorReset)
+	printApproval(a)
+	fmt.Println()
+	return nil
+}
+
+func loadApproval(ref string) (*store.Store, *store.Approval, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(ref), "#"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid approval ID %q", ref)
+	}
+	s, err := openStoreRequired()
+	if err != nil {
+		return nil, nil, err
+	}
+	a, err := s.GetApproval(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("approval #%d not found", id)
+	}
+	return s, a, nil
+}
+
+func printApproval(a *store.Approval) {
+	printField("Kind", a.Kind)
+	printField("Status", a.Status)
+	printField("Summary", a.Summary)
+	if a.Reason != "" {
+		printField("Reason", a.Reason)
+	}
+	if a.Kind == store.ApprovalKindMerge {
+		printField("Spawn", fmt.Sprintf("#%d (%s)", a.SpawnID, a.Branch))
+		if a.DiffLines > 0 {
+			printField("Changed Lines", fmt.Sprintf("%d", a.DiffLines))
+		}
+	}
+	if a.Kind == store.ApprovalKindLoopStep {
+		printField("Loop Run", fmt.Sprintf("#%d (%s)", a.LoopRunID, a.LoopName))
+		printField("Step", fmt.Sprintf("%d (%s), cycle %d", a.StepIndex, a.StepLabel, a.Cycle))
+	}
+	printField("Created", a.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	if !a.DecidedAt.IsZero() {
+		printField("Decided", a.DecidedAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	if a.DecidedBy != "" {
+		printField("Decided By", a.DecidedBy)
+	}
+	if a.Comment != "" {
+		printField("Comment", a.Comment)
+	}
+}
+
+func approvalDecider(a *store.Approval) string {
+	if a.DecidedBy == "" {
+		return "a human"
+	}
+	return a.DecidedBy
+}
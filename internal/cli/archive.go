@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Export the project store as a portable archive",
+	Long: `Write plans, issues, wiki, turns, loop runs, spawns and stats to a versioned
+.tar.gz archive that 'adaf import' can load on another machine.
+
+Turn recordings are large and excluded unless --recordings is set. With no
+file argument the archive is written to <project>-<date>.adaf.tar.gz; use "-"
+for stdout.
+
+Examples:
+  adaf export
+  adaf export backup.adaf.tar.gz --recordings
+  adaf export - | ssh host 'cd repo && adaf import -'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExport,
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import a project archive into this project",
+	Long: `Load an archive written by 'adaf export'. In a directory without an adaf
+project, a new project is initialized from the archive.
+
+Into an existing project the archive is merged: numeric IDs (turns, issues,
+loop runs, spawns) are kept when free and remapped after the current highest
+ID otherwise, plans and wiki entries with a taken ID get an "-imported"
+suffix, and references between records follow the new IDs. Loop runs and
+spawns that were running at export time are imported as stopped.
+
+Examples:
+  adaf import backup.adaf.tar.gz
+  adaf import backup.adaf.tar.gz --dry-run
+  adaf import backup.adaf.tar.gz --remap-ids`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	exportCmd.Flags().Bool("recordings", false, "Include turn recordings")
+	importCmd.Flags().Bool("remap-ids", false, "Assign fresh IDs to every imported turn, issue, loop run and spawn")
+	importCmd.Flags().Bool("dry-run", false, "Validate the archive and show what would be imported")
+	importCmd.Flags().Bool("json", false, "Output the import report as JSON")
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	includeRecordings, _ := cmd.Flags().GetBool("recordings")
+
+	target := ""
+	if len(args) > 0 {
+		target = strings.TrimSpace(args[0])
+	}
+	if target == "" {
+		name := "project"
+		if project, err := s.LoadProject(); err == nil && strings.TrimSpace(project.Name) != "" {
+			name = strings.ReplaceAll(strings.TrimSpace(project.Name), string(filepath.Separator), "-")
+		}
+		target = fmt.Sprintf("%s-%s.adaf.tar.gz", name, time.Now().Format("20060102"))
+	}
+
+	out := os.Stdout
+	if target != "-" {
+		f, err := os.Create(target)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", target, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	manifest, err := s.Export(out, store.ExportOptions{IncludeRecordings: includeRecordings})
+	if err != nil {
+		if target != "-" {
+			os.Remove(target)
+		}
+		return fmt.Errorf("exporting project: %w", err)
+	}
+	if target == "-" {
+		return nil
+	}
+
+	printHeader("Export")
+	printField("Archive", target)
+	printField("Schema", fmt.Sprintf("v%d", manifest.SchemaVersion))
+	printArchiveCounts(manifest.Counts)
+	fmt.Println()
+	return nil
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	remapIDs, _ := cmd.Flags().GetBool("remap-ids")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	in := os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("opening archive: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+	archive, err := store.ReadArchive(in)
+	if err != nil {
+		return err
+	}
+
+	s, err := openStore()
+	if err != nil {
+		return err
+	}
+	if !s.Exists() {
+		if dryRun {
+			return fmt.Errorf("no adaf project found; --dry-run needs an existing project to merge into")
+		}
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting working directory: %w", err)
+		}
+		projCfg := store.ProjectConfig{
+			Name:        archive.Project.Name,
+			RepoPath:    wd,
+			AgentConfig: archive.Project.AgentConfig,
+			Metadata:    archive.Project.Metadata,
+		}
+		if strings.TrimSpace(projCfg.Name) == "" {
+			projCfg.Name = filepath.Base(wd)
+		}
+		if err := s.Init(projCfg); err != nil {
+			return fmt.Errorf("initializing project: %w", err)
+		}
+	}
+	if err := s.EnsureDirs(); err != nil {
+		return fmt.Errorf("ensuring project store dirs: %w", err)
+	}
+
+	report, err := s.Import(archive, store.ImportOptions{RemapIDs: remapIDs, DryRun: dryRun})
+	if err != nil {
+		return fmt.Errorf("importing archive: %w", err)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	title := "Import"
+	if dryRun {
+		title = "Import (dry run)"
+	}
+	printHeader(title)
+	printField("Project", report.Manifest.ProjectName)
+	printField("Exported", report.Manifest.ExportedAt.Local().Format("2006-01-02 15:04"))
+	printArchiveCounts(report.Imported)
+	for kind, n := range report.Remapped {
+		printField("Remapped "+kind, fmt.Sprintf("%d", n))
+	}
+	for kind, n := range report.Merged {
+		printField("Merged "+kind, fmt.Sprintf("%d", n))
+	}
+	renamed := make([]string, 0, len(report.Renamed))
+	for from, to := range report.Renamed {
+		renamed = append(renamed, from+" -> "+to)
+	}
+	sort.Strings(renamed)
+	for _, r := range renamed {
+		printField("Renamed", r)
+	}
+	fmt.Println()
+	return nil
+}
+
+func printArchiveCounts(counts map[string]int) {
+	kinds := make([]string, 0, len(counts))
+	for kind, n := range counts {
+		if n > 0 {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		label := strings.ReplaceAll(kind, "_", " ")
+		printField(strings.ToUpper(label[:1])+label[1:], fmt.Sprintf("%d", counts[kind]))
+	}
+}
@@ -10,6 +10,7 @@ var configCmd = &cobra.Command{
 
 Use subcommands like:
   adaf config agents
+  adaf config prices
   adaf config pushover`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return cmd.Help()
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agusx1211/adaf/internal/session"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+var inboxCmd = &cobra.Command{
+	Use:     "inbox",
+	Aliases: []string{"asks"},
+	Short:   "List questions child agents are waiting on",
+	Long: `List every pending 'adaf parent-ask' question across all sessions.
+
+Operators can answer any of them directly, even when the asking child's
+parent is itself blocked or waiting for spawns. Set "ask_escalation_minutes"
+in ~/.adaf/config.json to move unanswered questions up the spawn tree, one
+ancestor per interval, until they reach the human (which also sends a
+parent_ask notification).
+
+Inside an agent context only the questions currently addressed to your turn
+are listed.
+
+Examples:
+  adaf inbox
+  adaf inbox answer 7 "Use the v2 API; v1 is deprecated"`,
+	RunE: runInboxList,
+}
+
+var inboxAnswerCmd = &cobra.Command{
+	Use:     "answer <spawn-id> <answer>",
+	Aliases: []string{"reply"},
+	Short:   "Answer a pending question",
+	Args:    cobra.ExactArgs(2),
+	RunE:    runInboxAnswer,
+}
+
+func init() {
+	inboxAnswerCmd.Flags().String("by", "", "Name recorded on the answer (default: human)")
+	inboxCmd.AddCommand(inboxAnswerCmd)
+	rootCmd.AddCommand(inboxCmd)
+}
+
+func runInboxList(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	asks, err := inboxAsks(s)
+	if err != nil {
+		return fmt.Errorf("listing questions: %w", err)
+	}
+
+	printHeader("Inbox")
+	if len(asks) == 0 {
+		fmt.Printf("  %sNo pending questions.%s\n\n", colorDim, colorReset)
+		return nil
+	}
+	headers := []string{"SPAWN", "PROFILE", "WAITING", "FOR", "QUESTION"}
+	var rows [][]string
+	for _, ask := range asks {
+		profile := "-"
+		if rec, err := s.GetSpawn(ask.SpawnID); err == nil {
+			profile = rec.ChildProfile
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("#%d", ask.SpawnID),
+			profile,
+			time.Since(ask.CreatedAt).Round(time.Second).String(),
+			askAddressee(ask),
+			truncate(ask.Content, 60),
+		})
+	}
+	printTable(headers, rows)
+	fmt.Printf("\n  %sAnswer with: adaf inbox answer <spawn-id> \"...\"%s\n\n", colorDim, colorReset)
+	return nil
+}
+
+func runInboxAnswer(cmd *cobra.Command, args []string) error {
+	spawnID, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(args[0]), "#"))
+	if err != nil {
+		return fmt.Errorf("invalid spawn ID %q", args[0])
+	}
+	answer := strings.TrimSpace(args[1])
+	if answer == "" {
+		return fmt.Errorf("answer must not be empty")
+	}
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	ask, err := s.PendingAsk(spawnID)
+	if err != nil {
+		return err
+	}
+	if ask == nil {
+		return fmt.Errorf("no pending question from spawn #%d", spawnID)
+	}
+	if session.IsAgentContext() {
+		turnID, _, _, err := getTurnContext()
+		if err != nil {
+			return err
+		}
+		if ask.ToHuman || ask.AddresseeTurnID != turnID {
+			return fmt.Errorf("spawn #%d's question is addressed to %s, not to this turn", spawnID, askAddressee(*ask))
+		}
+	}
+	by, _ := cmd.Flags().GetString("by")
+	if _, err := s.ReplyToAsk(spawnID, answer, resolveIssueActor(by)); err != nil {
+		return err
+	}
+	fmt.Printf("Answered spawn #%d: %s\n", spawnID, truncate(ask.Content, 80))
+	return nil
+}
+
+// inboxAsks returns the pending asks visible from the current context: all
+// of them for a human, those addressed to the current turn for an agent.
+func inboxAsks(s *store.Store) ([]store.SpawnMessage, error) {
+	asks, err := s.PendingAsks()
+	if err != nil || !session.IsAgentContext() {
+		return asks, err
+	}
+	turnID, _, _, err := getTurnContext()
+	if err != nil {
+		return nil, err
+	}
+	var mine []store.SpawnMessage
+	for _, ask := range asks {
+		if !ask.ToHuman && ask.AddresseeTurnID == turnID {
+			mine = append(mine, ask)
+		}
+	}
+	return mine, nil
+}
+
+func askAddressee(ask store.SpawnMessage) string {
+	switch {
+	case ask.ToHuman:
+		return "human (escalated)"
+	case ask.AddresseeTurnID > 0 && !ask.EscalatedAt.IsZero():
+		return fmt.Sprintf("turn #%d (escalated)", ask.AddresseeTurnID)
+	case ask.AddresseeTurnID > 0:
+		return fmt.Sprintf("turn #%d", ask.AddresseeTurnID)
+	default:
+		return "parent"
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/agusx1211/adaf/internal/store"
 	"github.com/spf13/cobra"
@@ -27,7 +28,9 @@ Examples:
   adaf issue create --title "Fix login bug" --priority high --by architect
   adaf issue show 3                            # Show issue details
   adaf issue move 3 --status in_review         # Move issue across board columns
-  adaf issue comment 3 --body "Ready for review"`,
+  adaf issue comment 3 --body "Ready for review"
+  adaf issue claim 3                           # Lease issue #3 to the current turn
+  adaf issue sync                              # Sync with the external tracker`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return cmd.Help()
 	},
@@ -101,6 +104,7 @@ func init() {
 	issueCreateCmd.Flags().IntSlice("depends-on", nil, "Issue IDs this issue depends on (comma-separated)")
 	issueCreateCmd.Flags().String("plan", "", "Plan scope for this issue (empty = shared)")
 	issueCreateCmd.Flags().Int("session", 0, "Associated turn ID (optional; defaults to current agent turn)")
+	addAcceptanceCheckFlags(issueCreateCmd)
 	_ = issueCreateCmd.MarkFlagRequired("title")
 
 	issueUpdateCmd.Flags().String("status", "", "New status")
@@ -113,6 +117,8 @@ func init() {
 	issueUpdateCmd.Flags().IntSlice("depends-on", nil, "Replace dependency issue IDs (comma-separated)")
 	issueUpdateCmd.Flags().String("plan", "", "Move issue to a plan scope (empty = shared)")
 	issueUpdateCmd.Flags().Int("session", 0, "Associated turn ID")
+	addAcceptanceCheckFlags(issueUpdateCmd)
+	issueUpdateCmd.Flags().Bool("clear-checks", false, "Remove all acceptance checks")
 
 	issueMoveCmd.Flags().String("status", "", "New status (open, ongoing, in_review, closed)")
 	issueMoveCmd.Flags().String("by", "", "Actor for history attribution (defaults to profile/role/human)")
@@ -273,6 +279,10 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("validating dependencies: %w", err)
 	}
+	checks, err := acceptanceChecksFromFlags(cmd)
+	if err != nil {
+		return err
+	}
 
 	if client := TryConnect(); client != nil {
 		projectID := projectIDFromPath(s.ProjectDir())
@@ -284,6 +294,7 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 			"status":      store.IssueStatusOpen,
 			"labels":      labels,
 			"depends_on":  normalizedDependsOn,
+			"checks":      checks,
 			"created_by":  actor,
 			"updated_by":  actor,
 		}
@@ -311,6 +322,9 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 			if len(normalizedDependsOn) > 0 {
 				printField("Depends On", formatIssueDependencyIDs(normalizedDependsOn))
 			}
+			if len(checks) > 0 {
+				printField("Checks", describeAcceptanceChecks(checks))
+			}
 			fmt.Println()
 			return nil
 		}
@@ -322,6 +336,7 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 		Priority:    priority,
 		Labels:      labels,
 		DependsOn:   normalizedDependsOn,
+		Checks:      checks,
 		PlanID:      planID,
 		TurnID:      turnID,
 		CreatedBy:   actor,
@@ -346,12 +361,22 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 	if issue.TurnID > 0 {
 		printField("Turn", fmt.Sprintf("#%d", issue.TurnID))
 	}
+	if ext := issue.External; ext != nil {
+		linked := fmt.Sprintf("%s #%s", ext.Provider, ext.ID)
+		if ext.URL != "" {
+			linked += " " + ext.URL
+		}
+		printField("External", linked)
+	}
 	if len(issue.Labels) > 0 {
 		printField("Labels", strings.Join(issue.Labels, ", "))
 	}
 	if len(issue.DependsOn) > 0 {
 		printField("Depends On", formatIssueDependencyIDs(issue.DependsOn))
 	}
+	if len(issue.Checks) > 0 {
+		printField("Checks", describeAcceptanceChecks(issue.Checks))
+	}
 	fmt.Println()
 
 	return nil
@@ -393,9 +418,15 @@ func runIssueShow(cmd *cobra.Command, args []string) error {
 	if len(issue.DependsOn) > 0 {
 		printField("Depends On", formatIssueDependencyIDs(issue.DependsOn))
 	}
+	if len(issue.Checks) > 0 {
+		printField("Checks", describeAcceptanceChecks(issue.Checks))
+	}
 	if issue.TurnID > 0 {
 		printField("Turn", fmt.Sprintf("#%d", issue.TurnID))
 	}
+	if claim := issue.ActiveClaim(time.Now()); claim != nil {
+		printField("Claimed By", fmt.Sprintf("%s until %s", claim.Holder(), claim.ExpiresAt.Local().Format("2006-01-02 15:04:05")))
+	}
 
 	if issue.Description != "" {
 		fmt.Println()
@@ -447,6 +478,7 @@ func runIssueUpdate(cmd *cobra.Command, args []string) error {
 	actorFlag, _ := cmd.Flags().GetString("by")
 	actor := resolveIssueActor(actorFlag)
 	changed := false
+	prevStatus := issue.Status
 
 	if cmd.Flags().Changed("status") {
 		status, _ := cmd.Flags().GetString("status")
@@ -534,8 +566,21 @@ func runIssueUpdate(cmd *cobra.Command, args []string) error {
 		changed = true
 	}
 
+	if clear, _ := cmd.Flags().GetBool("clear-checks"); clear {
+		issue.Checks = nil
+		changed = true
+	}
+	if cmd.Flags().Changed("check") || cmd.Flags().Changed("require-file") || cmd.Flags().Changed("max-diff-lines") {
+		checks, checkErr := acceptanceChecksFromFlags(cmd)
+		if checkErr != nil {
+			return checkErr
+		}
+		issue.Checks = store.MergeAcceptanceChecks(issue.Checks, checks)
+		changed = true
+	}
+
 	if !changed {
-		return fmt.Errorf("no fields to update (use --status, --title, --description, --priority, --labels, --depends-on, --plan, or --session)")
+		return fmt.Errorf("no fields to update (use --status, --title, --description, --priority, --labels, --depends-on, --plan, --session, or check flags)")
 	}
 
 	issue.UpdatedBy = actor
@@ -543,6 +588,7 @@ func runIssueUpdate(cmd *cobra.Command, args []string) error {
 	if err := s.UpdateIssue(issue); err != nil {
 		return fmt.Errorf("updating issue: %w", err)
 	}
+	notifyIssueMoved(s, prevStatus, issue)
 
 	fmt.Println()
 	fmt.Printf("  %sIssue #%d updated.%s\n", styleBoldGreen, issue.ID, colorReset)
@@ -587,11 +633,13 @@ func runIssueMove(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("getting issue #%d: %w", id, err)
 	}
+	prevStatus := issue.Status
 	issue.Status = status
 	issue.UpdatedBy = actor
 	if err := s.UpdateIssue(issue); err != nil {
 		return fmt.Errorf("moving issue: %w", err)
 	}
+	notifyIssueMoved(s, prevStatus, issue)
 
 	fmt.Println()
 	fmt.Printf("  %sIssue #%d moved to %s.%s\n", styleBoldGreen, issue.ID, statusBadge(issue.Status), colorReset)
@@ -733,6 +781,14 @@ func formatIssueHistoryEntry(item store.IssueHistory) string {
 		return fmt.Sprintf("%s by %s: status %q -> %q", prefix, actor, item.From, item.To)
 	case "moved":
 		return fmt.Sprintf("%s by %s: scope %q -> %q", prefix, actor, item.From, item.To)
+	case "claimed":
+		return fmt.Sprintf("%s by %s: claimed for %s (%s)", prefix, actor, item.To, item.Message)
+	case "released":
+		msg := fmt.Sprintf("%s by %s: released claim of %s", prefix, actor, item.From)
+		if strings.TrimSpace(item.Message) != "" {
+			msg += " (" + item.Message + ")"
+		}
+		return msg
 	case "updated":
 		field := strings.TrimSpace(item.Field)
 		if field == "" {
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// defaultIssueClaimTTL is the lease length of a claim made from the CLI.
+const defaultIssueClaimTTL = time.Hour
+
+var issueClaimCmd = &cobra.Command{
+	Use:     "claim <id>",
+	Aliases: []string{"take", "lease"},
+	Short:   "Claim an issue for the current turn or spawn",
+	Long: `Lease an issue to the current turn or spawn so parallel agents do not
+pick the same work. Claiming an issue you already hold renews the lease.
+A spawn's leases are released when it finishes; others expire after --ttl.
+
+Inside an agent turn the holder comes from ADAF_SPAWN_ID / ADAF_TURN_ID,
+and loop agents hold the lease for their step (ADAF_LOOP_RUN_ID /
+ADAF_LOOP_STEP_INDEX) across its turns; outside one pass --turn or --spawn.
+
+Examples:
+  adaf issue claim 3
+  adaf issue claim 3 --ttl 2h
+  adaf issue release 3`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueClaim,
+}
+
+var issueReleaseCmd = &cobra.Command{
+	Use:     "release <id>",
+	Aliases: []string{"unclaim"},
+	Short:   "Release a claim on an issue",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runIssueRelease,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{issueClaimCmd, issueReleaseCmd} {
+		c.Flags().Int("turn", 0, "Turn ID holding the claim (defaults to ADAF_TURN_ID)")
+		c.Flags().Int("spawn", 0, "Spawn ID holding the claim (defaults to ADAF_SPAWN_ID)")
+		c.Flags().String("by", "", "Actor for history attribution (defaults to profile/role/human)")
+	}
+	issueClaimCmd.Flags().Duration("ttl", defaultIssueClaimTTL, "Lease length")
+	issueReleaseCmd.Flags().Bool("force", false, "Release a claim held by someone else")
+	issueReleaseCmd.Flags().String("reason", "", "Why the claim is released")
+
+	issueCmd.AddCommand(issueClaimCmd)
+	issueCmd.AddCommand(issueReleaseCmd)
+}
+
+// issueClaimHolder builds the claim identity from flags, falling back to the
+// agent turn environment.
+func issueClaimHolder(cmd *cobra.Command) (store.IssueClaim, error) {
+	turnID, _ := cmd.Flags().GetInt("turn")
+	spawnID, _ := cmd.Flags().GetInt("spawn")
+	by, _ := cmd.Flags().GetString("by")
+	var loopRunID, loopStep int
+	if !cmd.Flags().Changed("turn") && !cmd.Flags().Changed("spawn") {
+		turnID, _ = strconv.Atoi(strings.TrimSpace(os.Getenv("ADAF_TURN_ID")))
+		spawnID, _ = strconv.Atoi(strings.TrimSpace(os.Getenv("ADAF_SPAWN_ID")))
+		loopRunID, _ = strconv.Atoi(strings.TrimSpace(os.Getenv("ADAF_LOOP_RUN_ID")))
+		loopStep, _ = strconv.Atoi(strings.TrimSpace(os.Getenv("ADAF_LOOP_STEP_INDEX")))
+	}
+	if turnID <= 0 && spawnID <= 0 {
+		return store.IssueClaim{}, fmt.Errorf("no claim holder: run inside an agent turn or pass --turn/--spawn")
+	}
+	return store.IssueClaim{
+		TurnID:    turnID,
+		SpawnID:   spawnID,
+		LoopRunID: loopRunID,
+		LoopStep:  loopStep,
+		By:        resolveIssueActor(by),
+	}, nil
+}
+
+func runIssueClaim(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid issue ID %q: must be a number", args[0])
+	}
+	holder, err := issueClaimHolder(cmd)
+	if err != nil {
+		return err
+	}
+	ttl, _ := cmd.Flags().GetDuration("ttl")
+
+	issue, err := s.ClaimIssue(id, holder, ttl)
+	if err != nil {
+		var claimed *store.IssueClaimedError
+		if errors.As(err, &claimed) {
+			return fmt.Errorf("%w; pick another issue", err)
+		}
+		return fmt.Errorf("claiming issue #%d: %w", id, err)
+	}
+
+	fmt.Printf("  %sClaimed issue #%d for %s until %s.%s\n", styleBoldGreen, issue.ID,
+		issue.Claim.Holder(), issue.Claim.ExpiresAt.Local().Format("2006-01-02 15:04:05"), colorReset)
+	return nil
+}
+
+func runIssueRelease(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid issue ID %q: must be a number", args[0])
+	}
+	reason, _ := cmd.Flags().GetString("reason")
+	force, _ := cmd.Flags().GetBool("force")
+
+	var holder *store.IssueClaim
+	if !force {
+		h, err := issueClaimHolder(cmd)
+		if err != nil {
+			return fmt.Errorf("%w (or pass --force)", err)
+		}
+		holder = &h
+	}
+	before, err := s.GetIssue(id)
+	if err != nil {
+		return fmt.Errorf("getting issue #%d: %w", id, err)
+	}
+	if before.Claim == nil {
+		fmt.Printf("  %sIssue #%d is not claimed.%s\n", colorDim, id, colorReset)
+		return nil
+	}
+	if _, err := s.ReleaseIssueClaim(id, holder, strings.TrimSpace(reason)); err != nil {
+		return fmt.Errorf("releasing issue #%d: %w", id, err)
+	}
+	fmt.Printf("  %sReleased the claim on issue #%d.%s\n", styleBoldGreen, id, colorReset)
+	return nil
+}
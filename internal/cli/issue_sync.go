@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agusx1211/adaf/internal/issuesync"
+)
+
+var issueSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync issues with the external tracker",
+	Long: `Reconcile project issues with an external issue tracker in both directions.
+
+Configure the tracker in .adaf/project.json:
+
+  "issue_sync": {"type": "http", "url": "http://localhost:9000",
+                 "token_env": "TRACKER_TOKEN", "interval_minutes": 15,
+                 "status_map": {"ongoing": "in progress", "closed": "done"}}
+
+"type" is http (a JSON REST endpoint exchanging issues, see the README) or
+file (a JSON file of issues, relative to the project dir via "path").
+"direction" is both (default), pull or push. With "interval_minutes" the web
+daemon also syncs periodically.
+
+Open external issues without a local copy are imported and open local issues
+without an external copy are exported; the link is stored on the issue.
+Status, priority, labels, dependencies, title and description are synced per
+field: the side that changed a field since the last sync wins, and when both
+did, the later edit wins and the field is reported as a conflict. Local
+edits are read from the issue history. Comments are copied both ways.
+
+Examples:
+  adaf issue sync`,
+	RunE: runIssueSync,
+}
+
+func init() {
+	issueCmd.AddCommand(issueSyncCmd)
+}
+
+func runIssueSync(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	sy, err := issuesync.NewSyncer(s)
+	if err != nil {
+		return err
+	}
+	res, err := sy.Sync(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	printHeader("Issue Sync")
+	printField("Imported", formatSyncedIssues(res.Imported))
+	printField("Exported", formatSyncedIssues(res.Exported))
+	printField("Fields Pulled", fmt.Sprintf("%d", res.Pulled))
+	printField("Fields Pushed", fmt.Sprintf("%d", res.Pushed))
+	printField("Comments", fmt.Sprintf("%d in, %d out", res.CommentsIn, res.CommentsOut))
+	if len(res.Missing) > 0 {
+		printFieldColored("Missing Remotely", formatIssueDependencyIDs(res.Missing), colorYellow)
+	}
+	if len(res.Conflicts) > 0 {
+		var parts []string
+		for _, c := range res.Conflicts {
+			parts = append(parts, fmt.Sprintf("#%d %s (%s kept)", c.IssueID, c.Field, c.Winner))
+		}
+		printFieldColored("Conflicts", strings.Join(parts, ", "), colorYellow)
+	}
+	fmt.Println()
+	return nil
+}
+
+func formatSyncedIssues(ids []int) string {
+	if len(ids) == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%d (%s)", len(ids), formatIssueDependencyIDs(ids))
+}
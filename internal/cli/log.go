@@ -485,6 +485,15 @@ func printTurn(turn *store.Turn) {
 	if turn.CommitHash != "" {
 		printField("Commit", turn.CommitHash)
 	}
+	if turn.RerunOfTurnID > 0 {
+		printField("Re-run Of", fmt.Sprintf("Turn #%d", turn.RerunOfTurnID))
+	}
+	if turn.FailoverFromTurnID > 0 {
+		printField("Failover Of", fmt.Sprintf("Turn #%d (%s)", turn.FailoverFromTurnID, turn.FailoverReason))
+	}
+	if turn.FailedOverTo != "" {
+		printField("Failed Over To", fmt.Sprintf("%s (%s)", turn.FailedOverTo, turn.FailoverReason))
+	}
 	if turn.DurationSecs > 0 {
 		mins := turn.DurationSecs / 60
 		secs := turn.DurationSecs % 60
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
@@ -9,10 +10,12 @@ import (
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
+	"github.com/agusx1211/adaf/internal/budget"
 	"github.com/agusx1211/adaf/internal/config"
 	loopctrl "github.com/agusx1211/adaf/internal/loop"
-	"github.com/agusx1211/adaf/internal/pushover"
+	"github.com/agusx1211/adaf/internal/notify"
 	"github.com/agusx1211/adaf/internal/session"
+	"github.com/agusx1211/adaf/internal/stats"
 	"github.com/agusx1211/adaf/internal/store"
 )
 
@@ -25,12 +28,13 @@ for N turns, with support for inter-step messaging and stop signals.
 
 Loops are defined in ~/.adaf/config.json and can chain multiple agent profiles
 together with built-in positions (supervisor, manager, lead).
-Steps can send Pushover notifications, post messages to subsequent steps,
+Steps can send notifications, post messages to subsequent steps,
 and signal the loop to stop.
 
 Examples:
   adaf loop list                          # Show defined loops
   adaf loop start dev-cycle               # Start a loop
+  adaf loop start dev-cycle --dry-run     # Show the estimated cost per cycle
   adaf loop status                        # Check active loop
   adaf loop stop                          # Supervisor: signal loop to stop
   adaf loop message "auth module done"    # Post inter-step message
@@ -79,10 +83,23 @@ var loopCallSupervisorCmd = &cobra.Command{
 	RunE:    loopCallSupervisor,
 }
 
+var loopOutcomeCmd = &cobra.Command{
+	Use:     "outcome <token>",
+	Aliases: []string{"result"},
+	Short:   "Report the current step's outcome for conditional transitions",
+	Long: `Records an outcome token for the current loop step. When the step ends,
+its transitions can route on it, e.g. "when": "outcome == tests_failing".
+Reads ADAF_LOOP_RUN_ID and ADAF_LOOP_STEP_INDEX from environment.
+Reporting again from the same step replaces the earlier token.`,
+	Args: cobra.ExactArgs(1),
+	RunE: loopOutcome,
+}
+
 var loopNotifyCmd = &cobra.Command{
 	Use:   "notify <title> <message>",
-	Short: "Send a Pushover notification",
-	Long: `Send a Pushover notification from a loop step.
+	Short: "Send a notification to the user",
+	Long: `Send a notification from a loop step to every sink subscribed to
+loop_notify (Pushover by default; see 'adaf config notify').
 Reads ADAF_LOOP_RUN_ID from environment to confirm running inside a loop.
 
 Title: max 250 characters.
@@ -104,8 +121,9 @@ var loopStatusCmd = &cobra.Command{
 func init() {
 	loopStartCmd.Flags().String("plan", "", "Plan ID override for this loop run (defaults to active plan)")
 	loopStartCmd.Flags().String("priority", "", "Resource allocation priority for delegation (quality, normal, cost)")
+	loopStartCmd.Flags().Bool("dry-run", false, "Validate the loop and show its estimated cost per cycle without starting it")
 	loopNotifyCmd.Flags().IntP("priority", "p", 0, "Notification priority (-2 to 1)")
-	loopCmd.AddCommand(loopListCmd, loopStartCmd, loopStopCmd, loopMessageCmd, loopCallSupervisorCmd, loopNotifyCmd, loopStatusCmd)
+	loopCmd.AddCommand(loopListCmd, loopStartCmd, loopStopCmd, loopMessageCmd, loopCallSupervisorCmd, loopOutcomeCmd, loopNotifyCmd, loopStatusCmd)
 	rootCmd.AddCommand(loopCmd)
 }
 
@@ -158,7 +176,11 @@ func loopList(cmd *cobra.Command, args []string) error {
 			if spawnCount > 0 {
 				spawnTag = fmt.Sprintf(" [spawn:%d]", spawnCount)
 			}
-			fmt.Printf("    %d. %s (%s) x%d%s%s\n", i+1, step.Profile, positionLabel, turns, spawnTag, flags)
+			stepLabel := step.Label()
+			if step.ID != "" {
+				stepLabel = fmt.Sprintf("%s: %s", step.ID, stepLabel)
+			}
+			fmt.Printf("    %d. %s (%s) x%d%s%s\n", i+1, stepLabel, positionLabel, turns, spawnTag, flags)
 			if step.Instructions != "" {
 				instr := step.Instructions
 				if len(instr) > 60 {
@@ -166,6 +188,13 @@ func loopList(cmd *cobra.Command, args []string) error {
 				}
 				fmt.Printf("       %s%s%s\n", colorDim, instr, colorReset)
 			}
+			for _, tr := range step.Transitions {
+				when := tr.When
+				if strings.TrimSpace(when) == "" {
+					when = "always"
+				}
+				fmt.Printf("       %s-> %s when %s%s\n", colorDim, tr.Goto, when, colorReset)
+			}
 		}
 		fmt.Println()
 	}
@@ -181,12 +210,16 @@ func loopStart(cmd *cobra.Command, args []string) error {
 	planFlag, _ := cmd.Flags().GetString("plan")
 	resourcePriorityFlag, _ := cmd.Flags().GetString("priority")
 
-	s, err := openStoreRequired()
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	// A dry run only reads the project, so the store is not repaired until
+	// the loop actually starts.
+	s, err := openStore()
 	if err != nil {
 		return err
 	}
-	if err := s.EnsureDirs(); err != nil {
-		return err
+	if !s.Exists() {
+		return fmt.Errorf("no adaf project found (run 'adaf init' first)")
 	}
 
 	globalCfg, err := config.Load()
@@ -205,13 +238,9 @@ func loopStart(cmd *cobra.Command, args []string) error {
 	loopDefCopy := *loopDef
 	loopDefCopy.ResourcePriority = resourcePriority
 
-	if len(loopDefCopy.Steps) == 0 {
-		return fmt.Errorf("loop %q has no steps", loopName)
-	}
-	for i, step := range loopDefCopy.Steps {
-		if err := config.ValidateLoopStepPosition(step, globalCfg); err != nil {
-			return fmt.Errorf("loop %q step %d invalid: %w", loopName, i, err)
-		}
+	profiles, err := globalCfg.PrepareLoopLaunch(&loopDefCopy)
+	if err != nil {
+		return err
 	}
 
 	projCfg, err := s.LoadProject()
@@ -223,9 +252,12 @@ func loopStart(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	profiles, err := loopProfilesSnapshot(globalCfg, &loopDefCopy)
-	if err != nil {
-		return err
+	if dryRun {
+		printLoopCostEstimate(s, globalCfg, &loopDefCopy)
+		return nil
+	}
+	if err := s.EnsureDirs(); err != nil {
+		return fmt.Errorf("ensuring project store dirs: %w", err)
 	}
 
 	workDir := projCfg.RepoPath
@@ -270,6 +302,54 @@ func loopStart(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printLoopCostEstimate shows the estimated cost of one loop cycle from the
+// step profiles' historical averages and the model price catalog.
+func printLoopCostEstimate(s *store.Store, globalCfg *config.GlobalConfig, loopDef *config.LoopDef) {
+	var profiles []config.Profile
+	for _, step := range loopDef.Steps {
+		if p := globalCfg.FindProfile(step.Profile); p != nil {
+			profiles = append(profiles, *p)
+		}
+	}
+	estimates := stats.EstimateProfileCosts(s, globalCfg, profiles)
+
+	printHeader(fmt.Sprintf("Dry run: %s", loopDef.Name))
+	headers := []string{"#", "Step", "Turns", "Est/Turn", "Est/Step"}
+	var rows [][]string
+	var cycleTotal float64
+	var unknown []string
+	delegates := false
+	for i, step := range loopDef.Steps {
+		turns := step.Turns
+		if turns <= 0 {
+			turns = 1
+		}
+		perTurn, perStep := colorDim+"n/a"+colorReset, colorDim+"n/a"+colorReset
+		if est, ok := estimates[strings.ToLower(strings.TrimSpace(step.Profile))]; ok {
+			perTurn = est.String()
+			perStep = fmt.Sprintf("~$%.2f", est.PerRunUSD*float64(turns))
+			cycleTotal += est.PerRunUSD * float64(turns)
+		} else {
+			unknown = append(unknown, step.Profile)
+		}
+		if step.Team != "" {
+			delegates = true
+		}
+		rows = append(rows, []string{fmt.Sprintf("%d", i+1), step.Label(), fmt.Sprintf("%d", turns), perTurn, perStep})
+	}
+	printTable(headers, rows)
+	fmt.Println()
+
+	printField("Est. Cost/Cycle", fmt.Sprintf("~$%.2f", cycleTotal))
+	if len(unknown) > 0 {
+		printField("No Estimate", strings.Join(unknown, ", ")+" (no usable run history; not included)")
+	}
+	if delegates {
+		printField("Note", "sub-agent spawns are not included")
+	}
+	fmt.Printf("\n  %sDry run: no session was started.%s\n\n", colorDim, colorReset)
+}
+
 func resolveLoopResourcePriority(loopDef *config.LoopDef, flagValue string, flagSet bool) (string, error) {
 	if flagSet {
 		return config.ParseResourcePriority(flagValue)
@@ -284,50 +364,6 @@ func resolveLoopResourcePriority(loopDef *config.LoopDef, flagValue string, flag
 	return priority, nil
 }
 
-func loopProfilesSnapshot(globalCfg *config.GlobalConfig, loopDef *config.LoopDef) ([]config.Profile, error) {
-	seen := make(map[string]struct{}, len(loopDef.Steps))
-	profiles := make([]config.Profile, 0, len(loopDef.Steps))
-
-	addProfile := func(name string) error {
-		name = strings.TrimSpace(name)
-		if name == "" {
-			return nil
-		}
-		key := strings.ToLower(name)
-		if _, ok := seen[key]; ok {
-			return nil
-		}
-		prof := globalCfg.FindProfile(name)
-		if prof == nil {
-			return fmt.Errorf("profile %q not found for loop %q", name, loopDef.Name)
-		}
-		seen[key] = struct{}{}
-		profiles = append(profiles, *prof)
-		return nil
-	}
-
-	for _, step := range loopDef.Steps {
-		if strings.TrimSpace(step.Profile) == "" {
-			return nil, fmt.Errorf("loop %q has a step with empty profile", loopDef.Name)
-		}
-		if err := addProfile(step.Profile); err != nil {
-			return nil, err
-		}
-		// Include all profiles from the team's delegation tree so the daemon has
-		// everything needed for nested spawn resolution and prompt rendering.
-		if step.Team != "" {
-			if t := globalCfg.FindTeam(step.Team); t != nil && t.Delegation != nil {
-				for _, name := range config.CollectDelegationProfileNames(t.Delegation) {
-					if err := addProfile(name); err != nil {
-						return nil, err
-					}
-				}
-			}
-		}
-	}
-	return profiles, nil
-}
-
 func loopStop(cmd *cobra.Command, args []string) error {
 	runIDStr := os.Getenv("ADAF_LOOP_RUN_ID")
 	if runIDStr == "" {
@@ -443,6 +479,36 @@ func loopCallSupervisor(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func loopOutcome(cmd *cobra.Command, args []string) error {
+	runIDStr := os.Getenv("ADAF_LOOP_RUN_ID")
+	stepIdxStr := os.Getenv("ADAF_LOOP_STEP_INDEX")
+	if runIDStr == "" || stepIdxStr == "" {
+		return fmt.Errorf("ADAF_LOOP_RUN_ID/ADAF_LOOP_STEP_INDEX not set (are you running inside a loop step?)")
+	}
+	runID, err := strconv.Atoi(runIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid ADAF_LOOP_RUN_ID: %s", runIDStr)
+	}
+	stepIdx, err := strconv.Atoi(stepIdxStr)
+	if err != nil || stepIdx < 0 {
+		return fmt.Errorf("invalid ADAF_LOOP_STEP_INDEX: %s", stepIdxStr)
+	}
+	outcome := strings.ToLower(strings.TrimSpace(args[0]))
+	if outcome == "" || strings.ContainsAny(outcome, " \t\n") {
+		return fmt.Errorf("outcome must be a single non-empty token")
+	}
+
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	if err := s.SetLoopStepOutcome(runID, stepIdx, outcome); err != nil {
+		return fmt.Errorf("recording outcome: %w", err)
+	}
+	fmt.Printf("  %sOutcome %q recorded for loop run #%d step %d.%s\n", styleBoldGreen, outcome, runID, stepIdx+1, colorReset)
+	return nil
+}
+
 func loopStatus(cmd *cobra.Command, args []string) error {
 	s, err := openStoreRequired()
 	if err != nil {
@@ -481,6 +547,17 @@ func loopStatus(cmd *cobra.Command, args []string) error {
 	if s.IsLoopStopped(run.ID) {
 		printFieldColored("Stop Signal", "received", colorYellow)
 	}
+	if spend, err := s.GetLoopRunBudget(run.ID); err == nil && spend != nil {
+		printBudgetField("Budget", spend.CostUSD, spend.Tokens, spend.MaxCostUSD, spend.MaxTokens, spend.State)
+		printBudgetField(fmt.Sprintf("Step %d Budget", spend.StepIndex+1),
+			spend.StepCostUSD, spend.StepTokens, spend.StepMaxCostUSD, spend.StepMaxTokens, spend.StepState)
+	}
+	if run.PendingApproval > 0 {
+		printFieldColored("Approval", fmt.Sprintf("waiting on #%d (adaf approve %d | adaf deny %d)", run.PendingApproval, run.PendingApproval, run.PendingApproval), colorYellow)
+	}
+	if run.UsagePause != nil {
+		printFieldColored("Usage Pause", describeUsagePause(run.UsagePause), colorYellow)
+	}
 
 	// Show messages.
 	msgs, _ := s.ListLoopMessages(run.ID)
@@ -495,37 +572,70 @@ func loopStatus(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func printBudgetField(label string, cost float64, tokens int, maxCost float64, maxTokens int, state string) {
+	value := budget.Describe(budget.Usage{CostUSD: cost, Tokens: tokens}, config.Budget{MaxCostUSD: maxCost, MaxTokens: maxTokens})
+	switch state {
+	case budget.StateExceeded:
+		printFieldColored(label, value+" (exceeded)", colorRed)
+	case budget.StateWindingDown:
+		printFieldColored(label, value+" (winding down)", colorYellow)
+	default:
+		printField(label, value)
+	}
+}
+
+// describeUsagePause renders a usage-gate pause for status output.
+func describeUsagePause(p *store.UsagePause) string {
+	return fmt.Sprintf("%s waiting on %s %s (%.0f%%) until %s",
+		p.Profile, p.Provider, p.Limit, p.UtilizationPct, p.Until.Local().Format("Jan 2 15:04"))
+}
+
 func loopNotify(cmd *cobra.Command, args []string) error {
 	runIDStr := os.Getenv("ADAF_LOOP_RUN_ID")
 	if runIDStr == "" {
 		return fmt.Errorf("ADAF_LOOP_RUN_ID not set (are you running inside a loop step?)")
 	}
+	runID, _ := strconv.Atoi(runIDStr)
 
 	globalCfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
 
-	if !pushover.Configured(&globalCfg.Pushover) {
-		return fmt.Errorf("pushover not configured: run 'adaf config pushover setup' to set credentials")
-	}
-
 	priority, _ := cmd.Flags().GetInt("priority")
 	if priority < -2 || priority > 1 {
 		return fmt.Errorf("priority must be between -2 and 1")
 	}
 
-	msg := pushover.Message{
-		Title:    args[0],
-		Body:     args[1],
-		Priority: priority,
+	n, cfgErr := notify.New(globalCfg)
+	subscribed := false
+	for _, sc := range n.Sinks() {
+		if sc.Subscribes(config.NotifyLoopMessage) {
+			subscribed = true
+			break
+		}
+	}
+	if !subscribed {
+		if cfgErr != nil {
+			return fmt.Errorf("no usable notification sink: %w", cfgErr)
+		}
+		return fmt.Errorf("notifications not configured: run 'adaf config pushover setup' or add a sink (see 'adaf config notify --help')")
+	}
+
+	project := ""
+	if s, err := openStore(); err == nil && s.Exists() {
+		if projCfg, err := s.LoadProject(); err == nil && projCfg != nil {
+			project = projCfg.Name
+		}
 	}
 
-	if err := pushover.Send(&globalCfg.Pushover, msg); err != nil {
+	ctx, cancel := context.WithTimeout(cmd.Context(), notifyTimeout)
+	defer cancel()
+	if _, err := n.Notify(ctx, notify.LoopMessage(project, runID, args[0], args[1], priority)); err != nil {
 		return fmt.Errorf("sending notification: %w", err)
 	}
 
-	fmt.Printf("  %sPushover notification sent.%s\n", styleBoldGreen, colorReset)
+	fmt.Printf("  %sNotification sent.%s\n", styleBoldGreen, colorReset)
 	return nil
 }
 
@@ -7,6 +7,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/notify"
 	"github.com/agusx1211/adaf/internal/session"
 	"github.com/agusx1211/adaf/internal/store"
 )
@@ -30,7 +32,7 @@ func runParentAsk(cmd *cobra.Command, args []string) error {
 	question := args[0]
 	timeout, _ := cmd.Flags().GetDuration("timeout")
 
-	spawnID, _, _, err := getTurnContext()
+	turnID, _, _, err := getTurnContext()
 	if err != nil {
 		return err
 	}
@@ -40,6 +42,12 @@ func runParentAsk(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Asks are filed on the spawn that ran this turn.
+	spawnID := turnID
+	if rec, err := s.SpawnForTurn(turnID); err == nil && rec != nil {
+		spawnID = rec.ID
+	}
+
 	// Check for existing pending ask.
 	existing, err := s.PendingAsk(spawnID)
 	if err != nil {
@@ -56,17 +64,24 @@ func runParentAsk(cmd *cobra.Command, args []string) error {
 		Type:      "ask",
 		Content:   question,
 	}
+	rec, err := s.GetSpawn(spawnID)
+	if err == nil {
+		msg.AddresseeTurnID = rec.ParentTurnID
+	}
 	if err := s.CreateMessage(msg); err != nil {
 		return fmt.Errorf("creating ask message: %w", err)
 	}
 
 	// Update spawn status to awaiting_input.
-	rec, err := s.GetSpawn(spawnID)
-	if err == nil {
+	if rec != nil {
 		rec.Status = "awaiting_input"
 		s.UpdateSpawn(rec)
+		notifyEvent(s, notify.ParentAsk("", rec, question))
 	}
 
+	escalateAfter := askEscalationInterval()
+	lastMove := msg.CreatedAt
+
 	// Poll for reply.
 	deadline := time.Now().Add(timeout)
 	for time.Now().Before(deadline) {
@@ -87,12 +102,32 @@ func runParentAsk(cmd *cobra.Command, args []string) error {
 			}
 		}
 
+		// Nobody answered in time: move the question up the spawn tree.
+		if escalateAfter > 0 && !msg.ToHuman && time.Since(lastMove) >= escalateAfter {
+			if err := s.EscalateAsk(msg); err == nil {
+				lastMove = msg.EscalatedAt
+				if msg.ToHuman && rec != nil {
+					notifyEvent(s, notify.AskEscalated("", rec, msg))
+				}
+			}
+		}
+
 		time.Sleep(2 * time.Second)
 	}
 
 	return fmt.Errorf("timed out waiting for reply after %s", timeout)
 }
 
+// askEscalationInterval returns how long an ask waits at each level of the
+// spawn tree before escalating, or 0 when escalation is off.
+func askEscalationInterval() time.Duration {
+	globalCfg, err := config.Load()
+	if err != nil || globalCfg.AskEscalationMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(globalCfg.AskEscalationMinutes) * time.Minute
+}
+
 // --- adaf spawn-reply ---
 
 var spawnReplyCmd = &cobra.Command{
@@ -120,7 +155,6 @@ func runSpawnReply(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Find pending ask.
 	ask, err := s.PendingAsk(spawnID)
 	if err != nil {
 		return err
@@ -128,24 +162,8 @@ func runSpawnReply(cmd *cobra.Command, args []string) error {
 	if ask == nil {
 		return fmt.Errorf("no pending question from spawn #%d", spawnID)
 	}
-
-	// Create reply message.
-	reply := &store.SpawnMessage{
-		SpawnID:   spawnID,
-		Direction: "parent_to_child",
-		Type:      "reply",
-		Content:   answer,
-		ReplyToID: ask.ID,
-	}
-	if err := s.CreateMessage(reply); err != nil {
-		return fmt.Errorf("creating reply: %w", err)
-	}
-
-	// Update spawn status back to running.
-	rec, err := s.GetSpawn(spawnID)
-	if err == nil && rec.Status == "awaiting_input" {
-		rec.Status = "running"
-		s.UpdateSpawn(rec)
+	if _, err := s.ReplyToAsk(spawnID, answer, resolveIssueActor("")); err != nil {
+		return err
 	}
 
 	fmt.Printf("Replied to spawn #%d question: %s\n", spawnID, truncate(ask.Content, 80))
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/notify"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// notifyTimeout bounds notification delivery from short-lived commands.
+const notifyTimeout = 10 * time.Second
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Inspect and test notification sinks",
+	Long: `Notification sinks are configured under "notify" in ~/.adaf/config.json:
+
+  "notify": {
+    "sinks": [
+      {"name": "ci", "type": "webhook", "url": "https://example.com/hook",
+       "secret": "s3cret", "events": ["spawn_failed", "budget_exceeded"]},
+      {"name": "team", "type": "slack", "url": "https://hooks.slack.com/..."},
+      {"name": "me", "type": "email", "smtp_host": "smtp.example.com",
+       "username": "me", "password": "...", "from": "adaf@example.com",
+       "to": ["me@example.com"], "events": ["parent_ask"]},
+      {"name": "local", "type": "command", "command": "notify-send \"$ADAF_NOTIFY_TITLE\""}
+    ]
+  }
+
+Sink types: webhook, slack, discord, email, command, pushover.
+Events: loop_done, loop_notify, spawn_failed, budget_exceeded, parent_ask,
+issue_in_review, approval_needed. A sink without "events" receives all of
+them.
+
+Webhooks receive the event as JSON. With a secret, the body's HMAC-SHA256
+is sent as "X-Adaf-Signature: sha256=<hex>".`,
+}
+
+var notifyListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls", "status"},
+	Short:   "List configured notification sinks",
+	RunE:    notifyList,
+}
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test [sink]",
+	Short: "Send a test notification to one sink or to every sink",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  notifyTest,
+}
+
+func init() {
+	notifyTestCmd.Flags().String("event", "", "Send as this event kind and honour subscriptions (default: bypass them)")
+	notifyCmd.AddCommand(notifyListCmd, notifyTestCmd)
+	configCmd.AddCommand(notifyCmd)
+}
+
+func notifyList(cmd *cobra.Command, args []string) error {
+	globalCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	n, cfgErr := notify.New(globalCfg)
+
+	printHeader("Notification Sinks")
+	sinks := n.Sinks()
+	if len(sinks) == 0 {
+		fmt.Printf("  %sNo sinks configured.%s\n", colorDim, colorReset)
+	}
+	rows := make([][]string, 0, len(sinks))
+	for _, sc := range sinks {
+		events := "all"
+		if len(sc.Events) > 0 {
+			events = strings.Join(sc.Events, ", ")
+		}
+		rows = append(rows, []string{sc.Name, sc.Type, notifySinkTarget(sc), events})
+	}
+	if len(rows) > 0 {
+		printTable([]string{"Name", "Type", "Target", "Events"}, rows)
+	}
+	if cfgErr != nil {
+		fmt.Println()
+		printFieldColored("Skipped", cfgErr.Error(), colorRed)
+	}
+	return nil
+}
+
+func notifyTest(cmd *cobra.Command, args []string) error {
+	globalCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	n, cfgErr := notify.New(globalCfg)
+	if cfgErr != nil {
+		printFieldColored("Skipped", cfgErr.Error(), colorRed)
+	}
+
+	kind, _ := cmd.Flags().GetString("event")
+	if kind != "" && !config.ValidNotifyEvent(kind) {
+		return fmt.Errorf("unknown event %q (valid: %s)", kind, strings.Join(config.NotifyEvents, ", "))
+	}
+	ev := notify.Event{
+		Kind:    kind,
+		Title:   "adaf test",
+		Message: "This is a test notification from adaf.",
+	}
+	if ev.Kind == "" {
+		ev.Kind = "test"
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), notifyTimeout)
+	defer cancel()
+
+	var targets []config.NotifySink
+	for _, sc := range n.Sinks() {
+		if len(args) == 1 && sc.Name != args[0] {
+			continue
+		}
+		if kind != "" && !sc.Subscribes(kind) {
+			continue
+		}
+		targets = append(targets, sc)
+	}
+	if len(targets) == 0 {
+		if len(args) == 1 {
+			return fmt.Errorf("notify sink %q not found or not subscribed", args[0])
+		}
+		return fmt.Errorf("no notification sinks to test")
+	}
+
+	failed := 0
+	for _, sc := range targets {
+		fmt.Printf("  Sending to %s (%s)... ", sc.Name, sc.Type)
+		if err := n.SendTo(ctx, sc.Name, ev); err != nil {
+			failed++
+			fmt.Printf("%sFAILED%s %v\n", colorRed, colorReset, err)
+			continue
+		}
+		fmt.Printf("%sOK%s\n", styleBoldGreen, colorReset)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sink(s) failed", failed, len(targets))
+	}
+	return nil
+}
+
+func notifySinkTarget(sc config.NotifySink) string {
+	switch sc.Type {
+	case config.NotifySinkEmail:
+		return strings.Join(sc.To, ", ")
+	case config.NotifySinkCommand:
+		return truncate(sc.Command, 40)
+	case config.NotifySinkPushover:
+		return "pushover.net"
+	default:
+		return truncate(sc.URL, 40)
+	}
+}
+
+// notifyEvent delivers a lifecycle notification from a CLI command. The
+// process may exit right after, so delivery is synchronous but bounded,
+// and failures never fail the command.
+func notifyEvent(s *store.Store, ev notify.Event) {
+	globalCfg, err := config.Load()
+	if err != nil {
+		return
+	}
+	n, cfgErr := notify.New(globalCfg)
+	if cfgErr != nil {
+		debug.LogKV("cli.notify", "skipping invalid sinks", "error", cfgErr)
+	}
+	if ev.Project == "" && s != nil {
+		if projCfg, err := s.LoadProject(); err == nil && projCfg != nil {
+			ev.Project = projCfg.Name
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	if sent, err := n.Notify(ctx, ev); err != nil {
+		debug.LogKV("cli.notify", "delivery failed", "event", ev.Kind, "sinks", sent, "error", err)
+	}
+}
+
+// notifyIssueMoved reports an issue that just entered in_review.
+func notifyIssueMoved(s *store.Store, prevStatus string, issue *store.Issue) {
+	if issue.Status == store.IssueStatusInReview && store.NormalizeIssueStatus(prevStatus) != store.IssueStatusInReview {
+		notifyEvent(s, notify.IssueInReview("", issue))
+	}
+}
@@ -23,7 +23,8 @@ Examples:
   adaf plan list
   adaf plan create --id auth-system --title "Authentication System"
   adaf plan switch auth-system
-  adaf plan status auth-system frozen`,
+  adaf plan status auth-system frozen
+  adaf plan phase add --title "Login flow" --issue 3`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runPlanShow(cmd, args)
 	},
@@ -261,6 +262,14 @@ func runPlanShow(cmd *cobra.Command, args []string) error {
 	if !plan.Updated.IsZero() {
 		printField("Updated", plan.Updated.Format("2006-01-02 15:04:05"))
 	}
+	if len(plan.Phases) > 0 {
+		progress, err := s.PlanProgress(plan)
+		if err != nil {
+			return fmt.Errorf("computing plan progress: %w", err)
+		}
+		fmt.Println()
+		printPlanPhases(plan, progress)
+	}
 
 	fmt.Println()
 	return nil
@@ -154,6 +154,9 @@ func runPlanSet(cmd *cobra.Command, args []string) error {
 		if plan.Created.IsZero() {
 			plan.Created = existing.Created
 		}
+		if plan.Phases == nil {
+			plan.Phases = existing.Phases
+		}
 		if err := s.UpdatePlan(&plan); err != nil {
 			return fmt.Errorf("updating plan: %w", err)
 		}
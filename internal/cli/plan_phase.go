@@ -0,0 +1,328 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var planPhaseCmd = &cobra.Command{
+	Use:     "phase",
+	Aliases: []string{"phases", "milestone", "milestones"},
+	Short:   "Manage plan phases and milestones",
+	Long: `Manage the ordered phases (milestones) of a plan. Each phase has
+acceptance text, a status and linked issues; progress is computed from the
+statuses of the linked issues.
+
+Examples:
+  adaf plan phase list
+  adaf plan phase add --title "Login flow" --acceptance "Users can sign in" --issue 3 --issue 4
+  adaf plan phase update 1 --status active --add-issue 7
+  adaf plan phase update 2 --position 1
+  adaf plan phase remove 2`,
+	RunE: runPlanPhaseList,
+}
+
+var planPhaseListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List phases with progress",
+	Args:    cobra.NoArgs,
+	RunE:    runPlanPhaseList,
+}
+
+var planPhaseAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Append a phase to a plan",
+	Args:  cobra.NoArgs,
+	RunE:  runPlanPhaseAdd,
+}
+
+var planPhaseUpdateCmd = &cobra.Command{
+	Use:   "update <phase-id>",
+	Short: "Update a phase's status, text, linked issues or position",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPlanPhaseUpdate,
+}
+
+var planPhaseRemoveCmd = &cobra.Command{
+	Use:     "remove <phase-id>",
+	Aliases: []string{"rm", "delete"},
+	Short:   "Remove a phase from a plan",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runPlanPhaseRemove,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{planPhaseCmd, planPhaseListCmd, planPhaseAddCmd, planPhaseUpdateCmd, planPhaseRemoveCmd} {
+		c.Flags().String("plan", "", "Plan ID (defaults to the active plan)")
+	}
+
+	planPhaseAddCmd.Flags().String("title", "", "Phase title (required)")
+	planPhaseAddCmd.Flags().String("acceptance", "", "What must hold for the phase to be done")
+	planPhaseAddCmd.Flags().String("status", store.PlanPhasePending, "Phase status (pending, active, done, skipped)")
+	planPhaseAddCmd.Flags().IntSlice("issue", nil, "Issue ID(s) to link (can be repeated)")
+	_ = planPhaseAddCmd.MarkFlagRequired("title")
+
+	planPhaseUpdateCmd.Flags().String("title", "", "New phase title")
+	planPhaseUpdateCmd.Flags().String("acceptance", "", "New acceptance text")
+	planPhaseUpdateCmd.Flags().String("status", "", "New status (pending, active, done, skipped)")
+	planPhaseUpdateCmd.Flags().IntSlice("add-issue", nil, "Issue ID(s) to link")
+	planPhaseUpdateCmd.Flags().IntSlice("remove-issue", nil, "Issue ID(s) to unlink")
+	planPhaseUpdateCmd.Flags().Int("position", 0, "Move the phase to this 1-based position")
+
+	planPhaseCmd.AddCommand(
+		planPhaseListCmd,
+		planPhaseAddCmd,
+		planPhaseUpdateCmd,
+		planPhaseRemoveCmd,
+	)
+	planCmd.AddCommand(planPhaseCmd)
+}
+
+// loadPhasePlan resolves the --plan flag, falling back to the active plan.
+func loadPhasePlan(cmd *cobra.Command, s *store.Store) (*store.Plan, error) {
+	id, _ := cmd.Flags().GetString("plan")
+	id = strings.TrimSpace(id)
+	if id == "" {
+		plan, err := s.ActivePlan()
+		if err != nil {
+			return nil, fmt.Errorf("loading active plan: %w", err)
+		}
+		if plan == nil {
+			return nil, fmt.Errorf("no active plan; pass --plan <id> or run `adaf plan switch <id>`")
+		}
+		return plan, nil
+	}
+	if err := validatePlanID(id); err != nil {
+		return nil, err
+	}
+	plan, err := s.GetPlan(id)
+	if err != nil {
+		return nil, fmt.Errorf("loading plan %q: %w", id, err)
+	}
+	if plan == nil {
+		return nil, fmt.Errorf("plan %q not found", id)
+	}
+	return plan, nil
+}
+
+func parsePhaseID(arg string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimSpace(arg))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid phase ID %q: must be a positive number", arg)
+	}
+	return id, nil
+}
+
+func validatePhaseIssues(s *store.Store, ids []int) error {
+	for _, id := range ids {
+		issue, err := s.GetIssue(id)
+		if err != nil || issue == nil {
+			return fmt.Errorf("issue #%d not found", id)
+		}
+	}
+	return nil
+}
+
+func runPlanPhaseList(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	plan, err := loadPhasePlan(cmd, s)
+	if err != nil {
+		return err
+	}
+	progress, err := s.PlanProgress(plan)
+	if err != nil {
+		return fmt.Errorf("computing plan progress: %w", err)
+	}
+
+	printHeader("Phases of " + plan.ID)
+	if len(plan.Phases) == 0 {
+		fmt.Printf("  %sNo phases defined.%s Use %sadaf plan phase add --title \"...\"%s.\n\n", colorDim, colorReset, styleBoldWhite, colorReset)
+		return nil
+	}
+	printPlanPhases(plan, progress)
+	fmt.Println()
+	return nil
+}
+
+// printPlanPhases prints a plan's phases, its overall progress and the
+// acceptance text of the current phase.
+func printPlanPhases(plan *store.Plan, progress store.PlanProgress) {
+	headers := []string{"#", "ID", "STATUS", "PROGRESS", "ISSUES", "TITLE"}
+	var rows [][]string
+	for i, phase := range plan.Phases {
+		pp := progress.Phase(phase.ID)
+		marker := ""
+		if phase.ID == progress.CurrentPhaseID {
+			marker = "* "
+		}
+		issues := make([]string, 0, len(phase.IssueIDs))
+		for _, id := range phase.IssueIDs {
+			issues = append(issues, "#"+strconv.Itoa(id))
+		}
+		rows = append(rows, []string{
+			marker + strconv.Itoa(i+1),
+			strconv.Itoa(phase.ID),
+			statusBadge(store.NormalizePlanPhaseStatus(phase.Status)),
+			fmt.Sprintf("%d/%d (%d%%)", pp.ClosedIssues, pp.TotalIssues, pp.Percent),
+			truncate(strings.Join(issues, ","), 24),
+			truncate(phase.Title, 40),
+		})
+	}
+	printTable(headers, rows)
+
+	fmt.Printf("\n  %sProgress:%s %d/%d phases complete (%d%%)\n", colorDim, colorReset,
+		progress.CompletedPhases, progress.TotalPhases, progress.Percent)
+	if current := plan.Phase(progress.CurrentPhaseID); current != nil {
+		fmt.Printf("  %sCurrent:%s  %s\n", colorDim, colorReset, current.Title)
+		if strings.TrimSpace(current.Acceptance) != "" {
+			fmt.Printf("  %sAccept:%s   %s\n", colorDim, colorReset, current.Acceptance)
+		}
+	}
+}
+
+func runPlanPhaseAdd(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	plan, err := loadPhasePlan(cmd, s)
+	if err != nil {
+		return err
+	}
+
+	title, _ := cmd.Flags().GetString("title")
+	acceptance, _ := cmd.Flags().GetString("acceptance")
+	status, _ := cmd.Flags().GetString("status")
+	issueIDs, _ := cmd.Flags().GetIntSlice("issue")
+	if err := validatePhaseIssues(s, issueIDs); err != nil {
+		return err
+	}
+
+	phase, err := plan.AddPhase(store.PlanPhase{
+		Title:      title,
+		Acceptance: strings.TrimSpace(acceptance),
+		Status:     status,
+		IssueIDs:   issueIDs,
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.UpdatePlan(plan); err != nil {
+		return fmt.Errorf("saving plan: %w", err)
+	}
+
+	fmt.Printf("  %sAdded phase %d to plan %s.%s\n", styleBoldGreen, phase.ID, plan.ID, colorReset)
+	return nil
+}
+
+func runPlanPhaseUpdate(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	id, err := parsePhaseID(args[0])
+	if err != nil {
+		return err
+	}
+	plan, err := loadPhasePlan(cmd, s)
+	if err != nil {
+		return err
+	}
+	phase := plan.Phase(id)
+	if phase == nil {
+		return fmt.Errorf("phase %d not found in plan %q", id, plan.ID)
+	}
+
+	changed := false
+	if cmd.Flags().Changed("title") {
+		title, _ := cmd.Flags().GetString("title")
+		if strings.TrimSpace(title) == "" {
+			return fmt.Errorf("phase title cannot be empty")
+		}
+		phase.Title = strings.TrimSpace(title)
+		changed = true
+	}
+	if cmd.Flags().Changed("acceptance") {
+		acceptance, _ := cmd.Flags().GetString("acceptance")
+		phase.Acceptance = strings.TrimSpace(acceptance)
+		changed = true
+	}
+	if cmd.Flags().Changed("status") {
+		status, _ := cmd.Flags().GetString("status")
+		status = store.NormalizePlanPhaseStatus(status)
+		if !store.IsValidPlanPhaseStatus(status) {
+			return fmt.Errorf("invalid phase status %q (valid: pending, active, done, skipped)", status)
+		}
+		phase.Status = status
+		changed = true
+	}
+	if add, _ := cmd.Flags().GetIntSlice("add-issue"); len(add) > 0 {
+		if err := validatePhaseIssues(s, add); err != nil {
+			return err
+		}
+		phase.IssueIDs = store.NormalizeIssueDependencyIDs(append(phase.IssueIDs, add...))
+		changed = true
+	}
+	if remove, _ := cmd.Flags().GetIntSlice("remove-issue"); len(remove) > 0 {
+		kept := phase.IssueIDs[:0]
+		for _, issueID := range phase.IssueIDs {
+			if !slices.Contains(remove, issueID) {
+				kept = append(kept, issueID)
+			}
+		}
+		phase.IssueIDs = store.NormalizeIssueDependencyIDs(kept)
+		changed = true
+	}
+	if changed {
+		phase.Updated = time.Now().UTC()
+	}
+	if cmd.Flags().Changed("position") {
+		position, _ := cmd.Flags().GetInt("position")
+		if err := plan.MovePhase(id, position); err != nil {
+			return err
+		}
+		changed = true
+	}
+	if !changed {
+		return fmt.Errorf("nothing to update; pass --title, --acceptance, --status, --add-issue, --remove-issue or --position")
+	}
+
+	if err := s.UpdatePlan(plan); err != nil {
+		return fmt.Errorf("saving plan: %w", err)
+	}
+	fmt.Printf("  %sUpdated phase %d of plan %s.%s\n", styleBoldGreen, id, plan.ID, colorReset)
+	return nil
+}
+
+func runPlanPhaseRemove(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	id, err := parsePhaseID(args[0])
+	if err != nil {
+		return err
+	}
+	plan, err := loadPhasePlan(cmd, s)
+	if err != nil {
+		return err
+	}
+	if !plan.RemovePhase(id) {
+		return fmt.Errorf("phase %d not found in plan %q", id, plan.ID)
+	}
+	if err := s.UpdatePlan(plan); err != nil {
+		return fmt.Errorf("saving plan: %w", err)
+	}
+	fmt.Printf("  %sRemoved phase %d from plan %s.%s\n", styleBoldGreen, id, plan.ID, colorReset)
+	return nil
+}
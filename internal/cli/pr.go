@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agusx1211/adaf/internal/forge"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+var prCmd = &cobra.Command{
+	Use:     "pr",
+	Aliases: []string{"pulls", "pull-request"},
+	Short:   "Open pull requests for spawn and loop output",
+	Long: `Publish work to a git forge as pull requests instead of merging locally.
+
+Configure the forge in .adaf/project.json:
+
+  "forge": {"type": "gitea", "url": "https://git.example.com",
+            "owner": "team", "repo": "app", "base": "main"}
+
+"type" is gitea or github. The API token is read from $ADAF_FORGE_TOKEN
+(override the variable name with "token_env"); branches are pushed to the
+"remote" git remote (default origin). Without "base", pull requests target
+the branch currently checked out in the project repo.
+
+'pr create --spawn-id' pushes a completed spawn's branch; 'pr create
+--loop-run' pushes the commit HEAD was at when the run stopped, holding the
+run's accumulated commits, to adaf/loop-run-<id>. The body is built from the spawn summary and turn
+handoffs.
+
+'pr sync' copies new review comments onto the issues linked to the pull
+request (a "Review feedback" issue is filed when there are none) and reopens
+closed ones, so the next loop step picks the feedback up.
+
+Examples:
+  adaf pr create --spawn-id 3
+  adaf pr create --loop-run 12 --title "Nightly refactor"
+  adaf pr list
+  adaf pr sync`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var prCreateCmd = &cobra.Command{
+	Use:     "create",
+	Aliases: []string{"open", "new"},
+	Short:   "Push a spawn branch or loop run and open a pull request",
+	RunE:    runPRCreate,
+}
+
+var prListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List pull requests opened by adaf",
+	RunE:    runPRList,
+}
+
+var prSyncCmd = &cobra.Command{
+	Use:   "sync [id]",
+	Short: "Copy new review comments onto linked issues",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runPRSync,
+}
+
+func init() {
+	prCreateCmd.Flags().Int("spawn-id", 0, "Completed spawn whose branch to publish")
+	prCreateCmd.Flags().Int("loop-run", 0, "Loop run whose commits to publish")
+	prCreateCmd.Flags().String("title", "", "Pull request title (default: derived from the task or loop)")
+	prCmd.AddCommand(prCreateCmd)
+	prCmd.AddCommand(prListCmd)
+	prCmd.AddCommand(prSyncCmd)
+	rootCmd.AddCommand(prCmd)
+}
+
+func runPRCreate(cmd *cobra.Command, args []string) error {
+	spawnID, _ := cmd.Flags().GetInt("spawn-id")
+	runID, _ := cmd.Flags().GetInt("loop-run")
+	title, _ := cmd.Flags().GetString("title")
+	if (spawnID > 0) == (runID > 0) {
+		return fmt.Errorf("exactly one of --spawn-id or --loop-run is required")
+	}
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	pub, err := forge.NewPublisher(s)
+	if err != nil {
+		return err
+	}
+
+	var pr *store.PullRequest
+	if spawnID > 0 {
+		pr, err = pub.PublishSpawn(cmd.Context(), spawnID, title)
+	} else {
+		pr, err = pub.PublishLoopRun(cmd.Context(), runID, title)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n  %sOpened pull request #%d.%s\n", styleBoldGreen, pr.Number, colorReset)
+	printPullRequest(pr)
+	fmt.Println()
+	return nil
+}
+
+func runPRList(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	list, err := s.ListPullRequests()
+	if err != nil {
+		return fmt.Errorf("listing pull requests: %w", err)
+	}
+
+	printHeader("Pull Requests")
+	if len(list) == 0 {
+		fmt.Printf("  %sNo pull requests.%s\n\n", colorDim, colorReset)
+		return nil
+	}
+	headers := []string{"ID", "NUMBER", "STATE", "SOURCE", "HEAD", "TITLE", "ISSUES"}
+	var rows [][]string
+	for _, pr := range list {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", pr.ID),
+			fmt.Sprintf("#%d", pr.Number),
+			pr.State,
+			pullRequestSource(&pr),
+			pr.Head,
+			truncate(pr.Title, 48),
+			formatIssueIDs(pr.IssueIDs),
+		})
+	}
+	printTable(headers, rows)
+	fmt.Println()
+	return nil
+}
+
+func runPRSync(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	pub, err := forge.NewPublisher(s)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		n, err := pub.SyncOpen(cmd.Context())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Synced %d new review comment(s).\n", n)
+		return nil
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(args[0]), "#"))
+	if err != nil {
+		return fmt.Errorf("invalid pull request ID %q", args[0])
+	}
+	pr, err := s.GetPullRequest(id)
+	if err != nil {
+		return fmt.Errorf("pull request %d not found", id)
+	}
+	n, err := pub.Sync(cmd.Context(), pr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Synced %d new review comment(s) from PR #%d onto %s.\n", n, pr.Number, formatIssueIDs(pr.IssueIDs))
+	return nil
+}
+
+func printPullRequest(pr *store.PullRequest) {
+	printField("URL", pr.URL)
+	printField("Title", pr.Title)
+	printField("Branches", pr.Head+" -> "+pr.Base)
+	printField("Source", pullRequestSource(pr))
+	if len(pr.IssueIDs) > 0 {
+		printField("Issues", formatIssueIDs(pr.IssueIDs))
+	}
+}
+
+func pullRequestSource(pr *store.PullRequest) string {
+	switch {
+	case pr.SpawnID > 0:
+		return fmt.Sprintf("spawn #%d", pr.SpawnID)
+	case pr.LoopRunID > 0:
+		return fmt.Sprintf("loop run #%d", pr.LoopRunID)
+	default:
+		return "-"
+	}
+}
+
+func formatIssueIDs(ids []int) string {
+	if len(ids) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("#%d", id)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agusx1211/adaf/internal/config"
+)
+
+var pricesCmd = &cobra.Command{
+	Use:     "prices",
+	Aliases: []string{"price", "pricing"},
+	Short:   "Manage the model price catalog",
+	Long: `Manage per-model token prices (USD per million tokens) in ~/.adaf/config.json.
+
+Prices cost turns whose agent reports no USD amount and turn historical
+token averages into per-task estimates in 'adaf spawn-info', delegation
+prompts and 'adaf loop start --dry-run'. A model ending in "*" matches
+every model with that prefix; the most specific entry wins.
+
+Examples:
+  adaf config prices
+  adaf config prices set 'gpt-5*' --input 1.25 --cached 0.125 --output 10
+  adaf config prices remove 'gpt-5*'`,
+	RunE: runPricesList,
+}
+
+var pricesListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls", "l"},
+	Short:   "List model prices",
+	RunE:    runPricesList,
+}
+
+var pricesSetCmd = &cobra.Command{
+	Use:     "set <model>",
+	Aliases: []string{"add", "update"},
+	Short:   "Add or replace a model price",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runPricesSet,
+}
+
+var pricesRemoveCmd = &cobra.Command{
+	Use:     "remove <model>",
+	Aliases: []string{"rm", "delete"},
+	Short:   "Remove a model price",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runPricesRemove,
+}
+
+func init() {
+	pricesSetCmd.Flags().Float64("input", 0, "Uncached input price per million tokens")
+	pricesSetCmd.Flags().Float64("cached", 0, "Cache-read input price per million tokens")
+	pricesSetCmd.Flags().Float64("output", 0, "Output (including reasoning) price per million tokens")
+	pricesSetCmd.MarkFlagRequired("input")
+	pricesSetCmd.MarkFlagRequired("output")
+
+	pricesCmd.AddCommand(pricesListCmd, pricesSetCmd, pricesRemoveCmd)
+	configCmd.AddCommand(pricesCmd)
+}
+
+func runPricesList(cmd *cobra.Command, args []string) error {
+	globalCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if len(globalCfg.ModelPrices) == 0 {
+		fmt.Println(colorDim + "  No model prices configured. Add one with 'adaf config prices set'." + colorReset)
+		return nil
+	}
+
+	printHeader("Model Prices (USD per million tokens)")
+	headers := []string{"Model", "Input", "Cached", "Output"}
+	var rows [][]string
+	for _, p := range globalCfg.ModelPrices {
+		rows = append(rows, []string{
+			styleBoldWhite + p.Model + colorReset,
+			fmt.Sprintf("$%.3f", p.Input),
+			fmt.Sprintf("$%.3f", p.CachedInput),
+			fmt.Sprintf("$%.3f", p.Output),
+		})
+	}
+	printTable(headers, rows)
+	return nil
+}
+
+func runPricesSet(cmd *cobra.Command, args []string) error {
+	input, _ := cmd.Flags().GetFloat64("input")
+	cached, _ := cmd.Flags().GetFloat64("cached")
+	output, _ := cmd.Flags().GetFloat64("output")
+	price := config.ModelPrice{Model: args[0], Input: input, CachedInput: cached, Output: output}
+	if err := price.Validate(); err != nil {
+		return err
+	}
+
+	globalCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	globalCfg.SetModelPrice(price)
+	if err := config.Save(globalCfg); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+	fmt.Printf("  %sPrice for %s saved.%s\n", styleBoldGreen, price.Model, colorReset)
+	return nil
+}
+
+func runPricesRemove(cmd *cobra.Command, args []string) error {
+	globalCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if !globalCfg.RemoveModelPrice(args[0]) {
+		return fmt.Errorf("no price for model %q", args[0])
+	}
+	if err := config.Save(globalCfg); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+	fmt.Printf("  %sPrice for %s removed.%s\n", styleBoldGreen, args[0], colorReset)
+	return nil
+}
@@ -0,0 +1,307 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/loop"
+	"github.com/agusx1211/adaf/internal/recording"
+	"github.com/agusx1211/adaf/internal/session"
+	"github.com/agusx1211/adaf/internal/store"
+	"github.com/agusx1211/adaf/internal/worktree"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <turn>",
+	Short: "Replay a recorded turn, or re-run its prompt against a commit",
+	Long: `Replay re-renders a recorded turn through the same display used for live
+runs, following the recorded timing. Use --speed to accelerate (0 = instant)
+and --max-gap to cap long idle stretches.
+
+With --rerun, the turn's exact recorded prompt, agent, model and resume
+session are launched again in a fresh worktree at --commit (default HEAD).
+Pass --profile to see how a different profile handles the same turn. The
+result is saved as a new turn linked to the original, and any changes are
+committed on a branch that is kept for comparison.
+
+Examples:
+  adaf replay 42
+  adaf replay 42 --speed 4 --prompt
+  adaf replay 42 --rerun --commit HEAD~3
+  adaf replay 42 --rerun --profile reviewer --no-resume`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	replayCmd.Flags().Float64("speed", 1, "Playback speed multiplier (1 = real time, 0 = instant)")
+	replayCmd.Flags().Duration("max-gap", 5*time.Second, "Longest pause between events after scaling (0 = no cap)")
+	replayCmd.Flags().Bool("prompt", false, "Show the recorded prompt before the stream")
+	replayCmd.Flags().Bool("rerun", false, "Launch the recorded prompt again instead of replaying it")
+	replayCmd.Flags().String("commit", "HEAD", "Commit to re-run against (with --rerun)")
+	replayCmd.Flags().String("profile", "", "Profile to re-run with instead of the recorded agent/model (with --rerun)")
+	replayCmd.Flags().String("model", "", "Model override for the re-run (with --rerun)")
+	replayCmd.Flags().Bool("no-resume", false, "Start a fresh agent session instead of resuming the recorded one (with --rerun)")
+	rootCmd.AddCommand(replayCmd)
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	turn, err := findTurnByIdentifier(s, args[0])
+	if err != nil {
+		return err
+	}
+	rec, err := s.LoadTurnRecording(turn.ID)
+	if err != nil {
+		return fmt.Errorf("no recording for turn #%d: %w", turn.ID, err)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if rerun, _ := cmd.Flags().GetBool("rerun"); rerun {
+		return rerunTurn(ctx, cmd, s, turn, rec)
+	}
+
+	speed, _ := cmd.Flags().GetFloat64("speed")
+	maxGap, _ := cmd.Flags().GetDuration("max-gap")
+	showPrompt, _ := cmd.Flags().GetBool("prompt")
+
+	meta := recording.Metadata(rec)
+	printHeader(fmt.Sprintf("Replay of Turn #%d", turn.ID))
+	printField("Agent", recording.ReplayAgent(rec))
+	if model := recordedModel(meta["command"], turn.AgentModel); model != "" {
+		printField("Model", model)
+	}
+	if turn.ProfileName != "" {
+		printField("Profile", turn.ProfileName)
+	}
+	if d := meta["duration"]; d != "" {
+		printField("Duration", d)
+	}
+	if speed > 0 {
+		printField("Speed", fmt.Sprintf("%gx", speed))
+	} else {
+		printField("Speed", "instant")
+	}
+	fmt.Println()
+
+	err = recording.Replay(ctx, rec, os.Stdout, recording.ReplayOptions{
+		Speed:      speed,
+		MaxGap:     maxGap,
+		ShowPrompt: showPrompt,
+	})
+	if err != nil && ctx.Err() != nil {
+		fmt.Printf("\n  %sReplay interrupted.%s\n", colorDim, colorReset)
+		return nil
+	}
+	return err
+}
+
+// rerunTurn launches a recorded turn's prompt again in a worktree at the
+// requested commit and links the resulting turn to the original.
+func rerunTurn(ctx context.Context, cmd *cobra.Command, s *store.Store, orig *store.Turn, rec *store.TurnRecording) error {
+	if session.IsAgentContext() {
+		return fmt.Errorf("replay --rerun is not available inside an agent context")
+	}
+	prompt := recording.Prompt(rec)
+	if strings.TrimSpace(prompt) == "" {
+		return fmt.Errorf("turn #%d has no recorded prompt to re-run", orig.ID)
+	}
+	meta := recording.Metadata(rec)
+	recordedAgent := recording.ReplayAgent(rec)
+	if recordedAgent == "" {
+		recordedAgent = orig.Agent
+	}
+
+	profileName, _ := cmd.Flags().GetString("profile")
+	modelFlag, _ := cmd.Flags().GetString("model")
+	commitRef, _ := cmd.Flags().GetString("commit")
+	noResume, _ := cmd.Flags().GetBool("no-resume")
+
+	globalCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading global config: %w", err)
+	}
+	agentsCfg, err := agent.LoadAgentsConfig()
+	if err != nil {
+		return fmt.Errorf("loading agent configuration: %w", err)
+	}
+	agent.PopulateFromConfig(agentsCfg)
+
+	var prof config.Profile
+	if name := strings.TrimSpace(profileName); name != "" {
+		found := globalCfg.FindProfile(name)
+		if found == nil {
+			return fmt.Errorf("profile %q not found", name)
+		}
+		prof = *found
+	} else {
+		prof = config.Profile{
+			Name:  orig.ProfileName,
+			Agent: recordedAgent,
+			Model: recordedModel(meta["command"], orig.AgentModel),
+		}
+		if prof.Name == "" {
+			prof.Name = "rerun:" + recordedAgent
+		}
+	}
+	if m := strings.TrimSpace(modelFlag); m != "" {
+		prof.Model = m
+	}
+	ag, ok := agent.Get(prof.Agent)
+	if !ok {
+		return fmt.Errorf("unknown agent %q (valid: %s)", prof.Agent, strings.Join(agentNames(), ", "))
+	}
+
+	// Session IDs are agent-specific, so a resume only carries over when the
+	// same agent runs the turn again.
+	resumeID := strings.TrimSpace(meta["resume_session_id"])
+	if noResume || prof.Agent != recordedAgent {
+		resumeID = ""
+	}
+
+	projCfg, err := s.LoadProject()
+	if err != nil {
+		return fmt.Errorf("loading project: %w", err)
+	}
+	repoDir := projCfg.RepoPath
+	if repoDir == "" {
+		repoDir, _ = os.Getwd()
+	}
+	mgr := worktree.NewManager(repoDir)
+	branch := worktree.BranchName(orig.ID, "rerun-"+prof.Name)
+	wtPath, err := mgr.CreateFromRef(ctx, branch, commitRef)
+	if err != nil {
+		return fmt.Errorf("creating worktree at %s: %w", commitRef, err)
+	}
+	defer mgr.Remove(context.Background(), wtPath, false)
+
+	launch := agent.BuildLaunchSpec(&prof, agentsCfg, "")
+	agentCfg := agent.Config{
+		Name:            prof.Agent,
+		Command:         launch.Command,
+		Args:            launch.Args,
+		Env:             launch.Env,
+		WorkDir:         wtPath,
+		Prompt:          prompt,
+		MaxTurns:        1,
+		ResumeSessionID: resumeID,
+		Sandbox:         launch.Sandbox,
+	}
+
+	printHeader(fmt.Sprintf("Re-run of Turn #%d", orig.ID))
+	printField("Agent", prof.Agent)
+	if prof.Model != "" {
+		printField("Model", prof.Model)
+	}
+	printField("Profile", prof.Name)
+	printField("Commit", commitRef)
+	printField("Branch", branch)
+	if resumeID != "" {
+		printField("Resume", resumeID)
+	}
+	fmt.Println()
+
+	var (
+		newTurnID int
+		linkErr   error
+		result    *agent.Result
+	)
+	l := &loop.Loop{
+		Store:       s,
+		Agent:       ag,
+		Config:      agentCfg,
+		ProfileName: prof.Name,
+		GlobalCfg:   globalCfg,
+		PlanID:      orig.PlanID,
+		OnStart: func(turnID int, _ string) {
+			// Link before the loop finalizes (and freezes) the turn.
+			newTurnID = turnID
+			newTurn, err := s.GetTurn(turnID)
+			if err != nil {
+				linkErr = err
+				return
+			}
+			newTurn.RerunOfTurnID = orig.ID
+			newTurn.AgentModel = prof.Model
+			linkErr = s.UpdateTurn(newTurn)
+		},
+		OnEnd: func(_ int, _ string, r *agent.Result) {
+			result = r
+		},
+	}
+	runErr := l.Run(ctx)
+	if newTurnID == 0 {
+		return runErr
+	}
+
+	hash, committed, commitErr := mgr.AutoCommitIfDirty(context.Background(), wtPath,
+		fmt.Sprintf("adaf: re-run of turn #%d", orig.ID))
+	if commitErr != nil {
+		fmt.Fprintf(os.Stderr, "  %swarning: %v%s\n", colorDim, commitErr, colorReset)
+	}
+
+	if linkErr != nil {
+		return fmt.Errorf("linking re-run turn #%d: %w", newTurnID, linkErr)
+	}
+
+	fmt.Println()
+	printHeader("Comparison")
+	printTable([]string{"", "Original", "Re-run"}, [][]string{
+		{"Turn", fmt.Sprintf("#%d", orig.ID), fmt.Sprintf("#%d", newTurnID)},
+		{"Agent", recordedAgent, prof.Agent},
+		{"Model", recordedModel(meta["command"], orig.AgentModel), prof.Model},
+		{"Profile", orig.ProfileName, prof.Name},
+		{"Exit code", meta["exit_code"], rerunExitCode(result)},
+		{"Duration", meta["duration"], rerunDuration(result)},
+		{"Commit", orig.CommitHash, hash},
+	})
+	if committed {
+		fmt.Printf("\n  Changes committed on %s%s%s.\n", styleBoldGreen, branch, colorReset)
+	} else {
+		fmt.Printf("\n  %sThe re-run made no changes.%s\n", colorDim, colorReset)
+	}
+	return runErr
+}
+
+// recordedModel extracts the --model argument from a recorded command line,
+// falling back to the model stored on the turn.
+func recordedModel(command, fallback string) string {
+	fields := strings.Fields(command)
+	for i, f := range fields {
+		if f == "--model" && i+1 < len(fields) {
+			return fields[i+1]
+		}
+		if v, ok := strings.CutPrefix(f, "--model="); ok {
+			return v
+		}
+	}
+	return fallback
+}
+
+func rerunExitCode(r *agent.Result) string {
+	if r == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d", r.ExitCode)
+}
+
+func rerunDuration(r *agent.Result) string {
+	if r == nil {
+		return ""
+	}
+	return r.Duration.Round(time.Millisecond).String()
+}
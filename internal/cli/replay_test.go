@@ -0,0 +1,21 @@
+package cli
+
+import "testing"
+
+func TestRecordedModel(t *testing.T) {
+	tests := []struct {
+		command  string
+		fallback string
+		want     string
+	}{
+		{"claude -p --model opus --output-format stream-json", "", "opus"},
+		{"codex exec --model=gpt-5.1-codex --json", "", "gpt-5.1-codex"},
+		{"vibe --prompt", "mistral-medium", "mistral-medium"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if got := recordedModel(tt.command, tt.fallback); got != tt.want {
+			t.Errorf("recordedModel(%q, %q) = %q, want %q", tt.command, tt.fallback, got, tt.want)
+		}
+	}
+}
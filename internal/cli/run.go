@@ -51,6 +51,8 @@ func init() {
 	runCmd.Flags().String("reasoning-level", "", "Reasoning level (e.g. low, medium, high, xhigh)")
 	runCmd.Flags().BoolP("session", "s", false, "Start and leave detached (use 'adaf attach' to connect)")
 	runCmd.Flags().StringSlice("skills", nil, "Skill IDs to activate (e.g. autonomy,code_writing,commit)")
+	runCmd.Flags().Float64("max-cost", 0, "Hard spend limit in USD across the run and its spawns (0 = unlimited)")
+	runCmd.Flags().Int("max-tokens", 0, "Hard token limit across the run and its spawns (0 = unlimited)")
 	rootCmd.AddCommand(runCmd)
 }
 
@@ -77,11 +79,23 @@ func runAgent(cmd *cobra.Command, args []string) error {
 	reasoningLevel, _ := cmd.Flags().GetString("reasoning-level")
 	sessionMode, _ := cmd.Flags().GetBool("session")
 	skills, _ := cmd.Flags().GetStringSlice("skills")
+	maxCost, _ := cmd.Flags().GetFloat64("max-cost")
+	maxTokens, _ := cmd.Flags().GetInt("max-tokens")
+	runBudget := &config.Budget{MaxCostUSD: maxCost, MaxTokens: maxTokens}
+	if err := runBudget.Validate(); err != nil {
+		return err
+	}
 
 	modelFlag = strings.TrimSpace(modelFlag)
 	reasoningLevel = strings.TrimSpace(reasoningLevel)
 	customCmd = strings.TrimSpace(customCmd)
 
+	agentsCfg, err := agent.LoadAgentsConfig()
+	if err != nil {
+		return fmt.Errorf("loading agent configuration: %w", err)
+	}
+	agent.PopulateFromConfig(agentsCfg)
+
 	if _, ok := agent.Get(agentName); !ok {
 		return fmt.Errorf("unknown agent %q (valid: %s)", agentName, strings.Join(agentNames(), ", "))
 	}
@@ -90,11 +104,6 @@ func runAgent(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("loading global config: %w", err)
 	}
-
-	agentsCfg, err := agent.LoadAgentsConfig()
-	if err != nil {
-		return fmt.Errorf("loading agent configuration: %w", err)
-	}
 	if rec, ok := agentsCfg.Agents[agentName]; ok && customCmd == "" && strings.TrimSpace(rec.Path) != "" {
 		customCmd = strings.TrimSpace(rec.Path)
 	}
@@ -139,6 +148,9 @@ func runAgent(cmd *cobra.Command, args []string) error {
 	}
 
 	loopDef, maxCycles := buildRunLoopDefinition(agentName, profileName, prompt, maxTurns, globalCfg, skills)
+	if !runBudget.IsZero() {
+		loopDef.Budget = runBudget
+	}
 
 	var commandOverrides map[string]string
 	if customCmd != "" {
@@ -1,10 +1,13 @@
 package cli
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/store"
 	"github.com/spf13/cobra"
 )
 
@@ -82,6 +85,43 @@ func TestLoopStartRejectsAgentContext(t *testing.T) {
 	}
 }
 
+func TestLoopStartDryRunLeavesStoreUntouched(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	projectDir := t.TempDir()
+	t.Setenv("ADAF_PROJECT_DIR", projectDir)
+
+	s, err := store.New(projectDir)
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	if err := s.Init(store.ProjectConfig{Name: "test-project", RepoPath: projectDir}); err != nil {
+		t.Fatalf("store.Init() error = %v", err)
+	}
+	wiki := filepath.Join(s.Root(), "wiki")
+	if err := os.RemoveAll(wiki); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Save(&config.GlobalConfig{
+		Profiles: []config.Profile{{Name: "dev", Agent: "codex"}},
+		Loops:    []config.LoopDef{{Name: "dev-loop", Steps: []config.LoopStep{{Profile: "dev"}}}},
+	}); err != nil {
+		t.Fatalf("config.Save() error = %v", err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().AddFlagSet(loopStartCmd.Flags())
+	if err := cmd.Flags().Set("dry-run", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Flags().Set("dry-run", "false")
+	if err := loopStart(cmd, []string{"dev-loop"}); err != nil {
+		t.Fatalf("loopStart(--dry-run) error = %v", err)
+	}
+	if _, err := os.Stat(wiki); !os.IsNotExist(err) {
+		t.Fatalf("dry run repaired the store: stat %s = %v", wiki, err)
+	}
+}
+
 func TestLoopStartPlanFlagExists(t *testing.T) {
 	flag := loopStartCmd.Flags().Lookup("plan")
 	if flag == nil {
@@ -52,6 +52,8 @@ var commandAudienceByPath = map[string]commandAudience{
 	"stats":           commandAudienceUserOnly,
 	"loop list":       commandAudienceUserOnly,
 	"loop start":      commandAudienceUserOnly,
+	"approve":         commandAudienceUserOnly,
+	"deny":            commandAudienceUserOnly,
 
 	"spawn":                commandAudienceAgentOnly,
 	"spawn-status":         commandAudienceAgentOnly,
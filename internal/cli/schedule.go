@@ -0,0 +1,335 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/scheduler"
+	"github.com/agusx1211/adaf/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:     "schedule",
+	Aliases: []string{"schedules", "cron"},
+	Short:   "Manage cron-triggered loop runs",
+	Long: `Schedule loops to start on a cron expression.
+
+Schedules are evaluated by the adaf web daemon (adaf daemon start) for every
+project it serves. When a schedule comes due, the loop is started as a
+detached session, exactly like 'adaf loop start'. If a session for the same
+loop is still running, the run is skipped. Runs that come due while the
+daemon is down are recorded as missed and are not replayed.
+
+Cron expressions use five fields (minute hour day-of-month month day-of-week)
+in local time, or one of @hourly, @daily, @nightly, @weekly, @monthly.
+
+Examples:
+  adaf schedule add --loop dev-cycle --cron "0 2 * * *" --name nightly
+  adaf schedule add --loop triage --cron "*/30 9-18 * * 1-5" --max-cycles 1
+  adaf schedule list
+  adaf schedule disable nightly
+  adaf schedule history nightly`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List loop schedules",
+	RunE:    runScheduleList,
+}
+
+var scheduleAddCmd = &cobra.Command{
+	Use:     "add",
+	Aliases: []string{"create", "new"},
+	Short:   "Create a loop schedule",
+	RunE:    runScheduleAdd,
+}
+
+var scheduleEditCmd = &cobra.Command{
+	Use:     "edit <id|name>",
+	Aliases: []string{"update", "set"},
+	Short:   "Update a loop schedule",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runScheduleEdit,
+}
+
+var scheduleRemoveCmd = &cobra.Command{
+	Use:     "remove <id|name>",
+	Aliases: []string{"rm", "delete"},
+	Short:   "Delete a loop schedule",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runScheduleRemove,
+}
+
+var scheduleEnableCmd = &cobra.Command{
+	Use:   "enable <id|name>",
+	Short: "Enable a loop schedule",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setScheduleEnabled(args[0], true)
+	},
+}
+
+var scheduleDisableCmd = &cobra.Command{
+	Use:   "disable <id|name>",
+	Short: "Disable a loop schedule",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setScheduleEnabled(args[0], false)
+	},
+}
+
+var scheduleHistoryCmd = &cobra.Command{
+	Use:   "history <id|name>",
+	Short: "Show recent runs of a loop schedule",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runScheduleHistory,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{scheduleAddCmd, scheduleEditCmd} {
+		c.Flags().String("name", "", "Schedule name")
+		c.Flags().String("cron", "", "Cron expression (5 fields or @daily-style macro)")
+		c.Flags().String("loop", "", "Loop name from config")
+		c.Flags().String("plan", "", "Plan ID for the run (default: active plan at fire time)")
+		c.Flags().String("prompt", "", "Initial prompt passed to the loop")
+		c.Flags().Int("max-cycles", 0, "Stop the run after N cycles (0 = unlimited)")
+	}
+	_ = scheduleAddCmd.MarkFlagRequired("cron")
+	_ = scheduleAddCmd.MarkFlagRequired("loop")
+	scheduleAddCmd.Flags().Bool("disabled", false, "Create the schedule disabled")
+
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleAddCmd)
+	scheduleCmd.AddCommand(scheduleEditCmd)
+	scheduleCmd.AddCommand(scheduleRemoveCmd)
+	scheduleCmd.AddCommand(scheduleEnableCmd)
+	scheduleCmd.AddCommand(scheduleDisableCmd)
+	scheduleCmd.AddCommand(scheduleHistoryCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
+
+func runScheduleList(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	list, err := s.ListSchedules()
+	if err != nil {
+		return fmt.Errorf("listing schedules: %w", err)
+	}
+
+	printHeader("Loop Schedules")
+	if len(list) == 0 {
+		fmt.Printf("  %sNo schedules. Create one with 'adaf schedule add'.%s\n\n", colorDim, colorReset)
+		return nil
+	}
+
+	now := time.Now()
+	headers := []string{"ID", "NAME", "CRON", "LOOP", "STATE", "NEXT RUN", "LAST"}
+	var rows [][]string
+	for i := range list {
+		sch := &list[i]
+		state := "enabled"
+		if sch.Disabled {
+			state = "disabled"
+		}
+		next := "-"
+		if t := scheduler.NextRun(sch, now); !t.IsZero() {
+			next = t.Local().Format("2006-01-02 15:04")
+		}
+		last := "-"
+		if n := len(sch.History); n > 0 {
+			last = sch.History[n-1].Outcome
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("#%d", sch.ID),
+			truncate(sch.Name, 24),
+			sch.Cron,
+			sch.Loop,
+			state,
+			next,
+			last,
+		})
+	}
+	printTable(headers, rows)
+	fmt.Printf("\n  %sSchedules fire while the web daemon is running (adaf daemon start).%s\n\n", colorDim, colorReset)
+	return nil
+}
+
+func runScheduleAdd(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	sch := &store.LoopSchedule{}
+	applyScheduleFlags(cmd, sch)
+	if disabled, _ := cmd.Flags().GetBool("disabled"); disabled {
+		sch.Disabled = true
+	}
+	if err := validateScheduleConfig(sch); err != nil {
+		return err
+	}
+	if err := s.CreateSchedule(sch); err != nil {
+		return fmt.Errorf("creating schedule: %w", err)
+	}
+
+	fmt.Println()
+	fmt.Printf("  %sSchedule #%d created.%s\n", styleBoldGreen, sch.ID, colorReset)
+	printSchedule(sch)
+	return nil
+}
+
+func runScheduleEdit(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	sch, err := s.FindSchedule(args[0])
+	if err != nil {
+		return fmt.Errorf("schedule %q not found", args[0])
+	}
+	applyScheduleFlags(cmd, sch)
+	if err := validateScheduleConfig(sch); err != nil {
+		return err
+	}
+	if err := s.UpdateSchedule(sch); err != nil {
+		return fmt.Errorf("updating schedule: %w", err)
+	}
+
+	fmt.Println()
+	fmt.Printf("  %sSchedule #%d updated.%s\n", styleBoldGreen, sch.ID, colorReset)
+	printSchedule(sch)
+	return nil
+}
+
+func runScheduleRemove(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	sch, err := s.FindSchedule(args[0])
+	if err != nil {
+		return fmt.Errorf("schedule %q not found", args[0])
+	}
+	if err := s.DeleteSchedule(sch.ID); err != nil {
+		return fmt.Errorf("deleting schedule: %w", err)
+	}
+	fmt.Printf("\n  %sSchedule #%d removed.%s\n\n", styleBoldGreen, sch.ID, colorReset)
+	return nil
+}
+
+func setScheduleEnabled(ref string, enabled bool) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	sch, err := s.FindSchedule(ref)
+	if err != nil {
+		return fmt.Errorf("schedule %q not found", ref)
+	}
+	store.SetScheduleEnabled(sch, enabled)
+	if err := s.UpdateSchedule(sch); err != nil {
+		return fmt.Errorf("updating schedule: %w", err)
+	}
+	state := "enabled"
+	if !enabled {
+		state = "disabled"
+	}
+	fmt.Printf("\n  %sSchedule #%d %s.%s\n\n", styleBoldGreen, sch.ID, state, colorReset)
+	return nil
+}
+
+func runScheduleHistory(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+	sch, err := s.FindSchedule(args[0])
+	if err != nil {
+		return fmt.Errorf("schedule %q not found", args[0])
+	}
+
+	printHeader(fmt.Sprintf("Schedule #%d History", sch.ID))
+	headers := []string{"AT", "OUTCOME", "SESSION", "DETAIL"}
+	var rows [][]string
+	for i := len(sch.History) - 1; i >= 0; i-- {
+		ev := sch.History[i]
+		sessionCol := "-"
+		if ev.SessionID > 0 {
+			sessionCol = fmt.Sprintf("#%d", ev.SessionID)
+		}
+		outcome := ev.Outcome
+		if ev.Count > 1 {
+			outcome = fmt.Sprintf("%s x%d", outcome, ev.Count)
+		}
+		rows = append(rows, []string{
+			ev.At.Local().Format("2006-01-02 15:04"),
+			outcome,
+			sessionCol,
+			truncate(ev.Detail, 60),
+		})
+	}
+	printTable(headers, rows)
+	fmt.Println()
+	return nil
+}
+
+func applyScheduleFlags(cmd *cobra.Command, sch *store.LoopSchedule) {
+	if cmd.Flags().Changed("name") {
+		v, _ := cmd.Flags().GetString("name")
+		sch.Name = strings.TrimSpace(v)
+	}
+	if cmd.Flags().Changed("cron") {
+		v, _ := cmd.Flags().GetString("cron")
+		sch.Cron = strings.TrimSpace(v)
+	}
+	if cmd.Flags().Changed("loop") {
+		v, _ := cmd.Flags().GetString("loop")
+		sch.Loop = strings.TrimSpace(v)
+	}
+	if cmd.Flags().Changed("plan") {
+		v, _ := cmd.Flags().GetString("plan")
+		sch.PlanID = strings.TrimSpace(v)
+	}
+	if cmd.Flags().Changed("prompt") {
+		sch.InitialPrompt, _ = cmd.Flags().GetString("prompt")
+	}
+	if cmd.Flags().Changed("max-cycles") {
+		sch.MaxCycles, _ = cmd.Flags().GetInt("max-cycles")
+	}
+}
+
+func validateScheduleConfig(sch *store.LoopSchedule) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	return scheduler.ValidateSchedule(sch, cfg)
+}
+
+func printSchedule(sch *store.LoopSchedule) {
+	if sch.Name != "" {
+		printField("Name", sch.Name)
+	}
+	printField("Cron", sch.Cron)
+	printField("Loop", sch.Loop)
+	if sch.PlanID != "" {
+		printField("Plan", sch.PlanID)
+	}
+	if sch.MaxCycles > 0 {
+		printField("Max Cycles", fmt.Sprintf("%d", sch.MaxCycles))
+	}
+	if sch.Disabled {
+		printField("State", "disabled")
+	} else if next := scheduler.NextRun(sch, time.Now()); !next.IsZero() {
+		printField("Next Run", next.Local().Format("2006-01-02 15:04 MST"))
+	}
+	fmt.Println()
+}
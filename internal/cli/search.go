@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/agusx1211/adaf/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:     "search <query>",
+	Aliases: []string{"find"},
+	Short:   "Search turns, wiki, issues and recordings",
+	Long: `Full-text search across the project store using a persistent local index.
+
+Turns, wiki entries, issues and turn recordings are ranked together; titles
+weigh more than body text and results matching every query term come first.
+The index is built on first use and updated on every store write.
+
+Examples:
+  adaf search "auth middleware"
+  adaf search "flaky scheduler test" --type turn,issue
+  adaf search "rollout" --plan my-plan --limit 5
+  adaf search --reindex "auth"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().StringSlice("type", nil, "Document types to search: turn, wiki, issue, recording (default all)")
+	searchCmd.Flags().String("plan", "", "Restrict to shared documents and this plan's documents")
+	searchCmd.Flags().IntP("limit", "n", 20, "Maximum number of results")
+	searchCmd.Flags().Bool("reindex", false, "Rebuild the search index before searching")
+	searchCmd.Flags().Bool("json", false, "Output results as JSON")
+	rootCmd.AddCommand(searchCmd)
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	s, err := openStoreRequired()
+	if err != nil {
+		return err
+	}
+
+	query := strings.TrimSpace(strings.Join(args, " "))
+	if query == "" {
+		return fmt.Errorf("query is required")
+	}
+	kinds, _ := cmd.Flags().GetStringSlice("type")
+	if err := validateSearchKinds(kinds); err != nil {
+		return err
+	}
+	planID, _ := cmd.Flags().GetString("plan")
+	planID = strings.TrimSpace(planID)
+	if planID != "" {
+		if err := validatePlanID(planID); err != nil {
+			return err
+		}
+	}
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit <= 0 {
+		return fmt.Errorf("--limit must be > 0")
+	}
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	if reindex, _ := cmd.Flags().GetBool("reindex"); reindex {
+		if err := s.RebuildSearchIndex(); err != nil {
+			return fmt.Errorf("rebuilding search index: %w", err)
+		}
+	}
+
+	results, err := s.Search(store.SearchQuery{Text: query, Kinds: kinds, PlanID: planID, Limit: limit})
+	if err != nil {
+		return fmt.Errorf("searching: %w", err)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	printHeader("Search Results")
+	printField("Query", query)
+	printField("Matches", fmt.Sprintf("%d", len(results)))
+	fmt.Println()
+
+	if len(results) == 0 {
+		fmt.Printf("  %sNo matches found.%s\n\n", colorDim, colorReset)
+		return nil
+	}
+
+	for _, r := range results {
+		who := r.Agent
+		if r.Profile != "" {
+			who = strings.TrimSpace(r.Profile + " " + r.Agent)
+		}
+		meta := r.Updated.Format("2006-01-02")
+		if who != "" {
+			meta += " · " + who
+		}
+		if r.PlanID != "" {
+			meta += " · plan " + r.PlanID
+		}
+		fmt.Printf("  %s%s %s%s  %s\n", styleBoldGreen, r.Kind, searchResultRef(r), colorReset, truncate(r.Title, 70))
+		fmt.Printf("    %s%s%s\n", colorDim, meta, colorReset)
+		if r.Snippet != "" {
+			fmt.Printf("    %s\n", r.Snippet)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func searchResultRef(r store.SearchResult) string {
+	switch r.Kind {
+	case store.SearchKindTurn, store.SearchKindIssue, store.SearchKindRecording:
+		return "#" + r.ID
+	default:
+		return r.ID
+	}
+}
+
+func validateSearchKinds(kinds []string) error {
+	for _, k := range kinds {
+		k = strings.ToLower(strings.TrimSpace(k))
+		valid := false
+		for _, known := range store.SearchKinds {
+			if k == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("unknown search type %q (valid: %s)", k, strings.Join(store.SearchKinds, ", "))
+		}
+	}
+	return nil
+}
@@ -32,6 +32,9 @@ Examples:
   adaf spawn --profile developer --task-file task.md
   adaf spawn --profile lead-dev --task "Review PR #42" --read-only
   adaf spawn --profile qa --from-spawn 3 --task "QA pass before merge"
+  adaf spawn --profile developer --task "Fix auth" --check "go test ./..." --verify-retries 2
+  adaf spawn --profile developer --task "Port the parser" --host any
+  adaf spawn --profile developer --task "Hotfix login" --priority critical
   adaf spawn-status                       # Check all spawns
   adaf spawn-diff --spawn-id 3            # View changes
   adaf spawn-merge --spawn-id 3           # Merge changes`,
@@ -46,6 +49,10 @@ func init() {
 	spawnCmd.Flags().IntSlice("issue", nil, "Issue ID(s) to assign to the sub-agent (can be repeated)")
 	spawnCmd.Flags().Int("from-spawn", 0, "Create the child workspace from an existing spawn branch tip")
 	spawnCmd.Flags().Bool("read-only", false, "Run sub-agent in read-only mode (no worktree)")
+	addAcceptanceCheckFlags(spawnCmd)
+	spawnCmd.Flags().Int("verify-retries", 0, "Resume the child with the failure output this many times when checks fail")
+	spawnCmd.Flags().String("host", "", "Run the sub-agent on a configured remote host (name, or \"any\" for the least busy eligible one)")
+	spawnCmd.Flags().String("priority", "", "Queue priority when spawn limits are reached: critical, high, medium, low (default: highest of --issue, else medium)")
 	spawnCmd.SuggestFor = append(spawnCmd.SuggestFor, "spawn-profile", "spawnprofile")
 	rootCmd.AddCommand(spawnCmd)
 }
@@ -58,7 +65,21 @@ func runSpawn(cmd *cobra.Command, args []string) error {
 	issueIDs, _ := cmd.Flags().GetIntSlice("issue")
 	fromSpawnID, _ := cmd.Flags().GetInt("from-spawn")
 	readOnly, _ := cmd.Flags().GetBool("read-only")
+	verifyRetries, _ := cmd.Flags().GetInt("verify-retries")
+	host, _ := cmd.Flags().GetString("host")
+	priority, _ := cmd.Flags().GetString("priority")
 	childRole = strings.ToLower(strings.TrimSpace(childRole))
+	checks, err := acceptanceChecksFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if verifyRetries < 0 {
+		return fmt.Errorf("--verify-retries must be >= 0")
+	}
+	priority = strings.ToLower(strings.TrimSpace(priority))
+	if priority != "" && !store.IsValidIssuePriority(priority) {
+		return fmt.Errorf("invalid --priority %q (valid: critical, high, medium, low)", priority)
+	}
 
 	// Bare invocation: no profile, no task → show contextual guide.
 	if profileName == "" && task == "" && taskFile == "" {
@@ -107,40 +128,7 @@ func runSpawn(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if daemonSessionID, ok := currentDaemonSessionID(); ok {
-		resp, err := session.RequestSpawn(daemonSessionID, session.WireControlSpawn{
-			ParentTurnID:         parentTurnID,
-			ParentProfile:        parentProfile,
-			ParentPosition:       parentPosition,
-			ChildProfile:         profileName,
-			ChildRole:            childRole,
-			PlanID:               planID,
-			Task:                 task,
-			IssueIDs:             issueIDs,
-			WorkspaceFromSpawnID: fromSpawnID,
-			ReadOnly:             readOnly,
-			Delegation:           delegation,
-		})
-		if err != nil {
-			return fmt.Errorf("spawn failed: %w", err)
-		}
-		if resp == nil || !resp.OK {
-			if resp != nil && strings.TrimSpace(resp.Error) != "" {
-				return fmt.Errorf("spawn failed: %s", resp.Error)
-			}
-			return fmt.Errorf("spawn failed: daemon returned an empty response")
-		}
-
-		fmt.Printf("Spawned sub-agent #%d (%s)\n", resp.SpawnID, spawnedDescriptor(profileName, childRole))
-		return nil
-	}
-
-	o, err := ensureOrchestrator()
-	if err != nil {
-		return err
-	}
-
-	spawnID, err := o.Spawn(context.Background(), orchestrator.SpawnRequest{
+	spawnID, err := dispatchSpawn(orchestrator.SpawnRequest{
 		ParentTurnID:         parentTurnID,
 		ParentProfile:        parentProfile,
 		ParentPosition:       parentPosition,
@@ -152,15 +140,71 @@ func runSpawn(cmd *cobra.Command, args []string) error {
 		WorkspaceFromSpawnID: fromSpawnID,
 		ReadOnly:             readOnly,
 		Delegation:           delegation,
+		Checks:               checks,
+		VerifyRetries:        verifyRetries,
+		Host:                 strings.TrimSpace(host),
+		Priority:             priority,
 	})
 	if err != nil {
-		return fmt.Errorf("spawn failed: %w", err)
+		return err
 	}
 
+	if s, err := openStoreRequired(); err == nil {
+		if rec, err := s.GetSpawn(spawnID); err == nil && rec.Status == store.SpawnStatusQueued {
+			fmt.Printf("Queued sub-agent #%d (%s) at position %d; it starts when a spawn slot frees up\n",
+				spawnID, spawnedDescriptor(profileName, childRole), rec.QueuePosition)
+			return nil
+		}
+	}
 	fmt.Printf("Spawned sub-agent #%d (%s)\n", spawnID, spawnedDescriptor(profileName, childRole))
 	return nil
 }
 
+// dispatchSpawn starts a child through the owning daemon session when one is
+// active, or through the local orchestrator otherwise.
+func dispatchSpawn(req orchestrator.SpawnRequest) (int, error) {
+	if daemonSessionID, ok := currentDaemonSessionID(); ok {
+		resp, err := session.RequestSpawn(daemonSessionID, session.WireControlSpawn{
+			ParentTurnID:         req.ParentTurnID,
+			ParentProfile:        req.ParentProfile,
+			ParentPosition:       req.ParentPosition,
+			ChildProfile:         req.ChildProfile,
+			ChildRole:            req.ChildRole,
+			PlanID:               req.PlanID,
+			Task:                 req.Task,
+			IssueIDs:             req.IssueIDs,
+			WorkspaceFromSpawnID: req.WorkspaceFromSpawnID,
+			ReadOnly:             req.ReadOnly,
+			Delegation:           req.Delegation,
+			Checks:               req.Checks,
+			VerifyRetries:        req.VerifyRetries,
+			Host:                 req.Host,
+			Priority:             req.Priority,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("spawn failed: %w", err)
+		}
+		if resp == nil || !resp.OK {
+			if resp != nil && strings.TrimSpace(resp.Error) != "" {
+				return 0, fmt.Errorf("spawn failed: %s", resp.Error)
+			}
+			return 0, fmt.Errorf("spawn failed: daemon returned an empty response")
+		}
+		return resp.SpawnID, nil
+	}
+
+	o, err := ensureOrchestrator()
+	if err != nil {
+		return 0, err
+	}
+
+	spawnID, err := o.Spawn(context.Background(), req)
+	if err != nil {
+		return 0, fmt.Errorf("spawn failed: %w", err)
+	}
+	return spawnID, nil
+}
+
 func resolveCurrentDelegation(parentProfile string) (*config.DelegationConfig, error) {
 	if raw := strings.TrimSpace(os.Getenv("ADAF_DELEGATION_JSON")); raw != "" {
 		var deleg config.DelegationConfig
@@ -213,14 +257,10 @@ func resolveCurrentDelegation(parentProfile string) (*config.DelegationConfig, e
 	if step.Profile != "" && !strings.EqualFold(step.Profile, parentProfile) {
 		return nil, fmt.Errorf("loop step %d profile mismatch: env profile=%q, loop profile=%q", stepIdx, parentProfile, step.Profile)
 	}
-	if step.Team == "" {
-		return &config.DelegationConfig{}, nil
-	}
-	team := globalCfg.FindTeam(step.Team)
-	if team == nil || team.Delegation == nil {
-		return &config.DelegationConfig{}, nil
+	if deleg := globalCfg.StepDelegation(loopDef, step); deleg != nil {
+		return deleg.Clone(), nil
 	}
-	return team.Delegation.Clone(), nil
+	return &config.DelegationConfig{}, nil
 }
 
 func getTurnContext() (int, string, string, error) {
@@ -313,6 +353,16 @@ func printSpawnRecord(r *store.SpawnRecord) {
 	if r.ReadOnly {
 		printField("Mode", "read-only")
 	}
+	if r.RemoteHost != "" {
+		printField("Remote Host", r.RemoteHost)
+	}
+	if r.Isolation != "" {
+		printField("Isolation", r.Isolation)
+	}
+	if r.Status == store.SpawnStatusQueued {
+		printFieldColored("Queue Position", fmt.Sprintf("%d (priority %s, queued %s ago)",
+			r.QueuePosition, store.NormalizeIssuePriority(r.Priority), time.Since(r.QueuedAt).Round(time.Second)), colorYellow)
+	}
 	if r.Status == "running" || r.Status == "awaiting_input" {
 		printField("Elapsed", time.Since(r.StartedAt).Round(time.Second).String())
 	}
@@ -322,9 +372,27 @@ func printSpawnRecord(r *store.SpawnRecord) {
 	if r.MergeCommit != "" {
 		printField("Merge Commit", r.MergeCommit)
 	}
+	if r.PullRequestURL != "" {
+		printField("Pull Request", r.PullRequestURL)
+	}
+	if len(r.ConflictFiles) > 0 {
+		printField("Conflicts", strings.Join(r.ConflictFiles, ", "))
+	}
+	if r.ResolverSpawnID > 0 {
+		printField("Resolver Spawn", fmt.Sprintf("%d", r.ResolverSpawnID))
+	}
 	if r.Result != "" {
 		printField("Result", truncate(r.Result, 120))
 	}
+	if len(r.Checks) > 0 {
+		printField("Checks", describeAcceptance(r))
+	}
+	if r.UsagePause != nil {
+		printFieldColored("Usage Pause", describeUsagePause(r.UsagePause), colorYellow)
+	}
+	for _, f := range r.Failovers {
+		printField("Failover", fmt.Sprintf("%s -> %s (%s, turn #%d)", f.From, f.To, f.Reason, f.TurnID))
+	}
 	if r.Status == "awaiting_input" {
 		s, err := openStoreRequired()
 		if err == nil {
@@ -414,3 +482,66 @@ func printSpawnGuide() error {
 
 	return nil
 }
+
+// addAcceptanceCheckFlags registers the flags read by acceptanceChecksFromFlags.
+func addAcceptanceCheckFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("check", nil, "Acceptance command that must exit 0 in the spawn's worktree (can be repeated)")
+	cmd.Flags().StringArray("require-file", nil, "File the spawn's worktree must contain (can be repeated)")
+	cmd.Flags().Int("max-diff-lines", 0, "Fail verification when the spawn changes more lines than this (0 = no limit)")
+}
+
+// acceptanceChecksFromFlags builds acceptance checks from --check,
+// --require-file and --max-diff-lines.
+func acceptanceChecksFromFlags(cmd *cobra.Command) ([]store.AcceptanceCheck, error) {
+	commands, _ := cmd.Flags().GetStringArray("check")
+	files, _ := cmd.Flags().GetStringArray("require-file")
+	maxDiff, _ := cmd.Flags().GetInt("max-diff-lines")
+
+	var checks []store.AcceptanceCheck
+	for _, c := range commands {
+		checks = append(checks, store.AcceptanceCheck{Command: c})
+	}
+	for _, f := range files {
+		checks = append(checks, store.AcceptanceCheck{File: f})
+	}
+	if maxDiff < 0 {
+		return nil, fmt.Errorf("--max-diff-lines must be >= 0")
+	}
+	if maxDiff > 0 {
+		checks = append(checks, store.AcceptanceCheck{MaxDiffLines: maxDiff})
+	}
+	for _, c := range checks {
+		if err := c.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return checks, nil
+}
+
+func describeAcceptanceChecks(checks []store.AcceptanceCheck) string {
+	parts := make([]string, 0, len(checks))
+	for _, c := range checks {
+		parts = append(parts, c.Describe())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// describeAcceptance summarizes a spawn's checks and their latest results.
+func describeAcceptance(r *store.SpawnRecord) string {
+	if len(r.CheckResults) == 0 {
+		return describeAcceptanceChecks(r.Checks)
+	}
+	parts := make([]string, 0, len(r.CheckResults))
+	for _, res := range r.CheckResults {
+		mark := "pass"
+		if !res.Passed {
+			mark = "FAIL"
+		}
+		parts = append(parts, fmt.Sprintf("%s [%s]", res.Check.Describe(), mark))
+	}
+	desc := strings.Join(parts, "; ")
+	if r.VerifyAttempts > 0 {
+		desc += fmt.Sprintf(" (retries used: %d/%d)", r.VerifyAttempts, r.VerifyRetries)
+	}
+	return desc
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/agusx1211/adaf/internal/config"
 	"github.com/agusx1211/adaf/internal/profilescore"
+	"github.com/agusx1211/adaf/internal/stats"
 )
 
 var spawnInfoCmd = &cobra.Command{
@@ -43,6 +44,7 @@ func runSpawnInfo(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("loading config: %w", err)
 	}
 	perfByProfile := loadSpawnInfoPerformance(globalCfg)
+	costByProfile := loadSpawnInfoCostEstimates(globalCfg, deleg)
 
 	fmt.Printf("Maximum concurrent sub-agents: %d\n\n", deleg.EffectiveMaxParallel())
 
@@ -83,6 +85,9 @@ func runSpawnInfo(cmd *cobra.Command, args []string) error {
 		if cost := config.NormalizeProfileCost(p.Cost); cost != "" {
 			fmt.Printf("    Cost: %s\n", cost)
 		}
+		if est, ok := costByProfile[strings.ToLower(strings.TrimSpace(p.Name))]; ok {
+			fmt.Printf("    Est. cost/task: %s\n", est)
+		}
 
 		speed := dp.Speed
 		if speed == "" {
@@ -185,6 +190,22 @@ func loadSpawnInfoPerformance(globalCfg *config.GlobalConfig) map[string]profile
 	return out
 }
 
+// loadSpawnInfoCostEstimates estimates one task for each delegation
+// profile from the project's profile stats and the model price catalog.
+func loadSpawnInfoCostEstimates(globalCfg *config.GlobalConfig, deleg *config.DelegationConfig) map[string]stats.CostEstimate {
+	s, err := openStore()
+	if err != nil || !s.Exists() {
+		return nil
+	}
+	var profiles []config.Profile
+	for _, dp := range deleg.Profiles {
+		if p := globalCfg.FindProfile(dp.Name); p != nil {
+			profiles = append(profiles, *p)
+		}
+	}
+	return stats.EstimateProfileCosts(s, globalCfg, profiles)
+}
+
 func formatSpawnInfoDuration(avgDurationSecs float64) string {
 	if avgDurationSecs <= 0 {
 		return "n/a"
@@ -2,40 +2,227 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/agusx1211/adaf/internal/notify"
+	"github.com/agusx1211/adaf/internal/orchestrator"
+	"github.com/agusx1211/adaf/internal/session"
+	"github.com/agusx1211/adaf/internal/store"
+	"github.com/agusx1211/adaf/internal/worktree"
 )
 
+const mergeConflictResolve = "resolve"
+
 var spawnMergeCmd = &cobra.Command{
 	Use:     "spawn-merge",
 	Aliases: []string{"spawn_merge", "spawnmerge"},
 	Short:   "Merge a spawn's changes into the current branch",
-	RunE:    runSpawnMerge,
+	Long: `Merge a spawn's changes into the current branch.
+
+When the merge conflicts, the conflicted files are recorded on the spawn and
+--on-conflict decides what happens next:
+  abort    restore the current branch to its pre-merge state (default)
+  keep     leave the merge open; fix the files, git add them, then run
+           adaf spawn-merge --spawn-id N --continue
+  resolve  abort, then spawn a resolver child from the spawn's branch with
+           the conflict hunks in its task (merge the resolver spawn afterwards)
+
+Examples:
+  adaf spawn-merge --spawn-id 3
+  adaf spawn-merge --spawn-id 3 --on-conflict keep
+  adaf spawn-merge --spawn-id 3 --on-conflict resolve --resolver-profile developer`,
+	RunE: runSpawnMerge,
 }
 
 func init() {
 	spawnMergeCmd.Flags().Int("spawn-id", 0, "Spawn ID (required)")
 	spawnMergeCmd.Flags().Bool("squash", false, "Squash merge instead of merge commit")
+	spawnMergeCmd.Flags().String("on-conflict", orchestrator.MergeConflictAbort, "What to do on merge conflicts: abort, keep, or resolve")
+	spawnMergeCmd.Flags().String("resolver-profile", "", "Profile for the resolver child with --on-conflict resolve (default: the spawn's profile)")
+	spawnMergeCmd.Flags().Bool("continue", false, "Conclude a merge kept open with --on-conflict keep")
 	rootCmd.AddCommand(spawnMergeCmd)
 }
 
 func runSpawnMerge(cmd *cobra.Command, args []string) error {
 	spawnID, _ := cmd.Flags().GetInt("spawn-id")
 	squash, _ := cmd.Flags().GetBool("squash")
+	onConflict, _ := cmd.Flags().GetString("on-conflict")
+	resolverProfile, _ := cmd.Flags().GetString("resolver-profile")
+	cont, _ := cmd.Flags().GetBool("continue")
+	onConflict = strings.ToLower(strings.TrimSpace(onConflict))
 	if spawnID == 0 {
 		return fmt.Errorf("--spawn-id is required")
 	}
+	switch onConflict {
+	case orchestrator.MergeConflictAbort, orchestrator.MergeConflictKeep, mergeConflictResolve:
+	default:
+		return fmt.Errorf("invalid --on-conflict %q (valid: abort, keep, resolve)", onConflict)
+	}
 
 	o, err := ensureOrchestrator()
 	if err != nil {
 		return err
 	}
 
-	hash, err := o.Merge(context.Background(), spawnID, squash)
-	if err != nil {
+	if cont {
+		hash, err := o.CompleteMerge(context.Background(), spawnID)
+		if err != nil {
+			return fmt.Errorf("merge failed: %w", err)
+		}
+		fmt.Printf("Merged spawn #%d: commit=%s\n", spawnID, hash)
+		return nil
+	}
+
+	mode := onConflict
+	if mode == mergeConflictResolve {
+		mode = orchestrator.MergeConflictAbort
+	}
+	// The approval gate comes from the spawn record, not from the caller.
+	hash, err := o.MergeWithOptions(context.Background(), spawnID, orchestrator.MergeOptions{
+		Squash:      squash,
+		OnConflict:  mode,
+		RequestedBy: mergeRequester(),
+	})
+	if err == nil {
+		fmt.Printf("Merged spawn #%d: commit=%s\n", spawnID, hash)
+		printMergeApprovalComment(spawnID)
+		return nil
+	}
+	if handled, approvalErr := reportMergeApproval(err); handled {
+		return approvalErr
+	}
+
+	var conflict *worktree.MergeConflictError
+	if !errors.As(err, &conflict) {
 		return fmt.Errorf("merge failed: %w", err)
 	}
-	fmt.Printf("Merged spawn #%d: commit=%s\n", spawnID, hash)
-	return nil
+	switch onConflict {
+	case orchestrator.MergeConflictKeep:
+		fmt.Printf("Merge of spawn #%d stopped with conflicts in:\n", spawnID)
+		printConflictFiles(conflict.Files)
+		fmt.Printf("Resolve them, 'git add' the files, then run: adaf spawn-merge --spawn-id %d --continue\n", spawnID)
+		return fmt.Errorf("merge left open with %d conflicted file(s)", len(conflict.Files))
+	case mergeConflictResolve:
+		resolverID, err := spawnMergeResolver(o, spawnID, resolverProfile, conflict)
+		if err != nil {
+			return fmt.Errorf("merge conflicted and resolver spawn failed: %w", err)
+		}
+		fmt.Printf("Merge of spawn #%d conflicted (aborted) in:\n", spawnID)
+		printConflictFiles(conflict.Files)
+		fmt.Printf("Spawned resolver #%d; merge it once it completes: adaf spawn-merge --spawn-id %d\n", resolverID, resolverID)
+		return nil
+	default:
+		fmt.Printf("Merge of spawn #%d conflicted and was aborted. Conflicted files:\n", spawnID)
+		printConflictFiles(conflict.Files)
+		return fmt.Errorf("merge aborted: %d conflicted file(s) (retry with --on-conflict keep or resolve)", len(conflict.Files))
+	}
+}
+
+// mergeRequester returns the calling agent's turn ID, recorded on approvals
+// the merge files, or 0 outside an agent.
+func mergeRequester() int {
+	if !session.IsAgentContext() {
+		return 0
+	}
+	turnID, _, _, err := getTurnContext()
+	if err != nil {
+		return 0
+	}
+	return turnID
+}
+
+// reportMergeApproval explains a merge blocked on, or refused by, a human.
+func reportMergeApproval(err error) (bool, error) {
+	var pending *orchestrator.ApprovalPendingError
+	if errors.As(err, &pending) {
+		a := pending.Approval
+		if pending.Created {
+			if s, openErr := openStoreRequired(); openErr == nil {
+				notifyEvent(s, notify.ApprovalNeeded("", a))
+			}
+		}
+		fmt.Printf("Merge of spawn #%d is blocked: %s.\n", a.SpawnID, a.Reason)
+		fmt.Printf("A human must approve it (approval #%d). Continue with other work, or wait with: adaf approvals wait %d\n", a.ID, a.ID)
+		fmt.Printf("Once approved, re-run: adaf spawn-merge --spawn-id %d\n", a.SpawnID)
+		return true, fmt.Errorf("merge waiting for approval #%d", a.ID)
+	}
+	var denied *orchestrator.ApprovalDeniedError
+	if errors.As(err, &denied) {
+		a := denied.Approval
+		fmt.Printf("Merge of spawn #%d was denied by %s (approval #%d).\n", a.SpawnID, approvalDecider(a), a.ID)
+		if a.Comment != "" {
+			fmt.Printf("Reviewer comment: %s\n", a.Comment)
+		}
+		return true, fmt.Errorf("merge denied")
+	}
+	return false, nil
+}
+
+// printMergeApprovalComment relays the reviewer's comment after an approved
+// merge.
+func printMergeApprovalComment(spawnID int) {
+	s, err := openStoreRequired()
+	if err != nil {
+		return
+	}
+	a, err := s.MergeApproval(spawnID)
+	if err != nil || a == nil || a.Status != store.ApprovalApproved || a.Comment == "" {
+		return
+	}
+	fmt.Printf("Approved by %s: %s\n", approvalDecider(a), a.Comment)
+}
+
+func printConflictFiles(files []string) {
+	for _, f := range files {
+		fmt.Printf("  %s\n", f)
+	}
+}
+
+// spawnMergeResolver starts a child from the conflicted spawn's branch whose
+// task is to merge the parent HEAD and resolve the reported conflicts.
+func spawnMergeResolver(o *orchestrator.Orchestrator, spawnID int, profileName string, conflict *worktree.MergeConflictError) (int, error) {
+	s, err := openStoreRequired()
+	if err != nil {
+		return 0, err
+	}
+	rec, err := s.GetSpawn(spawnID)
+	if err != nil {
+		return 0, fmt.Errorf("spawn %d not found: %w", spawnID, err)
+	}
+	if strings.TrimSpace(profileName) == "" {
+		profileName = rec.ChildProfile
+	}
+
+	parentTurnID, parentProfile, parentPosition, err := getTurnContext()
+	if err != nil {
+		return 0, err
+	}
+	delegation, err := resolveCurrentDelegation(parentProfile)
+	if err != nil {
+		return 0, err
+	}
+
+	resolverID, err := dispatchSpawn(orchestrator.SpawnRequest{
+		ParentTurnID:         parentTurnID,
+		ParentProfile:        parentProfile,
+		ParentPosition:       parentPosition,
+		ChildProfile:         profileName,
+		PlanID:               strings.TrimSpace(os.Getenv("ADAF_PLAN_ID")),
+		Task:                 orchestrator.MergeResolverTask(rec, conflict),
+		IssueIDs:             rec.IssueIDs,
+		WorkspaceFromSpawnID: spawnID,
+		Delegation:           delegation,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := o.RecordMergeResolver(spawnID, resolverID); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not record resolver on spawn #%d: %v\n", spawnID, err)
+	}
+	return resolverID, nil
 }
@@ -46,14 +46,30 @@ func runSpawnStatus(cmd *cobra.Command, args []string) error {
 	}
 	if len(records) == 0 {
 		fmt.Println("No spawns found for this session.")
-		return nil
 	}
 	for _, r := range records {
 		printSpawnRecord(&r)
 	}
+	printEscalatedAsks(s, parentTurnID)
 	return nil
 }
 
+// printEscalatedAsks lists deeper descendants' questions that escalated to
+// this turn because their own parent did not answer.
+func printEscalatedAsks(s *store.Store, turnID int) {
+	asks, err := s.PendingAsks()
+	if err != nil {
+		return
+	}
+	for _, ask := range asks {
+		if ask.ToHuman || ask.EscalatedAt.IsZero() || ask.AddresseeTurnID != turnID {
+			continue
+		}
+		fmt.Printf("Escalated question from spawn #%d: %s\n", ask.SpawnID, truncate(ask.Content, 120))
+		fmt.Printf("  Answer with: adaf inbox answer %d \"...\"\n", ask.SpawnID)
+	}
+}
+
 var spawnWaitCmd = &cobra.Command{
 	Use:     "spawn-wait",
 	Aliases: []string{"spawn_wait", "spawnwait"},
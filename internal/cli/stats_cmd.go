@@ -75,7 +75,7 @@ func runStatsOverview(cmd *cobra.Command, args []string) error {
 			rows = append(rows, []string{
 				styleBoldWhite + ps.ProfileName + colorReset,
 				fmt.Sprintf("%d", ps.TotalRuns),
-				fmt.Sprintf("$%.2f", ps.TotalCostUSD),
+				formatStatsCost(ps.TotalCostUSD, ps.EstimatedCost),
 				lastRun,
 			})
 		}
@@ -94,7 +94,7 @@ func runStatsOverview(cmd *cobra.Command, args []string) error {
 			rows = append(rows, []string{
 				styleBoldWhite + ls.LoopName + colorReset,
 				fmt.Sprintf("%d", ls.TotalCycles),
-				fmt.Sprintf("$%.2f", ls.TotalCostUSD),
+				formatStatsCost(ls.TotalCostUSD, ls.EstimatedCost),
 				lastRun,
 			})
 		}
@@ -103,3 +103,26 @@ func runStatsOverview(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// formatStatsCost renders a cost total, noting the part that was priced
+// from the model price table rather than reported by the agent.
+func formatStatsCost(total, estimated float64) string {
+	if estimated <= 0 {
+		return fmt.Sprintf("$%.2f", total)
+	}
+	return fmt.Sprintf("$%.2f (~$%.2f estimated)", total, estimated)
+}
+
+// formatStatsTokens renders token totals with the cached input and
+// reasoning shares when there are any.
+func formatStatsTokens(input, cached, output, reasoning int) string {
+	in := formatTokens(input) + " input"
+	if cached > 0 {
+		in += fmt.Sprintf(" (+%s cached)", formatTokens(cached))
+	}
+	out := formatTokens(output) + " output"
+	if reasoning > 0 {
+		out += fmt.Sprintf(" (+%s reasoning)", formatTokens(reasoning))
+	}
+	return in + ", " + out
+}
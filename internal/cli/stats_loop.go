@@ -39,7 +39,7 @@ func runStatsLoop(cmd *cobra.Command, args []string) error {
 	printHeader(fmt.Sprintf("Loop: %s", name))
 	printField("Runs", fmt.Sprintf("%d", ls.TotalRuns))
 	printField("Total Cycles", fmt.Sprintf("%d", ls.TotalCycles))
-	printField("Total Cost", fmt.Sprintf("$%.2f", ls.TotalCostUSD))
+	printField("Total Cost", formatStatsCost(ls.TotalCostUSD, ls.EstimatedCost))
 	printField("Total Duration", formatDuration(ls.TotalDuration))
 
 	if len(ls.StepStats) > 0 {
@@ -94,7 +94,7 @@ func runStatsLoopMarkdown(s *store.Store, name string) error {
 		if turns == 0 {
 			turns = 1
 		}
-		fmt.Printf("  %d. %s (turns: %d", i+1, step.Profile, turns)
+		fmt.Printf("  %d. %s (turns: %d", i+1, step.Label(), turns)
 		if step.CanStop {
 			fmt.Print(", can_stop")
 		}
@@ -112,7 +112,7 @@ func runStatsLoopMarkdown(s *store.Store, name string) error {
 		fmt.Println("## Aggregate Statistics")
 		fmt.Printf("- Total Runs: %d\n", ls.TotalRuns)
 		fmt.Printf("- Total Cycles: %d\n", ls.TotalCycles)
-		fmt.Printf("- Total Cost: $%.2f\n", ls.TotalCostUSD)
+		fmt.Printf("- Total Cost: %s\n", formatStatsCost(ls.TotalCostUSD, ls.EstimatedCost))
 		fmt.Printf("- Total Duration: %s\n", formatDuration(ls.TotalDuration))
 		fmt.Printf("- Average Cost/Run: $%.2f\n", ls.TotalCostUSD/float64(ls.TotalRuns))
 		fmt.Printf("- Average Cycles/Run: %.1f\n", float64(ls.TotalCycles)/float64(ls.TotalRuns))
@@ -142,7 +142,7 @@ func runStatsLoopMarkdown(s *store.Store, name string) error {
 			if err != nil {
 				continue
 			}
-			metrics, _ := stats.ExtractFromRecording(s, tid)
+			metrics, _ := stats.ExtractFromRecording(s, tid, globalCfg)
 
 			outcome := "unknown"
 			if turn.BuildState == "success" {
@@ -158,7 +158,11 @@ func runStatsLoopMarkdown(s *store.Store, name string) error {
 				outcome)
 
 			if metrics != nil && metrics.TotalCostUSD > 0 {
-				fmt.Printf(" cost=$%.4f", metrics.TotalCostUSD)
+				if metrics.CostEstimated {
+					fmt.Printf(" cost=~$%.4f", metrics.TotalCostUSD)
+				} else {
+					fmt.Printf(" cost=$%.4f", metrics.TotalCostUSD)
+				}
 			}
 			fmt.Println()
 		}
@@ -71,15 +71,10 @@ func runStatsMigrate(cmd *cobra.Command, args []string) error {
 			ps.LastRunAt = turn.Date
 		}
 
-		metrics, err := stats.ExtractFromRecording(s, turn.ID)
+		metrics, err := stats.ExtractFromRecording(s, turn.ID, globalCfg)
 		if err == nil {
-			ps.TotalCostUSD += metrics.TotalCostUSD
-			ps.TotalInputTok += metrics.InputTokens
-			ps.TotalOutputTok += metrics.OutputTokens
+			stats.AddToProfileStats(ps, metrics)
 			ps.TotalTurns += metrics.NumTurns
-			for tool, count := range metrics.ToolCalls {
-				ps.ToolCalls[tool] += count
-			}
 		}
 	}
 
@@ -107,13 +102,13 @@ func runStatsMigrate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	migrateLoopStats(s)
+	migrateLoopStats(s, globalCfg)
 
 	fmt.Println(styleBoldGreen + "Migration complete." + colorReset)
 	return nil
 }
 
-func migrateLoopStats(s *store.Store) {
+func migrateLoopStats(s *store.Store, globalCfg *config.GlobalConfig) {
 	dir := s.Root()
 	_ = dir
 
@@ -143,9 +138,12 @@ func migrateLoopStats(s *store.Store) {
 		ls.TotalCycles += run.Cycle + 1
 
 		for _, sid := range run.TurnIDs {
-			metrics, err := stats.ExtractFromRecording(s, sid)
+			metrics, err := stats.ExtractFromRecording(s, sid, globalCfg)
 			if err == nil {
 				ls.TotalCostUSD += metrics.TotalCostUSD
+				if metrics.CostEstimated {
+					ls.EstimatedCost += metrics.TotalCostUSD
+				}
 				ls.TotalDuration += metrics.DurationSecs
 			}
 		}
@@ -39,8 +39,8 @@ func runStatsProfile(cmd *cobra.Command, args []string) error {
 
 	printHeader(fmt.Sprintf("Profile: %s", name))
 	printField("Runs", fmt.Sprintf("%d (%d success, %d failure)", ps.TotalRuns, ps.SuccessCount, ps.FailureCount))
-	printField("Total Cost", fmt.Sprintf("$%.2f", ps.TotalCostUSD))
-	printField("Total Tokens", fmt.Sprintf("%s input, %s output", formatTokens(ps.TotalInputTok), formatTokens(ps.TotalOutputTok)))
+	printField("Total Cost", formatStatsCost(ps.TotalCostUSD, ps.EstimatedCost))
+	printField("Total Tokens", formatStatsTokens(ps.TotalInputTok, ps.TotalCachedTok, ps.TotalOutputTok, ps.TotalReasonTok))
 	printField("Total Duration", formatDuration(ps.TotalDuration))
 
 	if len(ps.ToolCalls) > 0 {
@@ -117,8 +117,8 @@ func runStatsProfileMarkdown(s *store.Store, name string) error {
 	if ps.TotalRuns > 0 {
 		fmt.Println("## Aggregate Statistics")
 		fmt.Printf("- Total Runs: %d (%d success, %d failure)\n", ps.TotalRuns, ps.SuccessCount, ps.FailureCount)
-		fmt.Printf("- Total Cost: $%.2f\n", ps.TotalCostUSD)
-		fmt.Printf("- Total Tokens: %s input, %s output\n", formatTokens(ps.TotalInputTok), formatTokens(ps.TotalOutputTok))
+		fmt.Printf("- Total Cost: %s\n", formatStatsCost(ps.TotalCostUSD, ps.EstimatedCost))
+		fmt.Printf("- Total Tokens: %s\n", formatStatsTokens(ps.TotalInputTok, ps.TotalCachedTok, ps.TotalOutputTok, ps.TotalReasonTok))
 		fmt.Printf("- Total Duration: %s\n", formatDuration(ps.TotalDuration))
 		fmt.Printf("- Average Cost/Run: $%.2f\n", ps.TotalCostUSD/float64(ps.TotalRuns))
 		successRate := float64(ps.SuccessCount) / float64(ps.TotalRuns) * 100
@@ -159,7 +159,7 @@ func runStatsProfileMarkdown(s *store.Store, name string) error {
 		}
 
 		for _, turn := range profileTurns[start:] {
-			metrics, _ := stats.ExtractFromRecording(s, turn.ID)
+			metrics, _ := stats.ExtractFromRecording(s, turn.ID, globalCfg)
 
 			outcome := "unknown"
 			if turn.BuildState == "success" {
@@ -186,10 +186,12 @@ func runStatsProfileMarkdown(s *store.Store, name string) error {
 			}
 
 			if metrics != nil {
-				fmt.Printf("Cost: $%.4f, Tokens: %s in / %s out\n",
-					metrics.TotalCostUSD,
-					formatTokens(metrics.InputTokens),
-					formatTokens(metrics.OutputTokens))
+				cost := fmt.Sprintf("$%.4f", metrics.TotalCostUSD)
+				if metrics.CostEstimated {
+					cost = "~" + cost
+				}
+				fmt.Printf("Cost: %s, Tokens: %s\n", cost,
+					formatStatsTokens(metrics.InputTokens, metrics.CachedInputTokens, metrics.OutputTokens, metrics.ReasoningTokens))
 
 				if len(metrics.ToolCalls) > 0 {
 					var parts []string
@@ -107,6 +107,9 @@ func printTree(s *store.Store, showAll bool) error {
 
 func printTreeNode(s *store.Store, r store.SpawnRecord, children map[int][]store.SpawnRecord, indent string) {
 	statusStr := coloredStatus(r.Status)
+	if r.Status == store.SpawnStatusQueued && r.QueuePosition > 0 {
+		statusStr += fmt.Sprintf(" #%d", r.QueuePosition)
+	}
 	elapsed := elapsedStr(r)
 	task := truncate(r.Task, 60)
 	label := r.ChildProfile
@@ -136,6 +139,8 @@ func printTreeNode(s *store.Store, r store.SpawnRecord, children map[int][]store
 
 func coloredStatus(status string) string {
 	switch status {
+	case "queued":
+		return colorDim + colorYellow + status + colorReset
 	case "running":
 		return colorYellow + status + colorReset
 	case "awaiting_input":
@@ -144,7 +149,7 @@ func coloredStatus(status string) string {
 		return colorGreen + status + colorReset
 	case "merged":
 		return colorDim + colorGreen + status + colorReset
-	case "failed":
+	case "failed", "failed_verification":
 		return colorRed + status + colorReset
 	case "canceled", "cancelled":
 		return colorDim + colorRed + status + colorReset
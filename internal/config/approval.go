@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ApprovalPolicy makes agents wait for a human before gated actions. On a
+// DelegationConfig it covers merges of spawns made under that delegation; on
+// a LoopDef it also covers steps and applies to every merge in the run.
+type ApprovalPolicy struct {
+	Merges         bool     `json:"merges,omitempty"`           // every spawn merge needs approval
+	MergeLineLimit int      `json:"merge_line_limit,omitempty"` // merges changing more lines than this need approval (0 = off)
+	Steps          []string `json:"steps,omitempty"`            // loop step IDs or profiles to pause before (LoopDef only)
+}
+
+// Clone returns a deep copy of p.
+func (p *ApprovalPolicy) Clone() *ApprovalPolicy {
+	if p == nil {
+		return nil
+	}
+	out := *p
+	out.Steps = append([]string(nil), p.Steps...)
+	return &out
+}
+
+// Validate rejects negative limits and blank step references.
+func (p *ApprovalPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.MergeLineLimit < 0 {
+		return fmt.Errorf("approval merge_line_limit must be >= 0")
+	}
+	for _, ref := range p.Steps {
+		if strings.TrimSpace(ref) == "" {
+			return fmt.Errorf("approval steps must not be empty")
+		}
+	}
+	return nil
+}
+
+// ValidateSteps checks that every gated step names a step of loop.
+func (p *ApprovalPolicy) ValidateSteps(loop *LoopDef) error {
+	if p == nil || loop == nil {
+		return nil
+	}
+	for _, ref := range p.Steps {
+		found := false
+		for _, step := range loop.Steps {
+			if approvalStepMatches(ref, step) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("approval step %q does not match any step id or profile of loop %q", ref, loop.Name)
+		}
+	}
+	return nil
+}
+
+// GatesMerges reports whether the policy can require approval for merges.
+func (p *ApprovalPolicy) GatesMerges() bool {
+	return p != nil && (p.Merges || p.MergeLineLimit > 0)
+}
+
+// MergeReason returns why a merge of changedLines needs approval, or "" when
+// it does not.
+func (p *ApprovalPolicy) MergeReason(changedLines int) string {
+	switch {
+	case p == nil:
+		return ""
+	case p.Merges:
+		return "all merges need approval"
+	case p.MergeLineLimit > 0 && changedLines > p.MergeLineLimit:
+		return fmt.Sprintf("%d changed lines exceeds the %d-line approval limit", changedLines, p.MergeLineLimit)
+	default:
+		return ""
+	}
+}
+
+// GatesStep reports whether step must be approved before it runs.
+func (p *ApprovalPolicy) GatesStep(step LoopStep) bool {
+	if p == nil {
+		return false
+	}
+	for _, ref := range p.Steps {
+		if approvalStepMatches(ref, step) {
+			return true
+		}
+	}
+	return false
+}
+
+func approvalStepMatches(ref string, step LoopStep) bool {
+	ref = strings.TrimSpace(ref)
+	if step.ID != "" && strings.EqualFold(ref, step.ID) {
+		return true
+	}
+	return step.Profile != "" && strings.EqualFold(ref, step.Profile)
+}
+
+// MergeApprovalPolicies combines policies, keeping the strictest settings.
+func MergeApprovalPolicies(policies ...*ApprovalPolicy) *ApprovalPolicy {
+	var out *ApprovalPolicy
+	for _, p := range policies {
+		if p == nil {
+			continue
+		}
+		if out == nil {
+			out = p.Clone()
+			continue
+		}
+		out.Merges = out.Merges || p.Merges
+		if p.MergeLineLimit > 0 && (out.MergeLineLimit == 0 || p.MergeLineLimit < out.MergeLineLimit) {
+			out.MergeLineLimit = p.MergeLineLimit
+		}
+		for _, ref := range p.Steps {
+			dup := false
+			for _, have := range out.Steps {
+				if strings.EqualFold(have, ref) {
+					dup = true
+					break
+				}
+			}
+			if !dup {
+				out.Steps = append(out.Steps, ref)
+			}
+		}
+	}
+	return out
+}
+
+// StepDelegation returns the delegation of a loop step's team with the
+// loop's merge approvals applied on top of the team's own. It is nil when
+// the step has no team or the team has no delegation.
+func (c *GlobalConfig) StepDelegation(loop *LoopDef, step LoopStep) *DelegationConfig {
+	if step.Team == "" {
+		return nil
+	}
+	team := c.FindTeam(step.Team)
+	if team == nil || team.Delegation == nil {
+		return nil
+	}
+	deleg := team.Delegation
+	if loop != nil && loop.Approval.GatesMerges() {
+		deleg = deleg.Clone()
+		deleg.Approval = MergeApprovalPolicies(deleg.Approval, loop.Approval)
+	}
+	return deleg
+}
@@ -0,0 +1,88 @@
+package config
+
+import "testing"
+
+func TestApprovalPolicyMergeReason(t *testing.T) {
+	var nilPolicy *ApprovalPolicy
+	if nilPolicy.GatesMerges() || nilPolicy.MergeReason(1000) != "" {
+		t.Fatal("nil policy must not gate merges")
+	}
+	limit := &ApprovalPolicy{MergeLineLimit: 300}
+	if !limit.GatesMerges() {
+		t.Fatal("line limit must gate merges")
+	}
+	if got := limit.MergeReason(300); got != "" {
+		t.Fatalf("MergeReason(300) = %q, want none", got)
+	}
+	if got := limit.MergeReason(301); got == "" {
+		t.Fatal("MergeReason(301) must require approval")
+	}
+	if got := (&ApprovalPolicy{Merges: true}).MergeReason(0); got == "" {
+		t.Fatal("merges=true must require approval for any merge")
+	}
+	if (&ApprovalPolicy{Steps: []string{"deploy"}}).GatesMerges() {
+		t.Fatal("step-only policy must not gate merges")
+	}
+}
+
+func TestApprovalPolicySteps(t *testing.T) {
+	loop := &LoopDef{Name: "ship", Steps: []LoopStep{
+		{ID: "build", Profile: "dev"},
+		{ID: "deploy", Profile: "ops"},
+	}}
+	p := &ApprovalPolicy{Steps: []string{"Deploy"}}
+	if err := p.ValidateSteps(loop); err != nil {
+		t.Fatalf("ValidateSteps: %v", err)
+	}
+	if p.GatesStep(loop.Steps[0]) || !p.GatesStep(loop.Steps[1]) {
+		t.Fatal("only the deploy step must be gated")
+	}
+	if !(&ApprovalPolicy{Steps: []string{"dev"}}).GatesStep(loop.Steps[0]) {
+		t.Fatal("steps must match by profile too")
+	}
+	if err := (&ApprovalPolicy{Steps: []string{"release"}}).ValidateSteps(loop); err == nil {
+		t.Fatal("ValidateSteps must reject unknown steps")
+	}
+	if err := (&ApprovalPolicy{MergeLineLimit: -1}).Validate(); err == nil {
+		t.Fatal("Validate must reject negative limits")
+	}
+}
+
+func TestMergeApprovalPolicies(t *testing.T) {
+	got := MergeApprovalPolicies(
+		nil,
+		&ApprovalPolicy{MergeLineLimit: 500, Steps: []string{"deploy"}},
+		&ApprovalPolicy{MergeLineLimit: 200, Steps: []string{"DEPLOY", "review"}},
+	)
+	if got.MergeLineLimit != 200 || got.Merges {
+		t.Fatalf("merged = %+v, want strictest line limit", got)
+	}
+	if len(got.Steps) != 2 {
+		t.Fatalf("Steps = %v, want deploy and review", got.Steps)
+	}
+	if MergeApprovalPolicies(nil, nil) != nil {
+		t.Fatal("merging nothing must return nil")
+	}
+}
+
+func TestStepDelegationAppliesLoopMergeApproval(t *testing.T) {
+	cfg := &GlobalConfig{Teams: []Team{{
+		Name:       "devs",
+		Delegation: &DelegationConfig{Approval: &ApprovalPolicy{MergeLineLimit: 500}},
+	}}}
+	loop := &LoopDef{
+		Approval: &ApprovalPolicy{Merges: true},
+		Steps:    []LoopStep{{Profile: "lead", Team: "devs"}, {Profile: "solo"}},
+	}
+
+	got := cfg.StepDelegation(loop, loop.Steps[0])
+	if got == nil || got.Approval == nil || !got.Approval.Merges || got.Approval.MergeLineLimit != 500 {
+		t.Fatalf("StepDelegation() = %+v, want team limit plus loop merges", got)
+	}
+	if cfg.Teams[0].Delegation.Approval.Merges {
+		t.Fatal("StepDelegation() modified the team's delegation")
+	}
+	if cfg.StepDelegation(loop, loop.Steps[1]) != nil {
+		t.Fatal("step without a team must have no delegation")
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import "fmt"
+
+// DefaultBudgetSoftPercent is the share of a hard limit at which agents are
+// asked to wind down when a Budget does not set SoftPercent.
+const DefaultBudgetSoftPercent = 80
+
+// Budget caps agent spend for a loop run, a loop step, or a spawned
+// sub-agent and everything it spawns. Tokens count input plus output tokens
+// as reported by the agent's stream. A zero limit means unlimited.
+type Budget struct {
+	MaxCostUSD  float64 `json:"max_cost_usd,omitempty"` // hard limit in USD
+	MaxTokens   int     `json:"max_tokens,omitempty"`   // hard limit in tokens
+	SoftPercent int     `json:"soft_percent,omitempty"` // wind-down threshold in % of the hard limit (0 = 80)
+}
+
+// IsZero reports whether b sets no limit.
+func (b *Budget) IsZero() bool {
+	return b == nil || (b.MaxCostUSD <= 0 && b.MaxTokens <= 0)
+}
+
+// Clone returns a copy of b.
+func (b *Budget) Clone() *Budget {
+	if b == nil {
+		return nil
+	}
+	out := *b
+	return &out
+}
+
+// Validate rejects negative limits and out-of-range soft thresholds.
+func (b *Budget) Validate() error {
+	if b == nil {
+		return nil
+	}
+	if b.MaxCostUSD < 0 {
+		return fmt.Errorf("budget max_cost_usd must be >= 0")
+	}
+	if b.MaxTokens < 0 {
+		return fmt.Errorf("budget max_tokens must be >= 0")
+	}
+	if b.SoftPercent < 0 || b.SoftPercent > 100 {
+		return fmt.Errorf("budget soft_percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// EffectiveSoftPercent returns SoftPercent, defaulting to DefaultBudgetSoftPercent.
+func (b *Budget) EffectiveSoftPercent() int {
+	if b == nil || b.SoftPercent <= 0 {
+		return DefaultBudgetSoftPercent
+	}
+	return b.SoftPercent
+}
+
+// Exceeded reports whether the given spend reaches a hard limit.
+func (b *Budget) Exceeded(costUSD float64, tokens int) bool {
+	if b == nil {
+		return false
+	}
+	return (b.MaxCostUSD > 0 && costUSD >= b.MaxCostUSD) ||
+		(b.MaxTokens > 0 && tokens >= b.MaxTokens)
+}
+
+// SoftExceeded reports whether the given spend reaches the wind-down threshold.
+func (b *Budget) SoftExceeded(costUSD float64, tokens int) bool {
+	if b == nil {
+		return false
+	}
+	frac := float64(b.EffectiveSoftPercent()) / 100
+	return (b.MaxCostUSD > 0 && costUSD >= b.MaxCostUSD*frac) ||
+		(b.MaxTokens > 0 && float64(tokens) >= float64(b.MaxTokens)*frac)
+}
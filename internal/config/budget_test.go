@@ -0,0 +1,47 @@
+package config
+
+import "testing"
+
+func TestBudgetValidate(t *testing.T) {
+	tests := []struct {
+		budget  *Budget
+		wantErr bool
+	}{
+		{budget: nil},
+		{budget: &Budget{MaxCostUSD: 5, MaxTokens: 1000, SoftPercent: 90}},
+		{budget: &Budget{MaxCostUSD: -1}, wantErr: true},
+		{budget: &Budget{MaxTokens: -1}, wantErr: true},
+		{budget: &Budget{MaxCostUSD: 1, SoftPercent: 101}, wantErr: true},
+	}
+	for i, tt := range tests {
+		if err := tt.budget.Validate(); (err != nil) != tt.wantErr {
+			t.Fatalf("case %d: Validate() error = %v, wantErr %v", i, err, tt.wantErr)
+		}
+	}
+}
+
+func TestBudgetThresholds(t *testing.T) {
+	b := &Budget{MaxCostUSD: 10, MaxTokens: 1000}
+	if b.SoftExceeded(7.99, 799) {
+		t.Fatal("SoftExceeded below 80% = true, want false")
+	}
+	if !b.SoftExceeded(8, 0) || !b.SoftExceeded(0, 800) {
+		t.Fatal("SoftExceeded at 80% = false, want true")
+	}
+	if b.Exceeded(9.99, 999) {
+		t.Fatal("Exceeded below limit = true, want false")
+	}
+	if !b.Exceeded(10, 0) || !b.Exceeded(0, 1000) {
+		t.Fatal("Exceeded at limit = false, want true")
+	}
+
+	custom := &Budget{MaxTokens: 100, SoftPercent: 50}
+	if !custom.SoftExceeded(0, 50) {
+		t.Fatal("SoftExceeded with soft_percent=50 at 50 tokens = false, want true")
+	}
+
+	var unlimited *Budget
+	if unlimited.Exceeded(1e9, 1e9) || unlimited.SoftExceeded(1e9, 1e9) || !unlimited.IsZero() {
+		t.Fatal("nil budget should never be exceeded")
+	}
+}
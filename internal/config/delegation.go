@@ -18,6 +18,8 @@ type DelegationProfile struct {
 	Handoff        bool              `json:"handoff,omitempty"`         // can be transferred to next loop step
 	Delegation     *DelegationConfig `json:"delegation,omitempty"`      // child spawn rules for this option
 	Skills         []string          `json:"skills,omitempty"`          // skill IDs for spawned agents
+	Budget         *Budget           `json:"budget,omitempty"`          // spend limit for each spawn and its sub-tree
+	Sandbox        *Sandbox          `json:"sandbox,omitempty"`         // overrides the profile's sandbox for spawns
 }
 
 // DelegationConfig describes spawn capabilities for a loop step or session.
@@ -26,6 +28,7 @@ type DelegationConfig struct {
 	MaxParallel int                 `json:"max_parallel,omitempty"` // total concurrent spawns (0 = default 4)
 	Style       string              `json:"style,omitempty"`        // free-form delegation style guidance
 	StylePreset string              `json:"style_preset,omitempty"` // preset name (overrides style if set)
+	Approval    *ApprovalPolicy     `json:"approval,omitempty"`     // human sign-off before merging spawns
 }
 
 // Style preset constants.
@@ -75,6 +78,7 @@ func (d *DelegationConfig) Clone() *DelegationConfig {
 		MaxParallel: d.MaxParallel,
 		Style:       d.Style,
 		StylePreset: d.StylePreset,
+		Approval:    d.Approval.Clone(),
 	}
 	if len(d.Profiles) > 0 {
 		out.Profiles = make([]DelegationProfile, len(d.Profiles))
@@ -87,6 +91,8 @@ func (d *DelegationConfig) Clone() *DelegationConfig {
 				p.Skills = append([]string(nil), p.Skills...)
 			}
 			p.Delegation = p.Delegation.Clone()
+			p.Budget = p.Budget.Clone()
+			p.Sandbox = p.Sandbox.Clone()
 			out.Profiles[i] = p
 		}
 	}
@@ -23,10 +23,14 @@ type Profile struct {
 	MaxInstances int    `json:"max_instances,omitempty"` // max concurrent instances of this profile (0 = unlimited)
 	Speed        string `json:"speed,omitempty"`         // "fast", "medium", "slow" — informational speed rating
 	Cost         string `json:"cost,omitempty"`          // "free", "cheap", "normal", "expensive" — manual operator input
+
+	Sandbox  *Sandbox `json:"sandbox,omitempty"`  // Linux process sandbox (nil = unconfined)
+	Fallback []string `json:"fallback,omitempty"` // profiles to fail over to, in order, on quota/auth/crash failures
 }
 
 // LoopStep defines one step in a loop cycle.
 type LoopStep struct {
+	ID             string   `json:"id,omitempty"`              // optional step name used as a transition target
 	Profile        string   `json:"profile"`                   // profile name reference
 	Position       string   `json:"position,omitempty"`        // built-in execution level: supervisor|manager|lead|worker
 	Role           string   `json:"role,omitempty"`            // role name from global roles catalog
@@ -40,13 +44,20 @@ type LoopStep struct {
 	StandaloneChat bool     `json:"standalone_chat,omitempty"` // interactive chat mode (minimal prompt)
 	Skills         []string `json:"skills,omitempty"`          // skill IDs to activate for this step
 	SkillsExplicit bool     `json:"skills_explicit,omitempty"` // when true, use Skills exactly; empty means no skills
+	Budget         *Budget  `json:"budget,omitempty"`          // spend limit for each execution of this step
+
+	Transitions []StepTransition `json:"transitions,omitempty"` // conditional routing after this step (first match wins)
+	Parallel    []LoopStep       `json:"parallel,omitempty"`    // step group: members run concurrently, each in its own worktree
 }
 
 // LoopDef defines a loop as a cyclic template of profile steps.
 type LoopDef struct {
-	Name             string     `json:"name"`
-	Steps            []LoopStep `json:"steps"`
-	ResourcePriority string     `json:"resource_priority,omitempty"` // quality|normal|cost (runtime delegation preference)
+	Name             string          `json:"name"`
+	Steps            []LoopStep      `json:"steps"`
+	ResourcePriority string          `json:"resource_priority,omitempty"` // quality|normal|cost (runtime delegation preference)
+	Budget           *Budget         `json:"budget,omitempty"`            // spend limit for the whole run, spawns included
+	UsageGate        *UsageGate      `json:"usage_gate,omitempty"`        // pause or reroute when provider usage limits run low
+	Approval         *ApprovalPolicy `json:"approval,omitempty"`          // human sign-off before merges and gated steps
 }
 
 // PushoverConfig holds Pushover notification credentials.
@@ -80,17 +91,21 @@ type RecentProject struct {
 
 // GlobalConfig holds user-level preferences stored in ~/.adaf/config.json.
 type GlobalConfig struct {
-	Agents             map[string]GlobalAgentConfig `json:"agents,omitempty"`
-	Profiles           []Profile                    `json:"profiles,omitempty"`
-	Loops              []LoopDef                    `json:"loops,omitempty"`
-	Teams              []Team                       `json:"teams,omitempty"`
-	RecentCombinations []RecentCombination          `json:"recent_combinations,omitempty"`
-	RecentProjects     []RecentProject              `json:"recent_projects,omitempty"`
-	Pushover           PushoverConfig               `json:"pushover,omitempty"`
-	PromptRules        []PromptRule                 `json:"prompt_rules,omitempty"`
-	Roles              []RoleDefinition             `json:"roles,omitempty"`
-	DefaultRole        string                       `json:"default_role,omitempty"`
-	Skills             []Skill                      `json:"skills,omitempty"`
+	Agents               map[string]GlobalAgentConfig `json:"agents,omitempty"`
+	Profiles             []Profile                    `json:"profiles,omitempty"`
+	Loops                []LoopDef                    `json:"loops,omitempty"`
+	Teams                []Team                       `json:"teams,omitempty"`
+	RecentCombinations   []RecentCombination          `json:"recent_combinations,omitempty"`
+	RecentProjects       []RecentProject              `json:"recent_projects,omitempty"`
+	Pushover             PushoverConfig               `json:"pushover,omitempty"`
+	Notify               NotifyConfig                 `json:"notify,omitempty"`
+	AskEscalationMinutes int                          `json:"ask_escalation_minutes,omitempty"` // unanswered parent-asks move up one level per interval (0 = never)
+	PromptRules          []PromptRule                 `json:"prompt_rules,omitempty"`
+	Roles                []RoleDefinition             `json:"roles,omitempty"`
+	DefaultRole          string                       `json:"default_role,omitempty"`
+	Skills               []Skill                      `json:"skills,omitempty"`
+	RemoteHosts          []RemoteHost                 `json:"remote_hosts,omitempty"`
+	ModelPrices          []ModelPrice                 `json:"model_prices,omitempty"` // per-million-token prices for agents that report no USD cost
 }
 
 // GlobalAgentConfig holds per-agent overrides at the global (user) level.
@@ -190,6 +205,28 @@ func (c *GlobalConfig) FindProfile(name string) *Profile {
 	return nil
 }
 
+// FallbackProfiles resolves the fallback chain of the named profile,
+// skipping unknown names, the profile itself and repeats.
+func (c *GlobalConfig) FallbackProfiles(name string) []Profile {
+	prof := c.FindProfile(name)
+	if prof == nil {
+		return nil
+	}
+	seen := map[string]bool{strings.ToLower(prof.Name): true}
+	var out []Profile
+	for _, fb := range prof.Fallback {
+		key := strings.ToLower(strings.TrimSpace(fb))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if p := c.FindProfile(key); p != nil {
+			out = append(out, *p)
+		}
+	}
+	return out
+}
+
 // AddLoop appends a loop definition. Returns an error if the name already exists.
 func (c *GlobalConfig) AddLoop(l LoopDef) error {
 	for _, existing := range c.Loops {
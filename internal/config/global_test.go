@@ -0,0 +1,21 @@
+package config
+
+import "testing"
+
+func TestFallbackProfiles(t *testing.T) {
+	cfg := &GlobalConfig{Profiles: []Profile{
+		{Name: "primary", Agent: "claude", Fallback: []string{"Backup", "primary", "missing", "backup", "last"}},
+		{Name: "backup", Agent: "codex"},
+		{Name: "last", Agent: "gemini"},
+	}}
+	got := cfg.FallbackProfiles("primary")
+	if len(got) != 2 || got[0].Name != "backup" || got[1].Name != "last" {
+		t.Fatalf("FallbackProfiles() = %+v, want [backup last]", got)
+	}
+	if got := cfg.FallbackProfiles("backup"); len(got) != 0 {
+		t.Fatalf("FallbackProfiles(backup) = %+v, want none", got)
+	}
+	if got := cfg.FallbackProfiles("unknown"); got != nil {
+		t.Fatalf("FallbackProfiles(unknown) = %+v, want nil", got)
+	}
+}
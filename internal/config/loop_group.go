@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IsGroup reports whether the step is a parallel step group.
+func (s LoopStep) IsGroup() bool {
+	return len(s.Parallel) > 0
+}
+
+// Members returns the steps that execute for s: the group members for a
+// parallel group, or s itself.
+func (s LoopStep) Members() []LoopStep {
+	if s.IsGroup() {
+		return s.Parallel
+	}
+	return []LoopStep{s}
+}
+
+// Label returns a display name for the step: its profile, or the member
+// profiles of a parallel group.
+func (s LoopStep) Label() string {
+	if !s.IsGroup() {
+		return s.Profile
+	}
+	names := make([]string, 0, len(s.Parallel))
+	for _, m := range s.Parallel {
+		names = append(names, m.Profile)
+	}
+	return "parallel[" + strings.Join(names, ", ") + "]"
+}
+
+// ValidateLoopStepGroup validates a parallel step group. Members run
+// concurrently in separate worktrees, so they are limited to lead steps
+// without teams: loop control, delegation and routing stay with the group.
+func ValidateLoopStepGroup(step LoopStep) error {
+	if !step.IsGroup() {
+		return nil
+	}
+	if strings.TrimSpace(step.Profile) != "" {
+		return fmt.Errorf("parallel group cannot also set profile %q", step.Profile)
+	}
+	if strings.TrimSpace(step.Team) != "" || step.StandaloneChat || strings.TrimSpace(step.ManualPrompt) != "" {
+		return fmt.Errorf("parallel group cannot set team, standalone_chat or manual_prompt; set them on members")
+	}
+	for i, m := range step.Parallel {
+		if strings.TrimSpace(m.Profile) == "" {
+			return fmt.Errorf("parallel member %d must have a profile", i+1)
+		}
+		if m.IsGroup() {
+			return fmt.Errorf("parallel member %d (%s) cannot be a nested group", i+1, m.Profile)
+		}
+		if pos := EffectiveStepPosition(m); pos != PositionLead {
+			return fmt.Errorf("parallel member %d (%s) uses position %q; members must be leads", i+1, m.Profile, pos)
+		}
+		if strings.TrimSpace(m.Team) != "" {
+			return fmt.Errorf("parallel member %d (%s) cannot have a team", i+1, m.Profile)
+		}
+		if m.StandaloneChat {
+			return fmt.Errorf("parallel member %d (%s) cannot be a standalone chat", i+1, m.Profile)
+		}
+		if len(m.Transitions) > 0 {
+			return fmt.Errorf("parallel member %d (%s) cannot have transitions; set them on the group", i+1, m.Profile)
+		}
+		if err := m.Budget.Validate(); err != nil {
+			return fmt.Errorf("parallel member %d (%s): %w", i+1, m.Profile, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package config
+
+import "testing"
+
+func TestValidateLoopStepGroup(t *testing.T) {
+	valid := LoopStep{Parallel: []LoopStep{{Profile: "a"}, {Profile: "b", Position: PositionLead}}}
+	if err := ValidateLoopStepGroup(valid); err != nil {
+		t.Fatalf("ValidateLoopStepGroup(valid) error = %v", err)
+	}
+	if got := valid.Label(); got != "parallel[a, b]" {
+		t.Fatalf("Label() = %q, want %q", got, "parallel[a, b]")
+	}
+
+	invalid := []LoopStep{
+		{Profile: "x", Parallel: []LoopStep{{Profile: "a"}}},
+		{Team: "t", Parallel: []LoopStep{{Profile: "a"}}},
+		{Parallel: []LoopStep{{}}},
+		{Parallel: []LoopStep{{Profile: "a", Parallel: []LoopStep{{Profile: "b"}}}}},
+		{Parallel: []LoopStep{{Profile: "a", Position: PositionSupervisor}}},
+		{Parallel: []LoopStep{{Profile: "a", Team: "t"}}},
+		{Parallel: []LoopStep{{Profile: "a", Transitions: []StepTransition{{Goto: "stop"}}}}},
+	}
+	for i, step := range invalid {
+		if err := ValidateLoopStepGroup(step); err == nil {
+			t.Fatalf("case %d: ValidateLoopStepGroup() error = nil, want error", i)
+		}
+	}
+}
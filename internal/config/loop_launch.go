@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrepareLoopLaunch validates loopDef for a new loop session and snapshots
+// every profile it needs: the step profiles plus their teams' delegation
+// trees, so the daemon can resolve nested spawns. `adaf loop start` and the
+// scheduler both launch loops through it.
+func (c *GlobalConfig) PrepareLoopLaunch(loopDef *LoopDef) ([]Profile, error) {
+	if len(loopDef.Steps) == 0 {
+		return nil, fmt.Errorf("loop %q has no steps", loopDef.Name)
+	}
+	if err := loopDef.Budget.Validate(); err != nil {
+		return nil, fmt.Errorf("loop %q: %w", loopDef.Name, err)
+	}
+	if err := loopDef.UsageGate.Validate(); err != nil {
+		return nil, fmt.Errorf("loop %q: %w", loopDef.Name, err)
+	}
+	if err := loopDef.Approval.Validate(); err != nil {
+		return nil, fmt.Errorf("loop %q: %w", loopDef.Name, err)
+	}
+	if err := loopDef.Approval.ValidateSteps(loopDef); err != nil {
+		return nil, err
+	}
+	for i, step := range loopDef.Steps {
+		if err := ValidateLoopStepPosition(step, c); err != nil {
+			return nil, fmt.Errorf("loop %q step %d invalid: %w", loopDef.Name, i, err)
+		}
+		if err := step.Budget.Validate(); err != nil {
+			return nil, fmt.Errorf("loop %q step %d invalid: %w", loopDef.Name, i, err)
+		}
+	}
+	if err := ValidateLoopTransitions(loopDef); err != nil {
+		return nil, fmt.Errorf("loop %q: %w", loopDef.Name, err)
+	}
+	return c.loopProfilesSnapshot(loopDef)
+}
+
+func (c *GlobalConfig) loopProfilesSnapshot(loopDef *LoopDef) ([]Profile, error) {
+	seen := make(map[string]struct{}, len(loopDef.Steps))
+	profiles := make([]Profile, 0, len(loopDef.Steps))
+
+	addProfile := func(name string) error {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil
+		}
+		key := strings.ToLower(name)
+		if _, ok := seen[key]; ok {
+			return nil
+		}
+		prof := c.FindProfile(name)
+		if prof == nil {
+			return fmt.Errorf("profile %q not found for loop %q", name, loopDef.Name)
+		}
+		seen[key] = struct{}{}
+		profiles = append(profiles, *prof)
+		return nil
+	}
+
+	for _, group := range loopDef.Steps {
+		for _, step := range group.Members() {
+			if strings.TrimSpace(step.Profile) == "" {
+				return nil, fmt.Errorf("loop %q has a step with empty profile", loopDef.Name)
+			}
+			if err := addProfile(step.Profile); err != nil {
+				return nil, err
+			}
+			// Include all profiles from the team's delegation tree so the daemon has
+			// everything needed for nested spawn resolution and prompt rendering.
+			if step.Team != "" {
+				if t := c.FindTeam(step.Team); t != nil && t.Delegation != nil {
+					for _, name := range CollectDelegationProfileNames(t.Delegation) {
+						if err := addProfile(name); err != nil {
+							return nil, err
+						}
+					}
+				}
+			}
+		}
+	}
+	return profiles, nil
+}
@@ -0,0 +1,107 @@
+package config
+
+import "testing"
+
+func TestLoopProfilesSnapshot_CollectsTeamDelegationProfiles(t *testing.T) {
+	globalCfg := &GlobalConfig{
+		Profiles: []Profile{
+			{Name: "manager", Agent: "codex"},
+			{Name: "developer", Agent: "codex"},
+			{Name: "scout", Agent: "codex"},
+		},
+		Teams: []Team{
+			{
+				Name: "dev-team",
+				Delegation: &DelegationConfig{
+					Profiles: []DelegationProfile{
+						{Name: "developer", Role: RoleDeveloper},
+						{Name: "scout", Role: RoleScout},
+					},
+				},
+			},
+		},
+	}
+	loopDef := &LoopDef{
+		Name: "nested",
+		Steps: []LoopStep{
+			{
+				Profile: "manager",
+				Team:    "dev-team",
+			},
+		},
+	}
+
+	profiles, err := globalCfg.loopProfilesSnapshot(loopDef)
+	if err != nil {
+		t.Fatalf("loopProfilesSnapshot() error = %v", err)
+	}
+
+	got := make(map[string]struct{}, len(profiles))
+	for _, p := range profiles {
+		got[p.Name] = struct{}{}
+	}
+	for _, name := range []string{"manager", "developer", "scout"} {
+		if _, ok := got[name]; !ok {
+			t.Fatalf("profile %q missing from snapshot", name)
+		}
+	}
+}
+
+func TestLoopProfilesSnapshot_ErrorsOnMissingTeamProfile(t *testing.T) {
+	globalCfg := &GlobalConfig{
+		Profiles: []Profile{
+			{Name: "manager", Agent: "codex"},
+		},
+		Teams: []Team{
+			{
+				Name: "bad-team",
+				Delegation: &DelegationConfig{
+					Profiles: []DelegationProfile{
+						{Name: "missing"},
+					},
+				},
+			},
+		},
+	}
+	loopDef := &LoopDef{
+		Name: "nested",
+		Steps: []LoopStep{
+			{
+				Profile: "manager",
+				Team:    "bad-team",
+			},
+		},
+	}
+
+	if _, err := globalCfg.loopProfilesSnapshot(loopDef); err == nil {
+		t.Fatalf("loopProfilesSnapshot() error = nil, want missing profile error")
+	}
+}
+
+func TestPrepareLoopLaunch_ValidatesLoopSettings(t *testing.T) {
+	globalCfg := &GlobalConfig{Profiles: []Profile{{Name: "dev", Agent: "codex"}}}
+	valid := LoopDef{Name: "ok", Steps: []LoopStep{{Profile: "dev", Turns: 1}}}
+	profiles, err := globalCfg.PrepareLoopLaunch(&valid)
+	if err != nil {
+		t.Fatalf("PrepareLoopLaunch(valid) error = %v", err)
+	}
+	if len(profiles) != 1 || profiles[0].Name != "dev" {
+		t.Fatalf("profiles = %+v, want dev", profiles)
+	}
+
+	for name, mutate := range map[string]func(l *LoopDef){
+		"no steps":        func(l *LoopDef) { l.Steps = nil },
+		"loop budget":     func(l *LoopDef) { l.Budget = &Budget{MaxCostUSD: -1} },
+		"step budget":     func(l *LoopDef) { l.Steps[0].Budget = &Budget{MaxTokens: -1} },
+		"usage gate":      func(l *LoopDef) { l.UsageGate = &UsageGate{Action: "explode"} },
+		"missing profile": func(l *LoopDef) { l.Steps[0].Profile = "ghost" },
+		"approval steps":  func(l *LoopDef) { l.Approval = &ApprovalPolicy{Steps: []string{"reviewer"}} },
+	} {
+		l := valid
+		l.Steps = append([]LoopStep(nil), valid.Steps...)
+		mutate(&l)
+		if _, err := globalCfg.PrepareLoopLaunch(&l); err == nil {
+			t.Fatalf("PrepareLoopLaunch(%s) error = nil, want error", name)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ModelPrice is the USD price of a model's tokens, per million tokens. It is
+// used to cost turns whose agent does not report a dollar amount.
+type ModelPrice struct {
+	Model       string  `json:"model"`                  // model name, or a name prefix ending in "*"
+	Input       float64 `json:"input"`                  // uncached input tokens (including cache writes)
+	CachedInput float64 `json:"cached_input,omitempty"` // cache-read input tokens
+	Output      float64 `json:"output"`                 // output tokens, including reasoning
+}
+
+// Matches reports whether the entry prices model (case-insensitive).
+func (p ModelPrice) Matches(model string) bool {
+	pattern := strings.ToLower(strings.TrimSpace(p.Model))
+	model = strings.ToLower(strings.TrimSpace(model))
+	if pattern == "" || model == "" {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(model, prefix)
+	}
+	return pattern == model
+}
+
+// CostUSD prices a token count. Output tokens include reasoning tokens.
+func (p ModelPrice) CostUSD(input, cachedInput, output int) float64 {
+	return (float64(input)*p.Input + float64(cachedInput)*p.CachedInput + float64(output)*p.Output) / 1e6
+}
+
+// Validate rejects entries without a model or with negative prices.
+func (p ModelPrice) Validate() error {
+	if strings.TrimSpace(p.Model) == "" {
+		return fmt.Errorf("model price needs a model")
+	}
+	if p.Input < 0 || p.CachedInput < 0 || p.Output < 0 {
+		return fmt.Errorf("model price %q: prices must be >= 0", p.Model)
+	}
+	return nil
+}
+
+// SetModelPrice adds p to the catalog, replacing an entry for the same
+// model pattern (case-insensitive).
+func (c *GlobalConfig) SetModelPrice(p ModelPrice) {
+	p.Model = strings.TrimSpace(p.Model)
+	for i := range c.ModelPrices {
+		if strings.EqualFold(strings.TrimSpace(c.ModelPrices[i].Model), p.Model) {
+			c.ModelPrices[i] = p
+			return
+		}
+	}
+	c.ModelPrices = append(c.ModelPrices, p)
+}
+
+// RemoveModelPrice removes the entry for a model pattern (case-insensitive)
+// and reports whether one existed.
+func (c *GlobalConfig) RemoveModelPrice(model string) bool {
+	model = strings.TrimSpace(model)
+	out := c.ModelPrices[:0]
+	removed := false
+	for _, p := range c.ModelPrices {
+		if strings.EqualFold(strings.TrimSpace(p.Model), model) {
+			removed = true
+			continue
+		}
+		out = append(out, p)
+	}
+	c.ModelPrices = out
+	return removed
+}
+
+// FindModelPrice returns the price entry for model, or nil. An exact entry
+// wins over prefix entries, and the longest matching prefix wins otherwise.
+func (c *GlobalConfig) FindModelPrice(model string) *ModelPrice {
+	if c == nil {
+		return nil
+	}
+	var best *ModelPrice
+	for i := range c.ModelPrices {
+		p := &c.ModelPrices[i]
+		if !p.Matches(model) {
+			continue
+		}
+		if !strings.HasSuffix(strings.TrimSpace(p.Model), "*") {
+			return p
+		}
+		if best == nil || len(strings.TrimSpace(p.Model)) > len(strings.TrimSpace(best.Model)) {
+			best = p
+		}
+	}
+	return best
+}
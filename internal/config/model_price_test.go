@@ -0,0 +1,52 @@
+package config
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFindModelPrice(t *testing.T) {
+	cfg := &GlobalConfig{ModelPrices: []ModelPrice{
+		{Model: "gpt-*", Input: 1},
+		{Model: "gpt-5-codex*", Input: 2},
+		{Model: "GPT-5", Input: 3},
+	}}
+	for model, want := range map[string]float64{
+		"gpt-5":            3,
+		"gpt-5-codex-mini": 2,
+		"gpt-4.1":          1,
+		"gemini-2.5-pro":   0,
+		"":                 0,
+	} {
+		got := 0.0
+		if p := cfg.FindModelPrice(model); p != nil {
+			got = p.Input
+		}
+		if got != want {
+			t.Fatalf("FindModelPrice(%q) input = %v, want %v", model, got, want)
+		}
+	}
+}
+
+func TestModelPriceCostUSD(t *testing.T) {
+	p := ModelPrice{Model: "m", Input: 2, CachedInput: 0.5, Output: 10}
+	got := p.CostUSD(1_000_000, 2_000_000, 100_000)
+	if math.Abs(got-4) > 1e-9 {
+		t.Fatalf("CostUSD() = %v, want 4", got)
+	}
+}
+
+func TestSetAndRemoveModelPrice(t *testing.T) {
+	cfg := &GlobalConfig{}
+	cfg.SetModelPrice(ModelPrice{Model: "gpt-5*", Input: 1, Output: 8})
+	cfg.SetModelPrice(ModelPrice{Model: " GPT-5* ", Input: 1.25, Output: 10})
+	if len(cfg.ModelPrices) != 1 || cfg.ModelPrices[0].Input != 1.25 {
+		t.Fatalf("ModelPrices after replace = %+v", cfg.ModelPrices)
+	}
+	if err := (ModelPrice{Model: "x", Output: -1}).Validate(); err == nil {
+		t.Fatal("Validate() accepted a negative price")
+	}
+	if cfg.RemoveModelPrice("gemini") || !cfg.RemoveModelPrice("gpt-5*") || len(cfg.ModelPrices) != 0 {
+		t.Fatalf("RemoveModelPrice left %+v", cfg.ModelPrices)
+	}
+}
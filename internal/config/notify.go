@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+// Notification event kinds a sink can subscribe to.
+const (
+	NotifyLoopDone       = "loop_done"       // a loop run ended (stopped, cancelled, failed or over budget)
+	NotifyLoopMessage    = "loop_notify"     // a loop step ran `adaf loop notify`
+	NotifySpawnFailed    = "spawn_failed"    // a spawn finished failed or failed verification
+	NotifyBudgetExceeded = "budget_exceeded" // a loop run or spawn hit a hard budget limit
+	NotifyParentAsk      = "parent_ask"      // a child is blocked on `adaf parent-ask`
+	NotifyIssueInReview  = "issue_in_review" // an issue moved to in_review
+	NotifyApprovalNeeded = "approval_needed" // a merge or loop step waits for human approval
+)
+
+// NotifyEvents lists every event kind in display order.
+var NotifyEvents = []string{
+	NotifyLoopDone,
+	NotifyLoopMessage,
+	NotifySpawnFailed,
+	NotifyBudgetExceeded,
+	NotifyParentAsk,
+	NotifyIssueInReview,
+	NotifyApprovalNeeded,
+}
+
+// Notification sink types.
+const (
+	NotifySinkWebhook  = "webhook"  // JSON POST, optionally HMAC-signed
+	NotifySinkSlack    = "slack"    // Slack-compatible incoming webhook
+	NotifySinkDiscord  = "discord"  // Discord incoming webhook
+	NotifySinkEmail    = "email"    // SMTP email
+	NotifySinkCommand  = "command"  // local shell command
+	NotifySinkPushover = "pushover" // Pushover, using the pushover credentials
+)
+
+// NotifyConfig routes lifecycle events to notification sinks.
+type NotifyConfig struct {
+	Sinks []NotifySink `json:"sinks,omitempty"`
+}
+
+// NotifySink is one notification destination and the events it receives.
+// Only the fields of its Type are used.
+type NotifySink struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Events []string `json:"events,omitempty"` // subscribed event kinds (empty = all)
+
+	// webhook, slack, discord
+	URL     string            `json:"url,omitempty"`
+	Secret  string            `json:"secret,omitempty"`  // webhook HMAC-SHA256 signing key
+	Headers map[string]string `json:"headers,omitempty"` // extra webhook request headers
+
+	// email
+	SMTPHost string   `json:"smtp_host,omitempty"`
+	SMTPPort int      `json:"smtp_port,omitempty"` // default 587
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       []string `json:"to,omitempty"`
+
+	// command: run with `sh -c`, event JSON on stdin
+	Command string `json:"command,omitempty"`
+}
+
+// ValidNotifyEvent reports whether kind is a known event kind.
+func ValidNotifyEvent(kind string) bool {
+	for _, k := range NotifyEvents {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscribes reports whether the sink receives events of kind.
+func (s NotifySink) Subscribes(kind string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, k := range s.Events {
+		if k == kind || k == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the sink's type-specific settings.
+func (s NotifySink) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("notify sink name is required")
+	}
+	for _, k := range s.Events {
+		if k != "*" && !ValidNotifyEvent(k) {
+			return fmt.Errorf("notify sink %q: unknown event %q (valid: %s)", s.Name, k, strings.Join(NotifyEvents, ", "))
+		}
+	}
+	switch s.Type {
+	case NotifySinkWebhook, NotifySinkSlack, NotifySinkDiscord:
+		u, err := url.Parse(s.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("notify sink %q: url must be an http(s) URL", s.Name)
+		}
+	case NotifySinkEmail:
+		if strings.TrimSpace(s.SMTPHost) == "" {
+			return fmt.Errorf("notify sink %q: smtp_host is required", s.Name)
+		}
+		if s.SMTPPort < 0 || s.SMTPPort > 65535 {
+			return fmt.Errorf("notify sink %q: invalid smtp_port %d", s.Name, s.SMTPPort)
+		}
+		if _, err := mail.ParseAddress(s.From); err != nil {
+			return fmt.Errorf("notify sink %q: invalid from address %q", s.Name, s.From)
+		}
+		if len(s.To) == 0 {
+			return fmt.Errorf("notify sink %q: at least one to address is required", s.Name)
+		}
+		for _, to := range s.To {
+			if _, err := mail.ParseAddress(to); err != nil {
+				return fmt.Errorf("notify sink %q: invalid to address %q", s.Name, to)
+			}
+		}
+	case NotifySinkCommand:
+		if strings.TrimSpace(s.Command) == "" {
+			return fmt.Errorf("notify sink %q: command is required", s.Name)
+		}
+	case NotifySinkPushover:
+	default:
+		return fmt.Errorf("notify sink %q: unknown type %q", s.Name, s.Type)
+	}
+	return nil
+}
+
+// Validate checks every sink and rejects duplicate names.
+func (c *NotifyConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(c.Sinks))
+	for _, s := range c.Sinks {
+		if err := s.Validate(); err != nil {
+			return err
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("duplicate notify sink %q", s.Name)
+		}
+		seen[s.Name] = true
+	}
+	return nil
+}
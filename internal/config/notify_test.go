@@ -0,0 +1,43 @@
+package config
+
+import "testing"
+
+func TestNotifyConfigValidate(t *testing.T) {
+	tests := []struct {
+		sinks   []NotifySink
+		wantErr bool
+	}{
+		{sinks: nil},
+		{sinks: []NotifySink{{Name: "hook", Type: NotifySinkWebhook, URL: "https://example.com/hook", Events: []string{NotifySpawnFailed}}}},
+		{sinks: []NotifySink{{Name: "slack", Type: NotifySinkSlack, URL: "http://localhost:9000"}}},
+		{sinks: []NotifySink{{Name: "mail", Type: NotifySinkEmail, SMTPHost: "smtp.example.com", From: "a@example.com", To: []string{"b@example.com"}}}},
+		{sinks: []NotifySink{{Name: "cmd", Type: NotifySinkCommand, Command: "true", Events: []string{"*"}}}},
+		{sinks: []NotifySink{{Name: "po", Type: NotifySinkPushover}}},
+		{sinks: []NotifySink{{Type: NotifySinkPushover}}, wantErr: true},
+		{sinks: []NotifySink{{Name: "x", Type: "fax"}}, wantErr: true},
+		{sinks: []NotifySink{{Name: "x", Type: NotifySinkWebhook, URL: "ftp://example.com"}}, wantErr: true},
+		{sinks: []NotifySink{{Name: "x", Type: NotifySinkDiscord}}, wantErr: true},
+		{sinks: []NotifySink{{Name: "x", Type: NotifySinkCommand}}, wantErr: true},
+		{sinks: []NotifySink{{Name: "x", Type: NotifySinkEmail, SMTPHost: "h", From: "a@example.com"}}, wantErr: true},
+		{sinks: []NotifySink{{Name: "x", Type: NotifySinkEmail, SMTPHost: "h", From: "nope", To: []string{"b@example.com"}}}, wantErr: true},
+		{sinks: []NotifySink{{Name: "x", Type: NotifySinkPushover, Events: []string{"loop_started"}}}, wantErr: true},
+		{sinks: []NotifySink{{Name: "x", Type: NotifySinkPushover}, {Name: "x", Type: NotifySinkPushover}}, wantErr: true},
+	}
+	for i, tt := range tests {
+		cfg := &NotifyConfig{Sinks: tt.sinks}
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Fatalf("case %d: Validate() error = %v, wantErr %v", i, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNotifySinkSubscribes(t *testing.T) {
+	all := NotifySink{Name: "all"}
+	if !all.Subscribes(NotifyLoopDone) || !all.Subscribes(NotifyIssueInReview) {
+		t.Fatal("sink without events should receive everything")
+	}
+	some := NotifySink{Name: "some", Events: []string{NotifyParentAsk}}
+	if !some.Subscribes(NotifyParentAsk) || some.Subscribes(NotifyLoopDone) {
+		t.Fatalf("sink %+v subscriptions wrong", some)
+	}
+}
@@ -178,6 +178,9 @@ func ValidateDelegationForPosition(deleg *DelegationConfig) error {
 
 // ValidateLoopStepPosition validates one loop step against position constraints.
 func ValidateLoopStepPosition(step LoopStep, cfg *GlobalConfig) error {
+	if step.IsGroup() {
+		return ValidateLoopStepGroup(step)
+	}
 	pos := EffectiveStepPosition(step)
 	if !PositionCanOwnTurn(pos) {
 		return fmt.Errorf("loop step profile %q uses position %q; workers can only be spawned as sub-agents", step.Profile, pos)
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Remote host transports.
+const (
+	RemoteTransportSSH   = "ssh"   // run commands through the ssh client (default)
+	RemoteTransportLocal = "local" // run commands on this machine; for tests and loopback setups
+)
+
+// RemoteHost is a worker machine spawns can be dispatched to with
+// `adaf spawn --host`. Work moves through git: the spawn branch is pushed
+// to a repository under Root on the host, the agent runs in a worktree
+// there, and its commits are fetched back into the local spawn branch.
+type RemoteHost struct {
+	Name      string            `json:"name"`
+	Transport string            `json:"transport,omitempty"`  // ssh|local (empty = ssh)
+	Address   string            `json:"address,omitempty"`    // ssh destination, e.g. "dev@build-2"
+	Port      int               `json:"port,omitempty"`       // ssh port (0 = client default)
+	SSHArgs   []string          `json:"ssh_args,omitempty"`   // extra ssh client options, e.g. ["-i", "~/.ssh/adaf"]
+	Root      string            `json:"root,omitempty"`       // directory holding repos and worktrees (relative = home; empty = adaf-remote)
+	Agents    []string          `json:"agents"`               // agent CLIs installed on the host
+	Commands  map[string]string `json:"commands,omitempty"`   // agent -> command on the host when it differs from the local name
+	MaxSpawns int               `json:"max_spawns,omitempty"` // concurrent spawns on the host (0 = unlimited)
+}
+
+// EffectiveTransport returns Transport, defaulting to RemoteTransportSSH.
+func (h RemoteHost) EffectiveTransport() string {
+	if t := strings.ToLower(strings.TrimSpace(h.Transport)); t != "" {
+		return t
+	}
+	return RemoteTransportSSH
+}
+
+// EffectiveRoot returns Root with a leading "~/" dropped (paths on the host
+// are home-relative unless absolute), defaulting to "adaf-remote".
+func (h RemoteHost) EffectiveRoot() string {
+	root := strings.TrimSpace(h.Root)
+	root = strings.TrimPrefix(root, "~/")
+	if root == "" || root == "~" {
+		return "adaf-remote"
+	}
+	return path.Clean(root)
+}
+
+// HasAgent reports whether agentName is installed on the host.
+func (h RemoteHost) HasAgent(agentName string) bool {
+	return slices.ContainsFunc(h.Agents, func(a string) bool {
+		return strings.EqualFold(strings.TrimSpace(a), agentName)
+	})
+}
+
+// Validate rejects hosts that cannot be reached or run nothing.
+func (h RemoteHost) Validate() error {
+	name := strings.TrimSpace(h.Name)
+	if name == "" {
+		return fmt.Errorf("remote host name is required")
+	}
+	if strings.EqualFold(name, "any") {
+		return fmt.Errorf("remote host name %q is reserved", name)
+	}
+	switch h.EffectiveTransport() {
+	case RemoteTransportSSH:
+		if strings.TrimSpace(h.Address) == "" {
+			return fmt.Errorf("remote host %q: address is required for ssh", name)
+		}
+	case RemoteTransportLocal:
+	default:
+		return fmt.Errorf("remote host %q: transport must be one of: ssh, local", name)
+	}
+	if h.Port < 0 || h.MaxSpawns < 0 {
+		return fmt.Errorf("remote host %q: port and max_spawns must be >= 0", name)
+	}
+	if len(h.Agents) == 0 {
+		return fmt.Errorf("remote host %q: agents must list at least one agent", name)
+	}
+	return nil
+}
+
+// FindRemoteHost returns a remote host by name (case-insensitive), or nil.
+func (c *GlobalConfig) FindRemoteHost(name string) *RemoteHost {
+	for i := range c.RemoteHosts {
+		if strings.EqualFold(c.RemoteHosts[i].Name, name) {
+			return &c.RemoteHosts[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package config
+
+import "testing"
+
+func TestRemoteHostValidate(t *testing.T) {
+	tests := []struct {
+		host    RemoteHost
+		wantErr bool
+	}{
+		{host: RemoteHost{Name: "build-2", Address: "dev@build-2", Agents: []string{"claude"}}},
+		{host: RemoteHost{Name: "loop", Transport: "local", Agents: []string{"codex"}}},
+		{host: RemoteHost{Name: "", Address: "x", Agents: []string{"claude"}}, wantErr: true},
+		{host: RemoteHost{Name: "any", Address: "x", Agents: []string{"claude"}}, wantErr: true},
+		{host: RemoteHost{Name: "b", Agents: []string{"claude"}}, wantErr: true},
+		{host: RemoteHost{Name: "b", Transport: "rsh", Address: "x", Agents: []string{"claude"}}, wantErr: true},
+		{host: RemoteHost{Name: "b", Address: "x"}, wantErr: true},
+		{host: RemoteHost{Name: "b", Address: "x", Agents: []string{"claude"}, MaxSpawns: -1}, wantErr: true},
+	}
+	for i, tt := range tests {
+		if err := tt.host.Validate(); (err != nil) != tt.wantErr {
+			t.Fatalf("case %d: Validate() error = %v, wantErr %v", i, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRemoteHostEffectiveRoot(t *testing.T) {
+	for in, want := range map[string]string{
+		"":            "adaf-remote",
+		"~":           "adaf-remote",
+		"~/work/":     "work",
+		"/srv/adaf":   "/srv/adaf",
+		"spawns/../x": "x",
+	} {
+		if got := (RemoteHost{Root: in}).EffectiveRoot(); got != want {
+			t.Fatalf("EffectiveRoot(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
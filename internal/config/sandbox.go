@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Sandbox network modes.
+const (
+	SandboxNetworkHost      = "host"      // unrestricted egress (default)
+	SandboxNetworkNone      = "none"      // loopback only
+	SandboxNetworkAllowlist = "allowlist" // egress only to AllowHosts, through an adaf proxy
+)
+
+// Sandbox confines an agent process on Linux. Writes are limited to the
+// working directory, the adaf project store, the agent CLI's own state
+// directories and Writable; everything else is mounted read-only.
+type Sandbox struct {
+	Network    string   `json:"network,omitempty"`     // host|none|allowlist (empty = host)
+	AllowHosts []string `json:"allow_hosts,omitempty"` // hosts reachable in allowlist mode; "*.example.com" matches subdomains
+	Writable   []string `json:"writable,omitempty"`    // extra writable paths (absolute or ~/...)
+}
+
+// Clone returns a deep copy of s.
+func (s *Sandbox) Clone() *Sandbox {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	out.AllowHosts = append([]string(nil), s.AllowHosts...)
+	out.Writable = append([]string(nil), s.Writable...)
+	return &out
+}
+
+// EffectiveNetwork returns Network, defaulting to SandboxNetworkHost.
+func (s *Sandbox) EffectiveNetwork() string {
+	if s == nil {
+		return SandboxNetworkHost
+	}
+	if n := strings.ToLower(strings.TrimSpace(s.Network)); n != "" {
+		return n
+	}
+	return SandboxNetworkHost
+}
+
+// Validate rejects unknown network modes and relative writable paths.
+func (s *Sandbox) Validate() error {
+	if s == nil {
+		return nil
+	}
+	switch s.EffectiveNetwork() {
+	case SandboxNetworkHost, SandboxNetworkNone:
+	case SandboxNetworkAllowlist:
+		if len(s.AllowHosts) == 0 {
+			return fmt.Errorf("sandbox network %q requires allow_hosts", SandboxNetworkAllowlist)
+		}
+	default:
+		return fmt.Errorf("sandbox network must be one of: host, none, allowlist")
+	}
+	for _, h := range s.AllowHosts {
+		if strings.TrimSpace(h) == "" || strings.ContainsAny(h, "/: ") {
+			return fmt.Errorf("sandbox allow_hosts entry %q must be a bare host name", h)
+		}
+	}
+	for _, p := range s.Writable {
+		p = strings.TrimSpace(p)
+		if !filepath.IsAbs(p) && !strings.HasPrefix(p, "~/") {
+			return fmt.Errorf("sandbox writable path %q must be absolute or start with ~/", p)
+		}
+	}
+	return nil
+}
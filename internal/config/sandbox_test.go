@@ -0,0 +1,36 @@
+package config
+
+import "testing"
+
+func TestSandboxValidate(t *testing.T) {
+	tests := []struct {
+		sandbox *Sandbox
+		wantErr bool
+	}{
+		{sandbox: nil},
+		{sandbox: &Sandbox{}},
+		{sandbox: &Sandbox{Network: "none", Writable: []string{"/var/cache/go", "~/.npm"}}},
+		{sandbox: &Sandbox{Network: "allowlist", AllowHosts: []string{"api.anthropic.com", "*.github.com"}}},
+		{sandbox: &Sandbox{Network: "allowlist"}, wantErr: true},
+		{sandbox: &Sandbox{Network: "allowlist", AllowHosts: []string{"https://example.com"}}, wantErr: true},
+		{sandbox: &Sandbox{Network: "vpn"}, wantErr: true},
+		{sandbox: &Sandbox{Writable: []string{"relative/dir"}}, wantErr: true},
+	}
+	for i, tt := range tests {
+		if err := tt.sandbox.Validate(); (err != nil) != tt.wantErr {
+			t.Fatalf("case %d: Validate() error = %v, wantErr %v", i, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDelegationCloneCopiesSandbox(t *testing.T) {
+	d := &DelegationConfig{Profiles: []DelegationProfile{{
+		Name:    "worker",
+		Sandbox: &Sandbox{Network: SandboxNetworkAllowlist, AllowHosts: []string{"a.example"}},
+	}}}
+	c := d.Clone()
+	c.Profiles[0].Sandbox.AllowHosts[0] = "b.example"
+	if d.Profiles[0].Sandbox.AllowHosts[0] != "a.example" {
+		t.Fatal("Clone shares the sandbox allow list")
+	}
+}
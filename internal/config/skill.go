@@ -67,6 +67,7 @@ func DefaultSkills() []Skill {
 				"**Review & merge (MANDATORY for writable spawns):**\n" +
 				"- `adaf spawn-diff --spawn-id N` — View diff of spawn's changes\n" +
 				"- `adaf spawn-merge --spawn-id N [--squash]` — Merge spawn's changes into YOUR branch\n" +
+				"- `adaf spawn-merge --spawn-id N --on-conflict resolve` — On conflicts, spawn a resolver child from the spawn's branch (or `keep` to resolve them yourself, then `--continue`)\n" +
 				"- `adaf spawn-reject --spawn-id N` — Reject spawn's changes (destroys branch — see below)\n\n" +
 				"**Feedback scoring (MANDATORY after child completion):**\n" +
 				"- `adaf spawn-feedback --spawn-id N --difficulty <0-10> --quality <0-10> [--notes \"...\"]`\n" +
@@ -0,0 +1,243 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TransitionStop is the Goto target that ends the loop run.
+const TransitionStop = "stop"
+
+// StepTransition routes a loop to another step once the step it belongs to
+// has finished. A step's transitions are evaluated in order and the first
+// matching one wins; without a match the loop continues with the next step.
+type StepTransition struct {
+	When string `json:"when,omitempty"` // condition, e.g. `build_state == failing && issues.open > 0`; empty always matches
+	Goto string `json:"goto"`           // target step id, 1-based step number, or "stop"
+}
+
+// Condition fields available to StepTransition.When. Turn fields come from
+// the last turn of the finished step; issue counts cover the run's plan.
+var (
+	transitionTurnFields = []string{
+		"build_state", "current_state", "known_issues", "next_steps",
+		"what_was_built", "objective", "commit_hash", "agent", "agent_model",
+		"profile_name", "duration_secs",
+	}
+	transitionIssueFields = []string{
+		"issues.open", "issues.ongoing", "issues.in_review", "issues.closed",
+		"issues.critical", "issues.high", "issues.medium", "issues.low",
+	}
+)
+
+// TransitionFieldOutcome holds the token a step reported with
+// `adaf loop outcome <token>`.
+const TransitionFieldOutcome = "outcome"
+
+// StepCondition is a parsed StepTransition.When expression: comparisons
+// joined by && and ||, with && binding tighter.
+type StepCondition struct {
+	any [][]conditionTerm // OR of ANDs
+}
+
+type conditionTerm struct {
+	field string
+	op    string
+	value string
+}
+
+var conditionTermRE = regexp.MustCompile(`^([a-z_.]+)\s*(==|!=|>=|<=|>|<)\s*(.+)$`)
+
+// ParseStepCondition parses a transition condition. An empty expression
+// yields a condition that always matches.
+func ParseStepCondition(expr string) (*StepCondition, error) {
+	cond := &StepCondition{}
+	if strings.TrimSpace(expr) == "" {
+		return cond, nil
+	}
+	for _, alt := range strings.Split(expr, "||") {
+		var terms []conditionTerm
+		for _, raw := range strings.Split(alt, "&&") {
+			raw = strings.TrimSpace(raw)
+			m := conditionTermRE.FindStringSubmatch(raw)
+			if m == nil {
+				return nil, fmt.Errorf("invalid comparison %q (want <field> <op> <value>)", raw)
+			}
+			field := m[1]
+			if !validTransitionField(field) {
+				return nil, fmt.Errorf("unknown condition field %q", field)
+			}
+			value := strings.TrimSpace(m[3])
+			if unq, err := strconv.Unquote(value); err == nil {
+				value = unq
+			} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+				value = value[1 : len(value)-1]
+			}
+			terms = append(terms, conditionTerm{field: field, op: m[2], value: value})
+		}
+		cond.any = append(cond.any, terms)
+	}
+	return cond, nil
+}
+
+func validTransitionField(field string) bool {
+	if field == TransitionFieldOutcome {
+		return true
+	}
+	for _, f := range transitionTurnFields {
+		if f == field {
+			return true
+		}
+	}
+	for _, f := range transitionIssueFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Match evaluates the condition against field values. Missing fields
+// compare as empty strings.
+func (c *StepCondition) Match(fields map[string]string) bool {
+	if c == nil || len(c.any) == 0 {
+		return true
+	}
+	for _, terms := range c.any {
+		ok := true
+		for _, t := range terms {
+			if !t.match(fields[t.field]) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Outcomes returns the outcome tokens the condition compares against.
+func (c *StepCondition) Outcomes() []string {
+	if c == nil {
+		return nil
+	}
+	var out []string
+	for _, terms := range c.any {
+		for _, t := range terms {
+			if t.field == TransitionFieldOutcome && t.value != "" {
+				out = append(out, strings.ToLower(t.value))
+			}
+		}
+	}
+	return out
+}
+
+func (t conditionTerm) match(actual string) bool {
+	a, aErr := strconv.ParseFloat(strings.TrimSpace(actual), 64)
+	b, bErr := strconv.ParseFloat(t.value, 64)
+	if aErr == nil && bErr == nil {
+		switch t.op {
+		case "==":
+			return a == b
+		case "!=":
+			return a != b
+		case ">":
+			return a > b
+		case ">=":
+			return a >= b
+		case "<":
+			return a < b
+		case "<=":
+			return a <= b
+		}
+		return false
+	}
+	switch t.op {
+	case "==":
+		return strings.EqualFold(strings.TrimSpace(actual), t.value)
+	case "!=":
+		return !strings.EqualFold(strings.TrimSpace(actual), t.value)
+	}
+	// Ordering only applies to numbers.
+	return false
+}
+
+// FindStepIndex resolves a transition target to a step index. Targets match
+// a step id (case-insensitive) or a 1-based step number.
+func (l *LoopDef) FindStepIndex(target string) (int, bool) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return 0, false
+	}
+	for i, step := range l.Steps {
+		if step.ID != "" && strings.EqualFold(step.ID, target) {
+			return i, true
+		}
+	}
+	if n, err := strconv.Atoi(target); err == nil && n >= 1 && n <= len(l.Steps) {
+		return n - 1, true
+	}
+	return 0, false
+}
+
+// StepOutcomes lists the outcome tokens referenced by a step's transitions,
+// deduplicated in declaration order.
+func StepOutcomes(step LoopStep) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, tr := range step.Transitions {
+		cond, err := ParseStepCondition(tr.When)
+		if err != nil {
+			continue
+		}
+		for _, o := range cond.Outcomes() {
+			if !seen[o] {
+				seen[o] = true
+				out = append(out, o)
+			}
+		}
+	}
+	return out
+}
+
+// ValidateLoopTransitions checks step ids and every transition of l.
+func ValidateLoopTransitions(l *LoopDef) error {
+	if l == nil {
+		return nil
+	}
+	ids := make(map[string]int)
+	for i, step := range l.Steps {
+		id := strings.ToLower(strings.TrimSpace(step.ID))
+		if id == "" {
+			continue
+		}
+		if id == TransitionStop {
+			return fmt.Errorf("step %d: id %q is reserved", i+1, step.ID)
+		}
+		if _, err := strconv.Atoi(id); err == nil {
+			return fmt.Errorf("step %d: id %q must not be a number", i+1, step.ID)
+		}
+		if prev, dup := ids[id]; dup {
+			return fmt.Errorf("step %d: id %q already used by step %d", i+1, step.ID, prev+1)
+		}
+		ids[id] = i
+	}
+	for i, step := range l.Steps {
+		for j, tr := range step.Transitions {
+			if _, err := ParseStepCondition(tr.When); err != nil {
+				return fmt.Errorf("step %d transition %d: %w", i+1, j+1, err)
+			}
+			if strings.EqualFold(strings.TrimSpace(tr.Goto), TransitionStop) {
+				continue
+			}
+			if _, ok := l.FindStepIndex(tr.Goto); !ok {
+				return fmt.Errorf("step %d transition %d: unknown target step %q", i+1, j+1, tr.Goto)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStepConditionMatch(t *testing.T) {
+	tests := []struct {
+		expr   string
+		fields map[string]string
+		want   bool
+	}{
+		{expr: "", want: true},
+		{expr: "build_state == failing", fields: map[string]string{"build_state": "Failing"}, want: true},
+		{expr: "build_state != failing", fields: map[string]string{"build_state": "passing"}, want: true},
+		{expr: `outcome == "tests_failing"`, fields: map[string]string{"outcome": "tests_failing"}, want: true},
+		{expr: "issues.open > 0", fields: map[string]string{"issues.open": "0"}, want: false},
+		{expr: "issues.open >= 2 && issues.critical > 0", fields: map[string]string{"issues.open": "3", "issues.critical": "1"}, want: true},
+		{expr: "issues.open >= 2 && issues.critical > 0", fields: map[string]string{"issues.open": "3", "issues.critical": "0"}, want: false},
+		{expr: "outcome == done || build_state == passing", fields: map[string]string{"build_state": "passing"}, want: true},
+		{expr: "build_state > passing", fields: map[string]string{"build_state": "passing"}, want: false},
+		{expr: "commit_hash == ''", fields: map[string]string{}, want: true},
+	}
+	for _, tt := range tests {
+		cond, err := ParseStepCondition(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseStepCondition(%q) error = %v", tt.expr, err)
+		}
+		if got := cond.Match(tt.fields); got != tt.want {
+			t.Fatalf("ParseStepCondition(%q).Match(%v) = %v, want %v", tt.expr, tt.fields, got, tt.want)
+		}
+	}
+}
+
+func TestParseStepConditionRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{"build_state", "unknown_field == x", "issues.open => 1", "&& outcome == x"} {
+		if _, err := ParseStepCondition(expr); err == nil {
+			t.Fatalf("ParseStepCondition(%q) error = nil, want error", expr)
+		}
+	}
+}
+
+func TestValidateLoopTransitions(t *testing.T) {
+	valid := &LoopDef{Steps: []LoopStep{
+		{ID: "test", Profile: "p", Transitions: []StepTransition{
+			{When: "outcome == failing", Goto: "fix"},
+			{When: "outcome == passing", Goto: "3"},
+			{Goto: "stop"},
+		}},
+		{ID: "fix", Profile: "p"},
+		{Profile: "p"},
+	}}
+	if err := ValidateLoopTransitions(valid); err != nil {
+		t.Fatalf("ValidateLoopTransitions(valid) error = %v", err)
+	}
+	if got := StepOutcomes(valid.Steps[0]); !reflect.DeepEqual(got, []string{"failing", "passing"}) {
+		t.Fatalf("StepOutcomes() = %v, want [failing passing]", got)
+	}
+
+	invalid := []*LoopDef{
+		{Steps: []LoopStep{{Profile: "p", Transitions: []StepTransition{{Goto: "missing"}}}}},
+		{Steps: []LoopStep{{Profile: "p", Transitions: []StepTransition{{Goto: "4"}}}}},
+		{Steps: []LoopStep{{ID: "a", Profile: "p"}, {ID: "A", Profile: "p"}}},
+		{Steps: []LoopStep{{ID: "stop", Profile: "p"}}},
+		{Steps: []LoopStep{{ID: "2", Profile: "p"}}},
+		{Steps: []LoopStep{{Profile: "p", Transitions: []StepTransition{{When: "nope", Goto: "1"}}}}},
+	}
+	for i, def := range invalid {
+		if err := ValidateLoopTransitions(def); err == nil {
+			t.Fatalf("case %d: ValidateLoopTransitions() error = nil, want error", i)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultUsageGateThreshold is the provider utilization (in percent) at which
+// a UsageGate treats a profile as unavailable when Threshold is not set.
+const DefaultUsageGateThreshold = 90
+
+// Usage gate actions.
+const (
+	UsageGateActionPause = "pause" // sleep until the blocking limit resets
+	UsageGateActionRoute = "route" // run the profile's first fallback that has headroom
+)
+
+// UsageGate checks a profile's provider usage limits before a loop step or a
+// spawn launches it. A profile is blocked once any of its provider's limits
+// reaches Threshold. Route falls back to pausing when no fallback profile
+// has headroom.
+type UsageGate struct {
+	Threshold    float64 `json:"threshold,omitempty"`      // blocking utilization in % (0 = 90)
+	Action       string  `json:"action,omitempty"`         // "pause" (default) or "route"
+	MaxPauseMins int     `json:"max_pause_mins,omitempty"` // re-check after at most this long (0 = at reset)
+}
+
+// Clone returns a copy of g.
+func (g *UsageGate) Clone() *UsageGate {
+	if g == nil {
+		return nil
+	}
+	out := *g
+	return &out
+}
+
+// Validate rejects out-of-range thresholds and unknown actions.
+func (g *UsageGate) Validate() error {
+	if g == nil {
+		return nil
+	}
+	if g.Threshold < 0 || g.Threshold > 100 {
+		return fmt.Errorf("usage_gate threshold must be between 0 and 100")
+	}
+	switch g.Action {
+	case "", UsageGateActionPause, UsageGateActionRoute:
+	default:
+		return fmt.Errorf("usage_gate action must be %q or %q, got %q", UsageGateActionPause, UsageGateActionRoute, g.Action)
+	}
+	if g.MaxPauseMins < 0 {
+		return fmt.Errorf("usage_gate max_pause_mins must be >= 0")
+	}
+	return nil
+}
+
+// EffectiveThreshold returns Threshold, defaulting to DefaultUsageGateThreshold.
+func (g *UsageGate) EffectiveThreshold() float64 {
+	if g == nil || g.Threshold <= 0 {
+		return DefaultUsageGateThreshold
+	}
+	return g.Threshold
+}
+
+// EffectiveAction returns Action, defaulting to UsageGateActionPause.
+func (g *UsageGate) EffectiveAction() string {
+	if g == nil || g.Action == "" {
+		return UsageGateActionPause
+	}
+	return g.Action
+}
+
+// MaxPause returns the cap on a single pause, or 0 when pauses last until
+// the blocking limit resets.
+func (g *UsageGate) MaxPause() time.Duration {
+	if g == nil || g.MaxPauseMins <= 0 {
+		return 0
+	}
+	return time.Duration(g.MaxPauseMins) * time.Minute
+}
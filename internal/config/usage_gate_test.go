@@ -0,0 +1,39 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsageGateValidate(t *testing.T) {
+	tests := []struct {
+		gate    *UsageGate
+		wantErr bool
+	}{
+		{gate: nil},
+		{gate: &UsageGate{}},
+		{gate: &UsageGate{Threshold: 80, Action: UsageGateActionRoute, MaxPauseMins: 30}},
+		{gate: &UsageGate{Threshold: 120}, wantErr: true},
+		{gate: &UsageGate{Threshold: -1}, wantErr: true},
+		{gate: &UsageGate{Action: "skip"}, wantErr: true},
+		{gate: &UsageGate{MaxPauseMins: -5}, wantErr: true},
+	}
+	for i, tt := range tests {
+		if err := tt.gate.Validate(); (err != nil) != tt.wantErr {
+			t.Fatalf("case %d: Validate() error = %v, wantErr %v", i, err, tt.wantErr)
+		}
+	}
+}
+
+func TestUsageGateDefaults(t *testing.T) {
+	var nilGate *UsageGate
+	if got := nilGate.EffectiveThreshold(); got != DefaultUsageGateThreshold {
+		t.Fatalf("nil EffectiveThreshold() = %v, want %v", got, DefaultUsageGateThreshold)
+	}
+	if got := (&UsageGate{}).EffectiveAction(); got != UsageGateActionPause {
+		t.Fatalf("EffectiveAction() = %q, want %q", got, UsageGateActionPause)
+	}
+	if got := (&UsageGate{MaxPauseMins: 10}).MaxPause(); got != 10*time.Minute {
+		t.Fatalf("MaxPause() = %v, want 10m", got)
+	}
+}
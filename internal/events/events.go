@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/store"
 	"github.com/agusx1211/adaf/internal/stream"
 )
 
@@ -105,10 +106,11 @@ type SpawnInfo struct {
 	Profile       string
 	Position      string
 	Role          string
-	Status        string // "running", "awaiting_input", "completed", "failed", "canceled", "merged", "rejected"
+	Status        string // "queued", "running", "awaiting_input", "completed", "failed", "canceled", "merged", "rejected"
 	Question      string // pending question when status is "awaiting_input"
 	Summary       string // parent-facing final summary (or crash note on failure)
 	Result        string // raw completion/crash result text
+	QueuePosition int    // 1-based position while status is "queued"
 }
 
 // LoopStepStartMsg signals that a loop step has started.
@@ -121,6 +123,7 @@ type LoopStepStartMsg struct {
 	Profile    string
 	Turns      int
 	TotalSteps int
+	Members    []LoopStepMember // parallel group members; empty for single-profile steps
 }
 
 // LoopStepEndMsg signals that a loop step has ended.
@@ -132,6 +135,28 @@ type LoopStepEndMsg struct {
 	StepIndex  int
 	Profile    string
 	TotalSteps int
+	Members    []LoopStepMember // final member states of a parallel group
+}
+
+// LoopStepMember reports one member of a parallel loop step group.
+type LoopStepMember struct {
+	Profile string
+	Status  string // "running", "completed", "failed", "cancelled"
+	TurnID  int
+	Branch  string
+}
+
+// LoopBudgetMsg reports live budget consumption of a loop run.
+type LoopBudgetMsg struct {
+	RunID  int
+	Budget store.LoopRunBudget
+}
+
+// LoopUsagePauseMsg reports a loop step waiting for provider usage limits to
+// reset. A nil Pause means the step resumed.
+type LoopUsagePauseMsg struct {
+	RunID int
+	Pause *store.UsagePause
 }
 
 // LoopDoneMsg signals that the entire loop has finished.
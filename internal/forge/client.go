@@ -0,0 +1,209 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// client talks to the Gitea and GitHub REST APIs, which agree on the
+// endpoints adaf uses except for inline review comments.
+type client struct {
+	kind  string
+	base  string
+	owner string
+	repo  string
+	token string
+}
+
+type apiUser struct {
+	Login string `json:"login"`
+}
+
+type apiPullRequest struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	State   string `json:"state"`
+	Merged  bool   `json:"merged"`
+}
+
+type apiComment struct {
+	ID          int64     `json:"id"`
+	Body        string    `json:"body"`
+	User        apiUser   `json:"user"`
+	Path        string    `json:"path"`
+	Line        int       `json:"line"`
+	Position    int       `json:"position"`
+	State       string    `json:"state"`
+	CreatedAt   time.Time `json:"created_at"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+func (c *client) repoPath(format string, args ...any) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(c.owner), url.PathEscape(c.repo)) + fmt.Sprintf(format, args...)
+}
+
+func (c *client) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "adaf-forge")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "token "+c.token)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: HTTP %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// pageSize is the largest page each forge serves by default.
+func (c *client) pageSize() int {
+	if c.kind == store.ForgeGitHub {
+		return 100
+	}
+	return 50
+}
+
+func (c *client) pageQuery(page int) string {
+	if c.kind == store.ForgeGitHub {
+		return fmt.Sprintf("?per_page=%d&page=%d", c.pageSize(), page)
+	}
+	return fmt.Sprintf("?limit=%d&page=%d", c.pageSize(), page)
+}
+
+// listComments fetches every page of a comment listing, stopping at the
+// first short page.
+func (c *client) listComments(ctx context.Context, path string) ([]apiComment, error) {
+	var all []apiComment
+	for page := 1; ; page++ {
+		var batch []apiComment
+		if err := c.do(ctx, http.MethodGet, path+c.pageQuery(page), nil, &batch); err != nil {
+			return nil, err
+		}
+		all = append(all, batch...)
+		if len(batch) < c.pageSize() {
+			return all, nil
+		}
+	}
+}
+
+func (p apiPullRequest) toPullRequest() *PullRequest {
+	state := p.State
+	if p.Merged {
+		state = "merged"
+	}
+	return &PullRequest{Number: p.Number, URL: p.HTMLURL, State: state}
+}
+
+func (c *client) CreatePullRequest(ctx context.Context, in NewPullRequest) (*PullRequest, error) {
+	req := map[string]string{"title": in.Title, "body": in.Body, "head": in.Head, "base": in.Base}
+	var out apiPullRequest
+	if err := c.do(ctx, http.MethodPost, c.repoPath("/pulls"), req, &out); err != nil {
+		return nil, err
+	}
+	return out.toPullRequest(), nil
+}
+
+func (c *client) GetPullRequest(ctx context.Context, number int) (*PullRequest, error) {
+	var out apiPullRequest
+	if err := c.do(ctx, http.MethodGet, c.repoPath("/pulls/%d", number), nil, &out); err != nil {
+		return nil, err
+	}
+	return out.toPullRequest(), nil
+}
+
+func (c *client) ListComments(ctx context.Context, number int) ([]Comment, error) {
+	var comments []Comment
+
+	conversation, err := c.listComments(ctx, c.repoPath("/issues/%d/comments", number))
+	if err != nil {
+		return nil, err
+	}
+	for _, ac := range conversation {
+		comments = append(comments, Comment{Key: fmt.Sprintf("issue:%d", ac.ID), Author: ac.User.Login, Body: ac.Body, Created: ac.CreatedAt})
+	}
+
+	reviews, err := c.listComments(ctx, c.repoPath("/pulls/%d/reviews", number))
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reviews {
+		if strings.TrimSpace(r.Body) != "" {
+			body := r.Body
+			if r.State != "" && r.State != "COMMENTED" && r.State != "COMMENT" {
+				body = fmt.Sprintf("[%s] %s", r.State, body)
+			}
+			comments = append(comments, Comment{Key: fmt.Sprintf("review:%d", r.ID), Author: r.User.Login, Body: body, Created: r.SubmittedAt})
+		}
+		// Gitea nests inline comments under their review.
+		if c.kind == store.ForgeGitea {
+			var inline []apiComment
+			if err := c.do(ctx, http.MethodGet, c.repoPath("/pulls/%d/reviews/%d/comments", number, r.ID), nil, &inline); err != nil {
+				return nil, err
+			}
+			comments = appendInline(comments, inline)
+		}
+	}
+	if c.kind == store.ForgeGitHub {
+		inline, err := c.listComments(ctx, c.repoPath("/pulls/%d/comments", number))
+		if err != nil {
+			return nil, err
+		}
+		comments = appendInline(comments, inline)
+	}
+
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].Created.Before(comments[j].Created) })
+	return comments, nil
+}
+
+func appendInline(comments []Comment, inline []apiComment) []Comment {
+	for _, ac := range inline {
+		line := ac.Line
+		if line == 0 {
+			line = ac.Position
+		}
+		comments = append(comments, Comment{
+			Key:     fmt.Sprintf("inline:%d", ac.ID),
+			Author:  ac.User.Login,
+			Body:    ac.Body,
+			Path:    ac.Path,
+			Line:    line,
+			Created: ac.CreatedAt,
+		})
+	}
+	return comments
+}
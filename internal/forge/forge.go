@@ -0,0 +1,106 @@
+// Package forge opens pull requests for spawn and loop output on a git forge
+// and syncs their review comments back into the issue tracker.
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// DefaultTokenEnv is the environment variable read for the API token when a
+// ForgeConfig does not name one.
+const DefaultTokenEnv = "ADAF_FORGE_TOKEN"
+
+// DefaultRemote is the git remote pushed to when a ForgeConfig names none.
+const DefaultRemote = "origin"
+
+// Forge is the subset of a forge's API adaf needs.
+type Forge interface {
+	CreatePullRequest(ctx context.Context, in NewPullRequest) (*PullRequest, error)
+	GetPullRequest(ctx context.Context, number int) (*PullRequest, error)
+	// ListComments returns conversation comments, review summaries and
+	// inline review comments of a pull request, oldest first.
+	ListComments(ctx context.Context, number int) ([]Comment, error)
+}
+
+// NewPullRequest describes a pull request to open.
+type NewPullRequest struct {
+	Title string
+	Body  string
+	Head  string // pushed branch
+	Base  string // target branch
+}
+
+// PullRequest is a pull request as reported by the forge.
+type PullRequest struct {
+	Number int
+	URL    string
+	State  string // open, closed or merged
+}
+
+// Comment is one piece of review feedback.
+type Comment struct {
+	Key     string // stable across syncs, e.g. "issue:12" or "review:4"
+	Author  string
+	Body    string
+	Path    string // inline comments only
+	Line    int    // inline comments only
+	Created time.Time
+}
+
+// Validate checks that cfg names a supported forge and repository.
+func Validate(cfg *store.ForgeConfig) error {
+	if cfg == nil {
+		return fmt.Errorf("no forge configured (set \"forge\" in .adaf/project.json)")
+	}
+	switch cfg.Type {
+	case store.ForgeGitea, store.ForgeGitHub:
+	default:
+		return fmt.Errorf("unknown forge type %q (valid: %s, %s)", cfg.Type, store.ForgeGitea, store.ForgeGitHub)
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("forge url must be an http(s) URL")
+	}
+	if strings.TrimSpace(cfg.Owner) == "" || strings.TrimSpace(cfg.Repo) == "" {
+		return fmt.Errorf("forge owner and repo are required")
+	}
+	return nil
+}
+
+// New returns a client for cfg, authenticated with the token from its
+// TokenEnv variable.
+func New(cfg *store.ForgeConfig) (Forge, error) {
+	if err := Validate(cfg); err != nil {
+		return nil, err
+	}
+	tokenEnv := cfg.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = DefaultTokenEnv
+	}
+	c := &client{
+		kind:  cfg.Type,
+		base:  strings.TrimRight(cfg.URL, "/"),
+		owner: cfg.Owner,
+		repo:  cfg.Repo,
+		token: strings.TrimSpace(os.Getenv(tokenEnv)),
+	}
+	if c.kind == store.ForgeGitea && !strings.HasSuffix(c.base, "/api/v1") {
+		c.base += "/api/v1"
+	}
+	return c, nil
+}
+
+// Remote returns the git remote configured for cfg.
+func Remote(cfg *store.ForgeConfig) string {
+	if cfg == nil || strings.TrimSpace(cfg.Remote) == "" {
+		return DefaultRemote
+	}
+	return strings.TrimSpace(cfg.Remote)
+}
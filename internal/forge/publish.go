@@ -0,0 +1,318 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/store"
+	"github.com/agusx1211/adaf/internal/worktree"
+)
+
+// feedbackActor is recorded as the updater of issues touched by a sync.
+const feedbackActor = "forge"
+
+// Publisher pushes spawn branches and loop run commits to the project's
+// forge, opens pull requests for them and syncs review feedback back.
+type Publisher struct {
+	Store  *store.Store
+	Forge  Forge
+	Config *store.ForgeConfig
+	Git    *worktree.Manager
+}
+
+// NewPublisher builds a publisher from the project's forge config.
+func NewPublisher(s *store.Store) (*Publisher, error) {
+	projCfg, err := s.LoadProject()
+	if err != nil {
+		return nil, fmt.Errorf("loading project: %w", err)
+	}
+	f, err := New(projCfg.Forge)
+	if err != nil {
+		return nil, err
+	}
+	return &Publisher{Store: s, Forge: f, Config: projCfg.Forge, Git: worktree.NewManager(projCfg.RepoPath)}, nil
+}
+
+func (p *Publisher) base(ctx context.Context) (string, error) {
+	if b := strings.TrimSpace(p.Config.Base); b != "" {
+		return b, nil
+	}
+	return p.Git.CurrentBranch(ctx)
+}
+
+// PublishSpawn pushes a completed spawn's branch and opens a pull request
+// for it instead of merging locally.
+func (p *Publisher) PublishSpawn(ctx context.Context, spawnID int, title string) (*store.PullRequest, error) {
+	rec, err := p.Store.GetSpawn(spawnID)
+	if err != nil {
+		return nil, fmt.Errorf("spawn %d not found: %w", spawnID, err)
+	}
+	if rec.Status != store.SpawnStatusCompleted {
+		return nil, fmt.Errorf("spawn %d is %s, not completed", spawnID, rec.Status)
+	}
+	if rec.Branch == "" {
+		return nil, fmt.Errorf("spawn %d has no branch (read-only?)", spawnID)
+	}
+	if rec.PullRequestURL != "" {
+		return nil, fmt.Errorf("spawn %d already has a pull request: %s", spawnID, rec.PullRequestURL)
+	}
+	if strings.TrimSpace(title) == "" {
+		title = spawnTitle(rec)
+	}
+	var turn *store.Turn
+	if rec.ChildTurnID > 0 {
+		turn, _ = p.Store.GetTurn(rec.ChildTurnID)
+	}
+
+	pr, err := p.open(ctx, rec.Branch, rec.Branch, title, SpawnBody(rec, turn))
+	if err != nil {
+		return nil, err
+	}
+	pr.SpawnID = rec.ID
+	pr.IssueIDs = append([]int(nil), rec.IssueIDs...)
+	if err := p.Store.CreatePullRequest(pr); err != nil {
+		return nil, err
+	}
+	rec.PullRequestURL = pr.URL
+	if err := p.Store.UpdateSpawn(rec); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+// PublishLoopRun pushes the commit HEAD was at when a loop run stopped,
+// which holds the run's accumulated commits, to a run branch and opens a
+// pull request for it.
+func (p *Publisher) PublishLoopRun(ctx context.Context, runID int, title string) (*store.PullRequest, error) {
+	run, err := p.Store.GetLoopRun(runID)
+	if err != nil {
+		return nil, fmt.Errorf("loop run %d not found: %w", runID, err)
+	}
+	if run.HeadCommit == "" {
+		return nil, fmt.Errorf("loop run %d has no recorded head commit; only stopped runs can be published", runID)
+	}
+	if strings.TrimSpace(title) == "" {
+		title = fmt.Sprintf("Loop %s run #%d", run.LoopName, run.ID)
+	}
+	var turns []store.Turn
+	for _, id := range run.TurnIDs {
+		if t, err := p.Store.GetTurn(id); err == nil {
+			turns = append(turns, *t)
+		}
+	}
+
+	pr, err := p.open(ctx, run.HeadCommit, fmt.Sprintf("adaf/loop-run-%d", run.ID), title, LoopRunBody(run, turns))
+	if err != nil {
+		return nil, err
+	}
+	pr.LoopRunID = run.ID
+	if err := p.Store.CreatePullRequest(pr); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+// open pushes ref to the head branch and opens the pull request.
+func (p *Publisher) open(ctx context.Context, ref, head, title, body string) (*store.PullRequest, error) {
+	base, err := p.base(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("resolving base branch: %w", err)
+	}
+	if head == base {
+		return nil, fmt.Errorf("head and base are both %q", base)
+	}
+	if err := p.Git.Push(ctx, Remote(p.Config), ref, head); err != nil {
+		return nil, fmt.Errorf("pushing %s: %w", head, err)
+	}
+	remote, err := p.Forge.CreatePullRequest(ctx, NewPullRequest{Title: title, Body: body, Head: head, Base: base})
+	if err != nil {
+		return nil, fmt.Errorf("opening pull request: %w", err)
+	}
+	return &store.PullRequest{
+		Number: remote.Number,
+		URL:    remote.URL,
+		Title:  title,
+		Head:   head,
+		Base:   base,
+		State:  remote.State,
+	}, nil
+}
+
+// Sync refreshes the pull request's state and copies review comments not
+// seen before onto its feedback issues, reopening closed ones so the next
+// loop step picks the feedback up. It returns how many comments were new.
+func (p *Publisher) Sync(ctx context.Context, pr *store.PullRequest) (int, error) {
+	remote, err := p.Forge.GetPullRequest(ctx, pr.Number)
+	if err != nil {
+		return 0, err
+	}
+	pr.State = remote.State
+	comments, err := p.Forge.ListComments(ctx, pr.Number)
+	if err != nil {
+		return 0, err
+	}
+
+	synced := make(map[string]bool, len(pr.SyncedComments))
+	for _, key := range pr.SyncedComments {
+		synced[key] = true
+	}
+	var fresh []Comment
+	for _, c := range comments {
+		if !synced[c.Key] && strings.TrimSpace(c.Body) != "" {
+			fresh = append(fresh, c)
+		}
+	}
+
+	if len(fresh) > 0 {
+		if err := p.ensureFeedbackIssue(pr); err != nil {
+			return 0, err
+		}
+		// Record each comment as soon as it is copied, so a failure part way
+		// through does not copy the earlier ones again on the next sync.
+		for _, c := range fresh {
+			for _, issueID := range pr.IssueIDs {
+				if _, err := p.Store.AddIssueComment(issueID, FormatComment(pr, c), c.Author); err != nil {
+					return 0, fmt.Errorf("commenting on issue #%d: %w", issueID, err)
+				}
+				p.reopenIssue(issueID)
+			}
+			pr.SyncedComments = append(pr.SyncedComments, c.Key)
+			if err := p.Store.UpdatePullRequest(pr); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	pr.SyncedAt = time.Now().UTC()
+	if err := p.Store.UpdatePullRequest(pr); err != nil {
+		return 0, err
+	}
+	return len(fresh), nil
+}
+
+// SyncOpen syncs every pull request still open on the forge.
+func (p *Publisher) SyncOpen(ctx context.Context) (int, error) {
+	list, err := p.Store.ListPullRequests()
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for i := range list {
+		if list[i].State != "" && list[i].State != "open" {
+			continue
+		}
+		n, err := p.Sync(ctx, &list[i])
+		if err != nil {
+			return total, fmt.Errorf("syncing PR #%d: %w", list[i].Number, err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// ensureFeedbackIssue files an issue for review feedback when the pull
+// request has no linked issue yet.
+func (p *Publisher) ensureFeedbackIssue(pr *store.PullRequest) error {
+	if len(pr.IssueIDs) > 0 {
+		return nil
+	}
+	issue := &store.Issue{
+		Title:       "Review feedback: " + pr.Title,
+		Description: fmt.Sprintf("Address the review comments on pull request #%d (%s). New comments are added below.", pr.Number, pr.URL),
+		Status:      store.IssueStatusOpen,
+		Priority:    "high",
+		Labels:      []string{"review"},
+		CreatedBy:   feedbackActor,
+	}
+	if err := p.Store.CreateIssue(issue); err != nil {
+		return fmt.Errorf("creating feedback issue: %w", err)
+	}
+	pr.IssueIDs = []int{issue.ID}
+	return nil
+}
+
+func (p *Publisher) reopenIssue(issueID int) {
+	issue, err := p.Store.GetIssue(issueID)
+	if err != nil || store.NormalizeIssueStatus(issue.Status) == store.IssueStatusOpen {
+		return
+	}
+	issue.Status = store.IssueStatusOpen
+	issue.UpdatedBy = feedbackActor
+	p.Store.UpdateIssue(issue)
+}
+
+// FormatComment renders a review comment for an issue comment.
+func FormatComment(pr *store.PullRequest, c Comment) string {
+	where := ""
+	if c.Path != "" {
+		where = " on " + c.Path
+		if c.Line > 0 {
+			where += fmt.Sprintf(":%d", c.Line)
+		}
+	}
+	return fmt.Sprintf("Review comment on PR #%d%s by @%s:\n\n%s", pr.Number, where, c.Author, strings.TrimSpace(c.Body))
+}
+
+func spawnTitle(rec *store.SpawnRecord) string {
+	task := strings.TrimSpace(strings.SplitN(rec.Task, "\n", 2)[0])
+	if len(task) > 72 {
+		task = task[:69] + "..."
+	}
+	if task == "" {
+		task = "spawn #" + fmt.Sprint(rec.ID)
+	}
+	return task
+}
+
+// SpawnBody renders a pull request body from a spawn and its final turn's
+// handoff.
+func SpawnBody(rec *store.SpawnRecord, turn *store.Turn) string {
+	var b strings.Builder
+	writeSection(&b, "Task", rec.Task)
+	writeSection(&b, "Summary", rec.Summary)
+	if turn != nil {
+		b.WriteString(handoff(turn, "## Handoff"))
+	}
+	fmt.Fprintf(&b, "---\nOpened by adaf from spawn #%d (%s, branch `%s`).\n", rec.ID, rec.ChildProfile, rec.Branch)
+	return b.String()
+}
+
+// LoopRunBody renders a pull request body from a loop run's turn handoffs.
+func LoopRunBody(run *store.LoopRun, turns []store.Turn) string {
+	var b strings.Builder
+	for i := range turns {
+		t := &turns[i]
+		b.WriteString(handoff(t, fmt.Sprintf("## Turn #%d (%s)", t.ID, t.ProfileName)))
+	}
+	fmt.Fprintf(&b, "---\nOpened by adaf from loop %q run #%d.\n", run.LoopName, run.ID)
+	return b.String()
+}
+
+func handoff(t *store.Turn, heading string) string {
+	var b strings.Builder
+	fields := []struct{ label, value string }{
+		{"Objective", t.Objective},
+		{"What was built", t.WhatWasBuilt},
+		{"Key decisions", t.KeyDecisions},
+		{"Known issues", t.KnownIssues},
+		{"Next steps", t.NextSteps},
+		{"Build state", t.BuildState},
+	}
+	for _, f := range fields {
+		if v := strings.TrimSpace(f.value); v != "" {
+			fmt.Fprintf(&b, "**%s:** %s\n\n", f.label, v)
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return heading + "\n\n" + b.String()
+}
+
+func writeSection(b *strings.Builder, title, text string) {
+	if text = strings.TrimSpace(text); text != "" {
+		fmt.Fprintf(b, "## %s\n\n%s\n\n", title, text)
+	}
+}
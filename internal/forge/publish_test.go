@@ -0,0 +1,354 @@
+package forge
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// fakeGitea is a minimal stand-in for the Gitea pull request API.
+type fakeGitea struct {
+	mu       sync.Mutex
+	created  []map[string]string
+	state    string
+	comments []map[string]any
+	reviews  []map[string]any
+	inline   []map[string]any
+}
+
+func (f *fakeGitea) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	const prefix = "/api/v1/repos/team/app"
+	path := strings.TrimPrefix(r.URL.Path, prefix)
+	pull := map[string]any{"number": 1, "html_url": "http://forge/team/app/pulls/1", "state": f.state}
+	switch {
+	case r.Method == http.MethodPost && path == "/pulls":
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		f.created = append(f.created, req)
+		f.state = "open"
+		pull["state"] = "open"
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(pull)
+	case path == "/pulls/1":
+		json.NewEncoder(w).Encode(pull)
+	case path == "/issues/1/comments":
+		json.NewEncoder(w).Encode(page(f.comments, r))
+	case path == "/pulls/1/reviews":
+		json.NewEncoder(w).Encode(f.reviews)
+	case path == "/pulls/1/reviews/5/comments":
+		json.NewEncoder(w).Encode(f.inline)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// page returns the slice of items selected by the request's Gitea-style
+// limit and page parameters, defaulting like Gitea to the first 30.
+func page(items []map[string]any, r *http.Request) []map[string]any {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	n, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if limit <= 0 {
+		limit = 30
+	}
+	n = max(n, 1)
+	start := min((n-1)*limit, len(items))
+	return items[start:min(start+limit, len(items))]
+}
+
+func TestPublishSpawnAndSyncReviewComments(t *testing.T) {
+	repo, remote := initRepoWithRemote(t)
+	gitRun(t, repo, "checkout", "-b", "adaf/spawn-1")
+	os.WriteFile(filepath.Join(repo, "feature.txt"), []byte("feature\n"), 0644)
+	gitRun(t, repo, "add", "feature.txt")
+	gitRun(t, repo, "commit", "-m", "add feature")
+	gitRun(t, repo, "checkout", "main")
+
+	fake := &fakeGitea{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	s := newTestStore(t, repo, srv.URL)
+	turn := &store.Turn{Agent: "codex", WhatWasBuilt: "Feature flag parser", KnownIssues: "No docs yet"}
+	if err := s.CreateTurn(turn); err != nil {
+		t.Fatalf("CreateTurn: %v", err)
+	}
+	issue := &store.Issue{Title: "Parse flags", Status: "resolved"}
+	if err := s.CreateIssue(issue); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	rec := &store.SpawnRecord{
+		ChildProfile: "dev",
+		Task:         "Implement flag parsing",
+		Branch:       "adaf/spawn-1",
+		Status:       store.SpawnStatusCompleted,
+		Summary:      "Parser added with tests.",
+		ChildTurnID:  turn.ID,
+		IssueIDs:     []int{issue.ID},
+	}
+	if err := s.CreateSpawn(rec); err != nil {
+		t.Fatalf("CreateSpawn: %v", err)
+	}
+
+	pub, err := NewPublisher(s)
+	if err != nil {
+		t.Fatalf("NewPublisher: %v", err)
+	}
+	pr, err := pub.PublishSpawn(t.Context(), rec.ID, "")
+	if err != nil {
+		t.Fatalf("PublishSpawn: %v", err)
+	}
+
+	if got := strings.TrimSpace(gitOut(t, remote, "rev-parse", "adaf/spawn-1")); got != strings.TrimSpace(gitOut(t, repo, "rev-parse", "adaf/spawn-1")) {
+		t.Fatalf("remote branch not pushed, got %q", got)
+	}
+	if len(fake.created) != 1 {
+		t.Fatalf("created %d pull requests, want 1", len(fake.created))
+	}
+	req := fake.created[0]
+	if req["head"] != "adaf/spawn-1" || req["base"] != "main" || req["title"] != "Implement flag parsing" {
+		t.Fatalf("unexpected create request: %v", req)
+	}
+	for _, want := range []string{"Parser added with tests.", "Feature flag parser", "No docs yet"} {
+		if !strings.Contains(req["body"], want) {
+			t.Fatalf("body missing %q:\n%s", want, req["body"])
+		}
+	}
+	if got, _ := s.GetSpawn(rec.ID); got.PullRequestURL != pr.URL {
+		t.Fatalf("spawn PullRequestURL = %q, want %q", got.PullRequestURL, pr.URL)
+	}
+	if _, err := pub.PublishSpawn(t.Context(), rec.ID, ""); err == nil {
+		t.Fatal("expected second publish of the same spawn to fail")
+	}
+
+	now := time.Now().UTC()
+	fake.comments = []map[string]any{{"id": 10, "body": "Looks good overall", "user": map[string]string{"login": "alice"}, "created_at": now}}
+	fake.reviews = []map[string]any{{"id": 5, "body": "Please rename", "state": "REQUEST_CHANGES", "user": map[string]string{"login": "bob"}, "submitted_at": now.Add(time.Second)}}
+	fake.inline = []map[string]any{{"id": 20, "body": "Off by one", "path": "feature.txt", "line": 1, "user": map[string]string{"login": "bob"}, "created_at": now.Add(2 * time.Second)}}
+
+	n, err := pub.Sync(t.Context(), pr)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("Sync new comments = %d, want 3", n)
+	}
+	got, err := s.GetIssue(issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	if got.Status != store.IssueStatusOpen {
+		t.Fatalf("issue status = %q, want reopened", got.Status)
+	}
+	if len(got.Comments) != 3 {
+		t.Fatalf("issue comments = %d, want 3", len(got.Comments))
+	}
+	if !strings.Contains(got.Comments[1].Body, "[REQUEST_CHANGES] Please rename") {
+		t.Fatalf("review comment = %q", got.Comments[1].Body)
+	}
+	if !strings.Contains(got.Comments[2].Body, "on feature.txt:1") || got.Comments[2].By != "bob" {
+		t.Fatalf("inline comment = %+v", got.Comments[2])
+	}
+
+	n, err = pub.Sync(t.Context(), pr)
+	if err != nil {
+		t.Fatalf("second Sync: %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("second Sync new comments = %d, want 0", n)
+	}
+	if got, _ := s.GetIssue(issue.ID); len(got.Comments) != 3 {
+		t.Fatalf("issue comments after resync = %d, want 3", len(got.Comments))
+	}
+}
+
+func TestPublishLoopRunFilesFeedbackIssue(t *testing.T) {
+	repo, remote := initRepoWithRemote(t)
+	fake := &fakeGitea{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	s := newTestStore(t, repo, srv.URL)
+	turn := &store.Turn{Agent: "claude", ProfileName: "lead", NextSteps: "Wire up the CLI"}
+	if err := s.CreateTurn(turn); err != nil {
+		t.Fatalf("CreateTurn: %v", err)
+	}
+	run := &store.LoopRun{LoopName: "dev-cycle", TurnIDs: []int{turn.ID}}
+	if err := s.CreateLoopRun(run); err != nil {
+		t.Fatalf("CreateLoopRun: %v", err)
+	}
+
+	pub, err := NewPublisher(s)
+	if err != nil {
+		t.Fatalf("NewPublisher: %v", err)
+	}
+	if _, err := pub.PublishLoopRun(t.Context(), run.ID, ""); err == nil {
+		t.Fatal("expected publishing a run without a recorded head to fail")
+	}
+
+	// Commits made after the run stopped must not be published with it.
+	os.WriteFile(filepath.Join(repo, "run.txt"), []byte("run\n"), 0644)
+	gitRun(t, repo, "add", "run.txt")
+	gitRun(t, repo, "commit", "-m", "loop run work")
+	run.HeadCommit = strings.TrimSpace(gitOut(t, repo, "rev-parse", "HEAD"))
+	if err := s.UpdateLoopRun(run); err != nil {
+		t.Fatalf("UpdateLoopRun: %v", err)
+	}
+	os.WriteFile(filepath.Join(repo, "later.txt"), []byte("later\n"), 0644)
+	gitRun(t, repo, "add", "later.txt")
+	gitRun(t, repo, "commit", "-m", "unrelated later work")
+
+	pr, err := pub.PublishLoopRun(t.Context(), run.ID, "")
+	if err != nil {
+		t.Fatalf("PublishLoopRun: %v", err)
+	}
+	head := fmt.Sprintf("adaf/loop-run-%d", run.ID)
+	if pr.Head != head || pr.LoopRunID != run.ID {
+		t.Fatalf("pull request = %+v", pr)
+	}
+	if got := strings.TrimSpace(gitOut(t, remote, "rev-parse", head)); got != run.HeadCommit {
+		t.Fatalf("remote %s = %s, want recorded head %s", head, got, run.HeadCommit)
+	}
+	if body := fake.created[0]["body"]; !strings.Contains(body, "Wire up the CLI") || !strings.Contains(fake.created[0]["title"], "dev-cycle") {
+		t.Fatalf("unexpected create request: %v", fake.created[0])
+	}
+
+	fake.comments = []map[string]any{{"id": 1, "body": "Needs a changelog entry", "user": map[string]string{"login": "carol"}, "created_at": time.Now().UTC()}}
+	if _, err := pub.SyncOpen(t.Context()); err != nil {
+		t.Fatalf("SyncOpen: %v", err)
+	}
+	stored, err := s.GetPullRequest(pr.ID)
+	if err != nil {
+		t.Fatalf("GetPullRequest: %v", err)
+	}
+	if len(stored.IssueIDs) != 1 {
+		t.Fatalf("feedback issues = %v, want one", stored.IssueIDs)
+	}
+	issue, err := s.GetIssue(stored.IssueIDs[0])
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	if !strings.HasPrefix(issue.Title, "Review feedback:") || len(issue.Comments) != 1 {
+		t.Fatalf("feedback issue = %+v", issue)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		cfg  *store.ForgeConfig
+		want string
+	}{
+		{nil, "no forge configured"},
+		{&store.ForgeConfig{Type: "bitbucket"}, "unknown forge type"},
+		{&store.ForgeConfig{Type: "gitea", URL: "git.example.com"}, "http(s) URL"},
+		{&store.ForgeConfig{Type: "github", URL: "https://api.github.com"}, "owner and repo"},
+		{&store.ForgeConfig{Type: "github", URL: "https://api.github.com", Owner: "a", Repo: "b"}, ""},
+	}
+	for _, tc := range cases {
+		err := Validate(tc.cfg)
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("Validate(%+v) = %v, want nil", tc.cfg, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Validate(%+v) = %v, want %q", tc.cfg, err, tc.want)
+		}
+	}
+}
+
+func newTestStore(t *testing.T, repo, forgeURL string) *store.Store {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(DefaultTokenEnv, "secret")
+	s, err := store.New(repo)
+	if err != nil {
+		t.Fatalf("store.New: %v", err)
+	}
+	cfg := store.ProjectConfig{
+		Name:     "test",
+		RepoPath: repo,
+		Forge:    &store.ForgeConfig{Type: store.ForgeGitea, URL: forgeURL, Owner: "team", Repo: "app"},
+	}
+	if err := s.Init(cfg); err != nil {
+		t.Fatalf("store.Init: %v", err)
+	}
+	return s
+}
+
+// initRepoWithRemote creates a repo on main with a bare "origin" remote.
+func initRepoWithRemote(t *testing.T) (string, string) {
+	t.Helper()
+	remote := t.TempDir()
+	gitRun(t, remote, "init", "--bare")
+	repo := t.TempDir()
+	gitRun(t, repo, "init")
+	gitRun(t, repo, "checkout", "-b", "main")
+	os.WriteFile(filepath.Join(repo, "main.txt"), []byte("initial\n"), 0644)
+	gitRun(t, repo, "add", "main.txt")
+	gitRun(t, repo, "commit", "-m", "initial commit")
+	gitRun(t, repo, "remote", "add", "origin", remote)
+	return repo, remote
+}
+
+func gitOut(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	_ = gitOut(t, dir, args...)
+}
+
+func TestSyncReadsEveryCommentPage(t *testing.T) {
+	repo, _ := initRepoWithRemote(t)
+	fake := &fakeGitea{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	s := newTestStore(t, repo, srv.URL)
+	issue := &store.Issue{Title: "Busy review", Status: "resolved"}
+	if err := s.CreateIssue(issue); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	pr := &store.PullRequest{Number: 1, URL: "http://forge/team/app/pulls/1", State: "open", IssueIDs: []int{issue.ID}}
+	if err := s.CreatePullRequest(pr); err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	now := time.Now().UTC()
+	for i := range 120 {
+		fake.comments = append(fake.comments, map[string]any{"id": i + 1, "body": fmt.Sprintf("comment %d", i+1), "user": map[string]string{"login": "alice"}, "created_at": now.Add(time.Duration(i) * time.Second)})
+	}
+
+	pub, err := NewPublisher(s)
+	if err != nil {
+		t.Fatalf("NewPublisher: %v", err)
+	}
+	n, err := pub.Sync(t.Context(), pr)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if n != 120 {
+		t.Fatalf("Sync new comments = %d, want 120", n)
+	}
+}
@@ -0,0 +1,264 @@
+package isolation
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/agusx1211/adaf/internal/sandbox"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// Container runs each spawn in its own long-lived container started from a
+// per-project image. Paths are mounted at their host locations so git
+// worktree metadata, recordings and agent state keep working unchanged.
+type Container struct {
+	Runtime       string // podman or docker binary
+	Image         string
+	Containerfile string // absolute path; when set, Image is built from it
+	Context       string // absolute build context
+	RunArgs       []string
+	Mounts        []string // absolute host paths
+	RepoRoot      string
+}
+
+// NewContainer builds the container backend from cfg. It fails when no
+// image is configured or no runtime is installed.
+func NewContainer(cfg *store.IsolationConfig, repoRoot string) (*Container, error) {
+	c := &Container{
+		Runtime:  strings.TrimSpace(cfg.Runtime),
+		Image:    strings.TrimSpace(cfg.Image),
+		RunArgs:  append([]string(nil), cfg.RunArgs...),
+		RepoRoot: repoRoot,
+	}
+	if cf := strings.TrimSpace(cfg.Containerfile); cf != "" {
+		c.Containerfile = filepath.Join(repoRoot, cf)
+		c.Context = filepath.Dir(c.Containerfile)
+		if bc := strings.TrimSpace(cfg.Context); bc != "" {
+			c.Context = filepath.Join(repoRoot, bc)
+		}
+	}
+	if c.Image == "" && c.Containerfile == "" {
+		return nil, fmt.Errorf("container isolation requires image or containerfile")
+	}
+	for _, m := range cfg.Mounts {
+		m = expandHome(strings.TrimSpace(m))
+		if !filepath.IsAbs(m) {
+			return nil, fmt.Errorf("isolation mount %q must be absolute or start with ~/", m)
+		}
+		c.Mounts = append(c.Mounts, m)
+	}
+	if c.Runtime == "" {
+		for _, candidate := range []string{"podman", "docker"} {
+			if _, err := exec.LookPath(candidate); err == nil {
+				c.Runtime = candidate
+				break
+			}
+		}
+		if c.Runtime == "" {
+			return nil, fmt.Errorf("container isolation needs podman or docker in PATH")
+		}
+	}
+	return c, nil
+}
+
+func (c *Container) Name() string { return store.IsolationContainer }
+
+// ContainerName returns the container that runs spawnID of this project.
+func (c *Container) ContainerName(spawnID int) string {
+	return fmt.Sprintf("adaf-%s-spawn-%d", c.projectKey(), spawnID)
+}
+
+func (c *Container) projectKey() string {
+	sum := sha256.Sum256([]byte(c.RepoRoot))
+	return fmt.Sprintf("%x", sum[:4])
+}
+
+func (c *Container) run(ctx context.Context, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, c.Runtime, args...).CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("%s %s: %w: %s", filepath.Base(c.Runtime), args[0], err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// image returns the image to start, building the project's containerfile
+// first unless an image for its current contents already exists.
+func (c *Container) image(ctx context.Context) (string, error) {
+	if c.Containerfile == "" {
+		return c.Image, nil
+	}
+	spec, err := os.ReadFile(c.Containerfile)
+	if err != nil {
+		return "", fmt.Errorf("reading containerfile: %w", err)
+	}
+	sum := sha256.Sum256(spec)
+	tag := fmt.Sprintf("localhost/adaf-%s:%x", c.projectKey(), sum[:6])
+	if _, err := c.run(ctx, "image", "inspect", tag); err == nil {
+		return tag, nil
+	}
+	if _, err := c.run(ctx, "build", "-t", tag, "-f", c.Containerfile, c.Context); err != nil {
+		return "", fmt.Errorf("building spawn image: %w", err)
+	}
+	return tag, nil
+}
+
+// Attach starts the spawn's container, replacing a stale one with the same
+// name, and returns a wrapper that execs agent commands inside it.
+func (c *Container) Attach(ctx context.Context, ws Workspace) (Wrapper, error) {
+	img, err := c.image(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name := c.ContainerName(ws.SpawnID)
+	if err := c.Release(ctx, ws.SpawnID); err != nil {
+		return nil, err
+	}
+
+	home, _ := os.UserHomeDir()
+	args := []string{
+		"run", "-d", "--init",
+		"--name", name,
+		"--label", "adaf.spawn=" + strconv.Itoa(ws.SpawnID),
+		"-w", ws.Dir,
+	}
+	if home != "" {
+		args = append(args, "-e", "HOME="+home)
+	}
+	// Keep files written in the container owned by the caller.
+	if filepath.Base(c.Runtime) == "podman" {
+		args = append(args, "--userns=keep-id")
+	} else {
+		args = append(args, "--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()))
+	}
+	for _, m := range c.mounts(ctx, ws) {
+		args = append(args, "-v", m)
+	}
+	args = append(args, c.RunArgs...)
+	args = append(args, img, "sleep", "infinity")
+	if _, err := c.run(ctx, args...); err != nil {
+		return nil, fmt.Errorf("starting spawn container: %w", err)
+	}
+	return &containerExec{runtime: c.Runtime, name: name, dir: ws.Dir}, nil
+}
+
+// mounts lists volume specs for the worktree, its git metadata, agent state
+// and configured extra paths. The repository's shared git dir is mounted
+// read-only so the container cannot rewrite other branches, hooks or
+// config; only the worktree's own git dir stays writable.
+// Commits are made on the host when the child finishes.
+func (c *Container) mounts(ctx context.Context, ws Workspace) []string {
+	var paths []string
+	add := func(p string) {
+		if p == "" || slices.Contains(paths, p) {
+			return
+		}
+		if _, err := os.Stat(p); err == nil {
+			paths = append(paths, p)
+		}
+	}
+	for _, a := range ws.Agents {
+		for _, p := range sandbox.AgentStatePaths(a) {
+			add(p)
+		}
+	}
+	for _, p := range c.Mounts {
+		add(p)
+	}
+
+	dir := ws.Dir + ":" + ws.Dir
+	if ws.ReadOnly {
+		dir += ":ro"
+	}
+	out := []string{dir}
+	for _, p := range paths {
+		if p != ws.Dir {
+			out = append(out, p+":"+p)
+		}
+	}
+	commonDir := gitPath(ctx, ws.Dir, "--git-common-dir")
+	if commonDir != "" && commonDir != ws.Dir {
+		out = append(out, commonDir+":"+commonDir+":ro")
+	}
+	if gitDir := gitPath(ctx, ws.Dir, "--git-dir"); gitDir != "" && gitDir != commonDir && !ws.ReadOnly {
+		out = append(out, gitDir+":"+gitDir)
+	}
+	return out
+}
+
+// gitPath returns the absolute path git rev-parse reports for flag in dir,
+// or "" when dir is not a git checkout.
+func gitPath(ctx context.Context, dir, flag string) string {
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--path-format=absolute", flag).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Release removes the spawn's container, stopping whatever still runs in
+// it.
+func (c *Container) Release(ctx context.Context, spawnID int) error {
+	name := c.ContainerName(spawnID)
+	if _, err := c.run(ctx, "container", "inspect", name); err != nil {
+		return nil
+	}
+	_, err := c.run(ctx, "rm", "-f", name)
+	return err
+}
+
+// containerExec runs agent commands in a started spawn container.
+type containerExec struct {
+	runtime string
+	name    string
+	dir     string
+}
+
+// Wrap rewrites cmd into "<runtime> exec -i" in the container. Only env is
+// forwarded; the rest of the container environment comes from the image
+// and run_args.
+func (e *containerExec) Wrap(cmd *exec.Cmd, env map[string]string) error {
+	if len(cmd.Args) == 0 {
+		return fmt.Errorf("container exec: empty command")
+	}
+	bin, err := exec.LookPath(e.runtime)
+	if err != nil {
+		return fmt.Errorf("container exec: %w", err)
+	}
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	argv := []string{e.runtime, "exec", "-i", "-w", e.dir}
+	for _, k := range keys {
+		argv = append(argv, "-e", k+"="+env[k])
+	}
+	argv = append(argv, e.name)
+	argv = append(argv, cmd.Args...)
+	cmd.Path = bin
+	cmd.Args = argv
+	// The agent binary need not exist on the host.
+	cmd.Err = nil
+	return nil
+}
+
+func expandHome(p string) string {
+	rest, ok := strings.CutPrefix(p, "~/")
+	if !ok {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return p
+	}
+	return filepath.Join(home, rest)
+}
@@ -0,0 +1,69 @@
+// Package isolation provides the backends that keep spawned children apart.
+//
+// Every child that may write gets its own git worktree. The default
+// backend stops there: children share the host's toolchain, ports and
+// caches. The container backend additionally starts one OCI container per
+// spawn with the worktree mounted at its host path and runs the agent CLI
+// inside it. The container lives as long as the worktree and is removed
+// with it.
+package isolation
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// Workspace is one spawn's checkout handed to a backend.
+type Workspace struct {
+	SpawnID  int
+	Dir      string   // spawn worktree (or the repo root for read-only fallbacks)
+	Agents   []string // agent CLIs that may run in the workspace, primary first
+	ReadOnly bool
+}
+
+// Wrapper rewrites an agent command before it starts.
+type Wrapper interface {
+	Wrap(cmd *exec.Cmd, env map[string]string) error
+}
+
+// Backend isolates spawn workspaces. Attach runs once the worktree exists;
+// Release runs when the worktree is removed.
+type Backend interface {
+	// Name is the backend's config value, recorded on the spawn.
+	Name() string
+	// Attach prepares isolation for ws and returns the wrapper for its agent
+	// commands, or nil when commands run unchanged.
+	Attach(ctx context.Context, ws Workspace) (Wrapper, error)
+	// Release tears down what Attach set up for spawnID. It succeeds when
+	// nothing is left to remove.
+	Release(ctx context.Context, spawnID int) error
+}
+
+// New returns the backend configured for the project at repoRoot. A nil
+// cfg or an empty backend selects the worktree backend.
+func New(cfg *store.IsolationConfig, repoRoot string) (Backend, error) {
+	if cfg == nil {
+		return Worktree{}, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(cfg.Backend)) {
+	case "", store.IsolationWorktree:
+		return Worktree{}, nil
+	case store.IsolationContainer:
+		return NewContainer(cfg, repoRoot)
+	default:
+		return nil, fmt.Errorf("isolation backend must be one of: %s, %s", store.IsolationWorktree, store.IsolationContainer)
+	}
+}
+
+// Worktree is the default backend; the git worktree is all the isolation.
+type Worktree struct{}
+
+func (Worktree) Name() string { return store.IsolationWorktree }
+
+func (Worktree) Attach(context.Context, Workspace) (Wrapper, error) { return nil, nil }
+
+func (Worktree) Release(context.Context, int) error { return nil }
@@ -0,0 +1,206 @@
+package isolation
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// fakeRuntime writes a podman stand-in that logs its arguments, tracks
+// built images and running containers in files, and runs exec'd commands
+// on the host.
+func fakeRuntime(t *testing.T) (runtime, logPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	logPath = filepath.Join(dir, "calls.log")
+	runtime = filepath.Join(dir, "podman")
+	script := `#!/bin/sh
+state=` + dir + `
+echo "$*" >> "$state/calls.log"
+case "$1" in
+image) [ -f "$state/built" ] ;;
+build) touch "$state/built" ;;
+run) touch "$state/running"; echo 0123abcd ;;
+container) [ -f "$state/running" ] ;;
+rm) rm -f "$state/running" ;;
+exec)
+	shift
+	[ "$1" = -i ] && shift
+	[ "$1" = -w ] && { dir=$2; shift 2; }
+	while [ "$1" = -e ]; do export "$2"; shift 2; done
+	shift
+	cd "$dir" && exec "$@"
+	;;
+esac
+`
+	if err := os.WriteFile(runtime, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return runtime, logPath
+}
+
+func readCalls(t *testing.T, logPath string) []string {
+	t.Helper()
+	data, err := os.ReadFile(logPath)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestNew(t *testing.T) {
+	b, err := New(nil, t.TempDir())
+	if err != nil || b.Name() != store.IsolationWorktree {
+		t.Fatalf("New(nil) = %v, %v; want worktree", b, err)
+	}
+	if _, err := New(&store.IsolationConfig{Backend: "vm"}, t.TempDir()); err == nil {
+		t.Fatal("unknown backend was accepted")
+	}
+	if _, err := New(&store.IsolationConfig{Backend: "container", Runtime: "podman"}, t.TempDir()); err == nil {
+		t.Fatal("container backend without an image was accepted")
+	}
+	if _, err := New(&store.IsolationConfig{Backend: "container", Runtime: "podman", Image: "x", Mounts: []string{"rel/path"}}, t.TempDir()); err == nil {
+		t.Fatal("relative mount was accepted")
+	}
+}
+
+func TestContainerLifecycle(t *testing.T) {
+	runtime, logPath := fakeRuntime(t)
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, "Containerfile"), []byte("FROM alpine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	extra := t.TempDir()
+	b, err := New(&store.IsolationConfig{
+		Backend:       "container",
+		Runtime:       runtime,
+		Containerfile: "Containerfile",
+		RunArgs:       []string{"--cpus", "2"},
+		Mounts:        []string{extra},
+	}, repo)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	c := b.(*Container)
+	ctx := context.Background()
+	workDir := t.TempDir()
+
+	w, err := b.Attach(ctx, Workspace{SpawnID: 5, Dir: workDir, Agents: []string{"generic"}, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("Attach: %v", err)
+	}
+	calls := readCalls(t, logPath)
+	var build, run string
+	for _, c := range calls {
+		switch {
+		case strings.HasPrefix(c, "build "):
+			build = c
+		case strings.HasPrefix(c, "run "):
+			run = c
+		}
+	}
+	if !strings.Contains(build, "-f "+filepath.Join(repo, "Containerfile")+" "+repo) {
+		t.Fatalf("build call = %q", build)
+	}
+	for _, want := range []string{
+		"--name " + c.ContainerName(5),
+		"-v " + workDir + ":" + workDir + ":ro",
+		"-v " + extra + ":" + extra,
+		"--cpus 2",
+		"--userns=keep-id",
+		"sleep infinity",
+	} {
+		if !strings.Contains(run, want) {
+			t.Fatalf("run call %q is missing %q", run, want)
+		}
+	}
+
+	// The image for an unchanged containerfile is reused.
+	if _, err := b.Attach(ctx, Workspace{SpawnID: 6, Dir: workDir}); err != nil {
+		t.Fatalf("second Attach: %v", err)
+	}
+	builds := 0
+	for _, c := range readCalls(t, logPath) {
+		if strings.HasPrefix(c, "build ") {
+			builds++
+		}
+	}
+	if builds != 1 {
+		t.Fatalf("builds = %d, want 1", builds)
+	}
+
+	cmd := exec.Command("/not/on/host/agent")
+	cmd.Args = []string{"sh", "-c", `printf '%s %s' "$(pwd)" "$GREETING"`}
+	if err := w.Wrap(cmd, map[string]string{"GREETING": "hi there"}); err != nil {
+		t.Fatalf("Wrap: %v", err)
+	}
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if out.String() != workDir+" hi there" {
+		t.Fatalf("output = %q", out.String())
+	}
+
+	if err := b.Release(ctx, 5); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if last := readCalls(t, logPath); last[len(last)-1] != "rm -f "+c.ContainerName(5) {
+		t.Fatalf("last call = %q, want rm -f", last[len(last)-1])
+	}
+	// Nothing left to remove.
+	if err := b.Release(ctx, 5); err != nil {
+		t.Fatalf("second Release: %v", err)
+	}
+}
+
+func TestContainerMountsSharedGitDirReadOnly(t *testing.T) {
+	runtime, logPath := fakeRuntime(t)
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=t", "-c", "user.email=t@t"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("commit", "-q", "--allow-empty", "-m", "init")
+	worktree := filepath.Join(t.TempDir(), "wt")
+	git("worktree", "add", "-q", "-b", "child", worktree)
+
+	b, err := New(&store.IsolationConfig{Backend: "container", Runtime: runtime, Image: "img"}, repo)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := b.Attach(context.Background(), Workspace{SpawnID: 1, Dir: worktree}); err != nil {
+		t.Fatalf("Attach: %v", err)
+	}
+	var run string
+	for _, c := range readCalls(t, logPath) {
+		if strings.HasPrefix(c, "run ") {
+			run = c
+		}
+	}
+	common := gitPath(context.Background(), worktree, "--git-common-dir")
+	gitDir := gitPath(context.Background(), worktree, "--git-dir")
+	if common == "" || gitDir == common {
+		t.Fatalf("git dirs = %q, %q", common, gitDir)
+	}
+	for _, want := range []string{
+		"-v " + worktree + ":" + worktree + " ",
+		"-v " + common + ":" + common + ":ro",
+		"-v " + gitDir + ":" + gitDir + " ",
+	} {
+		if !strings.Contains(run, want) {
+			t.Fatalf("run call %q is missing %q", run, want)
+		}
+	}
+}
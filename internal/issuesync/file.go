@@ -0,0 +1,141 @@
+package issuesync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// FileProvider keeps external issues in a JSON array on disk. It stands in
+// for a real tracker in tests and lets teams exchange issues as a file.
+type FileProvider struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func (p *FileProvider) load() ([]ExternalIssue, error) {
+	data, err := os.ReadFile(p.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var issues []ExternalIssue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", p.Path, err)
+	}
+	return issues, nil
+}
+
+func (p *FileProvider) save(issues []ExternalIssue) error {
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p.Path), 0755); err != nil {
+		return err
+	}
+	tmp := p.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.Path)
+}
+
+func (p *FileProvider) List(ctx context.Context) ([]ExternalIssue, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.load()
+}
+
+func (p *FileProvider) Get(ctx context.Context, id string) (*ExternalIssue, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	issues, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	for i := range issues {
+		if issues[i].ID == id {
+			return &issues[i], nil
+		}
+	}
+	return nil, fmt.Errorf("external issue %s not found", id)
+}
+
+func (p *FileProvider) Create(ctx context.Context, in ExternalIssue) (*ExternalIssue, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	issues, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	next := 1
+	for _, iss := range issues {
+		if n, err := strconv.Atoi(iss.ID); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	in.ID = strconv.Itoa(next)
+	in.Comments = nil
+	in.Updated = time.Now().UTC()
+	issues = append(issues, in)
+	if err := p.save(issues); err != nil {
+		return nil, err
+	}
+	return &in, nil
+}
+
+func (p *FileProvider) Update(ctx context.Context, in ExternalIssue) (*ExternalIssue, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	issues, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	for i := range issues {
+		if issues[i].ID != in.ID {
+			continue
+		}
+		in.URL = issues[i].URL
+		in.Comments = issues[i].Comments
+		in.Updated = time.Now().UTC()
+		issues[i] = in
+		if err := p.save(issues); err != nil {
+			return nil, err
+		}
+		return &in, nil
+	}
+	return nil, fmt.Errorf("external issue %s not found", in.ID)
+}
+
+func (p *FileProvider) AddComment(ctx context.Context, issueID string, c ExternalComment) (*ExternalComment, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	issues, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	for i := range issues {
+		if issues[i].ID != issueID {
+			continue
+		}
+		now := time.Now().UTC()
+		c.ID = fmt.Sprintf("%s-%d", issueID, len(issues[i].Comments)+1)
+		c.Created = now
+		issues[i].Comments = append(issues[i].Comments, c)
+		issues[i].Updated = now
+		if err := p.save(issues); err != nil {
+			return nil, err
+		}
+		return &c, nil
+	}
+	return nil, fmt.Errorf("external issue %s not found", issueID)
+}
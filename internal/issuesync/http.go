@@ -0,0 +1,105 @@
+package issuesync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// HTTPProvider talks to a JSON REST endpoint exchanging ExternalIssue
+// objects:
+//
+//	GET  /issues               list
+//	GET  /issues/{id}          get
+//	POST /issues               create
+//	PUT  /issues/{id}          update
+//	POST /issues/{id}/comments add a comment
+//
+// Trackers with a different API can be bridged with a small adapter service.
+type HTTPProvider struct {
+	BaseURL string
+	Token   string
+}
+
+func (p *HTTPProvider) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.BaseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if p.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.Token)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: HTTP %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (p *HTTPProvider) List(ctx context.Context) ([]ExternalIssue, error) {
+	var out []ExternalIssue
+	if err := p.do(ctx, http.MethodGet, "/issues", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (p *HTTPProvider) Get(ctx context.Context, id string) (*ExternalIssue, error) {
+	var out ExternalIssue
+	if err := p.do(ctx, http.MethodGet, "/issues/"+url.PathEscape(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (p *HTTPProvider) Create(ctx context.Context, in ExternalIssue) (*ExternalIssue, error) {
+	var out ExternalIssue
+	if err := p.do(ctx, http.MethodPost, "/issues", in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (p *HTTPProvider) Update(ctx context.Context, in ExternalIssue) (*ExternalIssue, error) {
+	var out ExternalIssue
+	if err := p.do(ctx, http.MethodPut, "/issues/"+url.PathEscape(in.ID), in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (p *HTTPProvider) AddComment(ctx context.Context, issueID string, c ExternalComment) (*ExternalComment, error) {
+	var out ExternalComment
+	if err := p.do(ctx, http.MethodPost, "/issues/"+url.PathEscape(issueID)+"/comments", c, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,97 @@
+// Package issuesync keeps the project's issues in sync with an external
+// issue tracker in both directions.
+package issuesync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// Provider is the subset of an external tracker's API the sync engine needs.
+// Status and Priority are in the tracker's own vocabulary; DependsOn lists
+// external issue IDs.
+type Provider interface {
+	List(ctx context.Context) ([]ExternalIssue, error)
+	Get(ctx context.Context, id string) (*ExternalIssue, error)
+	Create(ctx context.Context, in ExternalIssue) (*ExternalIssue, error)
+	Update(ctx context.Context, in ExternalIssue) (*ExternalIssue, error)
+	AddComment(ctx context.Context, issueID string, c ExternalComment) (*ExternalComment, error)
+}
+
+// ExternalIssue is an issue as stored by the external tracker.
+type ExternalIssue struct {
+	ID          string            `json:"id"`
+	URL         string            `json:"url,omitempty"`
+	Title       string            `json:"title"`
+	Description string            `json:"description,omitempty"`
+	Status      string            `json:"status"`
+	Priority    string            `json:"priority,omitempty"`
+	Labels      []string          `json:"labels,omitempty"`
+	DependsOn   []string          `json:"depends_on,omitempty"`
+	Comments    []ExternalComment `json:"comments,omitempty"`
+	Updated     time.Time         `json:"updated"`
+}
+
+// ExternalComment is a comment on an external issue.
+type ExternalComment struct {
+	ID      string    `json:"id"`
+	Author  string    `json:"author,omitempty"`
+	Body    string    `json:"body"`
+	Created time.Time `json:"created"`
+}
+
+// Validate checks that cfg names a supported provider with its settings.
+func Validate(cfg *store.IssueSyncConfig) error {
+	if cfg == nil {
+		return fmt.Errorf("no issue tracker configured (set \"issue_sync\" in .adaf/project.json)")
+	}
+	switch cfg.Type {
+	case store.IssueSyncFile:
+		if strings.TrimSpace(cfg.Path) == "" {
+			return fmt.Errorf("issue_sync.path is required for the file provider")
+		}
+	case store.IssueSyncHTTP:
+		if !strings.HasPrefix(cfg.URL, "http://") && !strings.HasPrefix(cfg.URL, "https://") {
+			return fmt.Errorf("issue_sync.url must be an http(s) URL")
+		}
+	default:
+		return fmt.Errorf("unknown issue sync type %q (valid: %s, %s)", cfg.Type, store.IssueSyncFile, store.IssueSyncHTTP)
+	}
+	switch cfg.Direction {
+	case "", store.IssueSyncBoth, store.IssueSyncPull, store.IssueSyncPush:
+	default:
+		return fmt.Errorf("unknown issue sync direction %q (valid: both, pull, push)", cfg.Direction)
+	}
+	if cfg.IntervalMinutes < 0 {
+		return fmt.Errorf("issue_sync.interval_minutes must not be negative")
+	}
+	return nil
+}
+
+// New returns the provider described by cfg. Relative file paths resolve
+// against projectDir.
+func New(cfg *store.IssueSyncConfig, projectDir string) (Provider, error) {
+	if err := Validate(cfg); err != nil {
+		return nil, err
+	}
+	switch cfg.Type {
+	case store.IssueSyncFile:
+		path := cfg.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectDir, path)
+		}
+		return &FileProvider{Path: path}, nil
+	default:
+		token := ""
+		if cfg.TokenEnv != "" {
+			token = strings.TrimSpace(os.Getenv(cfg.TokenEnv))
+		}
+		return &HTTPProvider{BaseURL: strings.TrimRight(cfg.URL, "/"), Token: token}, nil
+	}
+}
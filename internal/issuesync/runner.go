@@ -0,0 +1,86 @@
+package issuesync
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// DefaultCheckInterval is how often a Runner looks for projects due a sync.
+const DefaultCheckInterval = time.Minute
+
+// Runner syncs every project whose issue_sync config sets interval_minutes,
+// for the web daemon.
+type Runner struct {
+	// Stores returns the project stores to consider.
+	Stores func() []*store.Store
+
+	mu   sync.Mutex
+	last map[string]time.Time // project dir -> last sync attempt
+}
+
+// Run checks for due projects every interval until ctx is done.
+func (r *Runner) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	r.Tick(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.Tick(ctx, now)
+		}
+	}
+}
+
+// Tick syncs every project whose interval has elapsed since its last sync.
+func (r *Runner) Tick(ctx context.Context, now time.Time) {
+	if r.Stores == nil {
+		return
+	}
+	r.mu.Lock()
+	if r.last == nil {
+		r.last = make(map[string]time.Time)
+	}
+	r.mu.Unlock()
+
+	for _, s := range r.Stores() {
+		projCfg, err := s.LoadProject()
+		if err != nil || projCfg.IssueSync == nil || projCfg.IssueSync.IntervalMinutes <= 0 {
+			continue
+		}
+		key := s.ProjectDir()
+		interval := time.Duration(projCfg.IssueSync.IntervalMinutes) * time.Minute
+		r.mu.Lock()
+		due := now.Sub(r.last[key]) >= interval
+		if due {
+			r.last[key] = now
+		}
+		r.mu.Unlock()
+		if !due {
+			continue
+		}
+
+		sy, err := NewSyncer(s)
+		if err != nil {
+			debug.LogKV("issuesync", "invalid issue_sync config", "project", key, "error", err)
+			continue
+		}
+		res, err := sy.Sync(ctx)
+		if err != nil {
+			debug.LogKV("issuesync", "sync failed", "project", key, "error", err)
+			continue
+		}
+		debug.LogKV("issuesync", "sync finished", "project", key,
+			"imported", len(res.Imported), "exported", len(res.Exported),
+			"pulled", res.Pulled, "pushed", res.Pushed, "conflicts", len(res.Conflicts))
+	}
+}
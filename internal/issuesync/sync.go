@@ -0,0 +1,504 @@
+package issuesync
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// syncedFields are the issue fields reconciled with the external tracker,
+// named as in IssueHistory.Field.
+var syncedFields = []string{"title", "description", "status", "priority", "labels", "depends_on"}
+
+// Syncer reconciles a project's issues with one external tracker.
+//
+// Issues are matched through Issue.External. For a linked pair, a field that
+// differs is resolved per side: the local side changed it if the issue's
+// history has an edit to that field after the last sync by someone other
+// than the syncer, and the remote side changed it if its value moved away
+// from the one recorded at the last sync, dated by the external Updated
+// time. When only one side changed, it wins; when both did, the later edit
+// wins and the field is reported as a conflict; when neither did, the local
+// value is pushed. Comments are copied both ways and linked so they are
+// never duplicated.
+type Syncer struct {
+	Store    *store.Store
+	Provider Provider
+	Config   *store.IssueSyncConfig
+}
+
+// Result summarizes one sync run.
+type Result struct {
+	Imported    []int      `json:"imported,omitempty"` // local IDs created from external issues
+	Exported    []int      `json:"exported,omitempty"` // local IDs created on the tracker
+	Pulled      int        `json:"pulled"`             // fields updated locally
+	Pushed      int        `json:"pushed"`             // fields updated on the tracker
+	CommentsIn  int        `json:"comments_in"`
+	CommentsOut int        `json:"comments_out"`
+	Missing     []int      `json:"missing,omitempty"` // linked issues the tracker no longer lists
+	Conflicts   []Conflict `json:"conflicts,omitempty"`
+}
+
+// Conflict is a field edited on both sides since the last sync.
+type Conflict struct {
+	IssueID int    `json:"issue_id"`
+	Field   string `json:"field"`
+	Winner  string `json:"winner"` // "local" or "remote"
+}
+
+// NewSyncer builds a syncer from the project's issue_sync config.
+func NewSyncer(s *store.Store) (*Syncer, error) {
+	projCfg, err := s.LoadProject()
+	if err != nil {
+		return nil, fmt.Errorf("loading project: %w", err)
+	}
+	p, err := New(projCfg.IssueSync, s.ProjectDir())
+	if err != nil {
+		return nil, err
+	}
+	return &Syncer{Store: s, Provider: p, Config: projCfg.IssueSync}, nil
+}
+
+// Actor is recorded on issue edits made by the syncer. Its history entries
+// are not counted as local edits.
+func (sy *Syncer) Actor() string {
+	return "sync:" + sy.Config.Type
+}
+
+func (sy *Syncer) pulls() bool  { return sy.Config.Direction != store.IssueSyncPush }
+func (sy *Syncer) pushes() bool { return sy.Config.Direction != store.IssueSyncPull }
+
+// Sync runs one full reconciliation: it imports unlinked open external
+// issues, exports unlinked open local issues, then syncs every linked pair.
+// It holds the project's issue sync lock throughout, so syncs started by
+// the daemon, the web server and the CLI never overlap.
+func (sy *Syncer) Sync(ctx context.Context) (*Result, error) {
+	unlock, err := sy.Store.LockIssueSync()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	remotes, err := sy.Provider.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing external issues: %w", err)
+	}
+	issues, err := sy.Store.ListIssues()
+	if err != nil {
+		return nil, fmt.Errorf("listing issues: %w", err)
+	}
+
+	res := &Result{}
+	localByExt := make(map[string]int)
+	extByLocal := make(map[int]string)
+	var linked []int
+	link := func(localID int, extID string) {
+		localByExt[extID] = localID
+		extByLocal[localID] = extID
+		linked = append(linked, localID)
+	}
+	for _, issue := range issues {
+		if issue.External != nil && issue.External.Provider == sy.Config.Type {
+			link(issue.ID, issue.External.ID)
+		}
+	}
+
+	if sy.pulls() {
+		for _, remote := range remotes {
+			if _, ok := localByExt[remote.ID]; ok || !store.IsOpenIssueStatus(sy.statusIn(remote.Status)) {
+				continue
+			}
+			issue := &store.Issue{
+				Title:     remote.Title,
+				Status:    store.IssueStatusOpen,
+				CreatedBy: sy.Actor(),
+				External:  &store.IssueExternal{Provider: sy.Config.Type, ID: remote.ID, URL: remote.URL},
+			}
+			if err := sy.Store.CreateIssue(issue); err != nil {
+				return res, fmt.Errorf("importing external issue %s: %w", remote.ID, err)
+			}
+			link(issue.ID, remote.ID)
+			res.Imported = append(res.Imported, issue.ID)
+		}
+	}
+
+	if sy.pushes() {
+		for i := range issues {
+			issue := &issues[i]
+			if issue.External != nil || !store.IsOpenIssueStatus(issue.Status) {
+				continue
+			}
+			out := sy.toExternal(issue, extByLocal)
+			created, err := sy.Provider.Create(ctx, out)
+			if err != nil {
+				return res, fmt.Errorf("exporting issue #%d: %w", issue.ID, err)
+			}
+			ext := &store.IssueExternal{
+				Provider:      sy.Config.Type,
+				ID:            created.ID,
+				URL:           created.URL,
+				SyncedAt:      time.Now().UTC(),
+				RemoteUpdated: created.Updated,
+			}
+			if _, err := sy.Store.EditIssue(issue.ID, func(cur *store.Issue) error {
+				cur.External = ext
+				cur.UpdatedBy = sy.Actor()
+				return nil
+			}); err != nil {
+				return res, err
+			}
+			link(issue.ID, created.ID)
+			remotes = append(remotes, *created)
+			res.Exported = append(res.Exported, issue.ID)
+		}
+	}
+
+	remoteByID := make(map[string]*ExternalIssue, len(remotes))
+	for i := range remotes {
+		remoteByID[remotes[i].ID] = &remotes[i]
+	}
+	sort.Ints(linked)
+	for _, id := range linked {
+		remote, ok := remoteByID[extByLocal[id]]
+		if !ok {
+			res.Missing = append(res.Missing, id)
+			continue
+		}
+		if err := sy.syncIssue(ctx, id, remote, localByExt, extByLocal, res); err != nil {
+			return res, fmt.Errorf("syncing issue #%d: %w", id, err)
+		}
+	}
+	return res, nil
+}
+
+func (sy *Syncer) syncIssue(ctx context.Context, id int, remote *ExternalIssue, localByExt map[string]int, extByLocal map[int]string, res *Result) error {
+	issue, err := sy.Store.GetIssue(id)
+	if err != nil {
+		return err
+	}
+	ext := issue.External
+	remoteDirty := false
+
+	linkedRemote := make(map[string]bool)
+	linkedLocal := make(map[int]bool)
+	for _, l := range ext.Comments {
+		linkedRemote[l.RemoteID] = true
+		linkedLocal[l.LocalID] = true
+	}
+	if sy.pulls() {
+		for _, rc := range remote.Comments {
+			if linkedRemote[rc.ID] || strings.TrimSpace(rc.Body) == "" {
+				continue
+			}
+			author := rc.Author
+			if author == "" {
+				author = sy.Actor()
+			}
+			updated, err := sy.Store.AddIssueComment(id, rc.Body, author)
+			if err != nil {
+				return err
+			}
+			localID := updated.Comments[len(updated.Comments)-1].ID
+			ext.Comments = append(ext.Comments, store.IssueCommentLink{LocalID: localID, RemoteID: rc.ID})
+			linkedLocal[localID] = true
+			res.CommentsIn++
+		}
+		// Reload so the comments just added are not overwritten below.
+		if issue, err = sy.Store.GetIssue(id); err != nil {
+			return err
+		}
+	}
+	if sy.pushes() {
+		for _, lc := range issue.Comments {
+			if linkedLocal[lc.ID] {
+				continue
+			}
+			rc, err := sy.Provider.AddComment(ctx, remote.ID, ExternalComment{Author: lc.By, Body: lc.Body})
+			if err != nil {
+				return err
+			}
+			ext.Comments = append(ext.Comments, store.IssueCommentLink{LocalID: lc.ID, RemoteID: rc.ID})
+			remoteDirty = true
+			res.CommentsOut++
+		}
+	}
+
+	localAt := sy.localEdits(issue.History, ext.SyncedAt)
+	remoteMoved := remote.Updated.After(ext.RemoteUpdated)
+	local := sy.toExternal(issue, extByLocal)
+	out := *remote
+	changedOut := false
+	var pulled []string
+	for _, field := range syncedFields {
+		lv, rv := fieldValue(&local, field), fieldValue(remote, field)
+		if field == "depends_on" {
+			rv = fieldValue(&ExternalIssue{DependsOn: knownDeps(remote.DependsOn, localByExt)}, field)
+		}
+		if lv == rv {
+			continue
+		}
+		// The tracker only reports one update time, so a field counts as
+		// changed remotely when it differs from the value seen last sync.
+		var remoteAt time.Time
+		if base, ok := ext.Remote[field]; remoteMoved && (!ok || base != fieldValue(remote, field)) {
+			remoteAt = remote.Updated
+		}
+		la := localAt[field]
+		remoteWins := !remoteAt.IsZero() && (la.IsZero() || remoteAt.After(la))
+		if !la.IsZero() && !remoteAt.IsZero() {
+			winner := "local"
+			if remoteWins {
+				winner = "remote"
+			}
+			res.Conflicts = append(res.Conflicts, Conflict{IssueID: id, Field: field, Winner: winner})
+		}
+		switch {
+		case remoteWins && sy.pulls():
+			sy.pullField(issue, remote, field, localByExt)
+			pulled = append(pulled, field)
+			res.Pulled++
+		case !remoteWins && sy.pushes():
+			pushField(&out, &local, field, localByExt)
+			changedOut = true
+			res.Pushed++
+		}
+	}
+
+	if changedOut {
+		updated, err := sy.Provider.Update(ctx, out)
+		if err != nil {
+			return err
+		}
+		remote = updated
+	} else if remoteDirty {
+		if remote, err = sy.Provider.Get(ctx, remote.ID); err != nil {
+			return err
+		}
+	}
+
+	if remote.URL != "" {
+		ext.URL = remote.URL
+	}
+	ext.RemoteUpdated = remote.Updated
+	ext.Remote = make(map[string]string, len(syncedFields))
+	for _, field := range syncedFields {
+		ext.Remote[field] = fieldValue(remote, field)
+	}
+	ext.SyncedAt = time.Now().UTC()
+
+	// The tracker calls above can take a while; write back only what the
+	// sync decided, over a fresh copy, so local edits made meanwhile stay.
+	_, err = sy.Store.EditIssue(id, func(cur *store.Issue) error {
+		for _, field := range pulled {
+			copySyncedField(cur, issue, field)
+		}
+		cur.External = ext
+		cur.UpdatedBy = sy.Actor()
+		return nil
+	})
+	return err
+}
+
+// copySyncedField copies one synced field from src to dst.
+func copySyncedField(dst, src *store.Issue, field string) {
+	switch field {
+	case "title":
+		dst.Title = src.Title
+	case "description":
+		dst.Description = src.Description
+	case "status":
+		dst.Status = src.Status
+	case "priority":
+		dst.Priority = src.Priority
+	case "labels":
+		dst.Labels = src.Labels
+	case "depends_on":
+		dst.DependsOn = src.DependsOn
+	}
+}
+
+// localEdits returns, per synced field, the latest edit recorded in history
+// after since by anyone but a syncer.
+func (sy *Syncer) localEdits(history []store.IssueHistory, since time.Time) map[string]time.Time {
+	edits := make(map[string]time.Time)
+	for _, h := range history {
+		if h.Field == "" || !h.At.After(since) || strings.HasPrefix(h.By, "sync:") {
+			continue
+		}
+		if h.At.After(edits[h.Field]) {
+			edits[h.Field] = h.At
+		}
+	}
+	return edits
+}
+
+// toExternal renders an issue in the tracker's vocabulary. Dependencies on
+// issues not linked to the tracker are left out.
+func (sy *Syncer) toExternal(issue *store.Issue, extByLocal map[int]string) ExternalIssue {
+	out := ExternalIssue{
+		Title:       issue.Title,
+		Description: issue.Description,
+		Status:      sy.statusOut(issue.Status),
+		Priority:    sy.priorityOut(issue.Priority),
+		Labels:      append([]string(nil), issue.Labels...),
+	}
+	if issue.External != nil {
+		out.ID = issue.External.ID
+	}
+	for _, dep := range issue.DependsOn {
+		if extID, ok := extByLocal[dep]; ok {
+			out.DependsOn = append(out.DependsOn, extID)
+		}
+	}
+	return out
+}
+
+func (sy *Syncer) pullField(issue *store.Issue, remote *ExternalIssue, field string, localByExt map[string]int) {
+	switch field {
+	case "title":
+		issue.Title = remote.Title
+	case "description":
+		issue.Description = remote.Description
+	case "status":
+		issue.Status = sy.statusIn(remote.Status)
+	case "priority":
+		issue.Priority = sy.priorityIn(remote.Priority)
+	case "labels":
+		issue.Labels = append([]string(nil), remote.Labels...)
+	case "depends_on":
+		// Keep dependencies the tracker cannot know about.
+		var deps []int
+		for _, dep := range issue.DependsOn {
+			if !dependsOnLinked(dep, localByExt) {
+				deps = append(deps, dep)
+			}
+		}
+		for _, extID := range remote.DependsOn {
+			if localID, ok := localByExt[extID]; ok {
+				deps = append(deps, localID)
+			}
+		}
+		if valid, err := sy.Store.ValidateIssueDependencies(issue.ID, deps); err == nil {
+			issue.DependsOn = valid
+		}
+	}
+}
+
+func pushField(out, local *ExternalIssue, field string, localByExt map[string]int) {
+	switch field {
+	case "title":
+		out.Title = local.Title
+	case "description":
+		out.Description = local.Description
+	case "status":
+		out.Status = local.Status
+	case "priority":
+		out.Priority = local.Priority
+	case "labels":
+		out.Labels = local.Labels
+	case "depends_on":
+		// Keep dependencies adaf cannot resolve.
+		deps := append([]string(nil), local.DependsOn...)
+		for _, extID := range out.DependsOn {
+			if _, ok := localByExt[extID]; !ok {
+				deps = append(deps, extID)
+			}
+		}
+		out.DependsOn = deps
+	}
+}
+
+func dependsOnLinked(localID int, localByExt map[string]int) bool {
+	for _, id := range localByExt {
+		if id == localID {
+			return true
+		}
+	}
+	return false
+}
+
+func knownDeps(deps []string, localByExt map[string]int) []string {
+	var out []string
+	for _, d := range deps {
+		if _, ok := localByExt[d]; ok {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// fieldValue returns a comparable rendering of one field.
+func fieldValue(iss *ExternalIssue, field string) string {
+	switch field {
+	case "title":
+		return strings.TrimSpace(iss.Title)
+	case "description":
+		return strings.TrimSpace(iss.Description)
+	case "status":
+		return iss.Status
+	case "priority":
+		return iss.Priority
+	case "labels":
+		return joinSorted(iss.Labels)
+	case "depends_on":
+		return joinSorted(iss.DependsOn)
+	}
+	return ""
+}
+
+func joinSorted(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, "\n")
+}
+
+var (
+	issueStatuses   = []string{store.IssueStatusOpen, store.IssueStatusOngoing, store.IssueStatusInReview, store.IssueStatusClosed}
+	issuePriorities = []string{"critical", "high", "medium", "low"}
+)
+
+func (sy *Syncer) statusOut(status string) string {
+	status = store.NormalizeIssueStatus(status)
+	if v, ok := sy.Config.StatusMap[status]; ok {
+		return v
+	}
+	return status
+}
+
+// statusIn maps a tracker status back to the first adaf status that maps to
+// it, falling back to adaf's own status aliases and then to open.
+func (sy *Syncer) statusIn(status string) string {
+	for _, st := range issueStatuses {
+		if sy.statusOut(st) == status {
+			return st
+		}
+	}
+	if st := store.NormalizeIssueStatus(status); store.IsValidIssueStatus(st) {
+		return st
+	}
+	return store.IssueStatusOpen
+}
+
+func (sy *Syncer) priorityOut(priority string) string {
+	priority = store.NormalizeIssuePriority(priority)
+	if v, ok := sy.Config.PriorityMap[priority]; ok {
+		return v
+	}
+	return priority
+}
+
+func (sy *Syncer) priorityIn(priority string) string {
+	for _, p := range issuePriorities {
+		if sy.priorityOut(p) == priority {
+			return p
+		}
+	}
+	if p := store.NormalizeIssuePriority(priority); store.IsValidIssuePriority(p) {
+		return p
+	}
+	return "medium"
+}
@@ -0,0 +1,397 @@
+package issuesync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func newSyncFixture(t *testing.T, seed []ExternalIssue) (*store.Store, *Syncer, *FileProvider) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	s, err := store.New(dir)
+	if err != nil {
+		t.Fatalf("store.New: %v", err)
+	}
+	cfg := &store.IssueSyncConfig{
+		Type:        store.IssueSyncFile,
+		Path:        "tracker.json",
+		StatusMap:   map[string]string{"open": "todo", "ongoing": "doing", "in_review": "doing", "closed": "done"},
+		PriorityMap: map[string]string{"high": "P1", "medium": "P2"},
+	}
+	if err := s.Init(store.ProjectConfig{Name: "test", RepoPath: dir, IssueSync: cfg}); err != nil {
+		t.Fatalf("store.Init: %v", err)
+	}
+	fp := &FileProvider{Path: filepath.Join(dir, "tracker.json")}
+	if len(seed) > 0 {
+		if err := fp.save(seed); err != nil {
+			t.Fatalf("seeding tracker: %v", err)
+		}
+	}
+	sy, err := NewSyncer(s)
+	if err != nil {
+		t.Fatalf("NewSyncer: %v", err)
+	}
+	sy.Provider = fp
+	return s, sy, fp
+}
+
+func mustSync(t *testing.T, sy *Syncer) *Result {
+	t.Helper()
+	res, err := sy.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	return res
+}
+
+func TestSyncImportsAndExports(t *testing.T) {
+	s, sy, fp := newSyncFixture(t, []ExternalIssue{{
+		ID:       "1",
+		Title:    "Remote bug",
+		Status:   "todo",
+		Priority: "P1",
+		Labels:   []string{"bug"},
+		Comments: []ExternalComment{{ID: "c1", Author: "ann", Body: "repro steps"}},
+		Updated:  time.Now().UTC().Add(-time.Hour),
+	}, {
+		ID: "9", Title: "Old remote", Status: "done", Updated: time.Now().UTC().Add(-time.Hour),
+	}})
+	ctx := context.Background()
+
+	dep := &store.Issue{Title: "Local dependency", CreatedBy: "human"}
+	if err := s.CreateIssue(dep); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	local := &store.Issue{Title: "Local task", Status: "ongoing", DependsOn: []int{dep.ID}, CreatedBy: "human"}
+	if err := s.CreateIssue(local); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	if _, err := s.AddIssueComment(local.ID, "started on it", "dev"); err != nil {
+		t.Fatalf("AddIssueComment: %v", err)
+	}
+
+	res := mustSync(t, sy)
+	if len(res.Imported) != 1 || len(res.Exported) != 2 {
+		t.Fatalf("imported %v, exported %v; want 1 and 2", res.Imported, res.Exported)
+	}
+	if res.CommentsIn != 1 || res.CommentsOut != 1 {
+		t.Fatalf("comments in/out = %d/%d, want 1/1", res.CommentsIn, res.CommentsOut)
+	}
+
+	imported, err := s.GetIssue(res.Imported[0])
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	if imported.Title != "Remote bug" || imported.Status != store.IssueStatusOpen || imported.Priority != "high" {
+		t.Fatalf("imported issue = %+v", imported)
+	}
+	if len(imported.Labels) != 1 || imported.Labels[0] != "bug" {
+		t.Fatalf("imported labels = %v", imported.Labels)
+	}
+	if len(imported.Comments) != 1 || imported.Comments[0].Body != "repro steps" || imported.Comments[0].By != "ann" {
+		t.Fatalf("imported comments = %+v", imported.Comments)
+	}
+	if imported.External == nil || imported.External.ID != "1" {
+		t.Fatalf("imported link = %+v", imported.External)
+	}
+
+	exported, err := s.GetIssue(local.ID)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	remote, err := fp.Get(ctx, exported.External.ID)
+	if err != nil {
+		t.Fatalf("Get exported: %v", err)
+	}
+	depRemoteID := mustIssue(t, s, dep.ID).External.ID
+	if remote.Title != "Local task" || remote.Status != "doing" || remote.Priority != "P2" {
+		t.Fatalf("exported remote = %+v", remote)
+	}
+	if len(remote.DependsOn) != 1 || remote.DependsOn[0] != depRemoteID {
+		t.Fatalf("exported depends_on = %v, want [%s]", remote.DependsOn, depRemoteID)
+	}
+	if len(remote.Comments) != 1 || remote.Comments[0].Body != "started on it" || remote.Comments[0].Author != "dev" {
+		t.Fatalf("exported comments = %+v", remote.Comments)
+	}
+
+	res = mustSync(t, sy)
+	if len(res.Imported)+len(res.Exported) != 0 || res.Pulled != 0 || res.Pushed != 0 || res.CommentsIn != 0 || res.CommentsOut != 0 {
+		t.Fatalf("second sync should be a no-op, got %+v", res)
+	}
+}
+
+func TestSyncResolvesFieldsBySide(t *testing.T) {
+	s, sy, fp := newSyncFixture(t, nil)
+	ctx := context.Background()
+
+	issue := &store.Issue{Title: "Ship it", Priority: "medium", CreatedBy: "human"}
+	if err := s.CreateIssue(issue); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	mustSync(t, sy)
+	extID := mustIssue(t, s, issue.ID).External.ID
+
+	// Local edits the title, the tracker edits the priority.
+	time.Sleep(5 * time.Millisecond)
+	cur := mustIssue(t, s, issue.ID)
+	cur.Title = "Ship it today"
+	cur.UpdatedBy = "human"
+	if err := s.UpdateIssue(cur); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	remote, _ := fp.Get(ctx, extID)
+	remote.Priority = "P1"
+	if _, err := fp.Update(ctx, *remote); err != nil {
+		t.Fatalf("remote Update: %v", err)
+	}
+
+	res := mustSync(t, sy)
+	if res.Pulled != 1 || res.Pushed != 1 || len(res.Conflicts) != 0 {
+		t.Fatalf("result = %+v, want one pull, one push, no conflicts", res)
+	}
+	if got := mustIssue(t, s, issue.ID); got.Priority != "high" || got.Title != "Ship it today" {
+		t.Fatalf("local = %q/%q", got.Title, got.Priority)
+	}
+	if remote, _ = fp.Get(ctx, extID); remote.Title != "Ship it today" || remote.Priority != "P1" {
+		t.Fatalf("remote = %q/%q", remote.Title, remote.Priority)
+	}
+
+	// Both sides change the status; the later edit (remote) wins.
+	time.Sleep(5 * time.Millisecond)
+	cur = mustIssue(t, s, issue.ID)
+	cur.Status = store.IssueStatusInReview
+	cur.UpdatedBy = "human"
+	if err := s.UpdateIssue(cur); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	remote.Status = "done"
+	if _, err := fp.Update(ctx, *remote); err != nil {
+		t.Fatalf("remote Update: %v", err)
+	}
+
+	res = mustSync(t, sy)
+	if len(res.Conflicts) != 1 || res.Conflicts[0].Field != "status" || res.Conflicts[0].Winner != "remote" {
+		t.Fatalf("conflicts = %+v, want status won by remote", res.Conflicts)
+	}
+	if got := mustIssue(t, s, issue.ID); got.Status != store.IssueStatusClosed {
+		t.Fatalf("local status = %q, want closed", got.Status)
+	}
+	last := mustIssue(t, s, issue.ID).History
+	if h := last[len(last)-1]; h.Field != "status" || h.By != sy.Actor() {
+		t.Fatalf("last history entry = %+v, want status change by %s", h, sy.Actor())
+	}
+}
+
+func TestSyncDirectionPullOnly(t *testing.T) {
+	s, sy, fp := newSyncFixture(t, []ExternalIssue{{ID: "1", Title: "From tracker", Status: "todo", Updated: time.Now().UTC()}})
+	sy.Config.Direction = store.IssueSyncPull
+
+	local := &store.Issue{Title: "Local only", CreatedBy: "human"}
+	if err := s.CreateIssue(local); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	res := mustSync(t, sy)
+	if len(res.Imported) != 1 || len(res.Exported) != 0 {
+		t.Fatalf("imported %v, exported %v", res.Imported, res.Exported)
+	}
+	remotes, _ := fp.List(context.Background())
+	if len(remotes) != 1 {
+		t.Fatalf("tracker has %d issues, want 1", len(remotes))
+	}
+}
+
+// hookProvider wraps a provider, counting creates and running onUpdate
+// before each tracker update.
+type hookProvider struct {
+	Provider
+	mu       sync.Mutex
+	creates  int
+	onUpdate func()
+}
+
+func (p *hookProvider) Create(ctx context.Context, in ExternalIssue) (*ExternalIssue, error) {
+	p.mu.Lock()
+	p.creates++
+	p.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	return p.Provider.Create(ctx, in)
+}
+
+func (p *hookProvider) Update(ctx context.Context, in ExternalIssue) (*ExternalIssue, error) {
+	if p.onUpdate != nil {
+		p.onUpdate()
+	}
+	return p.Provider.Update(ctx, in)
+}
+
+func TestSyncKeepsLocalEditsMadeDuringSync(t *testing.T) {
+	s, sy, fp := newSyncFixture(t, nil)
+
+	issue := &store.Issue{Title: "Ship it", CreatedBy: "human"}
+	if err := s.CreateIssue(issue); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	mustSync(t, sy)
+
+	time.Sleep(5 * time.Millisecond)
+	cur := mustIssue(t, s, issue.ID)
+	cur.Title = "Ship it today"
+	cur.UpdatedBy = "human"
+	if err := s.UpdateIssue(cur); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+
+	// A local edit lands while the title is being pushed to the tracker.
+	sy.Provider = &hookProvider{Provider: fp, onUpdate: func() {
+		cur := mustIssue(t, s, issue.ID)
+		cur.Description = "edited during sync"
+		cur.UpdatedBy = "human"
+		if err := s.UpdateIssue(cur); err != nil {
+			t.Errorf("UpdateIssue: %v", err)
+		}
+	}}
+	mustSync(t, sy)
+
+	got := mustIssue(t, s, issue.ID)
+	if got.Description != "edited during sync" || got.Title != "Ship it today" {
+		t.Fatalf("local = %q/%q, want the concurrent edit kept", got.Title, got.Description)
+	}
+}
+
+func TestConcurrentSyncsDoNotDuplicateExports(t *testing.T) {
+	s, sy, fp := newSyncFixture(t, nil)
+	if err := s.CreateIssue(&store.Issue{Title: "Export me", CreatedBy: "human"}); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	hp := &hookProvider{Provider: fp}
+	sy.Provider = hp
+	other := &Syncer{Store: s, Provider: hp, Config: sy.Config}
+
+	var wg sync.WaitGroup
+	for _, syncer := range []*Syncer{sy, other} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := syncer.Sync(context.Background()); err != nil {
+				t.Errorf("Sync: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if hp.creates != 1 {
+		t.Fatalf("tracker creates = %d, want 1", hp.creates)
+	}
+}
+
+func TestHTTPProvider(t *testing.T) {
+	backing := &FileProvider{Path: filepath.Join(t.TempDir(), "issues.json")}
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		ctx := r.Context()
+		var out any
+		var err error
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		switch {
+		case r.Method == http.MethodGet && len(parts) == 1:
+			out, err = backing.List(ctx)
+		case r.Method == http.MethodPost && len(parts) == 1:
+			var in ExternalIssue
+			json.NewDecoder(r.Body).Decode(&in)
+			out, err = backing.Create(ctx, in)
+		case r.Method == http.MethodGet && len(parts) == 2:
+			out, err = backing.Get(ctx, parts[1])
+		case r.Method == http.MethodPut && len(parts) == 2:
+			var in ExternalIssue
+			json.NewDecoder(r.Body).Decode(&in)
+			out, err = backing.Update(ctx, in)
+		case r.Method == http.MethodPost && len(parts) == 3:
+			var in ExternalComment
+			json.NewDecoder(r.Body).Decode(&in)
+			out, err = backing.AddComment(ctx, parts[1], in)
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	t.Setenv("TRACKER_TOKEN", "s3cret")
+	p, err := New(&store.IssueSyncConfig{Type: store.IssueSyncHTTP, URL: srv.URL + "/", TokenEnv: "TRACKER_TOKEN"}, "")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx := context.Background()
+	created, err := p.Create(ctx, ExternalIssue{Title: "Over HTTP", Status: "open"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if gotAuth != "Bearer s3cret" {
+		t.Fatalf("Authorization = %q", gotAuth)
+	}
+	if _, err := p.AddComment(ctx, created.ID, ExternalComment{Body: "hello"}); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	got, err := p.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Title != "Over HTTP" || len(got.Comments) != 1 {
+		t.Fatalf("Get = %+v", got)
+	}
+	if _, err := p.Get(ctx, "404"); err == nil {
+		t.Fatal("expected error for unknown issue")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		cfg  *store.IssueSyncConfig
+		want string
+	}{
+		{nil, "no issue tracker configured"},
+		{&store.IssueSyncConfig{Type: "jira"}, "unknown issue sync type"},
+		{&store.IssueSyncConfig{Type: "file"}, "path is required"},
+		{&store.IssueSyncConfig{Type: "http", URL: "localhost:9000"}, "http(s) URL"},
+		{&store.IssueSyncConfig{Type: "file", Path: "x.json", Direction: "sideways"}, "unknown issue sync direction"},
+		{&store.IssueSyncConfig{Type: "http", URL: "http://localhost:9000", Direction: "pull"}, ""},
+	}
+	for _, tc := range cases {
+		err := Validate(tc.cfg)
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("Validate(%+v) = %v, want nil", tc.cfg, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Validate(%+v) = %v, want %q", tc.cfg, err, tc.want)
+		}
+	}
+}
+
+func mustIssue(t *testing.T, s *store.Store, id int) *store.Issue {
+	t.Helper()
+	issue, err := s.GetIssue(id)
+	if err != nil {
+		t.Fatalf("GetIssue(%d): %v", id, err)
+	}
+	return issue
+}
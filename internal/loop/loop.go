@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/config"
 	"github.com/agusx1211/adaf/internal/debug"
 	"github.com/agusx1211/adaf/internal/hexid"
 	"github.com/agusx1211/adaf/internal/recording"
@@ -38,6 +39,9 @@ type WaitResult struct {
 	// Review marks a periodic running-spawn checkpoint (not a completion).
 	Review bool
 
+	// QueuePosition is set while the spawn waits for a concurrency slot.
+	QueuePosition int
+
 	// Health metrics populated for review checkpoints.
 	Elapsed           time.Duration
 	CompactionCount   int
@@ -53,6 +57,25 @@ const InterruptMessageCallSupervisor = "__adaf_control:call_supervisor__"
 
 var ErrStepEndedByControlSignal = errors.New("step ended by control signal")
 
+// Fallback is an alternate profile a Loop switches to when a turn fails in
+// a way another provider may not (see agent.ClassifyFailure).
+type Fallback struct {
+	ProfileName string
+	Agent       agent.Agent
+	// Config carries the fallback's launch settings (Name, Command, Args,
+	// Env, Sandbox). Runtime fields such as the prompt, workdir and output
+	// sinks are kept from the loop's current Config.
+	Config agent.Config
+}
+
+// pendingFailover carries a failed turn's prompt to its re-run.
+type pendingFailover struct {
+	fromTurnID int
+	reason     string
+	prompt     string
+	resume     bool
+}
+
 // Loop is the main agent loop controller. It runs an agent one or more times,
 // creating turn recordings in the store. Normal iterations create new turns;
 // wait-for-spawns resumes continue on the same turn.
@@ -64,6 +87,9 @@ type Loop struct {
 	// ProfileName is the name of the profile that launched this loop.
 	ProfileName string
 
+	// GlobalCfg prices turns whose agent reports no cost in profile stats.
+	GlobalCfg *config.GlobalConfig
+
 	// PlanID tracks the plan context for this loop.
 	PlanID string
 
@@ -111,6 +137,17 @@ type Loop struct {
 	// (e.g. orchestrator) can inspect the child's output.
 	LastResult *agent.Result
 
+	// Fallbacks are tried in order when a turn fails with a quota, auth or
+	// crash error: the failed turn's prompt is re-run as a new turn on the
+	// next fallback, which then stays active for the rest of the loop.
+	Fallbacks []Fallback
+
+	// OnFailover is called after the loop switches to a fallback profile.
+	OnFailover func(failedTurnID int, from, to, reason string)
+
+	// failover is set between a failed turn and its re-run.
+	failover *pendingFailover
+
 	// lastAgentSessionID holds the session/thread ID from the last agent
 	// run, used to resume the session on the next turn (e.g. after wait-for-spawns).
 	lastAgentSessionID string
@@ -231,6 +268,10 @@ func (l *Loop) Run(ctx context.Context) error {
 				LoopRunHexID: l.LoopRunHexID,
 				StepHexID:    l.StepHexID,
 			}
+			if l.failover != nil {
+				turnLog.FailoverFromTurnID = l.failover.fromTurnID
+				turnLog.FailoverReason = l.failover.reason
+			}
 			if err := l.Store.CreateTurn(turnLog); err != nil {
 				return fmt.Errorf("creating turn: %w", err)
 			}
@@ -284,13 +325,32 @@ func (l *Loop) Run(ctx context.Context) error {
 			if projectDir != "" {
 				cfg.Env["ADAF_PROJECT_DIR"] = projectDir
 			}
+			// Sandboxed agents still record notes, issues and spawns
+			// through the adaf CLI, which writes to the project store.
+			if cfg.Sandbox != nil {
+				cfg.Sandbox = cfg.Sandbox.Clone()
+				cfg.Sandbox.AllowWrite(l.Store.Root())
+			}
 		}
 		// Determine if we're resuming a previous agent session.
 		isResume := l.lastAgentSessionID != ""
 
 		debug.LogKV("loop", "turn mode", "turn_id", turnID, "is_resume", isResume)
 
-		if isResume {
+		failover := l.failover
+		l.failover = nil
+		if failover != nil {
+			// Re-run the failed turn's prompt. A continuation message means
+			// nothing to a fresh session, so it follows the full prompt.
+			cfg.Prompt = failover.prompt
+			if failover.resume {
+				full := l.Config.Prompt
+				if l.PromptFunc != nil {
+					full = l.PromptFunc(turnID)
+				}
+				cfg.Prompt = full + "\n## Continuation\n\n" + failover.prompt
+			}
+		} else if isResume {
 			// When resuming, the agent already has the full system prompt
 			// and conversation context from the previous turn. Send only
 			// new information (wait results and interrupt messages)
@@ -348,6 +408,9 @@ func (l *Loop) Run(ctx context.Context) error {
 		if l.StepHexID != "" {
 			rec.RecordMeta("step_hex_id", l.StepHexID)
 		}
+		if failover != nil {
+			rec.RecordMeta("failover_from_turn_id", fmt.Sprintf("%d", failover.fromTurnID))
+		}
 
 		// Create a turn-scoped context so external code can cancel just the
 		// current turn without stopping the entire loop.
@@ -516,6 +579,18 @@ func (l *Loop) Run(ctx context.Context) error {
 			"run_error", runErr != nil,
 		)
 
+		// Fail over when the turn failed in a way the next profile may not.
+		// Pending interrupts and cancellations are not failures.
+		var (
+			fallback *Fallback
+			failure  string
+		)
+		if !waitingForSpawns && len(l.Fallbacks) > 0 && ctx.Err() == nil && len(l.InterruptCh) == 0 {
+			if failure = agent.ClassifyFailure(result, runErr); failure != "" {
+				fallback = &l.Fallbacks[0]
+			}
+		}
+
 		// Refresh the latest turn snapshot before final metadata write so we
 		// never clobber handoff fields an agent updated during the turn.
 		if latestTurn, err := l.Store.GetTurn(turnID); err != nil {
@@ -550,6 +625,10 @@ func (l *Loop) Run(ctx context.Context) error {
 		} else {
 			turnLog.BuildState = "error"
 		}
+		if fallback != nil {
+			turnLog.FailedOverTo = fallback.ProfileName
+			turnLog.FailoverReason = failure
+		}
 		if !waitingForSpawns && turnLog.FinalizedAt.IsZero() {
 			turnLog.FinalizedAt = time.Now().UTC()
 		}
@@ -583,6 +662,12 @@ func (l *Loop) Run(ctx context.Context) error {
 			return flushRunErr
 		}
 
+		if fallback != nil {
+			l.switchToFallback(turnID, cfg.Prompt, isResume, failure)
+			// The re-run replaces this turn and does not count toward MaxTurns.
+			continue
+		}
+
 		// Check for wait-for-spawns signal first. This is turn control flow,
 		// not a terminal error condition.
 		if waitingForSpawns {
@@ -692,7 +777,7 @@ func (l *Loop) Run(ctx context.Context) error {
 
 		// Update profile stats from a fully completed turn.
 		if l.ProfileName != "" {
-			if err := stats.UpdateProfileStats(l.Store, l.ProfileName, turnID); err != nil {
+			if err := stats.UpdateProfileStats(l.Store, l.GlobalCfg, l.ProfileName, turnID); err != nil {
 				debug.LogKV("loop", "profile stats update failed",
 					"turn_id", turnID,
 					"profile", l.ProfileName,
@@ -719,6 +804,45 @@ func (l *Loop) Run(ctx context.Context) error {
 	}
 }
 
+// switchToFallback makes the next fallback the active profile and queues a
+// re-run of the failed turn's prompt on it.
+func (l *Loop) switchToFallback(failedTurnID int, prompt string, resume bool, reason string) {
+	fb := l.Fallbacks[0]
+	l.Fallbacks = l.Fallbacks[1:]
+	from := l.ProfileName
+
+	cfg := fb.Config
+	cfg.WorkDir = l.Config.WorkDir
+	cfg.Prompt = l.Config.Prompt
+	cfg.MaxTurns = l.Config.MaxTurns
+	cfg.EventSink = l.Config.EventSink
+	cfg.Stdout = l.Config.Stdout
+	cfg.Stderr = l.Config.Stderr
+	// Agent sessions do not carry across providers.
+	cfg.ResumeSessionID = ""
+	l.lastAgentSessionID = ""
+
+	l.Agent = fb.Agent
+	l.Config = cfg
+	l.ProfileName = fb.ProfileName
+	l.failover = &pendingFailover{
+		fromTurnID: failedTurnID,
+		reason:     reason,
+		prompt:     prompt,
+		resume:     resume,
+	}
+	debug.LogKV("loop", "failing over to fallback profile",
+		"turn_id", failedTurnID,
+		"from", from,
+		"to", fb.ProfileName,
+		"reason", reason,
+		"remaining_fallbacks", len(l.Fallbacks),
+	)
+	if l.OnFailover != nil {
+		l.OnFailover(failedTurnID, from, fb.ProfileName, reason)
+	}
+}
+
 func resultExitCode(result *agent.Result) int {
 	if result == nil {
 		return -1
@@ -782,6 +906,13 @@ func (l *Loop) LastAgentSessionID() string {
 	return strings.TrimSpace(l.lastAgentSessionID)
 }
 
+// ContinueWith queues a follow-up message for the next Run call. The next
+// turn resumes the last agent session when one is available (otherwise it
+// re-sends the full prompt) and carries msg the same way as an interrupt.
+func (l *Loop) ContinueWith(msg string) {
+	l.lastInterruptMsg = msg
+}
+
 // BuildResumePrompt returns the exact continuation prompt used by the loop
 // runtime when resuming an existing agent session.
 func BuildResumePrompt(waitResults []WaitResult, moreSpawnsPending bool, interruptMsg string, includeContinueLead bool) string {
@@ -867,6 +998,10 @@ func formatWaitResult(wr WaitResult) string {
 	b.WriteString("\n\n")
 
 	if wr.Review || !store.IsTerminalSpawnStatus(wr.Status) {
+		if wr.Status == store.SpawnStatusQueued {
+			fmt.Fprintf(&b, "- Waiting for a spawn slot (queue position %d); it starts automatically.\n\n", wr.QueuePosition)
+			return b.String()
+		}
 		if wr.Elapsed > 0 {
 			fmt.Fprintf(&b, "- Elapsed: %s\n", wr.Elapsed.Round(time.Second))
 		}
@@ -930,3 +930,118 @@ func containsAll(s string, subs ...string) bool {
 	}
 	return true
 }
+
+type quotaStubAgent struct {
+	runs []agent.Config
+}
+
+func (a *quotaStubAgent) Name() string { return "quota" }
+
+func (a *quotaStubAgent) Run(ctx context.Context, cfg agent.Config, recorder *recording.Recorder) (*agent.Result, error) {
+	a.runs = append(a.runs, cfg)
+	return &agent.Result{ExitCode: 1, Duration: time.Millisecond, Error: "API error: 429 Too Many Requests"}, nil
+}
+
+func TestLoopFailsOverToFallbackProfile(t *testing.T) {
+	dir := t.TempDir()
+	s, err := store.New(dir)
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	if err := s.Init(store.ProjectConfig{Name: "test", RepoPath: dir}); err != nil {
+		t.Fatalf("store.Init() error = %v", err)
+	}
+
+	primary := &quotaStubAgent{}
+	fallback := &stubAgent{}
+	type failoverCall struct {
+		turnID        int
+		from, to, why string
+	}
+	var calls []failoverCall
+	l := &Loop{
+		Store:       s,
+		Agent:       primary,
+		ProfileName: "primary",
+		Config: agent.Config{
+			Name:            "quota",
+			Command:         "quota-cli",
+			Prompt:          "do the task",
+			MaxTurns:        1,
+			ResumeSessionID: "sess-primary",
+		},
+		Fallbacks: []Fallback{{
+			ProfileName: "backup",
+			Agent:       fallback,
+			Config:      agent.Config{Name: "stub", Command: "stub-cli"},
+		}},
+		OnFailover: func(turnID int, from, to, reason string) {
+			calls = append(calls, failoverCall{turnID, from, to, reason})
+		},
+	}
+	if err := l.Run(context.Background()); err != nil {
+		t.Fatalf("Loop.Run() error = %v", err)
+	}
+
+	if len(primary.runs) != 1 || len(fallback.runs) != 1 {
+		t.Fatalf("runs = primary %d, fallback %d; want 1 each", len(primary.runs), len(fallback.runs))
+	}
+	rerun := fallback.runs[0]
+	if rerun.Command != "stub-cli" || rerun.ResumeSessionID != "" {
+		t.Fatalf("fallback run command=%q resume=%q, want stub-cli and no resume", rerun.Command, rerun.ResumeSessionID)
+	}
+	if !strings.Contains(rerun.Prompt, "do the task") {
+		t.Fatalf("fallback prompt = %q, want the failed turn's prompt", rerun.Prompt)
+	}
+	if len(calls) != 1 || calls[0] != (failoverCall{1, "primary", "backup", agent.FailureQuota}) {
+		t.Fatalf("OnFailover calls = %+v", calls)
+	}
+
+	failed, err := s.GetTurn(1)
+	if err != nil {
+		t.Fatalf("GetTurn(1) error = %v", err)
+	}
+	if failed.FailedOverTo != "backup" || failed.FailoverReason != agent.FailureQuota {
+		t.Fatalf("failed turn failover = %q/%q", failed.FailedOverTo, failed.FailoverReason)
+	}
+	rerunTurn, err := s.GetTurn(2)
+	if err != nil {
+		t.Fatalf("GetTurn(2) error = %v", err)
+	}
+	if rerunTurn.ProfileName != "backup" || rerunTurn.FailoverFromTurnID != 1 {
+		t.Fatalf("re-run turn profile=%q failover_from=%d", rerunTurn.ProfileName, rerunTurn.FailoverFromTurnID)
+	}
+	if len(l.Fallbacks) != 0 {
+		t.Fatalf("remaining fallbacks = %d, want 0", len(l.Fallbacks))
+	}
+}
+
+func TestLoopWithoutFallbackKeepsFailedTurn(t *testing.T) {
+	dir := t.TempDir()
+	s, err := store.New(dir)
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	if err := s.Init(store.ProjectConfig{Name: "test", RepoPath: dir}); err != nil {
+		t.Fatalf("store.Init() error = %v", err)
+	}
+	primary := &quotaStubAgent{}
+	l := &Loop{
+		Store:  s,
+		Agent:  primary,
+		Config: agent.Config{Prompt: "do the task", MaxTurns: 1},
+	}
+	if err := l.Run(context.Background()); err != nil {
+		t.Fatalf("Loop.Run() error = %v", err)
+	}
+	if len(primary.runs) != 1 {
+		t.Fatalf("runs = %d, want 1", len(primary.runs))
+	}
+	turn, err := s.GetTurn(1)
+	if err != nil {
+		t.Fatalf("GetTurn(1) error = %v", err)
+	}
+	if turn.FailedOverTo != "" {
+		t.Fatalf("FailedOverTo = %q, want empty", turn.FailedOverTo)
+	}
+}
@@ -0,0 +1,88 @@
+package looprun
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/notify"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// approvalPollInterval is how often a gated step re-reads its approval.
+var approvalPollInterval = 2 * time.Second
+
+// awaitStepApproval blocks before a step the loop's approval policy gates,
+// until a human approves or denies it. It returns the decided approval, or
+// nil when the step is not gated. If ctx ends first the approval is marked
+// canceled and ctx's error is returned.
+func awaitStepApproval(ctx context.Context, cfg RunConfig, run *store.LoopRun, cycle, stepIdx int, step config.LoopStep) (*store.Approval, error) {
+	if cfg.LoopDef == nil || !cfg.LoopDef.Approval.GatesStep(step) {
+		return nil, nil
+	}
+	a := &store.Approval{
+		Kind:      store.ApprovalKindLoopStep,
+		Summary:   fmt.Sprintf("start step %d (%s) of loop %q, cycle %d", stepIdx, step.Label(), run.LoopName, cycle),
+		Reason:    "loop approval policy gates this step",
+		LoopRunID: run.ID,
+		LoopName:  run.LoopName,
+		Cycle:     cycle,
+		StepIndex: stepIdx,
+		StepLabel: step.Label(),
+	}
+	if err := cfg.Store.CreateApproval(a); err != nil {
+		return nil, fmt.Errorf("filing step approval: %w", err)
+	}
+	debug.LogKV("looprun", "step waiting for approval", "run_id", run.ID, "step", stepIdx, "approval_id", a.ID)
+	run.PendingApproval = a.ID
+	cfg.Store.UpdateLoopRun(run)
+	defer func() {
+		run.PendingApproval = 0
+		cfg.Store.UpdateLoopRun(run)
+	}()
+
+	project := ""
+	if cfg.Project != nil {
+		project = cfg.Project.Name
+	}
+	notify.Publish(cfg.GlobalCfg, notify.ApprovalNeeded(project, a))
+
+	ticker := time.NewTicker(approvalPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if cur, err := cfg.Store.GetApproval(a.ID); err == nil && cur.Status == store.ApprovalPending {
+				cur.Status = store.ApprovalCanceled
+				cur.DecidedAt = time.Now().UTC()
+				cfg.Store.UpdateApproval(cur)
+			}
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+		cur, err := cfg.Store.GetApproval(a.ID)
+		if err != nil {
+			debug.LogKV("looprun", "reading step approval failed", "approval_id", a.ID, "error", err)
+			continue
+		}
+		if cur.Status != store.ApprovalPending {
+			debug.LogKV("looprun", "step approval decided", "run_id", run.ID, "step", stepIdx, "status", cur.Status)
+			return cur, nil
+		}
+	}
+}
+
+// approvalNote formats a reviewer's decision for the agents of the run.
+func approvalNote(a *store.Approval) string {
+	who := a.DecidedBy
+	if who == "" {
+		who = "a human"
+	}
+	note := fmt.Sprintf("Step %q was %s by %s.", a.StepLabel, a.Status, who)
+	if a.Comment != "" {
+		note += " Comment: " + a.Comment
+	}
+	return note
+}
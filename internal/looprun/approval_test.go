@@ -0,0 +1,117 @@
+package looprun
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func approvalTestConfig(t *testing.T, s *store.Store) RunConfig {
+	t.Helper()
+	proj, err := s.LoadProject()
+	if err != nil {
+		t.Fatalf("LoadProject: %v", err)
+	}
+	return RunConfig{
+		Store:     s,
+		GlobalCfg: &config.GlobalConfig{},
+		LoopDef: &config.LoopDef{
+			Name:     "gated",
+			Steps:    []config.LoopStep{{ID: "deploy", Profile: "missing-profile", Turns: 1}},
+			Approval: &config.ApprovalPolicy{Steps: []string{"deploy"}},
+		},
+		Project:   proj,
+		AgentsCfg: &agent.AgentsConfig{Agents: map[string]agent.AgentRecord{}},
+		WorkDir:   proj.RepoPath,
+		MaxCycles: 1,
+	}
+}
+
+func decideFirstPending(t *testing.T, s *store.Store, approve bool, comment string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		pending, _ := s.PendingApprovals()
+		if len(pending) > 0 {
+			if _, err := s.DecideApproval(pending[0].ID, approve, "alice", comment); err != nil {
+				t.Errorf("DecideApproval: %v", err)
+			}
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Errorf("no pending approval appeared")
+}
+
+func TestRun_DeniedStepIsSkipped(t *testing.T) {
+	old := approvalPollInterval
+	approvalPollInterval = 5 * time.Millisecond
+	defer func() { approvalPollInterval = old }()
+
+	s := newLooprunTestStore(t)
+	go decideFirstPending(t, s, false, "not today")
+
+	// The step's profile does not exist, so reaching it would fail the run.
+	if err := Run(context.Background(), approvalTestConfig(t, s), nil); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	runs, _ := s.ListLoopRuns()
+	if len(runs) != 1 {
+		t.Fatalf("loop runs = %d, want 1", len(runs))
+	}
+	if runs[0].PendingApproval != 0 {
+		t.Fatalf("PendingApproval = %d, want cleared", runs[0].PendingApproval)
+	}
+	msgs, _ := s.ListLoopMessages(runs[0].ID)
+	if len(msgs) != 1 || !strings.Contains(msgs[0].Content, "not today") {
+		t.Fatalf("loop messages = %+v, want denial note", msgs)
+	}
+}
+
+func TestRun_ApprovedStepRuns(t *testing.T) {
+	old := approvalPollInterval
+	approvalPollInterval = 5 * time.Millisecond
+	defer func() { approvalPollInterval = old }()
+
+	s := newLooprunTestStore(t)
+	go decideFirstPending(t, s, true, "")
+
+	err := Run(context.Background(), approvalTestConfig(t, s), nil)
+	if err == nil || !strings.Contains(err.Error(), "missing-profile") {
+		t.Fatalf("Run err = %v, want the approved step to run", err)
+	}
+}
+
+func TestRun_CancelWhileAwaitingApproval(t *testing.T) {
+	old := approvalPollInterval
+	approvalPollInterval = 5 * time.Millisecond
+	defer func() { approvalPollInterval = old }()
+
+	s := newLooprunTestStore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for {
+			if pending, _ := s.PendingApprovals(); len(pending) > 0 {
+				cancel()
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+
+	err := Run(ctx, approvalTestConfig(t, s), nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run err = %v, want context.Canceled", err)
+	}
+	list, _ := s.ListApprovals()
+	if len(list) != 1 || list[0].Status != store.ApprovalCanceled {
+		t.Fatalf("approvals = %+v, want one canceled", list)
+	}
+}
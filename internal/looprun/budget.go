@@ -0,0 +1,193 @@
+package looprun
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/budget"
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/events"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// budgetPublishInterval throttles how often live consumption is persisted
+// and broadcast while agents stream.
+const budgetPublishInterval = time.Second
+
+// runBudget connects a loop run's budget trackers to the runner. Crossing a
+// soft threshold interrupts the active turn with a wind-down notice;
+// crossing a hard limit cancels the run and the spawn tree beneath it.
+type runBudget struct {
+	store   *store.Store
+	runID   int
+	eventCh chan any
+	cancel  context.CancelFunc
+
+	run *budget.Tracker
+
+	mu          sync.Mutex
+	step        *budget.Tracker
+	stepIndex   int
+	activeTurn  int
+	lastPublish time.Time
+}
+
+func newRunBudget(s *store.Store, loopDef *config.LoopDef, runID int, eventCh chan any, cancel context.CancelFunc) *runBudget {
+	rb := &runBudget{
+		store:   s,
+		runID:   runID,
+		eventCh: eventCh,
+		cancel:  cancel,
+		run:     budget.New(fmt.Sprintf("loop %q", loopDef.Name), loopDef.Budget, nil),
+	}
+	rb.run.OnChange(func(*budget.Tracker) { rb.publish(false) })
+	rb.run.OnSoftLimit(func(t *budget.Tracker) {
+		debug.LogKV("looprun", "loop budget soft limit reached", "run_id", runID, "spent", budget.Describe(t.Spent(), t.Limit()))
+		if s != nil && runID > 0 {
+			_ = s.SignalLoopWindDown(runID)
+		}
+		rb.interruptActiveTurn(t)
+		rb.publish(true)
+	})
+	rb.run.OnHardLimit(rb.onHard)
+	return rb
+}
+
+func (rb *runBudget) onHard(t *budget.Tracker) {
+	debug.LogKV("looprun", "budget hard limit reached; cancelling run",
+		"run_id", rb.runID,
+		"scope", t.Name(),
+		"spent", budget.Describe(t.Spent(), t.Limit()),
+	)
+	rb.cancel()
+	rb.publish(true)
+}
+
+// startStep creates the tracker for one step execution.
+func (rb *runBudget) startStep(stepIdx int, stepDef config.LoopStep, profile string) *budget.Tracker {
+	t := budget.New(fmt.Sprintf("step %d (%s)", stepIdx+1, profile), stepDef.Budget, rb.run)
+	t.OnSoftLimit(func(t *budget.Tracker) {
+		debug.LogKV("looprun", "step budget soft limit reached", "run_id", rb.runID, "step", stepIdx)
+		rb.interruptActiveTurn(t)
+		rb.publish(true)
+	})
+	// A step that overruns its hard limit stops the run: later steps usually
+	// depend on the work this one was meant to finish.
+	t.OnHardLimit(rb.onHard)
+
+	rb.mu.Lock()
+	rb.step = t
+	rb.stepIndex = stepIdx
+	rb.activeTurn = 0
+	rb.mu.Unlock()
+	return t
+}
+
+// endStep releases the step tracker and its turn bindings.
+func (rb *runBudget) endStep(turnIDs []int) {
+	rb.mu.Lock()
+	step := rb.step
+	rb.activeTurn = 0
+	rb.mu.Unlock()
+	for _, id := range turnIDs {
+		budget.Unbind(id)
+	}
+	if step != nil {
+		step.Detach()
+	}
+	rb.publish(true)
+}
+
+// setActiveTurn records the turn currently running for the step so spawns
+// it requests are charged to the step.
+func (rb *runBudget) setActiveTurn(turnID int) {
+	rb.mu.Lock()
+	rb.activeTurn = turnID
+	step := rb.step
+	rb.mu.Unlock()
+	budget.Bind(turnID, step)
+}
+
+// stepWindingDown reports whether the current step or the run asked the
+// agent to wrap up.
+func (rb *runBudget) stepWindingDown() bool {
+	rb.mu.Lock()
+	step := rb.step
+	rb.mu.Unlock()
+	return step != nil && step.State() != budget.StateOK
+}
+
+func (rb *runBudget) interruptActiveTurn(t *budget.Tracker) {
+	rb.mu.Lock()
+	turnID := rb.activeTurn
+	rb.mu.Unlock()
+	if rb.store == nil || turnID <= 0 {
+		return
+	}
+	if err := rb.store.SignalInterrupt(turnID, budget.WindDownMessage(t)); err != nil {
+		debug.LogKV("looprun", "budget wind-down interrupt failed", "turn_id", turnID, "error", err)
+	}
+}
+
+// err returns the budget error that cancelled the run, if any.
+func (rb *runBudget) err() error {
+	if err := rb.run.Err(); err != nil {
+		return err
+	}
+	rb.mu.Lock()
+	step := rb.step
+	rb.mu.Unlock()
+	if step != nil {
+		return step.Err()
+	}
+	return nil
+}
+
+func (rb *runBudget) snapshot() store.LoopRunBudget {
+	rb.mu.Lock()
+	step, stepIdx := rb.step, rb.stepIndex
+	rb.mu.Unlock()
+
+	spent, limit := rb.run.Spent(), rb.run.Limit()
+	out := store.LoopRunBudget{
+		CostUSD:    spent.CostUSD,
+		Tokens:     spent.Tokens,
+		MaxCostUSD: limit.MaxCostUSD,
+		MaxTokens:  limit.MaxTokens,
+		State:      rb.run.State(),
+		StepIndex:  stepIdx,
+		UpdatedAt:  time.Now().UTC(),
+	}
+	if step != nil {
+		stepSpent, stepLimit := step.Spent(), step.Limit()
+		out.StepCostUSD = stepSpent.CostUSD
+		out.StepTokens = stepSpent.Tokens
+		out.StepMaxCostUSD = stepLimit.MaxCostUSD
+		out.StepMaxTokens = stepLimit.MaxTokens
+		out.StepState = step.State()
+	}
+	return out
+}
+
+// publish persists and broadcasts the current consumption. Unforced calls
+// are throttled to budgetPublishInterval.
+func (rb *runBudget) publish(force bool) {
+	rb.mu.Lock()
+	if !force && time.Since(rb.lastPublish) < budgetPublishInterval {
+		rb.mu.Unlock()
+		return
+	}
+	rb.lastPublish = time.Now()
+	rb.mu.Unlock()
+
+	snap := rb.snapshot()
+	if rb.store != nil && rb.runID > 0 {
+		if err := rb.store.SaveLoopRunBudget(rb.runID, &snap); err != nil {
+			debug.LogKV("looprun", "saving loop budget failed", "run_id", rb.runID, "error", err)
+		}
+	}
+	emitLoopEvent(rb.eventCh, "loop_budget", events.LoopBudgetMsg{RunID: rb.runID, Budget: snap})
+}
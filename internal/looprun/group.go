@@ -0,0 +1,415 @@
+package looprun
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/budget"
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/events"
+	"github.com/agusx1211/adaf/internal/loop"
+	"github.com/agusx1211/adaf/internal/store"
+	"github.com/agusx1211/adaf/internal/stream"
+	"github.com/agusx1211/adaf/internal/usage"
+	"github.com/agusx1211/adaf/internal/worktree"
+)
+
+// Parallel group member states reported in LoopStepMember.Status.
+const (
+	groupMemberRunning   = "running"
+	groupMemberCompleted = "completed"
+	groupMemberFailed    = "failed"
+	groupMemberCancelled = "cancelled"
+)
+
+// stepGroupRun carries the run state a parallel step group needs.
+type stepGroupRun struct {
+	cfg       RunConfig
+	loopDef   *config.LoopDef
+	run       *store.LoopRun
+	cycle     int
+	stepIdx   int
+	stepHexID string
+	step      config.LoopStep
+	spend     *runBudget
+	eventCh   chan any
+	messages  []store.LoopMessage
+}
+
+// groupMember is one member of a running parallel step group.
+type groupMember struct {
+	step    config.LoopStep
+	prof    *config.Profile
+	agent   agent.Agent
+	branch  string
+	wtPath  string
+	turnIDs []int
+	status  string
+	summary string
+}
+
+// stepGroupResult is the joined outcome of a parallel step group.
+type stepGroupResult struct {
+	turnIDs     []int
+	handoffs    []store.HandoffInfo
+	windingDown bool
+}
+
+// runStepGroup runs the members of a parallel step group concurrently, each
+// in its own worktree, and returns once all of them have finished. Member
+// work is auto-committed on its branch and handed to the next step as
+// HandoffInfo entries carrying the member's turn summary.
+func runStepGroup(ctx context.Context, g stepGroupRun) (stepGroupResult, error) {
+	var res stepGroupResult
+
+	members := make([]*groupMember, 0, len(g.step.Parallel))
+	for i, m := range g.step.Parallel {
+		prof := g.cfg.GlobalCfg.FindProfile(m.Profile)
+		if prof == nil {
+			return res, fmt.Errorf("profile %q not found for step %d member %d", m.Profile, g.stepIdx, i)
+		}
+		prof, err := gateStepProfile(ctx, g.cfg, g.run, prof, g.eventCh)
+		if err != nil {
+			return res, err
+		}
+		agentInstance, ok := agent.Get(prof.Agent)
+		if !ok {
+			return res, fmt.Errorf("agent %q not found for profile %q", prof.Agent, prof.Name)
+		}
+		members = append(members, &groupMember{step: m, prof: prof, agent: agentInstance, status: groupMemberRunning})
+	}
+
+	wt := worktree.NewManager(g.cfg.WorkDir)
+	cleanup := func() {
+		cleanCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		for _, m := range members {
+			if m.wtPath == "" {
+				continue
+			}
+			// Keep the branch: it is what the next step reviews and merges.
+			if err := wt.Remove(cleanCtx, m.wtPath, false); err != nil {
+				debug.LogKV("looprun", "group worktree cleanup failed", "worktree", m.wtPath, "error", err)
+			}
+		}
+	}
+	defer cleanup()
+
+	for _, m := range members {
+		branch := worktree.BranchName(g.run.ID, fmt.Sprintf("step%d-%s", g.stepIdx+1, m.prof.Name))
+		wtPath, err := wt.Create(ctx, branch)
+		if err != nil {
+			return res, fmt.Errorf("creating worktree for step %d member %s: %w", g.stepIdx, m.prof.Name, err)
+		}
+		m.branch, m.wtPath = branch, wtPath
+	}
+
+	emitLoopEvent(g.eventCh, "loop_step_start", events.LoopStepStartMsg{
+		RunID:      g.run.ID,
+		RunHexID:   g.run.HexID,
+		StepHexID:  g.stepHexID,
+		Cycle:      g.cycle,
+		StepIndex:  g.stepIdx,
+		Profile:    g.step.Label(),
+		Turns:      1,
+		TotalSteps: len(g.loopDef.Steps),
+		Members:    groupMemberEvents(members),
+	})
+
+	stepBudget := g.spend.startStep(g.stepIdx, g.step, g.step.Label())
+	var (
+		runMu       sync.Mutex
+		windingDown atomic.Bool
+		wg          sync.WaitGroup
+	)
+	for _, m := range members {
+		wg.Add(1)
+		go func(m *groupMember) {
+			defer wg.Done()
+			runGroupMember(ctx, g, m, members, stepBudget, &runMu, &windingDown)
+		}(m)
+	}
+	wg.Wait()
+
+	for _, m := range members {
+		res.turnIDs = append(res.turnIDs, m.turnIDs...)
+	}
+	g.spend.endStep(res.turnIDs)
+	unbindUsageGate(res.turnIDs)
+
+	autoCommitCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	for _, m := range members {
+		msg := fmt.Sprintf("adaf: auto-commit loop step %d member %s", g.stepIdx+1, m.prof.Name)
+		if _, _, err := wt.AutoCommitIfDirty(autoCommitCtx, m.wtPath, msg); err != nil {
+			debug.LogKV("looprun", "group member auto-commit failed", "branch", m.branch, "error", err)
+		}
+		lastTurn := 0
+		if n := len(m.turnIDs); n > 0 {
+			lastTurn = m.turnIDs[n-1]
+		}
+		res.handoffs = append(res.handoffs, store.HandoffInfo{
+			Profile: m.prof.Name,
+			Task:    m.step.Instructions,
+			Status:  m.status,
+			Branch:  m.branch,
+			TurnID:  lastTurn,
+			Summary: m.summary,
+		})
+	}
+	cancel()
+
+	emitLoopEvent(g.eventCh, "loop_step_end", events.LoopStepEndMsg{
+		RunID:      g.run.ID,
+		RunHexID:   g.run.HexID,
+		StepHexID:  g.stepHexID,
+		Cycle:      g.cycle,
+		StepIndex:  g.stepIdx,
+		Profile:    g.step.Label(),
+		TotalSteps: len(g.loopDef.Steps),
+		Members:    groupMemberEvents(members),
+	})
+
+	res.windingDown = windingDown.Load()
+	if ctx.Err() != nil {
+		return res, ctx.Err()
+	}
+	return res, nil
+}
+
+// runGroupMember runs one member to completion and records its status and
+// turn summary on m.
+func runGroupMember(ctx context.Context, g stepGroupRun, m *groupMember, all []*groupMember, stepBudget *budget.Tracker, runMu *sync.Mutex, windingDown *atomic.Bool) {
+	memberCtx, cancelMember := context.WithCancel(ctx)
+	defer cancelMember()
+
+	memberBudget := budget.New(fmt.Sprintf("step %d member %s", g.stepIdx+1, m.prof.Name), m.step.Budget, stepBudget)
+	memberBudget.OnHardLimit(func(*budget.Tracker) { cancelMember() })
+	defer memberBudget.Detach()
+	meter := &budget.Meter{}
+
+	step := m.step
+	step.Instructions = strings.TrimSpace(step.Instructions + "\n\n" + groupMemberNote(m, all))
+
+	runCfg := g.cfg
+	runCfg.WorkDir = m.wtPath
+	runCfg.ResumeSessionID = ""
+	agentCfg := buildAgentConfig(runCfg, m.prof, step, g.run.ID, g.stepIdx, g.run.HexID, g.stepHexID, nil)
+
+	promptInput := StepPromptInput{
+		Store:            g.cfg.Store,
+		Project:          g.cfg.Project,
+		GlobalCfg:        g.cfg.GlobalCfg,
+		PlanID:           g.cfg.PlanID,
+		InitialPrompt:    g.cfg.InitialPrompt,
+		ResourcePriority: config.EffectiveResourcePriority(g.loopDef.ResourcePriority),
+		LoopName:         g.loopDef.Name,
+		RunID:            g.run.ID,
+		Cycle:            g.cycle,
+		StepIndex:        g.stepIdx,
+		TotalSteps:       len(g.loopDef.Steps),
+		Step:             step,
+		LoopSteps:        g.loopDef.Steps,
+		Profile:          m.prof,
+		Messages:         g.messages,
+	}
+	prompt, err := BuildStepPrompt(promptInput)
+	if err != nil {
+		debug.LogKV("looprun", "group member prompt failed", "profile", m.prof.Name, "error", err)
+		m.status = groupMemberFailed
+		return
+	}
+	turns := step.Turns
+	if turns <= 0 {
+		turns = 1
+	}
+	agentCfg.Prompt = prompt
+	agentCfg.MaxTurns = turns
+
+	streamCh := make(chan stream.RawEvent, 64)
+	bridgeDone := make(chan struct{})
+	go func() {
+		defer close(bridgeDone)
+		for ev := range streamCh {
+			if ev.Err != nil {
+				continue
+			}
+			if ev.Text != "" {
+				emitLoopEvent(g.eventCh, "agent_raw_output", events.AgentRawOutputMsg{Data: ev.Text, SessionID: ev.TurnID})
+				continue
+			}
+			if ev.Parsed.Type == "" {
+				continue
+			}
+			memberBudget.Add(meter.Observe(ev.Parsed))
+			emitLoopEvent(g.eventCh, "agent_event", events.AgentEventMsg{
+				Event:  ev.Parsed,
+				Raw:    ev.Raw,
+				TurnID: ev.TurnID,
+			})
+		}
+	}()
+	agentCfg.EventSink = streamCh
+	agentCfg.Stdout = io.Discard
+	agentCfg.Stderr = io.Discard
+
+	l := &loop.Loop{
+		Store:        g.cfg.Store,
+		Agent:        m.agent,
+		Config:       agentCfg,
+		PlanID:       g.cfg.PlanID,
+		LoopRunHexID: g.run.HexID,
+		StepHexID:    g.stepHexID,
+		ProfileName:  m.prof.Name,
+		GlobalCfg:    g.cfg.GlobalCfg,
+		Fallbacks:    profileFallbacks(runCfg, m.prof, step, g.run.ID, g.stepIdx, g.run.HexID, g.stepHexID, nil),
+		PromptFunc: func(turnID int) string {
+			input := promptInput
+			input.CurrentTurnID = turnID
+			if built, err := BuildStepPrompt(input); err == nil {
+				return built
+			}
+			return prompt
+		},
+		OnPrompt: func(turnID int, turnHexID, prompt string, isResume bool) {
+			trimmed, truncated, originalLen := truncatePromptForEvent(prompt)
+			emitLoopEvent(g.eventCh, "agent_prompt", events.AgentPromptMsg{
+				SessionID:      turnID,
+				TurnHexID:      turnHexID,
+				Prompt:         trimmed,
+				IsResume:       isResume,
+				Truncated:      truncated,
+				OriginalLength: originalLen,
+			})
+		},
+		OnStart: func(turnID int, turnHexID string) {
+			runMu.Lock()
+			if len(m.turnIDs) == 0 || m.turnIDs[len(m.turnIDs)-1] != turnID {
+				m.turnIDs = append(m.turnIDs, turnID)
+				g.run.TurnIDs = append(g.run.TurnIDs, turnID)
+				g.cfg.Store.UpdateLoopRun(g.run)
+			}
+			runMu.Unlock()
+			budget.Bind(turnID, memberBudget)
+			usage.BindGate(turnID, g.loopDef.UsageGate)
+			emitLoopEvent(g.eventCh, "agent_started", events.AgentStartedMsg{
+				SessionID: turnID,
+				TurnHexID: turnHexID,
+				StepHexID: g.stepHexID,
+				RunHexID:  g.run.HexID,
+			})
+		},
+		StopAfterTurn: func(turnID int) bool {
+			// Members have no interrupt channel: budget and loop wind-down
+			// take effect at turn boundaries.
+			if g.spend.stepWindingDown() || memberBudget.State() != budget.StateOK {
+				return true
+			}
+			if g.cfg.Store != nil && g.run.ID > 0 && g.cfg.Store.IsLoopWindDown(g.run.ID) {
+				windingDown.Store(true)
+				return true
+			}
+			return false
+		},
+		OnEnd: func(turnID int, turnHexID string, result *agent.Result) {
+			emitLoopEvent(g.eventCh, "agent_finished", events.AgentFinishedMsg{
+				SessionID: turnID,
+				TurnHexID: turnHexID,
+				Result:    result,
+			})
+		},
+	}
+
+	loopErr := l.Run(memberCtx)
+	close(streamCh)
+	<-bridgeDone
+	for _, id := range m.turnIDs {
+		budget.Unbind(id)
+	}
+	debug.LogKV("looprun", "group member finished",
+		"step", g.stepIdx,
+		"profile", m.prof.Name,
+		"branch", m.branch,
+		"error", loopErr,
+	)
+
+	switch {
+	case memberCtx.Err() != nil:
+		m.status = groupMemberCancelled
+	case loopErr != nil:
+		m.status = groupMemberFailed
+	case l.LastResult != nil && l.LastResult.ExitCode != 0:
+		m.status = groupMemberFailed
+	default:
+		m.status = groupMemberCompleted
+	}
+	if err := memberBudget.Err(); err != nil {
+		m.summary = err.Error()
+	}
+	if n := len(m.turnIDs); n > 0 && g.cfg.Store != nil {
+		if turn, err := g.cfg.Store.GetTurn(m.turnIDs[n-1]); err == nil && turn != nil {
+			if summary := groupTurnSummary(turn); summary != "" {
+				m.summary = strings.TrimSpace(summary + " " + m.summary)
+			}
+		}
+	}
+}
+
+// groupMemberNote tells a member about its siblings and its branch.
+func groupMemberNote(m *groupMember, all []*groupMember) string {
+	var others []string
+	for _, o := range all {
+		if o != m {
+			others = append(others, o.prof.Name)
+		}
+	}
+	note := fmt.Sprintf("You are running in parallel in your own worktree on branch `%s`. Commit your work there; the next step reviews and merges it.", m.branch)
+	if len(others) > 0 {
+		note += " Other agents working at the same time: " + strings.Join(others, ", ") + ". Stay within your own task to avoid conflicting edits."
+	}
+	return note
+}
+
+func groupTurnSummary(turn *store.Turn) string {
+	for _, s := range []string{turn.WhatWasBuilt, turn.CurrentState, turn.Objective} {
+		if s = strings.TrimSpace(s); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func groupMemberEvents(members []*groupMember) []events.LoopStepMember {
+	out := make([]events.LoopStepMember, len(members))
+	for i, m := range members {
+		turnID := 0
+		if n := len(m.turnIDs); n > 0 {
+			turnID = m.turnIDs[n-1]
+		}
+		out[i] = events.LoopStepMember{
+			Profile: m.prof.Name,
+			Status:  m.status,
+			TurnID:  turnID,
+			Branch:  m.branch,
+		}
+	}
+	return out
+}
+
+// spawnHandoffs returns the handoffs that refer to running spawns, dropping
+// parallel group results.
+func spawnHandoffs(handoffs []store.HandoffInfo) []store.HandoffInfo {
+	var out []store.HandoffInfo
+	for _, h := range handoffs {
+		if h.SpawnID > 0 {
+			out = append(out, h)
+		}
+	}
+	return out
+}
@@ -0,0 +1,140 @@
+package looprun
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/events"
+)
+
+func initGroupTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", dir},
+		{"-C", dir, "config", "user.email", "test@test.com"},
+		{"-C", dir, "config", "user.name", "Test"},
+		{"-C", dir, "commit", "--allow-empty", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestRun_ParallelGroupRunsMembersInWorktrees(t *testing.T) {
+	s := newLooprunTestStore(t)
+	proj, err := s.LoadProject()
+	if err != nil {
+		t.Fatalf("LoadProject: %v", err)
+	}
+	repo := initGroupTestRepo(t)
+
+	tmp := t.TempDir()
+	scriptPath := filepath.Join(tmp, "write.sh")
+	if err := os.WriteFile(scriptPath, []byte("#!/bin/sh\necho done > work.txt\nexit 0\n"), 0755); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	loopDef := &config.LoopDef{
+		Name: "parallel-test",
+		Steps: []config.LoopStep{
+			{Parallel: []config.LoopStep{
+				{Profile: "alpha", Turns: 1, Instructions: "build alpha"},
+				{Profile: "beta", Turns: 1, Instructions: "build beta"},
+			}},
+			{Profile: "review", Turns: 1},
+		},
+	}
+	globalCfg := &config.GlobalConfig{
+		Profiles: []config.Profile{
+			{Name: "alpha", Agent: "generic"},
+			{Name: "beta", Agent: "generic"},
+			{Name: "review", Agent: "generic"},
+		},
+	}
+	agentsCfg := &agent.AgentsConfig{
+		Agents: map[string]agent.AgentRecord{
+			"generic": {Name: "generic", Path: scriptPath},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	eventCh := make(chan any, 256)
+	err = Run(ctx, RunConfig{
+		Store:     s,
+		GlobalCfg: globalCfg,
+		LoopDef:   loopDef,
+		Project:   proj,
+		AgentsCfg: agentsCfg,
+		WorkDir:   repo,
+		MaxCycles: 1,
+	}, eventCh)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	turns, err := s.ListTurns()
+	if err != nil {
+		t.Fatalf("ListTurns: %v", err)
+	}
+	if len(turns) != 3 {
+		t.Fatalf("len(turns) = %d, want 3", len(turns))
+	}
+
+	runs, err := s.ListLoopRuns()
+	if err != nil || len(runs) == 0 {
+		t.Fatalf("ListLoopRuns: %v (%d runs)", err, len(runs))
+	}
+	if got := runs[0].Steps[0].Profile; got != "parallel[alpha, beta]" {
+		t.Fatalf("group step profile = %q, want %q", got, "parallel[alpha, beta]")
+	}
+
+	var ended, handedOff bool
+	for len(eventCh) > 0 {
+		ev := <-eventCh
+		if p, ok := ev.(events.AgentPromptMsg); ok && strings.Contains(p.Prompt, "Parallel Step Results") {
+			handedOff = true
+		}
+		end, ok := ev.(events.LoopStepEndMsg)
+		if !ok || end.StepIndex != 0 {
+			continue
+		}
+		ended = true
+		if len(end.Members) != 2 {
+			t.Fatalf("step end members = %d, want 2", len(end.Members))
+		}
+		for _, m := range end.Members {
+			if m.Status != groupMemberCompleted {
+				t.Errorf("member %s status = %q, want %q", m.Profile, m.Status, groupMemberCompleted)
+			}
+			if m.Branch == "" {
+				t.Errorf("member %s has no branch", m.Profile)
+				continue
+			}
+			out, err := exec.Command("git", "-C", repo, "show", "--name-only", "--format=", m.Branch).CombinedOutput()
+			if err != nil {
+				t.Fatalf("git show %s: %v\n%s", m.Branch, err, out)
+			}
+			if !strings.Contains(string(out), "work.txt") {
+				t.Errorf("branch %s commit files = %q, want work.txt", m.Branch, out)
+			}
+		}
+	}
+	if !ended {
+		t.Fatal("missing loop_step_end event for the parallel group")
+	}
+	if !handedOff {
+		t.Fatal("next step prompt is missing the parallel step results")
+	}
+}
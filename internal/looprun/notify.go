@@ -0,0 +1,22 @@
+package looprun
+
+import (
+	"github.com/agusx1211/adaf/internal/notify"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// notifyLoopEnd delivers the end of a loop run, plus a budget notice when
+// the budget stopped it. Detached loops exit right after the run, so
+// delivery is synchronous.
+func notifyLoopEnd(cfg RunConfig, run *store.LoopRun, runErr error) {
+	project := ""
+	if cfg.Project != nil {
+		project = cfg.Project.Name
+	}
+	var events []notify.Event
+	if run.Status == "budget_exceeded" && runErr != nil {
+		events = append(events, notify.LoopBudgetExceeded(project, run, runErr))
+	}
+	events = append(events, notify.LoopDone(project, run, runErr))
+	notify.Deliver(cfg.GlobalCfg, events...)
+}
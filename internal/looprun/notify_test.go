@@ -0,0 +1,60 @@
+package looprun
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/notify"
+)
+
+func TestRun_DeliversLoopDoneBeforeReturning(t *testing.T) {
+	events := make(chan notify.Event, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var ev notify.Event
+		json.Unmarshal(body, &ev)
+		events <- ev
+	}))
+	defer srv.Close()
+
+	s := newLooprunTestStore(t)
+	proj, err := s.LoadProject()
+	if err != nil {
+		t.Fatalf("LoadProject: %v", err)
+	}
+	globalCfg := &config.GlobalConfig{
+		Notify: config.NotifyConfig{Sinks: []config.NotifySink{
+			{Name: "hook", Type: config.NotifySinkWebhook, URL: srv.URL, Events: []string{config.NotifyLoopDone}},
+		}},
+	}
+	loopDef := &config.LoopDef{
+		Name:  "notify-loop",
+		Steps: []config.LoopStep{{Profile: "missing-profile", Turns: 1}},
+	}
+
+	_ = Run(context.Background(), RunConfig{
+		Store:     s,
+		GlobalCfg: globalCfg,
+		LoopDef:   loopDef,
+		Project:   proj,
+		AgentsCfg: &agent.AgentsConfig{Agents: map[string]agent.AgentRecord{}},
+		WorkDir:   proj.RepoPath,
+		MaxCycles: 1,
+	}, nil)
+
+	// Detached loops exit right after Run, so delivery must be done by now.
+	select {
+	case ev := <-events:
+		if ev.Kind != config.NotifyLoopDone || ev.Fields["loop"] != "notify-loop" || ev.Project != proj.Name {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	default:
+		t.Fatal("loop_done notification was not delivered before Run returned")
+	}
+}
@@ -76,6 +76,7 @@ func BuildStepPrompt(input StepPromptInput) (string, error) {
 		CanPushover:       input.Step.CanPushover,
 		Messages:          input.Messages,
 		RunID:             input.RunID,
+		Outcomes:          config.StepOutcomes(input.Step),
 	}
 
 	opts := promptpkg.BuildOpts{
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/budget"
 	"github.com/agusx1211/adaf/internal/config"
 	"github.com/agusx1211/adaf/internal/debug"
 	"github.com/agusx1211/adaf/internal/eventq"
@@ -26,6 +27,8 @@ import (
 	"github.com/agusx1211/adaf/internal/stats"
 	"github.com/agusx1211/adaf/internal/store"
 	"github.com/agusx1211/adaf/internal/stream"
+	"github.com/agusx1211/adaf/internal/usage"
+	"github.com/agusx1211/adaf/internal/worktree"
 )
 
 // RunConfig holds everything needed to launch a loop run.
@@ -51,6 +54,10 @@ type RunConfig struct {
 
 	// InitialPrompt is a general objective injected into every agent's prompt across all loop steps.
 	InitialPrompt string
+
+	// UsageChecker reports provider usage for the loop's usage gate.
+	// Nil uses usage.DefaultMonitor().
+	UsageChecker usage.Checker
 }
 
 const spawnCleanupGracePeriod = 12 * time.Second
@@ -75,7 +82,7 @@ func emitLoopEvent(eventCh chan any, eventType string, event any) bool {
 }
 
 // Run is the blocking loop execution implementation.
-func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
+func Run(ctx context.Context, cfg RunConfig, eventCh chan any) (err error) {
 	debug.LogKV("looprun", "Run() starting",
 		"loop_name", cfg.LoopDef.Name,
 		"steps", len(cfg.LoopDef.Steps),
@@ -91,7 +98,8 @@ func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
 	for i, s := range loopDef.Steps {
 		pos := config.EffectiveStepPosition(s)
 		steps[i] = store.LoopRunStep{
-			Profile:      s.Profile,
+			ID:           s.ID,
+			Profile:      s.Label(),
 			Position:     s.Position,
 			Role:         s.Role,
 			Turns:        s.Turns,
@@ -125,8 +133,14 @@ func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
 			run.Status = "stopped"
 		}
 		run.StoppedAt = time.Now().UTC()
+		// Publishing the run later pushes this commit, not whatever HEAD
+		// has moved on to by then.
+		if head, err := worktree.NewManager(cfg.WorkDir).HeadCommit(context.Background(), cfg.WorkDir); err == nil {
+			run.HeadCommit = head
+		}
 		cfg.Store.UpdateLoopRun(run)
-		_ = stats.UpdateLoopStats(cfg.Store, loopDef.Name, run)
+		_ = stats.UpdateLoopStats(cfg.Store, cfg.GlobalCfg, loopDef.Name, run)
+		notifyLoopEnd(cfg, run, err)
 
 		// Clean up orphaned worktrees from spawns that were never
 		// merged or rejected. Without this, completed-but-unmerged
@@ -137,6 +151,19 @@ func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
 		}
 	}()
 
+	// Budget limits cancel the run through this context; the deferred check
+	// then reports the limit instead of a plain cancellation.
+	ctx, cancelRun := context.WithCancel(ctx)
+	defer cancelRun()
+	spend := newRunBudget(cfg.Store, loopDef, run.ID, eventCh, cancelRun)
+	defer func() {
+		if budgetErr := spend.err(); budgetErr != nil {
+			run.Status = "budget_exceeded"
+			err = budgetErr
+		}
+		spend.publish(true)
+	}()
+
 	prevRoleResume := roleResumeState{}
 	nextCycleStartStep := 0
 
@@ -183,6 +210,101 @@ func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
 			run.StepHexIDs[stepKey] = stepHexID
 			cfg.Store.UpdateLoopRun(run)
 
+			// Gated steps wait here for a human decision. A denial skips the
+			// step and tells the rest of the loop why.
+			decision, approvalErr := awaitStepApproval(ctx, cfg, run, cycle, stepIdx, stepDef)
+			if approvalErr != nil {
+				if ctx.Err() != nil {
+					run.Status = "cancelled"
+				}
+				return approvalErr
+			}
+			if decision != nil {
+				if decision.Status != store.ApprovalApproved {
+					cfg.Store.CreateLoopMessage(&store.LoopMessage{
+						RunID:     run.ID,
+						StepIndex: stepIdx,
+						Content:   approvalNote(decision) + " The step was skipped.",
+					})
+					continue
+				}
+				if decision.Comment != "" {
+					stepDef.Instructions = strings.TrimSpace(stepDef.Instructions + "\n\n" + approvalNote(decision))
+				}
+			}
+
+			// Parallel groups fan out to per-member worktrees and join here.
+			if stepDef.IsGroup() {
+				if err := config.ValidateLoopStepPosition(stepDef, cfg.GlobalCfg); err != nil {
+					return fmt.Errorf("step %d (%s) validation failed: %w", stepIdx, stepDef.Label(), err)
+				}
+				carried := run.PendingHandoffs
+				run.PendingHandoffs = nil
+				cfg.Store.UpdateLoopRun(run)
+
+				groupRes, groupErr := runStepGroup(ctx, stepGroupRun{
+					cfg:       cfg,
+					loopDef:   loopDef,
+					run:       run,
+					cycle:     cycle,
+					stepIdx:   stepIdx,
+					stepHexID: stepHexID,
+					step:      stepDef,
+					spend:     spend,
+					eventCh:   eventCh,
+					messages:  gatherUnseenMessages(cfg.Store, run, stepIdx),
+				})
+				allMsgs, _ := cfg.Store.ListLoopMessages(run.ID)
+				if len(allMsgs) > 0 {
+					run.StepLastSeenMsg[stepIdx] = allMsgs[len(allMsgs)-1].ID
+				}
+				prevRoleResume = roleResumeState{}
+				stepOutcome := consumeStepOutcome(cfg.Store, run, stepIdx)
+
+				if groupErr != nil {
+					cfg.Store.UpdateLoopRun(run)
+					if ctx.Err() != nil {
+						run.Status = "cancelled"
+						return ctx.Err()
+					}
+					return fmt.Errorf("step %d (%s) failed: %w", stepIdx, stepDef.Label(), groupErr)
+				}
+				if groupRes.windingDown || cfg.Store.IsLoopWindDown(run.ID) {
+					debug.LogKV("looprun", "loop wind-down requested; exiting run",
+						"run_id", run.ID,
+						"cycle", cycle,
+						"step", stepIdx,
+					)
+					return nil
+				}
+
+				run.PendingHandoffs = append(spawnHandoffs(carried), groupRes.handoffs...)
+				cfg.Store.UpdateLoopRun(run)
+
+				if len(stepDef.Transitions) > 0 {
+					fields := stepTransitionFields(cfg.Store, cfg.PlanID, groupRes.turnIDs, stepOutcome)
+					target, stop, matched := matchStepTransition(loopDef, stepIdx, fields)
+					if matched {
+						debug.LogKV("looprun", "step transition matched",
+							"run_id", run.ID,
+							"step", stepIdx,
+							"target_step", target,
+							"stop", stop,
+							"outcome", stepOutcome,
+						)
+						if stop {
+							return nil
+						}
+						if target <= stepIdx {
+							nextCycleStartStep = target
+							break
+						}
+						stepIdx = target - 1
+					}
+				}
+				continue
+			}
+
 			// Resolve profile.
 			prof := cfg.GlobalCfg.FindProfile(stepDef.Profile)
 			if prof == nil {
@@ -192,6 +314,13 @@ func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
 				return fmt.Errorf("step %d (%s) position validation failed: %w", stepIdx, stepDef.Profile, err)
 			}
 
+			// Hold or reroute the step while its provider is near its limits.
+			prof, err = gateStepProfile(ctx, cfg, run, prof, eventCh)
+			if err != nil {
+				run.Status = "cancelled"
+				return err
+			}
+
 			// Resolve agent.
 			agentInstance, ok := agent.Get(prof.Agent)
 			if !ok {
@@ -216,12 +345,7 @@ func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
 			})
 
 			// Resolve team to delegation config.
-			var effectiveDelegation *config.DelegationConfig
-			if stepDef.Team != "" {
-				if team := cfg.GlobalCfg.FindTeam(stepDef.Team); team != nil {
-					effectiveDelegation = team.Delegation
-				}
-			}
+			effectiveDelegation := cfg.GlobalCfg.StepDelegation(loopDef, stepDef)
 
 			// Resume only when the immediate previous turn used the same role
 			// and agent provider. Different roles must start fresh chats.
@@ -272,6 +396,9 @@ func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
 			agentCfg.Prompt = prompt
 			agentCfg.MaxTurns = turns
 
+			stepBudget := spend.startStep(stepIdx, stepDef, prof.Name)
+			meter := &budget.Meter{}
+
 			// Run the agent for this step using the existing loop infrastructure.
 			streamCh := make(chan stream.RawEvent, 64)
 			interruptCh := make(chan string, 1)
@@ -290,6 +417,7 @@ func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
 					if ev.Parsed.Type == "" {
 						continue
 					}
+					stepBudget.Add(meter.Observe(ev.Parsed))
 					emitLoopEvent(eventCh, "agent_event", events.AgentEventMsg{
 						Event:  ev.Parsed,
 						Raw:    ev.Raw,
@@ -420,6 +548,7 @@ func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
 				LoopRunHexID:           run.HexID,
 				StepHexID:              stepHexID,
 				InitialResumeSessionID: stepInitialResumeSessionID,
+				Fallbacks:              profileFallbacks(stepRunCfg, prof, stepDef, run.ID, stepIdx, run.HexID, stepHexID, effectiveDelegation),
 				PromptFunc: func(turnID int) string {
 					currentPromptInput := stepPromptInput
 					currentPromptInput.CurrentTurnID = turnID
@@ -430,6 +559,7 @@ func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
 					return built
 				},
 				ProfileName: prof.Name,
+				GlobalCfg:   cfg.GlobalCfg,
 				InterruptCh: interruptCh,
 				OnPrompt: func(turnID int, turnHexID, prompt string, isResume bool) {
 					trimmedPrompt, truncated, originalLen := truncatePromptForEvent(prompt)
@@ -460,6 +590,8 @@ func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
 						RunHexID:  run.HexID,
 					})
 
+					spend.setActiveTurn(turnID)
+					usage.BindGate(turnID, loopDef.UsageGate)
 					startPoll(turnID)
 					startInterruptPoll(turnID)
 				},
@@ -467,6 +599,14 @@ func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
 					setTurnCancel(cancel)
 				},
 				StopAfterTurn: func(turnID int) bool {
+					if spend.stepWindingDown() {
+						debug.LogKV("looprun", "step budget wind-down; finishing step after current turn",
+							"run_id", run.ID,
+							"turn_id", turnID,
+							"step", stepIdx,
+						)
+						return true
+					}
 					if cfg.Store == nil || run.ID <= 0 {
 						return false
 					}
@@ -526,6 +666,8 @@ func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
 			if stepTurnStart < len(run.TurnIDs) {
 				stepTurnIDs = append(stepTurnIDs, run.TurnIDs[stepTurnStart:]...)
 			}
+			spend.endStep(stepTurnIDs)
+			unbindUsageGate(stepTurnIDs)
 
 			// Update watermark: step has seen all current messages.
 			allMsgs, _ := cfg.Store.ListLoopMessages(run.ID)
@@ -549,11 +691,19 @@ func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
 			if stepAgentSessionID == "" {
 				stepAgentSessionID = lastStepAgentSessionID
 			}
-			prevRoleResume = nextRoleResumeState(stepDef, prof, stepAgentSessionID)
+			resumeProf := prof
+			if l.ProfileName != prof.Name {
+				// The step failed over; its session belongs to the fallback's agent.
+				failedOver := *prof
+				failedOver.Agent = l.Agent.Name()
+				resumeProf = &failedOver
+			}
+			prevRoleResume = nextRoleResumeState(stepDef, resumeProf, stepAgentSessionID)
 
 			if errors.Is(loopErr, loop.ErrStepEndedByControlSignal) {
 				loopErr = nil
 			}
+			stepOutcome := consumeStepOutcome(cfg.Store, run, stepIdx)
 
 			if loopErr != nil {
 				if ctx.Err() != nil {
@@ -601,6 +751,31 @@ func Run(ctx context.Context, cfg RunConfig, eventCh chan any) error {
 			if config.PositionCanStopLoop(config.EffectiveStepPosition(stepDef)) && cfg.Store.IsLoopStopped(run.ID) {
 				return nil
 			}
+
+			// Conditional transitions: a backward (or same-step) target starts
+			// the next cycle there, a forward target skips the steps between.
+			if len(stepDef.Transitions) > 0 {
+				fields := stepTransitionFields(cfg.Store, cfg.PlanID, stepTurnIDs, stepOutcome)
+				target, stop, matched := matchStepTransition(loopDef, stepIdx, fields)
+				if matched {
+					debug.LogKV("looprun", "step transition matched",
+						"run_id", run.ID,
+						"step", stepIdx,
+						"target_step", target,
+						"stop", stop,
+						"outcome", stepOutcome,
+					)
+					if stop {
+						return nil
+					}
+					if target <= stepIdx {
+						nextCycleStartStep = target
+						break
+					}
+					stepIdx = target - 1
+					continue
+				}
+			}
 		}
 	}
 }
@@ -739,6 +914,7 @@ func pollSpawnStatus(ctx context.Context, s *store.Store, parentTurnID int, even
 				Status:        rec.Status,
 				Summary:       rec.Summary,
 				Result:        rec.Result,
+				QueuePosition: rec.QueuePosition,
 			}
 			if rec.Status == "awaiting_input" {
 				if ask, err := s.PendingAsk(rec.ID); err == nil && ask != nil {
@@ -912,6 +1088,37 @@ func spawnSnapshotFingerprint(spawns []events.SpawnInfo) string {
 	return b.String()
 }
 
+// profileFallbacks resolves prof's fallback chain into loop fallbacks built
+// the same way as the step's own agent config, confined by prof's sandbox.
+// Profiles whose agent is not registered are skipped.
+func profileFallbacks(cfg RunConfig, prof *config.Profile, step config.LoopStep, runID, stepIndex int, runHexID, stepHexID string, delegation *config.DelegationConfig) []loop.Fallback {
+	if cfg.GlobalCfg == nil {
+		return nil
+	}
+	var out []loop.Fallback
+	for _, fb := range cfg.GlobalCfg.FallbackProfiles(prof.Name) {
+		agentInstance, ok := agent.Get(fb.Agent)
+		if !ok {
+			continue
+		}
+		fbCfg := cfg
+		fbCfg.ResumeSessionID = ""
+		out = append(out, loop.Fallback{
+			ProfileName: fb.Name,
+			Agent:       agentInstance,
+			Config:      buildAgentConfig(fbCfg, withStepSandbox(fb, prof), step, runID, stepIndex, runHexID, stepHexID, delegation),
+		})
+	}
+	return out
+}
+
+// withStepSandbox returns a copy of alt confined by the step profile's
+// sandbox, so failing over or routing to another profile never widens it.
+func withStepSandbox(alt config.Profile, step *config.Profile) *config.Profile {
+	alt.Sandbox = step.Sandbox
+	return &alt
+}
+
 // buildAgentConfig creates an agent.Config for a profile step.
 func buildAgentConfig(cfg RunConfig, prof *config.Profile, step config.LoopStep, runID, stepIndex int, runHexID, stepHexID string, delegation *config.DelegationConfig) agent.Config {
 	launch := agent.BuildLaunchSpec(prof, cfg.AgentsCfg, "")
@@ -957,6 +1164,7 @@ func buildAgentConfig(cfg RunConfig, prof *config.Profile, step config.LoopStep,
 		Env:             agentEnv,
 		WorkDir:         cfg.WorkDir,
 		ResumeSessionID: cfg.ResumeSessionID,
+		Sandbox:         launch.Sandbox,
 	}
 }
 
@@ -990,6 +1198,7 @@ func collectHandoffs(s *store.Store, turnIDs []int) []store.HandoffInfo {
 }
 
 func reparentHandoffs(s *store.Store, handoffs []store.HandoffInfo, newParentTurnID int) {
+	handoffs = spawnHandoffs(handoffs)
 	if len(handoffs) == 0 {
 		return
 	}
@@ -1055,6 +1264,7 @@ func waitForAnySessionSpawns(ctx context.Context, s *store.Store, parentTurnID i
 					ReadOnly:          sr.ReadOnly,
 					Branch:            sr.Branch,
 					Review:            sr.Review,
+					QueuePosition:     sr.QueuePosition,
 					Elapsed:           sr.Elapsed,
 					CompactionCount:   sr.CompactionCount,
 					ReadCount:         sr.ReadCount,
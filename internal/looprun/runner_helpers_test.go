@@ -529,6 +529,22 @@ func TestBuildAgentConfig_UsesLoopResourcePriority(t *testing.T) {
 	}
 }
 
+func TestProfileFallbacks_KeepStepSandbox(t *testing.T) {
+	globalCfg := &config.GlobalConfig{Profiles: []config.Profile{
+		{Name: "lead", Agent: "claude", Fallback: []string{"lead-codex"}, Sandbox: &config.Sandbox{Network: config.SandboxNetworkNone}},
+		{Name: "lead-codex", Agent: "codex"},
+	}}
+	cfg := RunConfig{WorkDir: "/tmp", GlobalCfg: globalCfg, AgentsCfg: &agent.AgentsConfig{}}
+
+	fbs := profileFallbacks(cfg, globalCfg.FindProfile("lead"), config.LoopStep{Position: config.PositionLead}, 1, 0, "", "", nil)
+	if len(fbs) != 1 || fbs[0].ProfileName != "lead-codex" {
+		t.Fatalf("fallbacks = %+v, want lead-codex", fbs)
+	}
+	if sb := fbs[0].Config.Sandbox; sb == nil || sb.Network != config.SandboxNetworkNone {
+		t.Fatalf("fallback sandbox = %+v, want the step's network=none sandbox", sb)
+	}
+}
+
 func TestNextStepResumeSessionID_StandaloneUsesBaseResume(t *testing.T) {
 	prof := &config.Profile{Name: "p1", Agent: "codex"}
 	step := config.LoopStep{StandaloneChat: true}
@@ -0,0 +1,114 @@
+package looprun
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// consumeStepOutcome returns the outcome token reported by the given step and
+// clears it. Outcomes reported from a different step are discarded.
+func consumeStepOutcome(s *store.Store, run *store.LoopRun, stepIdx int) string {
+	if s == nil || run == nil || run.ID <= 0 {
+		return ""
+	}
+	out, err := s.ConsumeLoopStepOutcome(run.ID)
+	if err != nil {
+		debug.LogKV("looprun", "reading step outcome failed", "run_id", run.ID, "error", err)
+		return ""
+	}
+	if out == nil {
+		return ""
+	}
+	if out.StepIndex != stepIdx {
+		debug.LogKV("looprun", "ignoring stale step outcome",
+			"run_id", run.ID,
+			"current_step", stepIdx,
+			"outcome_step", out.StepIndex,
+		)
+		return ""
+	}
+	return out.Outcome
+}
+
+// stepTransitionFields collects the values transition conditions are
+// evaluated against: the last finished turn of the step, the step outcome,
+// and issue counts for the run's plan.
+func stepTransitionFields(s *store.Store, planID string, turnIDs []int, outcome string) map[string]string {
+	fields := map[string]string{
+		config.TransitionFieldOutcome: outcome,
+	}
+	if s == nil {
+		return fields
+	}
+
+	for i := len(turnIDs) - 1; i >= 0; i-- {
+		turn, err := s.GetTurn(turnIDs[i])
+		if err != nil || turn == nil {
+			continue
+		}
+		fields["build_state"] = turn.BuildState
+		fields["current_state"] = turn.CurrentState
+		fields["known_issues"] = turn.KnownIssues
+		fields["next_steps"] = turn.NextSteps
+		fields["what_was_built"] = turn.WhatWasBuilt
+		fields["objective"] = turn.Objective
+		fields["commit_hash"] = turn.CommitHash
+		fields["agent"] = turn.Agent
+		fields["agent_model"] = turn.AgentModel
+		fields["profile_name"] = turn.ProfileName
+		fields["duration_secs"] = strconv.Itoa(turn.DurationSecs)
+		break
+	}
+
+	issues, err := s.ListIssuesForPlan(planID)
+	if err != nil {
+		debug.LogKV("looprun", "listing issues for transitions failed", "plan_id", planID, "error", err)
+		return fields
+	}
+	counts := map[string]int{
+		"issues.open": 0, "issues.ongoing": 0, "issues.in_review": 0, "issues.closed": 0,
+		"issues.critical": 0, "issues.high": 0, "issues.medium": 0, "issues.low": 0,
+	}
+	for _, issue := range issues {
+		status := strings.ToLower(strings.TrimSpace(issue.Status))
+		counts["issues."+status]++
+		if status != store.IssueStatusClosed {
+			counts["issues."+strings.ToLower(strings.TrimSpace(issue.Priority))]++
+		}
+	}
+	for k, v := range counts {
+		fields[k] = strconv.Itoa(v)
+	}
+	return fields
+}
+
+// matchStepTransition evaluates the transitions of the step at stepIdx in
+// order. It returns the target step index, or stop=true for a "stop"
+// target; matched is false when no transition applies.
+func matchStepTransition(loopDef *config.LoopDef, stepIdx int, fields map[string]string) (target int, stop bool, matched bool) {
+	step := loopDef.Steps[stepIdx]
+	for _, tr := range step.Transitions {
+		cond, err := config.ParseStepCondition(tr.When)
+		if err != nil {
+			debug.LogKV("looprun", "skipping invalid transition", "step", stepIdx, "when", tr.When, "error", err)
+			continue
+		}
+		if !cond.Match(fields) {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(tr.Goto), config.TransitionStop) {
+			return 0, true, true
+		}
+		idx, ok := loopDef.FindStepIndex(tr.Goto)
+		if !ok {
+			debug.LogKV("looprun", "skipping transition with unknown target", "step", stepIdx, "goto", tr.Goto)
+			continue
+		}
+		return idx, false, true
+	}
+	return 0, false, false
+}
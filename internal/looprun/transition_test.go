@@ -0,0 +1,155 @@
+package looprun
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func TestRun_TransitionsRouteOnIssueCounts(t *testing.T) {
+	tests := []struct {
+		name       string
+		openIssue  bool
+		wantOrders []string
+	}{
+		{name: "open issues go to fixer", openIssue: true, wantOrders: []string{"builder", "fixer"}},
+		{name: "clean goes to review then stops", openIssue: false, wantOrders: []string{"builder", "reviewer"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newLooprunTestStore(t)
+			proj, err := s.LoadProject()
+			if err != nil {
+				t.Fatalf("LoadProject: %v", err)
+			}
+			if tt.openIssue {
+				if err := s.CreateIssue(&store.Issue{Title: "tests failing", Status: store.IssueStatusOpen, Priority: "high"}); err != nil {
+					t.Fatalf("CreateIssue: %v", err)
+				}
+			}
+
+			scriptPath := filepath.Join(t.TempDir(), "ok.sh")
+			if err := os.WriteFile(scriptPath, []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+
+			loopDef := &config.LoopDef{
+				Name: "qa",
+				Steps: []config.LoopStep{
+					{ID: "build", Profile: "builder", Transitions: []config.StepTransition{
+						{When: "issues.open > 0", Goto: "fixer"},
+						{Goto: "review"},
+					}},
+					{ID: "review", Profile: "reviewer", Transitions: []config.StepTransition{{Goto: "stop"}}},
+					{ID: "fixer", Profile: "fixer"},
+				},
+			}
+			globalCfg := &config.GlobalConfig{
+				Profiles: []config.Profile{
+					{Name: "builder", Agent: "generic"},
+					{Name: "reviewer", Agent: "generic"},
+					{Name: "fixer", Agent: "generic"},
+				},
+			}
+			agentsCfg := &agent.AgentsConfig{
+				Agents: map[string]agent.AgentRecord{
+					"generic": {Name: "generic", Path: scriptPath},
+				},
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := Run(ctx, RunConfig{
+				Store:     s,
+				GlobalCfg: globalCfg,
+				LoopDef:   loopDef,
+				Project:   proj,
+				AgentsCfg: agentsCfg,
+				WorkDir:   proj.RepoPath,
+				MaxCycles: 1,
+			}, nil); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			turns, err := s.ListTurns()
+			if err != nil {
+				t.Fatalf("ListTurns: %v", err)
+			}
+			if len(turns) != len(tt.wantOrders) {
+				t.Fatalf("len(turns) = %d, want %d", len(turns), len(tt.wantOrders))
+			}
+			for i, want := range tt.wantOrders {
+				if turns[i].ProfileName != want {
+					t.Fatalf("turns[%d].ProfileName = %q, want %q", i, turns[i].ProfileName, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchStepTransitionUsesOutcomeAndTurnFields(t *testing.T) {
+	s := newLooprunTestStore(t)
+	turn := &store.Turn{ProfileName: "tester", BuildState: "failing"}
+	if err := s.CreateTurn(turn); err != nil {
+		t.Fatalf("CreateTurn: %v", err)
+	}
+	run := &store.LoopRun{LoopName: "qa"}
+	if err := s.CreateLoopRun(run); err != nil {
+		t.Fatalf("CreateLoopRun: %v", err)
+	}
+	if err := s.SetLoopStepOutcome(run.ID, 0, "needs_review"); err != nil {
+		t.Fatalf("SetLoopStepOutcome: %v", err)
+	}
+
+	loopDef := &config.LoopDef{
+		Steps: []config.LoopStep{
+			{ID: "test", Profile: "tester", Transitions: []config.StepTransition{
+				{When: "build_state == failing && outcome != needs_review", Goto: "fix"},
+				{When: "outcome == needs_review", Goto: "review"},
+			}},
+			{ID: "review", Profile: "reviewer"},
+			{ID: "fix", Profile: "fixer"},
+		},
+	}
+
+	outcome := consumeStepOutcome(s, run, 0)
+	if outcome != "needs_review" {
+		t.Fatalf("consumeStepOutcome() = %q, want %q", outcome, "needs_review")
+	}
+	if again := consumeStepOutcome(s, run, 0); again != "" {
+		t.Fatalf("second consumeStepOutcome() = %q, want empty", again)
+	}
+
+	fields := stepTransitionFields(s, "", []int{turn.ID}, outcome)
+	target, stop, matched := matchStepTransition(loopDef, 0, fields)
+	if !matched || stop || target != 1 {
+		t.Fatalf("matchStepTransition() = (%d, %v, %v), want review step", target, stop, matched)
+	}
+
+	fields = stepTransitionFields(s, "", []int{turn.ID}, "")
+	target, _, matched = matchStepTransition(loopDef, 0, fields)
+	if !matched || target != 2 {
+		t.Fatalf("matchStepTransition() without outcome = (%d, %v), want fix step", target, matched)
+	}
+}
+
+func TestConsumeStepOutcomeIgnoresOtherSteps(t *testing.T) {
+	s := newLooprunTestStore(t)
+	run := &store.LoopRun{LoopName: "qa"}
+	if err := s.CreateLoopRun(run); err != nil {
+		t.Fatalf("CreateLoopRun: %v", err)
+	}
+	if err := s.SetLoopStepOutcome(run.ID, 2, "done"); err != nil {
+		t.Fatalf("SetLoopStepOutcome: %v", err)
+	}
+	if got := consumeStepOutcome(s, run, 0); got != "" {
+		t.Fatalf("consumeStepOutcome() = %q, want empty for stale step", got)
+	}
+}
@@ -0,0 +1,87 @@
+package looprun
+
+import (
+	"context"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/events"
+	"github.com/agusx1211/adaf/internal/store"
+	"github.com/agusx1211/adaf/internal/usage"
+)
+
+func (cfg RunConfig) usageChecker() usage.Checker {
+	if cfg.UsageChecker != nil {
+		return cfg.UsageChecker
+	}
+	return usage.DefaultMonitor()
+}
+
+// gateStepProfile applies the loop's usage gate before a step (or group
+// member) launches prof. It returns the profile to run: prof itself, or for
+// the route action the first fallback profile with headroom, kept in prof's
+// sandbox. When nothing has headroom it pauses until prof's provider
+// recovers, recording the pause on the run so status views can show it.
+func gateStepProfile(ctx context.Context, cfg RunConfig, run *store.LoopRun, prof *config.Profile, eventCh chan any) (*config.Profile, error) {
+	var gate *config.UsageGate
+	if cfg.LoopDef != nil {
+		gate = cfg.LoopDef.UsageGate
+	}
+	if gate == nil {
+		return prof, nil
+	}
+	checker := cfg.usageChecker()
+	threshold := gate.EffectiveThreshold()
+	block, err := checker.Check(ctx, prof.Agent, threshold)
+	if err != nil {
+		debug.LogKV("looprun", "usage check failed; running step", "profile", prof.Name, "error", err)
+		return prof, nil
+	}
+	if block == nil {
+		return prof, nil
+	}
+	if gate.EffectiveAction() == config.UsageGateActionRoute && cfg.GlobalCfg != nil {
+		if alt := usage.FirstAvailable(ctx, checker, cfg.GlobalCfg.FallbackProfiles(prof.Name), threshold); alt != nil {
+			debug.LogKV("looprun", "usage gate routed step to fallback profile",
+				"run_id", run.ID,
+				"profile", prof.Name,
+				"routed_to", alt.Name,
+				"block", block.String(),
+			)
+			return withStepSandbox(*alt, prof), nil
+		}
+	}
+
+	started := time.Now().UTC()
+	err = usage.WaitForHeadroom(ctx, checker, prof.Agent, gate, block, func(b *usage.Block, until time.Time) {
+		debug.LogKV("looprun", "usage gate paused step",
+			"run_id", run.ID,
+			"profile", prof.Name,
+			"block", b.String(),
+			"until", until,
+		)
+		pause := &store.UsagePause{
+			Profile:        prof.Name,
+			Provider:       string(b.Provider),
+			Limit:          b.Limit.Name,
+			UtilizationPct: b.Limit.UtilizationPct,
+			Until:          until.UTC(),
+			StartedAt:      started,
+		}
+		run.UsagePause = pause
+		cfg.Store.UpdateLoopRun(run)
+		emitLoopEvent(eventCh, "loop_usage_pause", events.LoopUsagePauseMsg{RunID: run.ID, Pause: pause})
+	})
+	run.UsagePause = nil
+	cfg.Store.UpdateLoopRun(run)
+	emitLoopEvent(eventCh, "loop_usage_pause", events.LoopUsagePauseMsg{RunID: run.ID})
+	return prof, err
+}
+
+// unbindUsageGate drops the gate bindings made for a step's turns.
+func unbindUsageGate(turnIDs []int) {
+	for _, id := range turnIDs {
+		usage.UnbindGate(id)
+	}
+}
@@ -0,0 +1,119 @@
+package looprun
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/events"
+	"github.com/agusx1211/adaf/internal/store"
+	"github.com/agusx1211/adaf/internal/usage"
+)
+
+// agentUsageChecker blocks the listed agents until their check budget runs out.
+type agentUsageChecker struct {
+	blockedChecks map[string]int
+}
+
+func (c *agentUsageChecker) Check(ctx context.Context, agentName string, threshold float64) (*usage.Block, error) {
+	if c.blockedChecks[agentName] <= 0 {
+		return nil, nil
+	}
+	c.blockedChecks[agentName]--
+	reset := time.Now().Add(10 * time.Millisecond)
+	return &usage.Block{
+		Provider: usage.ProviderClaude,
+		Limit:    usage.UsageLimit{Name: "5-hour window", UtilizationPct: 97, ResetsAt: &reset},
+	}, nil
+}
+
+func TestGateStepProfileRoutesToFallbackWithHeadroom(t *testing.T) {
+	s := newLooprunTestStore(t)
+	run := &store.LoopRun{StepLastSeenMsg: make(map[int]int)}
+	if err := s.CreateLoopRun(run); err != nil {
+		t.Fatalf("CreateLoopRun: %v", err)
+	}
+	globalCfg := &config.GlobalConfig{Profiles: []config.Profile{
+		{Name: "lead", Agent: "claude", Fallback: []string{"lead-codex"}, Sandbox: &config.Sandbox{Network: config.SandboxNetworkNone}},
+		{Name: "lead-codex", Agent: "codex"},
+	}}
+	cfg := RunConfig{
+		Store:        s,
+		GlobalCfg:    globalCfg,
+		LoopDef:      &config.LoopDef{Name: "l", UsageGate: &config.UsageGate{Action: config.UsageGateActionRoute}},
+		UsageChecker: &agentUsageChecker{blockedChecks: map[string]int{"claude": 1}},
+	}
+
+	got, err := gateStepProfile(context.Background(), cfg, run, globalCfg.FindProfile("lead"), nil)
+	if err != nil {
+		t.Fatalf("gateStepProfile: %v", err)
+	}
+	if got.Name != "lead-codex" {
+		t.Fatalf("profile = %q, want lead-codex", got.Name)
+	}
+	if sb := got.Sandbox; sb == nil || sb.Network != config.SandboxNetworkNone {
+		t.Fatalf("routed sandbox = %+v, want the step's network=none sandbox", sb)
+	}
+}
+
+func TestGateStepProfilePausesUntilReset(t *testing.T) {
+	s := newLooprunTestStore(t)
+	run := &store.LoopRun{StepLastSeenMsg: make(map[int]int)}
+	if err := s.CreateLoopRun(run); err != nil {
+		t.Fatalf("CreateLoopRun: %v", err)
+	}
+	prof := &config.Profile{Name: "lead", Agent: "claude"}
+	cfg := RunConfig{
+		Store:     s,
+		GlobalCfg: &config.GlobalConfig{Profiles: []config.Profile{*prof}},
+		LoopDef:   &config.LoopDef{Name: "l", UsageGate: &config.UsageGate{Action: config.UsageGateActionRoute}},
+		// Blocked for the initial check and one re-check; no fallbacks exist.
+		UsageChecker: &agentUsageChecker{blockedChecks: map[string]int{"claude": 2}},
+	}
+	eventCh := make(chan any, 8)
+
+	got, err := gateStepProfile(context.Background(), cfg, run, prof, eventCh)
+	if err != nil {
+		t.Fatalf("gateStepProfile: %v", err)
+	}
+	if got != prof {
+		t.Fatalf("profile = %q, want the original profile", got.Name)
+	}
+	if run.UsagePause != nil {
+		t.Fatalf("run.UsagePause = %+v, want cleared after resume", run.UsagePause)
+	}
+
+	close(eventCh)
+	var pauses, resumes int
+	for ev := range eventCh {
+		msg, ok := ev.(events.LoopUsagePauseMsg)
+		if !ok {
+			continue
+		}
+		if msg.Pause == nil {
+			resumes++
+			continue
+		}
+		pauses++
+		if msg.Pause.Profile != "lead" || msg.Pause.Limit != "5-hour window" || msg.Pause.Until.IsZero() {
+			t.Fatalf("pause = %+v", msg.Pause)
+		}
+	}
+	if pauses != 2 || resumes != 1 {
+		t.Fatalf("pause events = %d, resume events = %d; want 2 and 1", pauses, resumes)
+	}
+}
+
+func TestGateStepProfileWithoutGateSkipsChecks(t *testing.T) {
+	checker := &agentUsageChecker{blockedChecks: map[string]int{"claude": 5}}
+	prof := &config.Profile{Name: "lead", Agent: "claude"}
+	cfg := RunConfig{LoopDef: &config.LoopDef{Name: "l"}, UsageChecker: checker}
+	got, err := gateStepProfile(context.Background(), cfg, &store.LoopRun{}, prof, nil)
+	if err != nil || got != prof {
+		t.Fatalf("gateStepProfile = %v, %v; want original profile", got, err)
+	}
+	if checker.blockedChecks["claude"] != 5 {
+		t.Fatal("checker was consulted without a usage gate")
+	}
+}
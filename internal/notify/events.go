@@ -0,0 +1,175 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/pushover"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// LoopDone describes a finished loop run. runErr is the error the run ended
+// with, if any.
+func LoopDone(project string, run *store.LoopRun, runErr error) Event {
+	msg := fmt.Sprintf("Loop %q run #%d ended: %s after %d cycle(s).", run.LoopName, run.ID, run.Status, run.Cycle+1)
+	if runErr != nil && !errors.Is(runErr, context.Canceled) {
+		msg += "\n" + runErr.Error()
+	}
+	return Event{
+		Kind:    config.NotifyLoopDone,
+		Title:   fmt.Sprintf("Loop %s %s", run.LoopName, run.Status),
+		Message: msg,
+		Project: project,
+		Fields: map[string]string{
+			"loop":   run.LoopName,
+			"run_id": strconv.Itoa(run.ID),
+			"status": run.Status,
+		},
+	}
+}
+
+// LoopMessage is a notification sent by a loop step.
+func LoopMessage(project string, runID int, title, message string, priority int) Event {
+	return Event{
+		Kind:     config.NotifyLoopMessage,
+		Title:    title,
+		Message:  message,
+		Priority: priority,
+		Project:  project,
+		Fields:   map[string]string{"run_id": strconv.Itoa(runID)},
+	}
+}
+
+// SpawnFailed describes a spawn that ended failed or failed verification.
+func SpawnFailed(project string, rec *store.SpawnRecord) Event {
+	msg := fmt.Sprintf("Spawn #%d (%s) %s.", rec.ID, rec.ChildProfile, rec.Status)
+	if rec.Result != "" {
+		msg += "\n" + rec.Result
+	}
+	return Event{
+		Kind:     config.NotifySpawnFailed,
+		Title:    fmt.Sprintf("Spawn #%d %s", rec.ID, rec.Status),
+		Message:  msg,
+		Priority: pushover.PriorityHigh,
+		Project:  project,
+		Fields:   spawnFields(rec),
+	}
+}
+
+// SpawnBudgetExceeded describes a spawn stopped by its budget.
+func SpawnBudgetExceeded(project string, rec *store.SpawnRecord, budgetErr error) Event {
+	return Event{
+		Kind:     config.NotifyBudgetExceeded,
+		Title:    fmt.Sprintf("Spawn #%d over budget", rec.ID),
+		Message:  budgetErr.Error(),
+		Priority: pushover.PriorityHigh,
+		Project:  project,
+		Fields:   spawnFields(rec),
+	}
+}
+
+// LoopBudgetExceeded describes a loop run stopped by its budget.
+func LoopBudgetExceeded(project string, run *store.LoopRun, budgetErr error) Event {
+	return Event{
+		Kind:     config.NotifyBudgetExceeded,
+		Title:    fmt.Sprintf("Loop %s over budget", run.LoopName),
+		Message:  budgetErr.Error(),
+		Priority: pushover.PriorityHigh,
+		Project:  project,
+		Fields: map[string]string{
+			"loop":   run.LoopName,
+			"run_id": strconv.Itoa(run.ID),
+		},
+	}
+}
+
+// ParentAsk describes a child blocked on a question for its parent.
+func ParentAsk(project string, rec *store.SpawnRecord, question string) Event {
+	return Event{
+		Kind:     config.NotifyParentAsk,
+		Title:    fmt.Sprintf("Spawn #%d is waiting for an answer", rec.ID),
+		Message:  question,
+		Priority: pushover.PriorityHigh,
+		Project:  project,
+		Fields:   spawnFields(rec),
+	}
+}
+
+// AskEscalated describes a child question that nobody in its spawn tree
+// answered in time and now waits on the human.
+func AskEscalated(project string, rec *store.SpawnRecord, ask *store.SpawnMessage) Event {
+	fields := spawnFields(rec)
+	fields["asked_at"] = ask.CreatedAt.UTC().Format(time.RFC3339)
+	return Event{
+		Kind:     config.NotifyParentAsk,
+		Title:    fmt.Sprintf("Spawn #%d question escalated to you", rec.ID),
+		Message:  fmt.Sprintf("%s\n\nAnswer with `adaf inbox answer %d \"...\"`.", ask.Content, rec.ID),
+		Priority: pushover.PriorityHigh,
+		Project:  project,
+		Fields:   fields,
+	}
+}
+
+// IssueInReview describes an issue that moved to in_review.
+func IssueInReview(project string, issue *store.Issue) Event {
+	fields := map[string]string{
+		"issue_id": strconv.Itoa(issue.ID),
+		"priority": issue.Priority,
+	}
+	if issue.UpdatedBy != "" {
+		fields["by"] = issue.UpdatedBy
+	}
+	return Event{
+		Kind:    config.NotifyIssueInReview,
+		Title:   fmt.Sprintf("Issue #%d ready for review", issue.ID),
+		Message: issue.Title,
+		Project: project,
+		Fields:  fields,
+	}
+}
+
+func spawnFields(rec *store.SpawnRecord) map[string]string {
+	fields := map[string]string{
+		"spawn_id": strconv.Itoa(rec.ID),
+		"profile":  rec.ChildProfile,
+		"status":   rec.Status,
+	}
+	if rec.ChildRole != "" {
+		fields["role"] = rec.ChildRole
+	}
+	if rec.Branch != "" {
+		fields["branch"] = rec.Branch
+	}
+	return fields
+}
+
+// ApprovalNeeded describes an action parked until a human decides.
+func ApprovalNeeded(project string, a *store.Approval) Event {
+	fields := map[string]string{
+		"approval_id": strconv.Itoa(a.ID),
+		"kind":        a.Kind,
+	}
+	if a.SpawnID > 0 {
+		fields["spawn_id"] = strconv.Itoa(a.SpawnID)
+	}
+	if a.LoopName != "" {
+		fields["loop"] = a.LoopName
+	}
+	msg := a.Summary
+	if a.Reason != "" {
+		msg += "\n" + a.Reason
+	}
+	msg += fmt.Sprintf("\nRun `adaf approve %d` or `adaf deny %d`.", a.ID, a.ID)
+	return Event{
+		Kind:     config.NotifyApprovalNeeded,
+		Title:    fmt.Sprintf("Approval #%d needed", a.ID),
+		Message:  msg,
+		Priority: pushover.PriorityHigh,
+		Project:  project,
+		Fields:   fields,
+	}
+}
@@ -0,0 +1,224 @@
+// Package notify delivers lifecycle notifications to the sinks configured
+// under "notify" in the global config: signed webhooks, Slack and Discord
+// incoming webhooks, SMTP email, local commands and Pushover.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/pushover"
+)
+
+// publishTimeout bounds one asynchronous Publish across all sinks.
+const publishTimeout = 30 * time.Second
+
+// deliverTimeout bounds one synchronous Deliver across all sinks.
+const deliverTimeout = 10 * time.Second
+
+// Event is one notification. It is also the JSON body POSTed to webhooks and
+// piped to command sinks.
+type Event struct {
+	Kind     string            `json:"event"`
+	Title    string            `json:"title"`
+	Message  string            `json:"message"`
+	Priority int               `json:"priority,omitempty"` // Pushover scale: -2 (lowest) to 1 (high)
+	Project  string            `json:"project,omitempty"`
+	Fields   map[string]string `json:"fields,omitempty"`
+	Time     time.Time         `json:"time"`
+}
+
+// Text renders the event as plain text: the message followed by its fields.
+func (e Event) Text() string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(e.Message))
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		b.WriteString("\n")
+	}
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n%s: %s", k, e.Fields[k])
+	}
+	if e.Project != "" {
+		fmt.Fprintf(&b, "\nproject: %s", e.Project)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Sink delivers events to one destination.
+type Sink interface {
+	Send(ctx context.Context, ev Event) error
+}
+
+type boundSink struct {
+	cfg  config.NotifySink
+	sink Sink
+}
+
+// Notifier fans events out to the sinks subscribed to them.
+type Notifier struct {
+	sinks []boundSink
+}
+
+// New builds a notifier from the global config. Invalid sinks are skipped
+// and reported in the returned error; the notifier is always usable.
+//
+// Pushover credentials set with `adaf config pushover setup` act as an
+// implicit "pushover" sink for loop_notify events unless a sink of that
+// name is configured.
+func New(cfg *config.GlobalConfig) (*Notifier, error) {
+	n := &Notifier{}
+	if cfg == nil {
+		return n, nil
+	}
+	var errs []error
+	seen := make(map[string]bool)
+	for _, sc := range cfg.Notify.Sinks {
+		if err := sc.Validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if seen[sc.Name] {
+			errs = append(errs, fmt.Errorf("duplicate notify sink %q", sc.Name))
+			continue
+		}
+		seen[sc.Name] = true
+		n.sinks = append(n.sinks, boundSink{cfg: sc, sink: newSink(sc, &cfg.Pushover)})
+	}
+	if !seen[config.NotifySinkPushover] && pushover.Configured(&cfg.Pushover) {
+		sc := config.NotifySink{
+			Name:   config.NotifySinkPushover,
+			Type:   config.NotifySinkPushover,
+			Events: []string{config.NotifyLoopMessage},
+		}
+		n.sinks = append(n.sinks, boundSink{cfg: sc, sink: newSink(sc, &cfg.Pushover)})
+	}
+	return n, errors.Join(errs...)
+}
+
+func newSink(sc config.NotifySink, pc *config.PushoverConfig) Sink {
+	switch sc.Type {
+	case config.NotifySinkWebhook:
+		return &webhookSink{url: sc.URL, secret: sc.Secret, headers: sc.Headers}
+	case config.NotifySinkSlack, config.NotifySinkDiscord:
+		return &chatSink{url: sc.URL, discord: sc.Type == config.NotifySinkDiscord}
+	case config.NotifySinkEmail:
+		return &emailSink{host: sc.SMTPHost, port: sc.SMTPPort, username: sc.Username, password: sc.Password, from: sc.From, to: sc.To}
+	case config.NotifySinkCommand:
+		return &commandSink{command: sc.Command}
+	default:
+		creds := *pc
+		return &pushoverSink{cfg: &creds}
+	}
+}
+
+// Sinks returns the configuration of every active sink.
+func (n *Notifier) Sinks() []config.NotifySink {
+	if n == nil {
+		return nil
+	}
+	out := make([]config.NotifySink, len(n.sinks))
+	for i, s := range n.sinks {
+		out[i] = s.cfg
+	}
+	return out
+}
+
+// Notify delivers ev to every subscribed sink concurrently and returns how
+// many sinks it was sent to. Failures of individual sinks are joined.
+func (n *Notifier) Notify(ctx context.Context, ev Event) (int, error) {
+	if n == nil {
+		return 0, nil
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now().UTC()
+	}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		sent int
+	)
+	for _, s := range n.sinks {
+		if !s.cfg.Subscribes(ev.Kind) {
+			continue
+		}
+		sent++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.sink.Send(ctx, ev); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", s.cfg.Name, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return sent, errors.Join(errs...)
+}
+
+// SendTo delivers ev to the named sink regardless of its subscriptions.
+func (n *Notifier) SendTo(ctx context.Context, name string, ev Event) error {
+	if n != nil {
+		for _, s := range n.sinks {
+			if s.cfg.Name == name {
+				if ev.Time.IsZero() {
+					ev.Time = time.Now().UTC()
+				}
+				return s.sink.Send(ctx, ev)
+			}
+		}
+	}
+	return fmt.Errorf("notify sink %q not found", name)
+}
+
+// Publish notifies cfg's sinks in the background. It is meant for
+// long-running processes; failures are only logged.
+func Publish(cfg *config.GlobalConfig, ev Event) {
+	n, err := New(cfg)
+	if err != nil {
+		debug.LogKV("notify", "skipping invalid sinks", "error", err)
+	}
+	if len(n.sinks) == 0 {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		defer cancel()
+		if sent, err := n.Notify(ctx, ev); err != nil {
+			debug.LogKV("notify", "delivery failed", "event", ev.Kind, "sinks", sent, "error", err)
+		}
+	}()
+}
+
+// Deliver notifies cfg's sinks of each event and waits for delivery, up to
+// a bounded timeout. It is meant for processes that may exit right after;
+// failures are only logged.
+func Deliver(cfg *config.GlobalConfig, events ...Event) {
+	n, err := New(cfg)
+	if err != nil {
+		debug.LogKV("notify", "skipping invalid sinks", "error", err)
+	}
+	if len(n.sinks) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), deliverTimeout)
+	defer cancel()
+	for _, ev := range events {
+		if sent, err := n.Notify(ctx, ev); err != nil {
+			debug.LogKV("notify", "delivery failed", "event", ev.Kind, "sinks", sent, "error", err)
+		}
+	}
+}
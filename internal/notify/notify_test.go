@@ -0,0 +1,282 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+type capturedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// standIn is a local HTTP server that records the requests it receives.
+func standIn(t *testing.T, status int) (*httptest.Server, chan capturedRequest) {
+	t.Helper()
+	reqs := make(chan capturedRequest, 8)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		reqs <- capturedRequest{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, reqs
+}
+
+func receive(t *testing.T, reqs chan capturedRequest) capturedRequest {
+	t.Helper()
+	select {
+	case r := <-reqs:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("stand-in received no request")
+		return capturedRequest{}
+	}
+}
+
+func TestWebhookSinkSignsBody(t *testing.T) {
+	srv, reqs := standIn(t, http.StatusNoContent)
+	cfg := &config.GlobalConfig{Notify: config.NotifyConfig{Sinks: []config.NotifySink{
+		{Name: "hook", Type: config.NotifySinkWebhook, URL: srv.URL, Secret: "s3cret", Headers: map[string]string{"X-Team": "core"}},
+	}}}
+	n, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rec := &store.SpawnRecord{ID: 7, ChildProfile: "worker", Status: store.SpawnStatusFailed, Result: "exit code 1"}
+	sent, err := n.Notify(context.Background(), SpawnFailed("demo", rec))
+	if err != nil || sent != 1 {
+		t.Fatalf("Notify = %d, %v; want 1, nil", sent, err)
+	}
+
+	got := receive(t, reqs)
+	if sig := got.header.Get(SignatureHeader); sig != Sign("s3cret", got.body) {
+		t.Fatalf("signature = %q, want %q", sig, Sign("s3cret", got.body))
+	}
+	if kind := got.header.Get(EventHeader); kind != config.NotifySpawnFailed {
+		t.Fatalf("event header = %q", kind)
+	}
+	if got.header.Get("X-Team") != "core" {
+		t.Fatalf("custom header missing: %v", got.header)
+	}
+	var ev Event
+	if err := json.Unmarshal(got.body, &ev); err != nil {
+		t.Fatalf("decoding body: %v", err)
+	}
+	if ev.Kind != config.NotifySpawnFailed || ev.Project != "demo" || ev.Fields["spawn_id"] != "7" || ev.Time.IsZero() {
+		t.Fatalf("unexpected payload: %+v", ev)
+	}
+}
+
+func TestWebhookSinkReportsHTTPErrors(t *testing.T) {
+	srv, _ := standIn(t, http.StatusBadGateway)
+	n, _ := New(&config.GlobalConfig{Notify: config.NotifyConfig{Sinks: []config.NotifySink{
+		{Name: "hook", Type: config.NotifySinkWebhook, URL: srv.URL},
+	}}})
+	_, err := n.Notify(context.Background(), Event{Kind: config.NotifyLoopDone, Title: "t"})
+	if err == nil || !strings.Contains(err.Error(), "hook: HTTP 502") {
+		t.Fatalf("Notify error = %v, want HTTP 502 from hook", err)
+	}
+}
+
+func TestChatSinkPayloads(t *testing.T) {
+	slackSrv, slackReqs := standIn(t, http.StatusOK)
+	discordSrv, discordReqs := standIn(t, http.StatusNoContent)
+	n, err := New(&config.GlobalConfig{Notify: config.NotifyConfig{Sinks: []config.NotifySink{
+		{Name: "slack", Type: config.NotifySinkSlack, URL: slackSrv.URL},
+		{Name: "discord", Type: config.NotifySinkDiscord, URL: discordSrv.URL},
+	}}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	issue := &store.Issue{ID: 3, Title: "Fix login", Priority: "high"}
+	if _, err := n.Notify(context.Background(), IssueInReview("demo", issue)); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	var slack map[string]string
+	json.Unmarshal(receive(t, slackReqs).body, &slack)
+	if !strings.HasPrefix(slack["text"], "*Issue #3 ready for review*\nFix login") {
+		t.Fatalf("slack text = %q", slack["text"])
+	}
+	var discord map[string]string
+	json.Unmarshal(receive(t, discordReqs).body, &discord)
+	if !strings.HasPrefix(discord["content"], "**Issue #3 ready for review**\nFix login") {
+		t.Fatalf("discord content = %q", discord["content"])
+	}
+}
+
+func TestDiscordSinkTruncatesByCharacter(t *testing.T) {
+	srv, reqs := standIn(t, http.StatusNoContent)
+	n, _ := New(&config.GlobalConfig{Notify: config.NotifyConfig{Sinks: []config.NotifySink{
+		{Name: "discord", Type: config.NotifySinkDiscord, URL: srv.URL},
+	}}})
+	if _, err := n.Notify(context.Background(), Event{Kind: config.NotifyLoopDone, Title: "done", Message: strings.Repeat("é", 3000)}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	var discord map[string]string
+	json.Unmarshal(receive(t, reqs).body, &discord)
+	content := discord["content"]
+	if got := utf8.RuneCountInString(content); got != discordContentLimit || !utf8.ValidString(content) {
+		t.Fatalf("content has %d characters (valid UTF-8 %v), want %d", got, utf8.ValidString(content), discordContentLimit)
+	}
+}
+
+func TestNotifyHonoursSubscriptions(t *testing.T) {
+	srv, reqs := standIn(t, http.StatusOK)
+	n, _ := New(&config.GlobalConfig{Notify: config.NotifyConfig{Sinks: []config.NotifySink{
+		{Name: "budget-only", Type: config.NotifySinkWebhook, URL: srv.URL, Events: []string{config.NotifyBudgetExceeded}},
+	}}})
+
+	sent, err := n.Notify(context.Background(), Event{Kind: config.NotifyLoopDone, Title: "done"})
+	if err != nil || sent != 0 {
+		t.Fatalf("unsubscribed Notify = %d, %v; want 0, nil", sent, err)
+	}
+	sent, err = n.Notify(context.Background(), Event{Kind: config.NotifyBudgetExceeded, Title: "over"})
+	if err != nil || sent != 1 {
+		t.Fatalf("subscribed Notify = %d, %v; want 1, nil", sent, err)
+	}
+	receive(t, reqs)
+	select {
+	case r := <-reqs:
+		t.Fatalf("unexpected extra request: %s", r.body)
+	default:
+	}
+
+	// SendTo bypasses subscriptions.
+	if err := n.SendTo(context.Background(), "budget-only", Event{Kind: "test", Title: "t"}); err != nil {
+		t.Fatalf("SendTo: %v", err)
+	}
+	receive(t, reqs)
+	if err := n.SendTo(context.Background(), "missing", Event{}); err == nil {
+		t.Fatal("SendTo unknown sink should fail")
+	}
+}
+
+func TestCommandSinkReceivesEvent(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "event.json")
+	n, _ := New(&config.GlobalConfig{Notify: config.NotifyConfig{Sinks: []config.NotifySink{
+		{Name: "local", Type: config.NotifySinkCommand, Command: `cat > "` + out + `"; echo "$ADAF_NOTIFY_EVENT" >> "` + out + `.kind"`},
+	}}})
+	if _, err := n.Notify(context.Background(), Event{Kind: config.NotifyParentAsk, Title: "question"}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("reading command output: %v", err)
+	}
+	var ev Event
+	if err := json.Unmarshal(data, &ev); err != nil || ev.Title != "question" {
+		t.Fatalf("stdin event = %s (%v)", data, err)
+	}
+	kind, _ := os.ReadFile(out + ".kind")
+	if strings.TrimSpace(string(kind)) != config.NotifyParentAsk {
+		t.Fatalf("ADAF_NOTIFY_EVENT = %q", kind)
+	}
+
+	n, _ = New(&config.GlobalConfig{Notify: config.NotifyConfig{Sinks: []config.NotifySink{
+		{Name: "broken", Type: config.NotifySinkCommand, Command: "echo boom >&2; exit 3"},
+	}}})
+	if _, err := n.Notify(context.Background(), Event{Kind: config.NotifyLoopDone}); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("failing command error = %v, want output included", err)
+	}
+}
+
+func TestEmailSinkBuildsMessage(t *testing.T) {
+	var (
+		gotAddr string
+		gotTo   []string
+		gotMsg  string
+	)
+	orig := sendMail
+	sendMail = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, string(msg)
+		return nil
+	}
+	t.Cleanup(func() { sendMail = orig })
+
+	n, err := New(&config.GlobalConfig{Notify: config.NotifyConfig{Sinks: []config.NotifySink{
+		{Name: "mail", Type: config.NotifySinkEmail, SMTPHost: "smtp.example.com", From: "adaf@example.com", To: []string{"me@example.com"}},
+	}}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	run := &store.LoopRun{ID: 4, LoopName: "nightly", Status: "stopped"}
+	if _, err := n.Notify(context.Background(), LoopDone("demo", run, nil)); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if gotAddr != "smtp.example.com:587" || len(gotTo) != 1 || gotTo[0] != "me@example.com" {
+		t.Fatalf("sendMail addr=%q to=%v", gotAddr, gotTo)
+	}
+	if !strings.Contains(gotMsg, "Subject: [adaf:demo] Loop nightly stopped\r\n") {
+		t.Fatalf("message missing subject:\n%s", gotMsg)
+	}
+	if !strings.Contains(gotMsg, "run_id: 4") {
+		t.Fatalf("message missing fields:\n%s", gotMsg)
+	}
+
+	// Line breaks in the title cannot inject headers.
+	if _, err := n.Notify(context.Background(), Event{Kind: config.NotifyLoopDone, Title: "done\r\nBcc: x@example.com\rX-Evil: 1"}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if !strings.Contains(gotMsg, "Subject: [adaf] done  Bcc: x@example.com X-Evil: 1\r\n") {
+		t.Fatalf("subject not flattened:\n%s", gotMsg)
+	}
+}
+
+func TestNewImplicitPushoverSinkAndInvalidSinks(t *testing.T) {
+	cfg := &config.GlobalConfig{
+		Pushover: config.PushoverConfig{UserKey: "u", AppToken: "a"},
+		Notify: config.NotifyConfig{Sinks: []config.NotifySink{
+			{Name: "bad", Type: config.NotifySinkWebhook, URL: "not a url"},
+		}},
+	}
+	n, err := New(cfg)
+	if err == nil || !strings.Contains(err.Error(), `"bad"`) {
+		t.Fatalf("New error = %v, want invalid sink reported", err)
+	}
+	sinks := n.Sinks()
+	if len(sinks) != 1 || sinks[0].Type != config.NotifySinkPushover {
+		t.Fatalf("sinks = %+v, want only the implicit pushover sink", sinks)
+	}
+	if !sinks[0].Subscribes(config.NotifyLoopMessage) || sinks[0].Subscribes(config.NotifyLoopDone) {
+		t.Fatalf("implicit pushover sink should only receive loop_notify: %+v", sinks[0])
+	}
+
+	cfg.Notify.Sinks = []config.NotifySink{{Name: "pushover", Type: config.NotifySinkPushover}}
+	n, err = New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if sinks := n.Sinks(); len(sinks) != 1 || len(sinks[0].Events) != 0 {
+		t.Fatalf("explicit pushover sink should replace the implicit one: %+v", sinks)
+	}
+}
+
+func TestPublishDeliversInBackground(t *testing.T) {
+	srv, reqs := standIn(t, http.StatusOK)
+	cfg := &config.GlobalConfig{Notify: config.NotifyConfig{Sinks: []config.NotifySink{
+		{Name: "hook", Type: config.NotifySinkWebhook, URL: srv.URL},
+	}}}
+	Publish(cfg, LoopBudgetExceeded("demo", &store.LoopRun{ID: 1, LoopName: "l"}, context.DeadlineExceeded))
+	got := receive(t, reqs)
+	if got.header.Get(EventHeader) != config.NotifyBudgetExceeded {
+		t.Fatalf("event header = %q", got.header.Get(EventHeader))
+	}
+	Publish(nil, Event{Kind: config.NotifyLoopDone})
+}
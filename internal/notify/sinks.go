@@ -0,0 +1,211 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/pushover"
+)
+
+const (
+	// SignatureHeader carries the hex HMAC-SHA256 of a webhook body, keyed
+	// with the sink's secret and prefixed with "sha256=".
+	SignatureHeader = "X-Adaf-Signature"
+	// EventHeader carries the event kind on webhook requests.
+	EventHeader = "X-Adaf-Event"
+
+	defaultSMTPPort = 587
+	// discordContentLimit is Discord's maximum message length in characters.
+	discordContentLimit = 2000
+	// commandOutputLimit is how much command output is kept in errors.
+	commandOutputLimit = 512
+)
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// sendMail is swapped out in tests.
+var sendMail = smtp.SendMail
+
+// Sign returns the signature header value for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func postJSON(ctx context.Context, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "adaf-notify")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// webhookSink POSTs the event as JSON, signing the body when a secret is set.
+type webhookSink struct {
+	url     string
+	secret  string
+	headers map[string]string
+}
+
+func (s *webhookSink) Send(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{EventHeader: ev.Kind}
+	for k, v := range s.headers {
+		headers[k] = v
+	}
+	if s.secret != "" {
+		headers[SignatureHeader] = Sign(s.secret, body)
+	}
+	return postJSON(ctx, s.url, body, headers)
+}
+
+// chatSink posts to a Slack-compatible or Discord incoming webhook.
+type chatSink struct {
+	url     string
+	discord bool
+}
+
+func (s *chatSink) Send(ctx context.Context, ev Event) error {
+	var payload map[string]string
+	if s.discord {
+		content := "**" + ev.Title + "**\n" + ev.Text()
+		if runes := []rune(content); len(runes) > discordContentLimit {
+			content = string(runes[:discordContentLimit])
+		}
+		payload = map[string]string{"content": content}
+	} else {
+		payload = map[string]string{"text": "*" + ev.Title + "*\n" + ev.Text()}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, s.url, body, nil)
+}
+
+// emailSink sends a plain-text email over SMTP. Authentication is used when
+// a username is set; net/smtp upgrades to STARTTLS when offered.
+type emailSink struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+func (s *emailSink) Send(ctx context.Context, ev Event) error {
+	port := s.port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+	subject := "[adaf] " + ev.Title
+	if ev.Project != "" {
+		subject = "[adaf:" + ev.Project + "] " + ev.Title
+	}
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", strings.NewReplacer("\r", " ", "\n", " ").Replace(subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", ev.Time.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(ev.Text(), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+
+	// net/smtp has no context support; run it aside so cancellation returns.
+	addr := net.JoinHostPort(s.host, strconv.Itoa(port))
+	done := make(chan error, 1)
+	go func() { done <- sendMail(addr, auth, s.from, s.to, []byte(msg.String())) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// commandSink runs a shell command with the event JSON on stdin and its main
+// fields in ADAF_NOTIFY_* environment variables.
+type commandSink struct {
+	command string
+}
+
+func (s *commandSink) Send(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.command)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(),
+		"ADAF_NOTIFY_EVENT="+ev.Kind,
+		"ADAF_NOTIFY_TITLE="+ev.Title,
+		"ADAF_NOTIFY_MESSAGE="+ev.Message,
+		"ADAF_NOTIFY_PROJECT="+ev.Project,
+	)
+	cmd.WaitDelay = 5 * time.Second
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		detail := strings.TrimSpace(string(out))
+		if len(detail) > commandOutputLimit {
+			detail = detail[len(detail)-commandOutputLimit:]
+		}
+		if detail != "" {
+			return fmt.Errorf("%w: %s", err, detail)
+		}
+		return err
+	}
+	return nil
+}
+
+// pushoverSink sends through the Pushover API. Only the message is sent to
+// keep within Pushover's length limits.
+type pushoverSink struct {
+	cfg *config.PushoverConfig
+}
+
+func (s *pushoverSink) Send(_ context.Context, ev Event) error {
+	return pushover.Send(s.cfg, pushover.Message{
+		Title:    ev.Title,
+		Body:     ev.Message,
+		Priority: ev.Priority,
+	})
+}
@@ -0,0 +1,193 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// ApprovalPendingError reports a merge parked until a human approves it.
+// Created is true when this call filed the approval.
+type ApprovalPendingError struct {
+	Approval *store.Approval
+	Created  bool
+}
+
+func (e *ApprovalPendingError) Error() string {
+	return fmt.Sprintf("merge of spawn %d is waiting for approval #%d (%s)", e.Approval.SpawnID, e.Approval.ID, e.Approval.Reason)
+}
+
+// ApprovalDeniedError reports a merge a human refused.
+type ApprovalDeniedError struct {
+	Approval *store.Approval
+}
+
+func (e *ApprovalDeniedError) Error() string {
+	msg := fmt.Sprintf("merge of spawn %d was denied (approval #%d)", e.Approval.SpawnID, e.Approval.ID)
+	if e.Approval.Comment != "" {
+		msg += ": " + e.Approval.Comment
+	}
+	return msg
+}
+
+// checkMergeApproval returns nil when rec may be merged under the gate
+// recorded on the spawn and opts.Approval. Otherwise it files (or finds) the
+// spawn's approval and reports it as pending or denied. An approval only
+// covers the branch head it was filed for.
+func (o *Orchestrator) checkMergeApproval(ctx context.Context, rec *store.SpawnRecord, opts MergeOptions) error {
+	policy := config.MergeApprovalPolicies(opts.Approval, gatePolicy(rec.MergeGate))
+	if !policy.GatesMerges() {
+		return nil
+	}
+	lines := 0
+	if !policy.Merges {
+		n, err := o.worktrees.BranchDiffLineCount(ctx, rec.Branch)
+		if err != nil {
+			return fmt.Errorf("measuring spawn %d diff for approval: %w", rec.ID, err)
+		}
+		lines = n
+	}
+	reason := policy.MergeReason(lines)
+	if reason == "" {
+		return nil
+	}
+	head, err := o.worktrees.BranchHead(ctx, rec.Branch)
+	if err != nil {
+		return fmt.Errorf("resolving spawn %d branch head for approval: %w", rec.ID, err)
+	}
+
+	existing, err := o.store.MergeApproval(rec.ID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.Head == head {
+		switch existing.Status {
+		case store.ApprovalApproved:
+			return nil
+		case store.ApprovalDenied:
+			return &ApprovalDeniedError{Approval: existing}
+		case store.ApprovalPending:
+			return &ApprovalPendingError{Approval: existing}
+		}
+	}
+	if existing != nil && existing.Status == store.ApprovalPending {
+		// The branch moved while waiting; the old request no longer
+		// describes what would be merged.
+		existing.Status = store.ApprovalCanceled
+		existing.DecidedAt = time.Now().UTC()
+		if err := o.store.UpdateApproval(existing); err != nil {
+			return fmt.Errorf("canceling stale merge approval: %w", err)
+		}
+	}
+
+	a := &store.Approval{
+		Kind:      store.ApprovalKindMerge,
+		Summary:   fmt.Sprintf("merge spawn #%d (%s) branch %s", rec.ID, rec.ChildProfile, rec.Branch),
+		Reason:    reason,
+		SpawnID:   rec.ID,
+		Branch:    rec.Branch,
+		Head:      head,
+		DiffLines: lines,
+		TurnID:    opts.RequestedBy,
+	}
+	if err := o.store.CreateApproval(a); err != nil {
+		return fmt.Errorf("filing merge approval: %w", err)
+	}
+	debug.LogKV("orch", "merge parked for approval", "spawn_id", rec.ID, "approval_id", a.ID, "reason", reason)
+	return &ApprovalPendingError{Approval: a, Created: true}
+}
+
+// parentMergeApproval returns the approval policy for merging the spawn req
+// creates. It is resolved from records the orchestrator keeps rather than
+// from the parent agent's environment: the child merge gate of the spawn
+// the parent turn belongs to, or the delegation of the loop step that ran
+// it. A stricter policy passed on the request is kept.
+func (o *Orchestrator) parentMergeApproval(req SpawnRequest) *config.ApprovalPolicy {
+	var fromRequest *config.ApprovalPolicy
+	if req.Delegation != nil {
+		fromRequest = req.Delegation.Approval
+	}
+	return config.MergeApprovalPolicies(fromRequest, o.turnMergeApproval(req.ParentTurnID))
+}
+
+// turnMergeApproval resolves the merge approval policy of the agent running
+// turnID.
+func (o *Orchestrator) turnMergeApproval(turnID int) *config.ApprovalPolicy {
+	if turnID <= 0 {
+		return nil
+	}
+	if spawns, err := o.store.ListSpawns(); err == nil {
+		for _, sp := range spawns {
+			if sp.ChildTurnID == turnID {
+				return gatePolicy(sp.ChildMergeGate)
+			}
+		}
+	}
+	if o.globalCfg == nil {
+		return nil
+	}
+	runs, err := o.store.ListLoopRuns()
+	if err != nil {
+		return nil
+	}
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		if !slices.Contains(run.TurnIDs, turnID) {
+			continue
+		}
+		loopDef := o.globalCfg.FindLoop(run.LoopName)
+		if loopDef == nil {
+			return nil
+		}
+		stepIdx := loopRunTurnStep(o.store, &run, turnID)
+		if stepIdx < 0 || stepIdx >= len(loopDef.Steps) {
+			return loopDef.Approval.Clone()
+		}
+		if deleg := o.globalCfg.StepDelegation(loopDef, loopDef.Steps[stepIdx]); deleg != nil {
+			return deleg.Approval.Clone()
+		}
+		return loopDef.Approval.Clone()
+	}
+	return nil
+}
+
+// loopRunTurnStep returns the step index turnID ran as in run, falling back
+// to the run's current step.
+func loopRunTurnStep(s *store.Store, run *store.LoopRun, turnID int) int {
+	if turn, err := s.GetTurn(turnID); err == nil && turn != nil && turn.StepHexID != "" {
+		for key, hex := range run.StepHexIDs {
+			if hex != turn.StepHexID {
+				continue
+			}
+			if _, step, ok := strings.Cut(key, ":"); ok {
+				if idx, err := strconv.Atoi(step); err == nil {
+					return idx
+				}
+			}
+		}
+	}
+	return run.StepIndex
+}
+
+// mergeGate records the merge part of p on a spawn.
+func mergeGate(p *config.ApprovalPolicy) *store.MergeGate {
+	if !p.GatesMerges() {
+		return nil
+	}
+	return &store.MergeGate{Merges: p.Merges, MergeLineLimit: p.MergeLineLimit}
+}
+
+// gatePolicy turns a recorded merge gate back into a policy.
+func gatePolicy(g *store.MergeGate) *config.ApprovalPolicy {
+	if g == nil {
+		return nil
+	}
+	return &config.ApprovalPolicy{Merges: g.Merges, MergeLineLimit: g.MergeLineLimit}
+}
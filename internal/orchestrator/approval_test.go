@@ -0,0 +1,225 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func TestMerge_ApprovalGate(t *testing.T) {
+	ctx, _, s, o, rec := createSpawnWithCommittedWorktree(t, "completed")
+	opts := MergeOptions{Approval: &config.ApprovalPolicy{Merges: true}, RequestedBy: 7}
+
+	_, err := o.MergeWithOptions(ctx, rec.ID, opts)
+	var pending *ApprovalPendingError
+	if !errors.As(err, &pending) || !pending.Created {
+		t.Fatalf("first merge err = %v, want new ApprovalPendingError", err)
+	}
+	if pending.Approval.TurnID != 7 || pending.Approval.Branch != rec.Branch {
+		t.Fatalf("approval = %+v", pending.Approval)
+	}
+
+	_, err = o.MergeWithOptions(ctx, rec.ID, opts)
+	if !errors.As(err, &pending) || pending.Created {
+		t.Fatalf("second merge err = %v, want existing ApprovalPendingError", err)
+	}
+
+	if _, err := s.DecideApproval(pending.Approval.ID, true, "alice", "ship it"); err != nil {
+		t.Fatalf("DecideApproval: %v", err)
+	}
+	if _, err := o.MergeWithOptions(ctx, rec.ID, opts); err != nil {
+		t.Fatalf("merge after approval: %v", err)
+	}
+	got, _ := s.GetSpawn(rec.ID)
+	if got.Status != "merged" {
+		t.Fatalf("status = %q, want merged", got.Status)
+	}
+}
+
+func TestMerge_ApprovalDenied(t *testing.T) {
+	ctx, _, s, o, rec := createSpawnWithCommittedWorktree(t, "completed")
+	opts := MergeOptions{Approval: &config.ApprovalPolicy{Merges: true}}
+
+	_, err := o.MergeWithOptions(ctx, rec.ID, opts)
+	var pending *ApprovalPendingError
+	if !errors.As(err, &pending) {
+		t.Fatalf("merge err = %v, want ApprovalPendingError", err)
+	}
+	if _, err := s.DecideApproval(pending.Approval.ID, false, "alice", "too risky"); err != nil {
+		t.Fatalf("DecideApproval: %v", err)
+	}
+	_, err = o.MergeWithOptions(ctx, rec.ID, opts)
+	var denied *ApprovalDeniedError
+	if !errors.As(err, &denied) || denied.Approval.Comment != "too risky" {
+		t.Fatalf("merge err = %v, want ApprovalDeniedError with comment", err)
+	}
+	got, _ := s.GetSpawn(rec.ID)
+	if got.Status != "completed" {
+		t.Fatalf("status = %q, want completed", got.Status)
+	}
+}
+
+func TestMerge_ApprovalLineLimit(t *testing.T) {
+	ctx, _, s, o, rec := createSpawnWithCommittedWorktree(t, "completed")
+
+	// The spawn adds a single line, which stays under the limit.
+	opts := MergeOptions{Approval: &config.ApprovalPolicy{MergeLineLimit: 300}}
+	if _, err := o.MergeWithOptions(ctx, rec.ID, opts); err != nil {
+		t.Fatalf("merge under limit: %v", err)
+	}
+	list, err := s.ListApprovals()
+	if err != nil {
+		t.Fatalf("ListApprovals: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("approvals = %d, want 0", len(list))
+	}
+	if got, _ := s.GetSpawn(rec.ID); got.Status != store.SpawnStatusMerged {
+		t.Fatalf("status = %q, want merged", got.Status)
+	}
+}
+
+func TestMerge_ApprovalGateRecordedOnSpawn(t *testing.T) {
+	ctx, _, s, o, rec := createSpawnWithCommittedWorktree(t, "completed")
+	rec.MergeGate = &store.MergeGate{Merges: true}
+	if err := s.UpdateSpawn(rec); err != nil {
+		t.Fatalf("UpdateSpawn: %v", err)
+	}
+
+	// No policy from the caller: the recorded gate still applies.
+	_, err := o.MergeWithOptions(ctx, rec.ID, MergeOptions{})
+	var pending *ApprovalPendingError
+	if !errors.As(err, &pending) || !pending.Created {
+		t.Fatalf("merge err = %v, want new ApprovalPendingError", err)
+	}
+}
+
+func TestMerge_ApprovalCoversOnlyTheApprovedHead(t *testing.T) {
+	ctx, _, s, o, rec := createSpawnWithCommittedWorktree(t, "completed")
+	opts := MergeOptions{Approval: &config.ApprovalPolicy{Merges: true}}
+
+	_, err := o.MergeWithOptions(ctx, rec.ID, opts)
+	var pending *ApprovalPendingError
+	if !errors.As(err, &pending) {
+		t.Fatalf("merge err = %v, want ApprovalPendingError", err)
+	}
+	first := pending.Approval
+	if first.Head == "" {
+		t.Fatal("approval has no head recorded")
+	}
+	if _, err := s.DecideApproval(first.ID, true, "alice", ""); err != nil {
+		t.Fatalf("DecideApproval: %v", err)
+	}
+
+	// The branch moves after approval.
+	if err := os.WriteFile(filepath.Join(rec.WorktreePath, "late.txt"), []byte("late\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	runGitWithConfig(t, rec.WorktreePath, []string{"user.name=Test", "user.email=test@example.com"}, "add", "late.txt")
+	runGitWithConfig(t, rec.WorktreePath, []string{"user.name=Test", "user.email=test@example.com"}, "commit", "-m", "late change")
+
+	_, err = o.MergeWithOptions(ctx, rec.ID, opts)
+	if !errors.As(err, &pending) || !pending.Created {
+		t.Fatalf("merge after branch moved err = %v, want new ApprovalPendingError", err)
+	}
+	if pending.Approval.ID == first.ID || pending.Approval.Head == first.Head {
+		t.Fatalf("re-approval = %+v, want a new approval for the new head", pending.Approval)
+	}
+	if got, _ := s.GetSpawn(rec.ID); got.Status != store.SpawnStatusCompleted {
+		t.Fatalf("status = %q, want completed", got.Status)
+	}
+}
+
+func TestParentMergeApprovalComesFromRecords(t *testing.T) {
+	repo := initGitRepo(t)
+	s := newTestStore(t, repo)
+	cfg := &config.GlobalConfig{
+		Loops: []config.LoopDef{{
+			Name:     "ship",
+			Approval: &config.ApprovalPolicy{MergeLineLimit: 300},
+			Steps:    []config.LoopStep{{Profile: "lead"}},
+		}},
+	}
+	o := New(s, cfg, repo)
+
+	parent := &store.SpawnRecord{ChildProfile: "lead", Status: "running", ChildTurnID: 42, ChildMergeGate: &store.MergeGate{Merges: true}}
+	if err := s.CreateSpawn(parent); err != nil {
+		t.Fatalf("CreateSpawn: %v", err)
+	}
+	run := &store.LoopRun{LoopName: "ship", Status: "running", TurnIDs: []int{50}}
+	if err := s.CreateLoopRun(run); err != nil {
+		t.Fatalf("CreateLoopRun: %v", err)
+	}
+
+	// The parent's environment claims no approval policy.
+	open := &config.DelegationConfig{}
+	if got := o.parentMergeApproval(SpawnRequest{ParentTurnID: 42, Delegation: open}); got == nil || !got.Merges {
+		t.Fatalf("spawned parent policy = %+v, want merges gated", got)
+	}
+	if got := o.parentMergeApproval(SpawnRequest{ParentTurnID: 50, Delegation: open}); got == nil || got.MergeLineLimit != 300 {
+		t.Fatalf("loop step parent policy = %+v, want the loop's line limit", got)
+	}
+	if got := o.parentMergeApproval(SpawnRequest{ParentTurnID: 99, Delegation: open}); got != nil {
+		t.Fatalf("unknown parent policy = %+v, want nil", got)
+	}
+}
+
+func TestSpawn_ChildApprovalKeepsTheStricterPolicy(t *testing.T) {
+	repo := initGitRepo(t)
+	s := newTestStore(t, repo)
+	cmdPath := filepath.Join(t.TempDir(), "generic-approval.sh")
+	if err := os.WriteFile(cmdPath, []byte("#!/usr/bin/env bash\necho done\n"), 0755); err != nil {
+		t.Fatalf("WriteFile(%q): %v", cmdPath, err)
+	}
+	if err := agent.SaveAgentsConfig(&agent.AgentsConfig{
+		Agents: map[string]agent.AgentRecord{
+			"generic": {Name: "generic", Path: cmdPath},
+		},
+	}); err != nil {
+		t.Fatalf("SaveAgentsConfig(): %v", err)
+	}
+	cfg := &config.GlobalConfig{
+		Profiles: []config.Profile{
+			{Name: "parent", Agent: "generic"},
+			{Name: "worker", Agent: "generic"},
+		},
+	}
+	o := New(s, cfg, repo)
+
+	spawnID, err := o.Spawn(context.Background(), SpawnRequest{
+		ParentTurnID:  93,
+		ParentProfile: "parent",
+		ChildProfile:  "worker",
+		Task:          "work",
+		Delegation: &config.DelegationConfig{
+			Profiles: []config.DelegationProfile{{
+				Name: "worker",
+				Delegation: &config.DelegationConfig{
+					Approval: &config.ApprovalPolicy{MergeLineLimit: 100},
+				},
+			}},
+			Approval: &config.ApprovalPolicy{Merges: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+	o.WaitOne(spawnID)
+
+	rec, err := s.GetSpawn(spawnID)
+	if err != nil {
+		t.Fatalf("GetSpawn(%d): %v", spawnID, err)
+	}
+	if rec.MergeGate == nil || !rec.MergeGate.Merges {
+		t.Fatalf("merge gate = %+v, want merges gated", rec.MergeGate)
+	}
+	if g := rec.ChildMergeGate; g == nil || !g.Merges || g.MergeLineLimit != 100 {
+		t.Fatalf("child merge gate = %+v, want merges gated and the 100-line limit", g)
+	}
+}
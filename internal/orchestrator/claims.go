@@ -0,0 +1,66 @@
+package orchestrator
+
+import (
+	"time"
+
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// spawnClaimTTL bounds a spawn's issue leases in case the spawn is never
+// finished (e.g. the daemon dies); normally they are released as soon as the
+// spawn reaches a terminal status.
+const spawnClaimTTL = 12 * time.Hour
+
+// parentClaimHolder returns the lease identity of a parent turn: its spawn
+// when the parent is itself a spawned agent, else the turn and its loop step.
+func (o *Orchestrator) parentClaimHolder(parentTurnID int) store.IssueClaim {
+	return o.store.TurnClaimHolder(parentTurnID)
+}
+
+// checkIssueClaims rejects a spawn whose assigned issues are leased to
+// anyone but its parent, so two agents never get the same issue.
+func (o *Orchestrator) checkIssueClaims(req SpawnRequest) error {
+	if len(req.IssueIDs) == 0 {
+		return nil
+	}
+	parent := o.parentClaimHolder(req.ParentTurnID)
+	now := time.Now().UTC()
+	for _, id := range req.IssueIDs {
+		issue, err := o.store.GetIssue(id)
+		if err != nil {
+			continue
+		}
+		if claim := issue.ActiveClaim(now); claim != nil && !claim.SameHolder(parent) {
+			return &store.IssueClaimedError{IssueID: id, Claim: *claim}
+		}
+	}
+	return nil
+}
+
+// claimSpawnIssues leases the spawn's assigned issues to it, taking over
+// leases its parent held. Conflicts were ruled out by checkIssueClaims; one
+// that slipped in since is logged and the issue is left to its holder.
+func (o *Orchestrator) claimSpawnIssues(spawnID int, req SpawnRequest) {
+	if len(req.IssueIDs) == 0 {
+		return
+	}
+	parent := o.parentClaimHolder(req.ParentTurnID)
+	now := time.Now().UTC()
+	for _, id := range req.IssueIDs {
+		issue, err := o.store.GetIssue(id)
+		if err != nil || store.IsTerminalIssueStatus(issue.Status) {
+			continue
+		}
+		if claim := issue.ActiveClaim(now); claim != nil && claim.SameHolder(parent) {
+			if _, err := o.store.ReleaseIssueClaim(id, &parent, "handed to spawn"); err != nil {
+				debug.LogKV("orch", "failed to take over parent issue claim", "spawn_id", spawnID, "issue", id, "error", err)
+				continue
+			}
+		}
+		claim := store.IssueClaim{SpawnID: spawnID, By: req.ChildProfile}
+		if _, err := o.store.ClaimIssue(id, claim, spawnClaimTTL); err != nil {
+			debug.LogKV("orch", "failed to claim spawn issue", "spawn_id", spawnID, "issue", id, "error", err)
+		}
+	}
+}
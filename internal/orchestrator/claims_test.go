@@ -0,0 +1,60 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func TestSpawn_RejectsIssueClaimedByAnotherAgent(t *testing.T) {
+	o, s, _ := newGatedQueueOrchestrator(t)
+	if err := s.CreateIssue(&store.Issue{Title: "contended"}); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	if _, err := s.ClaimIssue(1, store.IssueClaim{TurnID: 999}, time.Hour); err != nil {
+		t.Fatalf("ClaimIssue: %v", err)
+	}
+
+	req := queueTestRequest("duplicate work")
+	req.IssueIDs = []int{1}
+	_, err := o.Spawn(context.Background(), req)
+	var claimed *store.IssueClaimedError
+	if !errors.As(err, &claimed) || claimed.Claim.TurnID != 999 {
+		t.Fatalf("Spawn() error = %v, want IssueClaimedError", err)
+	}
+}
+
+func TestSpawn_TakesOverParentClaimAndReleasesOnCompletion(t *testing.T) {
+	o, s, gate := newGatedQueueOrchestrator(t)
+	if err := s.CreateIssue(&store.Issue{Title: "delegated"}); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	if _, err := s.ClaimIssue(1, store.IssueClaim{TurnID: 151}, time.Hour); err != nil {
+		t.Fatalf("ClaimIssue: %v", err)
+	}
+
+	req := queueTestRequest("delegated work")
+	req.IssueIDs = []int{1}
+	spawnID, err := o.Spawn(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+	issue, _ := s.GetIssue(1)
+	if issue.Claim == nil || issue.Claim.SpawnID != spawnID {
+		t.Fatalf("claim after spawn = %+v, want spawn #%d", issue.Claim, spawnID)
+	}
+
+	if err := os.WriteFile(gate, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := o.WaitOne(spawnID); got.Status != "completed" {
+		t.Fatalf("spawn status = %q, result = %q", got.Status, got.Result)
+	}
+	if issue, _ := s.GetIssue(1); issue.Claim != nil {
+		t.Fatalf("claim after completion = %+v, want released", issue.Claim)
+	}
+}
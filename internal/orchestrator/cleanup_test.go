@@ -84,6 +84,43 @@ func TestCleanupStaleWorktrees_RemovesMergedSpawnWorktree(t *testing.T) {
 	}
 }
 
+func TestCleanupSpawnWorktrees_RemovesFailedVerificationWorktree(t *testing.T) {
+	repo := initGitRepo(t)
+	s := newTestStore(t, repo)
+	mgr := worktree.NewManager(repo)
+	ctx := context.Background()
+
+	branch := "adaf/test/worker/20260212T030000"
+	wtPath, err := mgr.Create(ctx, branch)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	defer mgr.RemoveWithBranch(ctx, wtPath, branch)
+
+	rec := &store.SpawnRecord{
+		ParentTurnID:  7,
+		ParentProfile: "manager",
+		ChildProfile:  "worker",
+		Task:          "test",
+		Status:        store.SpawnStatusFailedVerification,
+		Branch:        branch,
+		WorktreePath:  wtPath,
+	}
+	if err := s.CreateSpawn(rec); err != nil {
+		t.Fatalf("CreateSpawn: %v", err)
+	}
+
+	o := New(s, nil, repo)
+	o.CleanupSpawnWorktrees([]int{7})
+
+	if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+		t.Fatalf("failed_verification spawn worktree should be removed, stat err=%v", err)
+	}
+	if branchExists(repo, branch) {
+		t.Fatalf("failed_verification spawn branch %q should be removed", branch)
+	}
+}
+
 func TestCleanupStaleWorktrees_RemovesOldUntrackedWorktree(t *testing.T) {
 	repo := initGitRepo(t)
 	s := newTestStore(t, repo)
@@ -162,7 +162,7 @@ func TestSpawn_UsesResolvedRoleAndDelegationMetadata(t *testing.T) {
 	}
 }
 
-func TestSpawn_RejectsImmediatelyWhenChildMaxInstancesReached(t *testing.T) {
+func TestSpawn_QueuesWhenChildMaxInstancesReached(t *testing.T) {
 	repo := initGitRepo(t)
 	s := newTestStore(t, repo)
 	cfg := &config.GlobalConfig{
@@ -186,23 +186,10 @@ func TestSpawn_RejectsImmediatelyWhenChildMaxInstancesReached(t *testing.T) {
 			},
 		},
 	})
-	if err == nil {
-		t.Fatalf("Spawn() error = nil, want max instances error")
-	}
-	if !strings.Contains(err.Error(), "sub-agent option") || !strings.Contains(err.Error(), "max 1") {
-		t.Fatalf("Spawn() error = %q, want per-option max instances hint", err)
-	}
-	if spawnID != 0 {
-		t.Fatalf("spawnID = %d, want 0", spawnID)
-	}
-
-	spawns, listErr := s.ListSpawns()
-	if listErr != nil {
-		t.Fatalf("ListSpawns: %v", listErr)
-	}
-	if len(spawns) != 0 {
-		t.Fatalf("expected no spawn records on limit rejection, got %d", len(spawns))
+	if err != nil {
+		t.Fatalf("Spawn() error = %v, want the spawn queued", err)
 	}
+	assertQueuedSpawn(t, o, s, spawnID, 1)
 }
 
 func TestSpawn_DelegationMaxInstancesArePerRoleBucket(t *testing.T) {
@@ -254,7 +241,7 @@ func TestSpawn_DelegationMaxInstancesArePerRoleBucket(t *testing.T) {
 	}
 }
 
-func TestSpawn_RejectsImmediatelyWhenParentMaxParallelReached(t *testing.T) {
+func TestSpawn_QueuesWhenParentMaxParallelReached(t *testing.T) {
 	repo := initGitRepo(t)
 	s := newTestStore(t, repo)
 	cfg := &config.GlobalConfig{
@@ -278,21 +265,8 @@ func TestSpawn_RejectsImmediatelyWhenParentMaxParallelReached(t *testing.T) {
 			},
 		},
 	})
-	if err == nil {
-		t.Fatalf("Spawn() error = nil, want max parallel error")
-	}
-	if !strings.Contains(err.Error(), "parent profile") || !strings.Contains(err.Error(), "max 1") {
-		t.Fatalf("Spawn() error = %q, want parent max parallel hint", err)
-	}
-	if spawnID != 0 {
-		t.Fatalf("spawnID = %d, want 0", spawnID)
-	}
-
-	spawns, listErr := s.ListSpawns()
-	if listErr != nil {
-		t.Fatalf("ListSpawns: %v", listErr)
-	}
-	if len(spawns) != 0 {
-		t.Fatalf("expected no spawn records on limit rejection, got %d", len(spawns))
+	if err != nil {
+		t.Fatalf("Spawn() error = %v, want the spawn queued", err)
 	}
+	assertQueuedSpawn(t, o, s, spawnID, 1)
 }
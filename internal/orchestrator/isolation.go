@@ -0,0 +1,56 @@
+package orchestrator
+
+import (
+	"context"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/isolation"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// isolationAttachTimeout bounds backend setup, which may build an image.
+const isolationAttachTimeout = 20 * time.Minute
+
+// isolationBackend returns the project's spawn isolation backend.
+func (o *Orchestrator) isolationBackend() (isolation.Backend, error) {
+	var cfg *store.IsolationConfig
+	if o.store != nil {
+		if projCfg, err := o.store.LoadProject(); err == nil && projCfg != nil {
+			cfg = projCfg.Isolation
+		}
+	}
+	return isolation.New(cfg, o.repoRoot)
+}
+
+// spawnAgents lists the agents a child of prof may run: its own and those
+// of its fallback profiles.
+func (o *Orchestrator) spawnAgents(prof *config.Profile) []string {
+	agents := []string{prof.Agent}
+	if o.globalCfg != nil {
+		for _, fb := range o.globalCfg.FallbackProfiles(prof.Name) {
+			agents = append(agents, fb.Agent)
+		}
+	}
+	return agents
+}
+
+// releaseIsolation tears down what the isolation backend set up for rec.
+func (o *Orchestrator) releaseIsolation(ctx context.Context, rec *store.SpawnRecord) {
+	if rec.Isolation == "" {
+		return
+	}
+	backend, err := o.isolationBackend()
+	if err == nil && backend.Name() != rec.Isolation {
+		// The project switched backends since the spawn started.
+		backend, err = isolation.New(&store.IsolationConfig{Backend: rec.Isolation}, o.repoRoot)
+	}
+	if err == nil {
+		err = backend.Release(ctx, rec.ID)
+	}
+	if err != nil {
+		debug.LogKV("orch", "spawn isolation cleanup failed",
+			"spawn_id", rec.ID, "backend", rec.Isolation, "error", err)
+	}
+}
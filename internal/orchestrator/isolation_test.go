@@ -0,0 +1,143 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func TestSpawn_ContainerIsolationRunsAgentInContainer(t *testing.T) {
+	repo := initGitRepo(t)
+	s := newTestStore(t, repo)
+
+	// A podman stand-in: exec runs the command on the host in the -w dir
+	// and marks that it went through the container.
+	rtDir := t.TempDir()
+	runtime := filepath.Join(rtDir, "podman")
+	script := `#!/bin/sh
+state=` + rtDir + `
+echo "$*" >> "$state/calls.log"
+case "$1" in
+run) touch "$state/running" ;;
+container) [ -f "$state/running" ] ;;
+rm) rm -f "$state/running" ;;
+exec)
+	shift 2
+	dir=$2; shift 2
+	while [ "$1" = -e ]; do export "$2"; shift 2; done
+	shift
+	cd "$dir" && IN_CONTAINER=1 exec "$@"
+	;;
+esac
+`
+	if err := os.WriteFile(runtime, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	projCfg, err := s.LoadProject()
+	if err != nil {
+		t.Fatalf("LoadProject: %v", err)
+	}
+	projCfg.Isolation = &store.IsolationConfig{Backend: store.IsolationContainer, Runtime: runtime, Image: "example/dev:latest"}
+	if err := s.SaveProject(projCfg); err != nil {
+		t.Fatalf("SaveProject: %v", err)
+	}
+
+	cmdPath := filepath.Join(t.TempDir(), "agent.sh")
+	agentScript := "#!/bin/sh\ncat > " + filepath.Join(rtDir, "prompt.txt") + "\necho \"container=$IN_CONTAINER\" > proof.txt\necho done\n"
+	if err := os.WriteFile(cmdPath, []byte(agentScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := agent.SaveAgentsConfig(&agent.AgentsConfig{
+		Agents: map[string]agent.AgentRecord{"generic": {Name: "generic", Path: cmdPath}},
+	}); err != nil {
+		t.Fatalf("SaveAgentsConfig(): %v", err)
+	}
+
+	cfg := &config.GlobalConfig{Profiles: []config.Profile{
+		{Name: "parent", Agent: "generic"},
+		{Name: "worker", Agent: "generic"},
+	}}
+	o := New(s, cfg, repo)
+	spawnID, err := o.Spawn(context.Background(), SpawnRequest{
+		ParentTurnID:  141,
+		ParentProfile: "parent",
+		ChildProfile:  "worker",
+		Task:          "contained work",
+		Delegation: &config.DelegationConfig{
+			Profiles: []config.DelegationProfile{{Name: "worker"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+	if got := o.WaitOne(spawnID); got.Status != "completed" {
+		t.Fatalf("status = %q, result = %q", got.Status, got.Result)
+	}
+
+	rec, err := s.GetSpawn(spawnID)
+	if err != nil {
+		t.Fatalf("GetSpawn: %v", err)
+	}
+	if rec.Isolation != store.IsolationContainer {
+		t.Fatalf("isolation = %q", rec.Isolation)
+	}
+	if proof := gitOutput(t, repo, "show", rec.Branch+":proof.txt"); strings.TrimSpace(proof) != "container=1" {
+		t.Fatalf("proof.txt = %q, want the agent to run through the runtime", proof)
+	}
+	// Children in a container cannot reach adaf, so the prompt does not
+	// tell them to.
+	if prompt, _ := os.ReadFile(filepath.Join(rtDir, "prompt.txt")); strings.Contains(string(prompt), "adaf parent-ask") ||
+		!strings.Contains(string(prompt), "adaf CLI is not available") {
+		t.Fatalf("container child prompt should omit adaf commands:\n%s", prompt)
+	}
+	calls, _ := os.ReadFile(filepath.Join(rtDir, "calls.log"))
+	if !strings.Contains(string(calls), "-v "+rec.WorktreePath+":"+rec.WorktreePath+" ") {
+		t.Fatalf("run call does not mount the worktree:\n%s", calls)
+	}
+
+	// The container lives until the worktree is cleaned up.
+	if _, err := os.Stat(filepath.Join(rtDir, "running")); err != nil {
+		t.Fatalf("container removed before worktree cleanup: %v", err)
+	}
+	o.CleanupSpawnWorktrees([]int{141})
+	if _, err := os.Stat(filepath.Join(rtDir, "running")); !os.IsNotExist(err) {
+		t.Fatalf("container still running after cleanup: %v", err)
+	}
+}
+
+func TestSpawn_ContainerIsolationRejectsRemoteHost(t *testing.T) {
+	repo := initGitRepo(t)
+	s := newTestStore(t, repo)
+	projCfg, _ := s.LoadProject()
+	projCfg.Isolation = &store.IsolationConfig{Backend: store.IsolationContainer, Runtime: "podman", Image: "x"}
+	if err := s.SaveProject(projCfg); err != nil {
+		t.Fatalf("SaveProject: %v", err)
+	}
+	cfg := &config.GlobalConfig{Profiles: []config.Profile{
+		{Name: "parent", Agent: "generic"},
+		{Name: "worker", Agent: "generic"},
+	}}
+	o := New(s, cfg, repo)
+	_, err := o.Spawn(context.Background(), SpawnRequest{
+		ParentTurnID:  142,
+		ParentProfile: "parent",
+		ChildProfile:  "worker",
+		Task:          "x",
+		Host:          "any",
+		Delegation: &config.DelegationConfig{
+			Profiles: []config.DelegationProfile{{Name: "worker"}},
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "container isolation") {
+		t.Fatalf("Spawn() error = %v, want isolation conflict", err)
+	}
+	if got := o.running["parent"]; got != 0 {
+		t.Fatalf("running[parent] = %d, want 0", got)
+	}
+}
@@ -2,10 +2,12 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/agusx1211/adaf/internal/config"
 	"github.com/agusx1211/adaf/internal/store"
@@ -194,6 +196,173 @@ func TestMerge_NoBranch(t *testing.T) {
 	}
 }
 
+// commitConflictOnParent commits a different spawn-work.txt on the parent
+// branch so merging the spawn branch produces an add/add conflict.
+func commitConflictOnParent(t *testing.T, repo string) string {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repo, "spawn-work.txt"), []byte("parent changes\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	runGit(t, repo, "add", "spawn-work.txt")
+	runGit(t, repo, "commit", "-m", "parent work")
+	return strings.TrimSpace(gitOutput(t, repo, "rev-parse", "HEAD"))
+}
+
+func TestMerge_ConflictAbortRecordsFiles(t *testing.T) {
+	ctx, repo, s, o, rec := createSpawnWithCommittedWorktree(t, "completed")
+	head := commitConflictOnParent(t, repo)
+
+	_, err := o.Merge(ctx, rec.ID, false)
+	var conflict *worktree.MergeConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Merge error = %v, want *worktree.MergeConflictError", err)
+	}
+
+	if status := strings.TrimSpace(gitOutput(t, repo, "status", "--porcelain", "--untracked-files=no")); status != "" {
+		t.Fatalf("parent checkout should be restored after abort, status=%q", status)
+	}
+	if got := strings.TrimSpace(gitOutput(t, repo, "rev-parse", "HEAD")); got != head {
+		t.Fatalf("HEAD = %s, want %s", got, head)
+	}
+
+	got, err := s.GetSpawn(rec.ID)
+	if err != nil {
+		t.Fatalf("GetSpawn(%d): %v", rec.ID, err)
+	}
+	if got.Status != "completed" {
+		t.Fatalf("status = %q, want completed", got.Status)
+	}
+	if len(got.ConflictFiles) != 1 || got.ConflictFiles[0] != "spawn-work.txt" {
+		t.Fatalf("ConflictFiles = %v, want [spawn-work.txt]", got.ConflictFiles)
+	}
+}
+
+func TestMerge_ConflictKeepThenComplete(t *testing.T) {
+	ctx, repo, s, o, rec := createSpawnWithCommittedWorktree(t, "completed")
+	commitConflictOnParent(t, repo)
+
+	_, err := o.MergeWithOptions(ctx, rec.ID, MergeOptions{OnConflict: MergeConflictKeep})
+	var conflict *worktree.MergeConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("MergeWithOptions error = %v, want *worktree.MergeConflictError", err)
+	}
+	if !strings.Contains(gitOutput(t, repo, "status", "--porcelain"), "spawn-work.txt") {
+		t.Fatalf("merge should be left open with spawn-work.txt conflicted")
+	}
+
+	if _, err := o.CompleteMerge(ctx, rec.ID); err == nil {
+		t.Fatalf("CompleteMerge error = nil, want unresolved conflicts")
+	}
+
+	if err := os.WriteFile(filepath.Join(repo, "spawn-work.txt"), []byte("both changes\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	runGit(t, repo, "add", "spawn-work.txt")
+
+	hash, err := o.CompleteMerge(ctx, rec.ID)
+	if err != nil {
+		t.Fatalf("CompleteMerge: %v", err)
+	}
+	got, err := s.GetSpawn(rec.ID)
+	if err != nil {
+		t.Fatalf("GetSpawn(%d): %v", rec.ID, err)
+	}
+	if got.Status != "merged" || got.MergeCommit != hash {
+		t.Fatalf("status=%q merge_commit=%q, want merged/%q", got.Status, got.MergeCommit, hash)
+	}
+	if len(got.ConflictFiles) != 0 {
+		t.Fatalf("ConflictFiles = %v, want cleared after merge", got.ConflictFiles)
+	}
+}
+
+func TestMerge_ResolverMarksConflictedSpawnMerged(t *testing.T) {
+	ctx, repo, s, o, rec := createSpawnWithCommittedWorktree(t, "completed")
+	commitConflictOnParent(t, repo)
+
+	_, err := o.Merge(ctx, rec.ID, false)
+	var conflict *worktree.MergeConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Merge error = %v, want *worktree.MergeConflictError", err)
+	}
+
+	// The resolver branches off the spawn's branch and merges the parent
+	// HEAD, resolving the conflict in the spawn's favor.
+	branch := worktree.BranchName(2, "worker")
+	wtPath, err := worktree.NewManager(repo).CreateFromRef(ctx, branch, rec.Branch)
+	if err != nil {
+		t.Fatalf("CreateFromRef(%q): %v", branch, err)
+	}
+	head := strings.TrimSpace(gitOutput(t, repo, "rev-parse", "HEAD"))
+	runGitWithConfig(t, wtPath, []string{"user.name=Test", "user.email=test@example.com"}, "merge", "-s", "ours", "-m", "resolve", head)
+
+	resolver := &store.SpawnRecord{
+		ParentTurnID:         1,
+		ParentProfile:        "manager",
+		ChildProfile:         "worker",
+		Task:                 "resolve conflicts",
+		Status:               "completed",
+		Branch:               branch,
+		WorktreePath:         wtPath,
+		WorkspaceFromSpawnID: rec.ID,
+	}
+	if err := s.CreateSpawn(resolver); err != nil {
+		t.Fatalf("CreateSpawn(resolver): %v", err)
+	}
+	if err := o.RecordMergeResolver(rec.ID, resolver.ID); err != nil {
+		t.Fatalf("RecordMergeResolver: %v", err)
+	}
+
+	hash, err := o.Merge(ctx, resolver.ID, false)
+	if err != nil {
+		t.Fatalf("Merge(resolver): %v", err)
+	}
+	got, err := s.GetSpawn(rec.ID)
+	if err != nil {
+		t.Fatalf("GetSpawn(%d): %v", rec.ID, err)
+	}
+	if got.Status != "merged" || got.MergeCommit != hash || len(got.ConflictFiles) != 0 {
+		t.Fatalf("conflicted spawn = status %q, merge_commit %q, conflicts %v; want merged by %q", got.Status, got.MergeCommit, got.ConflictFiles, hash)
+	}
+}
+
+func TestMerge_InvalidConflictMode(t *testing.T) {
+	ctx, _, _, o, rec := createSpawnWithCommittedWorktree(t, "completed")
+
+	_, err := o.MergeWithOptions(ctx, rec.ID, MergeOptions{OnConflict: "ignore"})
+	if err == nil || !strings.Contains(err.Error(), "invalid conflict mode") {
+		t.Fatalf("MergeWithOptions error = %v, want invalid conflict mode", err)
+	}
+}
+
+func TestMergeResolverTask_IncludesConflictContext(t *testing.T) {
+	rec := &store.SpawnRecord{ID: 4, Branch: "adaf/1/worker/x", Task: "add login"}
+	task := MergeResolverTask(rec, &worktree.MergeConflictError{
+		Branch: rec.Branch,
+		Target: "abc123",
+		Files:  []string{"auth.go"},
+		Hunks:  "<<<<<<< ours\n=======\n>>>>>>> theirs",
+	})
+	for _, want := range []string{"spawn #4", "add login", "git merge abc123", "- auth.go", "<<<<<<< ours"} {
+		if !strings.Contains(task, want) {
+			t.Fatalf("resolver task missing %q:\n%s", want, task)
+		}
+	}
+}
+
+func TestMergeResolverTask_TruncatesHunksOnRuneBoundary(t *testing.T) {
+	rec := &store.SpawnRecord{ID: 4, Branch: "adaf/1/worker/x", Task: "add login"}
+	task := MergeResolverTask(rec, &worktree.MergeConflictError{
+		Files: []string{"i18n.txt"},
+		Hunks: "x" + strings.Repeat("é", maxResolverHunkBytes),
+	})
+	if !utf8.ValidString(task) {
+		t.Fatal("resolver task is not valid UTF-8 after truncating hunks")
+	}
+	if !strings.Contains(task, "... (truncated)") {
+		t.Fatalf("resolver task should mark truncated hunks:\n%s", task[len(task)-200:])
+	}
+}
+
 func TestReject_CompletedSpawn(t *testing.T) {
 	ctx, repo, s, o, rec := createSpawnWithCommittedWorktree(t, "completed")
 
@@ -340,7 +509,7 @@ func TestInterruptSpawn_AlreadyCompleted(t *testing.T) {
 	}
 }
 
-func TestSpawn_RejectsWhenProfileMaxInstancesReached(t *testing.T) {
+func TestSpawn_QueuesWhenProfileMaxInstancesReached(t *testing.T) {
 	repo := initGitRepo(t)
 	s := newTestStore(t, repo)
 	cfg := &config.GlobalConfig{
@@ -384,21 +553,16 @@ func TestSpawn_RejectsWhenProfileMaxInstancesReached(t *testing.T) {
 			},
 		},
 	})
-	if err == nil {
-		t.Fatalf("Spawn() error = nil, want max instances error")
-	}
-	if !strings.Contains(err.Error(), "child profile") || !strings.Contains(err.Error(), "max 1") {
-		t.Fatalf("Spawn() error = %q, want child max instances hint", err)
-	}
-	if spawnID != 0 {
-		t.Fatalf("spawnID = %d, want 0", spawnID)
+	if err != nil {
+		t.Fatalf("Spawn() error = %v, want the spawn queued", err)
 	}
+	assertQueuedSpawn(t, o, s, spawnID, 1)
 
 	spawns, listErr := s.ListSpawns()
 	if listErr != nil {
 		t.Fatalf("ListSpawns: %v", listErr)
 	}
-	if len(spawns) != 1 {
-		t.Fatalf("expected only the pre-existing running spawn, got %d", len(spawns))
+	if len(spawns) != 2 {
+		t.Fatalf("expected the running spawn and the queued one, got %d", len(spawns))
 	}
 }
@@ -0,0 +1,26 @@
+package orchestrator
+
+import (
+	"github.com/agusx1211/adaf/internal/notify"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// notifySpawnEnd publishes failure and budget notifications for a finished
+// spawn. Successful spawns stay quiet.
+func (o *Orchestrator) notifySpawnEnd(spawnID int, budgetErr error) {
+	rec, err := o.store.GetSpawn(spawnID)
+	if err != nil || rec == nil {
+		return
+	}
+	project := ""
+	if projCfg, err := o.store.LoadProject(); err == nil && projCfg != nil {
+		project = projCfg.Name
+	}
+	if budgetErr != nil {
+		notify.Publish(o.globalCfg, notify.SpawnBudgetExceeded(project, rec, budgetErr))
+	}
+	switch rec.Status {
+	case store.SpawnStatusFailed, store.SpawnStatusFailedVerification:
+		notify.Publish(o.globalCfg, notify.SpawnFailed(project, rec))
+	}
+}
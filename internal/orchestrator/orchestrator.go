@@ -12,15 +12,21 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/budget"
 	"github.com/agusx1211/adaf/internal/config"
 	"github.com/agusx1211/adaf/internal/debug"
 	"github.com/agusx1211/adaf/internal/events"
+	"github.com/agusx1211/adaf/internal/isolation"
 	"github.com/agusx1211/adaf/internal/loop"
 	promptpkg "github.com/agusx1211/adaf/internal/prompt"
+	"github.com/agusx1211/adaf/internal/remote"
+	"github.com/agusx1211/adaf/internal/sandbox"
 	"github.com/agusx1211/adaf/internal/store"
 	"github.com/agusx1211/adaf/internal/stream"
+	"github.com/agusx1211/adaf/internal/usage"
 	"github.com/agusx1211/adaf/internal/worktree"
 )
 
@@ -42,6 +48,22 @@ type SpawnRequest struct {
 	Wait                 bool                     // if true, Spawn blocks until child completes
 	Delegation           *config.DelegationConfig // parent delegation config (required for strict spawning)
 
+	// Checks are acceptance gates run in the child's workspace after it
+	// exits cleanly; issue checks are merged in during Spawn. A failing child
+	// is resumed with the failure output up to VerifyRetries times before
+	// the spawn is marked failed_verification.
+	Checks        []store.AcceptanceCheck
+	VerifyRetries int
+
+	// Host names a configured remote host to run the child on, or "any" to
+	// let the orchestrator pick one. Empty runs the child locally.
+	Host string
+
+	// Priority orders the spawn queue when a concurrency limit is reached
+	// (critical, high, medium, low). Empty uses the highest priority among
+	// IssueIDs, or medium.
+	Priority string
+
 	// Resolved child execution settings populated during Spawn validation.
 	ChildDelegation   *config.DelegationConfig
 	ChildMaxInstances int
@@ -50,8 +72,12 @@ type SpawnRequest struct {
 	ChildSpeed        string
 	ChildHandoff      bool
 	ChildSkills       []string
+	ChildBudget       *config.Budget
+	ChildSandbox      *config.Sandbox
+	mergeApproval     *config.ApprovalPolicy // gates merging this spawn
 	childLimitKey     string
 	workspaceBaseRef  string
+	queuedSpawnID     int // record created when the request was queued
 }
 
 // SpawnResult is the outcome of a completed spawn.
@@ -68,6 +94,9 @@ type SpawnResult struct {
 	// (not a terminal completion).
 	Review bool
 
+	// QueuePosition is set for review checkpoints of queued spawns.
+	QueuePosition int
+
 	// Health metrics populated for review checkpoints.
 	Elapsed           time.Duration
 	CompactionCount   int
@@ -89,6 +118,8 @@ type activeSpawn struct {
 	recordMu     sync.Mutex
 
 	cancel      context.CancelFunc
+	turnMu      sync.Mutex
+	turnCancel  context.CancelFunc // cancels only the child's current turn
 	done        chan struct{}
 	eventBuffer *eventRingBuffer // circular buffer of recent events
 	interruptCh chan string      // signals the child loop about an interrupt
@@ -362,6 +393,7 @@ type Orchestrator struct {
 	globalCfg *config.GlobalConfig
 	worktrees *worktree.Manager
 	repoRoot  string
+	usage     usage.Checker // provider usage for loop usage gates
 
 	mu                sync.Mutex
 	running           map[string]int // parent profile -> count of running spawns
@@ -370,6 +402,10 @@ type Orchestrator struct {
 	spawns            map[int]*activeSpawn
 	waitAny           map[int]chan struct{} // parent turn -> completion notification channel
 	waiters           map[int]int           // parent turn -> active WaitAny waiter count
+	remoteLoad        map[string]int        // remote host -> count of running spawns
+	queue             []*queuedSpawn        // spawns waiting for a concurrency slot
+	queueSeq          uint64                // arrival counter for queue FIFO order
+	queuePosMu        sync.Mutex            // serializes queue position writes; taken before mu
 	spawnWG           sync.WaitGroup        // tracks running spawn goroutines
 	eventCh           chan any              // optional event channel for real-time sub-agent events
 }
@@ -381,6 +417,7 @@ func New(s *store.Store, globalCfg *config.GlobalConfig, repoRoot string) *Orche
 		globalCfg:         globalCfg,
 		worktrees:         worktree.NewManager(repoRoot),
 		repoRoot:          repoRoot,
+		usage:             usage.DefaultMonitor(),
 		running:           make(map[string]int),
 		instances:         make(map[string]int),
 		instancesByOption: make(map[string]int),
@@ -627,12 +664,31 @@ func (o *Orchestrator) Spawn(ctx context.Context, req SpawnRequest) (int, error)
 	if len(resolved.Skills) > 0 {
 		req.ChildSkills = append([]string(nil), resolved.Skills...)
 	}
+	if err := resolved.Budget.Validate(); err != nil {
+		return 0, fmt.Errorf("invalid budget for profile %q: %w", req.ChildProfile, err)
+	}
+	if !resolved.Budget.IsZero() {
+		req.ChildBudget = resolved.Budget.Clone()
+	}
+	// A delegation option's sandbox replaces the profile's own.
+	childSandbox := childProf.Sandbox
+	if resolved.Sandbox != nil {
+		childSandbox = resolved.Sandbox
+	}
+	if err := childSandbox.Validate(); err != nil {
+		return 0, fmt.Errorf("invalid sandbox for profile %q: %w", req.ChildProfile, err)
+	}
+	req.ChildSandbox = childSandbox.Clone()
 	if resolved.Delegation != nil {
 		req.ChildDelegation = resolved.Delegation.Clone()
 	} else {
 		// Nil child rules means explicit no-spawn for this child.
 		req.ChildDelegation = &config.DelegationConfig{}
 	}
+	// Approval gates flow down so sub-spawn merges stay reviewed; the
+	// stricter of the parent's and the child's own policy wins.
+	req.mergeApproval = o.parentMergeApproval(req)
+	req.ChildDelegation.Approval = config.MergeApprovalPolicies(req.ChildDelegation.Approval, req.mergeApproval)
 
 	workspaceBaseRef, err := o.resolveWorkspaceBaseRef(req)
 	if err != nil {
@@ -640,54 +696,29 @@ func (o *Orchestrator) Spawn(ctx context.Context, req SpawnRequest) (int, error)
 	}
 	req.workspaceBaseRef = workspaceBaseRef
 
-	o.mu.Lock()
-
-	// Check global child profile instance limit.
-	if childProf.MaxInstances > 0 {
-		currentInstances := o.instances[req.ChildProfile]
-		if currentInstances >= childProf.MaxInstances {
-			o.mu.Unlock()
-			return 0, fmt.Errorf(
-				"spawn limit reached: child profile %q has %d running instance(s) (max %d)",
-				req.ChildProfile, currentInstances, childProf.MaxInstances,
-			)
-		}
+	if req.VerifyRetries < 0 {
+		return 0, fmt.Errorf("verify retries must be >= 0, got %d", req.VerifyRetries)
 	}
-
-	// Check per-delegation-option instance limit (profile+position+role).
-	if req.ChildMaxInstances > 0 && req.childLimitKey != "" {
-		if o.instancesByOption == nil {
-			o.instancesByOption = make(map[string]int)
-		}
-		currentOptionInstances := o.instancesByOption[req.childLimitKey]
-		if currentOptionInstances >= req.ChildMaxInstances {
-			o.mu.Unlock()
-			return 0, fmt.Errorf(
-				"spawn limit reached: sub-agent option profile=%q role=%q has %d running instance(s) (max %d)",
-				req.ChildProfile, req.ChildRole, currentOptionInstances, req.ChildMaxInstances,
-			)
-		}
+	checks, err := o.resolveAcceptanceChecks(req)
+	if err != nil {
+		return 0, err
 	}
-
-	// Check parent concurrency limit from delegation config.
-	maxPar := deleg.EffectiveMaxParallel()
-	currentRunning := o.running[req.ParentProfile]
-	if currentRunning >= maxPar {
-		o.mu.Unlock()
-		return 0, fmt.Errorf(
-			"spawn limit reached: parent profile %q has %d running sub-agent(s) (max %d)",
-			req.ParentProfile, currentRunning, maxPar,
-		)
+	req.Checks = checks
+	if req.Priority, err = o.resolveSpawnPriority(req); err != nil {
+		return 0, err
+	}
+	if err := o.checkIssueClaims(req); err != nil {
+		return 0, err
 	}
 
-	o.running[req.ParentProfile]++
-	o.instances[req.ChildProfile]++
-	if req.childLimitKey != "" {
-		if o.instancesByOption == nil {
-			o.instancesByOption = make(map[string]int)
-		}
-		o.instancesByOption[req.childLimitKey]++
+	// When a concurrency limit is saturated the spawn is queued and starts
+	// once a slot frees up.
+	o.mu.Lock()
+	if reason := o.spawnLimitLocked(req, childProf); reason != "" {
+		o.mu.Unlock()
+		return o.enqueueSpawn(ctx, req, childProf, reason)
 	}
+	o.reserveSpawnSlotLocked(req)
 	runningCount := o.running[req.ParentProfile]
 	instanceCount := o.instances[req.ChildProfile]
 	optionInstanceCount := 0
@@ -717,6 +748,40 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 		"child_timeout", req.ChildTimeout,
 		"read_only", req.ReadOnly,
 	)
+	// Spawns follow the usage gate of the loop their parent turn runs in.
+	usageGate := usage.GateForTurn(req.ParentTurnID)
+	childProfileName := req.ChildProfile
+	var failovers []store.ProfileFailover
+	childProf, routed, usageBlock := o.gateSpawnProfile(ctx, usageGate, childProf)
+	if routed {
+		childProfileName = childProf.Name
+		failovers = append(failovers, store.ProfileFailover{
+			From:   req.ChildProfile,
+			To:     childProf.Name,
+			Reason: usageFailoverReason,
+			At:     time.Now().UTC(),
+		})
+	}
+
+	backend, err := o.isolationBackend()
+	if err != nil {
+		o.releaseSpawnSlot(req.ParentProfile, req.ChildProfile, req.childLimitKey)
+		return 0, fmt.Errorf("spawn isolation: %w", err)
+	}
+	if backend.Name() != store.IsolationWorktree && (req.Host != "" || req.ChildSandbox != nil) {
+		o.releaseSpawnSlot(req.ParentProfile, req.ChildProfile, req.childLimitKey)
+		return 0, fmt.Errorf("%s isolation cannot be combined with a sandbox or remote host", backend.Name())
+	}
+	if req.Host != "" && req.ChildSandbox != nil {
+		o.releaseSpawnSlot(req.ParentProfile, req.ChildProfile, req.childLimitKey)
+		return 0, fmt.Errorf("spawn cannot combine a sandbox with remote host %q", req.Host)
+	}
+	host, err := o.acquireRemoteHost(req.Host, childProf.Agent)
+	if err != nil {
+		o.releaseSpawnSlot(req.ParentProfile, req.ChildProfile, req.childLimitKey)
+		return 0, err
+	}
+
 	handoff := req.ChildHandoff
 	speed := req.ChildSpeed
 	if speed == "" {
@@ -737,18 +802,28 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 		Status:               "running",
 		Handoff:              handoff,
 		Speed:                speed,
+		Checks:               req.Checks,
+		VerifyRetries:        req.VerifyRetries,
+		Failovers:            failovers,
+		Priority:             req.Priority,
+		MergeGate:            mergeGate(req.mergeApproval),
+		ChildMergeGate:       mergeGate(req.ChildDelegation.Approval),
 	}
 
 	var wtPath string
 	if !req.ReadOnly {
 		branchName, createdPath, err := o.createWritableWorktree(ctx, req.ParentTurnID, req.ChildProfile, req.workspaceBaseRef)
 		if err != nil {
+			o.releaseRemoteHost(host)
 			o.releaseSpawnSlot(req.ParentProfile, req.ChildProfile, req.childLimitKey)
 			return 0, fmt.Errorf("creating worktree: %w", err)
 		}
 		wtPath = createdPath
 		rec.Branch = branchName
 		rec.WorktreePath = wtPath
+		if base, err := o.worktrees.HeadCommit(ctx, wtPath); err == nil {
+			rec.BaseCommit = base
+		}
 	} else {
 		// Read-only spawns get an isolated worktree (detached HEAD) so
 		// concurrent agents don't contend for lock files in the same directory.
@@ -766,13 +841,29 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 		// If creation fails, fall back to repoRoot.
 	}
 
-	if err := o.store.CreateSpawn(rec); err != nil {
+	var recErr error
+	if req.queuedSpawnID > 0 {
+		// The queued record becomes the running spawn.
+		rec.ID = req.queuedSpawnID
+		if queued, err := o.store.GetSpawn(rec.ID); err == nil {
+			rec.QueuedAt = queued.QueuedAt
+		}
+		rec.StartedAt = time.Now().UTC()
+		recErr = o.store.UpdateSpawn(rec)
+	} else {
+		recErr = o.store.CreateSpawn(rec)
+		if recErr == nil {
+			o.claimSpawnIssues(rec.ID, req)
+		}
+	}
+	if err := recErr; err != nil {
 		if wtPath != "" {
 			if rmErr := o.worktrees.RemoveWithBranch(ctx, wtPath, rec.Branch); rmErr != nil {
 				debug.LogKV("orch", "worktree cleanup failed after spawn record error",
 					"worktree", wtPath, "branch", rec.Branch, "error", rmErr)
 			}
 		}
+		o.releaseRemoteHost(host)
 		o.releaseSpawnSlot(req.ParentProfile, req.ChildProfile, req.childLimitKey)
 		debug.LogKV("orch", "spawn record creation failed", "error", err)
 		return 0, fmt.Errorf("creating spawn record: %w", err)
@@ -797,11 +888,90 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 					"worktree", wtPath, "branch", rec.Branch, "error", rmErr)
 			}
 		}
+		o.releaseRemoteHost(host)
 		o.releaseSpawnSlot(req.ParentProfile, req.ChildProfile, req.childLimitKey)
 		return rec.ID, fmt.Errorf("agent %q not found", childProf.Agent)
 	}
 
-	// Build child prompt.
+	workDir := o.repoRoot
+	if wtPath != "" {
+		workDir = wtPath
+	}
+
+	// failSetup marks the spawn failed when its workspace cannot be set up.
+	failSetup := func(result string) {
+		rec.Status = "failed"
+		rec.Result = result
+		o.store.UpdateSpawn(rec)
+		if wtPath != "" {
+			if rmErr := o.worktrees.RemoveWithBranch(ctx, wtPath, rec.Branch); rmErr != nil {
+				debug.LogKV("orch", "worktree cleanup failed after workspace setup error",
+					"worktree", wtPath, "branch", rec.Branch, "error", rmErr)
+			}
+		}
+		o.releaseRemoteHost(host)
+		o.releaseSpawnSlot(req.ParentProfile, req.ChildProfile, req.childLimitKey)
+	}
+
+	// Check the workspace out on the remote host; the child runs there and
+	// its commits are fetched back into the local branch when it exits.
+	var remoteWS remote.Workspace
+	if host != nil {
+		remoteWS = host.WorkspaceFor(o.remoteProject(), rec.ID)
+		prepCtx, prepCancel := context.WithTimeout(ctx, remoteTransferTimeout)
+		err := host.Prepare(prepCtx, workDir, remoteWS)
+		prepCancel()
+		if err != nil {
+			failSetup(fmt.Sprintf("remote host %s: %v", host.Name(), err))
+			return rec.ID, fmt.Errorf("preparing remote host %s: %w", host.Name(), err)
+		}
+		rec.RemoteHost = host.Name()
+		rec.RemoteWorktree = remoteWS.Dir
+		if err := o.withSpawnRecordLock(rec.ID, func(stored *store.SpawnRecord) error {
+			stored.RemoteHost = host.Name()
+			stored.RemoteWorktree = remoteWS.Dir
+			return nil
+		}); err != nil {
+			debug.LogKV("orch", "failed to persist remote host", "spawn_id", rec.ID, "error", err)
+		}
+		debug.LogKV("orch", "spawn dispatched to remote host",
+			"spawn_id", rec.ID,
+			"host", host.Name(),
+			"remote_worktree", remoteWS.Dir,
+		)
+	}
+
+	// Let the isolation backend wrap the workspace (e.g. start the spawn's
+	// container). It is released together with the worktree.
+	var isoWrapper isolation.Wrapper
+	if backend.Name() != store.IsolationWorktree {
+		attachCtx, attachCancel := context.WithTimeout(ctx, isolationAttachTimeout)
+		isoWrapper, err = backend.Attach(attachCtx, isolation.Workspace{
+			SpawnID:  rec.ID,
+			Dir:      workDir,
+			Agents:   o.spawnAgents(childProf),
+			ReadOnly: req.ReadOnly,
+		})
+		attachCancel()
+		if err != nil {
+			if relErr := backend.Release(context.Background(), rec.ID); relErr != nil {
+				debug.LogKV("orch", "isolation cleanup failed after attach error", "spawn_id", rec.ID, "error", relErr)
+			}
+			failSetup(fmt.Sprintf("%s isolation: %v", backend.Name(), err))
+			return rec.ID, fmt.Errorf("%s isolation: %w", backend.Name(), err)
+		}
+		rec.Isolation = backend.Name()
+		if err := o.withSpawnRecordLock(rec.ID, func(stored *store.SpawnRecord) error {
+			stored.Isolation = backend.Name()
+			return nil
+		}); err != nil {
+			debug.LogKV("orch", "failed to persist spawn isolation", "spawn_id", rec.ID, "error", err)
+		}
+	}
+
+	// Build child prompt. Remote and container-isolated children have no adaf
+	// CLI to call back with.
+	noAdafCLI := host != nil || isoWrapper != nil
 	projCfg, _ := o.store.LoadProject()
 	parentPlanID := req.PlanID
 	if parentPlanID == "" {
@@ -823,15 +993,12 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 		ParentTurnID: req.ParentTurnID,
 		Delegation:   req.ChildDelegation,
 		Skills:       req.ChildSkills,
+		NoAdafCLI:    noAdafCLI,
 	})
 
-	workDir := o.repoRoot
-	if wtPath != "" {
-		workDir = wtPath
-	}
-
 	agentEnv := map[string]string{
 		"ADAF_TURN_ID":     fmt.Sprintf("%d", rec.ID),
+		"ADAF_SPAWN_ID":    fmt.Sprintf("%d", rec.ID),
 		"ADAF_PROFILE":     childProf.Name,
 		"ADAF_PARENT_TURN": fmt.Sprintf("%d", req.ParentTurnID),
 		"ADAF_POSITION":    req.ChildPosition,
@@ -876,13 +1043,24 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 		Stdout:    io.Discard,
 		Stderr:    io.Discard,
 		EventSink: streamCh,
+		Sandbox:   sandbox.FromConfig(req.ChildSandbox, childProf.Agent),
 	}
+	if agentCfg.Sandbox != nil && req.ReadOnly {
+		agentCfg.Sandbox.ReadOnly = true
+	}
+	if host != nil {
+		agentCfg.Remote = host.Exec(remoteWS, childProf.Agent)
+	}
+	agentCfg.Isolation = isoWrapper
+	fallbacks := o.spawnFallbacks(req, childProf, agentCfg, launch.Env, agentsCfg, host, remoteWS)
 
 	var (
 		childCtx    context.Context
 		childCancel context.CancelFunc
 	)
-	if req.ChildTimeout > 0 {
+	// A usage pause does not count toward the child's timeout; it is applied
+	// once the pause ends.
+	if req.ChildTimeout > 0 && usageBlock == nil {
 		childCtx, childCancel = context.WithTimeout(ctx, req.ChildTimeout)
 	} else {
 		childCtx, childCancel = context.WithCancel(ctx)
@@ -906,6 +1084,23 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 	o.spawns[rec.ID] = as
 	o.mu.Unlock()
 
+	// The spawn is charged to the tracker of the turn that requested it, so
+	// loop, step and ancestor spawn limits cover the whole tree.
+	spend := budget.New(fmt.Sprintf("spawn #%d (%s)", rec.ID, req.ChildProfile), req.ChildBudget, budget.ForTurn(req.ParentTurnID))
+	spend.OnSoftLimit(func(t *budget.Tracker) {
+		debug.LogKV("orch", "spawn budget soft limit reached", "spawn_id", rec.ID, "scope", t.Name())
+		as.windDown(budget.WindDownMessage(t))
+	})
+	spend.OnHardLimit(func(t *budget.Tracker) {
+		debug.LogKV("orch", "spawn budget hard limit reached; cancelling",
+			"spawn_id", rec.ID,
+			"scope", t.Name(),
+			"spent", budget.Describe(t.Spent(), t.Limit()),
+		)
+		childCancel()
+	})
+	meter := &budget.Meter{}
+
 	// Bridge stream events into the ring buffer and forward them to eventCh for live sessions.
 	eventDone := make(chan struct{})
 	go func() {
@@ -926,6 +1121,7 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 			if ev.Parsed.Type == "" {
 				continue
 			}
+			spend.Add(meter.Observe(ev.Parsed))
 			o.emitEvent("agent_event", events.AgentEventMsg{Event: ev.Parsed, Raw: ev.Raw, SpawnID: rec.ID})
 		}
 	}()
@@ -980,6 +1176,15 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 		defer o.spawnWG.Done()
 		defer close(done)
 		defer o.onSpawnComplete(rec.ID, req.ParentProfile, req.ChildProfile, req.childLimitKey)
+		defer o.releaseRemoteHost(host)
+		var childTurnIDs []int
+		defer func() {
+			for _, id := range childTurnIDs {
+				budget.Unbind(id)
+				usage.UnbindGate(id)
+			}
+			spend.Detach()
+		}()
 
 		debug.LogKV("orch", "spawn goroutine started",
 			"spawn_id", rec.ID,
@@ -992,8 +1197,31 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 			Agent:       agentInstance,
 			Config:      agentCfg,
 			PlanID:      parentPlanID,
-			ProfileName: req.ChildProfile,
+			ProfileName: childProfileName,
+			GlobalCfg:   o.globalCfg,
+			Fallbacks:   fallbacks,
+			OnFailover: func(failedTurnID int, from, to, reason string) {
+				if err := o.withSpawnRecordLock(rec.ID, func(stored *store.SpawnRecord) error {
+					stored.Failovers = append(stored.Failovers, store.ProfileFailover{
+						From:   from,
+						To:     to,
+						Reason: reason,
+						TurnID: failedTurnID,
+						At:     time.Now().UTC(),
+					})
+					return nil
+				}); err != nil {
+					debug.LogKV("orch", "failed to persist spawn failover",
+						"spawn_id", rec.ID,
+						"turn_id", failedTurnID,
+						"error", err,
+					)
+				}
+			},
 			OnStart: func(turnID int, turnHexID string) {
+				childTurnIDs = append(childTurnIDs, turnID)
+				budget.Bind(turnID, spend)
+				usage.BindGate(turnID, usageGate)
 				if err := o.withSpawnRecordLock(rec.ID, func(stored *store.SpawnRecord) error {
 					stored.ChildTurnID = turnID
 					return nil
@@ -1037,6 +1265,7 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 					ParentTurnID: req.ParentTurnID,
 					Delegation:   req.ChildDelegation,
 					Skills:       req.ChildSkills,
+					NoAdafCLI:    noAdafCLI,
 				})
 				return newPrompt
 			},
@@ -1073,9 +1302,32 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 				return wr, morePending
 			},
 			InterruptCh: interruptCh,
+			OnTurnContext: func(cancel context.CancelFunc) {
+				as.turnMu.Lock()
+				as.turnCancel = cancel
+				as.turnMu.Unlock()
+			},
 		}
 
-		err := l.Run(childCtx)
+		var err error
+		runCtx := childCtx
+		if usageBlock != nil {
+			err = o.waitForSpawnUsage(childCtx, rec.ID, childProf, usageGate, usageBlock)
+			if req.ChildTimeout > 0 {
+				var cancelRun context.CancelFunc
+				runCtx, cancelRun = context.WithTimeout(childCtx, req.ChildTimeout)
+				defer cancelRun()
+			}
+		}
+		if err == nil {
+			err = l.Run(runCtx)
+		}
+		checkResults, verifyCommitNote, err := o.verifySpawnWork(runCtx, rec.ID, l, acceptanceRun{
+			checks:    req.Checks,
+			retries:   req.VerifyRetries,
+			sandbox:   agentCfg.Sandbox.Clone(),
+			isolation: isoWrapper,
+		}, err)
 		debug.LogKV("orch", "spawn loop finished",
 			"spawn_id", rec.ID,
 			"child_profile", req.ChildProfile,
@@ -1111,6 +1363,16 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 			recSnapshot = rec
 		}
 		autoCommitNote, autoCommitErr := o.autoCommitSpawnWork(recSnapshot)
+		if autoCommitNote == "" {
+			autoCommitNote = verifyCommitNote
+		}
+		if status == store.SpawnStatusCompleted && !store.AcceptancePassed(checkResults) {
+			status = store.SpawnStatusFailedVerification
+			result = verificationFailureReason(checkResults)
+		}
+		if len(checkResults) > 0 {
+			summary = appendSpawnSummary(summary, verificationReport(checkResults))
+		}
 		if status == store.SpawnStatusFailed {
 			if timedOut {
 				timeoutNote := timedOutSpawnMessage(rec.ID, req.ChildTimeoutMins, req.ChildProfile, req.ChildRole)
@@ -1120,6 +1382,10 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 				summary = appendSpawnSummary(summary, failedSpawnMessage(result))
 			}
 		}
+		if budgetErr := spend.Err(); budgetErr != nil {
+			result = appendSpawnResult(result, budgetErr.Error())
+			summary = appendSpawnSummary(summary, budgetErr.Error())
+		}
 		if status == store.SpawnStatusCanceled {
 			cancelNote := canceledSpawnMessage(autoCommitNote != "")
 			result = appendSpawnResult(result, cancelNote)
@@ -1130,13 +1396,19 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 		} else if autoCommitNote != "" {
 			result = appendSpawnResult(result, autoCommitNote)
 		}
+		if recSnapshot.RemoteHost != "" {
+			o.releaseRemoteSpawn(recSnapshot)
+		}
 		// Clean up read-only worktrees immediately — there's nothing to merge.
-		if recSnapshot.ReadOnly && recSnapshot.WorktreePath != "" {
-			cleanCtx, cleanCancel := context.WithTimeout(context.Background(), 10*time.Second)
-			if rmErr := o.worktrees.Remove(cleanCtx, recSnapshot.WorktreePath, false); rmErr != nil {
-				debug.LogKV("orch", "read-only worktree cleanup failed",
-					"spawn_id", rec.ID, "worktree", recSnapshot.WorktreePath, "error", rmErr)
+		if recSnapshot.ReadOnly {
+			cleanCtx, cleanCancel := context.WithTimeout(context.Background(), 30*time.Second)
+			if recSnapshot.WorktreePath != "" {
+				if rmErr := o.worktrees.Remove(cleanCtx, recSnapshot.WorktreePath, false); rmErr != nil {
+					debug.LogKV("orch", "read-only worktree cleanup failed",
+						"spawn_id", rec.ID, "worktree", recSnapshot.WorktreePath, "error", rmErr)
+				}
 			}
+			o.releaseIsolation(cleanCtx, recSnapshot)
 			cleanCancel()
 		}
 		if err := o.withSpawnRecordLock(rec.ID, func(stored *store.SpawnRecord) error {
@@ -1153,6 +1425,8 @@ func (o *Orchestrator) startSpawn(ctx context.Context, req SpawnRequest, childPr
 			)
 		}
 
+		o.notifySpawnEnd(rec.ID, spend.Err())
+
 		parentTurnID := req.ParentTurnID
 		if finalRec, err := o.store.GetSpawn(rec.ID); err == nil && finalRec != nil && finalRec.ParentTurnID > 0 {
 			parentTurnID = finalRec.ParentTurnID
@@ -1191,7 +1465,7 @@ func (o *Orchestrator) resolveWorkspaceBaseRef(req SpawnRequest) (string, error)
 	}
 
 	switch strings.ToLower(strings.TrimSpace(source.Status)) {
-	case store.SpawnStatusCompleted, store.SpawnStatusFailed, store.SpawnStatusCanceled, store.SpawnStatusCancelled:
+	case store.SpawnStatusCompleted, store.SpawnStatusFailed, store.SpawnStatusFailedVerification, store.SpawnStatusCanceled, store.SpawnStatusCancelled:
 		return source.Branch, nil
 	default:
 		return "", fmt.Errorf(
@@ -1258,6 +1532,9 @@ func (o *Orchestrator) autoCommitSpawnWork(rec *store.SpawnRecord) (string, erro
 	if rec == nil || rec.WorktreePath == "" || rec.Branch == "" || rec.ReadOnly {
 		return "", nil
 	}
+	if rec.RemoteHost != "" {
+		return o.collectRemoteSpawnWork(rec)
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
@@ -1548,6 +1825,7 @@ func (o *Orchestrator) onSpawnComplete(spawnID int, parentProfile, childProfile,
 	o.decrementInstancesLocked(childProfile)
 	o.decrementOptionInstancesLocked(childLimitKey)
 	o.mu.Unlock()
+	o.scheduleDrain()
 }
 
 func (o *Orchestrator) releaseSpawnSlot(parentProfile, childProfile, childLimitKey string) {
@@ -1556,6 +1834,7 @@ func (o *Orchestrator) releaseSpawnSlot(parentProfile, childProfile, childLimitK
 	o.decrementInstancesLocked(childProfile)
 	o.decrementOptionInstancesLocked(childLimitKey)
 	o.mu.Unlock()
+	o.scheduleDrain()
 }
 
 func (o *Orchestrator) decrementRunningLocked(profile string) {
@@ -1627,6 +1906,7 @@ func (o *Orchestrator) pendingReviewResults(pending map[int]struct{}) []SpawnRes
 			ReadOnly:          rec.ReadOnly,
 			Branch:            rec.Branch,
 			Review:            true,
+			QueuePosition:     rec.QueuePosition,
 			Elapsed:           health.Elapsed,
 			CompactionCount:   health.CompactionCount,
 			ReadCount:         health.ReadCount,
@@ -1943,6 +2223,23 @@ func (o *Orchestrator) InterruptSpawn(spawnID int, message string) error {
 	return nil
 }
 
+// windDown delivers msg to the child's next turn and ends only its current
+// turn, so the child can wrap up instead of being killed.
+func (as *activeSpawn) windDown(msg string) {
+	select {
+	case as.interruptCh <- msg:
+	default:
+		debug.LogKV("orch", "spawn wind-down dropped: channel full", "spawn_id", as.spawnID)
+		return
+	}
+	as.turnMu.Lock()
+	cancel := as.turnCancel
+	as.turnMu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
 // InspectSpawn returns recent stream events from a running spawn's event buffer.
 func (o *Orchestrator) InspectSpawn(spawnID int) ([]stream.RawEvent, error) {
 	o.mu.Lock()
@@ -1956,9 +2253,45 @@ func (o *Orchestrator) InspectSpawn(spawnID int) ([]stream.RawEvent, error) {
 	return as.eventBuffer.Snapshot(), nil
 }
 
+// Merge conflict modes for MergeOptions.OnConflict.
+const (
+	// MergeConflictAbort restores the parent checkout to its pre-merge state.
+	MergeConflictAbort = "abort"
+	// MergeConflictKeep leaves the merge in progress so the conflicts can be
+	// resolved in place and concluded with CompleteMerge.
+	MergeConflictKeep = "keep"
+)
+
+// MergeOptions controls how a spawn branch is merged.
+type MergeOptions struct {
+	Squash     bool
+	OnConflict string // MergeConflictAbort (default) or MergeConflictKeep
+
+	// Approval gates the merge behind a human decision on top of the gate
+	// recorded on the spawn when it was created.
+	Approval    *config.ApprovalPolicy
+	RequestedBy int // turn asking for the merge, recorded on new approvals
+}
+
 // Merge merges a completed spawn's branch into the current branch.
+// Conflicts are aborted; use MergeWithOptions to keep them open.
 func (o *Orchestrator) Merge(ctx context.Context, spawnID int, squash bool) (string, error) {
-	debug.LogKV("orch", "Merge() called", "spawn_id", spawnID, "squash", squash)
+	return o.MergeWithOptions(ctx, spawnID, MergeOptions{Squash: squash})
+}
+
+// MergeWithOptions merges a completed spawn's branch into the current branch.
+// When git reports conflicts, the conflicted paths are recorded on the spawn
+// and a *worktree.MergeConflictError is returned; the parent checkout is then
+// either restored or left mid-merge depending on opts.OnConflict.
+func (o *Orchestrator) MergeWithOptions(ctx context.Context, spawnID int, opts MergeOptions) (string, error) {
+	onConflict := strings.ToLower(strings.TrimSpace(opts.OnConflict))
+	if onConflict == "" {
+		onConflict = MergeConflictAbort
+	}
+	if onConflict != MergeConflictAbort && onConflict != MergeConflictKeep {
+		return "", fmt.Errorf("invalid conflict mode %q (valid: %s, %s)", opts.OnConflict, MergeConflictAbort, MergeConflictKeep)
+	}
+	debug.LogKV("orch", "Merge() called", "spawn_id", spawnID, "squash", opts.Squash, "on_conflict", onConflict)
 	rec, err := o.store.GetSpawn(spawnID)
 	if err != nil {
 		return "", fmt.Errorf("spawn %d not found: %w", spawnID, err)
@@ -1969,23 +2302,134 @@ func (o *Orchestrator) Merge(ctx context.Context, spawnID int, squash bool) (str
 	if rec.Branch == "" {
 		return "", fmt.Errorf("spawn %d has no branch (read-only?)", spawnID)
 	}
+	if err := o.checkMergeApproval(ctx, rec, opts); err != nil {
+		return "", err
+	}
 
 	var hash string
-	msg := fmt.Sprintf("Merge spawn #%d (%s): %s", spawnID, rec.ChildProfile, rec.Task)
-	if squash {
+	msg := spawnMergeMessage(rec)
+	if opts.Squash {
 		hash, err = o.worktrees.MergeSquash(ctx, rec.Branch, msg)
 	} else {
 		hash, err = o.worktrees.Merge(ctx, rec.Branch, msg)
 	}
+	if err != nil {
+		var conflict *worktree.MergeConflictError
+		if !errors.As(err, &conflict) {
+			return "", err
+		}
+		debug.LogKV("orch", "merge conflict", "spawn_id", spawnID, "files", len(conflict.Files), "on_conflict", onConflict)
+		if onConflict == MergeConflictAbort {
+			if abortErr := o.worktrees.AbortMerge(ctx); abortErr != nil {
+				return "", fmt.Errorf("%w (abort failed: %v)", err, abortErr)
+			}
+		}
+		rec.ConflictFiles = conflict.Files
+		if updateErr := o.store.UpdateSpawn(rec); updateErr != nil {
+			debug.LogKV("orch", "failed to record merge conflict", "spawn_id", spawnID, "error", updateErr)
+		}
+		return "", err
+	}
+
+	o.markSpawnMerged(rec, hash)
+	return hash, nil
+}
+
+// CompleteMerge concludes a merge that was kept open after conflicts, once
+// every conflicted path has been resolved and staged.
+func (o *Orchestrator) CompleteMerge(ctx context.Context, spawnID int) (string, error) {
+	debug.LogKV("orch", "CompleteMerge() called", "spawn_id", spawnID)
+	rec, err := o.store.GetSpawn(spawnID)
+	if err != nil {
+		return "", fmt.Errorf("spawn %d not found: %w", spawnID, err)
+	}
+	if rec.Status != "completed" {
+		return "", fmt.Errorf("spawn %d is %s, not completed", spawnID, rec.Status)
+	}
+	if len(rec.ConflictFiles) == 0 {
+		return "", fmt.Errorf("spawn %d has no recorded merge conflicts", spawnID)
+	}
+	hash, err := o.worktrees.CommitMerge(ctx, spawnMergeMessage(rec))
 	if err != nil {
 		return "", err
 	}
+	o.markSpawnMerged(rec, hash)
+	return hash, nil
+}
 
+// RecordMergeResolver links a conflicted spawn to the spawn that was started
+// to resolve its conflicts.
+func (o *Orchestrator) RecordMergeResolver(spawnID, resolverSpawnID int) error {
+	rec, err := o.store.GetSpawn(spawnID)
+	if err != nil {
+		return fmt.Errorf("spawn %d not found: %w", spawnID, err)
+	}
+	rec.ResolverSpawnID = resolverSpawnID
+	return o.store.UpdateSpawn(rec)
+}
+
+// markSpawnMerged records rec as merged at hash. A resolver's branch carries
+// the work of the conflicted spawn it was started for, so that spawn is
+// marked merged by the same commit.
+func (o *Orchestrator) markSpawnMerged(rec *store.SpawnRecord, hash string) {
 	rec.Status = "merged"
 	rec.MergeCommit = hash
+	rec.ConflictFiles = nil
 	o.store.UpdateSpawn(rec)
 
-	return hash, nil
+	spawns, err := o.store.ListSpawns()
+	if err != nil {
+		debug.LogKV("orch", "failed to list spawns for resolved conflicts", "spawn_id", rec.ID, "error", err)
+		return
+	}
+	for i := range spawns {
+		orig := &spawns[i]
+		if orig.ResolverSpawnID == rec.ID && orig.Status == store.SpawnStatusCompleted {
+			debug.LogKV("orch", "resolver merged; marking conflicted spawn merged", "spawn_id", orig.ID, "resolver_spawn_id", rec.ID)
+			o.markSpawnMerged(orig, hash)
+		}
+	}
+}
+
+func spawnMergeMessage(rec *store.SpawnRecord) string {
+	return fmt.Sprintf("Merge spawn #%d (%s): %s", rec.ID, rec.ChildProfile, rec.Task)
+}
+
+// maxResolverHunkBytes caps how much conflict diff is inlined into a resolver task.
+const maxResolverHunkBytes = 48 * 1024
+
+// MergeResolverTask builds the task for a child that resolves the conflicts
+// reported when merging spawn rec. The child is expected to run in a
+// workspace created from rec's branch (see SpawnRequest.WorkspaceFromSpawnID).
+func MergeResolverTask(rec *store.SpawnRecord, conflict *worktree.MergeConflictError) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Resolve the merge conflicts between spawn #%d and the parent branch.\n\n", rec.ID)
+	fmt.Fprintf(&b, "Your workspace starts at the tip of spawn #%d (%s), whose task was:\n%s\n\n", rec.ID, rec.Branch, rec.Task)
+	target := conflict.Target
+	if target == "" {
+		target = "the parent branch HEAD"
+	}
+	fmt.Fprintf(&b, "1. Run `git merge %s` in your workspace.\n", target)
+	b.WriteString("2. Resolve every conflict so that both sides' intent is preserved, then commit the merge.\n")
+	b.WriteString("3. Make sure the project still builds and its tests pass.\n\n")
+	b.WriteString("Conflicted files:\n")
+	for _, f := range conflict.Files {
+		fmt.Fprintf(&b, "- %s\n", f)
+	}
+	if hunks := strings.TrimSpace(conflict.Hunks); hunks != "" {
+		if len(hunks) > maxResolverHunkBytes {
+			// Cut on a rune boundary so the task stays valid UTF-8.
+			cut := maxResolverHunkBytes
+			for cut > 0 && !utf8.RuneStart(hunks[cut]) {
+				cut--
+			}
+			hunks = hunks[:cut] + "\n... (truncated)"
+		}
+		b.WriteString("\nConflict hunks as reported by the parent merge:\n```diff\n")
+		b.WriteString(hunks)
+		b.WriteString("\n```\n")
+	}
+	return b.String()
 }
 
 // Reject rejects a spawn's work and cleans up.
@@ -1996,6 +2440,11 @@ func (o *Orchestrator) Reject(ctx context.Context, spawnID int) error {
 		return fmt.Errorf("spawn %d not found: %w", spawnID, err)
 	}
 
+	// A queued spawn never started; drop it from the queue.
+	if o.dequeueSpawn(spawnID, store.SpawnStatusRejected, "rejected while queued") {
+		return nil
+	}
+
 	// Cancel if still running.
 	o.mu.Lock()
 	if as, ok := o.spawns[spawnID]; ok {
@@ -2102,7 +2551,8 @@ func (o *Orchestrator) CleanupSpawnWorktrees(turnIDs []int) {
 			// Only clean up terminal spawns that were NOT explicitly reviewed.
 			// Merged/rejected spawns are cleaned by CleanupReviewedSpawnWorktrees.
 			switch rec.Status {
-			case "completed", "failed", "canceled", "cancelled":
+			case store.SpawnStatusCompleted, store.SpawnStatusFailed, store.SpawnStatusFailedVerification,
+				store.SpawnStatusCanceled, store.SpawnStatusCancelled:
 				// These are orphaned worktrees — the parent never merged them.
 			default:
 				continue
@@ -2114,7 +2564,11 @@ func (o *Orchestrator) CleanupSpawnWorktrees(turnIDs []int) {
 				"branch", rec.Branch,
 				"worktree", rec.WorktreePath,
 			)
-			if rmErr := o.worktrees.RemoveWithBranch(ctx, rec.WorktreePath, rec.Branch); rmErr != nil {
+			// The container goes either way; a failed removal leaves only
+			// the worktree for the next cleanup pass.
+			rmErr := o.worktrees.RemoveWithBranch(ctx, rec.WorktreePath, rec.Branch)
+			o.releaseIsolation(ctx, &rec)
+			if rmErr != nil {
 				debug.LogKV("orch", "orphaned worktree cleanup failed",
 					"spawn_id", rec.ID,
 					"worktree", rec.WorktreePath,
@@ -2167,7 +2621,11 @@ func (o *Orchestrator) CleanupReviewedSpawnWorktrees(turnIDs []int) {
 				"branch", rec.Branch,
 				"worktree", rec.WorktreePath,
 			)
-			if rmErr := o.worktrees.RemoveWithBranch(ctx, rec.WorktreePath, rec.Branch); rmErr != nil {
+			// The container goes either way; a failed removal leaves only
+			// the worktree for the next cleanup pass.
+			rmErr := o.worktrees.RemoveWithBranch(ctx, rec.WorktreePath, rec.Branch)
+			o.releaseIsolation(ctx, &rec)
+			if rmErr != nil {
 				debug.LogKV("orch", "reviewed worktree cleanup failed",
 					"spawn_id", rec.ID,
 					"worktree", rec.WorktreePath,
@@ -2198,6 +2656,11 @@ func (o *Orchestrator) ReparentSpawn(spawnID, newParentTurnID int) error {
 
 	o.mu.Lock()
 	as, ok := o.spawns[spawnID]
+	for _, q := range o.queue {
+		if q.id == spawnID {
+			q.req.ParentTurnID = newParentTurnID
+		}
+	}
 	o.mu.Unlock()
 	if ok && as != nil {
 		as.SetParentTurnID(newParentTurnID)
@@ -2205,7 +2668,8 @@ func (o *Orchestrator) ReparentSpawn(spawnID, newParentTurnID int) error {
 	return nil
 }
 
-// ActiveSpawnsForParent returns IDs of currently running spawns for a parent turn.
+// ActiveSpawnsForParent returns IDs of currently running or queued spawns
+// for a parent turn.
 func (o *Orchestrator) ActiveSpawnsForParent(parentTurnID int) []int {
 	o.mu.Lock()
 	defer o.mu.Unlock()
@@ -2218,7 +2682,7 @@ func (o *Orchestrator) ActiveSpawnsForParent(parentTurnID int) []int {
 			ids = append(ids, as.spawnID)
 		}
 	}
-	return ids
+	return append(ids, o.queuedSpawnsForParentLocked(parentTurnID)...)
 }
 
 // --- Singleton ---
@@ -2229,13 +2693,15 @@ var (
 )
 
 // Init initializes the global orchestrator singleton and cleans up stale
-// worktrees left behind by previous sessions (crashed, killed, etc.).
+// worktrees and queued spawns left behind by previous sessions (crashed,
+// killed, etc.).
 func Init(s *store.Store, globalCfg *config.GlobalConfig, repoRoot string) *Orchestrator {
 	debug.LogKV("orch", "Init() called", "repo_root", repoRoot)
 	globalOrchMu.Lock()
 	defer globalOrchMu.Unlock()
 	globalOrch = New(s, globalCfg, repoRoot)
 	globalOrch.cleanupStaleWorktrees()
+	globalOrch.cancelOrphanedQueuedSpawns()
 	return globalOrch
 }
 
@@ -2245,3 +2711,48 @@ func Get() *Orchestrator {
 	defer globalOrchMu.Unlock()
 	return globalOrch
 }
+
+// spawnFallbacks builds the child's fallback chain from agentCfg, swapping
+// the primary profile's launch settings for each fallback's. Fallbacks run
+// under the spawn's sandbox policy so a failover never widens it.
+func (o *Orchestrator) spawnFallbacks(req SpawnRequest, childProf *config.Profile, agentCfg agent.Config, primaryEnv map[string]string, agentsCfg *agent.AgentsConfig, host *remote.Host, remoteWS remote.Workspace) []loop.Fallback {
+	var out []loop.Fallback
+	for _, fb := range o.globalCfg.FallbackProfiles(childProf.Name) {
+		agentInstance, ok := agent.Get(fb.Agent)
+		if !ok {
+			continue
+		}
+		// A remote child can only fail over to agents its host has.
+		if host != nil && !host.Config.HasAgent(fb.Agent) {
+			continue
+		}
+		launch := agent.BuildLaunchSpec(&fb, agentsCfg, "")
+		env := make(map[string]string, len(agentCfg.Env))
+		for k, v := range agentCfg.Env {
+			if _, primary := primaryEnv[k]; !primary {
+				env[k] = v
+			}
+		}
+		for k, v := range launch.Env {
+			env[k] = v
+		}
+		cfg := agentCfg
+		cfg.Name = fb.Agent
+		cfg.Command = launch.Command
+		cfg.Args = append([]string(nil), launch.Args...)
+		cfg.Env = env
+		cfg.Sandbox = sandbox.FromConfig(req.ChildSandbox, fb.Agent)
+		if cfg.Sandbox != nil && req.ReadOnly {
+			cfg.Sandbox.ReadOnly = true
+		}
+		if host != nil {
+			cfg.Remote = host.Exec(remoteWS, fb.Agent)
+		}
+		out = append(out, loop.Fallback{
+			ProfileName: fb.Name,
+			Agent:       agentInstance,
+			Config:      cfg,
+		})
+	}
+	return out
+}
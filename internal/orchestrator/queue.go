@@ -0,0 +1,374 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// queuedSpawn is a validated spawn request waiting for a concurrency slot.
+type queuedSpawn struct {
+	id        int
+	seq       uint64
+	rank      int // priority rank, lower starts first
+	ctx       context.Context
+	stop      func() bool // detaches the ctx cancellation hook
+	req       SpawnRequest
+	childProf *config.Profile
+}
+
+// spawnPriorityRank orders spawn priorities like the issue queue does.
+func spawnPriorityRank(priority string) int {
+	switch store.NormalizeIssuePriority(priority) {
+	case "critical":
+		return 0
+	case "high":
+		return 1
+	case "medium":
+		return 2
+	case "low":
+		return 3
+	default:
+		return 4
+	}
+}
+
+// resolveSpawnPriority returns the request's explicit priority, or the
+// highest priority among its assigned issues, or medium.
+func (o *Orchestrator) resolveSpawnPriority(req SpawnRequest) (string, error) {
+	if p := strings.TrimSpace(req.Priority); p != "" {
+		p = store.NormalizeIssuePriority(p)
+		if !store.IsValidIssuePriority(p) {
+			return "", fmt.Errorf("invalid spawn priority %q (valid: critical, high, medium, low)", req.Priority)
+		}
+		return p, nil
+	}
+	priority := "medium"
+	for _, id := range req.IssueIDs {
+		issue, err := o.store.GetIssue(id)
+		if err != nil || issue == nil {
+			continue
+		}
+		if p := store.NormalizeIssuePriority(issue.Priority); spawnPriorityRank(p) < spawnPriorityRank(priority) {
+			priority = p
+		}
+	}
+	return priority, nil
+}
+
+// spawnLimitLocked reports which concurrency limit keeps req from starting
+// now, or "" when a slot is free. Callers must hold o.mu.
+func (o *Orchestrator) spawnLimitLocked(req SpawnRequest, childProf *config.Profile) string {
+	// Global child profile instance limit.
+	if childProf.MaxInstances > 0 {
+		if n := o.instances[req.ChildProfile]; n >= childProf.MaxInstances {
+			return fmt.Sprintf("child profile %q has %d running instance(s) (max %d)",
+				req.ChildProfile, n, childProf.MaxInstances)
+		}
+	}
+	// Per-delegation-option instance limit (profile+position+role).
+	if req.ChildMaxInstances > 0 && req.childLimitKey != "" {
+		if n := o.instancesByOption[req.childLimitKey]; n >= req.ChildMaxInstances {
+			return fmt.Sprintf("sub-agent option profile=%q role=%q has %d running instance(s) (max %d)",
+				req.ChildProfile, req.ChildRole, n, req.ChildMaxInstances)
+		}
+	}
+	// Parent concurrency limit from delegation config.
+	if maxPar := req.Delegation.EffectiveMaxParallel(); o.running[req.ParentProfile] >= maxPar {
+		return fmt.Sprintf("parent profile %q has %d running sub-agent(s) (max %d)",
+			req.ParentProfile, o.running[req.ParentProfile], maxPar)
+	}
+	return ""
+}
+
+// reserveSpawnSlotLocked charges req against the concurrency limits; the
+// slot is given back by onSpawnComplete or releaseSpawnSlot.
+func (o *Orchestrator) reserveSpawnSlotLocked(req SpawnRequest) {
+	o.running[req.ParentProfile]++
+	o.instances[req.ChildProfile]++
+	if req.childLimitKey != "" {
+		if o.instancesByOption == nil {
+			o.instancesByOption = make(map[string]int)
+		}
+		o.instancesByOption[req.childLimitKey]++
+	}
+}
+
+// enqueueSpawn records req as a queued spawn that starts once the limit
+// named by reason frees up.
+func (o *Orchestrator) enqueueSpawn(ctx context.Context, req SpawnRequest, childProf *config.Profile, reason string) (int, error) {
+	rec := &store.SpawnRecord{
+		ParentTurnID:         req.ParentTurnID,
+		ParentProfile:        req.ParentProfile,
+		ChildProfile:         req.ChildProfile,
+		ChildPosition:        req.ChildPosition,
+		ChildRole:            req.ChildRole,
+		Task:                 req.Task,
+		IssueIDs:             req.IssueIDs,
+		ReadOnly:             req.ReadOnly,
+		WorkspaceFromSpawnID: req.WorkspaceFromSpawnID,
+		Status:               store.SpawnStatusQueued,
+		Checks:               req.Checks,
+		VerifyRetries:        req.VerifyRetries,
+		Priority:             req.Priority,
+		QueuedAt:             time.Now().UTC(),
+		QueuedByPID:          os.Getpid(),
+		MergeGate:            mergeGate(req.mergeApproval),
+		ChildMergeGate:       mergeGate(req.ChildDelegation.Approval),
+	}
+	if err := o.store.CreateSpawn(rec); err != nil {
+		return 0, fmt.Errorf("creating spawn record: %w", err)
+	}
+	// Queued spawns hold their issues so nobody else picks them meanwhile.
+	o.claimSpawnIssues(rec.ID, req)
+
+	req.queuedSpawnID = rec.ID
+	q := &queuedSpawn{
+		id:        rec.ID,
+		rank:      spawnPriorityRank(req.Priority),
+		ctx:       ctx,
+		req:       req,
+		childProf: childProf,
+	}
+	// A parent that goes away takes its queued children with it.
+	q.stop = context.AfterFunc(ctx, func() { o.cancelQueuedSpawn(rec.ID, "parent context canceled while queued") })
+	o.mu.Lock()
+	o.queueSeq++
+	q.seq = o.queueSeq
+	o.queue = append(o.queue, q)
+	o.mu.Unlock()
+	position := o.persistQueuePositions()[rec.ID]
+	if ctx.Err() != nil {
+		o.cancelQueuedSpawn(rec.ID, "parent context canceled while queued")
+	}
+
+	debug.LogKV("orch", "spawn queued",
+		"spawn_id", rec.ID,
+		"parent_turn", req.ParentTurnID,
+		"child_profile", req.ChildProfile,
+		"priority", req.Priority,
+		"position", position,
+		"reason", reason,
+	)
+	// A slot may have freed up between the limit check and the enqueue.
+	o.drainQueue()
+
+	if req.Wait {
+		o.WaitOne(rec.ID)
+	}
+	return rec.ID, nil
+}
+
+// orderedQueueLocked returns the queue in start order: priority first, then
+// parents with fewer running children (so one busy parent cannot starve its
+// siblings), then arrival. Callers must hold o.mu.
+func (o *Orchestrator) orderedQueueLocked() []*queuedSpawn {
+	runningByParent := make(map[int]int)
+	for _, as := range o.spawns {
+		if as != nil {
+			runningByParent[as.ParentTurnID()]++
+		}
+	}
+	ordered := append([]*queuedSpawn(nil), o.queue...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		ra, rb := runningByParent[a.req.ParentTurnID], runningByParent[b.req.ParentTurnID]
+		if ra != rb {
+			return ra < rb
+		}
+		return a.seq < b.seq
+	})
+	return ordered
+}
+
+// persistQueuePositions stores each queued spawn's 1-based position and
+// returns them by spawn ID. o.mu is held only to read the queue; the writes
+// happen under queuePosMu, so a caller that just took a spawn out of the
+// queue and then calls this knows no earlier pass still writes its record.
+// Callers must not hold o.mu.
+func (o *Orchestrator) persistQueuePositions() map[int]int {
+	o.queuePosMu.Lock()
+	defer o.queuePosMu.Unlock()
+	o.mu.Lock()
+	ordered := o.orderedQueueLocked()
+	o.mu.Unlock()
+
+	positions := make(map[int]int, len(ordered))
+	for i, q := range ordered {
+		positions[q.id] = i + 1
+		rec, err := o.store.GetSpawn(q.id)
+		if err != nil || rec.Status != store.SpawnStatusQueued || rec.QueuePosition == i+1 {
+			continue
+		}
+		rec.QueuePosition = i + 1
+		if err := o.store.UpdateSpawn(rec); err != nil {
+			debug.LogKV("orch", "failed to persist queue position", "spawn_id", q.id, "error", err)
+		}
+	}
+	return positions
+}
+
+// removeQueuedLocked takes spawnID out of the queue. Callers must hold o.mu.
+func (o *Orchestrator) removeQueuedLocked(spawnID int) *queuedSpawn {
+	for i, q := range o.queue {
+		if q.id == spawnID {
+			o.queue = append(o.queue[:i], o.queue[i+1:]...)
+			return q
+		}
+	}
+	return nil
+}
+
+// drainQueue starts queued spawns, best first, while slots are free.
+// Entries blocked on one limit do not hold back entries that fit.
+func (o *Orchestrator) drainQueue() {
+	for {
+		o.mu.Lock()
+		var next *queuedSpawn
+		for _, q := range o.orderedQueueLocked() {
+			if o.spawnLimitLocked(q.req, q.childProf) == "" {
+				next = q
+				break
+			}
+		}
+		if next == nil {
+			o.mu.Unlock()
+			return
+		}
+		o.removeQueuedLocked(next.id)
+		o.reserveSpawnSlotLocked(next.req)
+		o.mu.Unlock()
+		o.persistQueuePositions()
+		if next.stop != nil {
+			next.stop()
+		}
+
+		if err := next.ctx.Err(); err != nil {
+			o.releaseSpawnSlot(next.req.ParentProfile, next.req.ChildProfile, next.req.childLimitKey)
+			o.finishQueuedSpawn(next.id, next.req.ParentTurnID, store.SpawnStatusCanceled, "parent context canceled while queued")
+			continue
+		}
+		debug.LogKV("orch", "starting queued spawn",
+			"spawn_id", next.id,
+			"child_profile", next.req.ChildProfile,
+			"priority", next.req.Priority,
+		)
+		req := next.req
+		req.Wait = false // the caller that queued it is waiting on the record
+		if _, err := o.startSpawn(next.ctx, req, next.childProf); err != nil {
+			debug.LogKV("orch", "queued spawn failed to start", "spawn_id", next.id, "error", err)
+			o.finishQueuedSpawn(next.id, next.req.ParentTurnID, store.SpawnStatusFailed, err.Error())
+		}
+	}
+}
+
+// scheduleDrain starts queued spawns in the background when any are waiting.
+func (o *Orchestrator) scheduleDrain() {
+	o.mu.Lock()
+	pending := len(o.queue) > 0
+	o.mu.Unlock()
+	if pending {
+		go o.drainQueue()
+	}
+}
+
+// cancelQueuedSpawn drops a queued spawn before it started and marks it
+// canceled.
+func (o *Orchestrator) cancelQueuedSpawn(spawnID int, reason string) {
+	o.dequeueSpawn(spawnID, store.SpawnStatusCanceled, reason)
+}
+
+// dequeueSpawn removes a queued spawn and gives it the terminal status. It
+// reports whether the spawn was still queued.
+func (o *Orchestrator) dequeueSpawn(spawnID int, status, result string) bool {
+	o.mu.Lock()
+	q := o.removeQueuedLocked(spawnID)
+	o.mu.Unlock()
+	if q == nil {
+		return false
+	}
+	o.persistQueuePositions()
+	if q.stop != nil {
+		q.stop()
+	}
+	o.finishQueuedSpawn(spawnID, q.req.ParentTurnID, status, result)
+	return true
+}
+
+// finishQueuedSpawn ends a spawn that never left the queue and wakes waiters.
+// Records that already moved on (e.g. startSpawn marked them failed) are
+// left as they are.
+func (o *Orchestrator) finishQueuedSpawn(spawnID, parentTurnID int, status, result string) {
+	if err := o.withSpawnRecordLock(spawnID, func(rec *store.SpawnRecord) error {
+		if rec.Status != store.SpawnStatusQueued {
+			return nil
+		}
+		rec.Status = status
+		rec.Result = result
+		rec.QueuePosition = 0
+		rec.CompletedAt = time.Now().UTC()
+		return nil
+	}); err != nil {
+		debug.LogKV("orch", "failed to finish queued spawn", "spawn_id", spawnID, "error", err)
+	}
+	o.signalWaitAny(parentTurnID)
+}
+
+// queuedSpawnsForParentLocked lists queued spawn IDs for a parent turn.
+// Callers must hold o.mu.
+func (o *Orchestrator) queuedSpawnsForParentLocked(parentTurnID int) []int {
+	var ids []int
+	for _, q := range o.queue {
+		if q.req.ParentTurnID == parentTurnID {
+			ids = append(ids, q.id)
+		}
+	}
+	return ids
+}
+
+// cancelOrphanedQueuedSpawns marks queued records whose queue died with its
+// process as canceled. The queue lives in memory, so after a restart nothing
+// would ever start them; records queued by another live process are left
+// to it.
+func (o *Orchestrator) cancelOrphanedQueuedSpawns() {
+	spawns, err := o.store.ListSpawns()
+	if err != nil {
+		debug.LogKV("orch", "failed to list spawns for queue recovery", "error", err)
+		return
+	}
+	self := os.Getpid()
+	for _, rec := range spawns {
+		if rec.Status != store.SpawnStatusQueued {
+			continue
+		}
+		if rec.QueuedByPID != self && processAlive(rec.QueuedByPID) {
+			continue
+		}
+		debug.LogKV("orch", "canceling orphaned queued spawn", "spawn_id", rec.ID, "queued_by_pid", rec.QueuedByPID)
+		o.finishQueuedSpawn(rec.ID, rec.ParentTurnID, store.SpawnStatusCanceled, "queue lost when its process exited")
+	}
+}
+
+// processAlive reports whether a process with the given PID is running.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// On Unix, FindProcess always succeeds. Send signal 0 to check liveness.
+	return proc.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,289 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// newGatedQueueOrchestrator returns an orchestrator whose children block
+// until the returned gate file exists, with at most one running child.
+func newGatedQueueOrchestrator(t *testing.T) (*Orchestrator, *store.Store, string) {
+	t.Helper()
+	repo := initGitRepo(t)
+	s := newTestStore(t, repo)
+
+	gate := filepath.Join(t.TempDir(), "gate")
+	cmdPath := filepath.Join(t.TempDir(), "agent.sh")
+	script := "#!/bin/sh\ncat >/dev/null\nwhile [ ! -f " + gate + " ]; do sleep 0.05; done\necho done\n"
+	if err := os.WriteFile(cmdPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := agent.SaveAgentsConfig(&agent.AgentsConfig{
+		Agents: map[string]agent.AgentRecord{"generic": {Name: "generic", Path: cmdPath}},
+	}); err != nil {
+		t.Fatalf("SaveAgentsConfig(): %v", err)
+	}
+
+	cfg := &config.GlobalConfig{Profiles: []config.Profile{
+		{Name: "parent", Agent: "generic"},
+		{Name: "worker", Agent: "generic"},
+	}}
+	return New(s, cfg, repo), s, gate
+}
+
+func assertQueuedSpawn(t *testing.T, o *Orchestrator, s *store.Store, spawnID, position int) {
+	t.Helper()
+	if spawnID == 0 {
+		t.Fatal("spawnID = 0, want the queued spawn's record")
+	}
+	rec, err := s.GetSpawn(spawnID)
+	if err != nil {
+		t.Fatalf("GetSpawn(%d): %v", spawnID, err)
+	}
+	if rec.Status != store.SpawnStatusQueued || rec.QueuePosition != position {
+		t.Fatalf("spawn %d = status %q position %d, want queued at %d", spawnID, rec.Status, rec.QueuePosition, position)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.queue) == 0 || o.queue[len(o.queue)-1].id != spawnID {
+		t.Fatalf("spawn %d is not in the orchestrator queue", spawnID)
+	}
+}
+
+func queueTestRequest(task string) SpawnRequest {
+	return SpawnRequest{
+		ParentTurnID:  151,
+		ParentProfile: "parent",
+		ChildProfile:  "worker",
+		Task:          task,
+		ReadOnly:      true,
+		Delegation: &config.DelegationConfig{
+			MaxParallel: 1,
+			Profiles:    []config.DelegationProfile{{Name: "worker"}},
+		},
+	}
+}
+
+func TestSpawn_QueuesWhenLimitReachedAndStartsOnCompletion(t *testing.T) {
+	o, s, gate := newGatedQueueOrchestrator(t)
+
+	first, err := o.Spawn(context.Background(), queueTestRequest("first"))
+	if err != nil {
+		t.Fatalf("Spawn(first) error = %v", err)
+	}
+	second, err := o.Spawn(context.Background(), queueTestRequest("second"))
+	if err != nil {
+		t.Fatalf("Spawn(second) error = %v, want it queued", err)
+	}
+
+	rec, err := s.GetSpawn(second)
+	if err != nil {
+		t.Fatalf("GetSpawn: %v", err)
+	}
+	if rec.Status != store.SpawnStatusQueued || rec.QueuePosition != 1 || rec.QueuedAt.IsZero() || rec.QueuedByPID != os.Getpid() {
+		t.Fatalf("queued record = status %q position %d queued_at %v pid %d", rec.Status, rec.QueuePosition, rec.QueuedAt, rec.QueuedByPID)
+	}
+	if rec.Priority != "medium" {
+		t.Fatalf("priority = %q, want medium", rec.Priority)
+	}
+	if ids := o.ActiveSpawnsForParent(151); !slices.Contains(ids, first) || !slices.Contains(ids, second) {
+		t.Fatalf("ActiveSpawnsForParent = %v, want running and queued spawns", ids)
+	}
+
+	if err := os.WriteFile(gate, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := o.WaitOne(first); got.Status != "completed" {
+		t.Fatalf("first status = %q, result = %q", got.Status, got.Result)
+	}
+	if got := o.WaitOne(second); got.Status != "completed" {
+		t.Fatalf("second status = %q, result = %q", got.Status, got.Result)
+	}
+	rec, _ = s.GetSpawn(second)
+	if rec.QueuePosition != 0 || rec.QueuedAt.IsZero() || rec.StartedAt.Before(rec.QueuedAt) {
+		t.Fatalf("started record = position %d queued_at %v started_at %v", rec.QueuePosition, rec.QueuedAt, rec.StartedAt)
+	}
+}
+
+func TestReject_DropsQueuedSpawn(t *testing.T) {
+	o, s, gate := newGatedQueueOrchestrator(t)
+
+	first, err := o.Spawn(context.Background(), queueTestRequest("first"))
+	if err != nil {
+		t.Fatalf("Spawn(first) error = %v", err)
+	}
+	second, err := o.Spawn(context.Background(), queueTestRequest("second"))
+	if err != nil {
+		t.Fatalf("Spawn(second) error = %v", err)
+	}
+	third, err := o.Spawn(context.Background(), queueTestRequest("third"))
+	if err != nil {
+		t.Fatalf("Spawn(third) error = %v", err)
+	}
+
+	if err := o.Reject(context.Background(), second); err != nil {
+		t.Fatalf("Reject() error = %v", err)
+	}
+	rec, _ := s.GetSpawn(second)
+	if rec.Status != store.SpawnStatusRejected {
+		t.Fatalf("rejected queued spawn status = %q", rec.Status)
+	}
+	if rec, _ := s.GetSpawn(third); rec.QueuePosition != 1 {
+		t.Fatalf("third queue position = %d, want 1 after the spawn ahead was rejected", rec.QueuePosition)
+	}
+
+	if err := os.WriteFile(gate, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	o.WaitOne(first)
+	if got := o.WaitOne(third); got.Status != "completed" {
+		t.Fatalf("third status = %q, result = %q", got.Status, got.Result)
+	}
+	if rec, _ := s.GetSpawn(second); rec.Status != store.SpawnStatusRejected || rec.ChildTurnID != 0 {
+		t.Fatalf("rejected spawn was started: status %q child turn %d", rec.Status, rec.ChildTurnID)
+	}
+}
+
+func TestSpawn_QueuedSpawnCanceledWithContext(t *testing.T) {
+	o, s, gate := newGatedQueueOrchestrator(t)
+	t.Cleanup(func() { os.WriteFile(gate, nil, 0644) })
+
+	if _, err := o.Spawn(context.Background(), queueTestRequest("first")); err != nil {
+		t.Fatalf("Spawn(first) error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	queued, err := o.Spawn(ctx, queueTestRequest("second"))
+	if err != nil {
+		t.Fatalf("Spawn(second) error = %v", err)
+	}
+	cancel()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rec, _ := s.GetSpawn(queued)
+		if rec.Status == store.SpawnStatusCanceled {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("queued spawn status = %q after its context was canceled", rec.Status)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if ids := o.ActiveSpawnsForParent(151); slices.Contains(ids, queued) {
+		t.Fatalf("ActiveSpawnsForParent = %v still lists the canceled spawn", ids)
+	}
+}
+
+func TestSpawn_RejectsInvalidPriority(t *testing.T) {
+	o, _, _ := newGatedQueueOrchestrator(t)
+	req := queueTestRequest("bad priority")
+	req.Priority = "urgent"
+	if _, err := o.Spawn(context.Background(), req); err == nil {
+		t.Fatal("Spawn() error = nil, want invalid priority error")
+	}
+}
+
+func TestOrderedQueue_PriorityThenFairnessThenFIFO(t *testing.T) {
+	o := &Orchestrator{spawns: map[int]*activeSpawn{
+		1: {spawnID: 1, parentTurnID: 10},
+		2: {spawnID: 2, parentTurnID: 10},
+		3: {spawnID: 3, parentTurnID: 20},
+	}}
+	entry := func(id, parentTurn int, priority string, seq uint64) *queuedSpawn {
+		return &queuedSpawn{
+			id:   id,
+			seq:  seq,
+			rank: spawnPriorityRank(priority),
+			req:  SpawnRequest{ParentTurnID: parentTurn, Priority: priority},
+		}
+	}
+	o.queue = []*queuedSpawn{
+		entry(101, 10, "medium", 1), // busiest parent
+		entry(102, 20, "medium", 2),
+		entry(103, 30, "medium", 3), // idle parent
+		entry(104, 10, "low", 4),
+		entry(105, 10, "critical", 5),
+		entry(106, 30, "medium", 6),
+	}
+
+	var got []int
+	for _, q := range o.orderedQueueLocked() {
+		got = append(got, q.id)
+	}
+	want := []int{105, 103, 106, 102, 101, 104}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("queue order = %v, want %v", got, want)
+	}
+}
+
+func TestInit_CancelsQueuedSpawnsOfExitedProcesses(t *testing.T) {
+	repo := initGitRepo(t)
+	s := newTestStore(t, repo)
+
+	exited := exec.Command("true")
+	if err := exited.Run(); err != nil {
+		t.Fatal(err)
+	}
+	pids := map[string]int{
+		"exited": exited.Process.Pid,
+		"legacy": 0,
+		"live":   os.Getppid(),
+	}
+	ids := make(map[string]int)
+	for name, pid := range pids {
+		rec := &store.SpawnRecord{ChildProfile: "worker", Task: name, Status: store.SpawnStatusQueued, QueuePosition: 1, QueuedByPID: pid}
+		if err := s.CreateSpawn(rec); err != nil {
+			t.Fatalf("CreateSpawn: %v", err)
+		}
+		ids[name] = rec.ID
+	}
+
+	Init(s, &config.GlobalConfig{}, repo)
+
+	for name, id := range ids {
+		rec, err := s.GetSpawn(id)
+		if err != nil {
+			t.Fatalf("GetSpawn(%d): %v", id, err)
+		}
+		want := store.SpawnStatusCanceled
+		if name == "live" {
+			want = store.SpawnStatusQueued
+		}
+		if rec.Status != want {
+			t.Fatalf("%s spawn status = %q, want %q", name, rec.Status, want)
+		}
+		if want == store.SpawnStatusCanceled && (rec.QueuePosition != 0 || rec.CompletedAt.IsZero()) {
+			t.Fatalf("%s spawn = position %d completed_at %v, want finished", name, rec.QueuePosition, rec.CompletedAt)
+		}
+	}
+}
+
+func TestResolveSpawnPriority_UsesHighestIssuePriority(t *testing.T) {
+	repo := initGitRepo(t)
+	s := newTestStore(t, repo)
+	for _, p := range []string{"low", "high"} {
+		if err := s.CreateIssue(&store.Issue{Title: p, Priority: p}); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+	}
+	o := New(s, &config.GlobalConfig{}, repo)
+
+	got, err := o.resolveSpawnPriority(SpawnRequest{IssueIDs: []int{1, 2}})
+	if err != nil || got != "high" {
+		t.Fatalf("resolveSpawnPriority(issues) = %q, %v; want high", got, err)
+	}
+	got, err = o.resolveSpawnPriority(SpawnRequest{IssueIDs: []int{1, 2}, Priority: "Low"})
+	if err != nil || got != "low" {
+		t.Fatalf("resolveSpawnPriority(explicit) = %q, %v; want low", got, err)
+	}
+}
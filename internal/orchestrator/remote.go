@@ -0,0 +1,153 @@
+package orchestrator
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/remote"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// AnyRemoteHost lets the orchestrator pick the least busy eligible host.
+const AnyRemoteHost = "any"
+
+// remoteTransferTimeout bounds each git transfer and script run against a
+// remote host.
+const remoteTransferTimeout = 2 * time.Minute
+
+// acquireRemoteHost resolves the host a spawn of agentName runs on and
+// reserves one of its slots. An empty name runs the spawn locally and
+// returns nil.
+func (o *Orchestrator) acquireRemoteHost(name, agentName string) (*remote.Host, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil
+	}
+	if o.globalCfg == nil || len(o.globalCfg.RemoteHosts) == 0 {
+		return nil, fmt.Errorf("no remote hosts configured (add remote_hosts to ~/.adaf/config.json)")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.remoteLoad == nil {
+		o.remoteLoad = make(map[string]int)
+	}
+	hasCapacity := func(max int, hostName string) bool {
+		return max <= 0 || o.remoteLoad[hostName] < max
+	}
+
+	var picked *remote.Host
+	if strings.EqualFold(name, AnyRemoteHost) {
+		for _, cfg := range o.globalCfg.RemoteHosts {
+			if !cfg.HasAgent(agentName) || !hasCapacity(cfg.MaxSpawns, cfg.Name) {
+				continue
+			}
+			if picked != nil && o.remoteLoad[cfg.Name] >= o.remoteLoad[picked.Name()] {
+				continue
+			}
+			h, err := remote.NewHost(cfg)
+			if err != nil {
+				debug.LogKV("orch", "skipping invalid remote host", "host", cfg.Name, "error", err)
+				continue
+			}
+			picked = h
+		}
+		if picked == nil {
+			return nil, fmt.Errorf("no remote host with free capacity has agent %q", agentName)
+		}
+	} else {
+		cfg := o.globalCfg.FindRemoteHost(name)
+		if cfg == nil {
+			return nil, fmt.Errorf("remote host %q not found", name)
+		}
+		if !cfg.HasAgent(agentName) {
+			return nil, fmt.Errorf("remote host %q does not have agent %q", cfg.Name, agentName)
+		}
+		if !hasCapacity(cfg.MaxSpawns, cfg.Name) {
+			return nil, fmt.Errorf("remote host %q is at max_spawns (%d)", cfg.Name, cfg.MaxSpawns)
+		}
+		h, err := remote.NewHost(*cfg)
+		if err != nil {
+			return nil, err
+		}
+		picked = h
+	}
+	o.remoteLoad[picked.Name()]++
+	return picked, nil
+}
+
+func (o *Orchestrator) releaseRemoteHost(h *remote.Host) {
+	if h == nil {
+		return
+	}
+	o.mu.Lock()
+	o.remoteLoad[h.Name()]--
+	if o.remoteLoad[h.Name()] <= 0 {
+		delete(o.remoteLoad, h.Name())
+	}
+	o.mu.Unlock()
+}
+
+// remoteProject names this project's repository on remote hosts; the path
+// hash keeps checkouts with the same directory name apart.
+func (o *Orchestrator) remoteProject() string {
+	sum := sha256.Sum256([]byte(o.repoRoot))
+	return fmt.Sprintf("%s-%x", filepath.Base(o.repoRoot), sum[:4])
+}
+
+// remoteSpawnHost returns the host and workspace rec ran on.
+func (o *Orchestrator) remoteSpawnHost(rec *store.SpawnRecord) (*remote.Host, remote.Workspace, error) {
+	if o.globalCfg == nil {
+		return nil, remote.Workspace{}, fmt.Errorf("remote host %q not found", rec.RemoteHost)
+	}
+	cfg := o.globalCfg.FindRemoteHost(rec.RemoteHost)
+	if cfg == nil {
+		return nil, remote.Workspace{}, fmt.Errorf("remote host %q not found", rec.RemoteHost)
+	}
+	h, err := remote.NewHost(*cfg)
+	if err != nil {
+		return nil, remote.Workspace{}, err
+	}
+	return h, h.WorkspaceFor(o.remoteProject(), rec.ID), nil
+}
+
+// collectRemoteSpawnWork commits what the child left on its host and
+// fast-forwards the local spawn branch to it.
+func (o *Orchestrator) collectRemoteSpawnWork(rec *store.SpawnRecord) (string, error) {
+	h, ws, err := o.remoteSpawnHost(rec)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), remoteTransferTimeout)
+	defer cancel()
+
+	msg := fmt.Sprintf("adaf: auto-commit spawn #%d (%s)", rec.ID, rec.ChildProfile)
+	hash, committed, err := h.Collect(ctx, rec.WorktreePath, ws, msg)
+	if err != nil {
+		return "", err
+	}
+	if !committed {
+		return "", nil
+	}
+	return fmt.Sprintf("auto-commit: child left uncommitted changes on %s; adaf created commit %s because the child did not commit.", h.Name(), shortHash(hash)), nil
+}
+
+// releaseRemoteSpawn removes the spawn checkout from its host. Commits have
+// already been collected, so the checkout is not needed for review or merge.
+func (o *Orchestrator) releaseRemoteSpawn(rec *store.SpawnRecord) {
+	h, ws, err := o.remoteSpawnHost(rec)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), remoteTransferTimeout)
+		err = h.Release(ctx, ws)
+		cancel()
+	}
+	if err != nil {
+		debug.LogKV("orch", "remote worktree cleanup failed",
+			"spawn_id", rec.ID, "host", rec.RemoteHost, "error", err)
+	}
+}
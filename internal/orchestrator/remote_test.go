@@ -0,0 +1,138 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/config"
+)
+
+func TestSpawn_RemoteHostRunsChildAndFetchesCommits(t *testing.T) {
+	repo := initGitRepo(t)
+	s := newTestStore(t, repo)
+
+	// The agent exists only on the "host": the local command path is bogus
+	// and the host maps the agent to the real script.
+	cmdPath := filepath.Join(t.TempDir(), "remote-agent.sh")
+	script := `#!/bin/sh
+cat >/dev/null
+pwd > where.txt
+git add where.txt
+git -c user.name=Agent -c user.email=agent@example.com commit -q -m "agent commit"
+echo draft > uncommitted.txt
+echo "remote child done: $ADAF_PROFILE"
+`
+	if err := os.WriteFile(cmdPath, []byte(script), 0755); err != nil {
+		t.Fatalf("WriteFile(%q): %v", cmdPath, err)
+	}
+	if err := agent.SaveAgentsConfig(&agent.AgentsConfig{
+		Agents: map[string]agent.AgentRecord{
+			"generic": {Name: "generic", Path: "/nonexistent/generic-agent"},
+		},
+	}); err != nil {
+		t.Fatalf("SaveAgentsConfig(): %v", err)
+	}
+
+	hostRoot := t.TempDir()
+	cfg := &config.GlobalConfig{
+		Profiles: []config.Profile{
+			{Name: "parent", Agent: "generic"},
+			{Name: "worker", Agent: "generic"},
+		},
+		RemoteHosts: []config.RemoteHost{{
+			Name:      "loopback",
+			Transport: config.RemoteTransportLocal,
+			Root:      hostRoot,
+			Agents:    []string{"generic"},
+			Commands:  map[string]string{"generic": cmdPath},
+			MaxSpawns: 1,
+		}},
+	}
+	o := New(s, cfg, repo)
+
+	spawnID, err := o.Spawn(context.Background(), SpawnRequest{
+		ParentTurnID:  131,
+		ParentProfile: "parent",
+		ChildProfile:  "worker",
+		Task:          "remote work",
+		Host:          AnyRemoteHost,
+		Delegation: &config.DelegationConfig{
+			Profiles: []config.DelegationProfile{{Name: "worker"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+	got := o.WaitOne(spawnID)
+	if got.Status != "completed" {
+		t.Fatalf("status = %q, result = %q", got.Status, got.Result)
+	}
+	if !strings.Contains(got.Summary, "remote child done: worker") {
+		t.Fatalf("summary = %q, want relayed child output", got.Summary)
+	}
+	if !strings.Contains(got.Result, "on loopback") {
+		t.Fatalf("result = %q, want remote auto-commit note", got.Result)
+	}
+
+	rec, err := s.GetSpawn(spawnID)
+	if err != nil {
+		t.Fatalf("GetSpawn: %v", err)
+	}
+	if rec.RemoteHost != "loopback" || !strings.HasPrefix(rec.RemoteWorktree, hostRoot) {
+		t.Fatalf("remote host = %q, worktree = %q", rec.RemoteHost, rec.RemoteWorktree)
+	}
+
+	// Both the child's commit and the fallback commit landed on the local
+	// branch, and the child ran in the remote checkout.
+	where := gitOutput(t, repo, "show", rec.Branch+":where.txt")
+	if strings.TrimSpace(where) != rec.RemoteWorktree {
+		t.Fatalf("child ran in %q, want %q", strings.TrimSpace(where), rec.RemoteWorktree)
+	}
+	if got := gitOutput(t, repo, "show", rec.Branch+":uncommitted.txt"); strings.TrimSpace(got) != "draft" {
+		t.Fatalf("uncommitted.txt = %q", got)
+	}
+	if _, err := os.Stat(rec.RemoteWorktree); !os.IsNotExist(err) {
+		t.Fatalf("remote worktree should be removed, stat err = %v", err)
+	}
+	if n := len(o.remoteLoad); n != 0 {
+		t.Fatalf("remoteLoad = %v, want empty", o.remoteLoad)
+	}
+}
+
+func TestAcquireRemoteHost(t *testing.T) {
+	cfg := &config.GlobalConfig{RemoteHosts: []config.RemoteHost{
+		{Name: "a", Address: "dev@a", Agents: []string{"claude"}, MaxSpawns: 1},
+		{Name: "b", Address: "dev@b", Agents: []string{"claude", "codex"}},
+	}}
+	o := New(nil, cfg, t.TempDir())
+
+	if h, err := o.acquireRemoteHost("", "claude"); h != nil || err != nil {
+		t.Fatalf("empty host = %v, %v; want local", h, err)
+	}
+	if _, err := o.acquireRemoteHost("a", "codex"); err == nil {
+		t.Fatal("host without the agent was accepted")
+	}
+	if _, err := o.acquireRemoteHost("c", "claude"); err == nil {
+		t.Fatal("unknown host was accepted")
+	}
+
+	first, err := o.acquireRemoteHost("any", "claude")
+	if err != nil || first.Name() != "a" {
+		t.Fatalf("first any = %v, %v; want a", first, err)
+	}
+	if _, err := o.acquireRemoteHost("a", "claude"); err == nil {
+		t.Fatal("host over max_spawns was accepted")
+	}
+	second, err := o.acquireRemoteHost("any", "claude")
+	if err != nil || second.Name() != "b" {
+		t.Fatalf("second any = %v, %v; want b", second, err)
+	}
+	o.releaseRemoteHost(first)
+	if h, err := o.acquireRemoteHost("any", "claude"); err != nil || h.Name() != "a" {
+		t.Fatalf("after release = %v, %v; want a", h, err)
+	}
+}
@@ -0,0 +1,76 @@
+package orchestrator
+
+import (
+	"context"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/store"
+	"github.com/agusx1211/adaf/internal/usage"
+)
+
+// usageFailoverReason marks a spawn routed to a fallback profile by the
+// loop's usage gate rather than by a failed turn.
+const usageFailoverReason = "usage"
+
+// gateSpawnProfile applies gate before a spawn launches prof. It returns the
+// profile to launch and whether it was routed to a fallback. A non-nil Block
+// means the spawn must wait for prof's provider before starting.
+func (o *Orchestrator) gateSpawnProfile(ctx context.Context, gate *config.UsageGate, prof *config.Profile) (*config.Profile, bool, *usage.Block) {
+	if gate == nil || o.usage == nil {
+		return prof, false, nil
+	}
+	threshold := gate.EffectiveThreshold()
+	block, err := o.usage.Check(ctx, prof.Agent, threshold)
+	if err != nil {
+		debug.LogKV("orch", "usage check failed; spawning anyway", "profile", prof.Name, "error", err)
+		return prof, false, nil
+	}
+	if block == nil {
+		return prof, false, nil
+	}
+	if gate.EffectiveAction() == config.UsageGateActionRoute && o.globalCfg != nil {
+		if alt := usage.FirstAvailable(ctx, o.usage, o.globalCfg.FallbackProfiles(prof.Name), threshold); alt != nil {
+			debug.LogKV("orch", "usage gate routed spawn to fallback profile",
+				"profile", prof.Name,
+				"routed_to", alt.Name,
+				"block", block.String(),
+			)
+			return alt, true, nil
+		}
+	}
+	return prof, false, block
+}
+
+// waitForSpawnUsage holds a spawn until prof's provider has headroom again,
+// recording the pause on the spawn record while it lasts.
+func (o *Orchestrator) waitForSpawnUsage(ctx context.Context, spawnID int, prof *config.Profile, gate *config.UsageGate, block *usage.Block) error {
+	started := time.Now().UTC()
+	setPause := func(pause *store.UsagePause) {
+		if err := o.withSpawnRecordLock(spawnID, func(stored *store.SpawnRecord) error {
+			stored.UsagePause = pause
+			return nil
+		}); err != nil {
+			debug.LogKV("orch", "failed to persist spawn usage pause", "spawn_id", spawnID, "error", err)
+		}
+	}
+	err := usage.WaitForHeadroom(ctx, o.usage, prof.Agent, gate, block, func(b *usage.Block, until time.Time) {
+		debug.LogKV("orch", "usage gate paused spawn",
+			"spawn_id", spawnID,
+			"profile", prof.Name,
+			"block", b.String(),
+			"until", until,
+		)
+		setPause(&store.UsagePause{
+			Profile:        prof.Name,
+			Provider:       string(b.Provider),
+			Limit:          b.Limit.Name,
+			UtilizationPct: b.Limit.UtilizationPct,
+			Until:          until.UTC(),
+			StartedAt:      started,
+		})
+	})
+	setPause(nil)
+	return err
+}
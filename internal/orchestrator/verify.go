@@ -0,0 +1,234 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/isolation"
+	"github.com/agusx1211/adaf/internal/loop"
+	"github.com/agusx1211/adaf/internal/sandbox"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+const (
+	// acceptanceCommandTimeout bounds a single acceptance command.
+	acceptanceCommandTimeout = 15 * time.Minute
+	// acceptanceOutputLimit is how much command output (tail) is kept per check.
+	acceptanceOutputLimit = 4 * 1024
+)
+
+// resolveAcceptanceChecks combines the checks given on the request with
+// those attached to the assigned issues.
+func (o *Orchestrator) resolveAcceptanceChecks(req SpawnRequest) ([]store.AcceptanceCheck, error) {
+	lists := [][]store.AcceptanceCheck{req.Checks}
+	for _, id := range req.IssueIDs {
+		issue, err := o.store.GetIssue(id)
+		if err != nil || issue == nil {
+			continue
+		}
+		lists = append(lists, issue.Checks)
+	}
+	checks := store.MergeAcceptanceChecks(lists...)
+	for _, c := range checks {
+		if err := c.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return checks, nil
+}
+
+// acceptanceRun is what a spawn's acceptance checks are run with: the checks
+// captured when the spawn was created and the sandbox or isolation backend
+// the child ran in. Commands run with the child's confinement; the spawn
+// record is writable from inside that confinement, so its copy of the checks
+// is not trusted.
+type acceptanceRun struct {
+	checks    []store.AcceptanceCheck
+	retries   int
+	sandbox   *sandbox.Spec
+	isolation isolation.Wrapper
+}
+
+// verifySpawnWork runs the spawn's acceptance checks after a clean child exit.
+// When checks fail and retries remain, the child is resumed with the failure
+// output and the checks run again. It returns the final check results, the
+// last auto-commit note, and the error of the last child run.
+func (o *Orchestrator) verifySpawnWork(ctx context.Context, spawnID int, l *loop.Loop, run acceptanceRun, runErr error) ([]store.AcceptanceResult, string, error) {
+	var (
+		results    []store.AcceptanceResult
+		commitNote string
+		attempts   int
+	)
+	if len(run.checks) == 0 {
+		return results, commitNote, runErr
+	}
+	for {
+		if runErr != nil || l.LastResult == nil || l.LastResult.ExitCode != 0 {
+			return results, commitNote, runErr
+		}
+		rec, err := o.store.GetSpawn(spawnID)
+		if err != nil || rec == nil {
+			return results, commitNote, runErr
+		}
+
+		// Commit first so diff-size checks and the merged branch see the
+		// same tree the commands were run against.
+		if note, err := o.autoCommitSpawnWork(rec); err != nil {
+			debug.LogKV("orch", "auto-commit before verification failed", "spawn_id", spawnID, "error", err)
+		} else if note != "" {
+			commitNote = note
+		}
+
+		workDir := rec.WorktreePath
+		if workDir == "" {
+			workDir = o.repoRoot
+		}
+		results = o.runAcceptanceChecks(ctx, workDir, rec.BaseCommit, run)
+		passed := store.AcceptancePassed(results)
+		retry := !passed && attempts < run.retries && ctx.Err() == nil
+		debug.LogKV("orch", "acceptance checks evaluated",
+			"spawn_id", spawnID,
+			"checks", len(results),
+			"passed", passed,
+			"attempt", attempts,
+			"retry", retry,
+		)
+		if err := o.withSpawnRecordLock(spawnID, func(stored *store.SpawnRecord) error {
+			stored.CheckResults = results
+			if retry {
+				stored.VerifyAttempts = attempts + 1
+			}
+			return nil
+		}); err != nil {
+			debug.LogKV("orch", "failed to persist acceptance results", "spawn_id", spawnID, "error", err)
+		}
+		if !retry {
+			return results, commitNote, runErr
+		}
+
+		attempts++
+		l.ContinueWith(verificationFeedback(results, attempts, run.retries))
+		runErr = l.Run(ctx)
+	}
+}
+
+// runAcceptanceChecks evaluates run's checks in workDir. Every check runs
+// even after a failure so the child sees the full picture.
+func (o *Orchestrator) runAcceptanceChecks(ctx context.Context, workDir, baseCommit string, run acceptanceRun) []store.AcceptanceResult {
+	results := make([]store.AcceptanceResult, 0, len(run.checks))
+	for _, c := range run.checks {
+		res := store.AcceptanceResult{Check: c}
+		switch {
+		case strings.TrimSpace(c.Command) != "":
+			res.Passed, res.Output = runAcceptanceCommand(ctx, workDir, c.Command, run)
+		case strings.TrimSpace(c.File) != "":
+			if _, err := os.Stat(filepath.Join(workDir, c.File)); err != nil {
+				res.Output = fmt.Sprintf("required file %s is missing", c.File)
+			} else {
+				res.Passed = true
+			}
+		case c.MaxDiffLines > 0:
+			if baseCommit == "" {
+				res.Passed = true
+				res.Output = "no base commit recorded; diff size not checked"
+				break
+			}
+			lines, err := o.worktrees.DiffLineCount(ctx, workDir, baseCommit)
+			if err != nil {
+				res.Output = fmt.Sprintf("measuring diff: %v", err)
+				break
+			}
+			res.Passed = lines <= c.MaxDiffLines
+			res.Output = fmt.Sprintf("%d changed lines (limit %d)", lines, c.MaxDiffLines)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+func runAcceptanceCommand(ctx context.Context, workDir, command string, run acceptanceRun) (bool, string) {
+	ctx, cancel := context.WithTimeout(ctx, acceptanceCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = workDir
+	cmd.WaitDelay = 5 * time.Second
+	if run.isolation != nil {
+		if err := run.isolation.Wrap(cmd, nil); err != nil {
+			return false, err.Error()
+		}
+	} else {
+		cleanup, err := sandbox.Wrap(cmd, run.sandbox)
+		defer cleanup()
+		if err != nil {
+			return false, err.Error()
+		}
+	}
+	out, err := cmd.CombinedOutput()
+	output := tailOutput(string(out), acceptanceOutputLimit)
+	if err == nil {
+		return true, output
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		output = appendSpawnSummary(output, fmt.Sprintf("timed out after %s", acceptanceCommandTimeout))
+	case errors.As(err, &exitErr):
+		output = appendSpawnSummary(output, fmt.Sprintf("exit status %d", exitErr.ExitCode()))
+	default:
+		output = appendSpawnSummary(output, err.Error())
+	}
+	return false, output
+}
+
+func tailOutput(s string, limit int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= limit {
+		return s
+	}
+	return "...\n" + s[len(s)-limit:]
+}
+
+// verificationFailureReason is the one-line spawn result for failed checks.
+func verificationFailureReason(results []store.AcceptanceResult) string {
+	var failed []string
+	for _, r := range results {
+		if !r.Passed {
+			failed = append(failed, r.Check.Describe())
+		}
+	}
+	return "failed verification: " + strings.Join(failed, ", ")
+}
+
+// verificationReport renders check results for the parent's summary.
+func verificationReport(results []store.AcceptanceResult) string {
+	var b strings.Builder
+	b.WriteString("Acceptance checks:\n")
+	for _, r := range results {
+		mark := "PASS"
+		if !r.Passed {
+			mark = "FAIL"
+		}
+		fmt.Fprintf(&b, "- [%s] %s\n", mark, r.Check.Describe())
+		if !r.Passed && r.Output != "" {
+			b.WriteString("```\n" + r.Output + "\n```\n")
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// verificationFeedback is the message used to resume a child whose work
+// failed its acceptance checks.
+func verificationFeedback(results []store.AcceptanceResult, attempt, maxAttempts int) string {
+	return fmt.Sprintf(
+		"Your work did not pass its acceptance checks (retry %d of %d). Fix the failures below, commit, and finish again.\n\n%s",
+		attempt, maxAttempts, verificationReport(results),
+	)
+}
@@ -0,0 +1,251 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func TestRunAcceptanceChecks(t *testing.T) {
+	repo := initGitRepo(t)
+	s := newTestStore(t, repo)
+	o := New(s, nil, repo)
+
+	base := strings.TrimSpace(gitOutput(t, repo, "rev-parse", "HEAD"))
+	if err := os.WriteFile(filepath.Join(repo, "main.txt"), []byte("initial\nmore\nlines\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	results := o.runAcceptanceChecks(context.Background(), repo, base, acceptanceRun{checks: []store.AcceptanceCheck{
+		{Command: "true"},
+		{Command: "echo boom; exit 3"},
+		{File: "main.txt"},
+		{File: "missing.txt"},
+		{MaxDiffLines: 5},
+		{MaxDiffLines: 1},
+	}})
+	want := []bool{true, false, true, false, true, false}
+	if len(results) != len(want) {
+		t.Fatalf("results = %d, want %d", len(results), len(want))
+	}
+	for i, r := range results {
+		if r.Passed != want[i] {
+			t.Errorf("check %d (%s): passed = %v, want %v (output %q)", i, r.Check.Describe(), r.Passed, want[i], r.Output)
+		}
+	}
+	if !strings.Contains(results[1].Output, "boom") || !strings.Contains(results[1].Output, "exit status 3") {
+		t.Errorf("command output = %q, want output and exit status", results[1].Output)
+	}
+	if store.AcceptancePassed(results) {
+		t.Fatal("AcceptancePassed = true, want false")
+	}
+
+	reason := verificationFailureReason(results)
+	if !strings.Contains(reason, "echo boom") || !strings.Contains(reason, "missing.txt") || strings.Contains(reason, "`true`") {
+		t.Errorf("failure reason = %q", reason)
+	}
+}
+
+// markingWrapper stands in for a container backend: it runs the command on
+// the host with a marker in the environment.
+type markingWrapper struct{ calls int }
+
+func (w *markingWrapper) Wrap(cmd *exec.Cmd, env map[string]string) error {
+	w.calls++
+	cmd.Env = append(os.Environ(), "IN_ISOLATION=yes")
+	return nil
+}
+
+func TestRunAcceptanceChecksUsesIsolationWrapper(t *testing.T) {
+	repo := initGitRepo(t)
+	s := newTestStore(t, repo)
+	o := New(s, nil, repo)
+
+	w := &markingWrapper{}
+	results := o.runAcceptanceChecks(context.Background(), repo, "", acceptanceRun{
+		checks:    []store.AcceptanceCheck{{Command: `test "$IN_ISOLATION" = yes`}},
+		isolation: w,
+	})
+	if len(results) != 1 || !results[0].Passed {
+		t.Fatalf("results = %+v, want the command to pass inside the wrapper", results)
+	}
+	if w.calls != 1 {
+		t.Fatalf("wrapper calls = %d, want 1", w.calls)
+	}
+}
+
+func TestResolveAcceptanceChecksIncludesIssueChecks(t *testing.T) {
+	repo := initGitRepo(t)
+	s := newTestStore(t, repo)
+	o := New(s, nil, repo)
+
+	issue := &store.Issue{
+		Title:  "needs tests",
+		Status: store.IssueStatusOpen,
+		Checks: []store.AcceptanceCheck{{Command: "go test ./..."}, {MaxDiffLines: 200}},
+	}
+	if err := s.CreateIssue(issue); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+
+	checks, err := o.resolveAcceptanceChecks(SpawnRequest{
+		IssueIDs: []int{issue.ID},
+		Checks:   []store.AcceptanceCheck{{Command: "go test ./..."}, {MaxDiffLines: 50}},
+	})
+	if err != nil {
+		t.Fatalf("resolveAcceptanceChecks: %v", err)
+	}
+	want := []store.AcceptanceCheck{{Command: "go test ./..."}, {MaxDiffLines: 50}}
+	if len(checks) != len(want) {
+		t.Fatalf("checks = %+v, want %+v", checks, want)
+	}
+	for i := range want {
+		if checks[i] != want[i] {
+			t.Fatalf("checks = %+v, want %+v", checks, want)
+		}
+	}
+
+	if _, err := o.resolveAcceptanceChecks(SpawnRequest{Checks: []store.AcceptanceCheck{{File: "/etc/passwd"}}}); err == nil {
+		t.Fatal("expected absolute file check to be rejected")
+	}
+}
+
+func TestSpawn_FailedChecksResumeChildUntilRetriesRunOut(t *testing.T) {
+	tests := []struct {
+		name         string
+		retries      int
+		wantStatus   string
+		wantRuns     string
+		wantAttempts int
+	}{
+		// The resumed run creates the required file, so verification passes.
+		{name: "retry fixes the work", retries: 1, wantStatus: store.SpawnStatusCompleted, wantRuns: "2", wantAttempts: 1},
+		{name: "no retries left", retries: 0, wantStatus: store.SpawnStatusFailedVerification, wantRuns: "1", wantAttempts: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := initGitRepo(t)
+			s := newTestStore(t, repo)
+
+			counter := filepath.Join(t.TempDir(), "runs")
+			cmdPath := filepath.Join(t.TempDir(), "generic-verify.sh")
+			script := "#!/usr/bin/env bash\n" +
+				"n=$(( $(cat " + counter + " 2>/dev/null || echo 0) + 1 ))\n" +
+				"echo $n > " + counter + "\n" +
+				"if [ $n -ge 2 ]; then echo ok > fixed.txt; fi\n" +
+				"echo done\n"
+			if err := os.WriteFile(cmdPath, []byte(script), 0755); err != nil {
+				t.Fatalf("WriteFile(%q): %v", cmdPath, err)
+			}
+			if err := agent.SaveAgentsConfig(&agent.AgentsConfig{
+				Agents: map[string]agent.AgentRecord{
+					"generic": {Name: "generic", Path: cmdPath},
+				},
+			}); err != nil {
+				t.Fatalf("SaveAgentsConfig(): %v", err)
+			}
+
+			cfg := &config.GlobalConfig{
+				Profiles: []config.Profile{
+					{Name: "parent", Agent: "generic"},
+					{Name: "worker", Agent: "generic"},
+				},
+			}
+			o := New(s, cfg, repo)
+
+			spawnID, err := o.Spawn(context.Background(), SpawnRequest{
+				ParentTurnID:  91,
+				ParentProfile: "parent",
+				ChildProfile:  "worker",
+				Task:          "write fixed.txt",
+				Delegation: &config.DelegationConfig{
+					Profiles: []config.DelegationProfile{{Name: "worker"}},
+				},
+				Checks:        []store.AcceptanceCheck{{File: "fixed.txt"}},
+				VerifyRetries: tt.retries,
+			})
+			if err != nil {
+				t.Fatalf("Spawn() error = %v", err)
+			}
+
+			got := o.WaitOne(spawnID)
+			if got.Status != tt.wantStatus {
+				t.Fatalf("status = %q, want %q (result %q)", got.Status, tt.wantStatus, got.Result)
+			}
+			runs, _ := os.ReadFile(counter)
+			if strings.TrimSpace(string(runs)) != tt.wantRuns {
+				t.Fatalf("child runs = %q, want %s", strings.TrimSpace(string(runs)), tt.wantRuns)
+			}
+
+			rec, err := s.GetSpawn(spawnID)
+			if err != nil {
+				t.Fatalf("GetSpawn(%d): %v", spawnID, err)
+			}
+			if rec.VerifyAttempts != tt.wantAttempts {
+				t.Fatalf("verify attempts = %d, want %d", rec.VerifyAttempts, tt.wantAttempts)
+			}
+			if len(rec.CheckResults) != 1 || rec.CheckResults[0].Passed != (tt.wantStatus == store.SpawnStatusCompleted) {
+				t.Fatalf("check results = %+v", rec.CheckResults)
+			}
+			if tt.wantStatus == store.SpawnStatusFailedVerification && !strings.Contains(got.Result, "fixed.txt") {
+				t.Fatalf("result = %q, want failing check named", got.Result)
+			}
+		})
+	}
+}
+
+func TestSpawn_ChecksIgnoreSpawnRecordEditsByChild(t *testing.T) {
+	repo := initGitRepo(t)
+	s := newTestStore(t, repo)
+
+	// The child drops the checks from its own spawn record; verification
+	// must still run the checks captured at spawn time.
+	cmdPath := filepath.Join(t.TempDir(), "generic-tamper.sh")
+	script := "#!/usr/bin/env bash\n" +
+		"sed -i 's/\"checks\":/\"ignored\":/' " + filepath.Join(s.Root(), "local", "spawns") + "/$ADAF_SPAWN_ID.json\n" +
+		"echo done\n"
+	if err := os.WriteFile(cmdPath, []byte(script), 0755); err != nil {
+		t.Fatalf("WriteFile(%q): %v", cmdPath, err)
+	}
+	if err := agent.SaveAgentsConfig(&agent.AgentsConfig{
+		Agents: map[string]agent.AgentRecord{
+			"generic": {Name: "generic", Path: cmdPath},
+		},
+	}); err != nil {
+		t.Fatalf("SaveAgentsConfig(): %v", err)
+	}
+
+	cfg := &config.GlobalConfig{
+		Profiles: []config.Profile{
+			{Name: "parent", Agent: "generic"},
+			{Name: "worker", Agent: "generic"},
+		},
+	}
+	o := New(s, cfg, repo)
+
+	spawnID, err := o.Spawn(context.Background(), SpawnRequest{
+		ParentTurnID:  92,
+		ParentProfile: "parent",
+		ChildProfile:  "worker",
+		Task:          "write fixed.txt",
+		Delegation: &config.DelegationConfig{
+			Profiles: []config.DelegationProfile{{Name: "worker"}},
+		},
+		Checks: []store.AcceptanceCheck{{File: "fixed.txt"}},
+	})
+	if err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+
+	got := o.WaitOne(spawnID)
+	if got.Status != store.SpawnStatusFailedVerification {
+		t.Fatalf("status = %q, want %q (result %q)", got.Status, store.SpawnStatusFailedVerification, got.Result)
+	}
+}
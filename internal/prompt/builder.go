@@ -3,8 +3,10 @@ package prompt
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/agusx1211/adaf/internal/config"
 	"github.com/agusx1211/adaf/internal/store"
@@ -32,17 +34,24 @@ func buildSubAgentPrompt(opts BuildOpts) (string, error) {
 
 	if opts.ReadOnly {
 		b.WriteString("You are in READ-ONLY mode. Do NOT create, modify, or delete any files. Only read and analyze.\n")
-		b.WriteString("Wiki is a persistent knowledge base shared across all agents. Browse with `adaf wiki list` or `adaf wiki search \"<term>\"`.\n")
 	} else {
 		b.WriteString("Commit your work when you finish.\n")
-		b.WriteString("Wiki is a persistent knowledge base shared across all agents (not a session log or status dump). Browse with `adaf wiki list` or `adaf wiki search \"<term>\"`. Update with `adaf wiki update <id> --content \"...\"` only when durable knowledge changes.\n")
 	}
 
-	b.WriteString("If you need to communicate with your parent agent use: adaf parent-ask \"question\"\n")
-	b.WriteString("Do NOT manage turn logs with `adaf turn ...`; your parent agent owns turn handoff publication.\n")
+	if opts.NoAdafCLI {
+		b.WriteString("The adaf CLI is not available where you run: you cannot ask your parent questions, update issues, or spawn sub-agents. Work from the task as given and put everything your parent needs (results, open questions, issue progress) in your final message.\n")
+	} else {
+		if opts.ReadOnly {
+			b.WriteString("Wiki is a persistent knowledge base shared across all agents. Browse with `adaf wiki list` or `adaf wiki search \"<term>\"`.\n")
+		} else {
+			b.WriteString("Wiki is a persistent knowledge base shared across all agents (not a session log or status dump). Browse with `adaf wiki list` or `adaf wiki search \"<term>\"`. Update with `adaf wiki update <id> --content \"...\"` only when durable knowledge changes.\n")
+		}
+		b.WriteString("If you need to communicate with your parent agent use: adaf parent-ask \"question\"\n")
+		b.WriteString("Do NOT manage turn logs with `adaf turn ...`; your parent agent owns turn handoff publication.\n")
+	}
 
-	if opts.Delegation != nil && len(opts.Delegation.Profiles) > 0 {
-		b.WriteString(delegationSection(opts.Delegation, opts.GlobalCfg, nil, ""))
+	if !opts.NoAdafCLI && opts.Delegation != nil && len(opts.Delegation.Profiles) > 0 {
+		b.WriteString(delegationSection(opts.Delegation, opts.GlobalCfg, opts.Store, nil, ""))
 	}
 
 	b.WriteString("Your task below is your primary directive.\n")
@@ -60,7 +69,11 @@ func buildSubAgentPrompt(opts BuildOpts) (string, error) {
 			}
 			fmt.Fprintf(&b, "- #%d [%s] %s: %s\n", iss.ID, iss.Priority, iss.Title, iss.Description)
 		}
-		b.WriteString("\nUse `adaf issue show <id>` for full details, `adaf issue move <id> --status ongoing` to track progress, and `adaf issue comment <id> --body \"...\"` to leave updates.\n\n")
+		if opts.NoAdafCLI {
+			b.WriteString("\n")
+		} else {
+			b.WriteString("\nUse `adaf issue show <id>` for full details, `adaf issue move <id> --status ongoing` to track progress, and `adaf issue comment <id> --body \"...\"` to leave updates.\n\n")
+		}
 	}
 
 	b.WriteString(opts.Task)
@@ -83,6 +96,7 @@ type LoopPromptContext struct {
 	CanPushover       bool
 	Messages          []store.LoopMessage // unseen messages from other steps
 	RunID             int
+	Outcomes          []string // outcome tokens the step's transitions route on
 }
 
 // BuildOpts configures prompt generation.
@@ -123,6 +137,11 @@ type BuildOpts struct {
 	// IssueIDs are specific issues assigned to this sub-agent by the parent.
 	IssueIDs []int
 
+	// NoAdafCLI marks an agent that cannot run adaf commands, such as a
+	// child on a remote host or in an isolation container. Its prompt leaves
+	// out adaf instructions.
+	NoAdafCLI bool
+
 	// LoopContext provides loop-specific context (nil if not in a loop).
 	LoopContext *LoopPromptContext
 
@@ -299,7 +318,7 @@ func buildSkillsPrompt(opts BuildOpts) (string, error) {
 		if opts.LoopContext != nil {
 			resourcePriority = config.EffectiveResourcePriority(opts.LoopContext.ResourcePriority)
 		}
-		b.WriteString(delegationSection(opts.Delegation, opts.GlobalCfg, runningSpawns, resourcePriority))
+		b.WriteString(delegationSection(opts.Delegation, opts.GlobalCfg, opts.Store, runningSpawns, resourcePriority))
 	}
 
 	// Runtime data (always, when present): wait results, handoffs.
@@ -509,6 +528,10 @@ func renderLoopContext(opts BuildOpts) string {
 	if lc.CanCallSupervisor {
 		b.WriteString("If you need supervisor direction or have no actionable work left, escalate with: `adaf loop call-supervisor \"status + concrete ask\"`\n\n")
 	}
+	if len(lc.Outcomes) > 0 {
+		fmt.Fprintf(&b, "Before ending your turn, report this step's outcome with `adaf loop outcome <token>` using one of: %s. The loop picks the next step from it.\n\n",
+			"`"+strings.Join(lc.Outcomes, "`, `")+"`")
+	}
 	return b.String()
 }
 
@@ -566,10 +589,20 @@ func renderWaitResults(results []WaitResultInfo) string {
 
 // renderHandoffs formats the handoff section.
 func renderHandoffs(handoffs []store.HandoffInfo) string {
-	if len(handoffs) == 0 {
-		return ""
+	var spawns, members []store.HandoffInfo
+	for _, h := range handoffs {
+		if h.SpawnID > 0 {
+			spawns = append(spawns, h)
+		} else {
+			members = append(members, h)
+		}
 	}
 	var b strings.Builder
+	b.WriteString(renderGroupHandoffs(members))
+	if len(spawns) == 0 {
+		return b.String()
+	}
+	handoffs = spawns
 	b.WriteString("## Inherited Running Agents (Handoff)\n\n")
 	b.WriteString("The previous step handed off these running sub-agents to you:\n\n")
 	for _, h := range handoffs {
@@ -589,6 +622,28 @@ func renderHandoffs(handoffs []store.HandoffInfo) string {
 	return b.String()
 }
 
+// renderGroupHandoffs formats the results of a parallel step group.
+func renderGroupHandoffs(members []store.HandoffInfo) string {
+	if len(members) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("## Parallel Step Results\n\n")
+	b.WriteString("The previous step ran these agents in parallel, each on its own branch:\n\n")
+	for _, h := range members {
+		fmt.Fprintf(&b, "- %s (turn #%d): %s", h.Profile, h.TurnID, h.Status)
+		if h.Branch != "" {
+			fmt.Fprintf(&b, ", Branch: %s", h.Branch)
+		}
+		b.WriteString("\n")
+		if summary := strings.TrimSpace(h.Summary); summary != "" {
+			fmt.Fprintf(&b, "  Summary: %s\n", summary)
+		}
+	}
+	b.WriteString("\nReview each branch (`git diff HEAD...<branch>`) and merge what should land (`git merge <branch>`).\n\n")
+	return b.String()
+}
+
 // renderContextSection formats a lightweight project context section for the skills-driven path.
 // Agents discover plan details, issues, and session history via CLI commands.
 func renderContextSection(opts BuildOpts, project *store.ProjectConfig, plan *store.Plan) string {
@@ -643,12 +698,23 @@ func renderObjective(opts BuildOpts, project *store.ProjectConfig, plan *store.P
 	if opts.Task != "" {
 		b.WriteString(opts.Task + "\n\n")
 	} else {
+		var milestone *store.PlanPhase
+		if plan != nil && len(plan.Phases) > 0 && opts.Store != nil {
+			allIssues, _ := opts.Store.ListIssues()
+			progress := store.ComputePlanProgress(plan, allIssues)
+			if milestone = plan.Phase(progress.CurrentPhaseID); milestone != nil {
+				b.WriteString(renderPlanMilestone(plan, milestone, progress, allIssues))
+			}
+		}
 		if effectivePosition == config.PositionManager || effectivePosition == config.PositionSupervisor {
 			if plan != nil && strings.TrimSpace(plan.ID) != "" {
 				fmt.Fprintf(&b, "Use `adaf plan show %s` to inspect the active plan goals, rationale, and scope.\n", plan.ID)
 			} else {
 				b.WriteString("Use `adaf plan` to inspect active plan goals, rationale, and scope.\n")
 			}
+			if milestone != nil {
+				fmt.Fprintf(&b, "Steer work toward the current milestone; once its acceptance holds, mark it with `adaf plan phase update %d --plan %s --status done`.\n", milestone.ID, plan.ID)
+			}
 			b.WriteString("Use `adaf issues`, `adaf wiki list`, and `adaf log` to validate progress and then publish concrete guidance for the next step.\n")
 			b.WriteString("If wiki knowledge (architectural decisions, patterns, gotchas) is outdated, update with `adaf wiki update <id> --content \"...\"`.\n\n")
 			return b.String()
@@ -664,13 +730,28 @@ func renderObjective(opts BuildOpts, project *store.ProjectConfig, plan *store.P
 		}
 		openIssues := make([]store.Issue, 0, len(issues))
 		issuesByID := make(map[int]store.Issue, len(issues))
+		claimedByOthers := 0
+		holder := promptClaimHolder(opts)
+		now := time.Now()
 		for _, iss := range issues {
 			issuesByID[iss.ID] = iss
-			if store.IsOpenIssueStatus(iss.Status) {
-				openIssues = append(openIssues, iss)
+			if !store.IsOpenIssueStatus(iss.Status) {
+				continue
 			}
+			// Issues leased to other agents are already being worked on.
+			if claim := iss.ActiveClaim(now); claim != nil && !claim.SameHolder(holder) {
+				claimedByOthers++
+				continue
+			}
+			openIssues = append(openIssues, iss)
 		}
 		sortIssueQueue(openIssues)
+		if milestone != nil {
+			// Issues linked to the current milestone go first.
+			sort.SliceStable(openIssues, func(i, j int) bool {
+				return slices.Contains(milestone.IssueIDs, openIssues[i].ID) && !slices.Contains(milestone.IssueIDs, openIssues[j].ID)
+			})
+		}
 
 		type blockedIssue struct {
 			issue   store.Issue
@@ -697,6 +778,9 @@ func renderObjective(opts BuildOpts, project *store.ProjectConfig, plan *store.P
 			if strings.TrimSpace(currentIssue.Description) != "" {
 				b.WriteString(currentIssue.Description + "\n\n")
 			}
+			if roleCanWrite && !opts.NoAdafCLI {
+				fmt.Fprintf(&b, "Claim it before starting with `adaf issue claim %d`; if someone else holds it, take the next ready issue.\n\n", currentIssue.ID)
+			}
 
 			b.WriteString("## Ready Issues\n")
 			limit := 3
@@ -707,6 +791,9 @@ func renderObjective(opts BuildOpts, project *store.ProjectConfig, plan *store.P
 				iss := ready[i]
 				fmt.Fprintf(&b, "- #%d [%s] %s\n", iss.ID, iss.Priority, iss.Title)
 			}
+			if claimedByOthers > 0 {
+				fmt.Fprintf(&b, "(%d issue(s) claimed by other agents are not listed.)\n", claimedByOthers)
+			}
 			b.WriteString("\n")
 
 			if len(blocked) > 0 {
@@ -733,6 +820,8 @@ func renderObjective(opts BuildOpts, project *store.ProjectConfig, plan *store.P
 				fmt.Fprintf(&b, "- #%d waits on %s\n", bi.issue.ID, formatIssueDependencyIDs(bi.waiting))
 			}
 			b.WriteString("\n")
+		} else if claimedByOthers > 0 {
+			b.WriteString("Every open issue is claimed by another agent. Look for gaps, file new issues, or review work in progress.\n\n")
 		} else if plan != nil && plan.Title != "" {
 			b.WriteString("No open issues are tracked for this plan. Look for gaps, file issues, or refine the plan details.\n\n")
 		} else {
@@ -742,6 +831,40 @@ func renderObjective(opts BuildOpts, project *store.ProjectConfig, plan *store.P
 	return b.String()
 }
 
+// renderPlanMilestone formats the current milestone with its acceptance text
+// and the progress of its linked issues.
+func renderPlanMilestone(plan *store.Plan, milestone *store.PlanPhase, progress store.PlanProgress, issues []store.Issue) string {
+	pp := progress.Phase(milestone.ID)
+
+	var b strings.Builder
+	b.WriteString("## Current Milestone\n\n")
+	position := slices.IndexFunc(plan.Phases, func(p store.PlanPhase) bool { return p.ID == milestone.ID }) + 1
+	fmt.Fprintf(&b, "Phase %d of %d: **%s**", position, len(plan.Phases), milestone.Title)
+	if pp != nil && pp.TotalIssues > 0 {
+		fmt.Fprintf(&b, " (%d/%d linked issues closed)", pp.ClosedIssues, pp.TotalIssues)
+	}
+	b.WriteString("\n\n")
+	if acceptance := strings.TrimSpace(milestone.Acceptance); acceptance != "" {
+		b.WriteString("Acceptance: " + acceptance + "\n\n")
+	}
+
+	statusByID := make(map[int]string, len(issues))
+	for _, iss := range issues {
+		statusByID[iss.ID] = iss.Status
+	}
+	var open []string
+	for _, id := range milestone.IssueIDs {
+		if status, ok := statusByID[id]; ok && !store.IsTerminalIssueStatus(status) {
+			open = append(open, fmt.Sprintf("#%d", id))
+		}
+	}
+	if len(open) > 0 {
+		b.WriteString("Open milestone issues: " + strings.Join(open, ", ") + "\n\n")
+	}
+	fmt.Fprintf(&b, "Overall plan progress: %d/%d phases complete.\n\n", progress.CompletedPhases, progress.TotalPhases)
+	return b.String()
+}
+
 func unresolvedIssueDependencies(issue store.Issue, byID map[int]store.Issue) []int {
 	if len(issue.DependsOn) == 0 {
 		return nil
@@ -907,3 +1030,15 @@ func buildStandaloneChatContext(opts BuildOpts) (string, error) {
 func isDelegationActiveSpawnStatus(status string) bool {
 	return !store.IsTerminalSpawnStatus(status)
 }
+
+// promptClaimHolder is the issue lease identity of the agent being prompted.
+// Loop agents hold leases per step, so claims from earlier turns of the same
+// step still count as their own.
+func promptClaimHolder(opts BuildOpts) store.IssueClaim {
+	holder := store.IssueClaim{TurnID: opts.CurrentTurnID}
+	if lc := opts.LoopContext; lc != nil && lc.RunID > 0 {
+		holder.LoopRunID = lc.RunID
+		holder.LoopStep = lc.StepIndex
+	}
+	return holder
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/agusx1211/adaf/internal/config"
 	"github.com/agusx1211/adaf/internal/store"
@@ -133,6 +134,38 @@ func TestBuild_SubAgentIncludesParentCommunicationCommands(t *testing.T) {
 	}
 }
 
+func TestBuild_SubAgentWithoutAdafCLIOmitsAdafCommands(t *testing.T) {
+	s, project := initPromptTestStore(t)
+	issue := &store.Issue{Title: "Port parser", Status: "open", Priority: "high", Description: "Move it to the new AST"}
+	if err := s.CreateIssue(issue); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+
+	got, err := Build(BuildOpts{
+		Store:        s,
+		Project:      project,
+		Profile:      &config.Profile{Name: "dev", Agent: "codex"},
+		ParentTurnID: 100,
+		Task:         "Port the parser",
+		IssueIDs:     []int{issue.ID},
+		NoAdafCLI:    true,
+		Delegation: &config.DelegationConfig{
+			Profiles: []config.DelegationProfile{{Name: "dev"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if strings.Contains(got, "`adaf") || strings.Contains(got, "adaf parent-ask") {
+		t.Fatalf("prompt without the adaf CLI should not mention adaf commands\nprompt:\n%s", got)
+	}
+	for _, want := range []string{"Port parser", "adaf CLI is not available", "final message"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("prompt missing %q\nprompt:\n%s", want, got)
+		}
+	}
+}
+
 func TestBuild_MainAgentDoesNotIncludeParentCommunicationCommands(t *testing.T) {
 	s, project := initPromptTestStore(t)
 
@@ -856,6 +889,144 @@ func TestBuild_WorkerObjectiveUsesReadyIssues(t *testing.T) {
 	}
 }
 
+func TestBuild_WorkerObjectivePointsAtCurrentMilestone(t *testing.T) {
+	s, project := initPromptTestStore(t)
+	var issueIDs []int
+	for _, title := range []string{"Done groundwork", "Urgent side quest", "Milestone work"} {
+		issue := &store.Issue{PlanID: "main", Title: title, Status: "open", Priority: "medium"}
+		if title == "Urgent side quest" {
+			issue.Priority = "critical"
+		}
+		if err := s.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue(%s): %v", title, err)
+		}
+		issueIDs = append(issueIDs, issue.ID)
+	}
+	done, _ := s.GetIssue(issueIDs[0])
+	done.Status = "closed"
+	if err := s.UpdateIssue(done); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	if err := s.CreatePlan(&store.Plan{
+		ID:          "main",
+		Title:       "Main Plan",
+		Description: "LONG PLAN DESCRIPTION",
+		Status:      "active",
+		Phases: []store.PlanPhase{
+			{ID: 1, Title: "Groundwork", Status: store.PlanPhasePending, IssueIDs: []int{issueIDs[0]}},
+			{ID: 2, Title: "Ship it", Acceptance: "the feature works end to end", IssueIDs: []int{issueIDs[0], issueIDs[2]}},
+		},
+	}); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+	if err := s.SetActivePlan("main"); err != nil {
+		t.Fatalf("SetActivePlan: %v", err)
+	}
+
+	got, err := Build(BuildOpts{
+		Store:   s,
+		Project: project,
+		Profile: &config.Profile{Name: "dev", Agent: "codex"},
+		Skills:  []string{},
+	})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	for _, want := range []string{
+		"## Current Milestone",
+		"Phase 2 of 2: **Ship it** (1/2 linked issues closed)",
+		"Acceptance: the feature works end to end",
+		fmt.Sprintf("Open milestone issues: #%d", issueIDs[2]),
+		fmt.Sprintf("work on issue **#%d: Milestone work**", issueIDs[2]),
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("prompt missing %q\nprompt:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "LONG PLAN DESCRIPTION") {
+		t.Fatalf("prompt should not dump the plan description\nprompt:\n%s", got)
+	}
+
+	got, err = Build(BuildOpts{
+		Store:    s,
+		Project:  project,
+		Profile:  &config.Profile{Name: "lead", Agent: "codex"},
+		Position: config.PositionManager,
+		Skills:   []string{},
+	})
+	if err != nil {
+		t.Fatalf("Build(manager): %v", err)
+	}
+	if want := "`adaf plan phase update 2 --plan main --status done`"; !strings.Contains(got, want) {
+		t.Fatalf("manager prompt missing %q\nprompt:\n%s", want, got)
+	}
+}
+
+func TestBuild_WorkerObjectiveHidesIssuesClaimedByOthers(t *testing.T) {
+	s, project := initPromptTestStore(t)
+	for _, issue := range []*store.Issue{
+		{Title: "Taken by a sibling", Status: "open", Priority: "critical"},
+		{Title: "Still free", Status: "open", Priority: "low"},
+	} {
+		if err := s.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+	}
+	if _, err := s.ClaimIssue(1, store.IssueClaim{SpawnID: 9}, time.Hour); err != nil {
+		t.Fatalf("ClaimIssue: %v", err)
+	}
+
+	got, err := Build(BuildOpts{
+		Store:   s,
+		Project: project,
+		Profile: &config.Profile{Name: "dev", Agent: "codex"},
+		Skills:  []string{},
+	})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if strings.Contains(got, "Taken by a sibling") {
+		t.Fatalf("prompt should hide the claimed issue\nprompt:\n%s", got)
+	}
+	for _, want := range []string{"work on issue **#2: Still free**", "adaf issue claim 2", "1 issue(s) claimed by other agents"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("prompt missing %q\nprompt:\n%s", want, got)
+		}
+	}
+}
+
+func TestBuild_LoopObjectiveKeepsStepsOwnClaim(t *testing.T) {
+	s, project := initPromptTestStore(t)
+	if err := s.CreateIssue(&store.Issue{Title: "Claimed last turn", Status: "open", Priority: "high"}); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	if _, err := s.ClaimIssue(1, store.IssueClaim{TurnID: 4, LoopRunID: 2, LoopStep: 1}, time.Hour); err != nil {
+		t.Fatalf("ClaimIssue: %v", err)
+	}
+
+	build := func(stepIndex int) string {
+		got, err := Build(BuildOpts{
+			Store:         s,
+			Project:       project,
+			Profile:       &config.Profile{Name: "dev", Agent: "codex"},
+			CurrentTurnID: 5,
+			LoopContext:   &LoopPromptContext{LoopName: "dev-loop", RunID: 2, StepIndex: stepIndex, TotalSteps: 2},
+			Skills:        []string{},
+		})
+		if err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+		return got
+	}
+	if got := build(1); !strings.Contains(got, "work on issue **#1: Claimed last turn**") {
+		t.Fatalf("same step should keep its own claimed issue\nprompt:\n%s", got)
+	}
+	if got := build(0); strings.Contains(got, "Claimed last turn") {
+		t.Fatalf("another step should not see the claimed issue\nprompt:\n%s", got)
+	}
+}
+
 func TestBuild_ProfileDescriptionNotInOwnPrompt(t *testing.T) {
 	s, project := initPromptTestStore(t)
 	globalCfg := &config.GlobalConfig{}
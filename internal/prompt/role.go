@@ -8,6 +8,7 @@ import (
 
 	"github.com/agusx1211/adaf/internal/config"
 	"github.com/agusx1211/adaf/internal/profilescore"
+	"github.com/agusx1211/adaf/internal/stats"
 	"github.com/agusx1211/adaf/internal/store"
 )
 
@@ -116,8 +117,10 @@ func ReadOnlyPrompt() string {
 	return "# READ-ONLY MODE\n\nYou are in READ-ONLY mode. Do NOT create, modify, or delete any files. Only read and analyze.\n\nDo NOT write reports into repository files (for example `*.md`, `*.txt`, or TODO files). Return your report in your final assistant message.\n"
 }
 
-// delegationSection builds the delegation/spawning prompt section from a DelegationConfig.
-func delegationSection(deleg *config.DelegationConfig, globalCfg *config.GlobalConfig, runningSpawns []store.SpawnRecord, resourcePriority string) string {
+// delegationSection builds the delegation/spawning prompt section from a
+// DelegationConfig. st, when set, supplies the profile stats behind the
+// per-task cost estimates.
+func delegationSection(deleg *config.DelegationConfig, globalCfg *config.GlobalConfig, st *store.Store, runningSpawns []store.SpawnRecord, resourcePriority string) string {
 	if deleg == nil || len(deleg.Profiles) == 0 {
 		return ""
 	}
@@ -186,6 +189,7 @@ func delegationSection(deleg *config.DelegationConfig, globalCfg *config.GlobalC
 	b.WriteString("- **Avoid concentration:** spreading work across profiles prevents draining a single provider's usage quota and improves overall throughput.\n\n")
 
 	perfByProfile := loadDelegationPerformance(globalCfg)
+	costByProfile := loadDelegationCostEstimates(st, globalCfg, deleg)
 
 	if len(runningSpawns) > 0 {
 		b.WriteString("## Currently Running Spawns\n\n")
@@ -227,6 +231,9 @@ func delegationSection(deleg *config.DelegationConfig, globalCfg *config.GlobalC
 			if cost := config.NormalizeProfileCost(p.Cost); cost != "" {
 				line += fmt.Sprintf(", cost=%s", cost)
 			}
+			if est, ok := costByProfile[strings.ToLower(strings.TrimSpace(p.Name))]; ok {
+				line += fmt.Sprintf(", est_cost=%s", est)
+			}
 			speed := dp.Speed
 			if speed == "" {
 				speed = p.Speed
@@ -292,14 +299,14 @@ func delegationSection(deleg *config.DelegationConfig, globalCfg *config.GlobalC
 		b.WriteString("\n")
 	}
 
-	profileRows, roleColumns, roleMatrixRows := buildDelegationRoutingTables(deleg, globalCfg, perfByProfile)
+	profileRows, roleColumns, roleMatrixRows := buildDelegationRoutingTables(deleg, globalCfg, perfByProfile, costByProfile)
 	if len(profileRows) > 0 && len(roleColumns) > 0 {
 		b.WriteString("## Routing Scoreboard (difficulty-adjusted, judge-calibrated, judge-weighted)\n\n")
 		b.WriteString("### Profile Baseline\n\n")
-		b.WriteString("| Profile | Cost | Speed |\n")
-		b.WriteString("| --- | --- | ---: |\n")
+		b.WriteString("| Profile | Cost | Est. Cost/Task | Speed |\n")
+		b.WriteString("| --- | --- | ---: | ---: |\n")
 		for _, row := range profileRows {
-			fmt.Fprintf(&b, "| %s | %s | ", row.Profile, row.Cost)
+			fmt.Fprintf(&b, "| %s | %s | %s | ", row.Profile, row.Cost, row.EstCost)
 			if row.HasSpeedScore {
 				fmt.Fprintf(&b, "%.0f/100 |\n", row.SpeedScore)
 			} else {
@@ -372,6 +379,21 @@ func loadDelegationPerformance(globalCfg *config.GlobalConfig) map[string]profil
 	return out
 }
 
+// loadDelegationCostEstimates estimates one task for each delegation
+// profile from the project's profile stats and the model price catalog.
+func loadDelegationCostEstimates(st *store.Store, globalCfg *config.GlobalConfig, deleg *config.DelegationConfig) map[string]stats.CostEstimate {
+	if st == nil || globalCfg == nil || deleg == nil {
+		return nil
+	}
+	var profiles []config.Profile
+	for _, dp := range deleg.Profiles {
+		if p := globalCfg.FindProfile(dp.Name); p != nil {
+			profiles = append(profiles, *p)
+		}
+	}
+	return stats.EstimateProfileCosts(st, globalCfg, profiles)
+}
+
 func formatDelegationDuration(avgDurationSecs float64) string {
 	if avgDurationSecs <= 0 {
 		return "n/a"
@@ -436,6 +458,7 @@ func delegationLimitOptionKey(profile, position, role string) string {
 type delegationRoutingProfileRow struct {
 	Profile       string
 	Cost          string
+	EstCost       string
 	SpeedScore    float64
 	HasSpeedScore bool
 }
@@ -446,7 +469,7 @@ type delegationRoutingRoleMatrixRow struct {
 	Scores         map[string]float64
 }
 
-func buildDelegationRoutingTables(deleg *config.DelegationConfig, globalCfg *config.GlobalConfig, perfByProfile map[string]profilescore.ProfileSummary) ([]delegationRoutingProfileRow, []string, []delegationRoutingRoleMatrixRow) {
+func buildDelegationRoutingTables(deleg *config.DelegationConfig, globalCfg *config.GlobalConfig, perfByProfile map[string]profilescore.ProfileSummary, costByProfile map[string]stats.CostEstimate) ([]delegationRoutingProfileRow, []string, []delegationRoutingRoleMatrixRow) {
 	if deleg == nil || globalCfg == nil || len(deleg.Profiles) == 0 {
 		return nil, nil, nil
 	}
@@ -472,6 +495,10 @@ func buildDelegationRoutingTables(deleg *config.DelegationConfig, globalCfg *con
 			row := delegationRoutingProfileRow{
 				Profile: p.Name,
 				Cost:    cost,
+				EstCost: "n/a",
+			}
+			if est, ok := costByProfile[profileKey]; ok {
+				row.EstCost = est.String()
 			}
 			if perf, ok := perfByProfile[profileKey]; ok && perf.HasEnoughSamples {
 				row.SpeedScore = perf.SpeedScore
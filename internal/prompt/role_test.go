@@ -7,6 +7,7 @@ import (
 
 	"github.com/agusx1211/adaf/internal/config"
 	"github.com/agusx1211/adaf/internal/profilescore"
+	"github.com/agusx1211/adaf/internal/store"
 )
 
 func TestDelegationSection_IncludesSkillPointerWhenDelegationEnabled(t *testing.T) {
@@ -14,7 +15,7 @@ func TestDelegationSection_IncludesSkillPointerWhenDelegationEnabled(t *testing.
 		Profiles: []config.DelegationProfile{
 			{Name: "worker"},
 		},
-	}, nil, nil, "")
+	}, nil, nil, nil, "")
 
 	if !strings.Contains(got, "# Delegation") {
 		t.Fatalf("expected delegation header\nprompt:\n%s", got)
@@ -34,7 +35,7 @@ func TestDelegationSection_IncludesRoutingDiscipline(t *testing.T) {
 		Profiles: []config.DelegationProfile{
 			{Name: "worker"},
 		},
-	}, nil, nil, "")
+	}, nil, nil, nil, "")
 
 	if !strings.Contains(got, "## Routing Discipline") {
 		t.Fatalf("expected routing discipline section\nprompt:\n%s", got)
@@ -54,7 +55,7 @@ func TestDelegationSection_ResourcePriorityGuidance(t *testing.T) {
 		},
 	}
 
-	costPrompt := delegationSection(deleg, nil, nil, config.ResourcePriorityCost)
+	costPrompt := delegationSection(deleg, nil, nil, nil, config.ResourcePriorityCost)
 	if !strings.Contains(costPrompt, "Current priority: **cost**") {
 		t.Fatalf("expected cost priority section\nprompt:\n%s", costPrompt)
 	}
@@ -62,7 +63,7 @@ func TestDelegationSection_ResourcePriorityGuidance(t *testing.T) {
 		t.Fatalf("expected cheap-first guidance for cost mode\nprompt:\n%s", costPrompt)
 	}
 
-	qualityPrompt := delegationSection(deleg, nil, nil, config.ResourcePriorityQuality)
+	qualityPrompt := delegationSection(deleg, nil, nil, nil, config.ResourcePriorityQuality)
 	if !strings.Contains(qualityPrompt, "Current priority: **quality**") {
 		t.Fatalf("expected quality priority section\nprompt:\n%s", qualityPrompt)
 	}
@@ -72,12 +73,12 @@ func TestDelegationSection_ResourcePriorityGuidance(t *testing.T) {
 }
 
 func TestDelegationSection_NoDelegation(t *testing.T) {
-	got := delegationSection(nil, nil, nil, "")
+	got := delegationSection(nil, nil, nil, nil, "")
 	if got != "" {
 		t.Fatalf("delegationSection(nil) = %q, want empty", got)
 	}
 
-	got = delegationSection(&config.DelegationConfig{}, nil, nil, "")
+	got = delegationSection(&config.DelegationConfig{}, nil, nil, nil, "")
 	if got != "" {
 		t.Fatalf("delegationSection(empty) = %q, want empty", got)
 	}
@@ -99,7 +100,7 @@ func TestDelegationSection_IncludesRoleDetails(t *testing.T) {
 		},
 	}
 
-	got := delegationSection(deleg, globalCfg, nil, "")
+	got := delegationSection(deleg, globalCfg, nil, nil, "")
 	if !strings.Contains(got, "role=developer") {
 		t.Fatalf("expected role annotation in delegation section\nprompt:\n%s", got)
 	}
@@ -255,7 +256,7 @@ func TestDelegationSection_IncludesRoutingScoresSpeedAndCostTable(t *testing.T)
 		},
 	}
 
-	got := delegationSection(deleg, globalCfg, nil, "")
+	got := delegationSection(deleg, globalCfg, nil, nil, "")
 	if !strings.Contains(got, "cost=cheap") {
 		t.Fatalf("expected profile cost in available profiles section\nprompt:\n%s", got)
 	}
@@ -265,7 +266,7 @@ func TestDelegationSection_IncludesRoutingScoresSpeedAndCostTable(t *testing.T)
 	if !strings.Contains(got, "### Profile Baseline") {
 		t.Fatalf("expected profile baseline table heading\nprompt:\n%s", got)
 	}
-	if !strings.Contains(got, "| Profile | Cost | Speed |") {
+	if !strings.Contains(got, "| Profile | Cost | Est. Cost/Task | Speed |") {
 		t.Fatalf("expected profile baseline table header\nprompt:\n%s", got)
 	}
 	if !strings.Contains(got, "| worker | cheap |") {
@@ -334,8 +335,8 @@ func TestDelegationSection_RoutingScoreboardIncludesAllProfilesWithAvailabilityA
 		},
 	}
 
-	got := delegationSection(deleg, globalCfg, nil, "")
-	if !strings.Contains(got, "| opus 4.6 | expensive | ... |") {
+	got := delegationSection(deleg, globalCfg, nil, nil, "")
+	if !strings.Contains(got, "| opus 4.6 | expensive | n/a | ... |") {
 		t.Fatalf("expected baseline row for profile without feedback to show sparse marker\nprompt:\n%s", got)
 	}
 	if !strings.Contains(got, "| codex 5.3 | expensive |") {
@@ -414,7 +415,7 @@ func TestDelegationSection_HidesScoresUntilMinimumFeedbackSamples(t *testing.T)
 		},
 	}
 
-	got := delegationSection(deleg, globalCfg, nil, "")
+	got := delegationSection(deleg, globalCfg, nil, nil, "")
 	if !strings.Contains(got, "feedback=3") {
 		t.Fatalf("expected feedback count in profile line\nprompt:\n%s", got)
 	}
@@ -424,7 +425,7 @@ func TestDelegationSection_HidesScoresUntilMinimumFeedbackSamples(t *testing.T)
 	if strings.Contains(got, "speed_score=") {
 		t.Fatalf("speed score should be hidden for low sample profiles\nprompt:\n%s", got)
 	}
-	if !strings.Contains(got, "| worker | cheap | ... |") {
+	if !strings.Contains(got, "| worker | cheap | n/a | ... |") {
 		t.Fatalf("expected baseline speed to stay hidden with sparse samples\nprompt:\n%s", got)
 	}
 	if !strings.Contains(got, "| worker | ... |") {
@@ -464,17 +465,57 @@ func TestDelegationSection_ShowsScoresAfterMinimumFeedbackSamples(t *testing.T)
 		},
 	}
 
-	got := delegationSection(deleg, globalCfg, nil, "")
+	got := delegationSection(deleg, globalCfg, nil, nil, "")
 	if !strings.Contains(got, "feedback=10, score=") {
 		t.Fatalf("expected profile score once enough samples exist\nprompt:\n%s", got)
 	}
 	if !strings.Contains(got, "speed_score=") {
 		t.Fatalf("expected speed score once enough samples exist\nprompt:\n%s", got)
 	}
-	if strings.Contains(got, "| worker | cheap | ... |") {
+	if strings.Contains(got, "| worker | cheap | n/a | ... |") {
 		t.Fatalf("expected baseline speed score value after enough samples\nprompt:\n%s", got)
 	}
 	if strings.Contains(got, "| worker | ... |") {
 		t.Fatalf("expected role score value after enough samples\nprompt:\n%s", got)
 	}
 }
+
+func TestDelegationSection_ShowsEstimatedCostPerTask(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	s, _ := initPromptTestStore(t)
+	if err := s.SaveProfileStats(&store.ProfileStats{
+		ProfileName:    "worker",
+		TotalRuns:      2,
+		TokenRuns:      2,
+		TotalInputTok:  1_000_000,
+		TotalOutputTok: 100_000,
+		Model:          "gpt-5",
+	}); err != nil {
+		t.Fatalf("SaveProfileStats: %v", err)
+	}
+
+	deleg := &config.DelegationConfig{
+		Profiles: []config.DelegationProfile{
+			{Name: "worker", Role: config.RoleDeveloper},
+			{Name: "fresh", Role: config.RoleDeveloper},
+		},
+	}
+	globalCfg := &config.GlobalConfig{
+		Profiles: []config.Profile{
+			{Name: "worker", Agent: "codex", Cost: "cheap"},
+			{Name: "fresh", Agent: "codex", Cost: "cheap"},
+		},
+		ModelPrices: []config.ModelPrice{{Model: "gpt-5", Input: 1, Output: 10}},
+	}
+
+	got := delegationSection(deleg, globalCfg, s, nil, "")
+	if !strings.Contains(got, "est_cost=~$1.00/run (2 runs)") {
+		t.Fatalf("expected estimated cost in available profiles\nprompt:\n%s", got)
+	}
+	if !strings.Contains(got, "| worker | cheap | ~$1.00/run (2 runs) |") {
+		t.Fatalf("expected estimated cost in profile baseline\nprompt:\n%s", got)
+	}
+	if !strings.Contains(got, "| fresh | cheap | n/a |") {
+		t.Fatalf("expected n/a estimate for a profile without history\nprompt:\n%s", got)
+	}
+}
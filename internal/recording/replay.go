@@ -0,0 +1,143 @@
+package recording
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/store"
+	"github.com/agusx1211/adaf/internal/stream"
+)
+
+// ReplayOptions controls how a recorded turn is re-rendered.
+type ReplayOptions struct {
+	// Speed scales the recorded pacing: 1 is real time, 2 is twice as fast.
+	// Zero or negative replays instantly.
+	Speed float64
+
+	// MaxGap caps any single pause between events (after scaling) so long
+	// idle stretches such as tool runs don't stall the replay. Zero disables
+	// the cap.
+	MaxGap time.Duration
+
+	// ShowPrompt renders the recorded stdin prompt before the stream.
+	ShowPrompt bool
+}
+
+// Metadata returns the last value recorded for each meta key of rec.
+func Metadata(rec *store.TurnRecording) map[string]string {
+	meta := make(map[string]string)
+	if rec == nil {
+		return meta
+	}
+	for _, ev := range rec.Events {
+		if ev.Type != "meta" {
+			continue
+		}
+		key, value, ok := strings.Cut(ev.Data, "=")
+		if !ok {
+			continue
+		}
+		meta[key] = value
+	}
+	return meta
+}
+
+// Prompt returns the prompt that was piped to the agent on stdin, or "" when
+// the turn recorded none.
+func Prompt(rec *store.TurnRecording) string {
+	if rec == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, ev := range rec.Events {
+		if ev.Type == "stdin" {
+			b.WriteString(ev.Data)
+		}
+	}
+	return b.String()
+}
+
+// ReplayAgent returns the agent whose native stream format rec holds.
+func ReplayAgent(rec *store.TurnRecording) string {
+	if agentName := strings.TrimSpace(Metadata(rec)["agent"]); agentName != "" {
+		return agentName
+	}
+	if rec == nil {
+		return ""
+	}
+	return rec.Agent
+}
+
+// Replay re-renders a recorded turn to w. Stream events are fed through the
+// agent's own parser into stream.Display, exactly as they were shown live;
+// raw stdout/stderr chunks are written as-is. Pacing follows the recorded
+// timestamps scaled by opts.Speed.
+func Replay(ctx context.Context, rec *store.TurnRecording, w io.Writer, opts ReplayOptions) error {
+	if rec == nil {
+		return fmt.Errorf("no recording to replay")
+	}
+	display := stream.NewDisplay(w)
+	parse := stream.ParserFor(ReplayAgent(rec))
+
+	var last time.Time
+	for _, ev := range rec.Events {
+		if !last.IsZero() && !ev.Timestamp.IsZero() {
+			if err := sleepContext(ctx, replayDelay(ev.Timestamp.Sub(last), opts)); err != nil {
+				display.Finish()
+				return err
+			}
+		}
+		if !ev.Timestamp.IsZero() {
+			last = ev.Timestamp
+		}
+
+		switch ev.Type {
+		case "claude_stream":
+			// Parsers are line-oriented, so each recorded line is parsed on
+			// its own; this keeps rendering in lockstep with the pacing.
+			for parsed := range parse(ctx, strings.NewReader(ev.Data+"\n")) {
+				if parsed.Err != nil || parsed.Parsed.Type == "" {
+					continue
+				}
+				display.Handle(parsed.Parsed)
+			}
+		case "stdout", "stderr":
+			io.WriteString(w, ev.Data)
+		case "stdin":
+			if opts.ShowPrompt {
+				fmt.Fprintf(w, "\033[2m[prompt]\033[0m\n%s\n\n", strings.TrimRight(ev.Data, "\n"))
+			}
+		}
+	}
+	display.Finish()
+	return ctx.Err()
+}
+
+// replayDelay converts a recorded gap into the pause to apply on replay.
+func replayDelay(gap time.Duration, opts ReplayOptions) time.Duration {
+	if gap <= 0 || opts.Speed <= 0 {
+		return 0
+	}
+	d := time.Duration(float64(gap) / opts.Speed)
+	if opts.MaxGap > 0 && d > opts.MaxGap {
+		d = opts.MaxGap
+	}
+	return d
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
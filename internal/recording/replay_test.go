@@ -0,0 +1,102 @@
+package recording
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func TestReplayRendersStreamThroughDisplay(t *testing.T) {
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	rec := &store.TurnRecording{
+		TurnID: 3,
+		Agent:  "claude",
+		Events: []store.RecordingEvent{
+			{Timestamp: base, Type: "meta", Data: "agent=claude"},
+			{Timestamp: base, Type: "stdin", Data: "Fix the tests"},
+			{Timestamp: base.Add(time.Hour), Type: "claude_stream", Data: `{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Looking at the failures."}]}}`},
+			{Timestamp: base.Add(2 * time.Hour), Type: "stderr", Data: "warning: slow\n"},
+		},
+	}
+
+	var out bytes.Buffer
+	start := time.Now()
+	err := Replay(context.Background(), rec, &out, ReplayOptions{Speed: 1, MaxGap: time.Millisecond, ShowPrompt: true})
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Replay took %s, max gap was not applied", elapsed)
+	}
+	got := out.String()
+	for _, want := range []string{"Fix the tests", "Looking at the failures.", "warning: slow"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("replay output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "Fix the tests") > strings.Index(got, "Looking at the failures.") {
+		t.Fatalf("prompt rendered after stream:\n%s", got)
+	}
+}
+
+func TestReplayUsesRecordedAgentParser(t *testing.T) {
+	rec := &store.TurnRecording{
+		Agent: "claude",
+		Events: []store.RecordingEvent{
+			{Type: "meta", Data: "agent=codex"},
+			{Type: "claude_stream", Data: `{"type":"item.completed","item":{"id":"i1","type":"agent_message","text":"codex says hi"}}`},
+		},
+	}
+	if got := ReplayAgent(rec); got != "codex" {
+		t.Fatalf("ReplayAgent = %q, want codex", got)
+	}
+
+	var out bytes.Buffer
+	if err := Replay(context.Background(), rec, &out, ReplayOptions{}); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if !strings.Contains(out.String(), "codex says hi") {
+		t.Fatalf("codex stream not rendered:\n%s", out.String())
+	}
+}
+
+func TestReplayStopsOnCancel(t *testing.T) {
+	base := time.Now()
+	rec := &store.TurnRecording{
+		Events: []store.RecordingEvent{
+			{Timestamp: base, Type: "stdout", Data: "first\n"},
+			{Timestamp: base.Add(time.Hour), Type: "stdout", Data: "second\n"},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var out bytes.Buffer
+	if err := Replay(ctx, rec, &out, ReplayOptions{Speed: 1}); err == nil {
+		t.Fatal("Replay returned nil after cancellation")
+	}
+	if strings.Contains(out.String(), "second") {
+		t.Fatalf("replay continued past cancellation:\n%s", out.String())
+	}
+}
+
+func TestPromptAndMetadata(t *testing.T) {
+	rec := &store.TurnRecording{
+		Events: []store.RecordingEvent{
+			{Type: "meta", Data: "agent=claude"},
+			{Type: "meta", Data: "resume_session_id=abc=def"},
+			{Type: "stdin", Data: "do the thing"},
+		},
+	}
+	meta := Metadata(rec)
+	if meta["agent"] != "claude" || meta["resume_session_id"] != "abc=def" {
+		t.Fatalf("Metadata = %#v", meta)
+	}
+	if got := Prompt(rec); got != "do the thing" {
+		t.Fatalf("Prompt = %q", got)
+	}
+}
@@ -0,0 +1,65 @@
+package remote
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Exec describes an agent process that runs on a host instead of locally.
+type Exec struct {
+	Host      string // host name, for logs
+	Transport Transport
+	Dir       string // working directory on the host
+	Command   string // command on the host; empty uses the base name of the local command
+}
+
+// Wrap rewrites cmd, which must not have started, to run through the host
+// transport. Only env is forwarded to the host; the local environment stays
+// with the transport client (e.g. SSH_AUTH_SOCK). Stdin, stdout, stderr and
+// process-group handling of cmd are left untouched.
+func (e *Exec) Wrap(cmd *exec.Cmd, env map[string]string) error {
+	if e.Transport == nil {
+		return fmt.Errorf("remote exec on %q: no transport", e.Host)
+	}
+	if len(cmd.Args) == 0 {
+		return fmt.Errorf("remote exec on %q: empty command", e.Host)
+	}
+	argv := e.Transport.Argv(e.script(cmd.Args, env))
+	bin, err := exec.LookPath(argv[0])
+	if err != nil {
+		return fmt.Errorf("remote exec on %q: %w", e.Host, err)
+	}
+	cmd.Path = bin
+	cmd.Args = argv
+	// The agent binary need not exist locally; clear the lookup error
+	// exec.Command recorded for it.
+	cmd.Err = nil
+	return nil
+}
+
+func (e *Exec) script(args []string, env map[string]string) string {
+	command := e.Command
+	if command == "" {
+		command = filepath.Base(args[0])
+	}
+
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "cd %s && exec env", shellPath(e.Dir))
+	for _, k := range keys {
+		b.WriteString(" " + Quote(k+"="+env[k]))
+	}
+	b.WriteString(" " + Quote(command))
+	for _, a := range args[1:] {
+		b.WriteString(" " + Quote(a))
+	}
+	return b.String()
+}
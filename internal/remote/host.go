@@ -0,0 +1,158 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/agusx1211/adaf/internal/config"
+)
+
+// Host is a configured worker host.
+type Host struct {
+	Config    config.RemoteHost
+	Transport Transport
+}
+
+// NewHost validates cfg and builds its transport.
+func NewHost(cfg config.RemoteHost) (*Host, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	t, err := NewTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Host{Config: cfg, Transport: t}, nil
+}
+
+// Name returns the configured host name.
+func (h *Host) Name() string { return h.Config.Name }
+
+// Workspace locates one spawn checkout on a host. Paths are relative to the
+// remote home directory unless absolute.
+type Workspace struct {
+	Repo   string // per-project repository receiving pushed branches
+	Dir    string // worktree the agent runs in
+	Branch string // branch checked out in Dir
+}
+
+// WorkspaceFor returns where spawnID of project is checked out on the host.
+func (h *Host) WorkspaceFor(project string, spawnID int) Workspace {
+	root := h.Config.EffectiveRoot()
+	project = sanitize(project)
+	return Workspace{
+		Repo:   path.Join(root, project),
+		Dir:    path.Join(root, project+"-worktrees", fmt.Sprintf("spawn-%d", spawnID)),
+		Branch: fmt.Sprintf("adaf/spawn-%d", spawnID),
+	}
+}
+
+// Run executes script on the host and returns its combined output.
+func (h *Host) Run(ctx context.Context, script string) (string, error) {
+	argv := h.Transport.Argv(script)
+	out, err := exec.CommandContext(ctx, argv[0], argv[1:]...).CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("host %s: %w: %s", h.Name(), err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// git runs a local git command that may talk to the host.
+func (h *Host) git(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), h.Transport.GitEnv()...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Prepare pushes the HEAD of localDir to the host and checks it out in
+// ws.Dir, creating the host repository on first use. A stale checkout left
+// by an earlier attempt is replaced.
+func (h *Host) Prepare(ctx context.Context, localDir string, ws Workspace) error {
+	repo := shellPath(ws.Repo)
+	if _, err := h.Run(ctx, fmt.Sprintf(
+		"mkdir -p %s && { git -C %s rev-parse --git-dir >/dev/null 2>&1 || git init -q %s; }",
+		repo, repo, repo)); err != nil {
+		return fmt.Errorf("creating repository: %w", err)
+	}
+	if err := h.git(ctx, localDir, "push", "-q", "--force", h.Transport.GitURL(ws.Repo), "HEAD:refs/heads/"+ws.Branch); err != nil {
+		return fmt.Errorf("pushing to %s: %w", h.Name(), err)
+	}
+	dir := shellPath(ws.Dir)
+	if _, err := h.Run(ctx, fmt.Sprintf(
+		"git -C %s worktree remove --force %s >/dev/null 2>&1; git -C %s worktree prune && mkdir -p %s && git -C %s worktree add -q -f %s %s",
+		repo, dir, repo, shellPath(path.Dir(ws.Dir)), repo, dir, Quote(ws.Branch))); err != nil {
+		return fmt.Errorf("creating worktree: %w", err)
+	}
+	return nil
+}
+
+// Collect commits anything the agent left uncommitted in ws.Dir and
+// fast-forwards localDir to the host branch. It returns the hash of the
+// fallback commit when one was made.
+func (h *Host) Collect(ctx context.Context, localDir string, ws Workspace, message string) (string, bool, error) {
+	out, err := h.Run(ctx, fmt.Sprintf(
+		"cd %s && if [ -n \"$(git status --porcelain)\" ]; then git add -A && git -c user.name=ADAF -c user.email=adaf@local commit -q -m %s && git rev-parse HEAD; fi",
+		shellPath(ws.Dir), Quote(message)))
+	if err != nil {
+		return "", false, fmt.Errorf("auto-commit on %s: %w", h.Name(), err)
+	}
+	hash := strings.TrimSpace(out)
+
+	if err := h.git(ctx, localDir, "fetch", "-q", h.Transport.GitURL(ws.Repo), "refs/heads/"+ws.Branch); err != nil {
+		return "", false, fmt.Errorf("fetching from %s: %w", h.Name(), err)
+	}
+	if err := h.git(ctx, localDir, "merge", "-q", "--ff-only", "FETCH_HEAD"); err != nil {
+		return "", false, fmt.Errorf("fast-forwarding to %s work: %w", h.Name(), err)
+	}
+	return hash, hash != "", nil
+}
+
+// Release removes ws.Dir and its branch from the host.
+func (h *Host) Release(ctx context.Context, ws Workspace) error {
+	repo := shellPath(ws.Repo)
+	_, err := h.Run(ctx, fmt.Sprintf(
+		"git -C %s worktree remove --force %s; git -C %s worktree prune; git -C %s branch -q -D %s",
+		repo, shellPath(ws.Dir), repo, repo, Quote(ws.Branch)))
+	return err
+}
+
+// Exec returns the execution spec running agentName in ws.Dir.
+func (h *Host) Exec(ws Workspace, agentName string) *Exec {
+	return &Exec{
+		Host:      h.Name(),
+		Transport: h.Transport,
+		Dir:       ws.Dir,
+		Command:   h.Config.Commands[agentName],
+	}
+}
+
+// shellPath quotes p for a script that starts in the remote home directory,
+// anchoring relative paths there so later cd or -C flags don't move them.
+func shellPath(p string) string {
+	if path.IsAbs(p) {
+		return Quote(p)
+	}
+	return `"$PWD"/` + Quote(p)
+}
+
+func sanitize(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '-'
+	}, s)
+	s = strings.Trim(s, ".-")
+	if s == "" {
+		return "project"
+	}
+	return s
+}
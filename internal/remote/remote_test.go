@@ -0,0 +1,148 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/config"
+)
+
+func TestQuote(t *testing.T) {
+	for in, want := range map[string]string{
+		"plain":         "plain",
+		"a/b-c_d.e=f":   "a/b-c_d.e=f",
+		"":              "''",
+		"two words":     "'two words'",
+		"it's":          `'it'\''s'`,
+		"$HOME; rm -rf": `'$HOME; rm -rf'`,
+	} {
+		if got := Quote(in); got != want {
+			t.Fatalf("Quote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestSSHTransportArgv(t *testing.T) {
+	tr := &SSHTransport{Address: "dev@build-2", Port: 2222, Args: []string{"-i", "/keys/adaf"}}
+	got := strings.Join(tr.Argv("echo 'hi'"), " ")
+	want := `ssh -T -o BatchMode=yes -p 2222 -i /keys/adaf dev@build-2 sh -c 'echo '\''hi'\'''`
+	if got != want {
+		t.Fatalf("Argv = %s\nwant %s", got, want)
+	}
+	if got := tr.GitURL("adaf-remote/app"); got != "dev@build-2:adaf-remote/app" {
+		t.Fatalf("GitURL = %s", got)
+	}
+	if env := tr.GitEnv(); len(env) != 1 || env[0] != "GIT_SSH_COMMAND=ssh -o BatchMode=yes -p 2222 -i /keys/adaf" {
+		t.Fatalf("GitEnv = %v", env)
+	}
+}
+
+func TestExecWrapRunsInRemoteDir(t *testing.T) {
+	home := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(home, "work"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	e := &Exec{Host: "loop", Transport: &LocalTransport{Home: home}, Dir: "work", Command: "sh"}
+
+	cmd := exec.Command("/not/installed/here", "-c", `pwd; printf '%s|%s\n' "$GREETING" "$1"`, "x", "it's")
+	cmd.Env = append(os.Environ(), "LOCAL_ONLY=1")
+	if err := e.Wrap(cmd, map[string]string{"GREETING": "hello world"}); err != nil {
+		t.Fatalf("Wrap: %v", err)
+	}
+	cmd.Dir = t.TempDir()
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || lines[0] != filepath.Join(home, "work") || lines[1] != "hello world|it's" {
+		t.Fatalf("output = %q", out.String())
+	}
+}
+
+func TestPrepareCollectRelease(t *testing.T) {
+	ctx := context.Background()
+	local := t.TempDir()
+	git(t, local, "init", "-q", "-b", "main")
+	writeFile(t, filepath.Join(local, "a.txt"), "one\n")
+	git(t, local, "add", "-A")
+	git(t, local, "-c", "user.name=T", "-c", "user.email=t@e", "commit", "-q", "-m", "init")
+
+	h, err := NewHost(config.RemoteHost{Name: "loop", Transport: "local", Root: t.TempDir(), Agents: []string{"generic"}})
+	if err != nil {
+		t.Fatalf("NewHost: %v", err)
+	}
+	ws := h.WorkspaceFor("my app", 7)
+	if ws.Branch != "adaf/spawn-7" || filepath.Base(ws.Repo) != "my-app" {
+		t.Fatalf("workspace = %+v", ws)
+	}
+	if err := h.Prepare(ctx, local, ws); err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+	// Preparing again replaces the checkout.
+	if err := h.Prepare(ctx, local, ws); err != nil {
+		t.Fatalf("Prepare (again): %v", err)
+	}
+
+	// The agent commits once and leaves one change uncommitted.
+	writeFile(t, filepath.Join(ws.Dir, "a.txt"), "two\n")
+	git(t, ws.Dir, "-c", "user.name=T", "-c", "user.email=t@e", "commit", "-q", "-am", "agent edit")
+	writeFile(t, filepath.Join(ws.Dir, "b.txt"), "new\n")
+
+	hash, committed, err := h.Collect(ctx, local, ws, "adaf: auto-commit spawn #7")
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if !committed || hash == "" {
+		t.Fatalf("Collect committed = %v hash = %q", committed, hash)
+	}
+	if head := strings.TrimSpace(gitOut(t, local, "rev-parse", "HEAD")); head != hash {
+		t.Fatalf("local HEAD = %s, want %s", head, hash)
+	}
+	if data, _ := os.ReadFile(filepath.Join(local, "b.txt")); string(data) != "new\n" {
+		t.Fatalf("b.txt = %q", data)
+	}
+
+	if _, committed, err := h.Collect(ctx, local, ws, "unused"); err != nil || committed {
+		t.Fatalf("second Collect committed = %v err = %v", committed, err)
+	}
+
+	if err := h.Release(ctx, ws); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if _, err := os.Stat(ws.Dir); !os.IsNotExist(err) {
+		t.Fatalf("worktree still present: %v", err)
+	}
+	if out := gitOut(t, ws.Repo, "branch", "--list", ws.Branch); strings.TrimSpace(out) != "" {
+		t.Fatalf("branch still present: %q", out)
+	}
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	gitOut(t, dir, args...)
+}
+
+func gitOut(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
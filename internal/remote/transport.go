@@ -0,0 +1,121 @@
+// Package remote runs spawn agents on worker hosts declared in the global
+// config.
+//
+// Nothing but git and a shell is required on a host. Before a spawn starts,
+// its branch is pushed into a per-project repository under the host root and
+// checked out in a worktree there; the agent command is then rewritten to
+// run through the host transport, so its stdout — and with it the NDJSON
+// event stream — is piped back into the local recorder unchanged. When the
+// agent exits, leftover changes are committed on the host and the commits
+// are fast-forwarded into the local spawn worktree, where spawn-diff and
+// spawn-merge find them as usual.
+package remote
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/agusx1211/adaf/internal/config"
+)
+
+// Transport reaches a host.
+type Transport interface {
+	// Argv returns the local command line that runs script with sh on the
+	// host, starting in the remote user's home directory.
+	Argv(script string) []string
+	// GitURL returns a URL local git can push to and fetch from for the
+	// repository at path on the host.
+	GitURL(path string) string
+	// GitEnv returns extra environment for local git commands using GitURL.
+	GitEnv() []string
+}
+
+// NewTransport builds the transport configured for h.
+func NewTransport(h config.RemoteHost) (Transport, error) {
+	switch h.EffectiveTransport() {
+	case config.RemoteTransportSSH:
+		return &SSHTransport{Address: h.Address, Port: h.Port, Args: h.SSHArgs}, nil
+	case config.RemoteTransportLocal:
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		return &LocalTransport{Home: home}, nil
+	default:
+		return nil, fmt.Errorf("unknown remote transport %q", h.Transport)
+	}
+}
+
+// SSHTransport runs commands with the system ssh client in batch mode, so
+// authentication must work without prompts (keys or an agent).
+type SSHTransport struct {
+	Address string
+	Port    int
+	Args    []string
+}
+
+func (t *SSHTransport) sshArgs() []string {
+	args := []string{"-o", "BatchMode=yes"}
+	if t.Port > 0 {
+		args = append(args, "-p", strconv.Itoa(t.Port))
+	}
+	return append(args, t.Args...)
+}
+
+// Argv implements Transport. The remote login shell receives a single
+// quoted sh invocation so the script reaches sh verbatim.
+func (t *SSHTransport) Argv(script string) []string {
+	argv := append([]string{"ssh", "-T"}, t.sshArgs()...)
+	return append(argv, t.Address, "sh -c "+Quote(script))
+}
+
+// GitURL implements Transport using scp-like syntax, which keeps relative
+// paths relative to the remote home directory.
+func (t *SSHTransport) GitURL(p string) string {
+	return t.Address + ":" + p
+}
+
+// GitEnv implements Transport, passing the port and client options to git.
+func (t *SSHTransport) GitEnv() []string {
+	words := []string{"ssh"}
+	for _, a := range t.sshArgs() {
+		words = append(words, Quote(a))
+	}
+	return []string{"GIT_SSH_COMMAND=" + strings.Join(words, " ")}
+}
+
+// LocalTransport runs commands on this machine. It exercises the same code
+// path as SSH without needing an sshd, and suits loopback setups where the
+// host root is a separate disk or checkout area.
+type LocalTransport struct {
+	Home string // stands in for the remote home directory
+}
+
+// Argv implements Transport.
+func (t *LocalTransport) Argv(script string) []string {
+	return []string{"sh", "-c", "cd " + Quote(t.Home) + " && " + script}
+}
+
+// GitURL implements Transport.
+func (t *LocalTransport) GitURL(p string) string {
+	if path.IsAbs(p) {
+		return p
+	}
+	return path.Join(t.Home, p)
+}
+
+// GitEnv implements Transport.
+func (t *LocalTransport) GitEnv() []string { return nil }
+
+// Quote returns s quoted for a POSIX shell.
+func Quote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:@+,", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
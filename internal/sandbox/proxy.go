@@ -0,0 +1,135 @@
+package sandbox
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// proxySocket is the unix socket name inside the proxy's temp directory.
+const proxySocket = "proxy.sock"
+
+// proxy is an HTTP forward proxy that only lets requests through to
+// allow-listed hosts. Sandboxed agents have no route out of their network
+// namespace; their only egress is this proxy, reached over a unix socket
+// that the sandbox init bridges to a loopback port.
+type proxy struct {
+	allow     []string
+	dir       string
+	srv       *http.Server
+	transport *http.Transport
+}
+
+func startProxy(allow []string) (*proxy, error) {
+	dir, err := os.MkdirTemp("", "adaf-sandbox-")
+	if err != nil {
+		return nil, fmt.Errorf("creating proxy dir: %w", err)
+	}
+	ln, err := net.Listen("unix", filepath.Join(dir, proxySocket))
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("listening for proxy: %w", err)
+	}
+	p := &proxy{
+		allow:     append([]string(nil), allow...),
+		dir:       dir,
+		transport: &http.Transport{Proxy: nil, ResponseHeaderTimeout: 5 * time.Minute},
+	}
+	p.srv = &http.Server{Handler: p, ReadHeaderTimeout: 30 * time.Second}
+	go p.srv.Serve(ln)
+	return p, nil
+}
+
+// Close stops the proxy and removes its socket.
+func (p *proxy) Close() {
+	p.srv.Close()
+	p.transport.CloseIdleConnections()
+	os.RemoveAll(p.dir)
+}
+
+func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := r.URL.Hostname()
+	if r.Method == http.MethodConnect {
+		host, _, _ = net.SplitHostPort(r.Host)
+	}
+	if !hostAllowed(host, p.allow) {
+		http.Error(w, fmt.Sprintf("adaf sandbox: host %q is not in the allow list", host), http.StatusForbidden)
+		return
+	}
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+	if r.URL.Scheme == "" || r.URL.Host == "" {
+		http.Error(w, "adaf sandbox: proxy requests must use an absolute URL", http.StatusBadRequest)
+		return
+	}
+
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	out.Header.Del("Proxy-Connection")
+	out.Header.Del("Proxy-Authorization")
+	resp, err := p.transport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
+
+func (p *proxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	dst, err := net.DialTimeout("tcp", r.Host, 30*time.Second)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		dst.Close()
+		http.Error(w, "adaf sandbox: tunneling not supported", http.StatusInternalServerError)
+		return
+	}
+	src, buf, err := hj.Hijack()
+	if err != nil {
+		dst.Close()
+		return
+	}
+	if _, err := io.WriteString(src, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+		src.Close()
+		dst.Close()
+		return
+	}
+	if n := buf.Reader.Buffered(); n > 0 {
+		pending, _ := buf.Reader.Peek(n)
+		dst.Write(pending)
+	}
+	splice(src, dst)
+}
+
+// splice copies between a and b in both directions until either side is
+// done, then closes both.
+func splice(a, b net.Conn) {
+	var once sync.Once
+	closeBoth := func() {
+		a.Close()
+		b.Close()
+	}
+	go func() {
+		io.Copy(a, b)
+		once.Do(closeBoth)
+	}()
+	io.Copy(b, a)
+	once.Do(closeBoth)
+}
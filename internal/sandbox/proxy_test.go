@@ -0,0 +1,72 @@
+package sandbox
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHostAllowed(t *testing.T) {
+	allow := []string{"api.anthropic.com", "*.github.com"}
+	cases := map[string]bool{
+		"api.anthropic.com":  true,
+		"API.Anthropic.com.": true,
+		"anthropic.com":      false,
+		"api.github.com":     true,
+		"github.com":         false,
+		"evil.com":           false,
+	}
+	for host, want := range cases {
+		if got := hostAllowed(host, allow); got != want {
+			t.Errorf("hostAllowed(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestProxyFiltersHosts(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello")
+	}))
+	defer backend.Close()
+
+	p, err := startProxy([]string{"127.0.0.1"})
+	if err != nil {
+		t.Fatalf("startProxy: %v", err)
+	}
+	defer p.Close()
+
+	sock := filepath.Join(p.dir, proxySocket)
+	client := &http.Client{Transport: &http.Transport{
+		Proxy: http.ProxyURL(&url.URL{Scheme: "http", Host: "proxy"}),
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", sock)
+		},
+	}}
+
+	resp, err := client.Get(backend.URL)
+	if err != nil {
+		t.Fatalf("allowed request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "hello" {
+		t.Fatalf("allowed request = %d %q", resp.StatusCode, body)
+	}
+
+	blocked := strings.Replace(backend.URL, "127.0.0.1", "localhost", 1)
+	resp, err = client.Get(blocked)
+	if err != nil {
+		t.Fatalf("blocked request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("blocked request status = %d, want 403", resp.StatusCode)
+	}
+}
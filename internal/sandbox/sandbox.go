@@ -0,0 +1,151 @@
+// Package sandbox confines agent processes with Linux namespaces.
+//
+// A sandboxed command is re-executed through the adaf binary itself: the
+// child starts in fresh user, mount and (optionally) network namespaces,
+// mounts the filesystem read-only except for the allowed paths, and then
+// launches the real agent in a nested user namespace mapped back to the
+// caller's uid so the agent keeps no privileges over those mounts.
+// Binaries that may act as the sandbox init must call RunInitIfRequested
+// first thing in main.
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/agusx1211/adaf/internal/config"
+)
+
+// initEnv carries the encoded init spec from Wrap to the re-executed binary.
+const initEnv = "ADAF_SANDBOX_INIT"
+
+// Spec describes how one agent process is confined. The process working
+// directory is writable unless ReadOnly is set.
+type Spec struct {
+	ReadOnly   bool     `json:"read_only,omitempty"`
+	Writable   []string `json:"writable,omitempty"`
+	Network    string   `json:"network,omitempty"`
+	AllowHosts []string `json:"allow_hosts,omitempty"`
+}
+
+// FromConfig builds a Spec for agentName from a profile or delegation
+// sandbox config. It returns nil when cfg is nil (sandbox disabled).
+func FromConfig(cfg *config.Sandbox, agentName string) *Spec {
+	if cfg == nil {
+		return nil
+	}
+	spec := &Spec{
+		Network:    cfg.EffectiveNetwork(),
+		AllowHosts: append([]string(nil), cfg.AllowHosts...),
+	}
+	spec.Writable = append(spec.Writable, AgentStatePaths(agentName)...)
+	for _, p := range cfg.Writable {
+		spec.Writable = append(spec.Writable, expandHome(strings.TrimSpace(p)))
+	}
+	return spec
+}
+
+// Clone returns a deep copy of s.
+func (s *Spec) Clone() *Spec {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	out.Writable = append([]string(nil), s.Writable...)
+	out.AllowHosts = append([]string(nil), s.AllowHosts...)
+	return &out
+}
+
+// AllowWrite adds paths to the writable set, skipping duplicates.
+func (s *Spec) AllowWrite(paths ...string) {
+	for _, p := range paths {
+		if p = strings.TrimSpace(p); p != "" && !slices.Contains(s.Writable, p) {
+			s.Writable = append(s.Writable, p)
+		}
+	}
+}
+
+// gitMetadataDirs returns the git directories a linked worktree at dir
+// writes to when committing: its own gitdir and the shared common dir. A
+// regular checkout keeps .git inside dir and needs nothing extra.
+func gitMetadataDirs(dir string) []string {
+	data, err := os.ReadFile(filepath.Join(dir, ".git"))
+	if err != nil {
+		return nil
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return nil
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+	out := []string{gitDir}
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		c := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(c) {
+			c = filepath.Join(gitDir, c)
+		}
+		out = append(out, filepath.Clean(c))
+	}
+	return out
+}
+
+// agentStateDirs lists, per built-in agent, the home-relative paths the CLI
+// writes session state and credentials to. Without them the agents cannot
+// resume sessions or refresh logins inside the sandbox.
+var agentStateDirs = map[string][]string{
+	"claude":   {".claude", ".claude.json"},
+	"codex":    {".codex"},
+	"gemini":   {".gemini"},
+	"opencode": {".local/share/opencode", ".local/state/opencode", ".config/opencode", ".cache/opencode"},
+	"vibe":     {".vibe"},
+}
+
+// AgentStatePaths returns the absolute state paths written by agentName.
+func AgentStatePaths(agentName string) []string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return nil
+	}
+	rel := agentStateDirs[agentName]
+	out := make([]string, 0, len(rel))
+	for _, r := range rel {
+		out = append(out, filepath.Join(home, r))
+	}
+	return out
+}
+
+func expandHome(p string) string {
+	rest, ok := strings.CutPrefix(p, "~/")
+	if !ok {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return p
+	}
+	return filepath.Join(home, rest)
+}
+
+// hostAllowed reports whether host matches one of the allow-list patterns.
+// "*.example.com" matches any subdomain of example.com but not the apex.
+func hostAllowed(host string, allow []string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, pattern := range allow {
+		pattern = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(pattern)), ".")
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
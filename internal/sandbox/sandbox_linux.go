@@ -0,0 +1,433 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/agusx1211/adaf/internal/config"
+)
+
+// oPath is O_PATH, which package syscall does not export.
+const oPath = 0x200000
+
+// initSpec is the state handed from Wrap to the sandbox init.
+type initSpec struct {
+	Spec
+	WorkDir  string   `json:"work_dir"`
+	Protect  []string `json:"protect,omitempty"` // read-only islands inside writable paths
+	ProxyDir string   `json:"proxy_dir,omitempty"`
+	UID      int      `json:"uid"`
+	GID      int      `json:"gid"`
+}
+
+// Supported reports whether this platform can run sandboxed commands.
+func Supported() bool { return true }
+
+// Wrap rewrites cmd so it runs inside the sandbox described by spec. The
+// command's environment must already be set. The returned cleanup func must
+// be called after the command exits; it is never nil.
+func Wrap(cmd *exec.Cmd, spec *Spec) (func(), error) {
+	noop := func() {}
+	if spec == nil {
+		return noop, nil
+	}
+	if cmd.Err != nil {
+		return noop, cmd.Err
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return noop, fmt.Errorf("sandbox: locating adaf binary: %w", err)
+	}
+	workDir := cmd.Dir
+	if workDir == "" {
+		if workDir, err = os.Getwd(); err != nil {
+			return noop, fmt.Errorf("sandbox: %w", err)
+		}
+	}
+	if workDir, err = filepath.Abs(workDir); err != nil {
+		return noop, fmt.Errorf("sandbox: %w", err)
+	}
+
+	is := initSpec{
+		Spec:    *spec.Clone(),
+		WorkDir: workDir,
+		UID:     os.Getuid(),
+		GID:     os.Getgid(),
+	}
+	if !is.ReadOnly {
+		// Commits from a linked worktree write to the main repository's git
+		// dir; its hooks and config stay read-only so the agent cannot plant
+		// code that later runs outside the sandbox.
+		for _, dir := range gitMetadataDirs(workDir) {
+			is.AllowWrite(dir)
+			is.Protect = append(is.Protect, filepath.Join(dir, "hooks"), filepath.Join(dir, "config"))
+		}
+	}
+	cleanup := noop
+	cloneflags := uintptr(syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS)
+	switch is.Network {
+	case "", config.SandboxNetworkHost:
+		is.Network = config.SandboxNetworkHost
+	case config.SandboxNetworkNone:
+		cloneflags |= syscall.CLONE_NEWNET
+	case config.SandboxNetworkAllowlist:
+		cloneflags |= syscall.CLONE_NEWNET
+		p, err := startProxy(is.AllowHosts)
+		if err != nil {
+			return noop, fmt.Errorf("sandbox: %w", err)
+		}
+		is.ProxyDir = p.dir
+		cleanup = p.Close
+	default:
+		return noop, fmt.Errorf("sandbox: unknown network mode %q", is.Network)
+	}
+
+	raw, err := json.Marshal(is)
+	if err != nil {
+		cleanup()
+		return noop, fmt.Errorf("sandbox: encoding spec: %w", err)
+	}
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = append(env, initEnv+"="+string(raw))
+	cmd.Args = append([]string{self, cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = self
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Cloneflags |= cloneflags
+	cmd.SysProcAttr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: is.UID, Size: 1}}
+	cmd.SysProcAttr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: is.GID, Size: 1}}
+	cmd.SysProcAttr.GidMappingsEnableSetgroups = false
+	return cleanup, nil
+}
+
+// RunInitIfRequested runs the sandbox init and exits when the process was
+// started by Wrap. Otherwise it returns immediately.
+func RunInitIfRequested() {
+	raw, ok := os.LookupEnv(initEnv)
+	if !ok {
+		return
+	}
+	os.Unsetenv(initEnv)
+	code, err := runInit(raw, os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "adaf sandbox: %v\n", err)
+		os.Exit(126)
+	}
+	os.Exit(code)
+}
+
+func runInit(raw string, argv []string) (int, error) {
+	var spec initSpec
+	if err := json.Unmarshal([]byte(raw), &spec); err != nil {
+		return 0, fmt.Errorf("decoding spec: %w", err)
+	}
+	if len(argv) == 0 {
+		return 0, fmt.Errorf("no command to run")
+	}
+
+	// The proxy socket lives under /tmp, which is replaced below; keep a
+	// handle on its directory to reach it afterwards.
+	proxyDirFD := -1
+	if spec.ProxyDir != "" {
+		fd, err := syscall.Open(spec.ProxyDir, oPath|syscall.O_CLOEXEC, 0)
+		if err != nil {
+			return 0, fmt.Errorf("opening proxy dir: %w", err)
+		}
+		proxyDirFD = fd
+	}
+
+	if err := setupMounts(spec); err != nil {
+		return 0, err
+	}
+
+	env := os.Environ()
+	if spec.Network != config.SandboxNetworkHost {
+		if err := loopbackUp(); err != nil {
+			return 0, fmt.Errorf("configuring loopback: %w", err)
+		}
+	}
+	if proxyDirFD >= 0 {
+		addr, err := bridge(fmt.Sprintf("/proc/self/fd/%d/%s", proxyDirFD, proxySocket))
+		if err != nil {
+			return 0, err
+		}
+		env = append(env, proxyEnv("http://"+addr)...)
+	}
+
+	// Run the agent in a nested user namespace mapped back to the caller's
+	// ids so it holds no capabilities over the mounts set up above.
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Dir = spec.WorkDir
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags:                 syscall.CLONE_NEWUSER,
+		UidMappings:                []syscall.SysProcIDMap{{ContainerID: spec.UID, HostID: 0, Size: 1}},
+		GidMappings:                []syscall.SysProcIDMap{{ContainerID: spec.GID, HostID: 0, Size: 1}},
+		GidMappingsEnableSetgroups: false,
+		Pdeathsig:                  syscall.SIGKILL,
+	}
+
+	sigs := make(chan os.Signal, 4)
+	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP)
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+	go func() {
+		for sig := range sigs {
+			cmd.Process.Signal(sig)
+		}
+	}()
+
+	err := cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			return 128 + int(ws.Signal()), nil
+		}
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
+
+// setupMounts gives the process a private /tmp, binds the writable paths
+// and remounts everything else read-only.
+func setupMounts(spec initSpec) error {
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("making mounts private: %w", err)
+	}
+
+	// The working directory is always bound so it survives a private /tmp;
+	// read-only spawns get it remounted read-only afterwards.
+	paths := append([]string{spec.WorkDir}, spec.Writable...)
+	type bind struct {
+		target   string
+		fd       int
+		dir      bool
+		readOnly bool
+	}
+	var binds []bind
+	defer func() {
+		for _, b := range binds {
+			syscall.Close(b.fd)
+		}
+	}()
+	// Open the sources before /tmp is replaced so paths under it survive.
+	for i, p := range paths {
+		real, err := filepath.EvalSymlinks(p)
+		if err != nil {
+			continue // optional state paths may not exist
+		}
+		fi, err := os.Stat(real)
+		if err != nil {
+			continue
+		}
+		fd, err := syscall.Open(real, oPath|syscall.O_CLOEXEC, 0)
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", real, err)
+		}
+		binds = append(binds, bind{target: real, fd: fd, dir: fi.IsDir(), readOnly: i == 0 && spec.ReadOnly})
+	}
+
+	if err := syscall.Mount("tmpfs", "/tmp", "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "mode=1777"); err != nil {
+		return fmt.Errorf("mounting /tmp: %w", err)
+	}
+	targets := make([]string, 0, len(binds)+1)
+	targets = append(targets, "/tmp")
+	for _, b := range binds {
+		if b.dir {
+			os.MkdirAll(b.target, 0o755)
+		} else if _, err := os.Stat(b.target); err != nil {
+			os.MkdirAll(filepath.Dir(b.target), 0o755)
+			if f, err := os.OpenFile(b.target, os.O_CREATE|os.O_WRONLY, 0o644); err == nil {
+				f.Close()
+			}
+		}
+		src := fmt.Sprintf("/proc/self/fd/%d", b.fd)
+		if err := syscall.Mount(src, b.target, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+			return fmt.Errorf("binding %s: %w", b.target, err)
+		}
+		if b.readOnly {
+			if err := remountReadOnly(b.target); err != nil {
+				return fmt.Errorf("remounting %s read-only: %w", b.target, err)
+			}
+			continue
+		}
+		targets = append(targets, b.target)
+	}
+
+	for _, p := range spec.Protect {
+		if _, err := os.Stat(p); err != nil {
+			continue
+		}
+		if err := syscall.Mount(p, p, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+			return fmt.Errorf("binding %s: %w", p, err)
+		}
+		if err := remountReadOnly(p); err != nil {
+			return fmt.Errorf("remounting %s read-only: %w", p, err)
+		}
+	}
+
+	mounts, err := mountPoints()
+	if err != nil {
+		return err
+	}
+	for _, mp := range mounts {
+		if keepWritable(mp, targets) {
+			continue
+		}
+		if err := remountReadOnly(mp); err != nil {
+			return fmt.Errorf("remounting %s read-only: %w", mp, err)
+		}
+	}
+	return nil
+}
+
+// keepWritable reports whether mount point mp is left as is: kernel
+// filesystems and anything at or below a writable target.
+func keepWritable(mp string, targets []string) bool {
+	for _, prefix := range []string{"/proc", "/sys", "/dev"} {
+		if pathWithin(mp, prefix) {
+			return true
+		}
+	}
+	for _, t := range targets {
+		if pathWithin(mp, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func pathWithin(p, dir string) bool {
+	return p == dir || strings.HasPrefix(p, strings.TrimSuffix(dir, "/")+"/")
+}
+
+// lockedMountFlags are the per-mount flags that must be preserved when an
+// unprivileged user namespace remounts a mount it inherited.
+const lockedMountFlags = syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC |
+	syscall.MS_NOATIME | syscall.MS_NODIRATIME | syscall.MS_RELATIME
+
+func remountReadOnly(mp string) error {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(mp, &st); err != nil {
+		if errors.Is(err, syscall.ENOENT) || errors.Is(err, syscall.EACCES) {
+			return nil // unreachable for the agent as well
+		}
+		return err
+	}
+	if st.Flags&syscall.MS_RDONLY != 0 {
+		return nil
+	}
+	flags := uintptr(st.Flags) & lockedMountFlags
+	return syscall.Mount("", mp, "", syscall.MS_REMOUNT|syscall.MS_BIND|syscall.MS_RDONLY|flags, "")
+}
+
+// mountPoints lists the mount points in /proc/self/mountinfo.
+func mountPoints() ([]string, error) {
+	data, err := os.ReadFile("/proc/self/mountinfo")
+	if err != nil {
+		return nil, fmt.Errorf("reading mountinfo: %w", err)
+	}
+	var out []string
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+		out = append(out, unescapeMountPath(fields[4]))
+	}
+	return out, nil
+}
+
+// unescapeMountPath decodes the octal escapes (\040 etc.) used in mountinfo.
+func unescapeMountPath(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// loopbackUp brings up lo in a fresh network namespace.
+func loopbackUp() error {
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(fd)
+	var ifr [40]byte // struct ifreq
+	copy(ifr[:syscall.IFNAMSIZ], "lo")
+	binary.NativeEndian.PutUint16(ifr[syscall.IFNAMSIZ:], syscall.IFF_UP|syscall.IFF_RUNNING)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), syscall.SIOCSIFFLAGS, uintptr(unsafe.Pointer(&ifr[0]))); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// bridge forwards a loopback TCP port to the proxy's unix socket and
+// returns the port's address.
+func bridge(socketPath string) (string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("listening for proxy bridge: %w", err)
+	}
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				up, err := net.Dial("unix", socketPath)
+				if err != nil {
+					c.Close()
+					return
+				}
+				splice(c, up)
+			}()
+		}
+	}()
+	return ln.Addr().String(), nil
+}
+
+func proxyEnv(url string) []string {
+	var env []string
+	for _, k := range []string{"HTTP_PROXY", "HTTPS_PROXY", "ALL_PROXY"} {
+		env = append(env, k+"="+url, strings.ToLower(k)+"="+url)
+	}
+	return append(env, "NO_PROXY=localhost,127.0.0.1", "no_proxy=localhost,127.0.0.1")
+}
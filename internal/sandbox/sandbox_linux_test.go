@@ -0,0 +1,133 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	RunInitIfRequested()
+	os.Exit(m.Run())
+}
+
+func runSandboxed(t *testing.T, spec *Spec, dir, script string) (string, error) {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Dir = dir
+	cleanup, err := Wrap(cmd, spec)
+	if err != nil {
+		t.Fatalf("Wrap: %v", err)
+	}
+	defer cleanup()
+	out, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		t.Skipf("user namespaces unavailable: %v", err)
+	}
+	if err != nil && strings.Contains(string(out), "adaf sandbox:") {
+		t.Skipf("sandbox init failed in this environment: %s", out)
+	}
+	return string(out), err
+}
+
+func TestWrapConfinesWrites(t *testing.T) {
+	work := t.TempDir()
+	outside, err := os.MkdirTemp(".", ".sandbox-test-")
+	if err != nil {
+		t.Fatalf("MkdirTemp: %v", err)
+	}
+	outside, _ = filepath.Abs(outside)
+	t.Cleanup(func() { os.RemoveAll(outside) })
+
+	out, err := runSandboxed(t, &Spec{}, work,
+		`echo ok > inside.txt && echo tmp > /tmp/scratch && if echo no > "`+outside+`/escape.txt"; then exit 3; fi`)
+	if err != nil {
+		t.Fatalf("sandboxed command failed: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(work, "inside.txt")); err != nil {
+		t.Fatalf("write inside workdir not visible: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "escape.txt")); !os.IsNotExist(err) {
+		t.Fatalf("write outside workdir escaped the sandbox: %v", err)
+	}
+	if _, err := os.Stat("/tmp/scratch"); !os.IsNotExist(err) {
+		t.Fatalf("sandbox /tmp is not private: %v", err)
+	}
+}
+
+func TestWrapReadOnlyAndExtraWritable(t *testing.T) {
+	work := t.TempDir()
+	extra := t.TempDir()
+	out, err := runSandboxed(t, &Spec{ReadOnly: true, Writable: []string{extra}}, work,
+		`if echo no > denied.txt; then exit 3; fi; echo ok > "`+extra+`/allowed.txt"`)
+	if err != nil {
+		t.Fatalf("sandboxed command failed: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(work, "denied.txt")); !os.IsNotExist(err) {
+		t.Fatalf("read-only workdir was written: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(extra, "allowed.txt")); err != nil {
+		t.Fatalf("extra writable path not writable: %v", err)
+	}
+}
+
+func TestWrapNetworkNone(t *testing.T) {
+	out, err := runSandboxed(t, &Spec{Network: "none"}, t.TempDir(), `cat /proc/net/dev`)
+	if err != nil {
+		t.Fatalf("sandboxed command failed: %v\n%s", err, out)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		name, _, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok && name != "lo" {
+			t.Fatalf("unexpected interface %q in isolated network namespace:\n%s", name, out)
+		}
+	}
+}
+
+func TestWrapPreservesExitCode(t *testing.T) {
+	out, err := runSandboxed(t, &Spec{}, t.TempDir(), `exit 7`)
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 7 {
+		t.Fatalf("exit error = %v, want exit status 7\n%s", err, out)
+	}
+}
+
+func TestWrapWorktreeCanCommitButNotTouchHooks(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	wt := filepath.Join(t.TempDir(), "wt")
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=T", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git(repo, "init", "-q")
+	git(repo, "commit", "-q", "--allow-empty", "-m", "init")
+	git(repo, "worktree", "add", "-q", "-b", "child", wt)
+
+	out, err := runSandboxed(t, &Spec{}, wt,
+		`echo x > f && git add f && git -c user.name=T -c user.email=t@example.com commit -q -m child && `+
+			`if echo evil > "$(git rev-parse --git-common-dir)/hooks/post-merge"; then exit 3; fi`)
+	if err != nil {
+		t.Fatalf("sandboxed command failed: %v\n%s", err, out)
+	}
+	cmd := exec.Command("git", "log", "-1", "--format=%s", "child")
+	cmd.Dir = repo
+	if msg, _ := cmd.Output(); strings.TrimSpace(string(msg)) != "child" {
+		t.Fatalf("commit from sandboxed worktree not recorded: %q", msg)
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".git", "hooks", "post-merge")); !os.IsNotExist(err) {
+		t.Fatalf("sandboxed agent wrote a git hook: %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// Supported reports whether this platform can run sandboxed commands.
+func Supported() bool { return false }
+
+// Wrap fails on platforms without Linux namespaces; a configured sandbox is
+// never silently skipped.
+func Wrap(cmd *exec.Cmd, spec *Spec) (func(), error) {
+	if spec == nil {
+		return func() {}, nil
+	}
+	return func() {}, fmt.Errorf("sandbox: not supported on %s", runtime.GOOS)
+}
+
+// RunInitIfRequested is a no-op on platforms without sandbox support.
+func RunInitIfRequested() {}
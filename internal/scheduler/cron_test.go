@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"1,,2 * * * *",
+		"@yearly-ish",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	loc := time.Local
+	base := time.Date(2026, 3, 4, 10, 7, 30, 0, loc) // Wednesday
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"* * * * *", base, time.Date(2026, 3, 4, 10, 8, 0, 0, loc)},
+		{"*/15 * * * *", base, time.Date(2026, 3, 4, 10, 15, 0, 0, loc)},
+		{"0 2 * * *", base, time.Date(2026, 3, 5, 2, 0, 0, 0, loc)},
+		{"30 9-17/4 * * *", base, time.Date(2026, 3, 4, 13, 30, 0, 0, loc)},
+		{"0 0 * * 0", base, time.Date(2026, 3, 8, 0, 0, 0, 0, loc)},
+		{"0 0 * * 7", base, time.Date(2026, 3, 8, 0, 0, 0, 0, loc)},
+		{"0 9 * * 1-5", time.Date(2026, 3, 6, 9, 0, 0, 0, loc), time.Date(2026, 3, 9, 9, 0, 0, 0, loc)},
+		{"0 0 1 * 5", base, time.Date(2026, 3, 6, 0, 0, 0, 0, loc)},
+		{"@monthly", base, time.Date(2026, 4, 1, 0, 0, 0, 0, loc)},
+		{"@hourly", base, time.Date(2026, 3, 4, 11, 0, 0, 0, loc)},
+		{"0 0 29 2 *", base, time.Date(2028, 2, 29, 0, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		if got := c.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%q.Next(%s) = %s, want %s", tt.expr, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestCronNextImpossibleDate(t *testing.T) {
+	c, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron: %v", err)
+	}
+	if got := c.Next(time.Now()); !got.IsZero() {
+		t.Fatalf("Next = %s, want zero time", got)
+	}
+}
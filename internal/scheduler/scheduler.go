@@ -0,0 +1,287 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/session"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+const (
+	// DefaultInterval is how often Run evaluates schedules.
+	DefaultInterval = 30 * time.Second
+	// DefaultGrace is how late a fire time may be handled before it counts as missed.
+	DefaultGrace = 2 * time.Minute
+	// maxFiresPerCheck bounds how many past fire times one evaluation walks.
+	maxFiresPerCheck = 10000
+)
+
+// Scheduler evaluates the loop schedules of a set of projects and starts a
+// detached loop session for each one that comes due.
+type Scheduler struct {
+	// Stores returns the project stores whose schedules should be evaluated.
+	Stores func() []*store.Store
+	// StartLoop starts a detached loop session for sch and returns its
+	// session ID. Defaults to StartLoopSession.
+	StartLoop func(s *store.Store, sch *store.LoopSchedule) (int, error)
+	// LoopRunning reports whether a session for the named loop is live in
+	// the project of s. Defaults to loopHasRunningSession.
+	LoopRunning func(s *store.Store, loopName string) bool
+	// Grace defaults to DefaultGrace.
+	Grace time.Duration
+}
+
+// Run evaluates schedules every interval until ctx is done.
+func (sc *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	sc.Tick(time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			sc.Tick(now)
+		}
+	}
+}
+
+// Tick evaluates every schedule of every project against now.
+func (sc *Scheduler) Tick(now time.Time) {
+	if sc.Stores == nil {
+		return
+	}
+	for _, s := range sc.Stores() {
+		schedules, err := s.ListSchedules()
+		if err != nil {
+			debug.LogKV("scheduler", "listing schedules failed", "project", s.ProjectDir(), "error", err)
+			continue
+		}
+		for i := range schedules {
+			sc.evaluate(s, &schedules[i], now)
+		}
+	}
+}
+
+// evaluate handles every fire time of sch between its last check and now.
+// Only the most recent fire time within the grace window starts a session;
+// older ones are recorded as a single grouped missed event.
+func (sc *Scheduler) evaluate(s *store.Store, sch *store.LoopSchedule, now time.Time) {
+	if sch.Disabled {
+		return
+	}
+	cron, err := ParseCron(sch.Cron)
+	if err != nil {
+		debug.LogKV("scheduler", "invalid cron", "schedule_id", sch.ID, "cron", sch.Cron, "error", err)
+		return
+	}
+
+	from := sch.LastCheckedAt
+	if from.IsZero() {
+		from = sch.CreatedAt
+	}
+	var events []store.ScheduleEvent
+	var fires []time.Time
+	for t := cron.Next(from); !t.IsZero() && !t.After(now); t = cron.Next(t) {
+		fires = append(fires, t)
+		if len(fires) >= maxFiresPerCheck {
+			break
+		}
+	}
+	if len(fires) == 0 {
+		return
+	}
+
+	grace := sc.Grace
+	if grace <= 0 {
+		grace = DefaultGrace
+	}
+	due := fires[len(fires)-1]
+	missed := fires[:len(fires)-1]
+	if now.Sub(due) > grace {
+		missed = fires
+		due = time.Time{}
+	}
+	if len(missed) > 0 {
+		events = append(events, store.ScheduleEvent{
+			At:      missed[0].UTC(),
+			Outcome: store.ScheduleOutcomeMissed,
+			Count:   len(missed),
+			Detail:  fmt.Sprintf("last missed run at %s", missed[len(missed)-1].UTC().Format(time.RFC3339)),
+		})
+		debug.LogKV("scheduler", "missed runs", "schedule_id", sch.ID, "count", len(missed))
+	}
+	if !due.IsZero() {
+		events = append(events, sc.fire(s, sch, due))
+	}
+
+	// sch may be stale by now; only the bookkeeping is written back.
+	if err := s.RecordScheduleCheck(sch.ID, now, events...); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			debug.LogKV("scheduler", "schedule deleted during evaluation", "schedule_id", sch.ID)
+			return
+		}
+		debug.LogKV("scheduler", "saving schedule failed", "schedule_id", sch.ID, "error", err)
+	}
+}
+
+func (sc *Scheduler) fire(s *store.Store, sch *store.LoopSchedule, at time.Time) store.ScheduleEvent {
+	ev := store.ScheduleEvent{At: at.UTC()}
+
+	running := sc.LoopRunning
+	if running == nil {
+		running = loopHasRunningSession
+	}
+	if running(s, sch.Loop) {
+		ev.Outcome = store.ScheduleOutcomeSkipped
+		ev.Detail = fmt.Sprintf("loop %q is already running", sch.Loop)
+		debug.LogKV("scheduler", "skipping schedule; loop already running", "schedule_id", sch.ID, "loop", sch.Loop)
+		return ev
+	}
+
+	start := sc.StartLoop
+	if start == nil {
+		start = StartLoopSession
+	}
+	sessionID, err := start(s, sch)
+	if err != nil {
+		ev.Outcome = store.ScheduleOutcomeFailed
+		ev.Detail = err.Error()
+		debug.LogKV("scheduler", "starting scheduled loop failed", "schedule_id", sch.ID, "loop", sch.Loop, "error", err)
+		return ev
+	}
+	ev.Outcome = store.ScheduleOutcomeStarted
+	ev.SessionID = sessionID
+	debug.LogKV("scheduler", "scheduled loop started", "schedule_id", sch.ID, "loop", sch.Loop, "session_id", sessionID)
+	return ev
+}
+
+// loopHasRunningSession reports whether a live session runs loopName in the
+// project of s; the same loop running in another project does not count.
+// Lookup errors are treated as running, so a schedule never stacks another
+// session on top of an unknown state.
+func loopHasRunningSession(s *store.Store, loopName string) bool {
+	active, err := session.ListActiveSessions()
+	if err != nil {
+		return true
+	}
+	// Scheduled sessions run in the project's repo path; sessions started
+	// elsewhere record the project directory.
+	projectIDs := map[string]bool{session.ProjectIDFromDir(s.ProjectDir()): true}
+	if projCfg, err := s.LoadProject(); err == nil && projCfg.RepoPath != "" {
+		projectIDs[session.ProjectIDFromDir(projCfg.RepoPath)] = true
+	}
+	for _, meta := range active {
+		if !strings.EqualFold(strings.TrimSpace(meta.LoopName), strings.TrimSpace(loopName)) {
+			continue
+		}
+		id := meta.ProjectID
+		if id == "" && meta.ProjectDir != "" {
+			id = session.ProjectIDFromDir(meta.ProjectDir)
+		}
+		if projectIDs[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateSchedule checks a schedule's cron expression and loop reference.
+func ValidateSchedule(sch *store.LoopSchedule, cfg *config.GlobalConfig) error {
+	if _, err := ParseCron(sch.Cron); err != nil {
+		return err
+	}
+	if strings.TrimSpace(sch.Loop) == "" {
+		return fmt.Errorf("loop is required")
+	}
+	if cfg != nil && cfg.FindLoop(sch.Loop) == nil {
+		return fmt.Errorf("loop %q not found", sch.Loop)
+	}
+	if sch.MaxCycles < 0 {
+		return fmt.Errorf("max cycles must be >= 0")
+	}
+	return nil
+}
+
+// NextRun returns the next fire time of sch after now, or the zero time when
+// the schedule is disabled or invalid.
+func NextRun(sch *store.LoopSchedule, now time.Time) time.Time {
+	if sch.Disabled {
+		return time.Time{}
+	}
+	cron, err := ParseCron(sch.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	return cron.Next(now)
+}
+
+// StartLoopSession snapshots the schedule's loop definition and profiles
+// from the global config and starts it as a detached loop session daemon.
+func StartLoopSession(s *store.Store, sch *store.LoopSchedule) (int, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return 0, fmt.Errorf("loading config: %w", err)
+	}
+	loopDef := cfg.FindLoop(sch.Loop)
+	if loopDef == nil {
+		return 0, fmt.Errorf("loop %q not found", sch.Loop)
+	}
+	loopDefCopy := *loopDef
+	priority, err := config.ParseResourcePriority(loopDefCopy.ResourcePriority)
+	if err != nil {
+		return 0, fmt.Errorf("loop %q %w", loopDefCopy.Name, err)
+	}
+	loopDefCopy.ResourcePriority = priority
+	profiles, err := cfg.PrepareLoopLaunch(&loopDefCopy)
+	if err != nil {
+		return 0, err
+	}
+
+	projCfg, err := s.LoadProject()
+	if err != nil {
+		return 0, fmt.Errorf("loading project: %w", err)
+	}
+	planID := strings.TrimSpace(sch.PlanID)
+	if planID == "" {
+		planID = projCfg.ActivePlanID
+	}
+	workDir := projCfg.RepoPath
+	if workDir == "" {
+		workDir = s.ProjectDir()
+	}
+
+	dcfg := session.DaemonConfig{
+		ProjectDir:    workDir,
+		ProjectName:   projCfg.Name,
+		WorkDir:       workDir,
+		PlanID:        planID,
+		ProfileName:   loopDefCopy.Name,
+		AgentName:     "loop",
+		Loop:          loopDefCopy,
+		Profiles:      profiles,
+		Pushover:      cfg.Pushover,
+		MaxCycles:     sch.MaxCycles,
+		InitialPrompt: strings.TrimSpace(sch.InitialPrompt),
+	}
+	sessionID, err := session.CreateSession(dcfg)
+	if err != nil {
+		return 0, fmt.Errorf("creating loop session: %w", err)
+	}
+	if err := session.StartDaemon(sessionID); err != nil {
+		session.AbortSessionStartup(sessionID, "scheduled loop daemon failed: "+err.Error())
+		return 0, fmt.Errorf("starting loop daemon: %w", err)
+	}
+	return sessionID, nil
+}
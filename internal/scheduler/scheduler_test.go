@@ -0,0 +1,269 @@
+package scheduler
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/session"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+type fakeLauncher struct {
+	started []int
+	running bool
+	err     error
+	nextID  int
+}
+
+func (f *fakeLauncher) scheduler(s *store.Store) *Scheduler {
+	return &Scheduler{
+		Stores: func() []*store.Store { return []*store.Store{s} },
+		StartLoop: func(_ *store.Store, sch *store.LoopSchedule) (int, error) {
+			if f.err != nil {
+				return 0, f.err
+			}
+			f.nextID++
+			f.started = append(f.started, sch.ID)
+			return f.nextID, nil
+		},
+		LoopRunning: func(*store.Store, string) bool { return f.running },
+		Grace:       2 * time.Minute,
+	}
+}
+
+func newScheduleStore(t *testing.T) *store.Store {
+	t.Helper()
+	dir := t.TempDir()
+	s, err := store.New(dir)
+	if err != nil {
+		t.Fatalf("store.New: %v", err)
+	}
+	if err := s.Init(store.ProjectConfig{Name: "sched-test", RepoPath: dir}); err != nil {
+		t.Fatalf("store.Init: %v", err)
+	}
+	return s
+}
+
+func createSchedule(t *testing.T, s *store.Store, cron string, lastChecked time.Time) *store.LoopSchedule {
+	t.Helper()
+	sch := &store.LoopSchedule{Cron: cron, Loop: "dev", LastCheckedAt: lastChecked}
+	if err := s.CreateSchedule(sch); err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+	return sch
+}
+
+func reload(t *testing.T, s *store.Store, id int) *store.LoopSchedule {
+	t.Helper()
+	sch, err := s.GetSchedule(id)
+	if err != nil {
+		t.Fatalf("GetSchedule: %v", err)
+	}
+	return sch
+}
+
+func TestTickStartsDueSchedule(t *testing.T) {
+	s := newScheduleStore(t)
+	now := time.Date(2026, 5, 1, 10, 0, 30, 0, time.Local)
+	sch := createSchedule(t, s, "0 * * * *", now.Add(-5*time.Minute))
+
+	f := &fakeLauncher{}
+	f.scheduler(s).Tick(now)
+
+	if len(f.started) != 1 {
+		t.Fatalf("started = %v, want one run", f.started)
+	}
+	got := reload(t, s, sch.ID)
+	if len(got.History) != 1 || got.History[0].Outcome != store.ScheduleOutcomeStarted {
+		t.Fatalf("history = %+v, want one started event", got.History)
+	}
+	if got.LastSessionID != 1 || !got.LastCheckedAt.Equal(now) {
+		t.Fatalf("LastSessionID=%d LastCheckedAt=%s", got.LastSessionID, got.LastCheckedAt)
+	}
+
+	// A second tick in the same minute must not fire again.
+	f.scheduler(s).Tick(now.Add(10 * time.Second))
+	if len(f.started) != 1 {
+		t.Fatalf("started = %v after second tick, want one run", f.started)
+	}
+}
+
+func TestTickSkipsWhenLoopRunning(t *testing.T) {
+	s := newScheduleStore(t)
+	now := time.Date(2026, 5, 1, 10, 0, 30, 0, time.Local)
+	sch := createSchedule(t, s, "0 * * * *", now.Add(-time.Minute))
+
+	f := &fakeLauncher{running: true}
+	f.scheduler(s).Tick(now)
+
+	if len(f.started) != 0 {
+		t.Fatalf("started = %v, want none", f.started)
+	}
+	got := reload(t, s, sch.ID)
+	if len(got.History) != 1 || got.History[0].Outcome != store.ScheduleOutcomeSkipped {
+		t.Fatalf("history = %+v, want one skipped event", got.History)
+	}
+}
+
+func TestTickKeepsEditsMadeWhileFiring(t *testing.T) {
+	s := newScheduleStore(t)
+	now := time.Date(2026, 5, 1, 10, 0, 30, 0, time.Local)
+	sch := createSchedule(t, s, "0 * * * *", now.Add(-time.Minute))
+
+	sc := (&fakeLauncher{}).scheduler(s)
+	sc.StartLoop = func(s *store.Store, _ *store.LoopSchedule) (int, error) {
+		edited := reload(t, s, sch.ID)
+		edited.Name = "nightly"
+		edited.MaxCycles = 3
+		if err := s.UpdateSchedule(edited); err != nil {
+			t.Fatalf("UpdateSchedule: %v", err)
+		}
+		return 7, nil
+	}
+	sc.Tick(now)
+
+	got := reload(t, s, sch.ID)
+	if got.Name != "nightly" || got.MaxCycles != 3 {
+		t.Fatalf("schedule = %+v, want the concurrent edit kept", got)
+	}
+	if got.LastSessionID != 7 || len(got.History) != 1 || !got.LastCheckedAt.Equal(now) {
+		t.Fatalf("bookkeeping = session %d, history %+v, checked %s", got.LastSessionID, got.History, got.LastCheckedAt)
+	}
+}
+
+func TestTickDoesNotRecreateDeletedSchedule(t *testing.T) {
+	s := newScheduleStore(t)
+	now := time.Date(2026, 5, 1, 10, 0, 30, 0, time.Local)
+	sch := createSchedule(t, s, "0 * * * *", now.Add(-time.Minute))
+
+	sc := (&fakeLauncher{}).scheduler(s)
+	sc.StartLoop = func(s *store.Store, _ *store.LoopSchedule) (int, error) {
+		if err := s.DeleteSchedule(sch.ID); err != nil {
+			t.Fatalf("DeleteSchedule: %v", err)
+		}
+		return 1, nil
+	}
+	sc.Tick(now)
+
+	if list, _ := s.ListSchedules(); len(list) != 0 {
+		t.Fatalf("schedules = %+v, want the deleted schedule to stay deleted", list)
+	}
+}
+
+func TestLoopHasRunningSessionIsProjectScoped(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	s := newScheduleStore(t)
+	startLiveLoopSession := func(projectDir string) {
+		t.Helper()
+		id, err := session.CreateSession(session.DaemonConfig{ProjectDir: projectDir, Loop: config.LoopDef{Name: "dev"}})
+		if err != nil {
+			t.Fatalf("CreateSession: %v", err)
+		}
+		meta, err := session.LoadMeta(id)
+		if err != nil {
+			t.Fatalf("LoadMeta: %v", err)
+		}
+		meta.Status = session.StatusRunning
+		meta.PID = os.Getpid()
+		if err := session.SaveMeta(id, meta); err != nil {
+			t.Fatalf("SaveMeta: %v", err)
+		}
+	}
+
+	startLiveLoopSession(t.TempDir())
+	if loopHasRunningSession(s, "dev") {
+		t.Fatal("a loop running in another project must not block this project's schedule")
+	}
+	startLiveLoopSession(s.ProjectDir())
+	if !loopHasRunningSession(s, "dev") {
+		t.Fatal("the loop running in this project must block the schedule")
+	}
+}
+
+func TestTickGroupsMissedRuns(t *testing.T) {
+	s := newScheduleStore(t)
+	now := time.Date(2026, 5, 1, 10, 0, 30, 0, time.Local)
+	// Down since 06:30: 07:00, 08:00 and 09:00 are missed, 10:00 is due.
+	sch := createSchedule(t, s, "0 * * * *", now.Add(-4*time.Hour))
+
+	f := &fakeLauncher{}
+	f.scheduler(s).Tick(now)
+
+	if len(f.started) != 1 {
+		t.Fatalf("started = %v, want only the latest run", f.started)
+	}
+	got := reload(t, s, sch.ID)
+	if len(got.History) != 2 {
+		t.Fatalf("history = %+v, want missed + started", got.History)
+	}
+	if got.History[0].Outcome != store.ScheduleOutcomeMissed || got.History[0].Count != 3 {
+		t.Fatalf("missed event = %+v, want count 3", got.History[0])
+	}
+	if got.History[1].Outcome != store.ScheduleOutcomeStarted {
+		t.Fatalf("second event = %+v, want started", got.History[1])
+	}
+}
+
+func TestTickOutsideGraceOnlyRecordsMissed(t *testing.T) {
+	s := newScheduleStore(t)
+	now := time.Date(2026, 5, 1, 10, 30, 0, 0, time.Local)
+	sch := createSchedule(t, s, "0 * * * *", now.Add(-time.Hour))
+
+	f := &fakeLauncher{}
+	f.scheduler(s).Tick(now)
+
+	if len(f.started) != 0 {
+		t.Fatalf("started = %v, want none", f.started)
+	}
+	got := reload(t, s, sch.ID)
+	if len(got.History) != 1 || got.History[0].Outcome != store.ScheduleOutcomeMissed || got.History[0].Count != 1 {
+		t.Fatalf("history = %+v, want one missed event", got.History)
+	}
+}
+
+func TestTickRecordsFailureAndIgnoresDisabled(t *testing.T) {
+	s := newScheduleStore(t)
+	now := time.Date(2026, 5, 1, 10, 0, 30, 0, time.Local)
+	failing := createSchedule(t, s, "0 * * * *", now.Add(-time.Minute))
+	disabled := createSchedule(t, s, "0 * * * *", now.Add(-time.Minute))
+	store.SetScheduleEnabled(disabled, false)
+	if err := s.UpdateSchedule(disabled); err != nil {
+		t.Fatalf("UpdateSchedule: %v", err)
+	}
+
+	f := &fakeLauncher{err: errors.New("boom")}
+	f.scheduler(s).Tick(now)
+
+	got := reload(t, s, failing.ID)
+	if len(got.History) != 1 || got.History[0].Outcome != store.ScheduleOutcomeFailed || got.History[0].Detail != "boom" {
+		t.Fatalf("history = %+v, want one failed event", got.History)
+	}
+	if got := reload(t, s, disabled.ID); len(got.History) != 0 {
+		t.Fatalf("disabled schedule history = %+v, want none", got.History)
+	}
+}
+
+func TestStartLoopSessionValidatesLoopLikeLoopStart(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := config.Save(&config.GlobalConfig{
+		Profiles: []config.Profile{{Name: "dev", Agent: "codex"}},
+		Loops: []config.LoopDef{{
+			Name:   "dev",
+			Steps:  []config.LoopStep{{Profile: "dev", Turns: 1}},
+			Budget: &config.Budget{MaxCostUSD: -1},
+		}},
+	}); err != nil {
+		t.Fatalf("config.Save: %v", err)
+	}
+	s := newScheduleStore(t)
+	sch := createSchedule(t, s, "0 * * * *", time.Now())
+
+	_, err := StartLoopSession(s, sch)
+	if err == nil || !strings.Contains(err.Error(), "max_cost_usd") {
+		t.Fatalf("StartLoopSession error = %v, want budget validation error", err)
+	}
+}
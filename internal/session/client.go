@@ -14,6 +14,7 @@ import (
 	"github.com/agusx1211/adaf/internal/agent"
 	"github.com/agusx1211/adaf/internal/debug"
 	"github.com/agusx1211/adaf/internal/events"
+	"github.com/agusx1211/adaf/internal/store"
 	"github.com/agusx1211/adaf/internal/stream"
 )
 
@@ -202,6 +203,7 @@ func (c *Client) StreamEvents(eventCh chan<- any, isLive func()) error {
 						Question:      s.Question,
 						Summary:       s.Summary,
 						Result:        s.Result,
+						QueuePosition: s.QueuePosition,
 					}
 				}
 				snapshot.Spawns = spawns
@@ -355,6 +357,7 @@ func (c *Client) forwardEventMsg(eventCh chan<- any, msg *WireMsg, loopDoneSeen
 				Question:      s.Question,
 				Summary:       s.Summary,
 				Result:        s.Result,
+				QueuePosition: s.QueuePosition,
 			}
 		}
 		eventCh <- events.SpawnStatusMsg{Spawns: spawns}
@@ -374,6 +377,7 @@ func (c *Client) forwardEventMsg(eventCh chan<- any, msg *WireMsg, loopDoneSeen
 			Profile:    data.Profile,
 			Turns:      data.Turns,
 			TotalSteps: data.TotalSteps,
+			Members:    loopStepMembersFromWire(data.Members),
 		}
 		return false
 
@@ -390,9 +394,52 @@ func (c *Client) forwardEventMsg(eventCh chan<- any, msg *WireMsg, loopDoneSeen
 			StepIndex:  data.StepIndex,
 			Profile:    data.Profile,
 			TotalSteps: data.TotalSteps,
+			Members:    loopStepMembersFromWire(data.Members),
 		}
 		return false
 
+	case MsgBudget:
+		data, err := DecodeData[WireBudget](msg)
+		if err != nil {
+			return false
+		}
+		eventCh <- events.LoopBudgetMsg{
+			RunID: data.RunID,
+			Budget: store.LoopRunBudget{
+				CostUSD:        data.CostUSD,
+				Tokens:         data.Tokens,
+				MaxCostUSD:     data.MaxCostUSD,
+				MaxTokens:      data.MaxTokens,
+				State:          data.State,
+				StepIndex:      data.StepIndex,
+				StepCostUSD:    data.StepCostUSD,
+				StepTokens:     data.StepTokens,
+				StepMaxCostUSD: data.StepMaxCostUSD,
+				StepMaxTokens:  data.StepMaxTokens,
+				StepState:      data.StepState,
+			},
+		}
+		return false
+
+	case MsgUsagePause:
+		data, err := DecodeData[WireUsagePause](msg)
+		if err != nil {
+			return false
+		}
+		ev := events.LoopUsagePauseMsg{RunID: data.RunID}
+		if !data.Until.IsZero() {
+			ev.Pause = &store.UsagePause{
+				Profile:        data.Profile,
+				Provider:       data.Provider,
+				Limit:          data.Limit,
+				UtilizationPct: data.UtilizationPct,
+				Until:          data.Until,
+				StartedAt:      data.StartedAt,
+			}
+		}
+		eventCh <- ev
+		return false
+
 	case MsgLoopDone:
 		data, err := DecodeData[WireLoopDone](msg)
 		if err != nil {
@@ -467,3 +514,19 @@ func (c *Client) Close() error {
 	c.cancel()
 	return c.ws.Close(websocket.StatusNormalClosure, "detach")
 }
+
+func loopStepMembersFromWire(members []WireLoopStepMember) []events.LoopStepMember {
+	if len(members) == 0 {
+		return nil
+	}
+	out := make([]events.LoopStepMember, len(members))
+	for i, m := range members {
+		out[i] = events.LoopStepMember{
+			Profile: m.Profile,
+			Status:  m.Status,
+			TurnID:  m.TurnID,
+			Branch:  m.Branch,
+		}
+	}
+	return out
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/coder/websocket"
 
 	"github.com/agusx1211/adaf/internal/agent"
+	"github.com/agusx1211/adaf/internal/budget"
 	"github.com/agusx1211/adaf/internal/config"
 	"github.com/agusx1211/adaf/internal/debug"
 	"github.com/agusx1211/adaf/internal/events"
@@ -278,6 +279,7 @@ type broadcaster struct {
 	snapshotRecentN  int
 	lastModel        string
 	lastLoopDone     *WireLoopDone
+	lastBudget       WireBudget
 	lastDone         *WireDone
 	eventsWriteError bool
 
@@ -580,6 +582,7 @@ func (b *broadcaster) updateSnapshotLocked(msg *WireMsg, payload any) {
 			Status:       "running",
 			Action:       action,
 			StartedAt:    startedAt,
+			Budget:       b.lastBudget,
 		}
 		if !resumed {
 			b.clearSnapshotRecentLocked()
@@ -644,6 +647,7 @@ func (b *broadcaster) updateSnapshotLocked(msg *WireMsg, payload any) {
 				Status:    "running",
 				Action:    "responding",
 				StartedAt: time.Now().UTC(),
+				Budget:    b.lastBudget,
 			}
 		} else if data.SessionID > 0 && b.snapshot.Session != nil {
 			b.snapshot.Session.Action = "responding"
@@ -663,6 +667,7 @@ func (b *broadcaster) updateSnapshotLocked(msg *WireMsg, payload any) {
 				Agent:     b.meta.AgentName,
 				Profile:   b.snapshot.Loop.Profile,
 				Model:     b.lastModel,
+				Budget:    b.lastBudget,
 			}
 		}
 		if data.TurnHexID != "" {
@@ -745,6 +750,26 @@ func (b *broadcaster) updateSnapshotLocked(msg *WireMsg, payload any) {
 		cp := data
 		b.lastLoopDone = &cp
 
+	case MsgBudget:
+		data, ok := decodeWireData[WireBudget](msg, payload)
+		if !ok {
+			return
+		}
+		b.lastBudget = data
+		if b.snapshot.Session != nil {
+			b.snapshot.Session.Budget = data
+		}
+
+	case MsgUsagePause:
+		data, ok := decodeWireData[WireUsagePause](msg, payload)
+		if !ok {
+			return
+		}
+		if data.Until.IsZero() {
+			data = WireUsagePause{}
+		}
+		b.snapshot.Loop.UsagePause = data
+
 	case MsgDone:
 		data, ok := decodeWireData[WireDone](msg, payload)
 		if !ok {
@@ -1147,6 +1172,10 @@ func (b *broadcaster) runLoop(ctx context.Context, cfg *DaemonConfig) error {
 				ReadOnly:             req.Spawn.ReadOnly,
 				Wait:                 req.Spawn.Wait,
 				Delegation:           req.Spawn.Delegation,
+				Checks:               req.Spawn.Checks,
+				VerifyRetries:        req.Spawn.VerifyRetries,
+				Host:                 req.Spawn.Host,
+				Priority:             req.Spawn.Priority,
 			}
 
 			spawnID, err := orch.Spawn(ctx, spawnReq)
@@ -1275,6 +1304,7 @@ func (b *broadcaster) runLoop(ctx context.Context, cfg *DaemonConfig) error {
 						Question:      sp.Question,
 						Summary:       sp.Summary,
 						Result:        sp.Result,
+						QueuePosition: sp.QueuePosition,
 					}
 				}
 				b.broadcastTyped(MsgSpawn, WireSpawn{Spawns: spawns})
@@ -1294,6 +1324,7 @@ func (b *broadcaster) runLoop(ctx context.Context, cfg *DaemonConfig) error {
 					Profile:    ev.Profile,
 					Turns:      ev.Turns,
 					TotalSteps: totalSteps,
+					Members:    wireLoopStepMembers(ev.Members),
 				})
 			case events.LoopStepEndMsg:
 				if ev.RunID > 0 {
@@ -1310,6 +1341,33 @@ func (b *broadcaster) runLoop(ctx context.Context, cfg *DaemonConfig) error {
 					StepIndex:  ev.StepIndex,
 					Profile:    ev.Profile,
 					TotalSteps: totalSteps,
+					Members:    wireLoopStepMembers(ev.Members),
+				})
+			case events.LoopUsagePauseMsg:
+				pause := WireUsagePause{RunID: ev.RunID}
+				if p := ev.Pause; p != nil {
+					pause.Profile = p.Profile
+					pause.Provider = p.Provider
+					pause.Limit = p.Limit
+					pause.UtilizationPct = p.UtilizationPct
+					pause.Until = p.Until
+					pause.StartedAt = p.StartedAt
+				}
+				b.broadcastTyped(MsgUsagePause, pause)
+			case events.LoopBudgetMsg:
+				b.broadcastTyped(MsgBudget, WireBudget{
+					RunID:          ev.RunID,
+					CostUSD:        ev.Budget.CostUSD,
+					Tokens:         ev.Budget.Tokens,
+					MaxCostUSD:     ev.Budget.MaxCostUSD,
+					MaxTokens:      ev.Budget.MaxTokens,
+					State:          ev.Budget.State,
+					StepIndex:      ev.Budget.StepIndex,
+					StepCostUSD:    ev.Budget.StepCostUSD,
+					StepTokens:     ev.Budget.StepTokens,
+					StepMaxCostUSD: ev.Budget.StepMaxCostUSD,
+					StepMaxTokens:  ev.Budget.StepMaxTokens,
+					StepState:      ev.Budget.StepState,
 				})
 			}
 		}
@@ -1384,12 +1442,30 @@ func donePayloadError(loopErr error) string {
 	return loopErr.Error()
 }
 
+func wireLoopStepMembers(members []events.LoopStepMember) []WireLoopStepMember {
+	if len(members) == 0 {
+		return nil
+	}
+	out := make([]WireLoopStepMember, len(members))
+	for i, m := range members {
+		out[i] = WireLoopStepMember{
+			Profile: m.Profile,
+			Status:  m.Status,
+			TurnID:  m.TurnID,
+			Branch:  m.Branch,
+		}
+	}
+	return out
+}
+
 func classifyLoopDoneReason(loopErr error) string {
 	switch {
 	case loopErr == nil:
 		return "stopped"
 	case errors.Is(loopErr, context.Canceled):
 		return "cancelled"
+	case errors.As(loopErr, new(*budget.ExceededError)):
+		return "budget_exceeded"
 	default:
 		return "error"
 	}
@@ -506,6 +506,71 @@ func TestSnapshotSessionActionTracksMsgEventAsResponding(t *testing.T) {
 	}
 }
 
+func TestSnapshotSessionCarriesLoopBudget(t *testing.T) {
+	eventsPath := filepath.Join(t.TempDir(), "events.jsonl")
+	eventsFile, err := os.OpenFile(eventsPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		t.Fatalf("open events file: %v", err)
+	}
+	defer eventsFile.Close()
+
+	b := &broadcaster{
+		eventsFile: eventsFile,
+		meta:       WireMeta{SessionID: 122, AgentName: "claude"},
+	}
+	// Budget published before the first turn starts must survive the new session.
+	b.broadcastTyped(MsgBudget, WireBudget{RunID: 3, CostUSD: 1.5, MaxCostUSD: 2, State: "winding_down"})
+	b.broadcastTyped(MsgStarted, WireStarted{SessionID: 43})
+	b.broadcastTyped(MsgBudget, WireBudget{RunID: 3, CostUSD: 1.75, MaxCostUSD: 2, State: "winding_down", StepTokens: 900})
+
+	ws := wsTestServer(t, b)
+	readWSMsg(t, ws) // meta
+
+	snapshot, err := DecodeData[WireSnapshot](readWSMsg(t, ws))
+	if err != nil {
+		t.Fatalf("DecodeData[WireSnapshot]: %v", err)
+	}
+	if snapshot == nil || snapshot.Session == nil {
+		t.Fatal("missing snapshot session")
+	}
+	got := snapshot.Session.Budget
+	if got.CostUSD != 1.75 || got.StepTokens != 900 || got.State != "winding_down" {
+		t.Fatalf("snapshot session budget = %+v, want latest budget", got)
+	}
+}
+
+func TestSnapshotLoopTracksUsagePause(t *testing.T) {
+	eventsPath := filepath.Join(t.TempDir(), "events.jsonl")
+	eventsFile, err := os.OpenFile(eventsPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		t.Fatalf("open events file: %v", err)
+	}
+	defer eventsFile.Close()
+
+	b := &broadcaster{
+		eventsFile: eventsFile,
+		meta:       WireMeta{SessionID: 123, AgentName: "claude"},
+	}
+	until := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	b.broadcastTyped(MsgLoopStepStart, WireLoopStepStart{RunID: 4, StepIndex: 1, Profile: "lead"})
+	b.broadcastTyped(MsgUsagePause, WireUsagePause{RunID: 4, Profile: "lead", Provider: "claude", Limit: "5-hour window", UtilizationPct: 96, Until: until})
+
+	b.mu.Lock()
+	got := b.snapshot.Loop.UsagePause
+	b.mu.Unlock()
+	if got.Profile != "lead" || !got.Until.Equal(until) {
+		t.Fatalf("snapshot loop usage pause = %+v, want the pause", got)
+	}
+
+	b.broadcastTyped(MsgUsagePause, WireUsagePause{RunID: 4})
+	b.mu.Lock()
+	got = b.snapshot.Loop.UsagePause
+	b.mu.Unlock()
+	if got != (WireUsagePause{}) {
+		t.Fatalf("snapshot loop usage pause after resume = %+v, want zero", got)
+	}
+}
+
 func TestLoopStepStartKeepsExistingTotalStepsWhenUnset(t *testing.T) {
 	eventsPath := filepath.Join(t.TempDir(), "events.jsonl")
 	eventsFile, err := os.OpenFile(eventsPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/store"
 )
 
 // Wire message types sent over the Unix socket.
@@ -22,6 +23,8 @@ const (
 	MsgLoopStepStart = "loop_step_start" // Loop step started
 	MsgLoopStepEnd   = "loop_step_end"   // Loop step ended
 	MsgLoopDone      = "loop_done"       // Loop finished
+	MsgBudget        = "budget"          // Loop run budget consumption
+	MsgUsagePause    = "usage_pause"     // Loop step paused for (or resumed after) provider usage limits
 	MsgDone          = "done"            // Entire agent loop completed
 	MsgLive          = "live"            // Marker: snapshot sent, now streaming live
 )
@@ -67,6 +70,20 @@ type WireSnapshotLoop struct {
 	StepIndex  int    `json:"step_index"`
 	Profile    string `json:"profile,omitempty"`
 	TotalSteps int    `json:"total_steps,omitempty"`
+	// UsagePause is set while the current step waits for usage limits.
+	UsagePause WireUsagePause `json:"usage_pause,omitzero"`
+}
+
+// WireUsagePause describes a loop step waiting for a provider usage limit to
+// reset. A zero Until means the step resumed.
+type WireUsagePause struct {
+	RunID          int       `json:"run_id,omitempty"`
+	Profile        string    `json:"profile,omitempty"`
+	Provider       string    `json:"provider,omitempty"`
+	Limit          string    `json:"limit,omitempty"`
+	UtilizationPct float64   `json:"utilization_pct,omitempty"`
+	Until          time.Time `json:"until,omitzero"`
+	StartedAt      time.Time `json:"started_at,omitzero"`
 }
 
 // WireSnapshotSession is the current turn/session state for reconnects.
@@ -85,6 +102,24 @@ type WireSnapshotSession struct {
 	Action       string    `json:"action,omitempty"`
 	StartedAt    time.Time `json:"started_at,omitempty"`
 	EndedAt      time.Time `json:"ended_at,omitempty"`
+	// Budget is the loop run's consumption across the whole spawn tree.
+	Budget WireBudget `json:"budget,omitzero"`
+}
+
+// WireBudget carries loop run and current step budget consumption.
+type WireBudget struct {
+	RunID          int     `json:"run_id,omitempty"`
+	CostUSD        float64 `json:"cost_usd,omitempty"`
+	Tokens         int     `json:"tokens,omitempty"`
+	MaxCostUSD     float64 `json:"max_cost_usd,omitempty"`
+	MaxTokens      int     `json:"max_tokens,omitempty"`
+	State          string  `json:"state,omitempty"`
+	StepIndex      int     `json:"step_index"`
+	StepCostUSD    float64 `json:"step_cost_usd,omitempty"`
+	StepTokens     int     `json:"step_tokens,omitempty"`
+	StepMaxCostUSD float64 `json:"step_max_cost_usd,omitempty"`
+	StepMaxTokens  int     `json:"step_max_tokens,omitempty"`
+	StepState      string  `json:"step_state,omitempty"`
 }
 
 // WireStarted signals that a new agent session has begun.
@@ -143,6 +178,7 @@ type WireSpawnInfo struct {
 	Question      string `json:"question,omitempty"`
 	Summary       string `json:"summary,omitempty"`
 	Result        string `json:"result,omitempty"`
+	QueuePosition int    `json:"queue_position,omitempty"`
 }
 
 // WireSpawn carries spawn hierarchy updates.
@@ -160,6 +196,16 @@ type WireLoopStepStart struct {
 	Profile    string `json:"profile"`
 	Turns      int    `json:"turns"`
 	TotalSteps int    `json:"total_steps,omitempty"`
+
+	Members []WireLoopStepMember `json:"members,omitempty"`
+}
+
+// WireLoopStepMember reports one member of a parallel loop step group.
+type WireLoopStepMember struct {
+	Profile string `json:"profile"`
+	Status  string `json:"status"`
+	TurnID  int    `json:"turn_id,omitempty"`
+	Branch  string `json:"branch,omitempty"`
 }
 
 // WireLoopStepEnd signals a loop step end.
@@ -171,13 +217,15 @@ type WireLoopStepEnd struct {
 	StepIndex  int    `json:"step_index"`
 	Profile    string `json:"profile"`
 	TotalSteps int    `json:"total_steps,omitempty"`
+
+	Members []WireLoopStepMember `json:"members,omitempty"`
 }
 
 // WireLoopDone signals the loop completion state.
 type WireLoopDone struct {
 	RunID    int    `json:"run_id,omitempty"`
 	RunHexID string `json:"run_hex_id,omitempty"`
-	Reason   string `json:"reason,omitempty"` // "stopped", "cancelled", "error"
+	Reason   string `json:"reason,omitempty"` // "stopped", "cancelled", "budget_exceeded", "error"
 	Error    string `json:"error,omitempty"`
 }
 
@@ -209,6 +257,10 @@ type WireControlSpawn struct {
 	ReadOnly             bool                     `json:"read_only,omitempty"`
 	Wait                 bool                     `json:"wait,omitempty"`
 	Delegation           *config.DelegationConfig `json:"delegation,omitempty"`
+	Checks               []store.AcceptanceCheck  `json:"checks,omitempty"`
+	VerifyRetries        int                      `json:"verify_retries,omitempty"`
+	Host                 string                   `json:"host,omitempty"`
+	Priority             string                   `json:"priority,omitempty"`
 }
 
 // WireControlWait carries a wait-for-spawns signal request.
@@ -3,6 +3,7 @@ package session
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -494,6 +495,9 @@ func FindOnlyRunningSession() (*SessionMeta, error) {
 	return &active[0], nil
 }
 
+// ErrNoRunningSession is returned by lookups that find no matching live session.
+var ErrNoRunningSession = errors.New("no running session")
+
 // FindRunningByLoopName finds a running session by its loop name (case-insensitive).
 // Returns an error if no running session matches or if multiple match.
 func FindRunningByLoopName(name string) (*SessionMeta, error) {
@@ -512,7 +516,7 @@ func FindRunningByLoopName(name string) (*SessionMeta, error) {
 	}
 
 	if len(matches) == 0 {
-		return nil, fmt.Errorf("no running session for loop %q", requested)
+		return nil, fmt.Errorf("%w for loop %q", ErrNoRunningSession, requested)
 	}
 	if len(matches) > 1 {
 		return nil, fmt.Errorf("multiple running sessions for loop %q, specify the session ID", requested)
@@ -0,0 +1,75 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// CostEstimate is the expected USD cost of one run of a profile, drawn from
+// the profile's historical averages.
+type CostEstimate struct {
+	PerRunUSD float64
+	Runs      int  // historical runs the averages come from
+	Priced    bool // average tokens priced from the model price catalog
+}
+
+// String renders the estimate for prompts and CLI output, e.g.
+// "~$0.42/run (12 runs)".
+func (e CostEstimate) String() string {
+	runs := "runs"
+	if e.Runs == 1 {
+		runs = "run"
+	}
+	return fmt.Sprintf("~$%.2f/run (%d %s)", e.PerRunUSD, e.Runs, runs)
+}
+
+// EstimateRunCost estimates one run from ps. The average tokens per run are
+// priced at the catalog price for model (the profile's last recorded model
+// when empty), so agents are compared at current prices; without a price,
+// the average recorded cost is used. Token totals that include runs recorded
+// before the cached and reasoning split undercount cache traffic, so those
+// histories also use the recorded cost. ok is false without usable history.
+func EstimateRunCost(ps *store.ProfileStats, model string, cfg *config.GlobalConfig) (CostEstimate, bool) {
+	if ps == nil || ps.TotalRuns <= 0 {
+		return CostEstimate{}, false
+	}
+	est := CostEstimate{Runs: ps.TotalRuns}
+
+	if strings.TrimSpace(model) == "" {
+		model = ps.Model
+	}
+	tokens := ps.TotalInputTok + ps.TotalCachedTok + ps.TotalOutputTok + ps.TotalReasonTok
+	if price := cfg.FindModelPrice(model); price != nil && tokens > 0 && ps.TokenRuns == ps.TotalRuns {
+		est.PerRunUSD = price.CostUSD(ps.TotalInputTok, ps.TotalCachedTok, ps.TotalOutputTok+ps.TotalReasonTok) / float64(ps.TokenRuns)
+		est.Priced = true
+		return est, true
+	}
+	if ps.TotalCostUSD <= 0 {
+		return CostEstimate{}, false
+	}
+	est.PerRunUSD = ps.TotalCostUSD / float64(ps.TotalRuns)
+	return est, true
+}
+
+// EstimateProfileCosts estimates one run of each profile from the project's
+// stats, keyed by lowercased profile name. Profiles without usable history
+// are omitted.
+func EstimateProfileCosts(st *store.Store, cfg *config.GlobalConfig, profiles []config.Profile) map[string]CostEstimate {
+	out := make(map[string]CostEstimate)
+	if st == nil {
+		return out
+	}
+	for _, prof := range profiles {
+		ps, err := st.GetProfileStats(prof.Name)
+		if err != nil {
+			continue
+		}
+		if est, ok := EstimateRunCost(ps, prof.Model, cfg); ok {
+			out[strings.ToLower(strings.TrimSpace(prof.Name))] = est
+		}
+	}
+	return out
+}
@@ -0,0 +1,55 @@
+package stats
+
+import (
+	"math"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func TestEstimateRunCost(t *testing.T) {
+	cfg := &config.GlobalConfig{ModelPrices: []config.ModelPrice{
+		{Model: "gpt-5*", Input: 1, CachedInput: 0.1, Output: 10},
+	}}
+	ps := &store.ProfileStats{
+		TotalRuns:      4,
+		TokenRuns:      4,
+		TotalCostUSD:   2,
+		TotalInputTok:  2_000_000,
+		TotalCachedTok: 10_000_000,
+		TotalOutputTok: 100_000,
+		TotalReasonTok: 100_000,
+		Model:          "gpt-5-codex",
+	}
+
+	// (2M * $1 + 10M * $0.1 + 0.2M * $10) / 4 runs
+	est, ok := EstimateRunCost(ps, "", cfg)
+	if !ok || !est.Priced || est.Runs != 4 || math.Abs(est.PerRunUSD-1.25) > 1e-9 {
+		t.Fatalf("EstimateRunCost(catalog) = %+v, %v", est, ok)
+	}
+	if got := est.String(); got != "~$1.25/run (4 runs)" {
+		t.Fatalf("String() = %q", got)
+	}
+
+	// A model without a catalog price falls back to the recorded average.
+	est, ok = EstimateRunCost(ps, "claude-opus", cfg)
+	if !ok || est.Priced || math.Abs(est.PerRunUSD-0.5) > 1e-9 {
+		t.Fatalf("EstimateRunCost(history) = %+v, %v", est, ok)
+	}
+
+	// Runs recorded before the token split fall back to the recorded average.
+	legacy := *ps
+	legacy.TotalRuns = 6
+	est, ok = EstimateRunCost(&legacy, "", cfg)
+	if !ok || est.Priced || est.Runs != 6 || math.Abs(est.PerRunUSD-2.0/6) > 1e-9 {
+		t.Fatalf("EstimateRunCost(legacy) = %+v, %v", est, ok)
+	}
+
+	if _, ok := EstimateRunCost(&store.ProfileStats{TotalRuns: 3}, "", cfg); ok {
+		t.Fatal("EstimateRunCost() estimated a profile with no cost or tokens")
+	}
+	if _, ok := EstimateRunCost(&store.ProfileStats{}, "gpt-5", cfg); ok {
+		t.Fatal("EstimateRunCost() estimated a profile with no runs")
+	}
+}
@@ -8,24 +8,30 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/agusx1211/adaf/internal/config"
 	"github.com/agusx1211/adaf/internal/store"
-	"github.com/agusx1211/adaf/internal/stream"
 )
 
 // SessionMetrics holds metrics extracted from a session's recording.
 type SessionMetrics struct {
-	TotalCostUSD float64
-	InputTokens  int
-	OutputTokens int
-	NumTurns     int
-	DurationSecs int
-	ToolCalls    map[string]int // tool_name -> invocation count
-	Success      bool
+	Agent             string
+	Model             string
+	TotalCostUSD      float64
+	CostEstimated     bool // TotalCostUSD was priced from the model price table
+	InputTokens       int  // uncached input, including cache writes
+	CachedInputTokens int  // input served from the prompt cache
+	OutputTokens      int  // output, excluding reasoning
+	ReasoningTokens   int
+	NumTurns          int
+	DurationSecs      int
+	ToolCalls         map[string]int // tool_name -> invocation count
+	Success           bool
 }
 
-// ExtractFromRecording reads the events.jsonl file for a turn and
-// parses claude_stream events to extract metrics.
-func ExtractFromRecording(st *store.Store, turnID int) (*SessionMetrics, error) {
+// ExtractFromRecording reads the events.jsonl file for a turn and parses
+// the agent's stream events to extract metrics. Turns whose agent reports
+// no cost are priced from cfg's model price table; cfg may be nil.
+func ExtractFromRecording(st *store.Store, turnID int, cfg *config.GlobalConfig) (*SessionMetrics, error) {
 	eventsPath, err := findEventsFile(st, turnID)
 	if err != nil {
 		return nil, err
@@ -40,7 +46,12 @@ func ExtractFromRecording(st *store.Store, turnID int) (*SessionMetrics, error)
 	m := &SessionMetrics{
 		ToolCalls: make(map[string]int),
 	}
+	if turn, err := st.GetTurn(turnID); err == nil {
+		m.Agent = turn.Agent
+		m.Model = turn.AgentModel
+	}
 
+	var extractor MetricsExtractor
 	scanner := bufio.NewScanner(f)
 	scanner.Buffer(make([]byte, 0, 256*1024), 1024*1024)
 
@@ -53,13 +64,36 @@ func ExtractFromRecording(st *store.Store, turnID int) (*SessionMetrics, error)
 
 		switch event.Type {
 		case "claude_stream":
-			processStreamEvent(m, event.Data)
+			// The agent meta event precedes the stream output.
+			if extractor == nil {
+				extractor = NewMetricsExtractor(m.Agent)
+			}
+			extractor.ProcessStreamEvent(m, []byte(event.Data))
 		case "meta":
 			processMetaEvent(m, event.Data)
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return m, err
+	}
+
+	EstimateCost(m, cfg)
+	return m, nil
+}
 
-	return m, scanner.Err()
+// EstimateCost prices m from cfg's model price table when the agent
+// reported no cost. It reports whether a price was applied.
+func EstimateCost(m *SessionMetrics, cfg *config.GlobalConfig) bool {
+	if m.TotalCostUSD > 0 || m.InputTokens+m.CachedInputTokens+m.OutputTokens+m.ReasoningTokens == 0 {
+		return false
+	}
+	price := cfg.FindModelPrice(m.Model)
+	if price == nil {
+		return false
+	}
+	m.TotalCostUSD = price.CostUSD(m.InputTokens, m.CachedInputTokens, m.OutputTokens+m.ReasoningTokens)
+	m.CostEstimated = m.TotalCostUSD > 0
+	return m.CostEstimated
 }
 
 // findEventsFile locates the events.jsonl for a given turn ID.
@@ -73,42 +107,12 @@ func findEventsFile(st *store.Store, turnID int) (string, error) {
 	return "", fmt.Errorf("events.jsonl not found for turn %d", turnID)
 }
 
-// processStreamEvent parses a claude_stream event and extracts metrics.
-func processStreamEvent(m *SessionMetrics, data string) {
-	var ev stream.ClaudeEvent
-	if err := json.Unmarshal([]byte(data), &ev); err != nil {
-		return
-	}
-
-	switch ev.Type {
-	case "result":
-		if ev.TotalCostUSD > 0 {
-			m.TotalCostUSD = ev.TotalCostUSD
-		}
-		if ev.NumTurns > 0 {
-			m.NumTurns = ev.NumTurns
-		}
-		if ev.DurationMS > 0 {
-			m.DurationSecs = int(ev.DurationMS / 1000)
-		}
-		if ev.Usage != nil {
-			m.InputTokens = ev.Usage.InputTokens
-			m.OutputTokens = ev.Usage.OutputTokens
-		}
-
-	case "assistant":
-		if ev.AssistantMessage != nil {
-			for _, block := range ev.AssistantMessage.Content {
-				if block.Type == "tool_use" && block.Name != "" {
-					m.ToolCalls[block.Name]++
-				}
-			}
-		}
-	}
-}
-
-// processMetaEvent checks for exit_code meta events to determine success.
+// processMetaEvent reads the agent name and the exit code, which
+// determines success.
 func processMetaEvent(m *SessionMetrics, data string) {
+	if agentName, ok := strings.CutPrefix(data, "agent="); ok && agentName != "" {
+		m.Agent = agentName
+	}
 	if strings.HasPrefix(data, "exit_code=") {
 		code := strings.TrimPrefix(data, "exit_code=")
 		m.Success = code == "0"
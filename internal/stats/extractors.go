@@ -0,0 +1,249 @@
+package stats
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/agusx1211/adaf/internal/stream"
+)
+
+// MetricsExtractor folds an agent's native stream-json lines into
+// SessionMetrics. Recordings store every agent's raw output as
+// "claude_stream" events, so the extractor is picked from the recorded
+// agent name rather than the event type.
+//
+// Extractors normalize token counts: InputTokens excludes cache reads
+// (which go to CachedInputTokens) and OutputTokens excludes reasoning
+// (which goes to ReasoningTokens).
+type MetricsExtractor interface {
+	ProcessStreamEvent(m *SessionMetrics, data []byte)
+}
+
+// NewMetricsExtractor returns a fresh extractor for agentName. Unknown
+// agents get the Claude extractor, which matches the recording format of
+// stream-json agents.
+func NewMetricsExtractor(agentName string) MetricsExtractor {
+	switch strings.ToLower(strings.TrimSpace(agentName)) {
+	case "codex":
+		return &codexExtractor{seenTools: make(map[string]bool)}
+	case "gemini":
+		return geminiExtractor{}
+	case "opencode":
+		return opencodeExtractor{}
+	case "vibe":
+		return vibeExtractor{}
+	default:
+		return claudeExtractor{}
+	}
+}
+
+// claudeExtractor reads Claude stream-json events. The final "result" event
+// carries the session totals, including the reported USD cost.
+type claudeExtractor struct{}
+
+func (claudeExtractor) ProcessStreamEvent(m *SessionMetrics, data []byte) {
+	var ev stream.ClaudeEvent
+	if err := json.Unmarshal(data, &ev); err != nil {
+		return
+	}
+
+	switch ev.Type {
+	case "system":
+		if ev.Subtype == "init" && ev.Model != "" {
+			m.Model = ev.Model
+		}
+
+	case "result":
+		if ev.TotalCostUSD > 0 {
+			m.TotalCostUSD = ev.TotalCostUSD
+		}
+		if ev.NumTurns > 0 {
+			m.NumTurns = ev.NumTurns
+		}
+		if ev.DurationMS > 0 {
+			m.DurationSecs = int(ev.DurationMS / 1000)
+		}
+		if ev.Usage != nil {
+			m.InputTokens = ev.Usage.InputTokens + ev.Usage.CacheCreationInputTokens
+			m.CachedInputTokens = ev.Usage.CacheReadInputTokens
+			m.OutputTokens = ev.Usage.OutputTokens
+		}
+
+	case "assistant":
+		if ev.AssistantMessage != nil {
+			for _, block := range ev.AssistantMessage.Content {
+				if block.Type == "tool_use" && block.Name != "" {
+					m.ToolCalls[block.Name]++
+				}
+			}
+		}
+	}
+}
+
+// codexEvent is the subset of `codex exec --json` events that carry metrics.
+type codexEvent struct {
+	Type  string `json:"type"`
+	Usage *struct {
+		InputTokens           int `json:"input_tokens"`
+		CachedInputTokens     int `json:"cached_input_tokens"`
+		OutputTokens          int `json:"output_tokens"`
+		ReasoningOutputTokens int `json:"reasoning_output_tokens"`
+	} `json:"usage,omitempty"`
+	Item *struct {
+		ID     string `json:"id"`
+		Type   string `json:"type"`
+		Server string `json:"server"`
+		Tool   string `json:"tool"`
+	} `json:"item,omitempty"`
+}
+
+// codexExtractor reads Codex JSONL. Codex reports no cost, and its
+// input_tokens include the cached ones; usage arrives per turn.completed.
+type codexExtractor struct {
+	seenTools map[string]bool // item IDs already counted as tool calls
+}
+
+func (x *codexExtractor) ProcessStreamEvent(m *SessionMetrics, data []byte) {
+	var ev codexEvent
+	if err := json.Unmarshal(data, &ev); err != nil {
+		return
+	}
+
+	switch ev.Type {
+	case "turn.completed":
+		m.NumTurns++
+		if u := ev.Usage; u != nil {
+			m.InputTokens += max(u.InputTokens-u.CachedInputTokens, 0)
+			m.CachedInputTokens += u.CachedInputTokens
+			m.OutputTokens += max(u.OutputTokens-u.ReasoningOutputTokens, 0)
+			m.ReasoningTokens += u.ReasoningOutputTokens
+		}
+
+	case "item.started", "item.completed":
+		if ev.Item == nil || x.seenTools[ev.Item.ID] {
+			return
+		}
+		var name string
+		switch ev.Item.Type {
+		case "command_execution":
+			name = "Bash"
+		case "mcp_tool_call":
+			name = strings.Trim(ev.Item.Server+"."+ev.Item.Tool, ".")
+			if name == "" {
+				name = "mcp"
+			}
+		case "web_search":
+			name = "web_search"
+		default:
+			return
+		}
+		if ev.Item.ID != "" {
+			x.seenTools[ev.Item.ID] = true
+		}
+		m.ToolCalls[name]++
+	}
+}
+
+// geminiExtractor reads Gemini CLI stream-json. Its result stats split the
+// prompt into cached and uncached tokens but report no cost.
+type geminiExtractor struct{}
+
+func (geminiExtractor) ProcessStreamEvent(m *SessionMetrics, data []byte) {
+	var ev stream.GeminiEvent
+	if err := json.Unmarshal(data, &ev); err != nil {
+		return
+	}
+
+	switch ev.Type {
+	case "init":
+		if ev.Model != "" {
+			m.Model = ev.Model
+		}
+	case "tool_use":
+		if ev.ToolName != "" {
+			m.ToolCalls[ev.ToolName]++
+		}
+	case "result":
+		m.NumTurns++
+		if st := ev.Stats; st != nil {
+			uncached := st.Input
+			if uncached == 0 {
+				uncached = max(st.InputTokens-st.Cached, 0)
+			}
+			m.InputTokens += uncached
+			m.CachedInputTokens += st.Cached
+			m.OutputTokens += st.OutputTokens
+			if st.DurationMS > 0 {
+				m.DurationSecs += int(st.DurationMS / 1000)
+			}
+		}
+	}
+}
+
+// opencodeEvent is the subset of `opencode run --format json` events that
+// carry metrics.
+type opencodeEvent struct {
+	Type string `json:"type"`
+	Part *struct {
+		Tool   string  `json:"tool"`
+		Cost   float64 `json:"cost"`
+		Tokens *struct {
+			Input     int `json:"input"`
+			Output    int `json:"output"`
+			Reasoning int `json:"reasoning"`
+			Cache     *struct {
+				Read  int `json:"read"`
+				Write int `json:"write"`
+			} `json:"cache"`
+		} `json:"tokens"`
+	} `json:"part,omitempty"`
+}
+
+// opencodeExtractor reads OpenCode JSON events. Every step_finish reports
+// that step's tokens and cost, so they are summed.
+type opencodeExtractor struct{}
+
+func (opencodeExtractor) ProcessStreamEvent(m *SessionMetrics, data []byte) {
+	var ev opencodeEvent
+	if err := json.Unmarshal(data, &ev); err != nil || ev.Part == nil {
+		return
+	}
+
+	switch ev.Type {
+	case "tool_use":
+		name := ev.Part.Tool
+		if name == "" {
+			name = "tool"
+		}
+		m.ToolCalls[name]++
+	case "step_finish":
+		m.NumTurns++
+		m.TotalCostUSD += ev.Part.Cost
+		if t := ev.Part.Tokens; t != nil {
+			m.InputTokens += t.Input
+			m.OutputTokens += t.Output
+			m.ReasoningTokens += t.Reasoning
+			if t.Cache != nil {
+				m.InputTokens += t.Cache.Write
+				m.CachedInputTokens += t.Cache.Read
+			}
+		}
+	}
+}
+
+// vibeExtractor reads Vibe streaming messages, which carry tool calls but
+// no usage data.
+type vibeExtractor struct{}
+
+func (vibeExtractor) ProcessStreamEvent(m *SessionMetrics, data []byte) {
+	var msg stream.VibeMessage
+	if err := json.Unmarshal(data, &msg); err != nil || msg.Role != "assistant" {
+		return
+	}
+	m.NumTurns++
+	for _, tc := range msg.ToolCalls {
+		if tc.Function.Name != "" {
+			m.ToolCalls[tc.Function.Name]++
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func extract(agentName string, lines ...string) *SessionMetrics {
+	m := &SessionMetrics{ToolCalls: make(map[string]int)}
+	x := NewMetricsExtractor(agentName)
+	for _, line := range lines {
+		x.ProcessStreamEvent(m, []byte(line))
+	}
+	return m
+}
+
+func TestExtractors_NormalizeTokens(t *testing.T) {
+	tests := []struct {
+		agent                                   string
+		lines                                   []string
+		cost                                    float64
+		input, cached, output, reasoning, turns int
+		tools                                   map[string]int
+	}{
+		{
+			agent: "claude",
+			lines: []string{
+				`{"type":"system","subtype":"init","model":"claude-sonnet-4-5"}`,
+				`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read"}]}}`,
+				`{"type":"result","total_cost_usd":0.42,"num_turns":3,"usage":{"input_tokens":100,"cache_creation_input_tokens":50,"cache_read_input_tokens":900,"output_tokens":70}}`,
+			},
+			cost: 0.42, input: 150, cached: 900, output: 70, turns: 3,
+			tools: map[string]int{"Read": 1},
+		},
+		{
+			agent: "codex",
+			lines: []string{
+				`{"type":"item.started","item":{"id":"i1","type":"command_execution","command":"ls"}}`,
+				`{"type":"item.completed","item":{"id":"i1","type":"command_execution","status":"completed"}}`,
+				`{"type":"item.completed","item":{"id":"i2","type":"mcp_tool_call","server":"adaf","tool":"issues"}}`,
+				`{"type":"turn.completed","usage":{"input_tokens":1000,"cached_input_tokens":800,"output_tokens":300,"reasoning_output_tokens":200}}`,
+			},
+			input: 200, cached: 800, output: 100, reasoning: 200, turns: 1,
+			tools: map[string]int{"Bash": 1, "adaf.issues": 1},
+		},
+		{
+			agent: "gemini",
+			lines: []string{
+				`{"type":"init","model":"gemini-2.5-pro"}`,
+				`{"type":"tool_use","tool_name":"read_file","tool_id":"t1"}`,
+				`{"type":"result","status":"success","stats":{"input_tokens":500,"cached":300,"input":200,"output_tokens":40,"duration_ms":2500}}`,
+			},
+			input: 200, cached: 300, output: 40, turns: 1,
+			tools: map[string]int{"read_file": 1},
+		},
+		{
+			agent: "opencode",
+			lines: []string{
+				`{"type":"tool_use","part":{"tool":"bash"}}`,
+				`{"type":"step_finish","part":{"cost":0.01,"tokens":{"input":10,"output":5,"reasoning":2,"cache":{"read":100,"write":20}}}}`,
+				`{"type":"step_finish","part":{"cost":0.02,"tokens":{"input":30,"output":15,"reasoning":3,"cache":{"read":200,"write":0}}}}`,
+			},
+			cost: 0.03, input: 60, cached: 300, output: 20, reasoning: 5, turns: 2,
+			tools: map[string]int{"bash": 1},
+		},
+		{
+			agent: "vibe",
+			lines: []string{
+				`{"role":"assistant","content":"hi","tool_calls":[{"function":{"name":"grep"}}]}`,
+				`{"role":"tool","name":"grep","content":"ok"}`,
+			},
+			turns: 1,
+			tools: map[string]int{"grep": 1},
+		},
+	}
+	for _, tt := range tests {
+		m := extract(tt.agent, tt.lines...)
+		if math.Abs(m.TotalCostUSD-tt.cost) > 1e-9 || m.InputTokens != tt.input || m.CachedInputTokens != tt.cached ||
+			m.OutputTokens != tt.output || m.ReasoningTokens != tt.reasoning || m.NumTurns != tt.turns {
+			t.Fatalf("%s: metrics = %+v", tt.agent, m)
+		}
+		if len(m.ToolCalls) != len(tt.tools) {
+			t.Fatalf("%s: tool calls = %v, want %v", tt.agent, m.ToolCalls, tt.tools)
+		}
+		for tool, n := range tt.tools {
+			if m.ToolCalls[tool] != n {
+				t.Fatalf("%s: tool calls = %v, want %v", tt.agent, m.ToolCalls, tt.tools)
+			}
+		}
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	cfg := &config.GlobalConfig{ModelPrices: []config.ModelPrice{
+		{Model: "gpt-5*", Input: 1, CachedInput: 0.1, Output: 10},
+	}}
+
+	m := &SessionMetrics{Model: "gpt-5-codex", InputTokens: 1_000_000, CachedInputTokens: 1_000_000, OutputTokens: 50_000, ReasoningTokens: 50_000}
+	if !EstimateCost(m, cfg) || !m.CostEstimated || math.Abs(m.TotalCostUSD-2.1) > 1e-9 {
+		t.Fatalf("estimated metrics = %+v, want $2.10", m)
+	}
+
+	reported := &SessionMetrics{Model: "gpt-5", TotalCostUSD: 0.5, InputTokens: 10}
+	if EstimateCost(reported, cfg) || reported.TotalCostUSD != 0.5 {
+		t.Fatalf("reported cost was overridden: %+v", reported)
+	}
+	unpriced := &SessionMetrics{Model: "gemini-2.5-pro", InputTokens: 10}
+	if EstimateCost(unpriced, cfg) || unpriced.TotalCostUSD != 0 {
+		t.Fatalf("unpriced model got a cost: %+v", unpriced)
+	}
+}
+
+func TestExtractFromRecording_UsesRecordedAgentAndPriceTable(t *testing.T) {
+	cfg := &config.GlobalConfig{ModelPrices: []config.ModelPrice{
+		{Model: "gpt-5", Input: 2, CachedInput: 0.5, Output: 8},
+	}}
+
+	dir := t.TempDir()
+	st, err := store.New(dir)
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	if err := st.Init(store.ProjectConfig{Name: "stats-test", RepoPath: dir}); err != nil {
+		t.Fatalf("store.Init() error = %v", err)
+	}
+	turn := &store.Turn{Agent: "codex", AgentModel: "gpt-5", Objective: "x"}
+	if err := st.CreateTurn(turn); err != nil {
+		t.Fatalf("CreateTurn() error = %v", err)
+	}
+	for _, ev := range []store.RecordingEvent{
+		{Type: "meta", Data: "agent=codex"},
+		{Type: "claude_stream", Data: `{"type":"turn.completed","usage":{"input_tokens":1500000,"cached_input_tokens":1000000,"output_tokens":250000}}`},
+		{Type: "meta", Data: "exit_code=0"},
+	} {
+		ev.Timestamp = time.Now().UTC()
+		if err := st.AppendRecordingEvent(turn.ID, ev); err != nil {
+			t.Fatalf("AppendRecordingEvent() error = %v", err)
+		}
+	}
+
+	m, err := ExtractFromRecording(st, turn.ID, cfg)
+	if err != nil {
+		t.Fatalf("ExtractFromRecording() error = %v", err)
+	}
+	if m.Agent != "codex" || m.InputTokens != 500_000 || m.CachedInputTokens != 1_000_000 || !m.Success {
+		t.Fatalf("metrics = %+v", m)
+	}
+	// 0.5M uncached * $2 + 1M cached * $0.5 + 0.25M output * $8
+	if !m.CostEstimated || math.Abs(m.TotalCostUSD-3.5) > 1e-9 {
+		t.Fatalf("cost = %v (estimated %v), want ~$3.50", m.TotalCostUSD, m.CostEstimated)
+	}
+}
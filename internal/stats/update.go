@@ -3,17 +3,19 @@ package stats
 import (
 	"time"
 
+	"github.com/agusx1211/adaf/internal/config"
 	"github.com/agusx1211/adaf/internal/store"
 )
 
 // UpdateProfileStats extracts metrics from a completed turn and
-// merges them into the profile's aggregate stats.
-func UpdateProfileStats(st *store.Store, profileName string, turnID int) error {
+// merges them into the profile's aggregate stats. cfg prices turns whose
+// agent reports no cost.
+func UpdateProfileStats(st *store.Store, cfg *config.GlobalConfig, profileName string, turnID int) error {
 	if profileName == "" {
 		return nil
 	}
 
-	metrics, err := ExtractFromRecording(st, turnID)
+	metrics, err := ExtractFromRecording(st, turnID, cfg)
 	if err != nil {
 		// Recording may not exist yet or be unreadable; non-fatal.
 		return nil
@@ -27,13 +29,7 @@ func UpdateProfileStats(st *store.Store, profileName string, turnID int) error {
 	stats.TotalRuns++
 	stats.TotalTurns += metrics.NumTurns
 	stats.TotalDuration += metrics.DurationSecs
-	stats.TotalCostUSD += metrics.TotalCostUSD
-	stats.TotalInputTok += metrics.InputTokens
-	stats.TotalOutputTok += metrics.OutputTokens
-
-	for tool, count := range metrics.ToolCalls {
-		stats.ToolCalls[tool] += count
-	}
+	AddToProfileStats(stats, metrics)
 
 	if metrics.Success {
 		stats.SuccessCount++
@@ -51,6 +47,29 @@ func UpdateProfileStats(st *store.Store, profileName string, turnID int) error {
 	return st.SaveProfileStats(stats)
 }
 
+// AddToProfileStats adds one run's cost, tokens and tool calls to a
+// profile's totals.
+func AddToProfileStats(ps *store.ProfileStats, m *SessionMetrics) {
+	ps.TotalCostUSD += m.TotalCostUSD
+	if m.CostEstimated {
+		ps.EstimatedCost += m.TotalCostUSD
+	}
+	ps.TotalInputTok += m.InputTokens
+	ps.TotalCachedTok += m.CachedInputTokens
+	ps.TotalOutputTok += m.OutputTokens
+	ps.TotalReasonTok += m.ReasoningTokens
+	ps.TokenRuns++
+	if m.Model != "" {
+		ps.Model = m.Model
+	}
+	if ps.ToolCalls == nil {
+		ps.ToolCalls = make(map[string]int)
+	}
+	for tool, count := range m.ToolCalls {
+		ps.ToolCalls[tool] += count
+	}
+}
+
 // updateSpawnStats queries spawn records and updates spawn-related stats.
 func updateSpawnStats(st *store.Store, stats *store.ProfileStats, profileName string) {
 	spawns, err := st.ListSpawns()
@@ -75,7 +94,7 @@ func updateSpawnStats(st *store.Store, stats *store.ProfileStats, profileName st
 }
 
 // UpdateLoopStats updates loop-level stats after a loop run completes.
-func UpdateLoopStats(st *store.Store, loopName string, run *store.LoopRun) error {
+func UpdateLoopStats(st *store.Store, cfg *config.GlobalConfig, loopName string, run *store.LoopRun) error {
 	if loopName == "" {
 		return nil
 	}
@@ -90,11 +109,14 @@ func UpdateLoopStats(st *store.Store, loopName string, run *store.LoopRun) error
 
 	// Aggregate cost and duration from all turns in this run.
 	for _, sid := range run.TurnIDs {
-		metrics, err := ExtractFromRecording(st, sid)
+		metrics, err := ExtractFromRecording(st, sid, cfg)
 		if err != nil {
 			continue
 		}
 		stats.TotalCostUSD += metrics.TotalCostUSD
+		if metrics.CostEstimated {
+			stats.EstimatedCost += metrics.TotalCostUSD
+		}
 		stats.TotalDuration += metrics.DurationSecs
 	}
 
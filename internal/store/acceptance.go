@@ -0,0 +1,103 @@
+package store
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// AcceptanceCheck is a verification gate evaluated in a spawn's worktree once
+// the child finishes. Exactly one of the fields is set.
+type AcceptanceCheck struct {
+	Command      string `json:"command,omitempty"`        // shell command that must exit 0
+	File         string `json:"file,omitempty"`           // path that must exist, relative to the worktree
+	MaxDiffLines int    `json:"max_diff_lines,omitempty"` // limit on added+deleted lines vs the spawn's base commit
+}
+
+// AcceptanceResult is the outcome of one acceptance check.
+type AcceptanceResult struct {
+	Check  AcceptanceCheck `json:"check"`
+	Passed bool            `json:"passed"`
+	Output string          `json:"output,omitempty"` // tail of command output or failure detail
+}
+
+// Validate reports whether the check is well formed.
+func (c AcceptanceCheck) Validate() error {
+	set := 0
+	if strings.TrimSpace(c.Command) != "" {
+		set++
+	}
+	if strings.TrimSpace(c.File) != "" {
+		set++
+		if filepath.IsAbs(c.File) || strings.HasPrefix(filepath.Clean(c.File), "..") {
+			return fmt.Errorf("acceptance file %q must be relative to the worktree", c.File)
+		}
+	}
+	if c.MaxDiffLines < 0 {
+		return fmt.Errorf("acceptance diff limit must be positive, got %d", c.MaxDiffLines)
+	}
+	if c.MaxDiffLines > 0 {
+		set++
+	}
+	switch set {
+	case 0:
+		return fmt.Errorf("acceptance check is empty")
+	case 1:
+		return nil
+	default:
+		return fmt.Errorf("acceptance check must set exactly one of command, file or max_diff_lines")
+	}
+}
+
+// Describe returns a short human-readable label for the check.
+func (c AcceptanceCheck) Describe() string {
+	switch {
+	case strings.TrimSpace(c.Command) != "":
+		return "run `" + strings.TrimSpace(c.Command) + "`"
+	case strings.TrimSpace(c.File) != "":
+		return "file " + strings.TrimSpace(c.File) + " exists"
+	case c.MaxDiffLines > 0:
+		return fmt.Sprintf("diff <= %d lines", c.MaxDiffLines)
+	default:
+		return "(empty check)"
+	}
+}
+
+// MergeAcceptanceChecks concatenates check lists, dropping duplicates and
+// keeping only the strictest diff-size limit.
+func MergeAcceptanceChecks(lists ...[]AcceptanceCheck) []AcceptanceCheck {
+	var out []AcceptanceCheck
+	seen := make(map[AcceptanceCheck]bool)
+	maxDiff := 0
+	for _, list := range lists {
+		for _, c := range list {
+			c.Command = strings.TrimSpace(c.Command)
+			c.File = strings.TrimSpace(c.File)
+			if c.MaxDiffLines > 0 {
+				if maxDiff == 0 || c.MaxDiffLines < maxDiff {
+					maxDiff = c.MaxDiffLines
+				}
+				continue
+			}
+			if c == (AcceptanceCheck{}) || seen[c] {
+				continue
+			}
+			seen[c] = true
+			out = append(out, c)
+		}
+	}
+	if maxDiff > 0 {
+		out = append(out, AcceptanceCheck{MaxDiffLines: maxDiff})
+	}
+	return out
+}
+
+// AcceptancePassed reports whether every result passed.
+func AcceptancePassed(results []AcceptanceResult) bool {
+	for _, r := range results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
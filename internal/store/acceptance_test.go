@@ -0,0 +1,41 @@
+package store
+
+import "testing"
+
+func TestAcceptanceCheckValidate(t *testing.T) {
+	cases := []struct {
+		check AcceptanceCheck
+		ok    bool
+	}{
+		{AcceptanceCheck{Command: "make test"}, true},
+		{AcceptanceCheck{File: "docs/README.md"}, true},
+		{AcceptanceCheck{MaxDiffLines: 100}, true},
+		{AcceptanceCheck{}, false},
+		{AcceptanceCheck{Command: "make", File: "x"}, false},
+		{AcceptanceCheck{File: "/abs/path"}, false},
+		{AcceptanceCheck{File: "../outside"}, false},
+		{AcceptanceCheck{MaxDiffLines: -1}, false},
+	}
+	for _, tc := range cases {
+		err := tc.check.Validate()
+		if (err == nil) != tc.ok {
+			t.Errorf("Validate(%+v) error = %v, want ok=%v", tc.check, err, tc.ok)
+		}
+	}
+}
+
+func TestMergeAcceptanceChecks(t *testing.T) {
+	got := MergeAcceptanceChecks(
+		[]AcceptanceCheck{{Command: " make test "}, {MaxDiffLines: 300}},
+		[]AcceptanceCheck{{Command: "make test"}, {File: "CHANGELOG.md"}, {MaxDiffLines: 120}},
+	)
+	want := []AcceptanceCheck{{Command: "make test"}, {File: "CHANGELOG.md"}, {MaxDiffLines: 120}}
+	if len(got) != len(want) {
+		t.Fatalf("MergeAcceptanceChecks = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("MergeAcceptanceChecks = %+v, want %+v", got, want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NormalizePlanPhaseStatus lowercases a phase status; empty means pending.
+func NormalizePlanPhaseStatus(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if status == "" {
+		return PlanPhasePending
+	}
+	return status
+}
+
+// IsValidPlanPhaseStatus reports whether status is a known phase status.
+func IsValidPlanPhaseStatus(status string) bool {
+	switch NormalizePlanPhaseStatus(status) {
+	case PlanPhasePending, PlanPhaseActive, PlanPhaseDone, PlanPhaseSkipped:
+		return true
+	default:
+		return false
+	}
+}
+
+// Phase returns the phase with the given ID, or nil.
+func (p *Plan) Phase(id int) *PlanPhase {
+	for i := range p.Phases {
+		if p.Phases[i].ID == id {
+			return &p.Phases[i]
+		}
+	}
+	return nil
+}
+
+// AddPhase appends phase with the next free ID and returns the stored copy.
+func (p *Plan) AddPhase(phase PlanPhase) (*PlanPhase, error) {
+	phase.Title = strings.TrimSpace(phase.Title)
+	if phase.Title == "" {
+		return nil, fmt.Errorf("phase title is required")
+	}
+	phase.Status = NormalizePlanPhaseStatus(phase.Status)
+	if !IsValidPlanPhaseStatus(phase.Status) {
+		return nil, fmt.Errorf("invalid phase status %q (valid: pending, active, done, skipped)", phase.Status)
+	}
+	phase.ID = 1
+	for _, existing := range p.Phases {
+		if existing.ID >= phase.ID {
+			phase.ID = existing.ID + 1
+		}
+	}
+	phase.IssueIDs = NormalizeIssueDependencyIDs(phase.IssueIDs)
+	now := time.Now().UTC()
+	phase.Created = now
+	phase.Updated = now
+	p.Phases = append(p.Phases, phase)
+	return &p.Phases[len(p.Phases)-1], nil
+}
+
+// RemovePhase drops the phase with the given ID and reports whether it existed.
+func (p *Plan) RemovePhase(id int) bool {
+	for i := range p.Phases {
+		if p.Phases[i].ID == id {
+			p.Phases = append(p.Phases[:i], p.Phases[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// MovePhase moves the phase with the given ID to a 1-based position.
+func (p *Plan) MovePhase(id, position int) error {
+	if position < 1 || position > len(p.Phases) {
+		return fmt.Errorf("phase position %d out of range (1-%d)", position, len(p.Phases))
+	}
+	from := -1
+	for i := range p.Phases {
+		if p.Phases[i].ID == id {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		return fmt.Errorf("phase %d not found in plan %q", id, p.ID)
+	}
+	phase := p.Phases[from]
+	p.Phases = append(p.Phases[:from], p.Phases[from+1:]...)
+	to := position - 1
+	p.Phases = append(p.Phases[:to], append([]PlanPhase{phase}, p.Phases[to:]...)...)
+	return nil
+}
+
+// ComputePlanProgress derives phase and plan progress from the statuses of
+// the linked issues. Linked issues missing from issues are ignored. A phase is
+// complete when marked done or when it has linked issues and all are closed;
+// skipped phases count toward nothing. The current phase is the first
+// incomplete active phase, else the first incomplete pending one.
+func ComputePlanProgress(plan *Plan, issues []Issue) PlanProgress {
+	progress := PlanProgress{Phases: []PhaseProgress{}}
+	if plan == nil {
+		return progress
+	}
+	progress.PlanID = plan.ID
+
+	statusByID := make(map[int]string, len(issues))
+	for _, iss := range issues {
+		statusByID[iss.ID] = iss.Status
+	}
+
+	percentSum := 0
+	firstPending := 0
+	for _, phase := range plan.Phases {
+		pp := PhaseProgress{
+			PhaseID: phase.ID,
+			Title:   phase.Title,
+			Status:  NormalizePlanPhaseStatus(phase.Status),
+		}
+		for _, id := range phase.IssueIDs {
+			status, ok := statusByID[id]
+			if !ok {
+				continue
+			}
+			pp.TotalIssues++
+			if IsTerminalIssueStatus(status) {
+				pp.ClosedIssues++
+			}
+		}
+		switch {
+		case pp.Status == PlanPhaseDone:
+			pp.Complete = true
+			pp.Percent = 100
+		case pp.TotalIssues > 0:
+			pp.Complete = pp.ClosedIssues == pp.TotalIssues
+			pp.Percent = pp.ClosedIssues * 100 / pp.TotalIssues
+		}
+		progress.Phases = append(progress.Phases, pp)
+
+		if pp.Status == PlanPhaseSkipped {
+			continue
+		}
+		progress.TotalPhases++
+		percentSum += pp.Percent
+		if pp.Complete {
+			progress.CompletedPhases++
+			continue
+		}
+		if pp.Status == PlanPhaseActive && progress.CurrentPhaseID == 0 {
+			progress.CurrentPhaseID = phase.ID
+		}
+		if firstPending == 0 {
+			firstPending = phase.ID
+		}
+	}
+	if progress.CurrentPhaseID == 0 {
+		progress.CurrentPhaseID = firstPending
+	}
+	if progress.TotalPhases > 0 {
+		progress.Percent = percentSum / progress.TotalPhases
+	}
+	return progress
+}
+
+// Phase returns the progress of the phase with the given ID, or nil.
+func (p PlanProgress) Phase(id int) *PhaseProgress {
+	for i := range p.Phases {
+		if p.Phases[i].PhaseID == id {
+			return &p.Phases[i]
+		}
+	}
+	return nil
+}
+
+// PlanProgress computes the progress of plan against the project's issues.
+func (s *Store) PlanProgress(plan *Plan) (PlanProgress, error) {
+	issues, err := s.ListIssues()
+	if err != nil {
+		return PlanProgress{}, err
+	}
+	return ComputePlanProgress(plan, issues), nil
+}
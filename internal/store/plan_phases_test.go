@@ -0,0 +1,77 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComputePlanProgress(t *testing.T) {
+	plan := &Plan{ID: "p", Phases: []PlanPhase{
+		{ID: 1, Title: "marked done", Status: PlanPhaseDone},
+		{ID: 2, Title: "all closed", IssueIDs: []int{1, 2}},
+		{ID: 3, Title: "dropped", Status: PlanPhaseSkipped, IssueIDs: []int{3}},
+		{ID: 4, Title: "half way", IssueIDs: []int{2, 3, 99}},
+		{ID: 5, Title: "next"},
+	}}
+	issues := []Issue{
+		{ID: 1, Status: IssueStatusClosed},
+		{ID: 2, Status: IssueStatusClosed},
+		{ID: 3, Status: IssueStatusOngoing},
+	}
+
+	got := ComputePlanProgress(plan, issues)
+	if got.CurrentPhaseID != 4 {
+		t.Fatalf("CurrentPhaseID = %d, want 4", got.CurrentPhaseID)
+	}
+	if got.TotalPhases != 4 || got.CompletedPhases != 2 || got.Percent != (100+100+50+0)/4 {
+		t.Fatalf("plan progress = %+v", got)
+	}
+	half := got.Phase(4)
+	if half.TotalIssues != 2 || half.ClosedIssues != 1 || half.Percent != 50 || half.Complete {
+		t.Fatalf("phase 4 progress = %+v (missing issue 99 should be ignored)", half)
+	}
+	if !got.Phase(2).Complete || got.Phase(5).Complete {
+		t.Fatalf("phase completion = %+v", got.Phases)
+	}
+
+	plan.Phases[4].Status = PlanPhaseActive
+	if got := ComputePlanProgress(plan, issues); got.CurrentPhaseID != 5 {
+		t.Fatalf("CurrentPhaseID = %d, want the active phase 5", got.CurrentPhaseID)
+	}
+}
+
+func TestPlanPhaseEditing(t *testing.T) {
+	plan := &Plan{ID: "p"}
+	for _, title := range []string{"one", "two", "three"} {
+		if _, err := plan.AddPhase(PlanPhase{Title: title, IssueIDs: []int{2, 1, 2}}); err != nil {
+			t.Fatalf("AddPhase(%s): %v", title, err)
+		}
+	}
+	if _, err := plan.AddPhase(PlanPhase{Title: "bad", Status: "finished"}); err == nil {
+		t.Fatal("AddPhase accepted an invalid status")
+	}
+	if phase := plan.Phase(2); phase.Status != PlanPhasePending || !reflect.DeepEqual(phase.IssueIDs, []int{1, 2}) {
+		t.Fatalf("phase 2 = %+v", phase)
+	}
+
+	if !plan.RemovePhase(2) || plan.RemovePhase(2) {
+		t.Fatal("RemovePhase(2) should succeed once")
+	}
+	if phase, _ := plan.AddPhase(PlanPhase{Title: "four"}); phase.ID != 4 {
+		t.Fatalf("new phase ID = %d, want 4", phase.ID)
+	}
+
+	if err := plan.MovePhase(4, 1); err != nil {
+		t.Fatalf("MovePhase: %v", err)
+	}
+	if err := plan.MovePhase(4, 9); err == nil {
+		t.Fatal("MovePhase accepted an out-of-range position")
+	}
+	var order []int
+	for _, phase := range plan.Phases {
+		order = append(order, phase.ID)
+	}
+	if !reflect.DeepEqual(order, []int{4, 1, 3}) {
+		t.Fatalf("phase order = %v, want [4 1 3]", order)
+	}
+}
@@ -3,6 +3,9 @@ package store
 import "strings"
 
 const (
+	// SpawnStatusQueued marks a spawn waiting for a concurrency slot; it
+	// starts automatically once one frees up.
+	SpawnStatusQueued        = "queued"
 	SpawnStatusRunning       = "running"
 	SpawnStatusAwaitingInput = "awaiting_input"
 	SpawnStatusCompleted     = "completed"
@@ -11,13 +14,17 @@ const (
 	SpawnStatusCancelled     = "cancelled"
 	SpawnStatusMerged        = "merged"
 	SpawnStatusRejected      = "rejected"
+
+	// SpawnStatusFailedVerification marks a spawn whose child exited cleanly
+	// but whose acceptance checks did not pass.
+	SpawnStatusFailedVerification = "failed_verification"
 )
 
 // IsTerminalSpawnStatus reports whether status represents a completed spawn
 // lifecycle state.
 func IsTerminalSpawnStatus(status string) bool {
 	switch strings.ToLower(strings.TrimSpace(status)) {
-	case SpawnStatusCompleted, SpawnStatusFailed, SpawnStatusCanceled, SpawnStatusCancelled, SpawnStatusMerged, SpawnStatusRejected:
+	case SpawnStatusCompleted, SpawnStatusFailed, SpawnStatusFailedVerification, SpawnStatusCanceled, SpawnStatusCancelled, SpawnStatusMerged, SpawnStatusRejected:
 		return true
 	default:
 		return false
@@ -8,6 +8,7 @@ func TestIsTerminalSpawnStatus(t *testing.T) {
 		status string
 		want   bool
 	}{
+		{name: "queued", status: SpawnStatusQueued, want: false},
 		{name: "running", status: SpawnStatusRunning, want: false},
 		{name: "awaiting_input", status: SpawnStatusAwaitingInput, want: false},
 		{name: "completed", status: SpawnStatusCompleted, want: true},
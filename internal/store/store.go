@@ -33,6 +33,9 @@ type Store struct {
 	signalMu         sync.Mutex
 	waitSignals      map[int]chan struct{}
 	interruptSignals map[int]chan string
+
+	searchMu sync.Mutex
+	search   *searchIndex
 }
 
 var operationalProjectSubdirs = []string{
@@ -41,6 +44,7 @@ var operationalProjectSubdirs = []string{
 	"spawns",
 	"messages",
 	"loopruns",
+	"schedules",
 	"stats",
 }
 
@@ -54,6 +58,9 @@ var requiredProjectSubdirs = []string{
 	"local/spawns",
 	"local/messages",
 	"local/loopruns",
+	"local/schedules",
+	"local/approvals",
+	"local/pulls",
 	"local/stats",
 	"local/stats/profiles",
 	"local/stats/loops",
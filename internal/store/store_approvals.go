@@ -0,0 +1,133 @@
+// store_approvals.go contains the pending-approval queue.
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+func (s *Store) approvalPath(id int) string {
+	return s.localDir("approvals", fmt.Sprintf("%d.json", id))
+}
+
+// CreateApproval files a new pending approval with an auto-assigned ID.
+func (s *Store) CreateApproval(a *Approval) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := s.localDir("approvals")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	a.ID = s.nextID(dir)
+	a.Status = ApprovalPending
+	a.CreatedAt = time.Now().UTC()
+	return s.writeJSONLocked(s.approvalPath(a.ID), a)
+}
+
+// GetApproval loads a single approval by ID.
+func (s *Store) GetApproval(id int) (*Approval, error) {
+	var a Approval
+	if err := s.readJSONLocked(s.approvalPath(id), &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// UpdateApproval persists changes to an approval.
+func (s *Store) UpdateApproval(a *Approval) error {
+	return s.writeJSONLocked(s.approvalPath(a.ID), a)
+}
+
+// ListApprovals returns all approvals, sorted by ID.
+func (s *Store) ListApprovals() ([]Approval, error) {
+	dir := s.localDir("approvals")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var list []Approval
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		var a Approval
+		if err := s.readJSONLocked(filepath.Join(dir, e.Name()), &a); err != nil {
+			continue
+		}
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
+
+// PendingApprovals returns the approvals still waiting for a decision.
+func (s *Store) PendingApprovals() ([]Approval, error) {
+	all, err := s.ListApprovals()
+	if err != nil {
+		return nil, err
+	}
+	var pending []Approval
+	for _, a := range all {
+		if a.Status == ApprovalPending {
+			pending = append(pending, a)
+		}
+	}
+	return pending, nil
+}
+
+// MergeApproval returns the latest merge approval filed for a spawn, or nil.
+func (s *Store) MergeApproval(spawnID int) (*Approval, error) {
+	all, err := s.ListApprovals()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(all) - 1; i >= 0; i-- {
+		if all[i].Kind == ApprovalKindMerge && all[i].SpawnID == spawnID {
+			return &all[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// DecideApproval approves or denies a pending approval.
+// The check and the write happen under the store and file locks, so two
+// deciders cannot both act on the same pending approval.
+func (s *Store) DecideApproval(id int, approve bool, by, comment string) (*Approval, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.approvalPath(id)
+	lf, err := lockFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("lock approval %d: %w", id, err)
+	}
+	defer unlockFile(lf)
+
+	var a Approval
+	if err := s.readJSON(path, &a); err != nil {
+		return nil, fmt.Errorf("approval %d not found: %w", id, err)
+	}
+	if a.Status != ApprovalPending {
+		return nil, fmt.Errorf("approval %d is already %s", id, a.Status)
+	}
+	a.Status = ApprovalDenied
+	if approve {
+		a.Status = ApprovalApproved
+	}
+	a.DecidedBy = strings.TrimSpace(by)
+	a.Comment = strings.TrimSpace(comment)
+	a.DecidedAt = time.Now().UTC()
+	if err := s.writeJSON(path, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
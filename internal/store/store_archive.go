@@ -0,0 +1,1023 @@
+// store_archive.go contains project store export and import.
+package store
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ArchiveFormat identifies adaf project archives.
+	ArchiveFormat = "adaf-archive"
+	// ArchiveVersion is the archive layout version written by Export.
+	ArchiveVersion = 1
+	// SchemaVersion is the version of the store record types. Bump it when a
+	// change to the types in this package would break older readers.
+	//
+	// 2: schedules, approvals, pull requests and issue claims, plus the
+	// record fields added alongside them.
+	SchemaVersion = 2
+)
+
+// Archive entry kinds, used for manifest counts and import reports.
+const (
+	ArchiveKindPlans         = "plans"
+	ArchiveKindIssues        = "issues"
+	ArchiveKindWiki          = "wiki"
+	ArchiveKindTurns         = "turns"
+	ArchiveKindLoopRuns      = "loopruns"
+	ArchiveKindLoopMessages  = "loop_messages"
+	ArchiveKindSpawns        = "spawns"
+	ArchiveKindSpawnMessages = "spawn_messages"
+	ArchiveKindProfileStats  = "profile_stats"
+	ArchiveKindLoopStats     = "loop_stats"
+	ArchiveKindRecordings    = "recordings"
+	ArchiveKindSchedules     = "schedules"
+	ArchiveKindApprovals     = "approvals"
+	ArchiveKindPullRequests  = "pulls"
+)
+
+// ArchiveManifest describes an exported project archive.
+type ArchiveManifest struct {
+	Format         string         `json:"format"`
+	ArchiveVersion int            `json:"archive_version"`
+	SchemaVersion  int            `json:"schema_version"`
+	ProjectID      string         `json:"project_id,omitempty"`
+	ProjectName    string         `json:"project_name,omitempty"`
+	ExportedAt     time.Time      `json:"exported_at"`
+	Recordings     bool           `json:"recordings,omitempty"`
+	Counts         map[string]int `json:"counts"`
+}
+
+// Archive is the decoded content of a project archive.
+type Archive struct {
+	Manifest      ArchiveManifest
+	Project       ProjectConfig
+	Plans         []Plan
+	Issues        []Issue
+	Wiki          []WikiEntry
+	Turns         []Turn
+	LoopRuns      []LoopRun
+	LoopMessages  []LoopMessage
+	Spawns        []SpawnRecord
+	SpawnMessages []SpawnMessage
+	ProfileStats  []ProfileStats
+	LoopStats     []LoopStats
+	Schedules     []LoopSchedule
+	Approvals     []Approval
+	PullRequests  []PullRequest
+	Recordings    []ArchivedRecording
+}
+
+// ArchivedRecording is a turn recording and its streamed events log.
+type ArchivedRecording struct {
+	TurnID    int
+	Recording *TurnRecording
+	Events    []byte // raw events.jsonl
+}
+
+// ExportOptions controls what Export includes.
+type ExportOptions struct {
+	IncludeRecordings bool
+}
+
+// ImportOptions controls how Import merges an archive into the store.
+type ImportOptions struct {
+	// RemapIDs allocates fresh IDs for every numeric record. Without it IDs
+	// are kept unless they collide with records already in the store.
+	RemapIDs bool
+	// DryRun validates and plans the import without writing anything.
+	DryRun bool
+}
+
+// ImportReport summarizes an import.
+type ImportReport struct {
+	Manifest ArchiveManifest   `json:"manifest"`
+	Imported map[string]int    `json:"imported"`
+	Merged   map[string]int    `json:"merged,omitempty"`
+	Remapped map[string]int    `json:"remapped,omitempty"`
+	Renamed  map[string]string `json:"renamed,omitempty"` // "kind/old" -> new string ID
+}
+
+// Export writes the project store as a gzip-compressed tar archive.
+func (s *Store) Export(w io.Writer, opts ExportOptions) (*ArchiveManifest, error) {
+	a, err := s.collectArchive(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now().UTC()
+	add := func(name string, v any) error {
+		var data []byte
+		if raw, ok := v.([]byte); ok {
+			data = raw
+		} else {
+			var err error
+			if data, err = json.MarshalIndent(v, "", "  "); err != nil {
+				return fmt.Errorf("encoding %s: %w", name, err)
+			}
+		}
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: now, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	entries := []archiveEntry{
+		{"manifest.json", a.Manifest},
+		{"project.json", a.Project},
+	}
+	appendEntry := func(name string, v any) {
+		entries = append(entries, archiveEntry{name, v})
+	}
+	for _, v := range a.Plans {
+		appendEntry("plans/"+v.ID+".json", v)
+	}
+	for _, v := range a.Issues {
+		appendEntry(fmt.Sprintf("issues/%d.json", v.ID), v)
+	}
+	for _, v := range a.Wiki {
+		appendEntry("wiki/"+v.ID+".json", v)
+	}
+	for _, v := range a.Turns {
+		appendEntry(fmt.Sprintf("turns/%d.json", v.ID), v)
+	}
+	for _, v := range a.LoopRuns {
+		appendEntry(fmt.Sprintf("loopruns/%d.json", v.ID), v)
+	}
+	for _, v := range a.LoopMessages {
+		appendEntry(fmt.Sprintf("loopruns/%d/messages/%d.json", v.RunID, v.ID), v)
+	}
+	for _, v := range a.Spawns {
+		appendEntry(fmt.Sprintf("spawns/%d.json", v.ID), v)
+	}
+	for _, v := range a.SpawnMessages {
+		appendEntry(fmt.Sprintf("spawns/%d/messages/%d.json", v.SpawnID, v.ID), v)
+	}
+	for _, v := range a.ProfileStats {
+		appendEntry("stats/profiles/"+v.ProfileName+".json", v)
+	}
+	for _, v := range a.LoopStats {
+		appendEntry("stats/loops/"+v.LoopName+".json", v)
+	}
+	for _, v := range a.Schedules {
+		appendEntry(fmt.Sprintf("schedules/%d.json", v.ID), v)
+	}
+	for _, v := range a.Approvals {
+		appendEntry(fmt.Sprintf("approvals/%d.json", v.ID), v)
+	}
+	for _, v := range a.PullRequests {
+		appendEntry(fmt.Sprintf("pulls/%d.json", v.ID), v)
+	}
+	for _, v := range a.Recordings {
+		if v.Recording != nil {
+			appendEntry(fmt.Sprintf("records/%d/recording.json", v.TurnID), v.Recording)
+		}
+		if len(v.Events) > 0 {
+			appendEntry(fmt.Sprintf("records/%d/events.jsonl", v.TurnID), v.Events)
+		}
+	}
+
+	for _, e := range entries {
+		if err := add(e.name, e.v); err != nil {
+			return nil, fmt.Errorf("writing archive entry %s: %w", e.name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return &a.Manifest, nil
+}
+
+func (s *Store) collectArchive(opts ExportOptions) (*Archive, error) {
+	project, err := s.LoadProject()
+	if err != nil {
+		return nil, fmt.Errorf("loading project: %w", err)
+	}
+	a := &Archive{Project: *project}
+
+	if a.Plans, err = s.ListPlans(); err != nil {
+		return nil, fmt.Errorf("listing plans: %w", err)
+	}
+	if a.Issues, err = s.ListIssues(); err != nil {
+		return nil, fmt.Errorf("listing issues: %w", err)
+	}
+	if a.Wiki, err = s.ListWiki(); err != nil {
+		return nil, fmt.Errorf("listing wiki: %w", err)
+	}
+	if a.Turns, err = s.ListTurns(); err != nil {
+		return nil, fmt.Errorf("listing turns: %w", err)
+	}
+	if a.LoopRuns, err = s.ListLoopRuns(); err != nil {
+		return nil, fmt.Errorf("listing loop runs: %w", err)
+	}
+	for _, run := range a.LoopRuns {
+		msgs, err := s.ListLoopMessages(run.ID)
+		if err != nil {
+			return nil, fmt.Errorf("listing loop run %d messages: %w", run.ID, err)
+		}
+		a.LoopMessages = append(a.LoopMessages, msgs...)
+	}
+	if a.Spawns, err = s.ListSpawns(); err != nil {
+		return nil, fmt.Errorf("listing spawns: %w", err)
+	}
+	for _, rec := range a.Spawns {
+		msgs, err := s.ListMessages(rec.ID)
+		if err != nil {
+			return nil, fmt.Errorf("listing spawn %d messages: %w", rec.ID, err)
+		}
+		a.SpawnMessages = append(a.SpawnMessages, msgs...)
+	}
+	if a.ProfileStats, err = s.ListProfileStats(); err != nil {
+		return nil, fmt.Errorf("listing profile stats: %w", err)
+	}
+	if a.LoopStats, err = s.ListLoopStats(); err != nil {
+		return nil, fmt.Errorf("listing loop stats: %w", err)
+	}
+	if a.Schedules, err = s.ListSchedules(); err != nil {
+		return nil, fmt.Errorf("listing schedules: %w", err)
+	}
+	if a.Approvals, err = s.ListApprovals(); err != nil {
+		return nil, fmt.Errorf("listing approvals: %w", err)
+	}
+	if a.PullRequests, err = s.ListPullRequests(); err != nil {
+		return nil, fmt.Errorf("listing pull requests: %w", err)
+	}
+	if opts.IncludeRecordings {
+		for _, dir := range s.RecordsDirs() {
+			entries, _ := os.ReadDir(dir)
+			for _, e := range entries {
+				turnID, err := strconv.Atoi(e.Name())
+				if err != nil || !e.IsDir() {
+					continue
+				}
+				rec := ArchivedRecording{TurnID: turnID}
+				if r, err := s.LoadRecording(turnID); err == nil {
+					rec.Recording = r
+				}
+				if data, err := os.ReadFile(filepath.Join(dir, e.Name(), "events.jsonl")); err == nil {
+					rec.Events = data
+				}
+				if rec.Recording != nil || len(rec.Events) > 0 {
+					a.Recordings = append(a.Recordings, rec)
+				}
+			}
+		}
+		sort.Slice(a.Recordings, func(i, j int) bool { return a.Recordings[i].TurnID < a.Recordings[j].TurnID })
+	}
+
+	a.Manifest = ArchiveManifest{
+		Format:         ArchiveFormat,
+		ArchiveVersion: ArchiveVersion,
+		SchemaVersion:  SchemaVersion,
+		ProjectID:      s.projectID,
+		ProjectName:    project.Name,
+		ExportedAt:     time.Now().UTC(),
+		Recordings:     opts.IncludeRecordings,
+		Counts:         a.counts(),
+	}
+	return a, nil
+}
+
+func (a *Archive) counts() map[string]int {
+	counts := map[string]int{
+		ArchiveKindPlans:         len(a.Plans),
+		ArchiveKindIssues:        len(a.Issues),
+		ArchiveKindWiki:          len(a.Wiki),
+		ArchiveKindTurns:         len(a.Turns),
+		ArchiveKindLoopRuns:      len(a.LoopRuns),
+		ArchiveKindLoopMessages:  len(a.LoopMessages),
+		ArchiveKindSpawns:        len(a.Spawns),
+		ArchiveKindSpawnMessages: len(a.SpawnMessages),
+		ArchiveKindProfileStats:  len(a.ProfileStats),
+		ArchiveKindLoopStats:     len(a.LoopStats),
+		ArchiveKindSchedules:     len(a.Schedules),
+		ArchiveKindApprovals:     len(a.Approvals),
+		ArchiveKindPullRequests:  len(a.PullRequests),
+	}
+	if len(a.Recordings) > 0 {
+		counts[ArchiveKindRecordings] = len(a.Recordings)
+	}
+	return counts
+}
+
+// ReadArchive decodes and validates a project archive. Records are decoded
+// strictly against the store types, so an archive written by a different
+// schema version is rejected instead of silently losing fields.
+func ReadArchive(r io.Reader) (*Archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	files := make(map[string][]byte)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		if strings.HasPrefix(name, "/") || strings.HasPrefix(name, "..") {
+			return nil, fmt.Errorf("archive entry %q escapes the archive root", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("reading archive entry %s: %w", name, err)
+		}
+		files[name] = data
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	a := &Archive{}
+	manifest, ok := files["manifest.json"]
+	if !ok {
+		return nil, fmt.Errorf("archive has no manifest.json")
+	}
+	if err := json.Unmarshal(manifest, &a.Manifest); err != nil {
+		return nil, fmt.Errorf("decoding manifest: %w", err)
+	}
+	if err := a.Manifest.validate(); err != nil {
+		return nil, err
+	}
+	if data, ok := files["project.json"]; ok {
+		if err := decodeArchiveRecord("project.json", data, &a.Project); err != nil {
+			return nil, err
+		}
+	}
+
+	recordings := make(map[int]*ArchivedRecording)
+	for _, name := range names {
+		data := files[name]
+		parts := strings.Split(name, "/")
+		var err error
+		switch {
+		case name == "manifest.json" || name == "project.json":
+			continue
+		case len(parts) == 2 && parts[0] == "plans":
+			err = appendArchiveRecord(name, data, &a.Plans)
+		case len(parts) == 2 && parts[0] == "issues":
+			err = appendArchiveRecord(name, data, &a.Issues)
+		case len(parts) == 2 && parts[0] == "wiki":
+			err = appendArchiveRecord(name, data, &a.Wiki)
+		case len(parts) == 2 && parts[0] == "turns":
+			err = appendArchiveRecord(name, data, &a.Turns)
+		case len(parts) == 2 && parts[0] == "loopruns":
+			err = appendArchiveRecord(name, data, &a.LoopRuns)
+		case len(parts) == 4 && parts[0] == "loopruns" && parts[2] == "messages":
+			err = appendArchiveRecord(name, data, &a.LoopMessages)
+		case len(parts) == 2 && parts[0] == "spawns":
+			err = appendArchiveRecord(name, data, &a.Spawns)
+		case len(parts) == 4 && parts[0] == "spawns" && parts[2] == "messages":
+			err = appendArchiveRecord(name, data, &a.SpawnMessages)
+		case len(parts) == 3 && parts[0] == "stats" && parts[1] == "profiles":
+			err = appendArchiveRecord(name, data, &a.ProfileStats)
+		case len(parts) == 3 && parts[0] == "stats" && parts[1] == "loops":
+			err = appendArchiveRecord(name, data, &a.LoopStats)
+		case len(parts) == 2 && parts[0] == "schedules":
+			err = appendArchiveRecord(name, data, &a.Schedules)
+		case len(parts) == 2 && parts[0] == "approvals":
+			err = appendArchiveRecord(name, data, &a.Approvals)
+		case len(parts) == 2 && parts[0] == "pulls":
+			err = appendArchiveRecord(name, data, &a.PullRequests)
+		case len(parts) == 3 && parts[0] == "records":
+			turnID, convErr := strconv.Atoi(parts[1])
+			if convErr != nil || turnID <= 0 {
+				return nil, fmt.Errorf("archive entry %s: invalid turn ID", name)
+			}
+			rec := recordings[turnID]
+			if rec == nil {
+				rec = &ArchivedRecording{TurnID: turnID}
+				recordings[turnID] = rec
+			}
+			switch parts[2] {
+			case "recording.json":
+				rec.Recording = &TurnRecording{}
+				err = decodeArchiveRecord(name, data, rec.Recording)
+			case "events.jsonl":
+				rec.Events = data
+			default:
+				err = fmt.Errorf("archive entry %s: unknown recording file", name)
+			}
+		default:
+			err = fmt.Errorf("archive entry %s: unknown entry", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, rec := range recordings {
+		a.Recordings = append(a.Recordings, *rec)
+	}
+	sort.Slice(a.Recordings, func(i, j int) bool { return a.Recordings[i].TurnID < a.Recordings[j].TurnID })
+
+	if err := a.validate(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (m ArchiveManifest) validate() error {
+	if m.Format != ArchiveFormat {
+		return fmt.Errorf("not an adaf archive (format %q)", m.Format)
+	}
+	if m.ArchiveVersion < 1 || m.ArchiveVersion > ArchiveVersion {
+		return fmt.Errorf("unsupported archive version %d (this adaf reads up to %d)", m.ArchiveVersion, ArchiveVersion)
+	}
+	if m.SchemaVersion < 1 || m.SchemaVersion > SchemaVersion {
+		return fmt.Errorf("archive schema version %d is not supported (this adaf uses %d); upgrade adaf to import it", m.SchemaVersion, SchemaVersion)
+	}
+	return nil
+}
+
+func decodeArchiveRecord(name string, data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("archive entry %s does not match the store schema: %w", name, err)
+	}
+	return nil
+}
+
+func appendArchiveRecord[T any](name string, data []byte, out *[]T) error {
+	var v T
+	if err := decodeArchiveRecord(name, data, &v); err != nil {
+		return err
+	}
+	*out = append(*out, v)
+	return nil
+}
+
+// validate checks record identities and that counts match the manifest.
+func (a *Archive) validate() error {
+	seen := make(map[string]bool)
+	check := func(kind, id string, ok bool) error {
+		if !ok {
+			return fmt.Errorf("archive %s record has invalid ID %q", kind, id)
+		}
+		key := kind + "/" + id
+		if seen[key] {
+			return fmt.Errorf("archive has duplicate %s record %q", kind, id)
+		}
+		seen[key] = true
+		return nil
+	}
+	for _, v := range a.Plans {
+		if err := check(ArchiveKindPlans, v.ID, validArchiveName(v.ID)); err != nil {
+			return err
+		}
+	}
+	for _, v := range a.Wiki {
+		if err := check(ArchiveKindWiki, v.ID, validArchiveName(v.ID)); err != nil {
+			return err
+		}
+	}
+	for _, v := range a.Issues {
+		if err := check(ArchiveKindIssues, strconv.Itoa(v.ID), v.ID > 0); err != nil {
+			return err
+		}
+	}
+	for _, v := range a.Turns {
+		if err := check(ArchiveKindTurns, strconv.Itoa(v.ID), v.ID > 0); err != nil {
+			return err
+		}
+	}
+	for _, v := range a.LoopRuns {
+		if err := check(ArchiveKindLoopRuns, strconv.Itoa(v.ID), v.ID > 0); err != nil {
+			return err
+		}
+	}
+	for _, v := range a.LoopMessages {
+		if err := check(ArchiveKindLoopMessages, fmt.Sprintf("%d/%d", v.RunID, v.ID), v.ID > 0 && v.RunID > 0); err != nil {
+			return err
+		}
+	}
+	for _, v := range a.Spawns {
+		if err := check(ArchiveKindSpawns, strconv.Itoa(v.ID), v.ID > 0); err != nil {
+			return err
+		}
+	}
+	for _, v := range a.SpawnMessages {
+		if err := check(ArchiveKindSpawnMessages, fmt.Sprintf("%d/%d", v.SpawnID, v.ID), v.ID > 0 && v.SpawnID > 0); err != nil {
+			return err
+		}
+	}
+	for _, v := range a.ProfileStats {
+		if err := check(ArchiveKindProfileStats, v.ProfileName, validArchiveName(v.ProfileName)); err != nil {
+			return err
+		}
+	}
+	for _, v := range a.LoopStats {
+		if err := check(ArchiveKindLoopStats, v.LoopName, validArchiveName(v.LoopName)); err != nil {
+			return err
+		}
+	}
+	for _, v := range a.Schedules {
+		if err := check(ArchiveKindSchedules, strconv.Itoa(v.ID), v.ID > 0); err != nil {
+			return err
+		}
+	}
+	for _, v := range a.Approvals {
+		if err := check(ArchiveKindApprovals, strconv.Itoa(v.ID), v.ID > 0); err != nil {
+			return err
+		}
+	}
+	for _, v := range a.PullRequests {
+		if err := check(ArchiveKindPullRequests, strconv.Itoa(v.ID), v.ID > 0); err != nil {
+			return err
+		}
+	}
+	for kind, want := range a.Manifest.Counts {
+		if got := a.counts()[kind]; got != want {
+			return fmt.Errorf("archive manifest lists %d %s but the archive contains %d", want, kind, got)
+		}
+	}
+	return nil
+}
+
+func validArchiveName(id string) bool {
+	return strings.TrimSpace(id) != "" && id != "." && id != ".." && !strings.ContainsAny(id, `/\`)
+}
+
+// Import merges a project archive into the store. Numeric IDs are kept when
+// free and remapped otherwise (or always, with RemapIDs); plans and wiki
+// entries whose ID is taken get a suffixed ID. References between records
+// follow the remapping. Loop runs and spawns that were in flight at export
+// time are imported as stopped.
+func (s *Store) Import(a *Archive, opts ImportOptions) (*ImportReport, error) {
+	if !s.Exists() {
+		return nil, fmt.Errorf("project store is not initialized")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := &ImportReport{
+		Manifest: a.Manifest,
+		Imported: make(map[string]int),
+		Merged:   make(map[string]int),
+		Remapped: make(map[string]int),
+		Renamed:  make(map[string]string),
+	}
+
+	plans := remapArchiveNames(ArchiveKindPlans, a.Plans, func(p Plan) string { return p.ID }, s.planPath, report)
+	wiki := remapArchiveNames(ArchiveKindWiki, a.Wiki, func(e WikiEntry) string { return e.ID },
+		func(id string) string { return filepath.Join(s.root, "wiki", id+".json") }, report)
+	issues := s.remapArchiveIDs(ArchiveKindIssues, filepath.Join(s.root, "issues"), issueIDs(a.Issues), opts.RemapIDs, report)
+	turns := s.remapArchiveIDs(ArchiveKindTurns, s.turnsDir(), turnIDs(a.Turns), opts.RemapIDs, report)
+	runs := s.remapArchiveIDs(ArchiveKindLoopRuns, s.localDir("loopruns"), loopRunIDs(a.LoopRuns), opts.RemapIDs, report)
+	spawns := s.remapArchiveIDs(ArchiveKindSpawns, s.localDir("spawns"), spawnIDs(a.Spawns), opts.RemapIDs, report)
+	schedules := s.remapArchiveIDs(ArchiveKindSchedules, s.localDir("schedules"), archiveIDs(a.Schedules, func(v LoopSchedule) int { return v.ID }), opts.RemapIDs, report)
+	approvals := s.remapArchiveIDs(ArchiveKindApprovals, s.localDir("approvals"), archiveIDs(a.Approvals, func(v Approval) int { return v.ID }), opts.RemapIDs, report)
+	pulls := s.remapArchiveIDs(ArchiveKindPullRequests, s.localDir("pulls"), archiveIDs(a.PullRequests, func(v PullRequest) int { return v.ID }), opts.RemapIDs, report)
+
+	mapPlan := func(id string) string {
+		if id == "" {
+			return ""
+		}
+		if mapped, ok := plans[id]; ok {
+			return mapped
+		}
+		return id
+	}
+
+	var writes []archiveWrite
+	var shared []string
+	spawnClaims := make(map[int][]int)
+	for _, p := range a.Plans {
+		p.ID = plans[p.ID]
+		writes = append(writes, archiveWrite{s.planPath(p.ID), p})
+		shared = append(shared, "plans/"+p.ID+".json")
+		report.Imported[ArchiveKindPlans]++
+	}
+	for _, e := range a.Wiki {
+		e.ID = wiki[e.ID]
+		e.PlanID = mapPlan(e.PlanID)
+		writes = append(writes, archiveWrite{filepath.Join(s.root, "wiki", e.ID+".json"), e})
+		shared = append(shared, "wiki/"+e.ID+".json")
+		report.Imported[ArchiveKindWiki]++
+	}
+	for _, issue := range a.Issues {
+		issue.ID = issues.get(issue.ID)
+		issue.PlanID = mapPlan(issue.PlanID)
+		issue.DependsOn = issues.list(issue.DependsOn)
+		issue.TurnID = turns.get(issue.TurnID)
+		if c := issue.Claim; c != nil {
+			// A claim whose holder was not imported is dropped with it.
+			spawnID, turnID, runID := spawns.get(c.SpawnID), turns.get(c.TurnID), runs.get(c.LoopRunID)
+			if (c.SpawnID != 0 && spawnID == 0) || (c.TurnID != 0 && turnID == 0) || (c.LoopRunID != 0 && runID == 0) {
+				issue.Claim = nil
+			} else {
+				claim := *c
+				claim.SpawnID, claim.TurnID, claim.LoopRunID = spawnID, turnID, runID
+				issue.Claim = &claim
+				if claim.SpawnID != 0 {
+					spawnClaims[claim.SpawnID] = append(spawnClaims[claim.SpawnID], issue.ID)
+				}
+			}
+		}
+		name := fmt.Sprintf("%d.json", issue.ID)
+		writes = append(writes, archiveWrite{filepath.Join(s.root, "issues", name), issue})
+		shared = append(shared, "issues/"+name)
+		report.Imported[ArchiveKindIssues]++
+	}
+	for _, t := range a.Turns {
+		t.ID = turns.get(t.ID)
+		t.PlanID = mapPlan(t.PlanID)
+		t.RerunOfTurnID = turns.get(t.RerunOfTurnID)
+		t.FailoverFromTurnID = turns.get(t.FailoverFromTurnID)
+		writes = append(writes, archiveWrite{filepath.Join(s.turnsDir(), fmt.Sprintf("%d.json", t.ID)), t})
+		report.Imported[ArchiveKindTurns]++
+	}
+	for _, run := range a.LoopRuns {
+		run.ID = runs.get(run.ID)
+		run.PlanID = mapPlan(run.PlanID)
+		run.TurnIDs = turns.list(run.TurnIDs)
+		var handoffs []HandoffInfo
+		for _, h := range run.PendingHandoffs {
+			h.SpawnID = spawns.get(h.SpawnID)
+			h.TurnID = turns.get(h.TurnID)
+			handoffs = append(handoffs, h)
+		}
+		run.PendingHandoffs = handoffs
+		run.DaemonSessionID = 0
+		if run.Status == "running" {
+			run.Status = "stopped"
+			if run.StoppedAt.IsZero() {
+				run.StoppedAt = a.Manifest.ExportedAt
+			}
+		}
+		writes = append(writes, archiveWrite{s.loopRunPath(run.ID), run})
+		report.Imported[ArchiveKindLoopRuns]++
+	}
+	for _, msg := range a.LoopMessages {
+		msg.RunID = runs.get(msg.RunID)
+		if msg.RunID == 0 {
+			continue
+		}
+		writes = append(writes, archiveWrite{filepath.Join(s.loopMessagesDir(msg.RunID), fmt.Sprintf("%d.json", msg.ID)), msg})
+		report.Imported[ArchiveKindLoopMessages]++
+	}
+	for _, rec := range a.Spawns {
+		rec.ID = spawns.get(rec.ID)
+		rec.ParentTurnID = turns.get(rec.ParentTurnID)
+		rec.ChildTurnID = turns.get(rec.ChildTurnID)
+		rec.HandedOffToTurn = turns.get(rec.HandedOffToTurn)
+		rec.IssueIDs = issues.list(rec.IssueIDs)
+		rec.WorkspaceFromSpawnID = spawns.get(rec.WorkspaceFromSpawnID)
+		rec.ResolverSpawnID = spawns.get(rec.ResolverSpawnID)
+		for i := range rec.Failovers {
+			rec.Failovers[i].TurnID = turns.get(rec.Failovers[i].TurnID)
+		}
+		// Worktrees belong to the exporting machine.
+		rec.WorktreePath = ""
+		if !IsTerminalSpawnStatus(rec.Status) {
+			rec.Status = "canceled"
+			rec.QueuePosition = 0
+		}
+		writes = append(writes, archiveWrite{s.localDir("spawns", fmt.Sprintf("%d.json", rec.ID)), rec})
+		report.Imported[ArchiveKindSpawns]++
+	}
+	for _, msg := range a.SpawnMessages {
+		msg.SpawnID = spawns.get(msg.SpawnID)
+		if msg.SpawnID == 0 {
+			continue
+		}
+		writes = append(writes, archiveWrite{filepath.Join(s.messagesDir(msg.SpawnID), fmt.Sprintf("%d.json", msg.ID)), msg})
+		report.Imported[ArchiveKindSpawnMessages]++
+	}
+	for _, st := range a.ProfileStats {
+		st.TurnIDs = turns.list(st.TurnIDs)
+		var existing ProfileStats
+		if s.readJSON(s.profileStatsPath(st.ProfileName), &existing) == nil {
+			st = mergeProfileStats(existing, st)
+			report.Merged[ArchiveKindProfileStats]++
+		}
+		writes = append(writes, archiveWrite{s.profileStatsPath(st.ProfileName), st})
+		report.Imported[ArchiveKindProfileStats]++
+	}
+	for _, st := range a.LoopStats {
+		st.TurnIDs = turns.list(st.TurnIDs)
+		var existing LoopStats
+		if s.readJSON(s.loopStatsPath(st.LoopName), &existing) == nil {
+			st = mergeLoopStats(existing, st)
+			report.Merged[ArchiveKindLoopStats]++
+		}
+		writes = append(writes, archiveWrite{s.loopStatsPath(st.LoopName), st})
+		report.Imported[ArchiveKindLoopStats]++
+	}
+	for spawnID, ids := range spawnClaims {
+		var existing []int
+		_ = s.readJSON(s.spawnClaimsPath(spawnID), &existing)
+		for _, id := range ids {
+			if !slices.Contains(existing, id) {
+				existing = append(existing, id)
+			}
+		}
+		writes = append(writes, archiveWrite{s.spawnClaimsPath(spawnID), existing})
+	}
+	now := time.Now().UTC()
+	for _, sch := range a.Schedules {
+		sch.ID = schedules.get(sch.ID)
+		sch.PlanID = mapPlan(sch.PlanID)
+		// Sessions belong to the exporting machine, and the time the
+		// schedule spent in the archive is not a run of missed fires.
+		sch.LastSessionID = 0
+		sch.LastCheckedAt = now
+		writes = append(writes, archiveWrite{s.schedulePath(sch.ID), sch})
+		report.Imported[ArchiveKindSchedules]++
+	}
+	for _, ap := range a.Approvals {
+		ap.ID = approvals.get(ap.ID)
+		ap.SpawnID = spawns.get(ap.SpawnID)
+		ap.TurnID = turns.get(ap.TurnID)
+		ap.LoopRunID = runs.get(ap.LoopRunID)
+		// Loop runs are imported as stopped, so no step waits on these.
+		if ap.Kind == ApprovalKindLoopStep && ap.Status == ApprovalPending {
+			ap.Status = ApprovalCanceled
+		}
+		writes = append(writes, archiveWrite{s.approvalPath(ap.ID), ap})
+		report.Imported[ArchiveKindApprovals]++
+	}
+	for _, pr := range a.PullRequests {
+		pr.ID = pulls.get(pr.ID)
+		pr.SpawnID = spawns.get(pr.SpawnID)
+		pr.LoopRunID = runs.get(pr.LoopRunID)
+		pr.IssueIDs = issues.list(pr.IssueIDs)
+		writes = append(writes, archiveWrite{s.pullRequestPath(pr.ID), pr})
+		report.Imported[ArchiveKindPullRequests]++
+	}
+	for _, rec := range a.Recordings {
+		turnID := turns.get(rec.TurnID)
+		if turnID == 0 {
+			continue
+		}
+		dir := s.localDir("records", strconv.Itoa(turnID))
+		if rec.Recording != nil {
+			r := *rec.Recording
+			r.TurnID = turnID
+			writes = append(writes, archiveWrite{filepath.Join(dir, "recording.json"), r})
+		}
+		if len(rec.Events) > 0 {
+			writes = append(writes, archiveWrite{filepath.Join(dir, "events.jsonl"), rec.Events})
+		}
+		report.Imported[ArchiveKindRecordings]++
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	for _, w := range writes {
+		if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+			return nil, err
+		}
+		if raw, ok := w.v.([]byte); ok {
+			if err := os.WriteFile(w.path, raw, 0644); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := s.writeJSON(w.path, w.v); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", w.path, err)
+		}
+	}
+
+	project, err := s.LoadProject()
+	if err == nil && project.ActivePlanID == "" && a.Project.ActivePlanID != "" {
+		project.ActivePlanID = mapPlan(a.Project.ActivePlanID)
+		if err := s.SaveProject(project); err != nil {
+			return nil, err
+		}
+		shared = append(shared, "project.json")
+	}
+
+	// The next search rebuilds the index with the imported records.
+	_ = os.Remove(s.searchSnapshotPath())
+
+	s.AutoCommit(shared, fmt.Sprintf("adaf: import archive of %s", a.Manifest.ProjectName))
+	return report, nil
+}
+
+// archiveEntry is a file in the archive; v is JSON-encoded unless it is a
+// raw []byte.
+type archiveEntry struct {
+	name string
+	v    any
+}
+
+// archiveWrite is a store file written by Import, encoded like archiveEntry.
+type archiveWrite struct {
+	path string
+	v    any
+}
+
+// archiveIDMap maps archived numeric IDs to store IDs. References to IDs
+// that are not in the archive are kept when IDs are preserved and dropped
+// when they are remapped, so they never point at unrelated records.
+type archiveIDMap struct {
+	ids      map[int]int
+	remapped bool
+}
+
+func (m archiveIDMap) get(id int) int {
+	if id == 0 {
+		return 0
+	}
+	if mapped, ok := m.ids[id]; ok {
+		return mapped
+	}
+	if m.remapped {
+		return 0
+	}
+	return id
+}
+
+func (m archiveIDMap) list(ids []int) []int {
+	if len(ids) == 0 {
+		return ids
+	}
+	out := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if mapped := m.get(id); mapped != 0 {
+			out = append(out, mapped)
+		}
+	}
+	return out
+}
+
+// remapArchiveIDs keeps the archived IDs when none of them exist in dir and
+// force is unset; otherwise it assigns new IDs after the highest one in dir.
+func (s *Store) remapArchiveIDs(kind, dir string, ids []int, force bool, report *ImportReport) archiveIDMap {
+	m := archiveIDMap{ids: make(map[int]int, len(ids))}
+	conflict := force
+	for _, id := range ids {
+		if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf("%d.json", id))); err == nil {
+			conflict = true
+			break
+		}
+	}
+	if !conflict {
+		for _, id := range ids {
+			m.ids[id] = id
+		}
+		return m
+	}
+	m.remapped = true
+	next := s.nextID(dir)
+	sort.Ints(ids)
+	for _, id := range ids {
+		m.ids[id] = next
+		if id != next {
+			report.Remapped[kind]++
+		}
+		next++
+	}
+	return m
+}
+
+// remapArchiveNames maps string IDs, suffixing those already taken.
+func remapArchiveNames[T any](kind string, items []T, idOf func(T) string, pathOf func(string) string, report *ImportReport) map[string]string {
+	out := make(map[string]string, len(items))
+	taken := make(map[string]bool)
+	for _, item := range items {
+		id := idOf(item)
+		mapped := id
+		for n := 1; ; n++ {
+			if _, err := os.Stat(pathOf(mapped)); os.IsNotExist(err) && !taken[mapped] {
+				break
+			}
+			if n == 1 {
+				mapped = id + "-imported"
+			} else {
+				mapped = fmt.Sprintf("%s-imported-%d", id, n)
+			}
+		}
+		taken[mapped] = true
+		out[id] = mapped
+		if mapped != id {
+			report.Renamed[kind+"/"+id] = mapped
+		}
+	}
+	return out
+}
+
+func archiveIDs[T any](items []T, idOf func(T) int) []int {
+	ids := make([]int, len(items))
+	for i, v := range items {
+		ids[i] = idOf(v)
+	}
+	return ids
+}
+
+func issueIDs(items []Issue) []int {
+	ids := make([]int, len(items))
+	for i, v := range items {
+		ids[i] = v.ID
+	}
+	return ids
+}
+
+func turnIDs(items []Turn) []int {
+	ids := make([]int, len(items))
+	for i, v := range items {
+		ids[i] = v.ID
+	}
+	return ids
+}
+
+func loopRunIDs(items []LoopRun) []int {
+	ids := make([]int, len(items))
+	for i, v := range items {
+		ids[i] = v.ID
+	}
+	return ids
+}
+
+func spawnIDs(items []SpawnRecord) []int {
+	ids := make([]int, len(items))
+	for i, v := range items {
+		ids[i] = v.ID
+	}
+	return ids
+}
+
+func mergeProfileStats(dst, src ProfileStats) ProfileStats {
+	dst.TotalRuns += src.TotalRuns
+	dst.TotalTurns += src.TotalTurns
+	dst.TotalDuration += src.TotalDuration
+	dst.TotalCostUSD += src.TotalCostUSD
+	dst.EstimatedCost += src.EstimatedCost
+	dst.TotalInputTok += src.TotalInputTok
+	dst.TotalCachedTok += src.TotalCachedTok
+	dst.TotalOutputTok += src.TotalOutputTok
+	dst.TotalReasonTok += src.TotalReasonTok
+	dst.SpawnsCreated += src.SpawnsCreated
+	dst.SuccessCount += src.SuccessCount
+	dst.FailureCount += src.FailureCount
+	dst.ToolCalls = mergeCounts(dst.ToolCalls, src.ToolCalls)
+	dst.SpawnedBy = mergeCounts(dst.SpawnedBy, src.SpawnedBy)
+	dst.TurnIDs = append(dst.TurnIDs, src.TurnIDs...)
+	if src.LastRunAt.After(dst.LastRunAt) {
+		dst.LastRunAt = src.LastRunAt
+		if src.Model != "" {
+			dst.Model = src.Model
+		}
+	}
+	dst.UpdatedAt = time.Now().UTC()
+	return dst
+}
+
+func mergeLoopStats(dst, src LoopStats) LoopStats {
+	dst.TotalCycles += src.TotalCycles
+	dst.TotalRuns += src.TotalRuns
+	dst.TotalCostUSD += src.TotalCostUSD
+	dst.EstimatedCost += src.EstimatedCost
+	dst.TotalDuration += src.TotalDuration
+	dst.StepStats = mergeCounts(dst.StepStats, src.StepStats)
+	dst.TurnIDs = append(dst.TurnIDs, src.TurnIDs...)
+	if src.LastRunAt.After(dst.LastRunAt) {
+		dst.LastRunAt = src.LastRunAt
+	}
+	dst.UpdatedAt = time.Now().UTC()
+	return dst
+}
+
+func mergeCounts(dst, src map[string]int) map[string]int {
+	if dst == nil {
+		dst = make(map[string]int, len(src))
+	}
+	for k, v := range src {
+		dst[k] += v
+	}
+	return dst
+}
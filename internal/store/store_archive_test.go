@@ -0,0 +1,163 @@
+package store
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportImportRoundTripAndMerge(t *testing.T) {
+	src := newSearchTestStore(t)
+	if err := src.CreatePlan(&Plan{ID: "main", Title: "Main"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.CreateTurn(&Turn{Agent: "claude", PlanID: "main", Objective: "Build auth"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.CreateIssue(&Issue{Title: "Login bug", Status: "open", Priority: "high", PlanID: "main", TurnID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.CreateIssue(&Issue{Title: "Follow-up", Status: "open", Priority: "low", DependsOn: []int{1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.CreateSpawn(&SpawnRecord{ParentTurnID: 1, ChildProfile: "worker", Status: "running", WorktreePath: "/tmp/wt"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.SaveRecording(&TurnRecording{TurnID: 1, Agent: "claude", Events: []RecordingEvent{{Type: "stdout", Data: "hello"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	manifest, err := src.Export(&buf, ExportOptions{IncludeRecordings: true})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if manifest.SchemaVersion != SchemaVersion || manifest.Counts[ArchiveKindIssues] != 2 || manifest.Counts[ArchiveKindRecordings] != 1 {
+		t.Fatalf("manifest = %+v", manifest)
+	}
+	data := buf.Bytes()
+
+	dst := newSearchTestStore(t)
+	a, err := ReadArchive(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadArchive: %v", err)
+	}
+	if _, err := dst.Import(a, ImportOptions{}); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	issue, err := dst.GetIssue(2)
+	if err != nil || issue.Title != "Follow-up" || len(issue.DependsOn) != 1 || issue.DependsOn[0] != 1 {
+		t.Fatalf("fresh import kept IDs: issue = %+v, err = %v", issue, err)
+	}
+	spawn, err := dst.GetSpawn(1)
+	if err != nil || spawn.Status != "canceled" || spawn.WorktreePath != "" {
+		t.Fatalf("imported spawn = %+v, err = %v; want canceled without worktree", spawn, err)
+	}
+	if rec, err := dst.LoadRecording(1); err != nil || rec.TurnID != 1 {
+		t.Fatalf("imported recording = %+v, err = %v", rec, err)
+	}
+
+	// Importing the same archive again merges with remapped IDs.
+	a, _ = ReadArchive(bytes.NewReader(data))
+	report, err := dst.Import(a, ImportOptions{})
+	if err != nil {
+		t.Fatalf("second Import: %v", err)
+	}
+	if got := report.Renamed["plans/main"]; got != "main-imported" {
+		t.Fatalf("renamed plan = %q, want main-imported", got)
+	}
+	issue, err = dst.GetIssue(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Title != "Follow-up" || len(issue.DependsOn) != 1 || issue.DependsOn[0] != 3 {
+		t.Fatalf("remapped issue = %+v, want depends_on [3]", issue)
+	}
+	first, _ := dst.GetIssue(3)
+	if first.PlanID != "main-imported" || first.TurnID != 2 {
+		t.Fatalf("remapped issue refs = plan %q turn %d, want main-imported / 2", first.PlanID, first.TurnID)
+	}
+	spawn, err = dst.GetSpawn(2)
+	if err != nil || spawn.ParentTurnID != 2 {
+		t.Fatalf("remapped spawn = %+v, err = %v", spawn, err)
+	}
+}
+
+func TestExportImportCarriesSchedulesApprovalsPullsAndClaims(t *testing.T) {
+	src := newSearchTestStore(t)
+	if err := src.CreateIssue(&Issue{Title: "Login bug", Status: "open", Priority: "high"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.CreateSpawn(&SpawnRecord{ParentTurnID: 1, ChildProfile: "worker", Status: "completed"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.ClaimIssue(1, IssueClaim{SpawnID: 1}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.CreateSchedule(&LoopSchedule{Name: "nightly", Cron: "@daily", Loop: "dev", LastSessionID: 12}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.CreateApproval(&Approval{Kind: ApprovalKindMerge, SpawnID: 1, Summary: "merge #1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.CreatePullRequest(&PullRequest{Number: 7, Title: "Fix login", SpawnID: 1, IssueIDs: []int{1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := src.Export(&buf, ExportOptions{}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	data := buf.Bytes()
+
+	// A second import remaps every ID, so references must follow.
+	dst := newSearchTestStore(t)
+	for i := 0; i < 2; i++ {
+		a, err := ReadArchive(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("ReadArchive: %v", err)
+		}
+		if _, err := dst.Import(a, ImportOptions{}); err != nil {
+			t.Fatalf("Import %d: %v", i+1, err)
+		}
+	}
+
+	sch, err := dst.GetSchedule(2)
+	if err != nil || sch.Name != "nightly" || sch.LastSessionID != 0 {
+		t.Fatalf("imported schedule = %+v, err = %v; want nightly without a session", sch, err)
+	}
+	ap, err := dst.GetApproval(2)
+	if err != nil || ap.SpawnID != 2 || ap.Status != ApprovalPending {
+		t.Fatalf("imported approval = %+v, err = %v; want pending for spawn 2", ap, err)
+	}
+	pr, err := dst.GetPullRequest(2)
+	if err != nil || pr.Number != 7 || pr.SpawnID != 2 || len(pr.IssueIDs) != 1 || pr.IssueIDs[0] != 2 {
+		t.Fatalf("imported pull request = %+v, err = %v", pr, err)
+	}
+	issue, err := dst.GetIssue(2)
+	if err != nil || issue.Claim == nil || issue.Claim.SpawnID != 2 {
+		t.Fatalf("imported issue claim = %+v, err = %v; want held by spawn 2", issue, err)
+	}
+	if released, err := dst.ReleaseSpawnClaims(2, "done"); err != nil || len(released) != 1 || released[0] != 2 {
+		t.Fatalf("ReleaseSpawnClaims(2) = %v, %v; want the imported claim indexed", released, err)
+	}
+}
+
+func TestReadArchiveRejectsNewerSchema(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	manifest := []byte(`{"format":"adaf-archive","archive_version":1,"schema_version":99,"counts":{}}`)
+	tw.WriteHeader(&tar.Header{Name: "manifest.json", Mode: 0644, Size: int64(len(manifest)), Typeflag: tar.TypeReg})
+	tw.Write(manifest)
+	tw.Close()
+	gz.Close()
+
+	_, err := ReadArchive(&buf)
+	if err == nil || !strings.Contains(err.Error(), "schema version 99") {
+		t.Fatalf("ReadArchive error = %v, want schema version error", err)
+	}
+}
@@ -0,0 +1,102 @@
+// store_inbox.go contains the operator inbox of pending parent-ask questions.
+package store
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// SpawnForTurn returns the spawn whose child ran turnID, or nil when the turn
+// is not a spawned child (e.g. a top-level loop turn).
+func (s *Store) SpawnForTurn(turnID int) (*SpawnRecord, error) {
+	if turnID <= 0 {
+		return nil, nil
+	}
+	records, err := s.ListSpawns()
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		if records[i].ChildTurnID == turnID {
+			return &records[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// UpdateMessage persists changes to a spawn message.
+func (s *Store) UpdateMessage(msg *SpawnMessage) error {
+	return s.writeJSONLocked(filepath.Join(s.messagesDir(msg.SpawnID), fmt.Sprintf("%d.json", msg.ID)), msg)
+}
+
+// PendingAsks returns the unanswered asks of every spawn awaiting input,
+// oldest first.
+func (s *Store) PendingAsks() ([]SpawnMessage, error) {
+	records, err := s.ListSpawns()
+	if err != nil {
+		return nil, err
+	}
+	var asks []SpawnMessage
+	for _, rec := range records {
+		if rec.Status != SpawnStatusAwaitingInput {
+			continue
+		}
+		ask, err := s.PendingAsk(rec.ID)
+		if err != nil || ask == nil {
+			continue
+		}
+		asks = append(asks, *ask)
+	}
+	sort.Slice(asks, func(i, j int) bool { return asks[i].CreatedAt.Before(asks[j].CreatedAt) })
+	return asks, nil
+}
+
+// ReplyToAsk answers a spawn's pending ask and puts the spawn back to
+// running. It returns the reply.
+func (s *Store) ReplyToAsk(spawnID int, answer, author string) (*SpawnMessage, error) {
+	ask, err := s.PendingAsk(spawnID)
+	if err != nil {
+		return nil, err
+	}
+	if ask == nil {
+		return nil, fmt.Errorf("no pending question from spawn #%d", spawnID)
+	}
+	reply := &SpawnMessage{
+		SpawnID:   spawnID,
+		Direction: "parent_to_child",
+		Type:      "reply",
+		Content:   answer,
+		ReplyToID: ask.ID,
+		Author:    author,
+	}
+	if err := s.CreateMessage(reply); err != nil {
+		return nil, fmt.Errorf("creating reply: %w", err)
+	}
+	if rec, err := s.GetSpawn(spawnID); err == nil && rec.Status == SpawnStatusAwaitingInput {
+		rec.Status = SpawnStatusRunning
+		s.UpdateSpawn(rec)
+	}
+	return reply, nil
+}
+
+// EscalateAsk moves an unanswered ask to the next ancestor turn of its
+// current addressee, or to the human once no spawned ancestor is left.
+func (s *Store) EscalateAsk(ask *SpawnMessage) error {
+	if ask.ToHuman {
+		return nil
+	}
+	parent, err := s.SpawnForTurn(ask.AddresseeTurnID)
+	if err != nil {
+		return err
+	}
+	if parent != nil && parent.ParentTurnID > 0 {
+		ask.AddresseeTurnID = parent.ParentTurnID
+	} else {
+		ask.AddresseeTurnID = 0
+		ask.ToHuman = true
+	}
+	ask.EscalatedAt = time.Now().UTC()
+	return s.UpdateMessage(ask)
+}
@@ -0,0 +1,64 @@
+package store
+
+import "testing"
+
+func TestAskEscalationAndReply(t *testing.T) {
+	dir := t.TempDir()
+	s, err := New(dir)
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	if err := s.Init(ProjectConfig{Name: "inbox-test", RepoPath: dir}); err != nil {
+		t.Fatalf("store.Init() error = %v", err)
+	}
+
+	// Loop turn 10 spawned a manager (turn 20), which spawned a worker.
+	manager := &SpawnRecord{ParentTurnID: 10, ChildProfile: "manager", ChildTurnID: 20, Status: SpawnStatusRunning}
+	if err := s.CreateSpawn(manager); err != nil {
+		t.Fatalf("CreateSpawn(manager) error = %v", err)
+	}
+	worker := &SpawnRecord{ParentTurnID: 20, ChildProfile: "worker", ChildTurnID: 30, Status: SpawnStatusAwaitingInput}
+	if err := s.CreateSpawn(worker); err != nil {
+		t.Fatalf("CreateSpawn(worker) error = %v", err)
+	}
+	ask := &SpawnMessage{SpawnID: worker.ID, Direction: "child_to_parent", Type: "ask", Content: "which API?", AddresseeTurnID: 20}
+	if err := s.CreateMessage(ask); err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+
+	asks, err := s.PendingAsks()
+	if err != nil || len(asks) != 1 || asks[0].SpawnID != worker.ID {
+		t.Fatalf("PendingAsks() = %+v, %v", asks, err)
+	}
+
+	if err := s.EscalateAsk(ask); err != nil {
+		t.Fatalf("EscalateAsk() error = %v", err)
+	}
+	if ask.ToHuman || ask.AddresseeTurnID != 10 {
+		t.Fatalf("after first escalation: addressee=%d to_human=%v, want turn 10", ask.AddresseeTurnID, ask.ToHuman)
+	}
+	if err := s.EscalateAsk(ask); err != nil {
+		t.Fatalf("EscalateAsk() error = %v", err)
+	}
+	if !ask.ToHuman {
+		t.Fatalf("after second escalation: addressee=%d, want human", ask.AddresseeTurnID)
+	}
+	stored, _ := s.PendingAsk(worker.ID)
+	if stored == nil || !stored.ToHuman || stored.EscalatedAt.IsZero() {
+		t.Fatalf("stored ask = %+v, want escalation persisted", stored)
+	}
+
+	reply, err := s.ReplyToAsk(worker.ID, "v2", "human")
+	if err != nil {
+		t.Fatalf("ReplyToAsk() error = %v", err)
+	}
+	if reply.ReplyToID != ask.ID || reply.Author != "human" {
+		t.Fatalf("reply = %+v", reply)
+	}
+	if rec, _ := s.GetSpawn(worker.ID); rec.Status != SpawnStatusRunning {
+		t.Fatalf("worker status = %q, want running", rec.Status)
+	}
+	if _, err := s.ReplyToAsk(worker.ID, "again", "human"); err == nil {
+		t.Fatal("second ReplyToAsk() must fail with no pending question")
+	}
+}
@@ -0,0 +1,287 @@
+// store_issue_claims.go contains issue claim (lease) methods.
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IssueClaimedError reports that an issue is leased to another holder.
+type IssueClaimedError struct {
+	IssueID int
+	Claim   IssueClaim
+}
+
+func (e *IssueClaimedError) Error() string {
+	return fmt.Sprintf("issue #%d is claimed by %s until %s", e.IssueID, e.Claim.Holder(), e.Claim.ExpiresAt.Format(time.RFC3339))
+}
+
+// Holder describes who holds the lease, e.g. "spawn #3".
+func (c IssueClaim) Holder() string {
+	switch {
+	case c.SpawnID > 0:
+		return fmt.Sprintf("spawn #%d", c.SpawnID)
+	case c.LoopRunID > 0:
+		return fmt.Sprintf("loop run #%d step %d", c.LoopRunID, c.LoopStep+1)
+	case c.TurnID > 0:
+		return fmt.Sprintf("turn #%d", c.TurnID)
+	default:
+		return "unknown"
+	}
+}
+
+// SameHolder reports whether c and other name the same spawn; else the same
+// loop step when both are loop leases, so every turn of a step shares its
+// leases; else the same turn.
+func (c IssueClaim) SameHolder(other IssueClaim) bool {
+	if c.SpawnID > 0 || other.SpawnID > 0 {
+		return c.SpawnID == other.SpawnID
+	}
+	if c.LoopRunID > 0 && other.LoopRunID > 0 {
+		return c.LoopRunID == other.LoopRunID && c.LoopStep == other.LoopStep
+	}
+	return c.TurnID > 0 && c.TurnID == other.TurnID
+}
+
+// TurnClaimHolder returns the lease identity of a turn: its spawn when the
+// turn belongs to a spawned agent, else the turn together with the loop step
+// it ran in, if any.
+func (s *Store) TurnClaimHolder(turnID int) IssueClaim {
+	holder := IssueClaim{TurnID: turnID}
+	if rec, err := s.SpawnForTurn(turnID); err == nil && rec != nil {
+		holder.SpawnID = rec.ID
+		return holder
+	}
+	turn, err := s.GetTurn(turnID)
+	if err != nil || turn.LoopRunHexID == "" || turn.StepHexID == "" {
+		return holder
+	}
+	runs, err := s.ListLoopRuns()
+	if err != nil {
+		return holder
+	}
+	for _, run := range runs {
+		if run.HexID != turn.LoopRunHexID {
+			continue
+		}
+		for key, hex := range run.StepHexIDs {
+			if hex != turn.StepHexID {
+				continue
+			}
+			// Keys are "cycle:step".
+			_, stepStr, _ := strings.Cut(key, ":")
+			if step, err := strconv.Atoi(stepStr); err == nil {
+				holder.LoopRunID = run.ID
+				holder.LoopStep = step
+			}
+			return holder
+		}
+	}
+	return holder
+}
+
+// ActiveClaim returns the issue's lease if it is still in force at now.
+func (i *Issue) ActiveClaim(now time.Time) *IssueClaim {
+	if i == nil || i.Claim == nil || !now.Before(i.Claim.ExpiresAt) {
+		return nil
+	}
+	return i.Claim
+}
+
+// ClaimIssue leases an issue to the turn or spawn named in claim for ttl. The
+// holder of an active lease may renew it; anyone else gets an
+// *IssueClaimedError. Closed issues cannot be claimed.
+func (s *Store) ClaimIssue(id int, claim IssueClaim, ttl time.Duration) (*Issue, error) {
+	if claim.SpawnID <= 0 && claim.TurnID <= 0 {
+		return nil, fmt.Errorf("a claim needs a turn or spawn ID")
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("claim lease must be positive, got %s", ttl)
+	}
+	return s.updateIssueClaim(id, func(issue *Issue, now time.Time) (*IssueHistory, error) {
+		if IsTerminalIssueStatus(issue.Status) {
+			return nil, fmt.Errorf("issue #%d is closed", id)
+		}
+		entry := &IssueHistory{Type: "claimed", Field: "claim", To: claim.Holder()}
+		if current := issue.ActiveClaim(now); current != nil {
+			if !current.SameHolder(claim) {
+				return nil, &IssueClaimedError{IssueID: id, Claim: *current}
+			}
+			claim.ClaimedAt = current.ClaimedAt
+			entry.Message = "lease renewed until " + now.Add(ttl).Format(time.RFC3339)
+		} else {
+			if issue.Claim != nil {
+				entry.From = issue.Claim.Holder() // expired lease
+			}
+			claim.ClaimedAt = now
+			entry.Message = "leased until " + now.Add(ttl).Format(time.RFC3339)
+		}
+		claim.ExpiresAt = now.Add(ttl)
+		issue.Claim = &claim
+		entry.By = resolveIssueActor(claim.By, claim.Holder())
+		return entry, nil
+	})
+}
+
+// ReleaseIssueClaim drops the lease on an issue. When holder is non-nil an
+// active lease is only released for that same holder; a nil holder forces
+// the release. Releasing an unclaimed issue is a no-op.
+func (s *Store) ReleaseIssueClaim(id int, holder *IssueClaim, reason string) (*Issue, error) {
+	return s.updateIssueClaim(id, func(issue *Issue, now time.Time) (*IssueHistory, error) {
+		if issue.Claim == nil {
+			return nil, nil
+		}
+		if holder != nil && issue.ActiveClaim(now) != nil && !issue.Claim.SameHolder(*holder) {
+			return nil, &IssueClaimedError{IssueID: id, Claim: *issue.Claim}
+		}
+		entry := &IssueHistory{Type: "released", Field: "claim", From: issue.Claim.Holder(), Message: reason}
+		if holder != nil {
+			entry.By = resolveIssueActor(holder.By, holder.Holder())
+		}
+		issue.Claim = nil
+		return entry, nil
+	})
+}
+
+// ReleaseSpawnClaims drops every lease held by a spawn and returns the
+// released issue IDs. The spawn's leases are found through its claim index.
+func (s *Store) ReleaseSpawnClaims(spawnID int, reason string) ([]int, error) {
+	var ids []int
+	if err := s.readJSON(s.spawnClaimsPath(spawnID), &ids); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var released []int
+	for _, id := range ids {
+		issue, err := s.ReleaseIssueClaim(id, &IssueClaim{SpawnID: spawnID}, reason)
+		if errors.Is(err, os.ErrNotExist) {
+			continue // deleted since it was claimed
+		}
+		if err != nil {
+			return released, err
+		}
+		if issue.Claim == nil {
+			released = append(released, id)
+		}
+	}
+	return released, nil
+}
+
+// spawnClaimsPath is the local index of the issues a spawn holds leases on.
+func (s *Store) spawnClaimsPath(spawnID int) string {
+	return s.localDir("claims", fmt.Sprintf("spawn-%d.json", spawnID))
+}
+
+// indexSpawnClaim adds (add=true) or removes an issue from a spawn's claim
+// index. Callers hold the issue lock.
+func (s *Store) indexSpawnClaim(spawnID, issueID int, add bool) error {
+	path := s.spawnClaimsPath(spawnID)
+	var ids []int
+	if err := s.readJSON(path, &ids); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	has := slices.Contains(ids, issueID)
+	switch {
+	case add && !has:
+		ids = append(ids, issueID)
+	case !add && has:
+		ids = slices.DeleteFunc(ids, func(id int) bool { return id == issueID })
+	default:
+		return nil
+	}
+	if len(ids) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return s.writeJSON(path, ids)
+}
+
+// updateIssueClaim applies fn to an issue under the issue lock and, when fn
+// returns a history entry, persists the issue with that entry appended.
+func (s *Store) updateIssueClaim(id int, fn func(issue *Issue, now time.Time) (*IssueHistory, error)) (*Issue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lf, err := s.lockIssues()
+	if err != nil {
+		return nil, err
+	}
+	defer unlockFile(lf)
+
+	filename := fmt.Sprintf("%d.json", id)
+	path := filepath.Join(s.root, "issues", filename)
+	var issue Issue
+	if err := s.readJSON(path, &issue); err != nil {
+		return nil, err
+	}
+
+	prevSpawn := claimSpawnID(issue.Claim)
+	now := time.Now().UTC()
+	entry, err := fn(&issue, now)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return &issue, nil
+	}
+	issue.History = normalizeIssueHistory(issue.History, issue.Created, issue.CreatedBy)
+	entry.ID = nextIssueHistoryID(issue.History)
+	entry.By = resolveIssueActor(entry.By, issue.UpdatedBy, issue.CreatedBy)
+	entry.At = now
+	issue.History = append(issue.History, *entry)
+
+	if err := s.writeJSON(path, &issue); err != nil {
+		return nil, err
+	}
+	if nextSpawn := claimSpawnID(issue.Claim); nextSpawn != prevSpawn {
+		if prevSpawn > 0 {
+			if err := s.indexSpawnClaim(prevSpawn, id, false); err != nil {
+				return nil, fmt.Errorf("updating claim index of spawn #%d: %w", prevSpawn, err)
+			}
+		}
+		if nextSpawn > 0 {
+			if err := s.indexSpawnClaim(nextSpawn, id, true); err != nil {
+				return nil, fmt.Errorf("updating claim index of spawn #%d: %w", nextSpawn, err)
+			}
+		}
+	}
+	verb := "claim"
+	if entry.Type == "released" {
+		verb = "release"
+	}
+	s.AutoCommit([]string{"issues/" + filename}, fmt.Sprintf("adaf: %s issue #%d", verb, id))
+	return &issue, nil
+}
+
+func claimSpawnID(c *IssueClaim) int {
+	if c == nil {
+		return 0
+	}
+	return c.SpawnID
+}
+
+// lockIssues takes the cross-process lock that serializes issue writes, so
+// claim changes never interleave with creates, updates or comments.
+func (s *Store) lockIssues() (*os.File, error) {
+	if err := os.MkdirAll(s.localDir(), 0755); err != nil {
+		return nil, err
+	}
+	lf, err := lockFile(s.localDir("issues"))
+	if err != nil {
+		return nil, fmt.Errorf("locking issues: %w", err)
+	}
+	return lf, nil
+}
@@ -0,0 +1,196 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newClaimTestStore(t *testing.T) *Store {
+	t.Helper()
+	dir := t.TempDir()
+	s, err := New(dir)
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	if err := s.Init(ProjectConfig{Name: "claim-test", RepoPath: dir}); err != nil {
+		t.Fatalf("store.Init() error = %v", err)
+	}
+	for _, title := range []string{"first", "second"} {
+		if err := s.CreateIssue(&Issue{Title: title}); err != nil {
+			t.Fatalf("CreateIssue(%s) error = %v", title, err)
+		}
+	}
+	return s
+}
+
+func TestClaimIssue_LeaseLifecycle(t *testing.T) {
+	s := newClaimTestStore(t)
+	turn := IssueClaim{TurnID: 7, By: "dev"}
+
+	issue, err := s.ClaimIssue(1, turn, time.Hour)
+	if err != nil {
+		t.Fatalf("ClaimIssue() error = %v", err)
+	}
+	claimedAt := issue.Claim.ClaimedAt
+	if issue.ActiveClaim(time.Now()) == nil || issue.Claim.TurnID != 7 {
+		t.Fatalf("claim = %+v", issue.Claim)
+	}
+
+	_, err = s.ClaimIssue(1, IssueClaim{SpawnID: 3}, time.Hour)
+	var claimed *IssueClaimedError
+	if !errors.As(err, &claimed) || claimed.Claim.TurnID != 7 {
+		t.Fatalf("ClaimIssue(other) error = %v, want IssueClaimedError", err)
+	}
+	if _, err := s.ReleaseIssueClaim(1, &IssueClaim{SpawnID: 3}, ""); !errors.As(err, &claimed) {
+		t.Fatalf("ReleaseIssueClaim(other) error = %v, want IssueClaimedError", err)
+	}
+
+	issue, err = s.ClaimIssue(1, turn, 2*time.Hour)
+	if err != nil {
+		t.Fatalf("ClaimIssue(renew) error = %v", err)
+	}
+	if !issue.Claim.ClaimedAt.Equal(claimedAt) || time.Until(issue.Claim.ExpiresAt) < time.Hour {
+		t.Fatalf("renewed claim = %+v", issue.Claim)
+	}
+
+	// Regular updates neither drop nor forge a claim.
+	issue.Title = "renamed"
+	issue.Claim = nil
+	if err := s.UpdateIssue(issue); err != nil {
+		t.Fatalf("UpdateIssue() error = %v", err)
+	}
+	if got, _ := s.GetIssue(1); got.Claim == nil || got.Claim.TurnID != 7 {
+		t.Fatalf("claim after UpdateIssue = %+v", got.Claim)
+	}
+
+	issue, err = s.ReleaseIssueClaim(1, &turn, "done for now")
+	if err != nil || issue.Claim != nil {
+		t.Fatalf("ReleaseIssueClaim() = %+v, %v", issue.Claim, err)
+	}
+	var types []string
+	for _, h := range issue.History {
+		types = append(types, h.Type)
+	}
+	want := []string{"created", "claimed", "claimed", "updated", "released"}
+	if len(types) != len(want) {
+		t.Fatalf("history types = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("history types = %v, want %v", types, want)
+		}
+	}
+	if last := issue.History[len(issue.History)-1]; last.From != "turn #7" || last.By != "dev" || last.Message != "done for now" {
+		t.Fatalf("release history = %+v", last)
+	}
+}
+
+func TestClaimIssue_ExpiredLeaseCanBeTaken(t *testing.T) {
+	s := newClaimTestStore(t)
+	if _, err := s.ClaimIssue(1, IssueClaim{TurnID: 7}, time.Nanosecond); err != nil {
+		t.Fatalf("ClaimIssue() error = %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	issue, err := s.ClaimIssue(1, IssueClaim{SpawnID: 4}, time.Hour)
+	if err != nil {
+		t.Fatalf("ClaimIssue(after expiry) error = %v", err)
+	}
+	if last := issue.History[len(issue.History)-1]; last.From != "turn #7" || last.To != "spawn #4" {
+		t.Fatalf("claim history = %+v", last)
+	}
+}
+
+func TestClaimIssue_LoopStepHoldsLeaseAcrossTurns(t *testing.T) {
+	s := newClaimTestStore(t)
+	run := &LoopRun{LoopName: "dev", HexID: "run1", StepHexIDs: map[string]string{"0:1": "stepa", "1:1": "stepb"}}
+	if err := s.CreateLoopRun(run); err != nil {
+		t.Fatalf("CreateLoopRun() error = %v", err)
+	}
+	turn := &Turn{Agent: "codex", LoopRunHexID: run.HexID, StepHexID: "stepb"}
+	if err := s.CreateTurn(turn); err != nil {
+		t.Fatalf("CreateTurn() error = %v", err)
+	}
+	if got := s.TurnClaimHolder(turn.ID); got.LoopRunID != run.ID || got.LoopStep != 1 || got.TurnID != turn.ID {
+		t.Fatalf("TurnClaimHolder() = %+v, want loop run #%d step index 1", got, run.ID)
+	}
+
+	if _, err := s.ClaimIssue(1, IssueClaim{TurnID: 10, LoopRunID: run.ID, LoopStep: 1}, time.Hour); err != nil {
+		t.Fatalf("ClaimIssue() error = %v", err)
+	}
+	// A later turn of the same step renews; another step is turned away.
+	issue, err := s.ClaimIssue(1, IssueClaim{TurnID: 11, LoopRunID: run.ID, LoopStep: 1}, time.Hour)
+	if err != nil {
+		t.Fatalf("ClaimIssue(next turn) error = %v", err)
+	}
+	if issue.Claim.Holder() != fmt.Sprintf("loop run #%d step 2", run.ID) {
+		t.Fatalf("holder = %q", issue.Claim.Holder())
+	}
+	var claimed *IssueClaimedError
+	if _, err := s.ClaimIssue(1, IssueClaim{TurnID: 12, LoopRunID: run.ID, LoopStep: 0}, time.Hour); !errors.As(err, &claimed) {
+		t.Fatalf("ClaimIssue(other step) error = %v, want IssueClaimedError", err)
+	}
+}
+
+func TestClaimIssue_OnlyOneConcurrentClaimWins(t *testing.T) {
+	s := newClaimTestStore(t)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	wins := 0
+	for i := 1; i <= 8; i++ {
+		wg.Add(1)
+		go func(spawnID int) {
+			defer wg.Done()
+			if _, err := s.ClaimIssue(2, IssueClaim{SpawnID: spawnID}, time.Hour); err == nil {
+				mu.Lock()
+				wins++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if wins != 1 {
+		t.Fatalf("%d concurrent claims succeeded, want 1", wins)
+	}
+}
+
+func TestUpdateSpawn_TerminalStatusReleasesClaims(t *testing.T) {
+	s := newClaimTestStore(t)
+	rec := &SpawnRecord{ParentTurnID: 1, ChildProfile: "worker", Status: SpawnStatusRunning}
+	if err := s.CreateSpawn(rec); err != nil {
+		t.Fatalf("CreateSpawn() error = %v", err)
+	}
+	for _, id := range []int{1, 2} {
+		if _, err := s.ClaimIssue(id, IssueClaim{SpawnID: rec.ID}, time.Hour); err != nil {
+			t.Fatalf("ClaimIssue(%d) error = %v", id, err)
+		}
+	}
+
+	rec.Status = SpawnStatusAwaitingInput
+	if err := s.UpdateSpawn(rec); err != nil {
+		t.Fatalf("UpdateSpawn() error = %v", err)
+	}
+	if issue, _ := s.GetIssue(1); issue.Claim == nil {
+		t.Fatal("non-terminal spawn update released the claim")
+	}
+
+	rec.Status = SpawnStatusCompleted
+	if err := s.UpdateSpawn(rec); err != nil {
+		t.Fatalf("UpdateSpawn() error = %v", err)
+	}
+	if _, err := os.Stat(s.spawnClaimsPath(rec.ID)); !os.IsNotExist(err) {
+		t.Fatalf("spawn claim index should be gone after release, stat err=%v", err)
+	}
+	for _, id := range []int{1, 2} {
+		issue, _ := s.GetIssue(id)
+		if issue.Claim != nil {
+			t.Fatalf("issue #%d still claimed after the spawn completed", id)
+		}
+		if last := issue.History[len(issue.History)-1]; last.Type != "released" || last.Message != "spawn completed" {
+			t.Fatalf("issue #%d release history = %+v", id, last)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -80,6 +81,12 @@ func (s *Store) CreateIssue(issue *Issue) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	lf, err := s.lockIssues()
+	if err != nil {
+		return err
+	}
+	defer unlockFile(lf)
+
 	issue.ID = s.nextID(filepath.Join(s.root, "issues"))
 	now := time.Now().UTC()
 	normalizeIssueForCreate(issue, now)
@@ -88,6 +95,8 @@ func (s *Store) CreateIssue(issue *Issue) error {
 		return err
 	}
 
+	s.indexSearchDoc(issueSearchDoc(issue))
+
 	// Auto-commit the created issue
 	s.AutoCommit([]string{"issues/" + filename}, fmt.Sprintf("adaf: create issue #%d: %s", issue.ID, issue.Title))
 	return nil
@@ -105,13 +114,59 @@ func (s *Store) UpdateIssue(issue *Issue) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	filename := fmt.Sprintf("%d.json", issue.ID)
-	path := filepath.Join(s.root, "issues", filename)
+	lf, err := s.lockIssues()
+	if err != nil {
+		return err
+	}
+	defer unlockFile(lf)
 
 	var existing Issue
-	if err := s.readJSON(path, &existing); err != nil {
+	if err := s.readJSON(s.issuePath(issue.ID), &existing); err != nil {
 		return err
 	}
+	return s.saveIssueLocked(issue, existing)
+}
+
+// EditIssue re-reads an issue under the issue lock, applies fn and saves
+// the result like UpdateIssue. Use it when the change was computed from an
+// older copy, so edits made in between are kept.
+func (s *Store) EditIssue(id int, fn func(issue *Issue) error) (*Issue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lf, err := s.lockIssues()
+	if err != nil {
+		return nil, err
+	}
+	defer unlockFile(lf)
+
+	var existing Issue
+	if err := s.readJSON(s.issuePath(id), &existing); err != nil {
+		return nil, err
+	}
+	// fn edits its own copy so the history diff sees the stored values.
+	var issue Issue
+	if err := s.readJSON(s.issuePath(id), &issue); err != nil {
+		return nil, err
+	}
+	if err := fn(&issue); err != nil {
+		return nil, err
+	}
+	if err := s.saveIssueLocked(&issue, existing); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+func (s *Store) issuePath(id int) string {
+	return filepath.Join(s.root, "issues", fmt.Sprintf("%d.json", id))
+}
+
+// saveIssueLocked writes issue over existing, recording field changes in
+// its history. The caller holds s.mu and the issue lock.
+func (s *Store) saveIssueLocked(issue *Issue, existing Issue) error {
+	filename := fmt.Sprintf("%d.json", issue.ID)
+	path := filepath.Join(s.root, "issues", filename)
 
 	now := time.Now().UTC()
 	normalizeIssueForUpdate(issue, &existing, now)
@@ -133,6 +188,8 @@ func (s *Store) UpdateIssue(issue *Issue) error {
 		return err
 	}
 
+	s.indexSearchDoc(issueSearchDoc(issue))
+
 	// Auto-commit the updated issue
 	s.AutoCommit([]string{"issues/" + filename}, fmt.Sprintf("adaf: update issue #%d", issue.ID))
 	return nil
@@ -142,6 +199,12 @@ func (s *Store) AddIssueComment(issueID int, body, by string) (*Issue, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	lf, err := s.lockIssues()
+	if err != nil {
+		return nil, err
+	}
+	defer unlockFile(lf)
+
 	filename := fmt.Sprintf("%d.json", issueID)
 	path := filepath.Join(s.root, "issues", filename)
 
@@ -188,6 +251,7 @@ func (s *Store) AddIssueComment(issueID int, body, by string) (*Issue, error) {
 		return nil, err
 	}
 
+	s.indexSearchDoc(issueSearchDoc(&issue))
 	s.AutoCommit([]string{"issues/" + filename}, fmt.Sprintf("adaf: comment issue #%d", issueID))
 	return &issue, nil
 }
@@ -196,6 +260,12 @@ func (s *Store) DeleteIssue(id int) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	lf, err := s.lockIssues()
+	if err != nil {
+		return err
+	}
+	defer unlockFile(lf)
+
 	filename := fmt.Sprintf("%d.json", id)
 	path := filepath.Join(s.root, "issues", filename)
 	if err := os.Remove(path); err != nil {
@@ -205,6 +275,8 @@ func (s *Store) DeleteIssue(id int) error {
 		return fmt.Errorf("deleting issue %d: %w", id, err)
 	}
 
+	s.unindexSearchDoc(SearchKindIssue, strconv.Itoa(id))
+
 	// Auto-commit the deletion
 	s.AutoCommit([]string{"issues/" + filename}, fmt.Sprintf("adaf: delete issue #%d", id))
 	return nil
@@ -426,6 +498,7 @@ func normalizeIssueForUpdate(issue *Issue, existing *Issue, now time.Time) {
 	issue.Description = strings.TrimSpace(issue.Description)
 	issue.Created = existing.Created
 	issue.Updated = now
+	issue.Claim = existing.Claim
 
 	actor := resolveIssueActor(issue.UpdatedBy, issue.CreatedBy, existing.UpdatedBy, existing.CreatedBy)
 	issue.CreatedBy = resolveIssueActor(issue.CreatedBy, existing.CreatedBy, actor)
@@ -724,3 +797,17 @@ func equalIntSlice(left, right []int) bool {
 	}
 	return true
 }
+
+// LockIssueSync takes the project's cross-process issue sync lock, so only
+// one sync with the external tracker runs at a time. Call the returned
+// function to release it.
+func (s *Store) LockIssueSync() (func(), error) {
+	if err := os.MkdirAll(s.localDir(), 0755); err != nil {
+		return nil, err
+	}
+	lf, err := lockFile(s.localDir("issue_sync"))
+	if err != nil {
+		return nil, fmt.Errorf("locking issue sync: %w", err)
+	}
+	return func() { unlockFile(lf) }, nil
+}
@@ -234,6 +234,31 @@ func (s *Store) IsLoopWindDown(runID int) bool {
 	return err == nil
 }
 
+func (s *Store) loopRunBudgetPath(runID int) string {
+	return filepath.Join(s.loopRunDir(runID), "budget.json")
+}
+
+// SaveLoopRunBudget persists the live budget consumption of a loop run.
+func (s *Store) SaveLoopRunBudget(runID int, b *LoopRunBudget) error {
+	if err := os.MkdirAll(s.loopRunDir(runID), 0755); err != nil {
+		return err
+	}
+	return s.writeJSONLocked(s.loopRunBudgetPath(runID), b)
+}
+
+// GetLoopRunBudget loads the budget consumption of a loop run. It returns
+// nil without error when none has been recorded.
+func (s *Store) GetLoopRunBudget(runID int) (*LoopRunBudget, error) {
+	var b LoopRunBudget
+	if err := s.readJSONLocked(s.loopRunBudgetPath(runID), &b); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &b, nil
+}
+
 func (s *Store) loopCallSupervisorSignalPath(runID int) string {
 	return filepath.Join(s.loopRunDir(runID), "call_supervisor.json")
 }
@@ -288,3 +313,39 @@ func (s *Store) ConsumeLoopCallSupervisorSignal(runID int) (*LoopCallSupervisorS
 	}
 	return sig, nil
 }
+
+func (s *Store) loopStepOutcomePath(runID int) string {
+	return filepath.Join(s.loopRunDir(runID), "outcome.json")
+}
+
+// SetLoopStepOutcome records the outcome token reported by a loop step.
+// A later report from the same step replaces the earlier one.
+func (s *Store) SetLoopStepOutcome(runID, stepIndex int, outcome string) error {
+	dir := s.loopRunDir(runID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	out := &LoopStepOutcome{
+		RunID:     runID,
+		StepIndex: stepIndex,
+		Outcome:   outcome,
+		CreatedAt: time.Now().UTC(),
+	}
+	return s.writeJSONLocked(s.loopStepOutcomePath(runID), out)
+}
+
+// ConsumeLoopStepOutcome fetches and removes the reported outcome for a run.
+// Returns (nil, nil) when no outcome was reported.
+func (s *Store) ConsumeLoopStepOutcome(runID int) (*LoopStepOutcome, error) {
+	var out LoopStepOutcome
+	if err := s.readJSONLocked(s.loopStepOutcomePath(runID), &out); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := os.Remove(s.loopStepOutcomePath(runID)); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return &out, nil
+}
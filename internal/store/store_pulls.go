@@ -0,0 +1,69 @@
+// store_pulls.go contains pull requests opened on the project's forge.
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+func (s *Store) pullRequestPath(id int) string {
+	return s.localDir("pulls", fmt.Sprintf("%d.json", id))
+}
+
+// CreatePullRequest records a new pull request with an auto-assigned ID.
+func (s *Store) CreatePullRequest(pr *PullRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := s.localDir("pulls")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	pr.ID = s.nextID(dir)
+	pr.CreatedAt = time.Now().UTC()
+	return s.writeJSONLocked(s.pullRequestPath(pr.ID), pr)
+}
+
+// GetPullRequest loads a single pull request record by ID.
+func (s *Store) GetPullRequest(id int) (*PullRequest, error) {
+	var pr PullRequest
+	if err := s.readJSONLocked(s.pullRequestPath(id), &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// UpdatePullRequest persists changes to a pull request record.
+func (s *Store) UpdatePullRequest(pr *PullRequest) error {
+	return s.writeJSONLocked(s.pullRequestPath(pr.ID), pr)
+}
+
+// ListPullRequests returns all pull request records, sorted by ID.
+func (s *Store) ListPullRequests() ([]PullRequest, error) {
+	dir := s.localDir("pulls")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var list []PullRequest
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		var pr PullRequest
+		if err := s.readJSONLocked(filepath.Join(dir, e.Name()), &pr); err != nil {
+			continue
+		}
+		list = append(list, pr)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
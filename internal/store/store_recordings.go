@@ -2,10 +2,12 @@
 package store
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 )
 
 func (s *Store) SaveRecording(rec *TurnRecording) error {
@@ -13,7 +15,11 @@ func (s *Store) SaveRecording(rec *TurnRecording) error {
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
-	return s.writeJSON(filepath.Join(dir, "recording.json"), rec)
+	if err := s.writeJSON(filepath.Join(dir, "recording.json"), rec); err != nil {
+		return err
+	}
+	s.indexSearchDoc(s.recordingSearchDoc(rec))
+	return nil
 }
 
 func (s *Store) AppendRecordingEvent(turnID int, event RecordingEvent) error {
@@ -45,6 +51,30 @@ func (s *Store) LoadRecording(turnID int) (*TurnRecording, error) {
 	return &rec, nil
 }
 
+// LoadTurnRecording loads a turn recording, falling back to the streamed
+// events log for turns whose recording was never flushed (e.g. a crashed or
+// still-running turn).
+func (s *Store) LoadTurnRecording(turnID int) (*TurnRecording, error) {
+	if rec, err := s.LoadRecording(turnID); err == nil {
+		return rec, nil
+	}
+	f, err := os.Open(s.localDir("records", strconv.Itoa(turnID), "events.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rec := &TurnRecording{TurnID: turnID}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var ev RecordingEvent
+		if json.Unmarshal(scanner.Bytes(), &ev) == nil {
+			rec.Events = append(rec.Events, ev)
+		}
+	}
+	return rec, nil
+}
+
 // RecordsDirs returns paths to scan for turn recording directories.
 
 func (s *Store) RecordsDirs() []string {
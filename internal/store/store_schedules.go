@@ -0,0 +1,174 @@
+// store_schedules.go contains loop schedule management methods.
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxScheduleHistory caps how many events are kept per schedule.
+const maxScheduleHistory = 50
+
+func (s *Store) schedulePath(id int) string {
+	return s.localDir("schedules", fmt.Sprintf("%d.json", id))
+}
+
+// CreateSchedule persists a new loop schedule with an auto-assigned ID.
+func (s *Store) CreateSchedule(sch *LoopSchedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := s.localDir("schedules")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	sch.ID = s.nextID(dir)
+	sch.CreatedAt = now
+	sch.UpdatedAt = now
+	if sch.LastCheckedAt.IsZero() {
+		sch.LastCheckedAt = now
+	}
+	return s.writeJSONLocked(s.schedulePath(sch.ID), sch)
+}
+
+// GetSchedule loads a single loop schedule by ID.
+func (s *Store) GetSchedule(id int) (*LoopSchedule, error) {
+	var sch LoopSchedule
+	if err := s.readJSONLocked(s.schedulePath(id), &sch); err != nil {
+		return nil, err
+	}
+	return &sch, nil
+}
+
+// FindSchedule resolves a schedule by numeric ID or case-insensitive name.
+func (s *Store) FindSchedule(ref string) (*LoopSchedule, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.Atoi(ref); err == nil {
+		return s.GetSchedule(id)
+	}
+	list, err := s.ListSchedules()
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if strings.EqualFold(list[i].Name, ref) {
+			return &list[i], nil
+		}
+	}
+	return nil, fmt.Errorf("schedule %q not found", ref)
+}
+
+// UpdateSchedule persists changes to a loop schedule.
+func (s *Store) UpdateSchedule(sch *LoopSchedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sch.UpdatedAt = time.Now().UTC()
+	return s.writeJSONLocked(s.schedulePath(sch.ID), sch)
+}
+
+// RecordScheduleCheck saves the scheduler's bookkeeping for schedule id:
+// events are appended to its history, a started event becomes the last
+// fire, and checkedAt becomes the last check. The schedule is re-read under
+// its file lock so edits made while it was being evaluated are kept. It
+// returns an os.ErrNotExist error when the schedule has been deleted.
+func (s *Store) RecordScheduleCheck(id int, checkedAt time.Time, events ...ScheduleEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.schedulePath(id)
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	lf, err := lockFile(path)
+	if err != nil {
+		return fmt.Errorf("lock schedule %d: %w", id, err)
+	}
+	defer unlockFile(lf)
+
+	var sch LoopSchedule
+	if err := s.readJSON(path, &sch); err != nil {
+		return err
+	}
+	for _, ev := range events {
+		AppendScheduleEvent(&sch, ev)
+		if ev.Outcome == ScheduleOutcomeStarted {
+			sch.LastFiredAt = ev.At
+			sch.LastSessionID = ev.SessionID
+		}
+	}
+	sch.LastCheckedAt = checkedAt.UTC()
+	return s.writeJSON(path, &sch)
+}
+
+// DeleteSchedule removes a loop schedule.
+func (s *Store) DeleteSchedule(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.schedulePath(id)
+	lf, err := lockFile(path)
+	if err != nil {
+		return fmt.Errorf("lock schedule %d: %w", id, err)
+	}
+	defer unlockFile(lf)
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return err
+		}
+		return fmt.Errorf("deleting schedule %d: %w", id, err)
+	}
+	return nil
+}
+
+// ListSchedules returns all loop schedules, sorted by ID.
+func (s *Store) ListSchedules() ([]LoopSchedule, error) {
+	dir := s.localDir("schedules")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var list []LoopSchedule
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		var sch LoopSchedule
+		if err := s.readJSONLocked(filepath.Join(dir, e.Name()), &sch); err != nil {
+			continue
+		}
+		list = append(list, sch)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
+
+// AppendScheduleEvent adds ev to the schedule's history, dropping the oldest
+// entries beyond the history cap. The caller persists the schedule.
+func AppendScheduleEvent(sch *LoopSchedule, ev ScheduleEvent) {
+	sch.History = append(sch.History, ev)
+	if over := len(sch.History) - maxScheduleHistory; over > 0 {
+		sch.History = append([]ScheduleEvent(nil), sch.History[over:]...)
+	}
+}
+
+// SetScheduleEnabled toggles a schedule. Re-enabling moves the last check to
+// now so the disabled period is not reported as missed runs. The caller
+// persists the schedule.
+func SetScheduleEnabled(sch *LoopSchedule, enabled bool) {
+	if enabled && sch.Disabled {
+		sch.LastCheckedAt = time.Now().UTC()
+	}
+	sch.Disabled = !enabled
+}
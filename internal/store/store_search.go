@@ -0,0 +1,728 @@
+// store_search.go contains the persistent full-text search index.
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Search document kinds.
+const (
+	SearchKindTurn      = "turn"
+	SearchKindWiki      = "wiki"
+	SearchKindIssue     = "issue"
+	SearchKindRecording = "recording"
+)
+
+// SearchKinds lists every indexed document kind.
+var SearchKinds = []string{SearchKindTurn, SearchKindWiki, SearchKindIssue, SearchKindRecording}
+
+const (
+	searchIndexVersion = 1
+	// searchCompactBytes is the journal size at which the next search folds
+	// the journal into the snapshot.
+	searchCompactBytes = 4 << 20
+	// searchRecordingTextLimit caps the recording text tokenized per turn.
+	searchRecordingTextLimit = 1 << 20
+	searchTitleWeight        = 3
+	searchSnippetRadius      = 80
+	defaultSearchLimit       = 20
+)
+
+// SearchQuery selects documents from the search index.
+type SearchQuery struct {
+	Text   string
+	Kinds  []string // empty means all kinds
+	PlanID string   // when set, shared documents and this plan's documents match
+	Limit  int
+}
+
+// SearchResult is a ranked search hit.
+type SearchResult struct {
+	Kind    string    `json:"kind"`
+	ID      string    `json:"id"`
+	PlanID  string    `json:"plan_id,omitempty"`
+	Title   string    `json:"title"`
+	Agent   string    `json:"agent,omitempty"`
+	Profile string    `json:"profile,omitempty"`
+	Snippet string    `json:"snippet,omitempty"`
+	Score   float64   `json:"score"`
+	Updated time.Time `json:"updated"`
+}
+
+// searchDoc is the per-document metadata kept in the index. Document text is
+// not stored; snippets are cut from the source record at query time.
+type searchDoc struct {
+	Kind    string    `json:"kind"`
+	ID      string    `json:"id"`
+	PlanID  string    `json:"plan_id,omitempty"`
+	Title   string    `json:"title"`
+	Agent   string    `json:"agent,omitempty"`
+	Profile string    `json:"profile,omitempty"`
+	Updated time.Time `json:"updated"`
+	Length  int       `json:"length"`
+}
+
+func (d searchDoc) key() string {
+	return d.Kind + ":" + d.ID
+}
+
+// searchSnapshot is the on-disk inverted index. Postings map a term to the
+// weighted term frequency per document key.
+type searchSnapshot struct {
+	Version  int                       `json:"version"`
+	Docs     map[string]searchDoc      `json:"docs"`
+	Postings map[string]map[string]int `json:"postings"`
+}
+
+// searchJournalEntry is one incremental index change appended by a write.
+type searchJournalEntry struct {
+	Op    string         `json:"op"` // "put" or "del"
+	Doc   searchDoc      `json:"doc"`
+	Terms map[string]int `json:"terms,omitempty"`
+}
+
+// searchIndex is the in-memory copy of the snapshot plus replayed journal.
+type searchIndex struct {
+	snap          searchSnapshot
+	docTerms      map[string]map[string]int
+	snapshotStamp string
+	journalOffset int64
+}
+
+func (s *Store) searchDir() string {
+	return s.localDir("search")
+}
+
+func (s *Store) searchSnapshotPath() string {
+	return filepath.Join(s.searchDir(), "index.json")
+}
+
+func (s *Store) searchJournalPath() string {
+	return filepath.Join(s.searchDir(), "journal.jsonl")
+}
+
+// Search runs a ranked full-text query over turns, wiki entries, issues and
+// recordings. The index is built on first use and kept current by the store
+// write methods.
+func (s *Store) Search(q SearchQuery) ([]SearchResult, error) {
+	terms := searchTokens(q.Text)
+	if len(terms) == 0 {
+		return []SearchResult{}, nil
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	kinds := make(map[string]bool, len(q.Kinds))
+	for _, k := range q.Kinds {
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+			kinds[k] = true
+		}
+	}
+
+	s.searchMu.Lock()
+	defer s.searchMu.Unlock()
+	if err := s.syncSearchIndex(false); err != nil {
+		return nil, err
+	}
+	idx := s.search
+
+	// BM25 over the weighted term frequencies.
+	const k1, b = 1.2, 0.75
+	n := float64(len(idx.snap.Docs))
+	avgLen := 0.0
+	for _, d := range idx.snap.Docs {
+		avgLen += float64(d.Length)
+	}
+	if n > 0 {
+		avgLen /= n
+	}
+	if avgLen == 0 {
+		avgLen = 1
+	}
+	scores := make(map[string]float64)
+	matched := make(map[string]int)
+	for _, qt := range uniqueStrings(terms) {
+		seen := make(map[string]bool)
+		for _, term := range idx.expandTerm(qt) {
+			postings := idx.snap.Postings[term]
+			df := float64(len(postings))
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			for key, tf := range postings {
+				doc, ok := idx.snap.Docs[key]
+				if !ok || !searchDocAllowed(doc, kinds, q.PlanID) {
+					continue
+				}
+				f := float64(tf)
+				weight := 1.0
+				if term != qt {
+					weight = 0.5 // prefix match
+				}
+				scores[key] += weight * idf * f * (k1 + 1) / (f + k1*(1-b+b*float64(doc.Length)/avgLen))
+				if !seen[key] {
+					seen[key] = true
+					matched[key]++
+				}
+			}
+		}
+	}
+
+	results := make([]SearchResult, 0, len(scores))
+	queryTerms := len(uniqueStrings(terms))
+	for key, score := range scores {
+		doc := idx.snap.Docs[key]
+		// Documents matching every query term rank above partial matches.
+		if matched[key] == queryTerms {
+			score *= 2
+		}
+		results = append(results, SearchResult{
+			Kind:    doc.Kind,
+			ID:      doc.ID,
+			PlanID:  doc.PlanID,
+			Title:   doc.Title,
+			Agent:   doc.Agent,
+			Profile: doc.Profile,
+			Score:   math.Round(score*1000) / 1000,
+			Updated: doc.Updated,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if !results[i].Updated.Equal(results[j].Updated) {
+			return results[i].Updated.After(results[j].Updated)
+		}
+		return results[i].Kind+results[i].ID < results[j].Kind+results[j].ID
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	for i := range results {
+		results[i].Snippet = searchSnippet(s.searchDocText(results[i].Kind, results[i].ID), terms)
+	}
+	return results, nil
+}
+
+// RebuildSearchIndex discards the index and rebuilds it from the store.
+func (s *Store) RebuildSearchIndex() error {
+	s.searchMu.Lock()
+	defer s.searchMu.Unlock()
+	return s.syncSearchIndex(true)
+}
+
+func searchDocAllowed(doc searchDoc, kinds map[string]bool, planID string) bool {
+	if len(kinds) > 0 && !kinds[doc.Kind] {
+		return false
+	}
+	if planID != "" && doc.PlanID != "" && doc.PlanID != planID {
+		return false
+	}
+	return true
+}
+
+// expandTerm returns the indexed terms a query term matches: the term itself
+// and, for terms of three or more characters, longer terms it prefixes.
+func (idx *searchIndex) expandTerm(term string) []string {
+	out := []string{}
+	if _, ok := idx.snap.Postings[term]; ok {
+		out = append(out, term)
+	}
+	if len(term) < 3 {
+		return out
+	}
+	for t := range idx.snap.Postings {
+		if t != term && strings.HasPrefix(t, term) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// syncSearchIndex brings s.search up to date with the files on disk,
+// rebuilding from the store when there is no snapshot (or force is set) and
+// compacting a long journal. Callers hold s.searchMu.
+func (s *Store) syncSearchIndex(force bool) error {
+	if err := os.MkdirAll(s.searchDir(), 0755); err != nil {
+		return err
+	}
+	lf, err := lockFile(s.searchJournalPath())
+	if err != nil {
+		return fmt.Errorf("lock search index: %w", err)
+	}
+	defer unlockFile(lf)
+
+	stamp := fileStamp(s.searchSnapshotPath())
+	if force || stamp == "" {
+		return s.rebuildSearchIndexLocked()
+	}
+	if s.search == nil || s.search.snapshotStamp != stamp {
+		var snap searchSnapshot
+		if err := s.readJSON(s.searchSnapshotPath(), &snap); err != nil || snap.Version != searchIndexVersion {
+			return s.rebuildSearchIndexLocked()
+		}
+		s.search = newSearchIndex(snap)
+		s.search.snapshotStamp = stamp
+	}
+
+	size, err := s.search.replayJournal(s.searchJournalPath())
+	if err != nil {
+		return err
+	}
+	if size >= searchCompactBytes {
+		return s.writeSearchSnapshotLocked()
+	}
+	return nil
+}
+
+func newSearchIndex(snap searchSnapshot) *searchIndex {
+	if snap.Docs == nil {
+		snap.Docs = make(map[string]searchDoc)
+	}
+	if snap.Postings == nil {
+		snap.Postings = make(map[string]map[string]int)
+	}
+	idx := &searchIndex{snap: snap, docTerms: make(map[string]map[string]int)}
+	for term, postings := range snap.Postings {
+		for key, tf := range postings {
+			if idx.docTerms[key] == nil {
+				idx.docTerms[key] = make(map[string]int)
+			}
+			idx.docTerms[key][term] = tf
+		}
+	}
+	return idx
+}
+
+// replayJournal applies journal entries written since the last replay and
+// returns the journal size.
+func (idx *searchIndex) replayJournal(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			idx.journalOffset = 0
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if info.Size() < idx.journalOffset {
+		idx.journalOffset = 0
+	}
+	if _, err := f.Seek(idx.journalOffset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	reader := bufio.NewReader(f)
+	offset := idx.journalOffset
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// A partial trailing line is picked up on the next replay.
+			break
+		}
+		offset += int64(len(line))
+		var entry searchJournalEntry
+		if json.Unmarshal(line, &entry) != nil {
+			continue
+		}
+		switch entry.Op {
+		case "put":
+			idx.put(entry.Doc, entry.Terms)
+		case "del":
+			idx.remove(entry.Doc.key())
+		}
+	}
+	idx.journalOffset = offset
+	return info.Size(), nil
+}
+
+func (idx *searchIndex) put(doc searchDoc, terms map[string]int) {
+	key := doc.key()
+	idx.remove(key)
+	doc.Length = 0
+	for _, tf := range terms {
+		doc.Length += tf
+	}
+	idx.snap.Docs[key] = doc
+	idx.docTerms[key] = terms
+	for term, tf := range terms {
+		postings := idx.snap.Postings[term]
+		if postings == nil {
+			postings = make(map[string]int)
+			idx.snap.Postings[term] = postings
+		}
+		postings[key] = tf
+	}
+}
+
+func (idx *searchIndex) remove(key string) {
+	for term := range idx.docTerms[key] {
+		postings := idx.snap.Postings[term]
+		delete(postings, key)
+		if len(postings) == 0 {
+			delete(idx.snap.Postings, term)
+		}
+	}
+	delete(idx.docTerms, key)
+	delete(idx.snap.Docs, key)
+}
+
+// rebuildSearchIndexLocked indexes every document in the store. It reads the
+// record files directly so it never waits on s.mu while holding the journal
+// lock that writers take with s.mu held.
+func (s *Store) rebuildSearchIndexLocked() error {
+	s.search = newSearchIndex(searchSnapshot{Version: searchIndexVersion})
+
+	for _, path := range jsonFiles(s.turnsDir()) {
+		var turn Turn
+		if s.readJSON(path, &turn) == nil {
+			doc, text := turnSearchDoc(&turn)
+			s.search.put(doc, searchTerms(doc.Title, text))
+		}
+	}
+	for _, path := range jsonFiles(filepath.Join(s.root, "wiki")) {
+		var entry WikiEntry
+		if s.readJSON(path, &entry) == nil {
+			doc, text := wikiSearchDoc(&entry)
+			s.search.put(doc, searchTerms(doc.Title, text))
+		}
+	}
+	for _, path := range jsonFiles(filepath.Join(s.root, "issues")) {
+		var issue Issue
+		if s.readJSON(path, &issue) == nil {
+			doc, text := issueSearchDoc(&issue)
+			s.search.put(doc, searchTerms(doc.Title, text))
+		}
+	}
+	for _, dir := range s.RecordsDirs() {
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			turnID, err := strconv.Atoi(e.Name())
+			if err != nil || !e.IsDir() {
+				continue
+			}
+			rec, err := s.LoadTurnRecording(turnID)
+			if err != nil {
+				continue
+			}
+			doc, text := s.recordingSearchDoc(rec)
+			s.search.put(doc, searchTerms(doc.Title, text))
+		}
+	}
+	return s.writeSearchSnapshotLocked()
+}
+
+// writeSearchSnapshotLocked persists the in-memory index and truncates the
+// journal it now includes.
+func (s *Store) writeSearchSnapshotLocked() error {
+	path := s.searchSnapshotPath()
+	data, err := json.Marshal(s.search.snap)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	if err := os.Truncate(s.searchJournalPath(), 0); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	s.search.journalOffset = 0
+	s.search.snapshotStamp = fileStamp(path)
+	return nil
+}
+
+// indexSearchDoc appends a document update to the search journal. Index
+// maintenance never fails the write that triggered it.
+func (s *Store) indexSearchDoc(doc searchDoc, text string) {
+	s.appendSearchJournal(searchJournalEntry{Op: "put", Doc: doc, Terms: searchTerms(doc.Title, text)})
+}
+
+func (s *Store) unindexSearchDoc(kind, id string) {
+	s.appendSearchJournal(searchJournalEntry{Op: "del", Doc: searchDoc{Kind: kind, ID: id}})
+}
+
+func (s *Store) appendSearchJournal(entry searchJournalEntry) {
+	if strings.TrimSpace(s.root) == "" {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	path := s.searchJournalPath()
+	lf, err := lockFile(path)
+	if err != nil {
+		return
+	}
+	defer unlockFile(lf)
+	// Without a snapshot the next search rebuilds from the store anyway. The
+	// check runs under the journal lock so a concurrent rebuild cannot write
+	// its snapshot between the check and the append.
+	if _, err := os.Stat(s.searchSnapshotPath()); err != nil {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = f.Write(append(data, '\n'))
+}
+
+func turnSearchDoc(t *Turn) (searchDoc, string) {
+	updated := t.FinalizedAt
+	if updated.IsZero() {
+		updated = t.Date
+	}
+	doc := searchDoc{
+		Kind:    SearchKindTurn,
+		ID:      strconv.Itoa(t.ID),
+		PlanID:  t.PlanID,
+		Title:   t.Objective,
+		Agent:   t.Agent,
+		Profile: t.ProfileName,
+		Updated: updated,
+	}
+	text := joinSearchText(t.Agent, t.AgentModel, t.ProfileName, t.CommitHash, t.WhatWasBuilt,
+		t.KeyDecisions, t.Challenges, t.CurrentState, t.KnownIssues, t.NextSteps, t.BuildState)
+	return doc, text
+}
+
+func wikiSearchDoc(e *WikiEntry) (searchDoc, string) {
+	doc := searchDoc{
+		Kind:    SearchKindWiki,
+		ID:      e.ID,
+		PlanID:  e.PlanID,
+		Title:   e.Title,
+		Agent:   e.UpdatedBy,
+		Updated: e.Updated,
+	}
+	return doc, joinSearchText(e.ID, e.Content)
+}
+
+func issueSearchDoc(issue *Issue) (searchDoc, string) {
+	doc := searchDoc{
+		Kind:    SearchKindIssue,
+		ID:      strconv.Itoa(issue.ID),
+		PlanID:  issue.PlanID,
+		Title:   issue.Title,
+		Agent:   issue.UpdatedBy,
+		Updated: issue.Updated,
+	}
+	parts := []string{issue.Description, issue.Status, issue.Priority, strings.Join(issue.Labels, " ")}
+	for _, c := range issue.Comments {
+		parts = append(parts, c.Body)
+	}
+	return doc, joinSearchText(parts...)
+}
+
+func (s *Store) recordingSearchDoc(rec *TurnRecording) (searchDoc, string) {
+	doc := searchDoc{
+		Kind:    SearchKindRecording,
+		ID:      strconv.Itoa(rec.TurnID),
+		Title:   fmt.Sprintf("Recording of turn #%d", rec.TurnID),
+		Agent:   rec.Agent,
+		Updated: rec.EndTime,
+	}
+	if turn, err := s.GetTurn(rec.TurnID); err == nil && turn != nil {
+		doc.PlanID = turn.PlanID
+		doc.Profile = turn.ProfileName
+		if doc.Agent == "" {
+			doc.Agent = turn.Agent
+		}
+		if strings.TrimSpace(turn.Objective) != "" {
+			doc.Title = fmt.Sprintf("Recording of turn #%d: %s", rec.TurnID, turn.Objective)
+		}
+		if doc.Updated.IsZero() {
+			doc.Updated = turn.Date
+		}
+	}
+	var b strings.Builder
+	for _, ev := range rec.Events {
+		if ev.Type == "meta" {
+			continue
+		}
+		if b.Len()+len(ev.Data) > searchRecordingTextLimit {
+			break
+		}
+		b.WriteString(ev.Data)
+		b.WriteByte('\n')
+	}
+	return doc, b.String()
+}
+
+// searchDocText returns the text a snippet is cut from.
+func (s *Store) searchDocText(kind, id string) string {
+	switch kind {
+	case SearchKindTurn:
+		if n, err := strconv.Atoi(id); err == nil {
+			if t, err := s.GetTurn(n); err == nil {
+				doc, text := turnSearchDoc(t)
+				return joinSearchText(doc.Title, text)
+			}
+		}
+	case SearchKindWiki:
+		if e, err := s.GetWikiEntry(id); err == nil {
+			return e.Content
+		}
+	case SearchKindIssue:
+		if n, err := strconv.Atoi(id); err == nil {
+			if issue, err := s.GetIssue(n); err == nil {
+				_, text := issueSearchDoc(issue)
+				return joinSearchText(issue.Title, text)
+			}
+		}
+	case SearchKindRecording:
+		if n, err := strconv.Atoi(id); err == nil {
+			if rec, err := s.LoadTurnRecording(n); err == nil {
+				_, text := s.recordingSearchDoc(rec)
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+// searchTerms tokenizes a document, weighting title terms above body terms.
+func searchTerms(title, text string) map[string]int {
+	terms := make(map[string]int)
+	for _, t := range searchTokens(title) {
+		terms[t] += searchTitleWeight
+	}
+	for _, t := range searchTokens(text) {
+		terms[t]++
+	}
+	return terms
+}
+
+var searchStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "for": true, "from": true, "in": true, "is": true, "it": true, "of": true,
+	"on": true, "or": true, "that": true, "the": true, "this": true, "to": true, "was": true,
+	"with": true,
+}
+
+// searchTokens splits text into lowercase terms of letters and digits.
+func searchTokens(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := fields[:0]
+	for _, f := range fields {
+		if len(f) < 2 || len(f) > 64 || searchStopWords[f] {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// searchSnippet returns a window of text around the first query term match.
+func searchSnippet(text string, terms []string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return ""
+	}
+	// Lowercasing can change a rune's byte length, so keep the offset in
+	// text of every byte of lower to map a match back.
+	var lower strings.Builder
+	offsets := make([]int, 0, len(text))
+	for i, r := range text {
+		n, _ := lower.WriteRune(unicode.ToLower(r))
+		for range n {
+			offsets = append(offsets, i)
+		}
+	}
+	pos := -1
+	for _, t := range terms {
+		if i := strings.Index(lower.String(), t); i >= 0 && (pos < 0 || offsets[i] < pos) {
+			pos = offsets[i]
+		}
+	}
+	if pos < 0 {
+		pos = 0
+	}
+	start := max(pos-searchSnippetRadius, 0)
+	end := min(pos+searchSnippetRadius, len(text))
+	for start > 0 && !isSnippetBoundary(text[start-1]) {
+		start--
+	}
+	for end < len(text) && !isSnippetBoundary(text[end]) {
+		end++
+	}
+	snippet := strings.TrimSpace(text[start:end])
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if end < len(text) {
+		snippet += "..."
+	}
+	return snippet
+}
+
+func isSnippetBoundary(c byte) bool {
+	return c == ' '
+}
+
+func joinSearchText(parts ...string) string {
+	nonEmpty := parts[:0:0]
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, "\n")
+}
+
+func jsonFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			out = append(out, filepath.Join(dir, e.Name()))
+		}
+	}
+	return out
+}
+
+func fileStamp(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", info.ModTime().UnixNano(), info.Size())
+}
+
+func uniqueStrings(in []string) []string {
+	seen := make(map[string]bool, len(in))
+	out := make([]string, 0, len(in))
+	for _, v := range in {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func newSearchTestStore(t *testing.T) *Store {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Init(ProjectConfig{Name: "test", RepoPath: "/tmp"}); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSearchRanksAndFiltersAcrossKinds(t *testing.T) {
+	s := newSearchTestStore(t)
+
+	if err := s.CreateTurn(&Turn{Agent: "claude", Objective: "Refactor auth middleware", WhatWasBuilt: "Moved token checks into the middleware chain."}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateTurn(&Turn{Agent: "codex", Objective: "Fix flaky tests", WhatWasBuilt: "Retry logic in the scheduler."}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateWikiEntry(&WikiEntry{ID: "auth", Title: "Auth notes", Content: "The middleware validates session tokens."}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateIssue(&Issue{Title: "Middleware leaks headers", Status: "open", Priority: "high", PlanID: "other"}); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := s.Search(SearchQuery{Text: "auth middleware"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("len(results) = %d, want 3: %+v", len(results), results)
+	}
+	if results[0].Kind != SearchKindTurn || results[0].ID != "1" || results[0].Agent != "claude" {
+		t.Fatalf("top result = %+v, want turn 1 by claude", results[0])
+	}
+	if !strings.Contains(strings.ToLower(results[0].Snippet), "middleware") {
+		t.Fatalf("snippet = %q, want a middleware match", results[0].Snippet)
+	}
+
+	results, err = s.Search(SearchQuery{Text: "middleware", Kinds: []string{SearchKindWiki}})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "auth" {
+		t.Fatalf("wiki-only results = %+v, want wiki auth", results)
+	}
+
+	results, err = s.Search(SearchQuery{Text: "middleware", PlanID: "main"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	for _, r := range results {
+		if r.Kind == SearchKindIssue {
+			t.Fatalf("plan filter kept issue from another plan: %+v", r)
+		}
+	}
+}
+
+func TestSearchIndexUpdatesIncrementally(t *testing.T) {
+	s := newSearchTestStore(t)
+
+	if err := s.CreateWikiEntry(&WikiEntry{ID: "deploy", Title: "Deploy", Content: "Use the blue green rollout."}); err != nil {
+		t.Fatal(err)
+	}
+	if results, _ := s.Search(SearchQuery{Text: "rollout"}); len(results) != 1 {
+		t.Fatalf("initial search = %+v, want 1 hit", results)
+	}
+
+	// Writes after the index exists go through the journal.
+	entry, err := s.GetWikiEntry("deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry.Content = "Use canary releases."
+	if err := s.UpdateWikiEntry(entry); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveRecording(&TurnRecording{TurnID: 7, Agent: "vibe", Events: []RecordingEvent{{Type: "stdout", Data: "running canary checks"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(s.searchJournalPath()); err != nil || info.Size() == 0 {
+		t.Fatalf("expected journal entries, stat err=%v", err)
+	}
+
+	if results, _ := s.Search(SearchQuery{Text: "rollout"}); len(results) != 0 {
+		t.Fatalf("stale term still matches: %+v", results)
+	}
+	results, err := s.Search(SearchQuery{Text: "canary"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("canary results = %+v, want wiki and recording", results)
+	}
+
+	// A fresh store handle loads the snapshot and replays the journal.
+	reopened, err := New(s.ProjectDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results, _ := reopened.Search(SearchQuery{Text: "canary", Kinds: []string{SearchKindRecording}}); len(results) != 1 || results[0].ID != "7" {
+		t.Fatalf("reopened recording results = %+v, want recording 7", results)
+	}
+
+	if err := s.DeleteWikiEntry("deploy"); err != nil {
+		t.Fatal(err)
+	}
+	if results, _ := reopened.Search(SearchQuery{Text: "canary", Kinds: []string{SearchKindWiki}}); len(results) != 0 {
+		t.Fatalf("deleted wiki entry still matches: %+v", results)
+	}
+}
+
+func TestSearchSnippetMapsMatchThroughCaseFolding(t *testing.T) {
+	// "İ" lowercases to a longer byte sequence, shifting positions in the
+	// lowercased text.
+	text := strings.Repeat("İİİİ ", 60) + "middleware ünïcode tail " + strings.Repeat("word ", 40)
+	snippet := searchSnippet(text, []string{"middleware"})
+	if !utf8.ValidString(snippet) {
+		t.Fatalf("snippet is not valid UTF-8: %q", snippet)
+	}
+	if !strings.Contains(snippet, "middleware ünïcode") {
+		t.Fatalf("snippet = %q, want it centred on the match", snippet)
+	}
+}
@@ -67,7 +67,14 @@ func (s *Store) GetSpawn(id int) (*SpawnRecord, error) {
 // UpdateSpawn persists changes to a spawn record.
 
 func (s *Store) UpdateSpawn(rec *SpawnRecord) error {
-	return s.writeJSONLocked(s.localDir("spawns", fmt.Sprintf("%d.json", rec.ID)), rec)
+	if err := s.writeJSONLocked(s.localDir("spawns", fmt.Sprintf("%d.json", rec.ID)), rec); err != nil {
+		return err
+	}
+	// A finished spawn gives up its issue leases.
+	if IsTerminalSpawnStatus(rec.Status) {
+		s.ReleaseSpawnClaims(rec.ID, "spawn "+rec.Status)
+	}
+	return nil
 }
 
 // SpawnsByParent returns spawn records created by a given parent turn.
@@ -69,7 +69,11 @@ func (s *Store) CreateTurn(turn *Turn) error {
 	dir := s.turnsDir()
 	turn.ID = s.nextID(dir)
 	turn.Date = time.Now().UTC()
-	return s.writeJSON(filepath.Join(dir, fmt.Sprintf("%d.json", turn.ID)), turn)
+	if err := s.writeJSON(filepath.Join(dir, fmt.Sprintf("%d.json", turn.ID)), turn); err != nil {
+		return err
+	}
+	s.indexSearchDoc(turnSearchDoc(turn))
+	return nil
 }
 
 func (s *Store) GetTurn(id int) (*Turn, error) {
@@ -95,7 +99,11 @@ func (s *Store) UpdateTurn(turn *Turn) error {
 		}
 		return fmt.Errorf("%w: turn #%d has terminal build state %q", ErrTurnFrozen, existing.ID, existing.BuildState)
 	}
-	return s.writeJSON(path, turn)
+	if err := s.writeJSON(path, turn); err != nil {
+		return err
+	}
+	s.indexSearchDoc(turnSearchDoc(turn))
+	return nil
 }
 
 func (s *Store) LatestTurn() (*Turn, error) {
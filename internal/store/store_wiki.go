@@ -172,6 +172,7 @@ func (s *Store) CreateWikiEntry(entry *WikiEntry) error {
 		return err
 	}
 
+	s.indexSearchDoc(wikiSearchDoc(entry))
 	s.AutoCommit([]string{"wiki/" + filename}, fmt.Sprintf("adaf: create wiki %s", entry.ID))
 	return nil
 }
@@ -213,6 +214,7 @@ func (s *Store) UpdateWikiEntry(entry *WikiEntry) error {
 		return err
 	}
 
+	s.indexSearchDoc(wikiSearchDoc(entry))
 	s.AutoCommit([]string{"wiki/" + filename}, fmt.Sprintf("adaf: update wiki %s", entry.ID))
 	return nil
 }
@@ -229,6 +231,7 @@ func (s *Store) DeleteWikiEntry(id string) error {
 		return fmt.Errorf("deleting wiki entry %q: %w", id, err)
 	}
 
+	s.unindexSearchDoc(SearchKindWiki, id)
 	s.AutoCommit([]string{"wiki/" + id + ".json"}, fmt.Sprintf("adaf: delete wiki %s", id))
 	return nil
 }
@@ -12,15 +12,19 @@ type ProjectConfig struct {
 	AgentConfig  map[string]string `json:"agent_config"` // agent name -> path/config
 	Metadata     map[string]any    `json:"metadata"`
 	ActivePlanID string            `json:"active_plan_id,omitempty"`
+	Forge        *ForgeConfig      `json:"forge,omitempty"`      // remote forge for pull requests
+	IssueSync    *IssueSyncConfig  `json:"issue_sync,omitempty"` // external issue tracker
+	Isolation    *IsolationConfig  `json:"isolation,omitempty"`  // spawn isolation backend
 }
 
 type Plan struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"` // "active", "done", "cancelled", "frozen"
-	Created     time.Time `json:"created"`
-	Updated     time.Time `json:"updated"`
+	ID          string      `json:"id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Status      string      `json:"status"` // "active", "done", "cancelled", "frozen"
+	Phases      []PlanPhase `json:"phases,omitempty"`
+	Created     time.Time   `json:"created"`
+	Updated     time.Time   `json:"updated"`
 }
 
 type Issue struct {
@@ -39,6 +43,29 @@ type Issue struct {
 	History     []IssueHistory `json:"history,omitempty"`
 	Created     time.Time      `json:"created"`
 	Updated     time.Time      `json:"updated"`
+
+	// Checks are acceptance gates applied to spawns assigned this issue.
+	Checks []AcceptanceCheck `json:"checks,omitempty"`
+
+	// External links the issue to an external tracker (see adaf issue sync).
+	External *IssueExternal `json:"external,omitempty"`
+
+	// Claim is the lease of the agent working on the issue, if any. It only
+	// changes through ClaimIssue and ReleaseIssueClaim.
+	Claim *IssueClaim `json:"claim,omitempty"`
+}
+
+// IssueClaim is a lease on an issue held by a turn, a loop step or a spawn,
+// so parallel agents do not pick the same work. A spawn's leases are released
+// when it reaches a terminal status; others lapse at ExpiresAt.
+type IssueClaim struct {
+	TurnID    int       `json:"turn_id,omitempty"`
+	SpawnID   int       `json:"spawn_id,omitempty"`
+	LoopRunID int       `json:"loop_run_id,omitempty"` // with LoopStep, holds the lease across the step's turns
+	LoopStep  int       `json:"loop_step,omitempty"`   // step index within LoopRunID
+	By        string    `json:"by,omitempty"`
+	ClaimedAt time.Time `json:"claimed_at"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type IssueComment struct {
@@ -51,7 +78,7 @@ type IssueComment struct {
 
 type IssueHistory struct {
 	ID        int       `json:"id"`
-	Type      string    `json:"type"` // "created", "updated", "commented", "status_changed", "moved"
+	Type      string    `json:"type"` // "created", "updated", "commented", "status_changed", "moved", "claimed", "released"
 	Field     string    `json:"field,omitempty"`
 	From      string    `json:"from,omitempty"`
 	To        string    `json:"to,omitempty"`
@@ -103,6 +130,16 @@ type Turn struct {
 	NextSteps    string    `json:"next_steps"`
 	BuildState   string    `json:"build_state"`
 	DurationSecs int       `json:"duration_secs,omitempty"`
+
+	// RerunOfTurnID links a turn launched by 'adaf replay --rerun' to the
+	// recorded turn whose prompt it re-ran.
+	RerunOfTurnID int `json:"rerun_of_turn_id,omitempty"`
+
+	// Profile failover: a failed turn names the fallback profile that took
+	// over and why; the re-run turn links back to the failed one.
+	FailedOverTo       string `json:"failed_over_to,omitempty"`
+	FailoverReason     string `json:"failover_reason,omitempty"` // quota|auth|crash
+	FailoverFromTurnID int    `json:"failover_from_turn_id,omitempty"`
 }
 
 // TurnRecording captures the raw I/O of a single agent turn.
@@ -0,0 +1,55 @@
+package store
+
+import "time"
+
+// Approval kinds.
+const (
+	ApprovalKindMerge    = "merge"     // merging a spawn's branch
+	ApprovalKindLoopStep = "loop_step" // starting a gated loop step
+)
+
+// Approval statuses.
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalDenied   = "denied"
+	ApprovalCanceled = "canceled" // the waiting loop run ended, or the branch moved, before a decision
+)
+
+// MergeGate is the merge part of an approval policy, recorded on spawns so
+// merges are gated by what was resolved when the spawn was created rather
+// than by the merging agent's environment.
+type MergeGate struct {
+	Merges         bool `json:"merges,omitempty"`
+	MergeLineLimit int  `json:"merge_line_limit,omitempty"`
+}
+
+// Approval is a human sign-off requested before a gated action runs. The
+// reviewer's comment is relayed to the agent that asked.
+type Approval struct {
+	ID      int    `json:"id"`
+	Kind    string `json:"kind"`
+	Status  string `json:"status"`
+	Summary string `json:"summary"`          // what happens once approved
+	Reason  string `json:"reason,omitempty"` // which policy asked for approval
+
+	// Merge approvals. An approval covers the branch at Head only; a
+	// branch that moved afterwards needs a new one.
+	SpawnID   int    `json:"spawn_id,omitempty"`
+	Branch    string `json:"branch,omitempty"`
+	Head      string `json:"head,omitempty"`
+	DiffLines int    `json:"diff_lines,omitempty"`
+	TurnID    int    `json:"turn_id,omitempty"` // requesting turn
+
+	// Loop step approvals.
+	LoopRunID int    `json:"loop_run_id,omitempty"`
+	LoopName  string `json:"loop_name,omitempty"`
+	Cycle     int    `json:"cycle,omitempty"`
+	StepIndex int    `json:"step_index,omitempty"`
+	StepLabel string `json:"step_label,omitempty"`
+
+	Comment   string    `json:"comment,omitempty"`
+	DecidedBy string    `json:"decided_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	DecidedAt time.Time `json:"decided_at,omitzero"`
+}
@@ -0,0 +1,42 @@
+package store
+
+import "time"
+
+// Forge types.
+const (
+	ForgeGitea  = "gitea" // Gitea, Forgejo and compatible stand-ins
+	ForgeGitHub = "github"
+)
+
+// ForgeConfig points a project at the git forge that receives its pull
+// requests. The API token is read from the TokenEnv environment variable so
+// it never lands in project.json.
+type ForgeConfig struct {
+	Type     string `json:"type"`                // ForgeGitea or ForgeGitHub
+	URL      string `json:"url"`                 // forge root, e.g. https://gitea.example.com or https://api.github.com
+	Owner    string `json:"owner"`               // repository owner or organization
+	Repo     string `json:"repo"`                // repository name
+	Remote   string `json:"remote,omitempty"`    // git remote to push to (default "origin")
+	Base     string `json:"base,omitempty"`      // target branch (default: the current branch)
+	TokenEnv string `json:"token_env,omitempty"` // env var holding the API token (default ADAF_FORGE_TOKEN)
+}
+
+// PullRequest records a pull request opened from a spawn branch or a loop
+// run's commits, and which review comments were already synced back.
+type PullRequest struct {
+	ID        int    `json:"id"`
+	Number    int    `json:"number"` // number on the forge
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	Head      string `json:"head"` // pushed branch
+	Base      string `json:"base"`
+	State     string `json:"state"` // open, closed or merged, as last seen on the forge
+	SpawnID   int    `json:"spawn_id,omitempty"`
+	LoopRunID int    `json:"loop_run_id,omitempty"`
+
+	// IssueIDs receive review comments as issue comments.
+	IssueIDs       []int     `json:"issue_ids,omitempty"`
+	SyncedComments []string  `json:"synced_comments,omitempty"` // forge comment keys already copied
+	CreatedAt      time.Time `json:"created_at"`
+	SyncedAt       time.Time `json:"synced_at,omitzero"`
+}
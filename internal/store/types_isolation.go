@@ -0,0 +1,21 @@
+package store
+
+// Spawn isolation backends.
+const (
+	IsolationWorktree  = "worktree"  // git worktree only (default)
+	IsolationContainer = "container" // worktree mounted into a per-spawn OCI container
+)
+
+// IsolationConfig selects how spawned children are kept apart. Every
+// backend gives a writable child its own git worktree; the container
+// backend also runs the agent in a container of its own, so parallel
+// children get separate network namespaces, toolchains and caches.
+type IsolationConfig struct {
+	Backend       string   `json:"backend,omitempty"`       // IsolationWorktree (default) or IsolationContainer
+	Runtime       string   `json:"runtime,omitempty"`       // podman or docker (default: whichever is installed, podman first)
+	Image         string   `json:"image,omitempty"`         // prebuilt image, used when containerfile is empty
+	Containerfile string   `json:"containerfile,omitempty"` // image spec built per project, relative to the repo root
+	Context       string   `json:"context,omitempty"`       // build context relative to the repo root (default: the containerfile's directory)
+	RunArgs       []string `json:"run_args,omitempty"`      // extra flags for the runtime's run command, e.g. ["--cpus", "2"]
+	Mounts        []string `json:"mounts,omitempty"`        // extra host paths mounted at the same path (absolute or ~/...)
+}
@@ -0,0 +1,52 @@
+package store
+
+import "time"
+
+// Issue sync provider types.
+const (
+	IssueSyncFile = "file" // JSON file of external issues, mainly a stand-in for tests
+	IssueSyncHTTP = "http" // JSON REST endpoint
+)
+
+// Issue sync directions.
+const (
+	IssueSyncBoth = "both"
+	IssueSyncPull = "pull" // only external changes flow into adaf
+	IssueSyncPush = "push" // only adaf changes flow out
+)
+
+// IssueSyncConfig connects the project's issues to an external tracker.
+// Status and priority maps translate adaf values to the tracker's; values
+// without an entry are passed through unchanged.
+type IssueSyncConfig struct {
+	Type            string            `json:"type"`                       // IssueSyncFile or IssueSyncHTTP
+	Path            string            `json:"path,omitempty"`             // file provider, relative to the project dir
+	URL             string            `json:"url,omitempty"`              // http provider base URL
+	TokenEnv        string            `json:"token_env,omitempty"`        // env var holding the http bearer token
+	Direction       string            `json:"direction,omitempty"`        // both (default), pull or push
+	IntervalMinutes int               `json:"interval_minutes,omitempty"` // daemon sync period, 0 = manual only
+	StatusMap       map[string]string `json:"status_map,omitempty"`
+	PriorityMap     map[string]string `json:"priority_map,omitempty"`
+}
+
+// IssueExternal links an issue to its counterpart in an external tracker.
+type IssueExternal struct {
+	Provider string `json:"provider"`
+	ID       string `json:"id"`
+	URL      string `json:"url,omitempty"`
+	// SyncedAt is when the last sync finished; local history entries after
+	// it are unsynced local edits.
+	SyncedAt time.Time `json:"synced_at,omitzero"`
+	// RemoteUpdated is the external issue's update time as of the last sync.
+	RemoteUpdated time.Time `json:"remote_updated,omitzero"`
+	// Remote holds the external field values as of the last sync, so fields
+	// changed remotely since can be told apart from unchanged ones.
+	Remote   map[string]string  `json:"remote,omitempty"`
+	Comments []IssueCommentLink `json:"comments,omitempty"`
+}
+
+// IssueCommentLink pairs a local comment with its external copy.
+type IssueCommentLink struct {
+	LocalID  int    `json:"local_id"`
+	RemoteID string `json:"remote_id"`
+}
@@ -20,6 +20,36 @@ type LoopRun struct {
 	PendingHandoffs  []HandoffInfo     `json:"pending_handoffs,omitempty"` // spawns handed off to next step
 	StepHexIDs       map[string]string `json:"step_hex_ids,omitempty"`     // "cycle:step" -> hex ID
 	DaemonSessionID  int               `json:"daemon_session_id,omitempty"`
+	UsagePause       *UsagePause       `json:"usage_pause,omitempty"`         // set while the current step waits for provider limits
+	PendingApproval  int               `json:"pending_approval_id,omitempty"` // approval the current step waits for
+	HeadCommit       string            `json:"head_commit,omitempty"`         // repository HEAD when the run stopped
+}
+
+// UsagePause records work held back until a provider usage limit resets.
+type UsagePause struct {
+	Profile        string    `json:"profile"`
+	Provider       string    `json:"provider"`
+	Limit          string    `json:"limit"`
+	UtilizationPct float64   `json:"utilization_pct"`
+	Until          time.Time `json:"until"`
+	StartedAt      time.Time `json:"started_at"`
+}
+
+// LoopRunBudget is the live spend of a loop run (spawn tree included) and of
+// its current step against their configured budgets.
+type LoopRunBudget struct {
+	CostUSD        float64   `json:"cost_usd"`
+	Tokens         int       `json:"tokens"`
+	MaxCostUSD     float64   `json:"max_cost_usd,omitempty"`
+	MaxTokens      int       `json:"max_tokens,omitempty"`
+	State          string    `json:"state,omitempty"` // "", "winding_down", "exceeded"
+	StepIndex      int       `json:"step_index"`
+	StepCostUSD    float64   `json:"step_cost_usd"`
+	StepTokens     int       `json:"step_tokens"`
+	StepMaxCostUSD float64   `json:"step_max_cost_usd,omitempty"`
+	StepMaxTokens  int       `json:"step_max_tokens,omitempty"`
+	StepState      string    `json:"step_state,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 // HandoffInfo describes a spawn handed off from a previous loop step.
@@ -30,10 +60,16 @@ type HandoffInfo struct {
 	Status  string `json:"status"`
 	Speed   string `json:"speed,omitempty"`
 	Branch  string `json:"branch,omitempty"`
+
+	// TurnID and Summary are set for results of a parallel step group
+	// member instead of a running spawn (SpawnID is then 0).
+	TurnID  int    `json:"turn_id,omitempty"`
+	Summary string `json:"summary,omitempty"`
 }
 
 // LoopRunStep is a snapshot of a loop step definition stored with the run.
 type LoopRunStep struct {
+	ID           string `json:"id,omitempty"`
 	Profile      string `json:"profile"`
 	Position     string `json:"position,omitempty"`
 	Role         string `json:"role,omitempty"`
@@ -63,3 +99,49 @@ type LoopCallSupervisorSignal struct {
 	Content         string    `json:"content,omitempty"`
 	CreatedAt       time.Time `json:"created_at"`
 }
+
+// LoopStepOutcome is the outcome token a loop step reported with
+// `adaf loop outcome`, consumed by step transitions once the step ends.
+type LoopStepOutcome struct {
+	RunID     int       `json:"run_id"`
+	StepIndex int       `json:"step_index"`
+	Outcome   string    `json:"outcome"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Loop schedule event outcomes recorded in LoopSchedule.History.
+const (
+	ScheduleOutcomeStarted = "started"
+	ScheduleOutcomeSkipped = "skipped"
+	ScheduleOutcomeMissed  = "missed"
+	ScheduleOutcomeFailed  = "failed"
+)
+
+// LoopSchedule is a cron-style trigger that starts a detached loop session.
+type LoopSchedule struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name,omitempty"`
+	Cron          string    `json:"cron"` // 5-field cron expression or @hourly/@daily/@weekly/@monthly
+	Loop          string    `json:"loop"`
+	PlanID        string    `json:"plan_id,omitempty"` // empty = project's active plan
+	InitialPrompt string    `json:"initial_prompt,omitempty"`
+	MaxCycles     int       `json:"max_cycles,omitempty"` // 0 = unlimited
+	Disabled      bool      `json:"disabled,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Scheduler bookkeeping.
+	LastCheckedAt time.Time       `json:"last_checked_at,omitzero"` // fire times up to here have been handled
+	LastFiredAt   time.Time       `json:"last_fired_at,omitzero"`
+	LastSessionID int             `json:"last_session_id,omitempty"`
+	History       []ScheduleEvent `json:"history,omitempty"` // most recent last, capped
+}
+
+// ScheduleEvent records what happened when a schedule came due.
+type ScheduleEvent struct {
+	At        time.Time `json:"at"` // scheduled fire time (first one for grouped missed runs)
+	Outcome   string    `json:"outcome"`
+	SessionID int       `json:"session_id,omitempty"`
+	Count     int       `json:"count,omitempty"` // number of fire times grouped into this event
+	Detail    string    `json:"detail,omitempty"`
+}
@@ -17,16 +17,68 @@ type SpawnRecord struct {
 	WorktreePath         string    `json:"worktree_path,omitempty"`
 	ReadOnly             bool      `json:"read_only,omitempty"`
 	WorkspaceFromSpawnID int       `json:"workspace_from_spawn_id,omitempty"`
-	Status               string    `json:"status"` // "running","awaiting_input","completed","failed","canceled","merged","rejected"
+	Status               string    `json:"status"` // "queued","running","awaiting_input","completed","failed","canceled","merged","rejected"
 	Result               string    `json:"result,omitempty"`
 	ExitCode             int       `json:"exit_code,omitempty"`
 	Summary              string    `json:"summary,omitempty"` // child's final output for parent consumption
 	StartedAt            time.Time `json:"started_at"`
 	CompletedAt          time.Time `json:"completed_at,omitzero"`
 	MergeCommit          string    `json:"merge_commit,omitempty"`
+	ConflictFiles        []string  `json:"conflict_files,omitempty"`
+	ResolverSpawnID      int       `json:"resolver_spawn_id,omitempty"`
 	Handoff              bool      `json:"handoff,omitempty"`       // can be handed off to next loop step
 	Speed                string    `json:"speed,omitempty"`         // speed rating from delegation profile
 	HandedOffToTurn      int       `json:"handed_off_to,omitempty"` // turn that inherited this spawn
+
+	// Acceptance checks run in the worktree after the child finishes.
+	BaseCommit     string             `json:"base_commit,omitempty"` // worktree HEAD when the spawn started
+	Checks         []AcceptanceCheck  `json:"checks,omitempty"`
+	CheckResults   []AcceptanceResult `json:"check_results,omitempty"`
+	VerifyRetries  int                `json:"verify_retries,omitempty"`  // resumes allowed after failing checks
+	VerifyAttempts int                `json:"verify_attempts,omitempty"` // resumes used so far
+
+	// MergeGate gates merging this spawn; ChildMergeGate gates merges of
+	// the spawns this child makes. Both are resolved when the spawn is
+	// created.
+	MergeGate      *MergeGate `json:"merge_gate,omitempty"`
+	ChildMergeGate *MergeGate `json:"child_merge_gate,omitempty"`
+
+	// Failovers lists fallback profiles that took over after a turn failed.
+	Failovers []ProfileFailover `json:"failovers,omitempty"`
+
+	// UsagePause is set while the spawn waits for provider usage limits.
+	UsagePause *UsagePause `json:"usage_pause,omitempty"`
+
+	// PullRequestURL is set once the spawn's branch was opened as a pull
+	// request instead of being merged locally.
+	PullRequestURL string `json:"pull_request_url,omitempty"`
+
+	// RemoteHost is the worker host the child ran on; its commits are
+	// fetched back into Branch when it exits.
+	RemoteHost     string `json:"remote_host,omitempty"`
+	RemoteWorktree string `json:"remote_worktree,omitempty"` // checkout path on RemoteHost
+
+	// Isolation names the isolation backend that wraps the worktree when it
+	// is not the plain worktree backend; it is released with the worktree.
+	Isolation string `json:"isolation,omitempty"`
+
+	// Queue state for spawns that hit a concurrency limit. QueuePosition is
+	// 1-based and only set while the status is "queued"; Priority uses the
+	// issue priority vocabulary and orders the queue. QueuedByPID is the
+	// process holding the in-memory queue entry.
+	Priority      string    `json:"priority,omitempty"`
+	QueuedAt      time.Time `json:"queued_at,omitzero"`
+	QueuePosition int       `json:"queue_position,omitempty"`
+	QueuedByPID   int       `json:"queued_by_pid,omitempty"`
+}
+
+// ProfileFailover records one switch to a fallback profile.
+type ProfileFailover struct {
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Reason string    `json:"reason"`  // quota|auth|crash|usage
+	TurnID int       `json:"turn_id"` // the failed turn
+	At     time.Time `json:"at"`
 }
 
 // SpawnMessage is a message exchanged between parent and child agents.
@@ -38,4 +90,13 @@ type SpawnMessage struct {
 	Content   string    `json:"content"`
 	ReplyToID int       `json:"reply_to_id,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
+
+	// Asks: who is expected to answer. Escalation moves an unanswered ask up
+	// the spawn tree, one ancestor turn at a time, and finally to the human.
+	AddresseeTurnID int       `json:"addressee_turn_id,omitempty"`
+	ToHuman         bool      `json:"to_human,omitempty"`
+	EscalatedAt     time.Time `json:"escalated_at,omitzero"`
+
+	// Replies: who answered ("human", or the answering agent's profile).
+	Author string `json:"author,omitempty"`
 }
@@ -0,0 +1,44 @@
+package store
+
+import "time"
+
+// Plan phase statuses.
+const (
+	PlanPhasePending = "pending"
+	PlanPhaseActive  = "active"
+	PlanPhaseDone    = "done"
+	PlanPhaseSkipped = "skipped" // dropped from the plan, ignored by progress
+)
+
+// PlanPhase is an ordered milestone of a plan. Its progress is derived from
+// the statuses of the linked issues.
+type PlanPhase struct {
+	ID         int       `json:"id"`
+	Title      string    `json:"title"`
+	Acceptance string    `json:"acceptance,omitempty"` // what must hold for the phase to count as done
+	Status     string    `json:"status"`
+	IssueIDs   []int     `json:"issue_ids,omitempty"`
+	Created    time.Time `json:"created"`
+	Updated    time.Time `json:"updated"`
+}
+
+// PhaseProgress is the computed progress of one plan phase.
+type PhaseProgress struct {
+	PhaseID      int    `json:"phase_id"`
+	Title        string `json:"title"`
+	Status       string `json:"status"`
+	TotalIssues  int    `json:"total_issues"`
+	ClosedIssues int    `json:"closed_issues"`
+	Percent      int    `json:"percent"`
+	Complete     bool   `json:"complete"` // marked done, or every linked issue is closed
+}
+
+// PlanProgress is the computed progress of a plan across its phases.
+type PlanProgress struct {
+	PlanID          string          `json:"plan_id"`
+	Phases          []PhaseProgress `json:"phases"`
+	CompletedPhases int             `json:"completed_phases"`
+	TotalPhases     int             `json:"total_phases"` // phases not skipped
+	Percent         int             `json:"percent"`
+	CurrentPhaseID  int             `json:"current_phase_id,omitempty"`
+}
@@ -9,12 +9,17 @@ type ProfileStats struct {
 	TotalTurns     int            `json:"total_turns"`
 	TotalDuration  int            `json:"total_duration_secs"`
 	TotalCostUSD   float64        `json:"total_cost_usd"`
-	TotalInputTok  int            `json:"total_input_tokens"`
-	TotalOutputTok int            `json:"total_output_tokens"`
-	ToolCalls      map[string]int `json:"tool_calls"`     // tool_name -> count
-	SpawnsCreated  int            `json:"spawns_created"` // times this profile spawned sub-agents
-	SpawnedBy      map[string]int `json:"spawned_by"`     // parent_profile -> count
-	TurnIDs        []int          `json:"session_ids"`    // all session IDs for this profile
+	EstimatedCost  float64        `json:"estimated_cost_usd,omitempty"` // part of TotalCostUSD priced from the model price table
+	TotalInputTok  int            `json:"total_input_tokens"`           // uncached input
+	TotalCachedTok int            `json:"total_cached_input_tokens,omitempty"`
+	TotalOutputTok int            `json:"total_output_tokens"` // excluding reasoning
+	TotalReasonTok int            `json:"total_reasoning_tokens,omitempty"`
+	TokenRuns      int            `json:"token_runs,omitempty"`
+	Model          string         `json:"model,omitempty"` // model of the most recent run
+	ToolCalls      map[string]int `json:"tool_calls"`      // tool_name -> count
+	SpawnsCreated  int            `json:"spawns_created"`  // times this profile spawned sub-agents
+	SpawnedBy      map[string]int `json:"spawned_by"`      // parent_profile -> count
+	TurnIDs        []int          `json:"session_ids"`     // all session IDs for this profile
 	SuccessCount   int            `json:"success_count"`
 	FailureCount   int            `json:"failure_count"`
 	LastRunAt      time.Time      `json:"last_run_at,omitempty"`
@@ -27,6 +32,7 @@ type LoopStats struct {
 	TotalCycles   int            `json:"total_cycles"`
 	TotalRuns     int            `json:"total_runs"` // number of loop run instances
 	TotalCostUSD  float64        `json:"total_cost_usd"`
+	EstimatedCost float64        `json:"estimated_cost_usd,omitempty"` // part of TotalCostUSD priced from the model price table
 	TotalDuration int            `json:"total_duration_secs"`
 	StepStats     map[string]int `json:"step_stats"` // profile_name -> total runs in this loop
 	TurnIDs       []int          `json:"session_ids"`
@@ -12,6 +12,23 @@ import (
 // fail on another.
 const maxLineSize = 8 * 1024 * 1024 // 8 MB
 
+// ParserFor returns the stream parser matching an agent's native output
+// format. Claude and plugin agents share the Claude NDJSON format.
+func ParserFor(agentName string) func(ctx context.Context, r io.Reader) <-chan RawEvent {
+	switch agentName {
+	case "codex":
+		return ParseCodex
+	case "gemini":
+		return ParseGemini
+	case "opencode":
+		return ParseOpencode
+	case "vibe":
+		return ParseVibe
+	default:
+		return Parse
+	}
+}
+
 // Parse reads NDJSON lines from r and sends parsed events on the returned
 // channel. The channel is closed when the reader reaches EOF or the context
 // is cancelled.
@@ -0,0 +1,262 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+)
+
+// DefaultMonitorTTL is how long the default monitor reuses a snapshot.
+const DefaultMonitorTTL = time.Minute
+
+// monitorFetchTimeout bounds a single provider fetch made by a Monitor.
+const monitorFetchTimeout = 20 * time.Second
+
+// Block describes a provider limit that keeps a profile from starting work.
+type Block struct {
+	Provider ProviderKind
+	Limit    UsageLimit
+}
+
+// Until returns when the blocking limit resets, or the zero time when the
+// provider did not report a reset.
+func (b *Block) Until() time.Time {
+	if b == nil || b.Limit.ResetsAt == nil {
+		return time.Time{}
+	}
+	return *b.Limit.ResetsAt
+}
+
+func (b *Block) String() string {
+	if b == nil {
+		return ""
+	}
+	s := fmt.Sprintf("%s %s at %.0f%%", b.Provider.DisplayName(), b.Limit.Name, b.Limit.UtilizationPct)
+	if until := b.Until(); !until.IsZero() {
+		s += ", resets " + until.Local().Format("Jan 2 15:04")
+	}
+	return s
+}
+
+// Checker reports whether an agent's provider is at or above threshold
+// percent utilization on any limit. A nil Block means the agent may run.
+type Checker interface {
+	Check(ctx context.Context, agentName string, threshold float64) (*Block, error)
+}
+
+// ProviderForAgent maps an agent name to the provider whose limits it spends.
+func ProviderForAgent(agentName string) (ProviderKind, bool) {
+	switch strings.ToLower(strings.TrimSpace(agentName)) {
+	case "claude":
+		return ProviderClaude, true
+	case "codex":
+		return ProviderCodex, true
+	default:
+		return "", false
+	}
+}
+
+// Blocking returns the limit at or above threshold that resets last, since
+// work cannot resume until every exhausted window has reset.
+func (s UsageSnapshot) Blocking(threshold float64) (UsageLimit, bool) {
+	var (
+		worst UsageLimit
+		found bool
+	)
+	for _, l := range s.Limits {
+		if l.UtilizationPct < threshold {
+			continue
+		}
+		if !found || resetsAfter(l, worst) {
+			worst, found = l, true
+		}
+	}
+	return worst, found
+}
+
+func resetsAfter(a, b UsageLimit) bool {
+	if a.ResetsAt == nil {
+		return false
+	}
+	return b.ResetsAt == nil || a.ResetsAt.After(*b.ResetsAt)
+}
+
+type cachedSnapshot struct {
+	snapshot UsageSnapshot
+	err      error
+	at       time.Time
+}
+
+// Monitor caches provider snapshots so gating every loop step and spawn does
+// not query provider APIs (or launch the codex CLI) each time.
+type Monitor struct {
+	ttl       time.Duration
+	providers map[ProviderKind]Provider
+
+	mu    sync.Mutex
+	cache map[ProviderKind]cachedSnapshot
+}
+
+// NewMonitor returns a Monitor over providers that reuses snapshots for ttl.
+func NewMonitor(providers []Provider, ttl time.Duration) *Monitor {
+	m := &Monitor{
+		ttl:       ttl,
+		providers: make(map[ProviderKind]Provider, len(providers)),
+		cache:     make(map[ProviderKind]cachedSnapshot),
+	}
+	for _, p := range providers {
+		m.providers[p.Name()] = p
+	}
+	return m
+}
+
+var (
+	defaultMonitorOnce sync.Once
+	defaultMonitor     *Monitor
+)
+
+// DefaultMonitor returns the process-wide monitor over DefaultProviders.
+func DefaultMonitor() *Monitor {
+	defaultMonitorOnce.Do(func() {
+		defaultMonitor = NewMonitor(DefaultProviders(), DefaultMonitorTTL)
+	})
+	return defaultMonitor
+}
+
+// Snapshot returns the provider's usage, fetching it when the cached copy is
+// older than the monitor's TTL. ok is false when the provider is unknown or
+// has no credentials.
+func (m *Monitor) Snapshot(ctx context.Context, kind ProviderKind) (snapshot UsageSnapshot, ok bool, err error) {
+	p := m.providers[kind]
+	if p == nil || !p.HasCredentials() {
+		return UsageSnapshot{}, false, nil
+	}
+
+	m.mu.Lock()
+	c, hit := m.cache[kind]
+	m.mu.Unlock()
+	if hit && time.Since(c.at) < m.ttl {
+		return c.snapshot, true, c.err
+	}
+
+	// Fetch without the lock so a slow provider does not stall checks of
+	// the others; concurrent misses may fetch twice.
+	fetchCtx, cancel := context.WithTimeout(ctx, monitorFetchTimeout)
+	defer cancel()
+	snapshot, err = p.FetchUsage(fetchCtx)
+	if ctx.Err() == nil {
+		m.mu.Lock()
+		m.cache[kind] = cachedSnapshot{snapshot: snapshot, err: err, at: time.Now()}
+		m.mu.Unlock()
+	}
+	return snapshot, true, err
+}
+
+// Check implements Checker. Agents without a known provider, and providers
+// without credentials, are never blocked.
+func (m *Monitor) Check(ctx context.Context, agentName string, threshold float64) (*Block, error) {
+	kind, ok := ProviderForAgent(agentName)
+	if !ok {
+		return nil, nil
+	}
+	snapshot, ok, err := m.Snapshot(ctx, kind)
+	if !ok || err != nil {
+		return nil, err
+	}
+	limit, blocked := snapshot.Blocking(threshold)
+	if !blocked {
+		return nil, nil
+	}
+	return &Block{Provider: kind, Limit: limit}, nil
+}
+
+// unknownResetRecheck is how long a pause lasts when the blocking limit has
+// no reset time.
+const unknownResetRecheck = 15 * time.Minute
+
+// PauseUntil returns when work blocked by b should be re-checked under gate:
+// the limit's reset time, capped by the gate's MaxPause.
+func PauseUntil(b *Block, gate *config.UsageGate, now time.Time) time.Time {
+	until := b.Until()
+	switch {
+	case until.IsZero():
+		until = now.Add(unknownResetRecheck)
+	case !until.After(now):
+		// The window should have reset; give the provider time to catch up.
+		until = now.Add(DefaultMonitorTTL)
+	}
+	if limit := gate.MaxPause(); limit > 0 && until.Sub(now) > limit {
+		until = now.Add(limit)
+	}
+	return until
+}
+
+// WaitForHeadroom sleeps until agentName's provider drops below the gate's
+// threshold. onPause is called before each sleep with the blocking limit and
+// the time of the next check. Check errors end the wait so a provider
+// outage never stalls work.
+func WaitForHeadroom(ctx context.Context, checker Checker, agentName string, gate *config.UsageGate, b *Block, onPause func(b *Block, until time.Time)) error {
+	for b != nil {
+		until := PauseUntil(b, gate, time.Now())
+		if onPause != nil {
+			onPause(b, until)
+		}
+		timer := time.NewTimer(time.Until(until))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		var err error
+		if b, err = checker.Check(ctx, agentName, gate.EffectiveThreshold()); err != nil {
+			return nil
+		}
+	}
+	return nil
+}
+
+// FirstAvailable returns the first of profiles whose provider is below
+// threshold, or nil when all of them are blocked.
+func FirstAvailable(ctx context.Context, checker Checker, profiles []config.Profile, threshold float64) *config.Profile {
+	for i := range profiles {
+		if b, err := checker.Check(ctx, profiles[i].Agent, threshold); err == nil && b == nil {
+			return &profiles[i]
+		}
+	}
+	return nil
+}
+
+var (
+	gateMu sync.Mutex
+	byTurn = make(map[int]*config.UsageGate)
+)
+
+// BindGate associates a turn with the usage gate of the loop it runs in, so
+// spawns requested from that turn are gated the same way.
+func BindGate(turnID int, g *config.UsageGate) {
+	if turnID <= 0 || g == nil {
+		return
+	}
+	gateMu.Lock()
+	byTurn[turnID] = g
+	gateMu.Unlock()
+}
+
+// UnbindGate removes a turn association created by BindGate.
+func UnbindGate(turnID int) {
+	gateMu.Lock()
+	delete(byTurn, turnID)
+	gateMu.Unlock()
+}
+
+// GateForTurn returns the usage gate bound to turnID, or nil.
+func GateForTurn(turnID int) *config.UsageGate {
+	gateMu.Lock()
+	defer gateMu.Unlock()
+	return byTurn[turnID]
+}
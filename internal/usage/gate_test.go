@@ -0,0 +1,126 @@
+package usage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+)
+
+type fakeProvider struct {
+	kind    ProviderKind
+	limits  []UsageLimit
+	fetches int
+}
+
+func (p *fakeProvider) Name() ProviderKind   { return p.kind }
+func (p *fakeProvider) HasCredentials() bool { return true }
+
+func (p *fakeProvider) FetchUsage(ctx context.Context) (UsageSnapshot, error) {
+	p.fetches++
+	return NewSnapshot(p.kind, p.limits, 70, 90), nil
+}
+
+// blockingChecker reports a block resetting shortly for its first blocked
+// checks, then clears.
+type blockingChecker struct {
+	blocked int
+	calls   int
+}
+
+func (c *blockingChecker) Check(ctx context.Context, agentName string, threshold float64) (*Block, error) {
+	c.calls++
+	if c.calls > c.blocked {
+		return nil, nil
+	}
+	return soonBlock(), nil
+}
+
+func soonBlock() *Block {
+	at := time.Now().Add(10 * time.Millisecond)
+	return &Block{Provider: ProviderCodex, Limit: UsageLimit{Name: "weekly", UtilizationPct: 100, ResetsAt: &at}}
+}
+
+func TestSnapshotBlockingPicksLatestReset(t *testing.T) {
+	soon := time.Now().Add(time.Hour)
+	later := time.Now().Add(48 * time.Hour)
+	snap := UsageSnapshot{Limits: []UsageLimit{
+		{Name: "5-hour window", UtilizationPct: 97, ResetsAt: &soon},
+		{Name: "7-day window", UtilizationPct: 100, ResetsAt: &later},
+		{Name: "7-day Opus", UtilizationPct: 40},
+	}}
+	limit, ok := snap.Blocking(90)
+	if !ok || limit.Name != "7-day window" {
+		t.Fatalf("Blocking(90) = %+v, %v; want 7-day window", limit, ok)
+	}
+	if _, ok := snap.Blocking(101); ok {
+		t.Fatal("Blocking(101) reported a block")
+	}
+}
+
+func TestMonitorCheckCachesSnapshots(t *testing.T) {
+	p := &fakeProvider{kind: ProviderClaude, limits: []UsageLimit{{Name: "5-hour window", UtilizationPct: 95}}}
+	m := NewMonitor([]Provider{p}, time.Hour)
+
+	b, err := m.Check(context.Background(), "claude", 90)
+	if err != nil || b == nil || b.Provider != ProviderClaude {
+		t.Fatalf("Check(claude, 90) = %+v, %v; want claude block", b, err)
+	}
+	if b, _ := m.Check(context.Background(), "claude", 99); b != nil {
+		t.Fatalf("Check(claude, 99) = %+v, want nil", b)
+	}
+	if p.fetches != 1 {
+		t.Fatalf("fetches = %d, want 1 (cached)", p.fetches)
+	}
+	if b, _ := m.Check(context.Background(), "gemini", 0); b != nil {
+		t.Fatalf("Check(gemini) = %+v, want nil for an untracked provider", b)
+	}
+}
+
+func TestPauseUntil(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	reset := now.Add(3 * time.Hour)
+	past := now.Add(-time.Minute)
+	tests := []struct {
+		name string
+		b    *Block
+		gate *config.UsageGate
+		want time.Time
+	}{
+		{"reset time", &Block{Limit: UsageLimit{ResetsAt: &reset}}, &config.UsageGate{}, reset},
+		{"capped", &Block{Limit: UsageLimit{ResetsAt: &reset}}, &config.UsageGate{MaxPauseMins: 30}, now.Add(30 * time.Minute)},
+		{"unknown reset", &Block{}, &config.UsageGate{}, now.Add(unknownResetRecheck)},
+		{"stale reset", &Block{Limit: UsageLimit{ResetsAt: &past}}, &config.UsageGate{}, now.Add(DefaultMonitorTTL)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PauseUntil(tt.b, tt.gate, now); !got.Equal(tt.want) {
+				t.Fatalf("PauseUntil() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForHeadroomRechecksUntilClear(t *testing.T) {
+	checker := &blockingChecker{blocked: 1}
+	var pauses int
+	err := WaitForHeadroom(context.Background(), checker, "codex", &config.UsageGate{}, soonBlock(), func(*Block, time.Time) {
+		pauses++
+	})
+	if err != nil {
+		t.Fatalf("WaitForHeadroom() error = %v", err)
+	}
+	if pauses != 2 || checker.calls != 2 {
+		t.Fatalf("pauses = %d, checks = %d; want 2 and 2", pauses, checker.calls)
+	}
+}
+
+func TestWaitForHeadroomStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := WaitForHeadroom(ctx, &blockingChecker{}, "claude", &config.UsageGate{}, &Block{}, nil)
+	if err != context.Canceled {
+		t.Fatalf("WaitForHeadroom() error = %v, want context.Canceled", err)
+	}
+}
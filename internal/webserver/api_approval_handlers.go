@@ -0,0 +1,88 @@
+package webserver
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+type approvalDecisionRequest struct {
+	Comment string `json:"comment"`
+	By      string `json:"by"`
+}
+
+func handleApprovalsP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	list, err := s.ListApprovals()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list approvals")
+		return
+	}
+	status := strings.TrimSpace(r.URL.Query().Get("status"))
+	out := make([]store.Approval, 0, len(list))
+	for _, a := range list {
+		if status == "" || a.Status == status {
+			out = append(out, a)
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func handleApprovalByIDP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	a, ok := loadApprovalFromPath(s, w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+func handleApproveP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	decideApprovalFromRequest(s, w, r, true)
+}
+
+func handleDenyP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	decideApprovalFromRequest(s, w, r, false)
+}
+
+func decideApprovalFromRequest(s *store.Store, w http.ResponseWriter, r *http.Request, approve bool) {
+	a, ok := loadApprovalFromPath(s, w, r)
+	if !ok {
+		return
+	}
+	var req approvalDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if a.Status != store.ApprovalPending {
+		writeError(w, http.StatusConflict, "approval is already "+a.Status)
+		return
+	}
+	decided, err := s.DecideApproval(a.ID, approve, resolveWriteActor(req.By), req.Comment)
+	if err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, decided)
+}
+
+func loadApprovalFromPath(s *store.Store, w http.ResponseWriter, r *http.Request) (*store.Approval, bool) {
+	id, err := parsePathID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "approval not found")
+		return nil, false
+	}
+	a, err := s.GetApproval(id)
+	if err != nil {
+		if isNotFoundErr(err) {
+			writeError(w, http.StatusNotFound, "approval not found")
+			return nil, false
+		}
+		writeError(w, http.StatusInternalServerError, "failed to load approval")
+		return nil, false
+	}
+	return a, true
+}
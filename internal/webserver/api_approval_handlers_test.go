@@ -0,0 +1,51 @@
+package webserver
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func TestApprovalDecisions(t *testing.T) {
+	srv, s := newTestServer(t)
+	for i := 0; i < 2; i++ {
+		if err := s.CreateApproval(&store.Approval{Kind: store.ApprovalKindMerge, SpawnID: i + 1, Summary: "merge"}); err != nil {
+			t.Fatalf("CreateApproval: %v", err)
+		}
+	}
+	base := "/api/projects/test-project/approvals"
+
+	rec := performJSONRequest(t, srv, http.MethodPost, base+"/1/approve", `{"comment":"looks good","by":"alice"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("approve: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	approved := decodeResponse[store.Approval](t, rec)
+	if approved.Status != store.ApprovalApproved || approved.Comment != "looks good" || approved.DecidedBy != "alice" {
+		t.Fatalf("approved = %+v", approved)
+	}
+
+	rec = performJSONRequest(t, srv, http.MethodPost, base+"/1/deny", "")
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("deny decided approval: status = %d, want 409", rec.Code)
+	}
+
+	rec = performJSONRequest(t, srv, http.MethodPost, base+"/2/deny", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("deny: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if denied := decodeResponse[store.Approval](t, rec); denied.DecidedBy != "human" {
+		t.Fatalf("denied.DecidedBy = %q, want human", denied.DecidedBy)
+	}
+
+	rec = performJSONRequest(t, srv, http.MethodGet, base+"?status=approved", "")
+	list := decodeResponse[[]store.Approval](t, rec)
+	if len(list) != 1 || list[0].ID != 1 {
+		t.Fatalf("approved list = %+v", list)
+	}
+
+	rec = performJSONRequest(t, srv, http.MethodGet, base+"/9", "")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("get missing: status = %d, want 404", rec.Code)
+	}
+}
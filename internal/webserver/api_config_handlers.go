@@ -225,6 +225,14 @@ func validateCreateProfile(prof config.Profile) string {
 	if prof.Cost != "" && !config.ValidProfileCost(prof.Cost) {
 		return "cost must be one of: free, cheap, normal, expensive"
 	}
+	if err := prof.Sandbox.Validate(); err != nil {
+		return err.Error()
+	}
+	for _, fb := range prof.Fallback {
+		if strings.EqualFold(strings.TrimSpace(fb), prof.Name) {
+			return "a profile cannot fall back to itself"
+		}
+	}
 	return ""
 }
 
@@ -248,10 +256,25 @@ func validateCreateLoop(loop config.LoopDef) string {
 	if p := strings.TrimSpace(loop.ResourcePriority); p != "" && !config.ValidResourcePriority(p) {
 		return "resource_priority must be one of: quality, normal, cost"
 	}
+	if err := loop.UsageGate.Validate(); err != nil {
+		return err.Error()
+	}
+	if err := loop.Approval.Validate(); err != nil {
+		return err.Error()
+	}
+	if err := loop.Approval.ValidateSteps(&loop); err != nil {
+		return err.Error()
+	}
 	if len(loop.Steps) == 0 {
 		return "at least one step is required"
 	}
 	for _, step := range loop.Steps {
+		if step.IsGroup() {
+			if err := config.ValidateLoopStepGroup(step); err != nil {
+				return err.Error()
+			}
+			continue
+		}
 		if strings.TrimSpace(step.Profile) == "" {
 			return "each step must have a profile"
 		}
@@ -0,0 +1,70 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+// inboxItem is a pending parent-ask with the spawn that asked it.
+type inboxItem struct {
+	store.SpawnMessage
+	Spawn *store.SpawnRecord `json:"spawn,omitempty"`
+}
+
+type inboxReplyRequest struct {
+	Answer string `json:"answer"`
+	By     string `json:"by"`
+}
+
+func handleInboxP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	asks, err := s.PendingAsks()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list pending questions")
+		return
+	}
+	out := make([]inboxItem, 0, len(asks))
+	for _, ask := range asks {
+		item := inboxItem{SpawnMessage: ask}
+		if rec, err := s.GetSpawn(ask.SpawnID); err == nil {
+			item.Spawn = rec
+		}
+		out = append(out, item)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func handleInboxReplyP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	spawnID, err := parsePathID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "spawn not found")
+		return
+	}
+	var req inboxReplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	answer := strings.TrimSpace(req.Answer)
+	if answer == "" {
+		writeError(w, http.StatusBadRequest, "answer is required")
+		return
+	}
+	ask, err := s.PendingAsk(spawnID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load question")
+		return
+	}
+	if ask == nil {
+		writeError(w, http.StatusNotFound, "no pending question for this spawn")
+		return
+	}
+	reply, err := s.ReplyToAsk(spawnID, answer, resolveWriteActor(req.By))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to save answer")
+		return
+	}
+	writeJSON(w, http.StatusOK, reply)
+}
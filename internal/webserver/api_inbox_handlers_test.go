@@ -0,0 +1,48 @@
+package webserver
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func TestInboxReply(t *testing.T) {
+	srv, s := newTestServer(t)
+	spawn := &store.SpawnRecord{ParentTurnID: 1, ChildProfile: "worker", Status: store.SpawnStatusAwaitingInput}
+	if err := s.CreateSpawn(spawn); err != nil {
+		t.Fatalf("CreateSpawn: %v", err)
+	}
+	if err := s.CreateMessage(&store.SpawnMessage{SpawnID: spawn.ID, Direction: "child_to_parent", Type: "ask", Content: "which API?"}); err != nil {
+		t.Fatalf("CreateMessage: %v", err)
+	}
+	base := "/api/projects/test-project/inbox"
+
+	rec := performJSONRequest(t, srv, http.MethodGet, base, "")
+	items := decodeResponse[[]inboxItem](t, rec)
+	if len(items) != 1 || items[0].Content != "which API?" || items[0].Spawn == nil || items[0].Spawn.ChildProfile != "worker" {
+		t.Fatalf("inbox = %+v", items)
+	}
+
+	rec = performJSONRequest(t, srv, http.MethodPost, base+"/1/reply", `{"answer":""}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("empty answer: status = %d, want 400", rec.Code)
+	}
+	rec = performJSONRequest(t, srv, http.MethodPost, base+"/1/reply", `{"answer":"use v2"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("reply: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	reply := decodeResponse[store.SpawnMessage](t, rec)
+	if reply.Content != "use v2" || reply.Author != "human" {
+		t.Fatalf("reply = %+v", reply)
+	}
+
+	rec = performJSONRequest(t, srv, http.MethodGet, base, "")
+	if items := decodeResponse[[]inboxItem](t, rec); len(items) != 0 {
+		t.Fatalf("inbox after reply = %+v, want empty", items)
+	}
+	rec = performJSONRequest(t, srv, http.MethodPost, base+"/1/reply", `{"answer":"again"}`)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("reply without question: status = %d, want 404", rec.Code)
+	}
+}
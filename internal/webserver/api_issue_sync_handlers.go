@@ -0,0 +1,22 @@
+package webserver
+
+import (
+	"net/http"
+
+	"github.com/agusx1211/adaf/internal/issuesync"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func handleIssueSyncP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	sy, err := issuesync.NewSyncer(s)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := sy.Sync(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
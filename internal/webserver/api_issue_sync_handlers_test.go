@@ -0,0 +1,47 @@
+package webserver
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/issuesync"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func TestIssueSyncHandler(t *testing.T) {
+	srv, s := newTestServer(t)
+	path := "/api/projects/test-project/issues/sync"
+
+	rec := performJSONRequest(t, srv, http.MethodPost, path, "")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("unconfigured: status = %d, want 400", rec.Code)
+	}
+
+	cfg, err := s.LoadProject()
+	if err != nil {
+		t.Fatalf("LoadProject: %v", err)
+	}
+	cfg.IssueSync = &store.IssueSyncConfig{Type: store.IssueSyncFile, Path: "tracker.json"}
+	if err := s.SaveProject(cfg); err != nil {
+		t.Fatalf("SaveProject: %v", err)
+	}
+	tracker := filepath.Join(s.ProjectDir(), "tracker.json")
+	if err := os.WriteFile(tracker, []byte(`[{"id":"7","title":"From tracker","status":"open","updated":"2026-01-01T00:00:00Z"}]`), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	rec = performJSONRequest(t, srv, http.MethodPost, path, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("sync: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	res := decodeResponse[issuesync.Result](t, rec)
+	if len(res.Imported) != 1 {
+		t.Fatalf("result = %+v, want one import", res)
+	}
+	issue, err := s.GetIssue(res.Imported[0])
+	if err != nil || issue.External == nil || issue.External.ID != "7" {
+		t.Fatalf("imported issue = %+v, err = %v", issue, err)
+	}
+}
@@ -0,0 +1,193 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+type planPhaseWriteRequest struct {
+	Title      *string `json:"title"`
+	Acceptance *string `json:"acceptance"`
+	Status     *string `json:"status"`
+	IssueIDs   *[]int  `json:"issue_ids"` // replaces the linked issues
+	Position   *int    `json:"position"`  // 1-based
+}
+
+// loadPlanForPhases loads the plan named in the path or writes the error.
+func loadPlanForPhases(s *store.Store, w http.ResponseWriter, r *http.Request) *store.Plan {
+	planID := strings.TrimSpace(r.PathValue("id"))
+	if planID == "" {
+		writeError(w, http.StatusNotFound, "plan not found")
+		return nil
+	}
+	plan, err := s.GetPlan(planID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load plan")
+		return nil
+	}
+	if plan == nil {
+		writeError(w, http.StatusNotFound, "plan not found")
+		return nil
+	}
+	return plan
+}
+
+func validatePlanPhaseIssues(s *store.Store, ids []int) bool {
+	for _, id := range ids {
+		if issue, err := s.GetIssue(id); err != nil || issue == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func handlePlanProgressP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	plan := loadPlanForPhases(s, w, r)
+	if plan == nil {
+		return
+	}
+	progress, err := s.PlanProgress(plan)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to compute plan progress")
+		return
+	}
+	writeJSON(w, http.StatusOK, progress)
+}
+
+func handleCreatePlanPhaseP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	plan := loadPlanForPhases(s, w, r)
+	if plan == nil {
+		return
+	}
+
+	var req planPhaseWriteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	phase := store.PlanPhase{}
+	if req.Title != nil {
+		phase.Title = *req.Title
+	}
+	if req.Acceptance != nil {
+		phase.Acceptance = strings.TrimSpace(*req.Acceptance)
+	}
+	if req.Status != nil {
+		phase.Status = *req.Status
+	}
+	if req.IssueIDs != nil {
+		if !validatePlanPhaseIssues(s, *req.IssueIDs) {
+			writeError(w, http.StatusBadRequest, "linked issue not found")
+			return
+		}
+		phase.IssueIDs = *req.IssueIDs
+	}
+	added, err := plan.AddPhase(phase)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	created := *added
+	if req.Position != nil {
+		if err := plan.MovePhase(created.ID, *req.Position); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if err := s.UpdatePlan(plan); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to update plan")
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func handleUpdatePlanPhaseP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	plan := loadPlanForPhases(s, w, r)
+	if plan == nil {
+		return
+	}
+	phaseID, err := parsePathID(r.PathValue("phase"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid phase id")
+		return
+	}
+	phase := plan.Phase(phaseID)
+	if phase == nil {
+		writeError(w, http.StatusNotFound, "phase not found")
+		return
+	}
+
+	var req planPhaseWriteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			writeError(w, http.StatusBadRequest, "phase title cannot be empty")
+			return
+		}
+		phase.Title = title
+	}
+	if req.Acceptance != nil {
+		phase.Acceptance = strings.TrimSpace(*req.Acceptance)
+	}
+	if req.Status != nil {
+		status := store.NormalizePlanPhaseStatus(*req.Status)
+		if !store.IsValidPlanPhaseStatus(status) {
+			writeError(w, http.StatusBadRequest, "invalid phase status")
+			return
+		}
+		phase.Status = status
+	}
+	if req.IssueIDs != nil {
+		if !validatePlanPhaseIssues(s, *req.IssueIDs) {
+			writeError(w, http.StatusBadRequest, "linked issue not found")
+			return
+		}
+		phase.IssueIDs = store.NormalizeIssueDependencyIDs(*req.IssueIDs)
+	}
+	phase.Updated = time.Now().UTC()
+	updated := *phase
+	if req.Position != nil {
+		if err := plan.MovePhase(phaseID, *req.Position); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if err := s.UpdatePlan(plan); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to update plan")
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+func handleDeletePlanPhaseP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	plan := loadPlanForPhases(s, w, r)
+	if plan == nil {
+		return
+	}
+	phaseID, err := parsePathID(r.PathValue("phase"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid phase id")
+		return
+	}
+	if !plan.RemovePhase(phaseID) {
+		writeError(w, http.StatusNotFound, "phase not found")
+		return
+	}
+	if err := s.UpdatePlan(plan); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to update plan")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
@@ -0,0 +1,64 @@
+package webserver
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func TestPlanPhaseHandlers(t *testing.T) {
+	srv, s := newTestServer(t)
+	if err := s.CreatePlan(&store.Plan{ID: "auth", Title: "Auth"}); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+	for _, status := range []string{"closed", "open"} {
+		if err := s.CreateIssue(&store.Issue{Title: "issue", Status: status, PlanID: "auth"}); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+	}
+	base := "/api/projects/test-project/plans/auth"
+
+	rec := performJSONRequest(t, srv, http.MethodPost, base+"/phases", `{"title":"Login","acceptance":"users sign in","issue_ids":[1,2]}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create phase: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if phase := decodeResponse[store.PlanPhase](t, rec); phase.ID != 1 || phase.Status != store.PlanPhasePending {
+		t.Fatalf("created phase = %+v", phase)
+	}
+	rec = performJSONRequest(t, srv, http.MethodPost, base+"/phases", `{"title":"Logout","position":1}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create second phase: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	rec = performJSONRequest(t, srv, http.MethodPost, base+"/phases", `{"title":"Bad","issue_ids":[9]}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("unknown issue: status = %d, want 400", rec.Code)
+	}
+
+	rec = performJSONRequest(t, srv, http.MethodPut, base+"/phases/2", `{"status":"skipped"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update phase: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	rec = performJSONRequest(t, srv, http.MethodPut, base+"/phases/1", `{"status":"finished"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid status: status = %d, want 400", rec.Code)
+	}
+
+	rec = performJSONRequest(t, srv, http.MethodGet, base+"/progress", "")
+	progress := decodeResponse[store.PlanProgress](t, rec)
+	if progress.CurrentPhaseID != 1 || progress.TotalPhases != 1 || progress.Percent != 50 {
+		t.Fatalf("progress = %+v", progress)
+	}
+	if len(progress.Phases) != 2 || progress.Phases[0].PhaseID != 2 {
+		t.Fatalf("phase order = %+v, want the moved phase first", progress.Phases)
+	}
+
+	rec = performJSONRequest(t, srv, http.MethodDelete, base+"/phases/2", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("delete phase: status = %d", rec.Code)
+	}
+	rec = performJSONRequest(t, srv, http.MethodDelete, base+"/phases/2", "")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("delete missing phase: status = %d, want 404", rec.Code)
+	}
+}
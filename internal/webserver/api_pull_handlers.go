@@ -0,0 +1,88 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/agusx1211/adaf/internal/forge"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+type createPullRequestRequest struct {
+	SpawnID   int    `json:"spawn_id"`
+	LoopRunID int    `json:"loop_run_id"`
+	Title     string `json:"title"`
+}
+
+type syncPullRequestResponse struct {
+	PullRequest *store.PullRequest `json:"pull_request"`
+	NewComments int                `json:"new_comments"`
+}
+
+func handlePullRequestsP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	list, err := s.ListPullRequests()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list pull requests")
+		return
+	}
+	if list == nil {
+		list = []store.PullRequest{}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func handleCreatePullRequestP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	var req createPullRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if (req.SpawnID > 0) == (req.LoopRunID > 0) {
+		writeError(w, http.StatusBadRequest, "exactly one of spawn_id or loop_run_id is required")
+		return
+	}
+	pub, err := forge.NewPublisher(s)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var pr *store.PullRequest
+	if req.SpawnID > 0 {
+		pr, err = pub.PublishSpawn(r.Context(), req.SpawnID, req.Title)
+	} else {
+		pr, err = pub.PublishLoopRun(r.Context(), req.LoopRunID, req.Title)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, pr)
+}
+
+func handleSyncPullRequestP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "pull request not found")
+		return
+	}
+	pr, err := s.GetPullRequest(id)
+	if err != nil {
+		if isNotFoundErr(err) {
+			writeError(w, http.StatusNotFound, "pull request not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to load pull request")
+		return
+	}
+	pub, err := forge.NewPublisher(s)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	n, err := pub.Sync(r.Context(), pr)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, syncPullRequestResponse{PullRequest: pr, NewComments: n})
+}
@@ -0,0 +1,40 @@
+package webserver
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+func TestPullRequestHandlers(t *testing.T) {
+	srv, s := newTestServer(t)
+	base := "/api/projects/test-project/pulls"
+
+	rec := performJSONRequest(t, srv, http.MethodGet, base, "")
+	if list := decodeResponse[[]store.PullRequest](t, rec); len(list) != 0 {
+		t.Fatalf("pulls = %+v, want empty", list)
+	}
+
+	if err := s.CreatePullRequest(&store.PullRequest{Number: 4, Title: "Add parser", Head: "adaf/spawn-1", Base: "main", State: "open"}); err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	rec = performJSONRequest(t, srv, http.MethodGet, base, "")
+	list := decodeResponse[[]store.PullRequest](t, rec)
+	if len(list) != 1 || list[0].Number != 4 || list[0].Head != "adaf/spawn-1" {
+		t.Fatalf("pulls = %+v", list)
+	}
+
+	rec = performJSONRequest(t, srv, http.MethodPost, base, `{"spawn_id":1,"loop_run_id":2}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("both sources: status = %d, want 400", rec.Code)
+	}
+	rec = performJSONRequest(t, srv, http.MethodPost, base, `{"spawn_id":1}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("no forge configured: status = %d, want 400", rec.Code)
+	}
+	rec = performJSONRequest(t, srv, http.MethodPost, base+"/99/sync", "")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown pull request: status = %d, want 404", rec.Code)
+	}
+}
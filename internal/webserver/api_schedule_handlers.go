@@ -0,0 +1,163 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/scheduler"
+	"github.com/agusx1211/adaf/internal/store"
+)
+
+type scheduleResponse struct {
+	store.LoopSchedule
+	NextRunAt time.Time `json:"next_run_at,omitzero"`
+}
+
+type scheduleWriteRequest struct {
+	Name          *string `json:"name"`
+	Cron          *string `json:"cron"`
+	Loop          *string `json:"loop"`
+	PlanID        *string `json:"plan_id"`
+	InitialPrompt *string `json:"initial_prompt"`
+	MaxCycles     *int    `json:"max_cycles"`
+	Disabled      *bool   `json:"disabled"`
+}
+
+func (req scheduleWriteRequest) apply(sch *store.LoopSchedule) {
+	if req.Name != nil {
+		sch.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Cron != nil {
+		sch.Cron = strings.TrimSpace(*req.Cron)
+	}
+	if req.Loop != nil {
+		sch.Loop = strings.TrimSpace(*req.Loop)
+	}
+	if req.PlanID != nil {
+		sch.PlanID = strings.TrimSpace(*req.PlanID)
+	}
+	if req.InitialPrompt != nil {
+		sch.InitialPrompt = *req.InitialPrompt
+	}
+	if req.MaxCycles != nil {
+		sch.MaxCycles = *req.MaxCycles
+	}
+	if req.Disabled != nil {
+		store.SetScheduleEnabled(sch, !*req.Disabled)
+	}
+}
+
+func newScheduleResponse(sch store.LoopSchedule) scheduleResponse {
+	return scheduleResponse{LoopSchedule: sch, NextRunAt: scheduler.NextRun(&sch, time.Now())}
+}
+
+func handleSchedulesP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	list, err := s.ListSchedules()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list schedules")
+		return
+	}
+	out := make([]scheduleResponse, 0, len(list))
+	for _, sch := range list {
+		out = append(out, newScheduleResponse(sch))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func handleScheduleByIDP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	sch, ok := loadScheduleFromPath(s, w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, newScheduleResponse(*sch))
+}
+
+func handleCreateScheduleP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	var req scheduleWriteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	sch := &store.LoopSchedule{}
+	req.apply(sch)
+	if !validateScheduleRequest(w, sch) {
+		return
+	}
+	if err := s.CreateSchedule(sch); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to create schedule")
+		return
+	}
+	writeJSON(w, http.StatusCreated, newScheduleResponse(*sch))
+}
+
+func handleUpdateScheduleP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	sch, ok := loadScheduleFromPath(s, w, r)
+	if !ok {
+		return
+	}
+	var req scheduleWriteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.apply(sch)
+	if !validateScheduleRequest(w, sch) {
+		return
+	}
+	if err := s.UpdateSchedule(sch); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to update schedule")
+		return
+	}
+	writeJSON(w, http.StatusOK, newScheduleResponse(*sch))
+}
+
+func handleDeleteScheduleP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "schedule not found")
+		return
+	}
+	if err := s.DeleteSchedule(id); err != nil {
+		if isNotFoundErr(err) {
+			writeError(w, http.StatusNotFound, "schedule not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to delete schedule")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+func loadScheduleFromPath(s *store.Store, w http.ResponseWriter, r *http.Request) (*store.LoopSchedule, bool) {
+	id, err := parsePathID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "schedule not found")
+		return nil, false
+	}
+	sch, err := s.GetSchedule(id)
+	if err != nil {
+		if isNotFoundErr(err) {
+			writeError(w, http.StatusNotFound, "schedule not found")
+			return nil, false
+		}
+		writeError(w, http.StatusInternalServerError, "failed to load schedule")
+		return nil, false
+	}
+	return sch, true
+}
+
+func validateScheduleRequest(w http.ResponseWriter, sch *store.LoopSchedule) bool {
+	cfg, err := config.Load()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load config")
+		return false
+	}
+	if err := scheduler.ValidateSchedule(sch, cfg); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
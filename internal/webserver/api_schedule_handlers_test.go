@@ -0,0 +1,60 @@
+package webserver
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/agusx1211/adaf/internal/config"
+)
+
+func TestScheduleCRUD(t *testing.T) {
+	srv, _ := newTestServer(t)
+	if err := config.Save(&config.GlobalConfig{
+		Loops: []config.LoopDef{{Name: "nightly", Steps: []config.LoopStep{{Profile: "p"}}}},
+	}); err != nil {
+		t.Fatalf("config.Save: %v", err)
+	}
+	base := "/api/projects/test-project/schedules"
+
+	rec := performJSONRequest(t, srv, http.MethodPost, base, `{"cron":"0 2 * * *","loop":"missing"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("create with unknown loop: status = %d, want 400", rec.Code)
+	}
+	rec = performJSONRequest(t, srv, http.MethodPost, base, `{"cron":"bad","loop":"nightly"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("create with bad cron: status = %d, want 400", rec.Code)
+	}
+
+	rec = performJSONRequest(t, srv, http.MethodPost, base, `{"name":"nightly-run","cron":"0 2 * * *","loop":"nightly","max_cycles":1}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	created := decodeResponse[scheduleResponse](t, rec)
+	if created.ID == 0 || created.NextRunAt.IsZero() || created.MaxCycles != 1 {
+		t.Fatalf("created = %+v", created)
+	}
+
+	rec = performJSONRequest(t, srv, http.MethodPut, base+"/1", `{"disabled":true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	updated := decodeResponse[scheduleResponse](t, rec)
+	if !updated.Disabled || !updated.NextRunAt.IsZero() || updated.Cron != "0 2 * * *" {
+		t.Fatalf("updated = %+v", updated)
+	}
+
+	rec = performJSONRequest(t, srv, http.MethodGet, base, "")
+	list := decodeResponse[[]scheduleResponse](t, rec)
+	if len(list) != 1 || list[0].Name != "nightly-run" {
+		t.Fatalf("list = %+v", list)
+	}
+
+	rec = performJSONRequest(t, srv, http.MethodDelete, base+"/1", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("delete: status = %d", rec.Code)
+	}
+	rec = performJSONRequest(t, srv, http.MethodGet, base+"/1", "")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("get after delete: status = %d, want 404", rec.Code)
+	}
+}
@@ -166,6 +166,10 @@ func handleStartLoopSessionP(s *store.Store, w http.ResponseWriter, r *http.Requ
 			return
 		}
 	}
+	if err := config.ValidateLoopTransitions(&loopDefCopy); err != nil {
+		writeError(w, http.StatusBadRequest, "loop definition has invalid transitions: "+err.Error())
+		return
+	}
 
 	projCfg, err := s.LoadProject()
 	if err != nil {
@@ -221,20 +225,22 @@ func handleStartLoopSessionP(s *store.Store, w http.ResponseWriter, r *http.Requ
 func collectLoopProfiles(cfg *config.GlobalConfig, loopDef *config.LoopDef) []config.Profile {
 	seen := map[string]bool{}
 	var profiles []config.Profile
-	for _, step := range loopDef.Steps {
-		if !seen[step.Profile] {
-			if p := cfg.FindProfile(step.Profile); p != nil {
-				profiles = append(profiles, *p)
-				seen[step.Profile] = true
+	for _, group := range loopDef.Steps {
+		for _, step := range group.Members() {
+			if !seen[step.Profile] {
+				if p := cfg.FindProfile(step.Profile); p != nil {
+					profiles = append(profiles, *p)
+					seen[step.Profile] = true
+				}
 			}
-		}
-		if step.Team != "" {
-			if t := cfg.FindTeam(step.Team); t != nil && t.Delegation != nil {
-				for _, dp := range t.Delegation.Profiles {
-					if !seen[dp.Name] {
-						if p := cfg.FindProfile(dp.Name); p != nil {
-							profiles = append(profiles, *p)
-							seen[dp.Name] = true
+			if step.Team != "" {
+				if t := cfg.FindTeam(step.Team); t != nil && t.Delegation != nil {
+					for _, dp := range t.Delegation.Profiles {
+						if !seen[dp.Name] {
+							if p := cfg.FindProfile(dp.Name); p != nil {
+								profiles = append(profiles, *p)
+								seen[dp.Name] = true
+							}
 						}
 					}
 				}
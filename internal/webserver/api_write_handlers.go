@@ -5,10 +5,13 @@ import (
 	"errors"
 	"net/http"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/agusx1211/adaf/internal/config"
+	"github.com/agusx1211/adaf/internal/notify"
 	"github.com/agusx1211/adaf/internal/store"
 )
 
@@ -47,6 +50,8 @@ type issueWriteRequest struct {
 	TurnID      int      `json:"turn_id"`
 	CreatedBy   string   `json:"created_by"`
 	UpdatedBy   string   `json:"updated_by"`
+
+	Checks []store.AcceptanceCheck `json:"checks"`
 }
 
 type issueCommentWriteRequest struct {
@@ -130,6 +135,10 @@ func handleCreateIssueP(s *store.Store, w http.ResponseWriter, r *http.Request)
 		writeError(w, http.StatusBadRequest, "invalid issue dependencies")
 		return
 	}
+	if err := validateAcceptanceChecks(req.Checks); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	now := time.Now().UTC()
 	actor := resolveWriteActor(req.CreatedBy, req.UpdatedBy)
@@ -141,6 +150,7 @@ func handleCreateIssueP(s *store.Store, w http.ResponseWriter, r *http.Request)
 		Priority:    priority,
 		Labels:      req.Labels,
 		DependsOn:   dependsOn,
+		Checks:      req.Checks,
 		TurnID:      req.TurnID,
 		CreatedBy:   actor,
 		UpdatedBy:   actor,
@@ -198,6 +208,14 @@ func handleUpdateIssueP(s *store.Store, w http.ResponseWriter, r *http.Request)
 		}
 		issue.DependsOn = dependsOn
 	}
+	if req.Checks != nil {
+		if err := validateAcceptanceChecks(req.Checks); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		issue.Checks = req.Checks
+	}
+	prevStatus := issue.Status
 	if status := normalizeLower(req.Status); status != "" {
 		status = store.NormalizeIssueStatus(status)
 		if !isAllowedValue(status, issueStatuses) || !store.IsValidIssueStatus(status) {
@@ -224,6 +242,9 @@ func handleUpdateIssueP(s *store.Store, w http.ResponseWriter, r *http.Request)
 		writeError(w, http.StatusInternalServerError, "failed to update issue")
 		return
 	}
+	if issue.Status == store.IssueStatusInReview && store.NormalizeIssueStatus(prevStatus) != store.IssueStatusInReview {
+		publishIssueInReview(s, issue)
+	}
 
 	writeJSON(w, http.StatusOK, issue)
 }
@@ -440,6 +461,54 @@ func handleWikiP(s *store.Store, w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, wiki)
 }
 
+func handleSearchP(s *store.Store, w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		writeError(w, http.StatusBadRequest, "query is required")
+		return
+	}
+
+	limit := 20
+	if rawLimit := strings.TrimSpace(r.URL.Query().Get("limit")); rawLimit != "" {
+		parsedLimit, err := strconv.Atoi(rawLimit)
+		if err != nil || parsedLimit <= 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		if parsedLimit > 100 {
+			parsedLimit = 100
+		}
+		limit = parsedLimit
+	}
+
+	var kinds []string
+	for _, raw := range r.URL.Query()["type"] {
+		for _, kind := range strings.Split(raw, ",") {
+			kind = normalizeLower(kind)
+			if kind == "" {
+				continue
+			}
+			if !slices.Contains(store.SearchKinds, kind) {
+				writeError(w, http.StatusBadRequest, "unknown type: "+kind)
+				return
+			}
+			kinds = append(kinds, kind)
+		}
+	}
+
+	results, err := s.Search(store.SearchQuery{
+		Text:   query,
+		Kinds:  kinds,
+		PlanID: strings.TrimSpace(r.URL.Query().Get("plan")),
+		Limit:  limit,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to search")
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
 func handleWikiSearchP(s *store.Store, w http.ResponseWriter, r *http.Request) {
 	query := strings.TrimSpace(r.URL.Query().Get("q"))
 	if query == "" {
@@ -701,3 +770,25 @@ func slugifyWikiID(title string) string {
 	slug = wikiSlugMultiDash.ReplaceAllString(slug, "-")
 	return strings.Trim(slug, "-")
 }
+
+func validateAcceptanceChecks(checks []store.AcceptanceCheck) error {
+	for _, c := range checks {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishIssueInReview notifies sinks subscribed to issue_in_review.
+func publishIssueInReview(s *store.Store, issue *store.Issue) {
+	globalCfg, err := config.Load()
+	if err != nil {
+		return
+	}
+	project := ""
+	if projCfg, err := s.LoadProject(); err == nil && projCfg != nil {
+		project = projCfg.Name
+	}
+	notify.Publish(globalCfg, notify.IssueInReview(project, issue))
+}
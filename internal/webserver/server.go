@@ -14,6 +14,8 @@ import (
 
 	"github.com/agusx1211/adaf/internal/config"
 	"github.com/agusx1211/adaf/internal/debug"
+	"github.com/agusx1211/adaf/internal/issuesync"
+	"github.com/agusx1211/adaf/internal/scheduler"
 	"github.com/agusx1211/adaf/internal/store"
 )
 
@@ -46,6 +48,8 @@ type Server struct {
 	keyFile     string
 	authToken   string
 	rateLimit   float64
+
+	stopScheduler context.CancelFunc
 }
 
 // NewMulti constructs a web server with a pre-populated project registry.
@@ -146,11 +150,37 @@ func (srv *Server) Start() error {
 		}
 	}()
 
+	srv.startScheduler()
 	return nil
 }
 
+// startScheduler runs the loop scheduler and periodic issue sync over all
+// registered projects for as long as the server is up.
+func (srv *Server) startScheduler() {
+	ctx, cancel := context.WithCancel(context.Background())
+	srv.stopScheduler = cancel
+	sched := &scheduler.Scheduler{Stores: srv.registeredStores}
+	go sched.Run(ctx, scheduler.DefaultInterval)
+	syncer := &issuesync.Runner{Stores: srv.registeredStores}
+	go syncer.Run(ctx, issuesync.DefaultCheckInterval)
+}
+
+func (srv *Server) registeredStores() []*store.Store {
+	entries := srv.registry.List()
+	stores := make([]*store.Store, 0, len(entries))
+	for _, e := range entries {
+		if s, ok := srv.registry.Get(e.ID); ok {
+			stores = append(stores, s)
+		}
+	}
+	return stores
+}
+
 // Shutdown gracefully stops the HTTP server.
 func (srv *Server) Shutdown(ctx context.Context) error {
+	if srv.stopScheduler != nil {
+		srv.stopScheduler()
+	}
 	if srv.httpServer == nil {
 		return nil
 	}
@@ -337,6 +367,7 @@ func (srv *Server) setupRoutes(mux *http.ServeMux) {
 // The prefix is either "/api/projects/{projectID}" or "/api" (for backward compat).
 func (srv *Server) registerProjectRoutes(mux *http.ServeMux, prefix string) {
 	mux.HandleFunc("GET "+prefix+"/project", srv.projectHandler(handleProjectP))
+	mux.HandleFunc("GET "+prefix+"/search", srv.projectHandler(handleSearchP))
 
 	mux.HandleFunc("GET "+prefix+"/plans", srv.projectHandler(handlePlansP))
 	mux.HandleFunc("GET "+prefix+"/plans/{id}", srv.projectHandler(handlePlanByIDP))
@@ -344,6 +375,10 @@ func (srv *Server) registerProjectRoutes(mux *http.ServeMux, prefix string) {
 	mux.HandleFunc("PUT "+prefix+"/plans/{id}", srv.projectHandler(handleUpdatePlanP))
 	mux.HandleFunc("POST "+prefix+"/plans/{id}/activate", srv.projectHandler(handleActivatePlanP))
 	mux.HandleFunc("DELETE "+prefix+"/plans/{id}", srv.projectHandler(handleDeletePlanP))
+	mux.HandleFunc("GET "+prefix+"/plans/{id}/progress", srv.projectHandler(handlePlanProgressP))
+	mux.HandleFunc("POST "+prefix+"/plans/{id}/phases", srv.projectHandler(handleCreatePlanPhaseP))
+	mux.HandleFunc("PUT "+prefix+"/plans/{id}/phases/{phase}", srv.projectHandler(handleUpdatePlanPhaseP))
+	mux.HandleFunc("DELETE "+prefix+"/plans/{id}/phases/{phase}", srv.projectHandler(handleDeletePlanPhaseP))
 
 	mux.HandleFunc("GET "+prefix+"/issues", srv.projectHandler(handleIssuesP))
 	mux.HandleFunc("GET "+prefix+"/issues/{id}", srv.projectHandler(handleIssueByIDP))
@@ -351,6 +386,7 @@ func (srv *Server) registerProjectRoutes(mux *http.ServeMux, prefix string) {
 	mux.HandleFunc("PUT "+prefix+"/issues/{id}", srv.projectHandler(handleUpdateIssueP))
 	mux.HandleFunc("POST "+prefix+"/issues/{id}/comments", srv.projectHandler(handleCreateIssueCommentP))
 	mux.HandleFunc("DELETE "+prefix+"/issues/{id}", srv.projectHandler(handleDeleteIssueP))
+	mux.HandleFunc("POST "+prefix+"/issues/sync", srv.projectHandler(handleIssueSyncP))
 
 	mux.HandleFunc("GET "+prefix+"/wiki", srv.projectHandler(handleWikiP))
 	mux.HandleFunc("GET "+prefix+"/wiki/search", srv.projectHandler(handleWikiSearchP))
@@ -384,6 +420,28 @@ func (srv *Server) registerProjectRoutes(mux *http.ServeMux, prefix string) {
 	mux.HandleFunc("POST "+prefix+"/loops/{id}/wind-down", srv.projectHandler(handleWindDownLoopRunP))
 	mux.HandleFunc("POST "+prefix+"/loops/{id}/message", srv.projectHandler(handleLoopRunMessageP))
 
+	// Loop schedules
+	mux.HandleFunc("GET "+prefix+"/schedules", srv.projectHandler(handleSchedulesP))
+	mux.HandleFunc("POST "+prefix+"/schedules", srv.projectHandler(handleCreateScheduleP))
+	mux.HandleFunc("GET "+prefix+"/schedules/{id}", srv.projectHandler(handleScheduleByIDP))
+	mux.HandleFunc("PUT "+prefix+"/schedules/{id}", srv.projectHandler(handleUpdateScheduleP))
+	mux.HandleFunc("DELETE "+prefix+"/schedules/{id}", srv.projectHandler(handleDeleteScheduleP))
+
+	// Human approvals
+	mux.HandleFunc("GET "+prefix+"/approvals", srv.projectHandler(handleApprovalsP))
+	mux.HandleFunc("GET "+prefix+"/approvals/{id}", srv.projectHandler(handleApprovalByIDP))
+	mux.HandleFunc("POST "+prefix+"/approvals/{id}/approve", srv.projectHandler(handleApproveP))
+	mux.HandleFunc("POST "+prefix+"/approvals/{id}/deny", srv.projectHandler(handleDenyP))
+
+	// Operator inbox (pending parent-ask questions)
+	mux.HandleFunc("GET "+prefix+"/inbox", srv.projectHandler(handleInboxP))
+	mux.HandleFunc("POST "+prefix+"/inbox/{id}/reply", srv.projectHandler(handleInboxReplyP))
+
+	// Forge pull requests
+	mux.HandleFunc("GET "+prefix+"/pulls", srv.projectHandler(handlePullRequestsP))
+	mux.HandleFunc("POST "+prefix+"/pulls", srv.projectHandler(handleCreatePullRequestP))
+	mux.HandleFunc("POST "+prefix+"/pulls/{id}/sync", srv.projectHandler(handleSyncPullRequestP))
+
 	// Chat Instances
 
 	mux.HandleFunc("GET "+prefix+"/chat-instances", srv.projectHandler(handleListChatInstances))
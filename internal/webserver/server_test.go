@@ -358,6 +358,31 @@ func TestWikiEndpoints(t *testing.T) {
 	}
 }
 
+func TestSearchEndpoint(t *testing.T) {
+	srv, s := newTestServer(t)
+
+	if err := s.CreateTurn(&store.Turn{Agent: "claude", Objective: "Harden auth middleware"}); err != nil {
+		t.Fatalf("CreateTurn: %v", err)
+	}
+	if err := s.CreateIssue(&store.Issue{Title: "Auth middleware drops headers", Status: "open", Priority: "high"}); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+
+	rec := performRequest(t, srv, http.MethodGet, "/api/search?q=middleware&type=issue")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("search status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	results := decodeResponse[[]store.SearchResult](t, rec)
+	if len(results) != 1 || results[0].Kind != store.SearchKindIssue || results[0].Snippet == "" {
+		t.Fatalf("issue search results = %+v, want one issue with a snippet", results)
+	}
+
+	rec = performRequest(t, srv, http.MethodGet, "/api/search?q=middleware&type=bogus")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("bad type status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
 func TestTurnsEndpoint(t *testing.T) {
 	srv, s := newTestServer(t)
 
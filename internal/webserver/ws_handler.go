@@ -124,6 +124,7 @@ func toWSEnvelope(event any) wsEnvelope {
 					Question:      ev.Spawns[i].Question,
 					Summary:       ev.Spawns[i].Summary,
 					Result:        ev.Spawns[i].Result,
+					QueuePosition: ev.Spawns[i].QueuePosition,
 				})
 			}
 			msg.Spawns = spawns
@@ -201,6 +202,7 @@ func toWSEnvelope(event any) wsEnvelope {
 				Question:      ev.Spawns[i].Question,
 				Summary:       ev.Spawns[i].Summary,
 				Result:        ev.Spawns[i].Result,
+				QueuePosition: ev.Spawns[i].QueuePosition,
 			})
 		}
 		return wsEnvelope{Type: session.MsgSpawn, Data: session.WireSpawn{Spawns: spawns}}
@@ -0,0 +1,88 @@
+package worktree
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/agusx1211/adaf/internal/debug"
+)
+
+// MergeConflictError is returned by Merge and MergeSquash when git stops
+// with unmerged paths. The repository is left mid-merge; callers decide
+// whether to AbortMerge or keep the merge open for manual resolution.
+type MergeConflictError struct {
+	Branch string   // branch that was being merged
+	Target string   // HEAD commit the branch was merged into
+	Files  []string // paths with unresolved conflicts
+	Hunks  string   // combined diff of the conflicted paths
+}
+
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("merge %s: conflicts in %s", e.Branch, strings.Join(e.Files, ", "))
+}
+
+// ConflictedFiles returns the paths that currently have unresolved merge
+// conflicts in the repository root.
+func (m *Manager) ConflictedFiles(ctx context.Context) ([]string, error) {
+	out, err := m.git(ctx, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// AbortMerge restores the repository root to its pre-merge state. It works
+// for both regular and squash merges.
+func (m *Manager) AbortMerge(ctx context.Context) error {
+	debug.LogKV("worktree", "AbortMerge()")
+	if _, err := m.git(ctx, "reset", "--merge"); err != nil {
+		return fmt.Errorf("abort merge: %w", err)
+	}
+	return nil
+}
+
+// CommitMerge concludes a merge that was left open after conflicts. It fails
+// while any path is still unmerged.
+func (m *Manager) CommitMerge(ctx context.Context, message string) (string, error) {
+	debug.LogKV("worktree", "CommitMerge()")
+	files, err := m.ConflictedFiles(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(files) > 0 {
+		return "", fmt.Errorf("unresolved conflicts remain in %s", strings.Join(files, ", "))
+	}
+	if _, err := m.git(ctx, "commit", "-m", message); err != nil {
+		return "", fmt.Errorf("commit merge: %w", err)
+	}
+	hash, err := m.git(ctx, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(hash), nil
+}
+
+// conflictError inspects the repository after a failed merge and returns a
+// *MergeConflictError when the failure was caused by conflicts, or nil.
+func (m *Manager) conflictError(ctx context.Context, branchName string) *MergeConflictError {
+	files, err := m.ConflictedFiles(ctx)
+	if err != nil || len(files) == 0 {
+		return nil
+	}
+	conflict := &MergeConflictError{Branch: branchName, Files: files}
+	if head, err := m.git(ctx, "rev-parse", "HEAD"); err == nil {
+		conflict.Target = strings.TrimSpace(head)
+	}
+	if hunks, err := m.git(ctx, append([]string{"diff", "--"}, files...)...); err == nil {
+		conflict.Hunks = hunks
+	}
+	debug.LogKV("worktree", "merge conflict detected", "branch", branchName, "files", len(files))
+	return conflict
+}
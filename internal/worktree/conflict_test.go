@@ -0,0 +1,107 @@
+package worktree
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// conflictingBranch creates a branch that rewrites main.txt and a diverging
+// commit on main touching the same line.
+func conflictingBranch(t *testing.T, repo string, mgr *Manager) (string, string) {
+	t.Helper()
+	ctx := context.Background()
+	identity := []string{"user.name=Test", "user.email=test@example.com"}
+
+	branch := "adaf/test/worker/20260212T000300"
+	wtPath, err := mgr.Create(ctx, branch)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	t.Cleanup(func() { mgr.RemoveWithBranch(context.Background(), wtPath, branch) })
+
+	if err := os.WriteFile(filepath.Join(wtPath, "main.txt"), []byte("from branch\n"), 0644); err != nil {
+		t.Fatalf("WriteFile(branch): %v", err)
+	}
+	runGitWithConfig(t, wtPath, identity, "commit", "-am", "branch change")
+
+	if err := os.WriteFile(filepath.Join(repo, "main.txt"), []byte("from main\n"), 0644); err != nil {
+		t.Fatalf("WriteFile(main): %v", err)
+	}
+	runGitWithConfig(t, repo, identity, "commit", "-am", "main change")
+	return branch, strings.TrimSpace(gitOutput(t, repo, "rev-parse", "HEAD"))
+}
+
+func TestMerge_ConflictReturnsConflictError(t *testing.T) {
+	for _, squash := range []bool{false, true} {
+		repo := initGitRepo(t)
+		runGit(t, repo, "config", "user.name", "Test")
+		runGit(t, repo, "config", "user.email", "test@example.com")
+		mgr := NewManager(repo)
+		ctx := context.Background()
+		branch, head := conflictingBranch(t, repo, mgr)
+
+		var err error
+		if squash {
+			_, err = mgr.MergeSquash(ctx, branch, "")
+		} else {
+			_, err = mgr.Merge(ctx, branch, "")
+		}
+		var conflict *MergeConflictError
+		if !errors.As(err, &conflict) {
+			t.Fatalf("squash=%v: err = %v, want *MergeConflictError", squash, err)
+		}
+		if len(conflict.Files) != 1 || conflict.Files[0] != "main.txt" {
+			t.Fatalf("squash=%v: Files = %v, want [main.txt]", squash, conflict.Files)
+		}
+		if conflict.Target != head {
+			t.Fatalf("squash=%v: Target = %q, want %q", squash, conflict.Target, head)
+		}
+		if !strings.Contains(conflict.Hunks, "from branch") || !strings.Contains(conflict.Hunks, "from main") {
+			t.Fatalf("squash=%v: Hunks missing both sides:\n%s", squash, conflict.Hunks)
+		}
+
+		if err := mgr.AbortMerge(ctx); err != nil {
+			t.Fatalf("squash=%v: AbortMerge: %v", squash, err)
+		}
+		if status := strings.TrimSpace(gitOutput(t, repo, "status", "--porcelain")); status != "" {
+			t.Fatalf("squash=%v: repo should be clean after abort, status=%q", squash, status)
+		}
+		if got := strings.TrimSpace(gitOutput(t, repo, "rev-parse", "HEAD")); got != head {
+			t.Fatalf("squash=%v: HEAD moved after abort: %s", squash, got)
+		}
+	}
+}
+
+func TestCommitMerge_RequiresResolvedConflicts(t *testing.T) {
+	repo := initGitRepo(t)
+	runGit(t, repo, "config", "user.name", "Test")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	mgr := NewManager(repo)
+	ctx := context.Background()
+	branch, head := conflictingBranch(t, repo, mgr)
+
+	if _, err := mgr.Merge(ctx, branch, "merge branch"); err == nil {
+		t.Fatalf("Merge: expected conflict")
+	}
+	if _, err := mgr.CommitMerge(ctx, "merge branch"); err == nil || !strings.Contains(err.Error(), "main.txt") {
+		t.Fatalf("CommitMerge with conflicts: err = %v, want unresolved main.txt", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(repo, "main.txt"), []byte("resolved\n"), 0644); err != nil {
+		t.Fatalf("WriteFile(resolved): %v", err)
+	}
+	runGit(t, repo, "add", "main.txt")
+
+	hash, err := mgr.CommitMerge(ctx, "merge branch")
+	if err != nil {
+		t.Fatalf("CommitMerge: %v", err)
+	}
+	parents := strings.Fields(strings.TrimSpace(gitOutput(t, repo, "show", "-s", "--format=%P", hash)))
+	if len(parents) != 2 || parents[0] != head {
+		t.Fatalf("merge parents = %v, want [%s <branch>]", parents, head)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -154,12 +155,16 @@ func (m *Manager) RemoveWithBranch(ctx context.Context, wtPath, branchName strin
 }
 
 // Merge merges the given branch into the current branch with a merge commit.
+// On conflicts it returns a *MergeConflictError and leaves the merge open.
 func (m *Manager) Merge(ctx context.Context, branchName, message string) (string, error) {
 	debug.LogKV("worktree", "Merge()", "branch", branchName)
 	if message == "" {
 		message = "Merge " + branchName
 	}
 	if _, err := m.git(ctx, "merge", "--no-ff", "-m", message, branchName); err != nil {
+		if conflict := m.conflictError(ctx, branchName); conflict != nil {
+			return "", conflict
+		}
 		return "", fmt.Errorf("merge %s: %w", branchName, err)
 	}
 	hash, err := m.git(ctx, "rev-parse", "HEAD")
@@ -170,8 +175,12 @@ func (m *Manager) Merge(ctx context.Context, branchName, message string) (string
 }
 
 // MergeSquash squash-merges the given branch into the current branch.
+// On conflicts it returns a *MergeConflictError and leaves the merge open.
 func (m *Manager) MergeSquash(ctx context.Context, branchName, message string) (string, error) {
 	if _, err := m.git(ctx, "merge", "--squash", branchName); err != nil {
+		if conflict := m.conflictError(ctx, branchName); conflict != nil {
+			return "", conflict
+		}
 		return "", fmt.Errorf("squash-merge %s: %w", branchName, err)
 	}
 	if message == "" {
@@ -196,6 +205,84 @@ func (m *Manager) Diff(ctx context.Context, branchName string) (string, error) {
 	return out, nil
 }
 
+// CurrentBranch returns the branch checked out in the repo root.
+func (m *Manager) CurrentBranch(ctx context.Context) (string, error) {
+	out, err := m.git(ctx, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	branch := strings.TrimSpace(out)
+	if branch == "HEAD" {
+		return "", fmt.Errorf("HEAD is detached")
+	}
+	return branch, nil
+}
+
+// Push pushes ref to branchName on remote.
+func (m *Manager) Push(ctx context.Context, remote, ref, branchName string) error {
+	_, err := m.git(ctx, "push", remote, ref+":refs/heads/"+branchName)
+	return err
+}
+
+// BranchHead returns the commit branchName points to.
+func (m *Manager) BranchHead(ctx context.Context, branchName string) (string, error) {
+	out, err := m.git(ctx, "rev-parse", "--verify", branchName+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// HeadCommit returns the commit checked out in a worktree.
+func (m *Manager) HeadCommit(ctx context.Context, worktreePath string) (string, error) {
+	out, err := m.git(ctx, "-C", worktreePath, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// DiffLineCount returns the number of added plus deleted lines in a worktree
+// (committed and uncommitted tracked changes) relative to baseRef. Binary
+// files count as one line each.
+func (m *Manager) DiffLineCount(ctx context.Context, worktreePath, baseRef string) (int, error) {
+	out, err := m.git(ctx, "-C", worktreePath, "diff", "--numstat", baseRef)
+	if err != nil {
+		return 0, err
+	}
+	return numstatLines(out), nil
+}
+
+// BranchDiffLineCount returns the number of added plus deleted lines a merge
+// of branchName would bring into the current branch.
+func (m *Manager) BranchDiffLineCount(ctx context.Context, branchName string) (int, error) {
+	out, err := m.git(ctx, "diff", "--numstat", "HEAD..."+branchName)
+	if err != nil {
+		return 0, err
+	}
+	return numstatLines(out), nil
+}
+
+// numstatLines sums `git diff --numstat` output. Binary files count as one
+// line each.
+func numstatLines(out string) int {
+	total := 0
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		if fields[0] == "-" {
+			total++
+			continue
+		}
+		added, _ := strconv.Atoi(fields[0])
+		deleted, _ := strconv.Atoi(fields[1])
+		total += added + deleted
+	}
+	return total
+}
+
 // AutoCommitIfDirty stages and commits all changes in a worktree when needed.
 // It returns (commitHash, committed, error). If there are no changes, committed=false.
 func (m *Manager) AutoCommitIfDirty(ctx context.Context, worktreePath, message string) (string, bool, error) {
@@ -254,6 +254,9 @@ export function useSessionSocket(sessionID) {
       if (data && Array.isArray(data.spawns)) {
         dispatch({ type: 'MERGE_SPAWNS', payload: normalizeSpawns(data.spawns) });
       }
+      if (data && data.loop) {
+        dispatch({ type: 'SET_USAGE_PAUSE', payload: usagePausePayload(data.loop.usage_pause, data.loop.run_id) });
+      }
       // Replay recent messages from snapshot (includes prompt, event, etc.)
       if (data && Array.isArray(data.recent)) {
         data.recent.forEach(function (recentMsg) {
@@ -410,6 +413,11 @@ export function useSessionSocket(sessionID) {
       return;
     }
 
+    if (type === 'usage_pause') {
+      dispatch({ type: 'SET_USAGE_PAUSE', payload: usagePausePayload(data, data && data.run_id) });
+      return;
+    }
+
     // Suppress loop lifecycle events from output
     if (type === 'loop_step_start' || type === 'loop_step_end' || type === 'loop_done') {
       return;
@@ -570,6 +578,13 @@ function asObject(value) {
   return null;
 }
 
+// usagePausePayload converts a wire usage pause into a SET_USAGE_PAUSE payload;
+// a pause without an end time means the step resumed.
+function usagePausePayload(raw, runID) {
+  var pause = raw && typeof raw === 'object' && raw.until ? raw : null;
+  return { run_id: Number(runID || 0), pause: pause };
+}
+
 function extractContentBlocks(event) {
   if (!event || typeof event !== 'object') return [];
   if (event.message && Array.isArray(event.message.content)) return event.message.content.slice();
@@ -59,12 +59,15 @@ export default function IssueDetailPanel() {
   function buildMarkdown(data) {
     var labels = data.labels ? data.labels.join(', ') : '';
     var dependsOn = data.depends_on ? data.depends_on.join(', ') : '';
+    var claim = data.claim && new Date(data.claim.expires_at) > new Date() ? data.claim : null;
+    var claimHolder = claim ? (claim.spawn_id ? 'spawn #' + claim.spawn_id : 'turn #' + claim.turn_id) : '';
     return (
       '# Issue #' + (issue.id || '') + '\n\n' +
       '**Plan:** ' + (data.plan_id || 'shared') + '  \n' +
       '**Status:** ' + (data.status || 'open') + '  \n' +
       '**Priority:** ' + (data.priority || 'medium') + '  \n' +
       (labels ? ('**Labels:** ' + labels + '  \n') : '') +
+      (claim ? ('**Claimed by:** ' + claimHolder + ' until ' + new Date(claim.expires_at).toLocaleString() + '  \n') : '') +
       (dependsOn ? ('**Depends on:** ' + dependsOn + '  \n\n') : '\n') +
       '## Details\n\n' +
       (data.title ? ('# ' + data.title + '\n\n') : '') +
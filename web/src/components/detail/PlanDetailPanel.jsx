@@ -56,10 +56,25 @@ export default function PlanDetailPanel() {
       '# ' + (data.title || data.id || 'Plan') + '\n\n' +
       '**Plan ID:** ' + data.id + '  \n' +
       '**Status:** ' + data.status + '  \n\n' +
-      (data.description || '_No description yet._')
+      (data.description || '_No description yet._') +
+      buildPhasesMarkdown(data.phases)
     );
   }
 
+  function buildPhasesMarkdown(phases) {
+    if (!phases || !phases.length) return '';
+    return '\n\n## Phases\n\n' + phases.map(function (phase, index) {
+      var line = (index + 1) + '. **' + phase.title + '** `' + (phase.status || 'pending') + '`';
+      if (phase.issue_ids && phase.issue_ids.length) {
+        line += ' — issues ' + phase.issue_ids.map(function (id) { return '#' + id; }).join(', ');
+      }
+      if (phase.acceptance) {
+        line += '  \n   _Acceptance:_ ' + phase.acceptance;
+      }
+      return line;
+    }).join('\n');
+  }
+
   function beginEdit() {
     setEditTitle(plan.title || '');
     setEditStatus(plan.status || 'active');
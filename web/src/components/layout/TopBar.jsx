@@ -1,6 +1,6 @@
 import { useState, useEffect, useRef, useMemo } from 'react';
 import { useAppState, useDispatch } from '../../state/store.js';
-import { normalizeStatus, formatNumber, formatElapsed } from '../../utils/format.js';
+import { normalizeStatus, formatNumber, formatElapsed, formatTime } from '../../utils/format.js';
 import { STATUS_RUNNING, statusColor } from '../../utils/colors.js';
 import { buildSpawnScopeMaps } from '../../utils/scopes.js';
 import StatusDot from '../common/StatusDot.jsx';
@@ -285,6 +285,15 @@ export default function TopBar() {
           </span>
         )}
 
+        {loopRun && loopRun.usage_pause && (
+          <span
+            title={'Waiting for ' + loopRun.usage_pause.provider + ' ' + loopRun.usage_pause.limit + ' (' + Math.round(loopRun.usage_pause.utilization_pct || 0) + '% used) to reset'}
+            style={{ fontFamily: "'JetBrains Mono', monospace", fontSize: 10, color: 'var(--orange)', display: 'flex', alignItems: 'center', gap: 4 }}
+          >
+            {'\u23F8'} {loopRun.usage_pause.profile} paused until {formatTime(loopRun.usage_pause.until)}
+          </span>
+        )}
+
         <span style={{
           display: 'flex', alignItems: 'center', gap: 4, padding: '2px 8px',
           background: wsOnline ? 'rgba(74,230,138,0.1)' : 'var(--bg-3)',
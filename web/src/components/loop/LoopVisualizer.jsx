@@ -3,6 +3,8 @@ import { useAppState, useDispatch } from '../../state/store.js';
 import { agentInfo, statusColor, STATUS_RUNNING } from '../../utils/colors.js';
 import { buildSpawnScopeMaps } from '../../utils/scopes.js';
 import { cropText, formatElapsed, parseTimestamp } from '../../utils/format.js';
+import { apiBase, apiCall } from '../../api/client.js';
+import { useToast } from '../common/Toast.jsx';
 
 var LIVE_STATUSES = {
   running: true,
@@ -368,6 +370,9 @@ export default function LoopVisualizer() {
                     {selectedNode.summary ? <InfoBox text={selectedNode.summary} /> : null}
                     {selectedNode.result ? <InfoBox text={selectedNode.result} /> : null}
                     {selectedNode.question ? <InfoBox text={'Q: ' + selectedNode.question} /> : null}
+                    {selectedNode.type === 'spawn' && selectedNode.status === 'awaiting_input' && selectedNode.question ? (
+                      <AskAnswerBox projectID={state.currentProjectID} spawnID={selectedNode.idNumber} />
+                    ) : null}
                   </div>
                 )}
 
@@ -1470,6 +1475,65 @@ function InfoBox({ text }) {
   );
 }
 
+function AskAnswerBox({ projectID, spawnID }) {
+  var toast = useToast();
+  var [answer, setAnswer] = useState('');
+  var [sending, setSending] = useState(false);
+
+  async function send() {
+    var text = answer.trim();
+    if (!text || sending) return;
+    setSending(true);
+    try {
+      await apiCall(apiBase(projectID) + '/inbox/' + encodeURIComponent(String(spawnID)) + '/reply', 'POST', { answer: text });
+      toast('Answer sent to spawn #' + spawnID, 'success');
+      setAnswer('');
+    } catch (err) {
+      if (!err.authRequired) {
+        toast('Failed to answer: ' + (err.message || err), 'error');
+      }
+    } finally {
+      setSending(false);
+    }
+  }
+
+  return (
+    <div style={{ marginTop: 6, display: 'flex', flexDirection: 'column', gap: 6 }}>
+      <textarea
+        value={answer}
+        onChange={function (e) { setAnswer(e.target.value); }}
+        placeholder="Answer as operator..."
+        rows={3}
+        style={{
+          resize: 'vertical',
+          border: '1px solid rgba(255,255,255,0.1)',
+          borderRadius: 6,
+          background: 'rgba(0,0,0,0.23)',
+          color: 'var(--text-1)',
+          padding: '6px 8px',
+          fontFamily: "'JetBrains Mono', monospace",
+          fontSize: 10,
+        }}
+      />
+      <button
+        onClick={send}
+        disabled={sending || !answer.trim()}
+        style={{
+          alignSelf: 'flex-end',
+          border: '1px solid var(--orange)',
+          borderRadius: 4,
+          background: 'transparent',
+          color: 'var(--orange)',
+          padding: '3px 10px',
+          fontFamily: "'JetBrains Mono', monospace",
+          fontSize: 10,
+          cursor: sending ? 'default' : 'pointer',
+        }}
+      >{sending ? 'Sending...' : 'Answer'}</button>
+    </div>
+  );
+}
+
 function LegendDot({ color, text }) {
   return (
     <span style={{ display: 'inline-flex', alignItems: 'center', gap: 4 }}>
@@ -208,6 +208,9 @@ function SpawnNode({ spawn, depth, childrenByParent, selectedScope, expandedNode
             <span style={{ fontFamily: "'JetBrains Mono', monospace", fontSize: 9, color: 'var(--text-3)' }}>
               {formatElapsed(spawn.started_at, spawn.completed_at)}
             </span>
+            {status === 'queued' && spawn.queue_position > 0 && (
+              <span style={{ fontFamily: "'JetBrains Mono', monospace", fontSize: 9, color: sColor }}>queued #{spawn.queue_position}</span>
+            )}
           </div>
           {spawn.task && (
             <div style={{ fontSize: 10, color: 'var(--text-2)', marginTop: 1, whiteSpace: 'nowrap', overflow: 'hidden', textOverflow: 'ellipsis' }}>
@@ -110,6 +110,13 @@ function reducer(state, action) {
     case 'SET_LOOP_RUNS':
       return { ...state, loopRuns: action.payload };
 
+    case 'SET_USAGE_PAUSE': {
+      // payload: { run_id, pause } where pause is null once the step resumes.
+      var pauseRun = state.loopRun;
+      if (!pauseRun || (action.payload.run_id && pauseRun.id !== action.payload.run_id)) return state;
+      return { ...state, loopRun: { ...pauseRun, usage_pause: action.payload.pause } };
+    }
+
     case 'SET_HISTORICAL_EVENTS': {
       var hTurnID = action.payload.turnID;
       var hEvents = action.payload.events;
@@ -290,6 +297,7 @@ export function normalizeSpawns(rawSpawns) {
       completed_at: spawn && spawn.completed_at ? spawn.completed_at : '',
       summary: spawn && spawn.summary ? String(spawn.summary) : '',
       result: spawn && spawn.result ? String(spawn.result) : '',
+      queue_position: numberOr(0, spawn && spawn.queue_position),
     };
   }).filter(function (s) { return s.id > 0; }).sort(function (a, b) {
     return parseTimestamp(b.started_at) - parseTimestamp(a.started_at);
@@ -34,7 +34,7 @@ export function statusColor(status) {
   var map = {
     running: '#f9e2af', starting: '#f9e2af',
     waiting: '#f9e2af', waiting_for_spawns: '#f9e2af',
-    awaiting_input: '#89b4fa',
+    awaiting_input: '#89b4fa', queued: '#6c7086',
     completed: '#a6e3a1', complete: '#a6e3a1', merged: '#a6e3a1',
     passing: '#a6e3a1', resolved: '#a6e3a1', done: '#a6e3a1',
     failed: '#f38ba8', failing: '#f38ba8',
@@ -54,7 +54,7 @@ export function statusIcon(status) {
   var map = {
     running: '\u25C9', starting: '\u25C9',
     waiting: '\u25CE', waiting_for_spawns: '\u25CE',
-    awaiting_input: '\u25CE',
+    awaiting_input: '\u25CE', queued: '\u25CC',
     completed: '\u2713', complete: '\u2713', merged: '\u2295',
     passing: '\u2713', failed: '\u2717', failing: '\u2717',
     canceled: '\u2298', cancelled: '\u2298', rejected: '\u2297',